@@ -0,0 +1,249 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// Transition is an event transition, which indexes the event-time-stamps,
+// acked-transitions and event-priorities of an event-initiating object
+type Transition uint8
+
+const (
+	TransitionToOffNormal Transition = 0
+	TransitionToFault     Transition = 1
+	TransitionToNormal    Transition = 2
+)
+
+func (t Transition) String() string {
+	switch t {
+	case TransitionToOffNormal:
+		return "to-offnormal"
+	case TransitionToFault:
+		return "to-fault"
+	case TransitionToNormal:
+		return "to-normal"
+	default:
+		return fmt.Sprintf("transition(%d)", t)
+	}
+}
+
+// Transition returns the transition into the event state
+func (e EventState) Transition() Transition {
+	switch e {
+	case EventStateNormal:
+		return TransitionToNormal
+	case EventStateFault:
+		return TransitionToFault
+	default:
+		return TransitionToOffNormal
+	}
+}
+
+// Has returns true if the transition is selected
+func (t EventTransitions) Has(tr Transition) bool {
+	switch tr {
+	case TransitionToOffNormal:
+		return t.ToOffNormal
+	case TransitionToFault:
+		return t.ToFault
+	case TransitionToNormal:
+		return t.ToNormal
+	default:
+		return false
+	}
+}
+
+// EventSummary is an object with an active event state or an
+// unacknowledged transition, as listed by GetEventInformation
+type EventSummary struct {
+	ObjectID         ObjectIdentifier
+	EventState       EventState
+	AckedTransitions EventTransitions
+
+	// EventTimeStamps holds the time of the last transition of each kind,
+	// indexed by Transition
+	EventTimeStamps [3]EventTimeStamp
+
+	NotifyType  NotifyType
+	EventEnable EventTransitions
+
+	// EventPriorities holds the priority of each transition, indexed by
+	// Transition
+	EventPriorities [3]uint32
+}
+
+// GetEventInformation lists the objects of a device with an active event
+// state or an unacknowledged transition, requesting the following pages
+// while the device reports more events. Date and time stamps are decoded in
+// the local time zone, which should be the time zone of the device.
+func (c *Client) GetEventInformation(ctx context.Context, deviceID uint32) ([]EventSummary, error) {
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var summaries []EventSummary
+	seen := make(map[ObjectIdentifier]bool)
+	var last *ObjectIdentifier
+	for {
+		e := NewEncoder(make([]byte, 0, 8))
+		if last != nil {
+			e.ContextObjectIdentifier(0, *last)
+		}
+
+		resp, err := c.sendRequest(ctx, deviceID, addr, ServiceGetEventInformation, e.Bytes())
+		if err != nil {
+			return nil, err
+		}
+
+		page, more, err := decodeEventInformation(resp.Data, time.Local)
+		if err != nil {
+			return nil, err
+		}
+
+		// A device restarting the list would be asked for it forever
+		added := 0
+		for _, s := range page {
+			if seen[s.ObjectID] {
+				continue
+			}
+			seen[s.ObjectID] = true
+			summaries = append(summaries, s)
+			added++
+		}
+		if !more || added == 0 {
+			return summaries, nil
+		}
+		last = &page[len(page)-1].ObjectID
+	}
+}
+
+// decodeEventInformation decodes a GetEventInformation ack
+func decodeEventInformation(data []byte, loc *time.Location) ([]EventSummary, bool, error) {
+	d := NewDecoder(data)
+	var summaries []EventSummary
+
+	d.Opening(0)
+	for d.Err() == nil && d.Len() > 0 && !d.IsClosing(0) {
+		var s EventSummary
+		s.ObjectID = d.ContextObjectIdentifier(0)
+		s.EventState = EventState(d.ContextEnumerated(1))
+		s.AckedTransitions = eventTransitionsFromBits(decodeBitString(d.context(2, 1, 2)))
+		d.Opening(3)
+		for i := range s.EventTimeStamps {
+			s.EventTimeStamps[i] = decodeEventTimeStamp(d, loc)
+		}
+		d.Closing(3)
+		s.NotifyType = NotifyType(d.ContextEnumerated(4))
+		s.EventEnable = eventTransitionsFromBits(decodeBitString(d.context(5, 1, 2)))
+		d.Opening(6)
+		for i := range s.EventPriorities {
+			s.EventPriorities[i] = d.Unsigned()
+		}
+		d.Closing(6)
+		summaries = append(summaries, s)
+	}
+	d.Closing(0)
+	more := d.ContextBoolean(1)
+
+	if err := d.Err(); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return summaries, more, nil
+}
+
+// Acknowledgement is an AcknowledgeAlarm request
+type Acknowledgement struct {
+	// ProcessID is the acknowledging process identifier
+	ProcessID uint32
+
+	// ObjectID is the event-initiating object
+	ObjectID ObjectIdentifier
+
+	// EventState is the state of the acknowledged transition
+	EventState EventState
+
+	// Timestamp is the time stamp of the acknowledged transition, as
+	// reported by the device
+	Timestamp EventTimeStamp
+
+	// Source identifies the operator acknowledging the alarm
+	Source string
+
+	// Time is the time of the acknowledgement; the zero value sends the
+	// current time
+	Time EventTimeStamp
+}
+
+// Acknowledge returns the acknowledgement of a transition of the object,
+// using its time stamp and, for the transition into the current state, the
+// current event state
+func (s EventSummary) Acknowledge(tr Transition, source string) Acknowledgement {
+	state := s.EventState
+	if state.Transition() != tr {
+		switch tr {
+		case TransitionToFault:
+			state = EventStateFault
+		case TransitionToNormal:
+			state = EventStateNormal
+		default:
+			state = EventStateOffNormal
+		}
+	}
+	ack := Acknowledgement{
+		ObjectID:   s.ObjectID,
+		EventState: state,
+		Source:     source,
+	}
+	if int(tr) < len(s.EventTimeStamps) {
+		ack.Timestamp = s.EventTimeStamps[tr]
+	}
+	return ack
+}
+
+// AcknowledgeAlarm acknowledges an event transition of an object
+func (c *Client) AcknowledgeAlarm(ctx context.Context, deviceID uint32, ack Acknowledgement) error {
+	if c.isStandby() {
+		return ErrStandby
+	}
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	ackTime := ack.Time
+	if ackTime.TimeOfDay == nil && ackTime.Sequence == nil && ackTime.DateTime.IsZero() {
+		ackTime.DateTime = time.Now()
+	}
+
+	e := NewEncoder(make([]byte, 0, 64))
+	e.ContextUnsigned(0, ack.ProcessID)
+	e.ContextObjectIdentifier(1, ack.ObjectID)
+	e.ContextEnumerated(2, uint32(ack.EventState))
+	e.Opening(3)
+	encodeEventTimeStamp(e, ack.Timestamp)
+	e.Closing(3)
+	e.ContextCharacterString(4, ack.Source)
+	e.Opening(5)
+	encodeEventTimeStamp(e, ackTime)
+	e.Closing(5)
+
+	_, err = c.sendRequest(ctx, deviceID, addr, ServiceAcknowledgeAlarm, e.Bytes())
+	return err
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+)
+
+// maxArrayReadConcurrency bounds the requests in flight when an array is
+// read in chunks
+const maxArrayReadConcurrency = 4
+
+// ReadArray reads every element of an array property. The whole array is
+// first read in a single request, which may be answered in segments when
+// the client accepts them (see WithSegmentation); when the device cannot
+// return it in one response, the array length is read and the elements are
+// fetched concurrently in ReadPropertyMultiple chunks, or one by one from
+// devices without ReadPropertyMultiple.
+func (c *Client) ReadArray(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, propertyID PropertyIdentifier) ([]interface{}, error) {
+	resp, err := c.readProperty(ctx, deviceID, objectID, propertyID, &ReadOptions{})
+	if err == nil {
+		values, decodeErr := decodeReadPropertyArray(resp.Data)
+		if decodeErr == nil {
+			return values, nil
+		}
+		err = decodeErr
+	}
+
+	var abortErr *AbortError
+	if !errors.As(err, &abortErr) && !errors.Is(err, ErrInvalidResponse) {
+		return nil, err
+	}
+
+	c.logger.Debug("reading array by index",
+		slog.Uint64("device_id", uint64(deviceID)),
+		slog.String("object", objectID.String()),
+		slog.String("property", propertyID.String()),
+		slog.String("reason", err.Error()),
+	)
+
+	lengthVal, err := c.ReadProperty(ctx, deviceID, objectID, propertyID, WithArrayIndex(0))
+	if err != nil {
+		return nil, err
+	}
+	length, ok := lengthVal.(uint32)
+	if !ok {
+		return nil, fmt.Errorf("%w: array length of type %T", ErrInvalidResponse, lengthVal)
+	}
+
+	return c.readArrayElements(ctx, deviceID, objectID, propertyID, length)
+}
+
+// readArrayElements reads elements 1 to length of an array property
+func (c *Client) readArrayElements(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, propertyID PropertyIdentifier, length uint32) ([]interface{}, error) {
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	values := make([]interface{}, length)
+	requests := make([]ReadPropertyRequest, length)
+	for i := range requests {
+		index := uint32(i + 1)
+		requests[i] = ReadPropertyRequest{ObjectID: objectID, PropertyID: propertyID, ArrayIndex: &index}
+	}
+
+	batches := splitReadAccessSpecs(requests, c.deviceMaxAPDU(deviceID))
+	err = runConcurrently(len(batches), func(i int) error {
+		results, err := c.readPropertyBatch(ctx, deviceID, addr, batches[i])
+		if err != nil {
+			return err
+		}
+		if len(results) != len(batches[i]) {
+			return fmt.Errorf("%w: %d results for %d array elements", ErrInvalidResponse, len(results), len(batches[i]))
+		}
+		for j, r := range results {
+			index := *batches[i][j].ArrayIndex
+			if r.Err != nil {
+				return fmt.Errorf("element %d: %w", index, r.Err)
+			}
+			values[index-1] = r.Value
+		}
+		return nil
+	})
+	if err == nil || !serviceUnsupported(err) {
+		return values, err
+	}
+
+	// The device lacks ReadPropertyMultiple
+	err = runConcurrently(int(length), func(i int) error {
+		value, err := c.ReadProperty(ctx, deviceID, objectID, propertyID, WithArrayIndex(uint32(i+1)))
+		if err != nil {
+			return fmt.Errorf("element %d: %w", i+1, err)
+		}
+		values[i] = value
+		return nil
+	})
+	return values, err
+}
+
+// runConcurrently calls fn for 0 to n-1, at most maxArrayReadConcurrency at a
+// time, and returns the first error
+func runConcurrently(n int, fn func(i int) error) error {
+	errs := make([]error, n)
+	sem := make(chan struct{}, maxArrayReadConcurrency)
+	var wg sync.WaitGroup
+
+	for i := 0; i < n; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			errs[i] = fn(i)
+		}(i)
+	}
+	wg.Wait()
+
+	for _, err := range errs {
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// serviceUnsupported returns true if the device rejected a request because
+// it does not execute the service
+func serviceUnsupported(err error) bool {
+	var rejectErr *RejectError
+	if errors.As(err, &rejectErr) {
+		return rejectErr.Reason == RejectReasonUnrecognizedService
+	}
+	var bacnetErr *BACnetError
+	if errors.As(err, &bacnetErr) {
+		return bacnetErr.Class == ErrorClassServices
+	}
+	return false
+}
+
+// decodeReadPropertyArray decodes a ReadProperty response holding all
+// elements of an array of application-tagged values
+func decodeReadPropertyArray(data []byte) ([]interface{}, error) {
+	d := NewDecoder(data)
+	d.ContextObjectIdentifier(0)
+	d.ContextEnumerated(1)
+	if d.IsContext(2) {
+		d.ContextUnsigned(2)
+	}
+	d.Opening(3)
+
+	var values []interface{}
+	for d.Err() == nil && !d.IsClosing(3) {
+		values = append(values, d.Value())
+	}
+	d.Closing(3)
+
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return values, nil
+}
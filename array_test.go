@@ -0,0 +1,109 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet_test
+
+import (
+	"context"
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+
+	"github.com/edgeo-scada/bacnet"
+	"github.com/edgeo-scada/bacnet/bacnettest"
+)
+
+func TestReadArray(t *testing.T) {
+	const deviceID = 1234
+	deviceOID := bacnet.NewObjectIdentifier(bacnet.ObjectTypeDevice, deviceID)
+	objectList := []interface{}{
+		deviceOID,
+		bacnet.NewObjectIdentifier(bacnet.ObjectTypeAnalogValue, 1),
+		bacnet.NewObjectIdentifier(bacnet.ObjectTypeAnalogValue, 2),
+		bacnet.NewObjectIdentifier(bacnet.ObjectTypeAnalogValue, 3),
+	}
+
+	tests := []struct {
+		name string
+
+		// fault fails the first ReadProperty
+		fault error
+
+		want     []interface{}
+		err      bool
+		requests int64
+	}{
+		{name: "whole", want: objectList, requests: 1},
+		{
+			name:     "aborted",
+			fault:    &bacnet.AbortError{Server: true, Reason: bacnet.AbortReasonSegmentationNotSupported},
+			want:     objectList,
+			requests: 3, // the whole array, its length, then its elements
+		},
+		{
+			name:     "refused",
+			fault:    &bacnet.BACnetError{Class: bacnet.ErrorClassProperty, Code: bacnet.ErrorCodeReadAccessDenied},
+			err:      true,
+			requests: 1,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+
+			sim, err := bacnettest.NewServer(deviceID, bacnettest.WithObjects(
+				bacnet.NewAnalogValue(1, "AV-1"),
+				bacnet.NewAnalogValue(2, "AV-2"),
+				bacnet.NewAnalogValue(3, "AV-3"),
+			))
+			if err != nil {
+				t.Fatal(err)
+			}
+			defer sim.Close()
+			if tt.fault != nil {
+				sim.Fail(bacnet.ServiceReadProperty, tt.fault, 1)
+			}
+
+			client, err := bacnet.NewClient(bacnet.WithLocalAddress("127.0.0.1:0"))
+			if err != nil {
+				t.Fatal(err)
+			}
+			if err := client.Connect(ctx); err != nil {
+				t.Fatal(err)
+			}
+			defer client.Close()
+			if err := sim.Bind(client); err != nil {
+				t.Fatal(err)
+			}
+
+			values, err := client.ReadArray(ctx, deviceID, deviceOID, bacnet.PropertyObjectList)
+			if tt.err {
+				if !errors.As(err, new(*bacnet.BACnetError)) {
+					t.Fatalf("err = %v, want %v", err, tt.fault)
+				}
+			} else if err != nil {
+				t.Fatal(err)
+			}
+			if !reflect.DeepEqual(values, tt.want) {
+				t.Errorf("values = %v, want %v", values, tt.want)
+			}
+			if got := sim.Requests(); got != tt.requests {
+				t.Errorf("%d requests, want %d", got, tt.requests)
+			}
+		})
+	}
+}
@@ -0,0 +1,178 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"time"
+)
+
+// BackupFile is a configuration file of a device backup
+type BackupFile struct {
+	ObjectID     ObjectIdentifier
+	AccessMethod FileAccessMethod
+
+	// Data is the content of a stream-access file
+	Data []byte
+
+	// Records are the records of a record-access file
+	Records [][]byte
+}
+
+// Backup runs the backup procedure of a device: it starts the backup with
+// ReinitializeDevice, waits for the backup-preparation-time of the device,
+// reads the File objects of its configuration-files and ends the backup.
+// The backup is ended even when reading a file fails.
+func (c *Client) Backup(ctx context.Context, deviceID uint32, opts ...BackupOption) ([]BackupFile, error) {
+	options := &BackupOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if err := c.ReinitializeDevice(ctx, deviceID, ReinitStartBackup, options.Password); err != nil {
+		return nil, fmt.Errorf("start backup: %w", err)
+	}
+
+	files, err := c.readBackupFiles(ctx, deviceID, options)
+	if endErr := c.ReinitializeDevice(ctx, deviceID, ReinitEndBackup, options.Password); endErr != nil && err == nil {
+		err = fmt.Errorf("end backup: %w", endErr)
+	}
+	if err != nil {
+		return nil, err
+	}
+	return files, nil
+}
+
+// readBackupFiles reads the configuration files of a device preparing for
+// a backup
+func (c *Client) readBackupFiles(ctx context.Context, deviceID uint32, options *BackupOptions) ([]BackupFile, error) {
+	if err := c.waitPreparation(ctx, deviceID, PropertyBackupPreparationTime); err != nil {
+		return nil, err
+	}
+
+	values, err := c.ReadArray(ctx, deviceID, NewObjectIdentifier(ObjectTypeDevice, deviceID), PropertyConfigurationFiles)
+	if err != nil {
+		return nil, fmt.Errorf("read configuration-files: %w", err)
+	}
+
+	files := make([]BackupFile, 0, len(values))
+	for _, value := range values {
+		fileID, ok := value.(ObjectIdentifier)
+		if !ok || fileID.Type != ObjectTypeFile {
+			return nil, fmt.Errorf("%w: configuration-files holds %v", ErrInvalidResponse, value)
+		}
+
+		method, err := c.ReadFileAccessMethod(ctx, deviceID, fileID.Instance)
+		if err != nil {
+			return nil, fmt.Errorf("read %s: %w", fileID, err)
+		}
+
+		var fileOpts []FileOption
+		if options.Progress != nil {
+			fileOpts = append(fileOpts, WithFileProgress(func(done, total int64) {
+				options.Progress(fileID, done, total)
+			}))
+		}
+
+		file := BackupFile{ObjectID: fileID, AccessMethod: method}
+		if method == FileAccessRecord {
+			file.Records, err = c.ReadFileRecords(ctx, deviceID, fileID.Instance, fileOpts...)
+		} else {
+			file.Data, err = c.ReadFile(ctx, deviceID, fileID.Instance, fileOpts...)
+		}
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, file)
+	}
+	return files, nil
+}
+
+// Restore runs the restore procedure of a device: it starts the restore
+// with ReinitializeDevice, waits for the restore-preparation-time of the
+// device, writes the files and ends the restore. The restore is aborted
+// when writing a file fails.
+func (c *Client) Restore(ctx context.Context, deviceID uint32, files []BackupFile, opts ...BackupOption) error {
+	options := &BackupOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if err := c.ReinitializeDevice(ctx, deviceID, ReinitStartRestore, options.Password); err != nil {
+		return fmt.Errorf("start restore: %w", err)
+	}
+
+	if err := c.writeRestoreFiles(ctx, deviceID, files, options); err != nil {
+		if abortErr := c.ReinitializeDevice(ctx, deviceID, ReinitAbortRestore, options.Password); abortErr != nil {
+			c.logger.Warn("restore not aborted",
+				slog.Uint64("device_id", uint64(deviceID)),
+				slog.String("error", abortErr.Error()))
+		}
+		return err
+	}
+
+	if err := c.ReinitializeDevice(ctx, deviceID, ReinitEndRestore, options.Password); err != nil {
+		return fmt.Errorf("end restore: %w", err)
+	}
+	return nil
+}
+
+// writeRestoreFiles writes the files of a restore
+func (c *Client) writeRestoreFiles(ctx context.Context, deviceID uint32, files []BackupFile, options *BackupOptions) error {
+	if err := c.waitPreparation(ctx, deviceID, PropertyRestorePreparationTime); err != nil {
+		return err
+	}
+
+	for _, file := range files {
+		fileID := file.ObjectID
+		var fileOpts []FileOption
+		if options.Progress != nil {
+			fileOpts = append(fileOpts, WithFileProgress(func(done, total int64) {
+				options.Progress(fileID, done, total)
+			}))
+		}
+
+		var err error
+		if file.AccessMethod == FileAccessRecord {
+			err = c.WriteFileRecords(ctx, deviceID, fileID.Instance, file.Records, fileOpts...)
+		} else {
+			err = c.WriteFile(ctx, deviceID, fileID.Instance, file.Data, fileOpts...)
+		}
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// waitPreparation waits for the backup or restore preparation time of a
+// device, in seconds, when the device has one
+func (c *Client) waitPreparation(ctx context.Context, deviceID uint32, propertyID PropertyIdentifier) error {
+	value, err := c.ReadProperty(ctx, deviceID, NewObjectIdentifier(ObjectTypeDevice, deviceID), propertyID)
+	if err != nil {
+		return nil
+	}
+	seconds, ok := value.(uint32)
+	if !ok || seconds == 0 {
+		return nil
+	}
+
+	c.logger.Debug("waiting for device preparation",
+		slog.Uint64("device_id", uint64(deviceID)),
+		slog.Uint64("seconds", uint64(seconds)))
+	return sleepContext(ctx, c.opts.clock, time.Duration(seconds)*time.Second)
+}
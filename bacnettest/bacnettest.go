@@ -0,0 +1,259 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package bacnettest provides an in-process simulated BACnet device for
+// integration tests of code built on the bacnet client.
+//
+// A Server listens on a loopback UDP port and serves an object registry
+// through the bacnet server mode. Latency and failures can be injected per
+// service:
+//
+//	sim, err := bacnettest.NewServer(1234,
+//		bacnettest.WithObjects(bacnet.NewAnalogValue(1, "ZN-T")),
+//		bacnettest.WithLatency(50*time.Millisecond),
+//	)
+//	if err != nil {
+//		t.Fatal(err)
+//	}
+//	defer sim.Close()
+//
+//	client, _ := bacnet.NewClient(bacnet.WithLocalAddress("127.0.0.1:0"))
+//	client.Connect(ctx)
+//	sim.Bind(client)
+package bacnettest
+
+import (
+	"context"
+	"fmt"
+	"math/rand/v2"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+// options holds configuration for a Server
+type options struct {
+	address       string
+	objects       []*bacnet.LocalObject
+	latency       time.Duration
+	jitter        time.Duration
+	deviceOptions []bacnet.DeviceOption
+	clientOptions []bacnet.Option
+}
+
+// Option is a functional option for configuring a Server
+type Option func(*options)
+
+// WithAddress sets the listen address (default 127.0.0.1:0, a free port)
+func WithAddress(address string) Option {
+	return func(o *options) {
+		o.address = address
+	}
+}
+
+// WithObjects adds objects to the simulated device
+func WithObjects(objects ...*bacnet.LocalObject) Option {
+	return func(o *options) {
+		o.objects = append(o.objects, objects...)
+	}
+}
+
+// WithLatency delays every confirmed request by d plus a random jitter of
+// up to jitter
+func WithLatency(d, jitter time.Duration) Option {
+	return func(o *options) {
+		o.latency = d
+		o.jitter = jitter
+	}
+}
+
+// WithMaxAPDU limits the APDU length accepted by the simulated device.
+// Replies that do not fit are aborted with segmentation-not-supported.
+func WithMaxAPDU(length uint16) Option {
+	return func(o *options) {
+		o.deviceOptions = append(o.deviceOptions, bacnet.WithDeviceMaxAPDU(length))
+	}
+}
+
+// WithDeviceOptions passes options to the simulated bacnet.Device
+func WithDeviceOptions(opts ...bacnet.DeviceOption) Option {
+	return func(o *options) {
+		o.deviceOptions = append(o.deviceOptions, opts...)
+	}
+}
+
+// WithClientOptions passes options to the client hosting the device, e.g.
+// bacnet.WithLogger
+func WithClientOptions(opts ...bacnet.Option) Option {
+	return func(o *options) {
+		o.clientOptions = append(o.clientOptions, opts...)
+	}
+}
+
+// Server is a simulated BACnet device
+type Server struct {
+	// Device is the simulated device
+	Device *bacnet.Device
+
+	// Objects holds the objects of the device. Objects may be added and
+	// values changed while the server runs.
+	Objects *bacnet.ObjectRegistry
+
+	opts   *options
+	client *bacnet.Client
+	addr   *net.UDPAddr
+
+	mu      sync.Mutex
+	latency time.Duration
+	jitter  time.Duration
+	faults  map[bacnet.ConfirmedServiceChoice]*fault
+
+	requests atomic.Int64
+}
+
+// fault is an injected failure of a service
+type fault struct {
+	err   error
+	count int // remaining failures, -1 for all
+}
+
+// NewServer starts a simulated device with the given instance number
+func NewServer(instance uint32, opts ...Option) (*Server, error) {
+	o := &options{address: "127.0.0.1:0"}
+	for _, opt := range opts {
+		opt(o)
+	}
+
+	s := &Server{
+		Objects: bacnet.NewObjectRegistry(),
+		opts:    o,
+		latency: o.latency,
+		jitter:  o.jitter,
+		faults:  make(map[bacnet.ConfirmedServiceChoice]*fault),
+	}
+
+	if err := s.Objects.Add(o.objects...); err != nil {
+		return nil, fmt.Errorf("bacnettest: add objects: %w", err)
+	}
+
+	deviceOpts := append([]bacnet.DeviceOption{
+		bacnet.WithDeviceName(fmt.Sprintf("simulator-%d", instance)),
+		bacnet.WithDeviceModel("bacnettest"),
+		bacnet.WithObjectDatabase(s.Objects),
+	}, o.deviceOptions...)
+	deviceOpts = append(deviceOpts, bacnet.WithRequestInterceptor(s.intercept))
+	s.Device = bacnet.NewDevice(instance, deviceOpts...)
+
+	clientOpts := append([]bacnet.Option{
+		bacnet.WithLocalAddress(o.address),
+	}, o.clientOptions...)
+	clientOpts = append(clientOpts, bacnet.WithLocalDevice(s.Device))
+
+	client, err := bacnet.NewClient(clientOpts...)
+	if err != nil {
+		return nil, fmt.Errorf("bacnettest: %w", err)
+	}
+	if err := client.Connect(context.Background()); err != nil {
+		return nil, fmt.Errorf("bacnettest: %w", err)
+	}
+	s.client = client
+
+	addr, ok := client.LocalAddr().(*net.UDPAddr)
+	if !ok {
+		client.Close()
+		return nil, fmt.Errorf("bacnettest: unexpected local address %v", client.LocalAddr())
+	}
+	s.addr = addr
+
+	return s, nil
+}
+
+// Close stops the simulated device
+func (s *Server) Close() error {
+	return s.client.Close()
+}
+
+// Addr returns the UDP address of the simulated device
+func (s *Server) Addr() *net.UDPAddr {
+	return s.addr
+}
+
+// Bind adds a static binding for the simulated device to client, so that
+// requests reach it without a broadcast Who-Is
+func (s *Server) Bind(client *bacnet.Client) error {
+	return client.BindDevice(s.Device.ObjectID().Instance, s.addr.String())
+}
+
+// SetLatency changes the delay applied to confirmed requests
+func (s *Server) SetLatency(d, jitter time.Duration) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.latency = d
+	s.jitter = jitter
+}
+
+// Fail makes the next count requests of a service fail with err; a
+// negative count fails all of them. err is typically a *bacnet.BACnetError,
+// *bacnet.RejectError or *bacnet.AbortError; bacnet.ErrNoReply drops the
+// requests so that the client times out.
+func (s *Server) Fail(service bacnet.ConfirmedServiceChoice, err error, count int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if count < 0 {
+		count = -1
+	}
+	s.faults[service] = &fault{err: err, count: count}
+}
+
+// ClearFaults removes all injected failures
+func (s *Server) ClearFaults() {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.faults = make(map[bacnet.ConfirmedServiceChoice]*fault)
+}
+
+// Requests returns the number of confirmed requests received
+func (s *Server) Requests() int64 {
+	return s.requests.Load()
+}
+
+// intercept applies the injected latency and failures to a request
+func (s *Server) intercept(req *bacnet.ServerRequest) error {
+	s.requests.Add(1)
+
+	s.mu.Lock()
+	delay := s.latency
+	if s.jitter > 0 {
+		delay += rand.N(s.jitter)
+	}
+	var err error
+	if f, ok := s.faults[req.Service]; ok {
+		err = f.err
+		if f.count > 0 {
+			f.count--
+			if f.count == 0 {
+				delete(s.faults, req.Service)
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	if delay > 0 {
+		time.Sleep(delay)
+	}
+	return err
+}
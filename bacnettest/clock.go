@@ -0,0 +1,208 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnettest
+
+import (
+	"sort"
+	"sync"
+	"time"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+// Clock is a simulated bacnet.Clock. Time only moves when Advance or Set is
+// called, firing the timers and tickers that fall due in order, so that
+// lifetimes, schedules and watchdogs can be tested without sleeping:
+//
+//	clock := bacnettest.NewClock(time.Date(2025, 1, 6, 5, 59, 0, 0, time.UTC))
+//	client, _ := bacnet.NewClient(bacnet.WithClock(clock))
+//	go scheduler.Run(ctx)
+//	clock.BlockUntil(1) // the scheduler is waiting for its next job
+//	clock.Advance(time.Minute)
+type Clock struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	now     time.Time
+	waiters []*clockWaiter
+}
+
+// clockWaiter is a pending timer or ticker
+type clockWaiter struct {
+	clock    *Clock
+	deadline time.Time
+	period   time.Duration // zero for timers
+	c        chan time.Time
+}
+
+// NewClock creates a simulated clock set to start
+func NewClock(start time.Time) *Clock {
+	c := &Clock{now: start}
+	c.cond = sync.NewCond(&c.mu)
+	return c
+}
+
+// Now returns the simulated time
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// NewTimer creates a timer firing once the clock has advanced by d
+func (c *Clock) NewTimer(d time.Duration) bacnet.Timer {
+	w := &clockWaiter{clock: c, c: make(chan time.Time, 1)}
+	c.mu.Lock()
+	w.deadline = c.now.Add(d)
+	c.mu.Unlock()
+
+	if d <= 0 {
+		w.c <- w.deadline
+		return w
+	}
+	c.add(w)
+	return w
+}
+
+// NewTicker creates a ticker firing every d of simulated time
+func (c *Clock) NewTicker(d time.Duration) bacnet.Ticker {
+	if d <= 0 {
+		panic("bacnettest: non-positive interval for NewTicker")
+	}
+	w := &clockWaiter{clock: c, period: d, c: make(chan time.Time, 1)}
+	c.mu.Lock()
+	w.deadline = c.now.Add(d)
+	c.mu.Unlock()
+
+	c.add(w)
+	return clockTicker{w}
+}
+
+// Advance moves the clock forward by d, firing due timers and tickers in
+// deadline order
+func (c *Clock) Advance(d time.Duration) {
+	c.Set(c.Now().Add(d))
+}
+
+// Set moves the clock to t, firing due timers and tickers in deadline
+// order. Moving backwards does not fire anything.
+func (c *Clock) Set(t time.Time) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for {
+		sort.Slice(c.waiters, func(i, j int) bool { return c.waiters[i].deadline.Before(c.waiters[j].deadline) })
+		if len(c.waiters) == 0 || c.waiters[0].deadline.After(t) {
+			break
+		}
+
+		w := c.waiters[0]
+		if w.deadline.After(c.now) {
+			c.now = w.deadline
+		}
+
+		// Like the time package, a tick is dropped if the last is unread
+		select {
+		case w.c <- c.now:
+		default:
+		}
+
+		if w.period > 0 {
+			w.deadline = w.deadline.Add(w.period)
+		} else {
+			c.waiters = c.waiters[1:]
+			c.cond.Broadcast()
+		}
+	}
+
+	c.now = t
+}
+
+// Pending returns the number of timers and tickers waiting to fire
+func (c *Clock) Pending() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
+
+// BlockUntil waits until at least n timers and tickers are waiting to fire,
+// i.e. the code under test has reached its waits and the clock can be
+// advanced deterministically
+func (c *Clock) BlockUntil(n int) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for len(c.waiters) < n {
+		c.cond.Wait()
+	}
+}
+
+// add registers a waiter
+func (c *Clock) add(w *clockWaiter) {
+	c.mu.Lock()
+	c.waiters = append(c.waiters, w)
+	c.cond.Broadcast()
+	c.mu.Unlock()
+}
+
+// remove unregisters a waiter and reports whether it was pending
+func (c *Clock) remove(w *clockWaiter) bool {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for i, other := range c.waiters {
+		if other == w {
+			c.waiters = append(c.waiters[:i], c.waiters[i+1:]...)
+			c.cond.Broadcast()
+			return true
+		}
+	}
+	return false
+}
+
+func (w *clockWaiter) C() <-chan time.Time {
+	return w.c
+}
+
+func (w *clockWaiter) Stop() bool {
+	return w.clock.remove(w)
+}
+
+func (w *clockWaiter) Reset(d time.Duration) bool {
+	active := w.clock.remove(w)
+
+	c := w.clock
+	c.mu.Lock()
+	w.deadline = c.now.Add(d)
+	c.mu.Unlock()
+
+	if d <= 0 {
+		select {
+		case w.c <- w.deadline:
+		default:
+		}
+		return active
+	}
+	c.add(w)
+	return active
+}
+
+// clockTicker is the Ticker view of a periodic waiter
+type clockTicker struct {
+	*clockWaiter
+}
+
+func (t clockTicker) Stop() {
+	t.clock.remove(t.clockWaiter)
+}
+
+var _ bacnet.Clock = (*Clock)(nil)
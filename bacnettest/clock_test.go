@@ -0,0 +1,122 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnettest
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+// waitForTimer waits until the only pending timer of clock fires at
+// deadline, i.e. the code under test is idle until then
+func waitForTimer(t *testing.T, clock *Clock, deadline time.Time) {
+	t.Helper()
+	limit := time.Now().Add(5 * time.Second)
+	for time.Now().Before(limit) {
+		clock.mu.Lock()
+		idle := len(clock.waiters) == 1 && clock.waiters[0].deadline.Equal(deadline)
+		clock.mu.Unlock()
+		if idle {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("no timer waiting for %s", deadline.Format(time.TimeOnly))
+}
+
+func TestPollerBackoff(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	sim, err := NewServer(1234, WithObjects(bacnet.NewAnalogValue(1, "ZN-T")))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer sim.Close()
+
+	start := time.Date(2025, 1, 6, 6, 0, 0, 0, time.UTC)
+	clock := NewClock(start)
+	client, err := bacnet.NewClient(
+		bacnet.WithLocalAddress("127.0.0.1:0"),
+		bacnet.WithClock(clock),
+		bacnet.WithTimeout(time.Minute),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := client.Connect(ctx); err != nil {
+		t.Fatal(err)
+	}
+	defer client.Close()
+	if err := sim.Bind(client); err != nil {
+		t.Fatal(err)
+	}
+
+	sim.Fail(bacnet.ServiceReadPropertyMultiple, &bacnet.BACnetError{
+		Class: bacnet.ErrorClassDevice,
+		Code:  bacnet.ErrorCodeOperationalProblem,
+	}, -1)
+
+	poller := bacnet.NewPoller(client, bacnet.WithPollerBackoff(time.Second, 4*time.Second))
+	point := bacnet.Point{
+		Name:     "ZN-T",
+		DeviceID: 1234,
+		ObjectID: bacnet.NewObjectIdentifier(bacnet.ObjectTypeAnalogValue, 1),
+		Interval: 10 * time.Second,
+	}
+	if err := poller.Add(point); err != nil {
+		t.Fatal(err)
+	}
+	done := make(chan error, 1)
+	go func() { done <- poller.Run(ctx) }()
+
+	update := <-poller.Updates()
+	if update.Quality != bacnet.QualityOffline {
+		t.Fatalf("quality = %s, want offline", update.Quality)
+	}
+
+	// The delay doubles up to the limit, without a read in between
+	now := start
+	for i, backoff := range []time.Duration{time.Second, 2 * time.Second, 4 * time.Second, 4 * time.Second} {
+		waitForTimer(t, clock, now.Add(backoff))
+		if got := sim.Requests(); got != int64(i+1) {
+			t.Fatalf("after %d failures: %d requests", i+1, got)
+		}
+		clock.Advance(backoff - time.Millisecond)
+		waitForTimer(t, clock, now.Add(backoff))
+		now = now.Add(backoff)
+		clock.Set(now)
+	}
+
+	// A device answering again is read at the interval of the point
+	waitForTimer(t, clock, now.Add(4*time.Second))
+	sim.ClearFaults()
+	now = now.Add(4 * time.Second)
+	clock.Set(now)
+
+	update = <-poller.Updates()
+	if update.Quality != bacnet.QualityGood || update.Err != nil {
+		t.Fatalf("update = %s %v, want good", update.Quality, update.Err)
+	}
+	waitForTimer(t, clock, now.Add(point.Interval))
+
+	cancel()
+	if err := <-done; err != context.Canceled {
+		t.Errorf("Run = %v, want %v", err, context.Canceled)
+	}
+}
@@ -0,0 +1,274 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"time"
+)
+
+// BDTEntry is an entry of the Broadcast Distribution Table of a BBMD: a
+// peer BBMD and the broadcast distribution mask of its subnet
+type BDTEntry struct {
+	Address *net.UDPAddr
+
+	// Mask is all ones for peers forwarding broadcasts by unicast; nil
+	// writes all ones
+	Mask net.IPMask
+}
+
+// FDTEntry is an entry of the Foreign Device Table of a BBMD
+type FDTEntry struct {
+	Address *net.UDPAddr
+
+	// TTL is the time-to-live the foreign device registered with
+	TTL time.Duration
+
+	// Remaining is the time left before the entry is purged, including
+	// the 30 second grace period
+	Remaining time.Duration
+}
+
+// bdtEntryLen and fdtEntryLen are the encoded lengths of table entries
+const (
+	bdtEntryLen = 10
+	fdtEntryLen = 10
+)
+
+// bvllNAKs are the BVLC-Result codes rejecting each BVLL request
+var bvllNAKs = map[BVLCFunction]BVLCResultCode{
+	BVLCWriteBroadcastDistributionTable: BVLCResultWriteBroadcastDistributionTableNAK,
+	BVLCReadBroadcastDistributionTable:  BVLCResultReadBroadcastDistributionTableNAK,
+	BVLCRegisterForeignDevice:           BVLCResultRegisterForeignDeviceNAK,
+	BVLCReadForeignDeviceTable:          BVLCResultReadForeignDeviceTableNAK,
+	BVLCDeleteForeignDeviceTableEntry:   BVLCResultDeleteForeignDeviceTableEntryNAK,
+	BVLCDistributeBroadcastToNetwork:    BVLCResultDistributeBroadcastToNetworkNAK,
+}
+
+// ipLink returns the BACnet/IP data link of the client
+func (c *Client) ipLink() (*bipLink, bool) {
+	if multi, ok := c.link.(*multiLink); ok {
+		for _, link := range multi.links {
+			if bip, ok := link.(*bipLink); ok {
+				return bip, true
+			}
+		}
+		return nil, false
+	}
+	bip, ok := c.link.(*bipLink)
+	return bip, ok
+}
+
+// forwardedBy returns the BBMD that forwarded the broadcasts received from
+// a link address, or "" when they were received directly
+func (c *Client) forwardedBy(addr net.Addr) string {
+	bip, ok := c.ipLink()
+	if !ok {
+		return ""
+	}
+	if bbmd := bip.forwardedBy(addr); bbmd != nil {
+		return bbmd.String()
+	}
+	return ""
+}
+
+// bbmdRequest sends a BVLL request to a BBMD given as host[:port], retrying
+// on timeout. It returns the payload of the ack function, or nil when ack is
+// BVLCResult; a NAK for the request is returned as a *BVLCResultError.
+func (c *Client) bbmdRequest(ctx context.Context, bbmd string, function BVLCFunction, payload []byte, ack BVLCFunction) ([]byte, error) {
+	bip, ok := c.ipLink()
+	if !ok {
+		return nil, fmt.Errorf("BBMD management requires BACnet/IP")
+	}
+	addr, err := bip.ParseAddr(bbmd)
+	if err != nil {
+		return nil, err
+	}
+	bbmdAddr := addr.(*net.UDPAddr)
+
+	nak := bvllNAKs[function]
+
+	var body []byte
+	var resultErr error
+	done := func(reply []byte) bool {
+		bvlc, err := DecodeBVLC(reply)
+		if err != nil || int(bvlc.Length) != len(reply) {
+			return false
+		}
+		switch {
+		case bvlc.Function == BVLCResult && len(reply) >= 6:
+			switch code := BVLCResultCode(binary.BigEndian.Uint16(reply[4:])); code {
+			case BVLCResultSuccessfulCompletion:
+				return ack == BVLCResult
+			case nak:
+				resultErr = &BVLCResultError{Code: code}
+				return true
+			}
+		case bvlc.Function == ack && ack != BVLCResult:
+			body = reply[4:]
+			return true
+		}
+		return false
+	}
+
+	for attempt := 0; attempt <= c.opts.retries; attempt++ {
+		reqCtx, cancel := context.WithTimeout(ctx, c.opts.timeout)
+		err := bip.bvllRequest(reqCtx, bbmdAddr, function, payload, done)
+		cancel()
+		switch {
+		case err == nil:
+			return body, resultErr
+		case ctx.Err() != nil:
+			return nil, ctx.Err()
+		case !errors.Is(err, context.DeadlineExceeded):
+			return nil, err
+		}
+	}
+	return nil, fmt.Errorf("%w: no reply from BBMD %s", ErrTimeout, bbmdAddr)
+}
+
+// registerForeignDevice registers as a foreign device with the BBMD of the
+// client options. The BBMD answers with a BVLC-Result: a NAK, from a BBMD
+// not accepting foreign devices or with a full table, is returned as a
+// *BVLCResultError.
+func (c *Client) registerForeignDevice(ctx context.Context) error {
+	bip, ok := c.ipLink()
+	if !ok {
+		return fmt.Errorf("foreign device registration requires BACnet/IP")
+	}
+
+	bbmd := net.JoinHostPort(c.opts.bbmdAddress, strconv.Itoa(c.opts.bbmdPort))
+	addr, err := bip.ParseAddr(bbmd)
+	if err != nil {
+		return fmt.Errorf("resolve BBMD address: %w", err)
+	}
+
+	ttl := make([]byte, 2)
+	binary.BigEndian.PutUint16(ttl, uint16(c.opts.foreignDeviceTTL.Seconds()))
+	if _, err := c.bbmdRequest(ctx, bbmd, BVLCRegisterForeignDevice, ttl, BVLCResult); err != nil {
+		return fmt.Errorf("register with BBMD %s: %w", addr, err)
+	}
+
+	// Broadcasts from an ephemeral port are distributed by the BBMD
+	if c.opts.ephemeralPort {
+		bip.bbmd.Store(addr.(*net.UDPAddr))
+	}
+
+	c.logger.Info("registered as foreign device",
+		slog.String("bbmd", addr.String()),
+		slog.Duration("ttl", c.opts.foreignDeviceTTL),
+	)
+
+	return nil
+}
+
+// ReadBroadcastDistributionTable reads the Broadcast Distribution Table of
+// a BBMD given as host[:port]
+func (c *Client) ReadBroadcastDistributionTable(ctx context.Context, bbmd string) ([]BDTEntry, error) {
+	body, err := c.bbmdRequest(ctx, bbmd, BVLCReadBroadcastDistributionTable, nil, BVLCReadBroadcastDistributionTableAck)
+	if err != nil {
+		return nil, err
+	}
+	if len(body)%bdtEntryLen != 0 {
+		return nil, ErrInvalidResponse
+	}
+
+	var bip bipLink
+	entries := make([]BDTEntry, 0, len(body)/bdtEntryLen)
+	for i := 0; i < len(body); i += bdtEntryLen {
+		addr, _ := bip.Addr(body[i : i+6])
+		entries = append(entries, BDTEntry{
+			Address: addr.(*net.UDPAddr),
+			Mask:    net.IPMask(append([]byte(nil), body[i+6:i+10]...)),
+		})
+	}
+	return entries, nil
+}
+
+// WriteBroadcastDistributionTable replaces the Broadcast Distribution Table
+// of a BBMD given as host[:port]
+func (c *Client) WriteBroadcastDistributionTable(ctx context.Context, bbmd string, entries []BDTEntry) error {
+	if c.isStandby() {
+		return ErrStandby
+	}
+
+	var bip bipLink
+	payload := make([]byte, 0, len(entries)*bdtEntryLen)
+	for i, entry := range entries {
+		mac := bip.MAC(entry.Address)
+		if mac == nil || entry.Address.IP.To4() == nil {
+			return fmt.Errorf("BDT entry %d: %v is not an IPv4 address", i+1, entry.Address)
+		}
+		mask := entry.Mask
+		if mask == nil {
+			mask = net.CIDRMask(32, 32)
+		}
+		if len(mask) != net.IPv4len {
+			return fmt.Errorf("BDT entry %d: invalid mask %v", i+1, mask)
+		}
+		payload = append(payload, mac...)
+		payload = append(payload, mask...)
+	}
+
+	_, err := c.bbmdRequest(ctx, bbmd, BVLCWriteBroadcastDistributionTable, payload, BVLCResult)
+	return err
+}
+
+// ReadForeignDeviceTable reads the Foreign Device Table of a BBMD given as
+// host[:port]
+func (c *Client) ReadForeignDeviceTable(ctx context.Context, bbmd string) ([]FDTEntry, error) {
+	body, err := c.bbmdRequest(ctx, bbmd, BVLCReadForeignDeviceTable, nil, BVLCReadForeignDeviceTableAck)
+	if err != nil {
+		return nil, err
+	}
+	if len(body)%fdtEntryLen != 0 {
+		return nil, ErrInvalidResponse
+	}
+
+	var bip bipLink
+	entries := make([]FDTEntry, 0, len(body)/fdtEntryLen)
+	for i := 0; i < len(body); i += fdtEntryLen {
+		addr, _ := bip.Addr(body[i : i+6])
+		entries = append(entries, FDTEntry{
+			Address:   addr.(*net.UDPAddr),
+			TTL:       time.Duration(binary.BigEndian.Uint16(body[i+6:])) * time.Second,
+			Remaining: time.Duration(binary.BigEndian.Uint16(body[i+8:])) * time.Second,
+		})
+	}
+	return entries, nil
+}
+
+// DeleteForeignDeviceTableEntry removes a foreign device from the Foreign
+// Device Table of a BBMD given as host[:port]
+func (c *Client) DeleteForeignDeviceTableEntry(ctx context.Context, bbmd string, device *net.UDPAddr) error {
+	if c.isStandby() {
+		return ErrStandby
+	}
+
+	var bip bipLink
+	mac := bip.MAC(device)
+	if mac == nil || device.IP.To4() == nil {
+		return fmt.Errorf("%v is not an IPv4 address", device)
+	}
+
+	_, err := c.bbmdRequest(ctx, bbmd, BVLCDeleteForeignDeviceTableEntry, mac, BVLCResult)
+	return err
+}
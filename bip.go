@@ -0,0 +1,323 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edgeo-scada/bacnet/internal/transport"
+)
+
+// maxForwardedOrigins bounds the originating addresses whose forwarding
+// BBMD is remembered
+const maxForwardedOrigins = 4096
+
+// bipLink is the BACnet/IP data link (Annex J). NPDUs are carried in BVLC
+// frames over UDP.
+type bipLink struct {
+	udp    *transport.UDPTransport
+	logger *slog.Logger
+
+	// bbmd distributes broadcasts when set, for foreign devices that
+	// cannot broadcast themselves
+	bbmd atomic.Pointer[net.UDPAddr]
+
+	// bvllMu serializes BVLL requests to BBMDs; bvllWaiter receives the
+	// control messages answering the request in progress
+	bvllMu     sync.Mutex
+	bvllWaiter atomic.Pointer[bvllWaiter]
+
+	// forwarded maps originating addresses to the BBMD that last
+	// forwarded their broadcasts; a direct frame removes the entry
+	forwardedMu sync.RWMutex
+	forwarded   map[string]*net.UDPAddr
+}
+
+// bvllWaiter waits for the BVLL control messages from a BBMD
+type bvllWaiter struct {
+	from    *net.UDPAddr
+	replies chan []byte
+}
+
+// newBIPLink creates a BACnet/IP data link from the client options
+func newBIPLink(o *clientOptions) *bipLink {
+	udp := transport.NewUDPTransport(o.localAddress)
+	udp.SetReadTimeout(o.timeout)
+	udp.SetWriteTimeout(o.timeout)
+	udp.SetSocketOptions(o.socketOptions)
+	if capture := o.udpCapture(); capture != nil {
+		udp.SetCapture(capture)
+	}
+	return &bipLink{udp: udp, logger: o.logger, forwarded: make(map[string]*net.UDPAddr)}
+}
+
+func (l *bipLink) Open(ctx context.Context) error {
+	return l.udp.Open(ctx)
+}
+
+func (l *bipLink) Close() error {
+	return l.udp.Close()
+}
+
+func (l *bipLink) IsClosed() bool {
+	return l.udp.IsClosed()
+}
+
+func (l *bipLink) LocalAddr() net.Addr {
+	return l.udp.LocalAddr()
+}
+
+func (l *bipLink) Send(ctx context.Context, addr net.Addr, npdu []byte) error {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return fmt.Errorf("bacnet/ip: unsupported address %v", addr)
+	}
+	return l.udp.Send(ctx, udpAddr, l.frame(BVLCOriginalUnicastNPDU, npdu))
+}
+
+func (l *bipLink) Broadcast(ctx context.Context, npdu []byte) error {
+	if bbmd := l.bbmd.Load(); bbmd != nil {
+		return l.udp.Send(ctx, bbmd, l.frame(BVLCDistributeBroadcastToNetwork, npdu))
+	}
+	return l.udp.Broadcast(ctx, DefaultPort, l.frame(BVLCOriginalBroadcastNPDU, npdu))
+}
+
+// frame prepends a BVLC header to an NPDU
+func (l *bipLink) frame(function BVLCFunction, npdu []byte) []byte {
+	bvlc := EncodeBVLC(function, len(npdu))
+	packet := make([]byte, 0, len(bvlc)+len(npdu))
+	packet = append(packet, bvlc...)
+	return append(packet, npdu...)
+}
+
+func (l *bipLink) Receive(timeout time.Duration) ([]byte, net.Addr, error) {
+	return l.ReceiveInto(make([]byte, maxPacketSize), timeout)
+}
+
+// ReceiveInto receives the next NPDU into buf
+func (l *bipLink) ReceiveInto(buf []byte, timeout time.Duration) ([]byte, net.Addr, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, nil, linkTimeoutError{}
+		}
+
+		n, addr, err := l.udp.ReceiveInto(buf, remaining)
+		if err != nil {
+			return nil, nil, err
+		}
+		data := buf[:n]
+
+		bvlc, err := DecodeBVLC(data)
+		if err != nil || bvlc.Type != BVLCTypeBACnetIP || int(bvlc.Length) != n {
+			continue
+		}
+
+		// Get NPDU data
+		npdu := data[4:]
+		switch bvlc.Function {
+		case BVLCOriginalUnicastNPDU, BVLCOriginalBroadcastNPDU:
+			l.clearForwarded(addr)
+		case BVLCForwardedNPDU:
+			// The NPDU comes from the originating address, not the BBMD:
+			// replies go straight to the origin
+			if len(npdu) < 6 {
+				continue
+			}
+			origin, _ := l.Addr(npdu[:6])
+			l.setForwarded(origin.(*net.UDPAddr), addr)
+			addr = origin.(*net.UDPAddr)
+			npdu = npdu[6:]
+		default:
+			// BVLL control messages carry no NPDU
+			l.handleBVLL(addr, bvlc.Function, data)
+			continue
+		}
+
+		return npdu, addr, nil
+	}
+}
+
+// setForwarded records the BBMD forwarding the broadcasts of origin. Past
+// maxForwardedOrigins origins, an arbitrary one is forgotten.
+func (l *bipLink) setForwarded(origin, bbmd *net.UDPAddr) {
+	key := origin.String()
+	l.forwardedMu.RLock()
+	prev := l.forwarded[key]
+	l.forwardedMu.RUnlock()
+	if prev != nil && prev.IP.Equal(bbmd.IP) && prev.Port == bbmd.Port {
+		return
+	}
+	l.forwardedMu.Lock()
+	if _, ok := l.forwarded[key]; !ok && len(l.forwarded) >= maxForwardedOrigins {
+		for evicted := range l.forwarded {
+			delete(l.forwarded, evicted)
+			break
+		}
+	}
+	l.forwarded[key] = bbmd
+	l.forwardedMu.Unlock()
+}
+
+// clearForwarded forgets the BBMD of an origin sending directly, from the
+// local network or to this station
+func (l *bipLink) clearForwarded(origin *net.UDPAddr) {
+	key := origin.String()
+	l.forwardedMu.RLock()
+	_, ok := l.forwarded[key]
+	l.forwardedMu.RUnlock()
+	if !ok {
+		return
+	}
+	l.forwardedMu.Lock()
+	delete(l.forwarded, key)
+	l.forwardedMu.Unlock()
+}
+
+// forwardedBy returns the BBMD that last forwarded the broadcasts of an
+// origin, or nil when its broadcasts were received directly
+func (l *bipLink) forwardedBy(origin net.Addr) *net.UDPAddr {
+	if origin == nil {
+		return nil
+	}
+	l.forwardedMu.RLock()
+	defer l.forwardedMu.RUnlock()
+	return l.forwarded[origin.String()]
+}
+
+// handleBVLL processes a BVLL control message. Requests for a BBMD,
+// including the broadcasts of foreign devices, are answered with a NAK: the
+// client is not a BBMD. Results and acks go to the request waiting for
+// them; a NAK that none waits for, such as the BBMD refusing to distribute
+// our broadcasts, is logged.
+func (l *bipLink) handleBVLL(addr *net.UDPAddr, function BVLCFunction, frame []byte) {
+	if nak, ok := bvllNAKs[function]; ok {
+		result := make([]byte, 2)
+		binary.BigEndian.PutUint16(result, uint16(nak))
+		if err := l.udp.Send(context.Background(), addr, l.frame(BVLCResult, result)); err != nil {
+			l.logger.Debug("failed to send BVLC-Result", slog.String("error", err.Error()))
+		}
+		return
+	}
+
+	if l.deliverBVLL(addr, frame) || function != BVLCResult || len(frame) < 6 {
+		return
+	}
+	if code := BVLCResultCode(binary.BigEndian.Uint16(frame[4:])); code != BVLCResultSuccessfulCompletion {
+		l.logger.Warn("BVLC-Result NAK",
+			slog.String("from", addr.String()),
+			slog.String("result", code.String()),
+		)
+	}
+}
+
+// deliverBVLL hands a BVLL control message to the request waiting for it,
+// reporting whether one was
+func (l *bipLink) deliverBVLL(addr net.Addr, frame []byte) bool {
+	w := l.bvllWaiter.Load()
+	from, ok := addr.(*net.UDPAddr)
+	if w == nil || !ok || !from.IP.Equal(w.from.IP) || from.Port != w.from.Port {
+		return false
+	}
+	select {
+	case w.replies <- append([]byte(nil), frame...):
+	default:
+	}
+	return true
+}
+
+// bvllRequest sends a BVLL control message to a BBMD and returns the
+// control messages it sends back until done returns true or ctx ends
+func (l *bipLink) bvllRequest(ctx context.Context, bbmd *net.UDPAddr, function BVLCFunction, payload []byte, done func(reply []byte) bool) error {
+	l.bvllMu.Lock()
+	defer l.bvllMu.Unlock()
+
+	w := &bvllWaiter{from: bbmd, replies: make(chan []byte, 4)}
+	l.bvllWaiter.Store(w)
+	defer l.bvllWaiter.Store(nil)
+
+	if err := l.udp.Send(ctx, bbmd, l.frame(function, payload)); err != nil {
+		return err
+	}
+	for {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case reply := <-w.replies:
+			if done(reply) {
+				return nil
+			}
+		}
+	}
+}
+
+// MAC returns the B/IP address: IPv4 address followed by the UDP port
+func (l *bipLink) MAC(addr net.Addr) []byte {
+	udpAddr, ok := addr.(*net.UDPAddr)
+	if !ok {
+		return nil
+	}
+	mac := make([]byte, 6)
+	copy(mac, udpAddr.IP.To4())
+	binary.BigEndian.PutUint16(mac[4:], uint16(udpAddr.Port))
+	return mac
+}
+
+func (l *bipLink) Addr(mac []byte) (net.Addr, error) {
+	switch len(mac) {
+	case 4:
+		return &net.UDPAddr{IP: net.IP(append([]byte(nil), mac...)), Port: DefaultPort}, nil
+	case 6:
+		return &net.UDPAddr{
+			IP:   net.IP(append([]byte(nil), mac[:4]...)),
+			Port: int(binary.BigEndian.Uint16(mac[4:])),
+		}, nil
+	default:
+		return nil, fmt.Errorf("invalid device address format")
+	}
+}
+
+// ParseAddr parses host[:port]; the port defaults to 47808
+func (l *bipLink) ParseAddr(s string) (net.Addr, error) {
+	host, portStr, err := net.SplitHostPort(s)
+	if err != nil {
+		host, portStr = s, strconv.Itoa(DefaultPort)
+	}
+	port, err := strconv.Atoi(portStr)
+	if err != nil || port < 1 || port > 65535 {
+		return nil, fmt.Errorf("invalid port in %q", s)
+	}
+
+	ip := net.ParseIP(host).To4()
+	if ip == nil {
+		addrs, err := net.LookupIP(host)
+		if err != nil || len(addrs) == 0 || addrs[0].To4() == nil {
+			return nil, fmt.Errorf("invalid IPv4 address %q", host)
+		}
+		ip = addrs[0].To4()
+	}
+
+	return &net.UDPAddr{IP: ip, Port: port}, nil
+}
@@ -0,0 +1,403 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/edgeo-scada/bacnet/internal/transport"
+)
+
+// BACnet/IPv6 (Annex U) constants
+const (
+	// BVLCTypeBACnetIPv6 is the BVLC type of B/IPv6
+	BVLCTypeBACnetIPv6 BVLCType = 0x82
+
+	// DefaultIPv6Multicast is the link-local B/IPv6 broadcast group
+	DefaultIPv6Multicast = "ff02::bac0"
+
+	// SiteIPv6Multicast is the site-local B/IPv6 broadcast group
+	SiteIPv6Multicast = "ff05::bac0"
+)
+
+// BVLC6Function is a B/IPv6 BVLC function
+type BVLC6Function uint8
+
+const (
+	BVLC6Result                       BVLC6Function = 0x00
+	BVLC6OriginalUnicastNPDU          BVLC6Function = 0x01
+	BVLC6OriginalBroadcastNPDU        BVLC6Function = 0x02
+	BVLC6AddressResolution            BVLC6Function = 0x03
+	BVLC6ForwardedAddressResolution   BVLC6Function = 0x04
+	BVLC6AddressResolutionAck         BVLC6Function = 0x05
+	BVLC6VirtualAddressResolution     BVLC6Function = 0x06
+	BVLC6VirtualAddressResolutionAck  BVLC6Function = 0x07
+	BVLC6ForwardedNPDU                BVLC6Function = 0x08
+	BVLC6RegisterForeignDevice        BVLC6Function = 0x09
+	BVLC6DeleteForeignDeviceEntry     BVLC6Function = 0x0A
+	BVLC6DistributeBroadcastToNetwork BVLC6Function = 0x0C
+)
+
+// BIP6Addr is a B/IPv6 station: its 3-octet virtual MAC address and, once
+// known, its UDP address
+type BIP6Addr struct {
+	VMAC [3]byte
+	UDP  *net.UDPAddr
+
+	// vmacSet is false for parsed addresses without a VMAC
+	vmacSet bool
+}
+
+// Network implements net.Addr
+func (a *BIP6Addr) Network() string { return "bacnet-ip6" }
+
+// String returns the VMAC and UDP address
+func (a *BIP6Addr) String() string {
+	vmac := fmt.Sprintf("%02x%02x%02x", a.VMAC[0], a.VMAC[1], a.VMAC[2])
+	if a.UDP == nil {
+		return vmac
+	}
+	return vmac + "@" + a.UDP.String()
+}
+
+// vmacFromInstance returns the VMAC of a device instance (Annex U.5)
+func vmacFromInstance(instance uint32) [3]byte {
+	return [3]byte{byte(instance >> 16), byte(instance >> 8), byte(instance)}
+}
+
+// bip6Link is the BACnet/IPv6 data link (Annex U)
+type bip6Link struct {
+	udp     *transport.UDPTransport
+	vmac    [3]byte
+	group   *net.UDPAddr
+	timeout time.Duration
+
+	mu      sync.Mutex
+	table   map[[3]byte]*net.UDPAddr
+	waiters map[[3]byte][]chan struct{}
+}
+
+// newBIP6Link creates a B/IPv6 data link from the client options
+func newBIP6Link(o *clientOptions) (*bip6Link, error) {
+	var iface *net.Interface
+	if o.ipv6Interface != "" {
+		ifi, err := net.InterfaceByName(o.ipv6Interface)
+		if err != nil {
+			return nil, fmt.Errorf("ipv6 interface: %w", err)
+		}
+		iface = ifi
+	}
+
+	group := &net.UDPAddr{IP: net.ParseIP(o.ipv6Multicast), Port: DefaultPort}
+	if group.IP == nil || !group.IP.IsMulticast() {
+		return nil, fmt.Errorf("invalid IPv6 multicast group %q", o.ipv6Multicast)
+	}
+	if o.ipv6Address != "" {
+		if _, port, err := net.SplitHostPort(o.ipv6Address); err == nil {
+			if p, err := strconv.Atoi(port); err == nil && p != 0 {
+				group.Port = p
+			}
+		}
+	}
+	if iface != nil {
+		group.Zone = iface.Name
+	}
+
+	// The VMAC defaults to the device instance
+	var vmac [3]byte
+	switch {
+	case o.ipv6VMAC != nil:
+		vmac = vmacFromInstance(*o.ipv6VMAC)
+	case o.localDevice != nil:
+		vmac = vmacFromInstance(o.localDevice.ObjectID().Instance)
+	case o.localDeviceID <= 0x3FFFFF:
+		vmac = vmacFromInstance(o.localDeviceID)
+	default:
+		rand.Read(vmac[:])
+	}
+
+	udp := transport.NewUDP6Transport(o.ipv6Address, iface, group)
+	udp.SetReadTimeout(o.timeout)
+	udp.SetWriteTimeout(o.timeout)
+	udp.SetSocketOptions(o.socketOptions)
+	if capture := o.udpCapture(); capture != nil {
+		udp.SetCapture(capture)
+	}
+
+	return &bip6Link{
+		udp:     udp,
+		vmac:    vmac,
+		group:   group,
+		timeout: o.timeout,
+		table:   make(map[[3]byte]*net.UDPAddr),
+		waiters: make(map[[3]byte][]chan struct{}),
+	}, nil
+}
+
+func (l *bip6Link) Open(ctx context.Context) error {
+	return l.udp.Open(ctx)
+}
+
+func (l *bip6Link) Close() error {
+	return l.udp.Close()
+}
+
+func (l *bip6Link) IsClosed() bool {
+	return l.udp.IsClosed()
+}
+
+// LocalAddr returns the local VMAC and UDP address
+func (l *bip6Link) LocalAddr() net.Addr {
+	udp, _ := l.udp.LocalAddr().(*net.UDPAddr)
+	return &BIP6Addr{VMAC: l.vmac, UDP: udp, vmacSet: true}
+}
+
+// frame encodes a BVLC6 message
+func (l *bip6Link) frame(function BVLC6Function, parts ...[]byte) []byte {
+	length := 4
+	for _, p := range parts {
+		length += len(p)
+	}
+	buf := make([]byte, 4, length)
+	buf[0] = byte(BVLCTypeBACnetIPv6)
+	buf[1] = byte(function)
+	binary.BigEndian.PutUint16(buf[2:], uint16(length))
+	for _, p := range parts {
+		buf = append(buf, p...)
+	}
+	return buf
+}
+
+func (l *bip6Link) Send(ctx context.Context, addr net.Addr, npdu []byte) error {
+	dest, ok := addr.(*BIP6Addr)
+	if !ok {
+		return fmt.Errorf("bacnet/ipv6: unsupported address %v", addr)
+	}
+
+	udp := dest.UDP
+	if udp == nil {
+		var err error
+		if udp, err = l.resolve(ctx, dest.VMAC); err != nil {
+			return err
+		}
+	}
+
+	return l.udp.Send(ctx, udp, l.frame(BVLC6OriginalUnicastNPDU, l.vmac[:], dest.VMAC[:], npdu))
+}
+
+func (l *bip6Link) Broadcast(ctx context.Context, npdu []byte) error {
+	return l.udp.Send(ctx, l.group, l.frame(BVLC6OriginalBroadcastNPDU, l.vmac[:], npdu))
+}
+
+// resolve finds the UDP address of a VMAC with Address-Resolution
+func (l *bip6Link) resolve(ctx context.Context, vmac [3]byte) (*net.UDPAddr, error) {
+	l.mu.Lock()
+	if udp, ok := l.table[vmac]; ok {
+		l.mu.Unlock()
+		return udp, nil
+	}
+	ch := make(chan struct{})
+	l.waiters[vmac] = append(l.waiters[vmac], ch)
+	l.mu.Unlock()
+
+	if err := l.udp.Send(ctx, l.group, l.frame(BVLC6AddressResolution, l.vmac[:], vmac[:])); err != nil {
+		return nil, fmt.Errorf("address resolution: %w", err)
+	}
+
+	timer := time.NewTimer(l.timeout)
+	defer timer.Stop()
+
+	select {
+	case <-ch:
+		l.mu.Lock()
+		udp := l.table[vmac]
+		l.mu.Unlock()
+		return udp, nil
+	case <-timer.C:
+	case <-ctx.Done():
+	}
+	return nil, fmt.Errorf("bacnet/ipv6: VMAC %x not resolved: %w", vmac, ErrDeviceNotFound)
+}
+
+// learn records the UDP address of a VMAC
+func (l *bip6Link) learn(vmac [3]byte, udp *net.UDPAddr) {
+	l.mu.Lock()
+	l.table[vmac] = udp
+	waiters := l.waiters[vmac]
+	delete(l.waiters, vmac)
+	l.mu.Unlock()
+
+	for _, ch := range waiters {
+		close(ch)
+	}
+}
+
+func (l *bip6Link) Receive(timeout time.Duration) ([]byte, net.Addr, error) {
+	return l.ReceiveInto(make([]byte, maxPacketSize), timeout)
+}
+
+// ReceiveInto receives the next NPDU into buf
+func (l *bip6Link) ReceiveInto(buf []byte, timeout time.Duration) ([]byte, net.Addr, error) {
+	deadline := time.Now().Add(timeout)
+
+	for {
+		remaining := time.Until(deadline)
+		if remaining <= 0 {
+			return nil, nil, linkTimeoutError{}
+		}
+
+		n, addr, err := l.udp.ReceiveInto(buf, remaining)
+		if err != nil {
+			return nil, nil, err
+		}
+
+		if npdu, from := l.handle(buf[:n], addr); npdu != nil {
+			return npdu, from, nil
+		}
+	}
+}
+
+// handle processes a BVLC6 message, returning the NPDU it carries if any
+func (l *bip6Link) handle(data []byte, addr *net.UDPAddr) ([]byte, net.Addr) {
+	if len(data) < 7 || data[0] != byte(BVLCTypeBACnetIPv6) ||
+		int(binary.BigEndian.Uint16(data[2:])) != len(data) {
+		return nil, nil
+	}
+
+	var src [3]byte
+	copy(src[:], data[4:7])
+	if src == l.vmac {
+		// Our own multicast looped back
+		return nil, nil
+	}
+	body := data[7:]
+
+	switch BVLC6Function(data[1]) {
+	case BVLC6OriginalUnicastNPDU:
+		if len(body) < 3 || [3]byte(body[:3]) != l.vmac {
+			return nil, nil
+		}
+		l.learn(src, addr)
+		return body[3:], &BIP6Addr{VMAC: src, UDP: addr, vmacSet: true}
+
+	case BVLC6OriginalBroadcastNPDU:
+		l.learn(src, addr)
+		return body, &BIP6Addr{VMAC: src, UDP: addr, vmacSet: true}
+
+	case BVLC6ForwardedNPDU:
+		if len(body) < 18 {
+			return nil, nil
+		}
+		orig := &net.UDPAddr{
+			IP:   net.IP(append([]byte(nil), body[:16]...)),
+			Port: int(binary.BigEndian.Uint16(body[16:18])),
+		}
+		l.learn(src, orig)
+		return body[18:], &BIP6Addr{VMAC: src, UDP: orig, vmacSet: true}
+
+	case BVLC6AddressResolution:
+		if len(body) >= 3 && [3]byte(body[:3]) == l.vmac {
+			l.udp.Send(context.Background(), addr, l.frame(BVLC6AddressResolutionAck, l.vmac[:], src[:]))
+		}
+
+	case BVLC6ForwardedAddressResolution:
+		if len(body) >= 21 && [3]byte(body[:3]) == l.vmac {
+			orig := &net.UDPAddr{
+				IP:   net.IP(append([]byte(nil), body[3:19]...)),
+				Port: int(binary.BigEndian.Uint16(body[19:21])),
+			}
+			l.udp.Send(context.Background(), orig, l.frame(BVLC6AddressResolutionAck, l.vmac[:], src[:]))
+		}
+
+	case BVLC6VirtualAddressResolution:
+		l.udp.Send(context.Background(), addr, l.frame(BVLC6VirtualAddressResolutionAck, l.vmac[:], src[:]))
+
+	case BVLC6AddressResolutionAck, BVLC6VirtualAddressResolutionAck:
+		l.learn(src, addr)
+	}
+
+	return nil, nil
+}
+
+// MAC returns the 3-octet VMAC
+func (l *bip6Link) MAC(addr net.Addr) []byte {
+	a, ok := addr.(*BIP6Addr)
+	if !ok {
+		return nil
+	}
+	return append([]byte(nil), a.VMAC[:]...)
+}
+
+func (l *bip6Link) Addr(mac []byte) (net.Addr, error) {
+	if len(mac) != 3 {
+		return nil, fmt.Errorf("invalid B/IPv6 VMAC length %d", len(mac))
+	}
+	a := &BIP6Addr{VMAC: [3]byte(mac), vmacSet: true}
+	l.mu.Lock()
+	a.UDP = l.table[a.VMAC]
+	l.mu.Unlock()
+	return a, nil
+}
+
+// ParseAddr parses "vmac@[ip]:port" or "[ip]:port"; without a VMAC the
+// station is resolved by address when bound to a device instance
+func (l *bip6Link) ParseAddr(s string) (net.Addr, error) {
+	a := &BIP6Addr{}
+	hostPort := s
+	if i := strings.IndexByte(s, '@'); i >= 0 {
+		n, err := strconv.ParseUint(s[:i], 16, 24)
+		if err != nil {
+			return nil, fmt.Errorf("invalid VMAC in %q", s)
+		}
+		a.VMAC = vmacFromInstance(uint32(n))
+		a.vmacSet = true
+		hostPort = s[i+1:]
+	}
+
+	udp, err := net.ResolveUDPAddr("udp6", hostPort)
+	if err != nil {
+		// Port defaults to 47808
+		if udp, err = net.ResolveUDPAddr("udp6", net.JoinHostPort(hostPort, strconv.Itoa(DefaultPort))); err != nil {
+			return nil, fmt.Errorf("invalid IPv6 address %q", s)
+		}
+	}
+	a.UDP = udp
+	return a, nil
+}
+
+// bindDevice records a static binding. Without an explicit VMAC the
+// device instance is used, as B/IPv6 devices do by default.
+func (l *bip6Link) bindDevice(deviceID uint32, addr net.Addr) net.Addr {
+	a, ok := addr.(*BIP6Addr)
+	if !ok {
+		return addr
+	}
+	if !a.vmacSet {
+		a.VMAC = vmacFromInstance(deviceID)
+		a.vmacSet = true
+	}
+	if a.UDP != nil {
+		l.learn(a.VMAC, a.UDP)
+	}
+	return a
+}
@@ -15,16 +15,16 @@
 package bacnet
 
 import (
+	"bytes"
 	"context"
-	"encoding/binary"
+	"errors"
 	"fmt"
 	"log/slog"
 	"net"
+	"reflect"
 	"sync"
 	"sync/atomic"
 	"time"
-
-	"github.com/edgeo-scada/bacnet/internal/transport"
 )
 
 // ConnectionState represents the client connection state
@@ -51,15 +51,24 @@ func (s ConnectionState) String() string {
 
 // Client is a BACnet/IP client
 type Client struct {
-	opts      *clientOptions
-	transport *transport.UDPTransport
+	opts *clientOptions
+	link dataLink
 
 	state    atomic.Int32
 	invokeID atomic.Uint32
 
-	// Pending requests
+	// Pending requests, and the link addresses they were sent to
 	pendingMu  sync.RWMutex
 	pending    map[uint8]chan *APDU
+	pendingTo  map[uint8]string
+
+	// Segmented responses being reassembled, by invoke ID
+	segmentsMu sync.Mutex
+	segments   map[uint8]*segmentedResponse
+
+	// Outstanding confirmed requests, by device instance
+	transactionsMu sync.Mutex
+	transactions   map[uint32]*transactionSlots
 
 	// Discovered devices
 	devicesMu sync.RWMutex
@@ -68,6 +77,49 @@ type Client struct {
 	// COV subscriptions
 	covMu     sync.RWMutex
 	covSubs   map[uint32]COVHandler
+	covProps  map[uint32]PropertyIdentifier // SubscribeCOVProperty subscriptions
+	covNextID uint32
+
+	// Interceptors of confirmed requests
+	interceptorsMu sync.RWMutex
+	interceptors   []Interceptor
+
+	// Handlers of received APDUs
+	packetHandlersMu sync.RWMutex
+	packetHandlers   []PacketHandler
+
+	// Collectors of received network layer messages, by registration
+	networkMu        sync.RWMutex
+	networkWatchers  map[int]networkWatcher
+	networkWatcherID int
+
+	// Collectors of received I-Am, by registration
+	iAmMu        sync.RWMutex
+	iAmWatchers  map[int]iAmWatcher
+	iAmWatcherID int
+
+	// Collectors of received I-Have, by registration
+	iHaveMu        sync.RWMutex
+	iHaveWatchers  map[int]iHaveWatcher
+	iHaveWatcherID int
+
+	// State texts of remote binary and multi-state objects
+	stateTextsMu sync.Mutex
+	stateTexts   map[stateTextKey]*StateTexts
+
+	// Object lists of remote devices, by device instance
+	objectListsMu sync.Mutex
+	objectLists   map[uint32]*objectList
+
+	// Local device served in server mode
+	server *Device
+
+	// COV subscriptions from other devices to local objects
+	servedCOVMu   sync.Mutex
+	servedCOVSubs map[servedCOVKey]*servedCOVSubscription
+
+	// Hot-standby coordination, nil unless redundant
+	redundancy *Redundancy
 
 	// Metrics
 	metrics *Metrics
@@ -75,13 +127,17 @@ type Client struct {
 	// Logger
 	logger *slog.Logger
 
+	// Protocol trace of the data links without a datagram capture hook
+	npduTrace ProtocolTraceFunc
+
 	// Receiver goroutine
 	receiverCtx    context.Context
 	receiverCancel context.CancelFunc
 	receiverDone   chan struct{}
 }
 
-// COVHandler is called when a COV notification is received
+// COVHandler is called when a COV notification is received. It runs on the
+// receive goroutine and must not block.
 type COVHandler func(deviceID uint32, objectID ObjectIdentifier, values []PropertyValue)
 
 // NewClient creates a new BACnet client
@@ -91,19 +147,84 @@ func NewClient(opts ...Option) (*Client, error) {
 		opt(options)
 	}
 
+	if options.site != "" {
+		options.logger = options.logger.With(slog.String("site", options.site))
+	}
+	if options.captureWriter != nil {
+		options.capture = newPcapWriter(options.captureWriter, options.logger)
+	}
+	if options.traceLog {
+		options.trace = logProtocolTrace(options.logger, options.trace)
+	}
+
 	c := &Client{
 		opts:     options,
 		pending:  make(map[uint8]chan *APDU),
+		pendingTo: make(map[uint8]string),
 		devices:  make(map[uint32]*DeviceInfo),
 		covSubs:  make(map[uint32]COVHandler),
-		metrics:  NewMetrics(),
+		covProps: make(map[uint32]PropertyIdentifier),
+
+		stateTexts: make(map[stateTextKey]*StateTexts),
+		objectLists: make(map[uint32]*objectList),
+		transactions: make(map[uint32]*transactionSlots),
+		segments:     make(map[uint8]*segmentedResponse),
+		server:   options.localDevice,
+		metrics:  newMetrics(options.latencyBuckets),
 		logger:   options.logger,
+
+		servedCOVSubs: make(map[servedCOVKey]*servedCOVSubscription),
 	}
+	c.metrics.site = options.site
 
-	// Create transport
-	c.transport = transport.NewUDPTransport(options.localAddress)
-	c.transport.SetReadTimeout(options.timeout)
-	c.transport.SetWriteTimeout(options.timeout)
+	// Notify subscribers of local object changes
+	if c.server != nil {
+		if reporter, ok := c.server.Database().(COVReporter); ok {
+			reporter.OnCOV(c.handleLocalCOV)
+		}
+	}
+
+	if options.ephemeralPort {
+		if options.bbmdAddress == "" {
+			return nil, fmt.Errorf("ephemeral port requires a BBMD for broadcasts")
+		}
+		host := options.localAddress
+		if h, _, err := net.SplitHostPort(host); err == nil {
+			host = h
+		}
+		options.localAddress = net.JoinHostPort(host, "0")
+	}
+
+	// Create data link
+	switch {
+	case options.scHubURI != "":
+		c.link = newSCLink(options)
+		c.npduTrace = options.trace
+	case options.mstpPort != "":
+		if options.maxAPDULength > MSTPMaxAPDULength {
+			options.maxAPDULength = MSTPMaxAPDULength
+		}
+		c.link = newMSTPLink(options)
+		c.npduTrace = options.trace
+	case options.ethInterface != "":
+		if options.maxAPDULength > EthernetMaxAPDULength {
+			options.maxAPDULength = EthernetMaxAPDULength
+		}
+		c.link = newEthLink(options)
+		c.npduTrace = options.trace
+	case options.ipv6:
+		bip6, err := newBIP6Link(options)
+		if err != nil {
+			return nil, err
+		}
+		if options.dualStack {
+			c.link = newMultiLink(newBIPLink(options), bip6)
+		} else {
+			c.link = bip6
+		}
+	default:
+		c.link = newBIPLink(options)
+	}
 
 	return c, nil
 }
@@ -116,12 +237,14 @@ func (c *Client) Connect(ctx context.Context) error {
 
 	c.metrics.ConnectAttempts.Inc()
 
-	if err := c.transport.Open(ctx); err != nil {
+	if err := c.link.Open(ctx); err != nil {
 		c.state.Store(int32(StateDisconnected))
 		c.metrics.ConnectFailures.Inc()
 		return fmt.Errorf("open transport: %w", err)
 	}
 
+	c.restoreState(ctx)
+
 	// Start receiver goroutine
 	c.receiverCtx, c.receiverCancel = context.WithCancel(context.Background())
 	c.receiverDone = make(chan struct{})
@@ -131,7 +254,7 @@ func (c *Client) Connect(ctx context.Context) error {
 	c.metrics.ConnectSuccesses.Inc()
 
 	c.logger.Info("connected",
-		slog.String("local_addr", c.transport.LocalAddr().String()),
+		slog.String("local_addr", c.link.LocalAddr().String()),
 	)
 
 	// Register as foreign device if BBMD is configured
@@ -141,6 +264,16 @@ func (c *Client) Connect(ctx context.Context) error {
 				slog.String("error", err.Error()),
 			)
 		}
+		go c.renewForeignDevice(c.receiverCtx)
+	}
+
+	// Announce the local device
+	if c.server != nil {
+		if err := c.IAm(ctx); err != nil {
+			c.logger.Warn("failed to announce local device",
+				slog.String("error", err.Error()),
+			)
+		}
 	}
 
 	return nil
@@ -167,9 +300,10 @@ func (c *Client) Close() error {
 		close(ch)
 	}
 	c.pending = make(map[uint8]chan *APDU)
+	c.pendingTo = make(map[uint8]string)
 	c.pendingMu.Unlock()
 
-	if err := c.transport.Close(); err != nil {
+	if err := c.link.Close(); err != nil {
 		return fmt.Errorf("close transport: %w", err)
 	}
 
@@ -182,16 +316,63 @@ func (c *Client) State() ConnectionState {
 	return ConnectionState(c.state.Load())
 }
 
+// LocalAddr returns the local address of the client, or nil if it is not
+// connected
+func (c *Client) LocalAddr() net.Addr {
+	return c.link.LocalAddr()
+}
+
 // Metrics returns the client metrics
 func (c *Client) Metrics() *Metrics {
 	return c.metrics
 }
 
+// Site returns the site name set with WithSite
+func (c *Client) Site() string {
+	return c.opts.site
+}
+
+// Namespaced prefixes name with the site name, e.g. "building-a/1234/ai:1",
+// so that device IDs repeated across sites don't collide in aggregated
+// topics, paths and point references. Without a site the name is returned
+// unchanged.
+func (c *Client) Namespaced(name string) string {
+	if c.opts.site == "" {
+		return name
+	}
+	return c.opts.site + "/" + name
+}
+
 // nextInvokeID returns the next invoke ID
 func (c *Client) nextInvokeID() uint8 {
 	return uint8(c.invokeID.Add(1) & 0xFF)
 }
 
+// nextSubscriberID reserves an unused subscriber process identifier. The
+// identifier stays reserved until a handler is registered for it or it is
+// released.
+func (c *Client) nextSubscriberID() uint32 {
+	c.covMu.Lock()
+	defer c.covMu.Unlock()
+	for {
+		c.covNextID++
+		if _, used := c.covSubs[c.covNextID]; c.covNextID != 0 && !used {
+			c.covSubs[c.covNextID] = nil
+			return c.covNextID
+		}
+	}
+}
+
+// releaseSubscriberID frees a reserved subscriber process identifier that
+// has no handler
+func (c *Client) releaseSubscriberID(subID uint32) {
+	c.covMu.Lock()
+	if handler, ok := c.covSubs[subID]; ok && handler == nil {
+		delete(c.covSubs, subID)
+	}
+	c.covMu.Unlock()
+}
+
 // receiver handles incoming packets
 func (c *Client) receiver() {
 	defer close(c.receiverDone)
@@ -203,12 +384,28 @@ func (c *Client) receiver() {
 		default:
 		}
 
-		data, addr, err := c.transport.ReceiveWithTimeout(100 * time.Millisecond)
+		// Buffered links receive into a pooled buffer, recycled once the
+		// packet is handled
+		var (
+			buf  *[]byte
+			data []byte
+			addr net.Addr
+			err  error
+		)
+		if br, ok := c.link.(bufferedReceiver); ok {
+			buf = packetPool.Get().(*[]byte)
+			data, addr, err = br.ReceiveInto(*buf, 100*time.Millisecond)
+		} else {
+			data, addr, err = c.link.Receive(100 * time.Millisecond)
+		}
 		if err != nil {
+			if buf != nil {
+				packetPool.Put(buf)
+			}
 			if netErr, ok := err.(net.Error); ok && netErr.Timeout() {
 				continue
 			}
-			if c.transport.IsClosed() {
+			if c.link.IsClosed() {
 				return
 			}
 			c.logger.Debug("receive error", slog.String("error", err.Error()))
@@ -217,30 +414,21 @@ func (c *Client) receiver() {
 
 		c.metrics.BytesReceived.Add(int64(len(data)))
 		c.metrics.RecordActivity()
+		c.traceNPDU(false, addr, data)
 
-		go c.handlePacket(data, addr)
+		go func() {
+			c.handlePacket(data, addr)
+			if buf != nil {
+				packetPool.Put(buf)
+			}
+		}()
 	}
 }
 
-// handlePacket processes an incoming packet
-func (c *Client) handlePacket(data []byte, addr *net.UDPAddr) {
-	// Decode BVLC header
-	bvlc, err := DecodeBVLC(data)
-	if err != nil {
-		c.logger.Debug("invalid BVLC", slog.String("error", err.Error()))
-		return
-	}
-
-	// Get NPDU data
-	npduData := data[4:]
-	if bvlc.Function == BVLCForwardedNPDU {
-		// Skip forwarded address (6 bytes)
-		if len(npduData) < 6 {
-			return
-		}
-		npduData = npduData[6:]
-	}
-
+// handlePacket processes an incoming NPDU. The NPDU and the decoded APDU
+// share the receive buffer, which is recycled when handlePacket returns:
+// anything kept longer must be copied.
+func (c *Client) handlePacket(npduData []byte, addr net.Addr) {
 	// Decode NPDU
 	npdu, offset, err := DecodeNPDU(npduData)
 	if err != nil {
@@ -248,8 +436,9 @@ func (c *Client) handlePacket(data []byte, addr *net.UDPAddr) {
 		return
 	}
 
-	// Skip network layer messages
+	// Network layer messages carry no APDU
 	if npdu.Control&NPDUControlNetworkLayerMessage != 0 {
+		c.dispatchNetworkMessage(npdu, addr)
 		return
 	}
 
@@ -262,13 +451,21 @@ func (c *Client) handlePacket(data []byte, addr *net.UDPAddr) {
 	}
 
 	c.metrics.ResponsesReceived.Inc()
+	c.dispatchPacket(npdu, apdu, addr)
 
 	// Handle based on PDU type
 	switch apdu.Type {
+	case PDUTypeConfirmedRequest:
+		c.handleConfirmedRequest(apdu, addr, npdu)
+
 	case PDUTypeUnconfirmedRequest:
 		c.handleUnconfirmedRequest(apdu, addr, npdu)
 
 	case PDUTypeSimpleAck, PDUTypeComplexAck:
+		if apdu.Segmented {
+			c.handleSegment(apdu, addr, npdu)
+			return
+		}
 		c.handleResponse(apdu)
 
 	case PDUTypeError:
@@ -281,89 +478,61 @@ func (c *Client) handlePacket(data []byte, addr *net.UDPAddr) {
 
 	case PDUTypeAbort:
 		c.metrics.AbortsReceived.Inc()
+		c.metrics.AbortOrigins.Inc(abortOrigin(apdu.Server))
+		if !apdu.Server {
+			// A client aborting its request to our server: the invoke
+			// ID is the client's, not one of our pending requests
+			c.logger.Debug("abort from client",
+				slog.String("address", addr.String()),
+				slog.Uint64("invoke_id", uint64(apdu.InvokeID)),
+				slog.String("reason", AbortReason(apdu.Service).String()),
+			)
+			return
+		}
 		c.handleResponse(apdu)
 	}
 }
 
 // handleUnconfirmedRequest handles unconfirmed service requests
-func (c *Client) handleUnconfirmedRequest(apdu *APDU, addr *net.UDPAddr, npdu *NPDU) {
+func (c *Client) handleUnconfirmedRequest(apdu *APDU, addr net.Addr, npdu *NPDU) {
 	switch UnconfirmedServiceChoice(apdu.Service) {
 	case ServiceIAm:
 		c.handleIAm(apdu.Data, addr, npdu)
 
+	case ServiceWhoIs:
+		c.handleWhoIs(apdu.Data, addr, npdu)
+
+	case ServiceIHave:
+		c.handleIHave(apdu.Data, addr, npdu)
+
+	case ServiceWhoHas:
+		c.handleWhoHas(apdu.Data, addr, npdu)
+
 	case ServiceUnconfirmedCOVNotification:
 		c.handleCOVNotification(apdu.Data)
 	}
 }
 
 // handleIAm handles I-Am responses
-func (c *Client) handleIAm(data []byte, addr *net.UDPAddr, npdu *NPDU) {
+func (c *Client) handleIAm(data []byte, addr net.Addr, npdu *NPDU) {
 	c.metrics.IAmReceived.Inc()
 
-	if len(data) < 4 {
+	d := NewDecoder(data)
+	oid := d.ObjectIdentifier()
+	maxAPDU := uint16(d.Unsigned())
+	segmentation := Segmentation(d.Enumerated())
+	vendorID := uint16(d.Unsigned())
+	if d.Err() != nil || oid.Type != ObjectTypeDevice {
 		return
 	}
 
-	// Decode device object identifier
-	tagNum, _, length, headerLen, err := DecodeTagNumber(data)
-	if err != nil || tagNum != uint8(TagObjectID) || length != 4 {
-		return
-	}
-
-	oidValue := binary.BigEndian.Uint32(data[headerLen:])
-	oid := DecodeObjectIdentifier(oidValue)
-
-	if oid.Type != ObjectTypeDevice {
+	// Ignore our own announcements
+	if c.server != nil && oid.Instance == c.server.opts.instance {
 		return
 	}
+	c.metrics.device(oid.Instance).RecordSeen()
 
-	offset := headerLen + 4
-
-	// Decode max APDU length
-	if len(data) < offset+1 {
-		return
-	}
-	tagNum, _, length, headerLen, err = DecodeTagNumber(data[offset:])
-	if err != nil {
-		return
-	}
-	maxAPDU := uint16(DecodeUnsigned(data[offset+headerLen : offset+headerLen+length]))
-	offset += headerLen + length
-
-	// Decode segmentation supported
-	if len(data) < offset+1 {
-		return
-	}
-	tagNum, _, length, headerLen, err = DecodeTagNumber(data[offset:])
-	if err != nil {
-		return
-	}
-	segmentation := Segmentation(DecodeUnsigned(data[offset+headerLen : offset+headerLen+length]))
-	offset += headerLen + length
-
-	// Decode vendor ID
-	if len(data) < offset+1 {
-		return
-	}
-	tagNum, _, length, headerLen, err = DecodeTagNumber(data[offset:])
-	if err != nil {
-		return
-	}
-	vendorID := uint16(DecodeUnsigned(data[offset+headerLen : offset+headerLen+length]))
-
-	// Build device address
-	var deviceAddr Address
-	if npdu.Control&NPDUControlSourceSpecifier != 0 {
-		deviceAddr = Address{
-			Net:  npdu.SrcNet,
-			Addr: npdu.SrcAddr,
-		}
-	} else {
-		deviceAddr = Address{
-			Net:  0,
-			Addr: addr.IP.To4(),
-		}
-	}
+	deviceAddr := c.sourceAddress(addr, npdu)
 
 	device := &DeviceInfo{
 		ObjectID:      oid,
@@ -371,28 +540,103 @@ func (c *Client) handleIAm(data []byte, addr *net.UDPAddr, npdu *NPDU) {
 		MaxAPDULength: maxAPDU,
 		Segmentation:  segmentation,
 		VendorID:      vendorID,
+		ForwardedBy:   c.forwardedBy(addr),
 	}
 
 	c.devicesMu.Lock()
-	_, exists := c.devices[oid.Instance]
+	prev, exists := c.devices[oid.Instance]
 	c.devices[oid.Instance] = device
 	c.devicesMu.Unlock()
 
 	if !exists {
 		c.metrics.DevicesDiscovered.Inc()
 	}
+	c.dispatchIAm(device)
+	if !exists || prev.Address.Net != deviceAddr.Net || !bytes.Equal(prev.Address.Addr, deviceAddr.Addr) ||
+		prev.MaxAPDULength != maxAPDU || prev.Segmentation != segmentation || prev.VendorID != vendorID ||
+		prev.ForwardedBy != device.ForwardedBy {
+		c.storeDevice(device)
+	}
 
 	c.logger.Debug("device discovered",
 		slog.Uint64("device_id", uint64(oid.Instance)),
 		slog.String("address", addr.String()),
 		slog.Uint64("vendor_id", uint64(vendorID)),
+		slog.String("forwarded_by", device.ForwardedBy),
 	)
 }
 
-// handleCOVNotification handles COV notification
+// sourceAddress returns the BACnet address of the source of a received
+// NPDU: its network and MAC address when routed, the link address otherwise
+func (c *Client) sourceAddress(addr net.Addr, npdu *NPDU) Address {
+	if npdu.Control&NPDUControlSourceSpecifier != 0 {
+		return Address{
+			Net:  npdu.SrcNet,
+			Addr: npdu.SrcAddr,
+		}
+	}
+	return Address{
+		Net:  0,
+		Addr: c.link.MAC(addr),
+	}
+}
+
+// handleCOVNotification decodes a COV notification and passes it to the
+// handler of its subscription
 func (c *Client) handleCOVNotification(data []byte) {
 	c.metrics.COVNotifications.Inc()
-	// TODO: Decode and dispatch to registered handlers
+
+	subID, deviceID, objectID, values, err := c.decodeCOVNotification(data)
+	if err != nil {
+		c.logger.Debug("invalid COV notification", slog.String("error", err.Error()))
+		return
+	}
+
+	c.covMu.RLock()
+	handler := c.covSubs[subID]
+	c.covMu.RUnlock()
+
+	if handler != nil {
+		handler(deviceID, objectID, values)
+	}
+}
+
+// decodeCOVNotification decodes the subscriber process identifier, the
+// initiating device, the monitored object and the values of a COV
+// notification
+func (c *Client) decodeCOVNotification(data []byte) (uint32, uint32, ObjectIdentifier, []PropertyValue, error) {
+	d := NewDecoder(data)
+	subID := d.ContextUnsigned(0)
+	device := d.ContextObjectIdentifier(1)
+	objectID := d.ContextObjectIdentifier(2)
+	d.ContextUnsigned(3)
+	d.Opening(4)
+
+	var values []PropertyValue
+	for d.Err() == nil && !d.IsClosing(4) {
+		pv := PropertyValue{ObjectID: objectID, PropertyID: PropertyIdentifier(d.ContextEnumerated(0))}
+		if d.IsContext(1) {
+			index := d.ContextUnsigned(1)
+			pv.ArrayIndex = &index
+		}
+		d.Opening(2)
+		value, err := c.decodeApplicationValues(d, 2)
+		if err != nil {
+			return 0, 0, ObjectIdentifier{}, nil, err
+		}
+		pv.Value = value
+		if d.IsContext(3) {
+			priority := uint8(d.ContextUnsigned(3))
+			pv.Priority = &priority
+		}
+		values = append(values, pv)
+	}
+	d.Closing(4)
+
+	if err := d.Err(); err != nil {
+		return 0, 0, ObjectIdentifier{}, nil, fmt.Errorf("%w: %v", ErrInvalidAPDU, err)
+	}
+	return subID, device.Instance, objectID, values, nil
 }
 
 // handleResponse handles a response to a pending request
@@ -402,6 +646,10 @@ func (c *Client) handleResponse(apdu *APDU) {
 	c.pendingMu.RUnlock()
 
 	if ok {
+		// The requester decodes the response after the receive buffer
+		// is recycled
+		apdu.Data = bytes.Clone(apdu.Data)
+
 		select {
 		case ch <- apdu:
 		default:
@@ -410,47 +658,108 @@ func (c *Client) handleResponse(apdu *APDU) {
 }
 
 // sendRequest sends a confirmed request and waits for response
-func (c *Client) sendRequest(ctx context.Context, addr *net.UDPAddr, service ConfirmedServiceChoice, data []byte) (*APDU, error) {
+func (c *Client) sendRequest(ctx context.Context, deviceID uint32, addr net.Addr, service ConfirmedServiceChoice, data []byte) (*APDU, error) {
+	return c.sendRoutedRequest(ctx, &Request{DeviceID: deviceID, Address: addr, Service: service, Data: data}, nil)
+}
+
+// sendRoutedRequest sends a confirmed request through the interceptors to
+// the source of a previously received NPDU, routing it through the
+// originating network if needed
+func (c *Client) sendRoutedRequest(ctx context.Context, req *Request, route *NPDU) (*APDU, error) {
 	if c.State() != StateConnected {
 		return nil, ErrNotConnected
 	}
 
-	invokeID := c.nextInvokeID()
+	invoke := c.intercept(func(ctx context.Context, req *Request) (*APDU, error) {
+		release, err := c.acquireTransaction(ctx, req.DeviceID)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+
+		start := c.opts.clock.Now()
+		resp, err := c.invoke(ctx, req, route)
+		c.recordDeviceRequest(req.DeviceID, c.opts.clock.Now().Sub(start), err)
+		return resp, err
+	})
+	return invoke(ctx, req)
+}
+
+// recordDeviceRequest updates the metrics of the device a request went to
+func (c *Client) recordDeviceRequest(deviceID uint32, rtt time.Duration, err error) {
+	if deviceID == wildcardDeviceInstance {
+		return
+	}
+
+	dm := c.metrics.device(deviceID)
+	dm.RequestsSent.Inc()
+	switch {
+	case err == nil:
+		dm.RequestsSucceeded.Inc()
+	case IsTimeout(err):
+		dm.RequestsTimedOut.Inc()
+	default:
+		dm.RequestsFailed.Inc()
+	}
+	if err != nil {
+		dm.RecordError(err)
+	}
+	if err == nil || isDeviceReply(err) {
+		dm.RequestLatency.Record(rtt)
+		dm.RecordSeen()
+	}
+}
+
+// invoke sends a confirmed request and waits for the response
+func (c *Client) invoke(ctx context.Context, req *Request, route *NPDU) (*APDU, error) {
+	addr, service := req.Address, req.Service
 
 	// Create response channel
 	respCh := make(chan *APDU, 1)
+	invokeID, err := c.allocInvokeID(respCh)
+	if err != nil {
+		return nil, err
+	}
+	req.InvokeID = invokeID
+
 	c.pendingMu.Lock()
-	c.pending[invokeID] = respCh
+	c.pendingTo[invokeID] = addr.String()
 	c.pendingMu.Unlock()
 
 	defer func() {
 		c.pendingMu.Lock()
 		delete(c.pending, invokeID)
+		delete(c.pendingTo, invokeID)
 		c.pendingMu.Unlock()
+		c.dropSegments(invokeID)
 	}()
 
-	// Encode APDU
-	apdu := EncodeConfirmedRequest(invokeID, service, data, 0, 5)
+	// Encode APDU, accepting a segmented response when configured
+	var maxSegments uint8
+	if c.acceptsSegments() {
+		maxSegments = EncodeMaxSegments(maxResponseSegments)
+	}
+	apdu := EncodeConfirmedRequest(invokeID, service, req.Data, maxSegments, EncodeMaxAPDU(int(c.opts.maxAPDULength)))
+	if c.acceptsSegments() {
+		apdu[0] |= segmentedResponseAccepted
+	}
 
 	// Encode NPDU
-	npdu := EncodeNPDU(true, NPDUControlPriorityNormal)
-
-	// Encode BVLC
-	bvlc := EncodeBVLC(BVLCOriginalUnicastNPDU, len(npdu)+len(apdu))
+	npdu := encodeRoutedNPDU(route, true, req.Priority)
 
 	// Build packet
-	packet := make([]byte, 0, len(bvlc)+len(npdu)+len(apdu))
-	packet = append(packet, bvlc...)
+	packet := make([]byte, 0, len(npdu)+len(apdu))
 	packet = append(packet, npdu...)
 	packet = append(packet, apdu...)
 
 	// Send request
-	start := time.Now()
+	start := c.opts.clock.Now()
 	c.metrics.RequestsSent.Inc()
 	c.metrics.ActiveRequests.Inc()
 	defer c.metrics.ActiveRequests.Dec()
 
-	if err := c.transport.Send(ctx, addr, packet); err != nil {
+	c.traceNPDU(true, addr, packet)
+	if err := c.link.Send(ctx, addr, packet); err != nil {
 		c.metrics.RequestsFailed.Inc()
 		return nil, fmt.Errorf("send request: %w", err)
 	}
@@ -458,74 +767,130 @@ func (c *Client) sendRequest(ctx context.Context, addr *net.UDPAddr, service Con
 	c.metrics.BytesSent.Add(int64(len(packet)))
 
 	// Wait for response
-	select {
-	case <-ctx.Done():
-		c.metrics.RequestsTimedOut.Inc()
-		return nil, ErrTimeout
+	resp, ok, err := c.awaitResponse(ctx, invokeID, addr, packet, respCh)
+	if err != nil {
+		return nil, err
+	}
+	c.metrics.RequestLatency.Record(c.opts.clock.Now().Sub(start))
 
-	case resp, ok := <-respCh:
-		c.metrics.RequestLatency.Record(time.Since(start))
+	if !ok {
+		return nil, ErrConnectionClosed
+	}
 
-		if !ok {
-			return nil, ErrConnectionClosed
+	// Acks and errors echo the service choice of the request
+	switch resp.Type {
+	case PDUTypeSimpleAck, PDUTypeComplexAck, PDUTypeError:
+		if got := ConfirmedServiceChoice(resp.Service); got != service {
+			c.metrics.RequestsFailed.Inc()
+			return nil, &ServiceMismatchError{InvokeID: invokeID, Expected: service, Received: got}
 		}
+	}
 
-		switch resp.Type {
-		case PDUTypeSimpleAck, PDUTypeComplexAck:
-			c.metrics.RequestsSucceeded.Inc()
-			return resp, nil
-
-		case PDUTypeError:
-			c.metrics.RequestsFailed.Inc()
-			return nil, c.decodeError(resp.Data)
+	switch resp.Type {
+	case PDUTypeSimpleAck, PDUTypeComplexAck:
+		c.metrics.RequestsSucceeded.Inc()
+		return resp, nil
 
-		case PDUTypeReject:
-			c.metrics.RequestsFailed.Inc()
-			return nil, &RejectError{
-				InvokeID: resp.InvokeID,
-				Reason:   RejectReason(resp.Service),
-			}
+	case PDUTypeError:
+		c.metrics.RequestsFailed.Inc()
+		err := c.decodeError(ConfirmedServiceChoice(resp.Service), resp.Data)
+		var bacErr *BACnetError
+		if errors.As(err, &bacErr) {
+			c.metrics.ErrorCodes.Inc(bacErr.Class.String() + "/" + bacErr.Code.String())
+		}
+		return nil, err
 
-		case PDUTypeAbort:
-			c.metrics.RequestsFailed.Inc()
-			return nil, &AbortError{
-				InvokeID: resp.InvokeID,
-				Reason:   AbortReason(resp.Service),
-			}
+	case PDUTypeReject:
+		c.metrics.RequestsFailed.Inc()
+		c.metrics.RejectReasons.Inc(RejectReason(resp.Service).String())
+		return nil, &RejectError{
+			InvokeID: resp.InvokeID,
+			Reason:   RejectReason(resp.Service),
+		}
 
-		default:
-			return nil, fmt.Errorf("%w: unexpected PDU type %02x", ErrInvalidResponse, resp.Type)
+	case PDUTypeAbort:
+		c.metrics.RequestsFailed.Inc()
+		c.metrics.AbortReasons.Inc(AbortReason(resp.Service).String())
+		return nil, &AbortError{
+			InvokeID: resp.InvokeID,
+			Server:   resp.Server,
+			Reason:   AbortReason(resp.Service),
 		}
+
+	default:
+		return nil, fmt.Errorf("%w: unexpected PDU type %02x", ErrInvalidResponse, resp.Type)
 	}
 }
 
-// decodeError decodes a BACnet error response
-func (c *Client) decodeError(data []byte) error {
-	if len(data) < 2 {
-		return ErrInvalidResponse
+// awaitResponse waits for the response to a request, sending the request
+// again each time the APDU timeout expires without one, up to the retries
+// of the client. A segmented response being received is waited for without
+// resending.
+func (c *Client) awaitResponse(ctx context.Context, invokeID uint8, addr net.Addr, packet []byte, respCh <-chan *APDU) (*APDU, bool, error) {
+	// Without an APDU timeout, the request waits for ctx alone
+	var (
+		timer   Timer
+		expired <-chan time.Time
+	)
+	if c.opts.timeout > 0 {
+		timer = c.opts.clock.NewTimer(c.opts.timeout)
+		defer timer.Stop()
+		expired = timer.C()
 	}
 
-	// Decode error class
-	_, _, length, headerLen, err := DecodeTagNumber(data)
-	if err != nil {
-		return ErrInvalidResponse
+	for attempt := 0; ; {
+		select {
+		case <-ctx.Done():
+			c.metrics.RequestsTimedOut.Inc()
+			return nil, false, ErrTimeout
+		case resp, ok := <-respCh:
+			return resp, ok, nil
+		case <-expired:
+			timer.Reset(c.opts.timeout)
+		}
+
+		if c.receivingSegments(invokeID) {
+			continue
+		}
+		if attempt >= c.opts.retries {
+			c.metrics.RequestsTimedOut.Inc()
+			return nil, false, ErrTimeout
+		}
+		attempt++
+
+		c.logger.Debug("request timed out, retrying",
+			slog.String("address", addr.String()),
+			slog.Uint64("invoke_id", uint64(invokeID)),
+			slog.Int("attempt", attempt),
+		)
+		c.traceNPDU(true, addr, packet)
+		if err := c.link.Send(ctx, addr, packet); err != nil {
+			c.metrics.RequestsFailed.Inc()
+			return nil, false, fmt.Errorf("send request: %w", err)
+		}
+		c.metrics.BytesSent.Add(int64(len(packet)))
 	}
-	errorClass := ErrorClass(DecodeUnsigned(data[headerLen : headerLen+length]))
+}
 
-	offset := headerLen + length
+// decodeError decodes the error response to a request for service
+func (c *Client) decodeError(service ConfirmedServiceChoice, data []byte) error {
+	d := NewDecoder(data)
 
-	// Decode error code
-	_, _, length, headerLen, err = DecodeTagNumber(data[offset:])
-	if err != nil {
-		return ErrInvalidResponse
+	// Errors of some services are wrapped in an error-type [0]
+	if d.IsOpening(0) {
+		d.Opening(0)
+	}
+	errorClass := ErrorClass(d.Enumerated())
+	errorCode := ErrorCode(d.Enumerated())
+	if err := d.Err(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidResponse, err)
 	}
-	errorCode := ErrorCode(DecodeUnsigned(data[offset+headerLen : offset+headerLen+length]))
 
-	return NewBACnetError(errorClass, errorCode)
+	return newServiceError(service, errorClass, errorCode)
 }
 
 // sendUnconfirmedRequest sends an unconfirmed request
-func (c *Client) sendUnconfirmedRequest(ctx context.Context, addr *net.UDPAddr, broadcast bool, service UnconfirmedServiceChoice, data []byte) error {
+func (c *Client) sendUnconfirmedRequest(ctx context.Context, addr net.Addr, broadcast bool, service UnconfirmedServiceChoice, data []byte) error {
 	if c.State() != StateConnected {
 		return ErrNotConnected
 	}
@@ -536,18 +901,8 @@ func (c *Client) sendUnconfirmedRequest(ctx context.Context, addr *net.UDPAddr,
 	// Encode NPDU
 	npdu := EncodeNPDU(false, NPDUControlPriorityNormal)
 
-	// Encode BVLC
-	var bvlcFunc BVLCFunction
-	if broadcast {
-		bvlcFunc = BVLCOriginalBroadcastNPDU
-	} else {
-		bvlcFunc = BVLCOriginalUnicastNPDU
-	}
-	bvlc := EncodeBVLC(bvlcFunc, len(npdu)+len(apdu))
-
 	// Build packet
-	packet := make([]byte, 0, len(bvlc)+len(npdu)+len(apdu))
-	packet = append(packet, bvlc...)
+	packet := make([]byte, 0, len(npdu)+len(apdu))
 	packet = append(packet, npdu...)
 	packet = append(packet, apdu...)
 
@@ -555,9 +910,11 @@ func (c *Client) sendUnconfirmedRequest(ctx context.Context, addr *net.UDPAddr,
 
 	var err error
 	if broadcast {
-		err = c.transport.Broadcast(ctx, DefaultPort, packet)
+		c.traceNPDU(true, nil, packet)
+		err = c.link.Broadcast(ctx, packet)
 	} else {
-		err = c.transport.Send(ctx, addr, packet)
+		c.traceNPDU(true, addr, packet)
+		err = c.link.Send(ctx, addr, packet)
 	}
 
 	if err != nil {
@@ -571,36 +928,37 @@ func (c *Client) sendUnconfirmedRequest(ctx context.Context, addr *net.UDPAddr,
 	return nil
 }
 
-// registerForeignDevice registers as a foreign device with the BBMD
-func (c *Client) registerForeignDevice(ctx context.Context) error {
-	addr, err := net.ResolveUDPAddr("udp4", fmt.Sprintf("%s:%d", c.opts.bbmdAddress, c.opts.bbmdPort))
-	if err != nil {
-		return fmt.Errorf("resolve BBMD address: %w", err)
+// renewForeignDevice re-registers with the BBMD at half the TTL so that the
+// registration does not lapse
+func (c *Client) renewForeignDevice(ctx context.Context) {
+	interval := c.opts.foreignDeviceTTL / 2
+	if interval < time.Second {
+		interval = time.Second
 	}
 
-	// TTL in seconds
-	ttl := uint16(c.opts.foreignDeviceTTL.Seconds())
+	ticker := c.opts.clock.NewTicker(interval)
+	defer ticker.Stop()
 
-	// Build register foreign device request
-	data := make([]byte, 6)
-	data[0] = byte(BVLCTypeBACnetIP)
-	data[1] = byte(BVLCRegisterForeignDevice)
-	binary.BigEndian.PutUint16(data[2:], 6) // Length
-	binary.BigEndian.PutUint16(data[4:], ttl)
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C():
+		}
 
-	if err := c.transport.Send(ctx, addr, data); err != nil {
-		return fmt.Errorf("send registration: %w", err)
+		err := c.registerForeignDevice(ctx)
+		if err != nil && ctx.Err() == nil {
+			c.logger.Warn("failed to renew foreign device registration",
+				slog.String("error", err.Error()),
+			)
+		}
 	}
-
-	c.logger.Info("registered as foreign device",
-		slog.String("bbmd", addr.String()),
-		slog.Duration("ttl", c.opts.foreignDeviceTTL),
-	)
-
-	return nil
 }
 
-// WhoIs sends a Who-Is request to discover devices
+// WhoIs sends a Who-Is request to discover devices. It returns the
+// devices answering before the discovery timeout, in the order they
+// answered, limited to the range of WithDeviceRange. When ctx ends first,
+// it returns the devices found so far with the context's error.
 func (c *Client) WhoIs(ctx context.Context, opts ...DiscoverOption) ([]*DeviceInfo, error) {
 	options := defaultDiscoverOptions()
 	for _, opt := range opts {
@@ -608,31 +966,114 @@ func (c *Client) WhoIs(ctx context.Context, opts ...DiscoverOption) ([]*DeviceIn
 	}
 
 	// Build Who-Is request
-	var data []byte
+	var e Encoder
 	if options.LowLimit != nil && options.HighLimit != nil {
-		data = append(data, EncodeContextUnsigned(0, *options.LowLimit)...)
-		data = append(data, EncodeContextUnsigned(1, *options.HighLimit)...)
+		e.ContextUnsigned(0, *options.LowLimit)
+		e.ContextUnsigned(1, *options.HighLimit)
 	}
 
-	// Send as broadcast
-	if err := c.sendUnconfirmedRequest(ctx, nil, true, ServiceWhoIs, data); err != nil {
+	// Collect the devices answering this Who-Is only: the client's device
+	// table also holds the devices of earlier scans and of other requests
+	found := newDiscovery(options)
+	stop := c.watchIAm(found.add)
+	defer stop()
+
+	// Send as broadcast, or directed to a single address
+	if options.Address != "" {
+		addr, err := c.link.ParseAddr(options.Address)
+		if err != nil {
+			return nil, fmt.Errorf("invalid discovery address: %w", err)
+		}
+		if err := c.sendUnconfirmedRequest(ctx, addr, false, ServiceWhoIs, e.Bytes()); err != nil {
+			return nil, err
+		}
+	} else if err := c.sendUnconfirmedRequest(ctx, nil, true, ServiceWhoIs, e.Bytes()); err != nil {
 		return nil, err
 	}
 
 	c.metrics.WhoIsSent.Inc()
 
-	// Wait for responses
-	time.Sleep(options.Timeout)
+	// Wait for responses, returning those received so far when ctx ends
+	err := sleepContext(ctx, c.opts.clock, options.Timeout)
+	return found.devices(), err
+}
 
-	// Collect discovered devices
-	c.devicesMu.RLock()
-	devices := make([]*DeviceInfo, 0, len(c.devices))
-	for _, dev := range c.devices {
-		devices = append(devices, dev)
+// discovery collects the devices found by a discovery, within its device
+// range. A device answering twice, through two routes, is listed once,
+// with its last address.
+type discovery struct {
+	options *DiscoverOptions
+
+	mu    sync.Mutex
+	found []*DeviceInfo
+	index map[uint32]int
+}
+
+// newDiscovery returns an empty collector of the devices found with the
+// options
+func newDiscovery(options *DiscoverOptions) *discovery {
+	return &discovery{options: options, index: make(map[uint32]int)}
+}
+
+// add records a device found, calling OnDevice when it is new
+func (d *discovery) add(dev *DeviceInfo) {
+	instance := dev.ObjectID.Instance
+	if d.options.LowLimit != nil && d.options.HighLimit != nil &&
+		(instance < *d.options.LowLimit || instance > *d.options.HighLimit) {
+		return
 	}
-	c.devicesMu.RUnlock()
 
-	return devices, nil
+	d.mu.Lock()
+	i, seen := d.index[instance]
+	if seen {
+		d.found[i] = dev
+	} else {
+		d.index[instance] = len(d.found)
+		d.found = append(d.found, dev)
+	}
+	d.mu.Unlock()
+
+	if !seen && d.options.OnDevice != nil {
+		d.options.OnDevice(dev)
+	}
+}
+
+// devices returns the devices found, in the order they were found
+func (d *discovery) devices() []*DeviceInfo {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return append([]*DeviceInfo(nil), d.found...)
+}
+
+// iAmWatcher collects the I-Am received while a Who-Is is pending
+type iAmWatcher func(device *DeviceInfo)
+
+// watchIAm registers a collector of the I-Am received, returning the
+// function that unregisters it
+func (c *Client) watchIAm(w iAmWatcher) func() {
+	c.iAmMu.Lock()
+	defer c.iAmMu.Unlock()
+	if c.iAmWatchers == nil {
+		c.iAmWatchers = make(map[int]iAmWatcher)
+	}
+	c.iAmWatcherID++
+	id := c.iAmWatcherID
+	c.iAmWatchers[id] = w
+
+	return func() {
+		c.iAmMu.Lock()
+		delete(c.iAmWatchers, id)
+		c.iAmMu.Unlock()
+	}
+}
+
+// dispatchIAm passes the device of a received I-Am to the collectors
+func (c *Client) dispatchIAm(device *DeviceInfo) {
+	c.iAmMu.RLock()
+	defer c.iAmMu.RUnlock()
+	for _, w := range c.iAmWatchers {
+		w(device)
+	}
 }
 
 // GetDevice returns information about a discovered device
@@ -643,17 +1084,58 @@ func (c *Client) GetDevice(deviceID uint32) (*DeviceInfo, bool) {
 	return dev, ok
 }
 
-// resolveDevice resolves a device ID to its address
-func (c *Client) resolveDevice(ctx context.Context, deviceID uint32) (*net.UDPAddr, error) {
+// BindDevice adds a static address binding for a device, so that it can be
+// reached without Who-Is discovery (e.g. across subnets without a BBMD).
+// For BACnet/IP the address is a host:port string; the port defaults to
+// 47808. For BACnet/IPv6 it is [ip]:port, optionally prefixed with the hex
+// VMAC and "@"; the VMAC defaults to the device instance.
+func (c *Client) BindDevice(deviceID uint32, address string) error {
+	addr, err := c.link.ParseAddr(address)
+	if err != nil {
+		return fmt.Errorf("invalid device address: %w", err)
+	}
+
+	if binder, ok := c.link.(deviceBinder); ok {
+		addr = binder.bindDevice(deviceID, addr)
+	}
+
+	device := &DeviceInfo{
+		ObjectID:      NewObjectIdentifier(ObjectTypeDevice, deviceID),
+		Address:       Address{Addr: c.link.MAC(addr)},
+		MaxAPDULength: MaxAPDULength,
+	}
+	c.devicesMu.Lock()
+	c.devices[deviceID] = device
+	c.devicesMu.Unlock()
+	c.storeDevice(device)
+
+	return nil
+}
+
+// resolveDevice resolves a device ID to its address, preferring one bound
+// to ctx by a DeviceHandle
+func (c *Client) resolveDevice(ctx context.Context, deviceID uint32) (net.Addr, error) {
+	if addr := boundAddr(ctx, deviceID); addr != nil {
+		return addr, nil
+	}
+
 	c.devicesMu.RLock()
 	dev, ok := c.devices[deviceID]
 	c.devicesMu.RUnlock()
 
 	if !ok {
-		// Try to discover the device
-		_, err := c.WhoIs(ctx, WithDeviceRange(deviceID, deviceID), WithDiscoveryTimeout(2*time.Second))
-		if err != nil {
-			return nil, err
+		// Try to discover the device, waiting no longer once it answers
+		findCtx, cancel := context.WithCancel(ctx)
+		defer cancel()
+		found, err := c.WhoIs(findCtx, WithDeviceRange(deviceID, deviceID), WithDiscoveryTimeout(2*time.Second),
+			WithDeviceFound(func(*DeviceInfo) { cancel() }))
+		if len(found) == 0 {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			if err != nil {
+				return nil, err
+			}
 		}
 
 		c.devicesMu.RLock()
@@ -665,21 +1147,7 @@ func (c *Client) resolveDevice(ctx context.Context, deviceID uint32) (*net.UDPAd
 		}
 	}
 
-	// Convert device address to UDP address
-	if len(dev.Address.Addr) == 4 {
-		return &net.UDPAddr{
-			IP:   net.IP(dev.Address.Addr),
-			Port: DefaultPort,
-		}, nil
-	} else if len(dev.Address.Addr) == 6 {
-		// IP + port format
-		return &net.UDPAddr{
-			IP:   net.IP(dev.Address.Addr[:4]),
-			Port: int(binary.BigEndian.Uint16(dev.Address.Addr[4:])),
-		}, nil
-	}
-
-	return nil, fmt.Errorf("invalid device address format")
+	return c.link.Addr(dev.Address.Addr)
 }
 
 // ReadProperty reads a property from a BACnet object
@@ -689,20 +1157,13 @@ func (c *Client) ReadProperty(ctx context.Context, deviceID uint32, objectID Obj
 		opt(options)
 	}
 
-	addr, err := c.resolveDevice(ctx, deviceID)
-	if err != nil {
-		return nil, err
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
 	}
 
-	// Build ReadProperty request
-	data := make([]byte, 0, 16)
-	data = append(data, EncodeContextObjectIdentifier(0, objectID)...)
-	data = append(data, EncodeContextEnumerated(1, uint32(propertyID))...)
-	if options.ArrayIndex != nil {
-		data = append(data, EncodeContextUnsigned(2, *options.ArrayIndex)...)
-	}
-
-	resp, err := c.sendRequest(ctx, addr, ServiceReadProperty, data)
+	resp, err := c.readProperty(ctx, deviceID, objectID, propertyID, options)
 	if err != nil {
 		return nil, err
 	}
@@ -711,48 +1172,45 @@ func (c *Client) ReadProperty(ctx context.Context, deviceID uint32, objectID Obj
 	return c.decodeReadPropertyResponse(resp.Data)
 }
 
-// decodeReadPropertyResponse decodes a ReadProperty response
-func (c *Client) decodeReadPropertyResponse(data []byte) (interface{}, error) {
-	if len(data) < 8 {
-		return nil, ErrInvalidResponse
-	}
-
-	offset := 0
-
-	// Skip object identifier [0]
-	tagNum, class, length, headerLen, err := DecodeTagNumber(data[offset:])
-	if err != nil || tagNum != 0 || class != TagClassContext {
-		return nil, ErrInvalidResponse
+// readProperty sends a ReadProperty request and returns the ack
+func (c *Client) readProperty(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, propertyID PropertyIdentifier, options *ReadOptions) (*APDU, error) {
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
 	}
-	offset += headerLen + length
 
-	// Skip property identifier [1]
-	tagNum, class, length, headerLen, err = DecodeTagNumber(data[offset:])
-	if err != nil || tagNum != 1 || class != TagClassContext {
-		return nil, ErrInvalidResponse
+	// Build ReadProperty request
+	e := NewEncoder(make([]byte, 0, 16))
+	e.ContextObjectIdentifier(0, objectID)
+	e.ContextEnumerated(1, uint32(propertyID))
+	if options.ArrayIndex != nil {
+		e.ContextUnsigned(2, *options.ArrayIndex)
 	}
-	offset += headerLen + length
 
-	// Check for optional array index [2]
-	if len(data) > offset {
-		tagNum, class, _, headerLen, err = DecodeTagNumber(data[offset:])
-		if err == nil && tagNum == 2 && class == TagClassContext {
-			offset += headerLen + length
-		}
-	}
+	return c.sendRoutedRequest(ctx, &Request{
+		DeviceID: deviceID,
+		Address:  addr,
+		Service:  ServiceReadProperty,
+		Priority: options.NetworkPriority,
+		Data:     e.Bytes(),
+	}, nil)
+}
 
-	// Check for opening tag [3]
-	if len(data) <= offset {
-		return nil, ErrInvalidResponse
+// decodeReadPropertyResponse decodes a ReadProperty response
+func (c *Client) decodeReadPropertyResponse(data []byte) (interface{}, error) {
+	d := NewDecoder(data)
+	d.ContextObjectIdentifier(0)
+	d.ContextEnumerated(1)
+	if d.IsContext(2) {
+		d.ContextUnsigned(2)
 	}
-	tagNum, class, length, _, err = DecodeTagNumber(data[offset:])
-	if err != nil || tagNum != 3 || class != TagClassContext || length != -1 {
-		return nil, ErrInvalidResponse
+	d.Opening(3)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
 	}
-	offset++
 
 	// Decode property value
-	return c.decodePropertyValue(data[offset:])
+	return c.decodePropertyValue(d.Rest())
 }
 
 // decodePropertyValue decodes a property value
@@ -771,14 +1229,22 @@ func (c *Client) decodePropertyValue(data []byte) (interface{}, error) {
 		return nil, nil
 	}
 
+	// Constructed values are returned undecoded
+	if length == -1 {
+		return data[headerLen:], nil
+	}
+
 	if class == TagClassApplication {
+		// An application boolean carries its value in the length field
+		if ApplicationTag(tagNum) == TagBoolean {
+			return length == 1, nil
+		}
+
 		valueData := data[headerLen : headerLen+length]
 
 		switch ApplicationTag(tagNum) {
 		case TagNull:
 			return nil, nil
-		case TagBoolean:
-			return length == 1, nil
 		case TagUnsignedInt:
 			return DecodeUnsigned(valueData), nil
 		case TagSignedInt:
@@ -794,8 +1260,10 @@ func (c *Client) decodePropertyValue(data []byte) (interface{}, error) {
 		case TagEnumerated:
 			return DecodeUnsigned(valueData), nil
 		case TagObjectID:
-			oidValue := binary.BigEndian.Uint32(valueData)
-			return DecodeObjectIdentifier(oidValue), nil
+			if len(valueData) != 4 {
+				return nil, ErrInvalidResponse
+			}
+			return DecodeObjectIdentifierFromBytes(valueData), nil
 		default:
 			return valueData, nil
 		}
@@ -811,105 +1279,288 @@ func (c *Client) WriteProperty(ctx context.Context, deviceID uint32, objectID Ob
 		opt(options)
 	}
 
+	if c.isStandby() && options.DryRun == nil {
+		return ErrStandby
+	}
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
 	addr, err := c.resolveDevice(ctx, deviceID)
 	if err != nil {
 		return err
 	}
 
 	// Build WriteProperty request
-	data := make([]byte, 0, 32)
-	data = append(data, EncodeContextObjectIdentifier(0, objectID)...)
-	data = append(data, EncodeContextEnumerated(1, uint32(propertyID))...)
+	e := NewEncoder(make([]byte, 0, 32))
+	e.ContextObjectIdentifier(0, objectID)
+	e.ContextEnumerated(1, uint32(propertyID))
 
 	if options.ArrayIndex != nil {
-		data = append(data, EncodeContextUnsigned(2, *options.ArrayIndex)...)
+		e.ContextUnsigned(2, *options.ArrayIndex)
 	}
 
 	// Property value [3]
-	data = append(data, EncodeOpeningTag(3)...)
-	encodedValue, err := c.encodePropertyValue(value)
-	if err != nil {
+	e.Opening(3)
+	if err := e.Value(value); err != nil {
 		return fmt.Errorf("encode value: %w", err)
 	}
-	data = append(data, encodedValue...)
-	data = append(data, EncodeClosingTag(3)...)
+	e.Closing(3)
 
 	// Priority [4]
 	if options.Priority != nil {
-		data = append(data, EncodeContextUnsigned(4, uint32(*options.Priority))...)
+		e.ContextUnsigned(4, uint32(*options.Priority))
 	}
 
-	_, err = c.sendRequest(ctx, addr, ServiceWriteProperty, data)
+	if options.DryRun != nil {
+		*options.DryRun = c.newWritePlan(deviceID, addr, ServiceWriteProperty, e.Bytes())
+		return nil
+	}
+
+	readOpts := []ReadOption{WithNetworkPriority(options.NetworkPriority)}
+	if options.ArrayIndex != nil {
+		readOpts = append(readOpts, WithArrayIndex(*options.ArrayIndex))
+	}
+
+	// The journal records the value a verified write replaces; a failed
+	// read leaves it unknown
+	var oldValue interface{}
+	if c.opts.journal != nil && options.Verify && value != nil {
+		oldValue, _ = c.ReadProperty(ctx, deviceID, objectID, propertyID, readOpts...)
+	}
+
+	err = c.sendWriteProperty(ctx, deviceID, addr, objectID, propertyID, value, e.Bytes(), options, readOpts)
+	c.journalWrite(JournalEntry{
+		DeviceID:   deviceID,
+		Service:    ServiceWriteProperty,
+		ObjectID:   objectID,
+		PropertyID: propertyID,
+		ArrayIndex: options.ArrayIndex,
+		OldValue:   oldValue,
+		Value:      value,
+		Priority:   options.Priority,
+		Err:        err,
+	})
 	return err
 }
 
-// encodePropertyValue encodes a property value for writing
-func (c *Client) encodePropertyValue(value interface{}) ([]byte, error) {
-	switch v := value.(type) {
-	case nil:
-		return []byte{0x00}, nil
-	case bool:
-		return EncodeBooleanTag(v), nil
-	case int:
-		if v >= 0 {
-			return EncodeUnsignedTag(uint32(v)), nil
+// sendWriteProperty sends an encoded WriteProperty request and, if
+// requested, reads the value back
+func (c *Client) sendWriteProperty(ctx context.Context, deviceID uint32, addr net.Addr, objectID ObjectIdentifier, propertyID PropertyIdentifier, value interface{}, data []byte, options *WriteOptions, readOpts []ReadOption) error {
+	_, err := c.sendRoutedRequest(ctx, &Request{
+		DeviceID: deviceID,
+		Address:  addr,
+		Service:  ServiceWriteProperty,
+		Priority: options.NetworkPriority,
+		Data:     data,
+	}, nil)
+	if err != nil || !options.Verify || value == nil {
+		return err
+	}
+
+	// Read back the written value; relinquishing with null cannot be
+	// verified this way
+	readBack, err := c.ReadProperty(ctx, deviceID, objectID, propertyID, readOpts...)
+	if err != nil {
+		return fmt.Errorf("verify write: %w", err)
+	}
+	if !valuesEqual(value, readBack) {
+		return &VerificationError{ObjectID: objectID, PropertyID: propertyID, Written: value, ReadBack: readBack}
+	}
+	return nil
+}
+
+// valuesEqual compares a written value with the value read back, regardless
+// of the Go types numbers and enumerations are written and decoded as
+func valuesEqual(written, readBack interface{}) bool {
+	w, r := reflect.ValueOf(written), reflect.ValueOf(readBack)
+	if w.IsValid() && r.IsValid() && isNumeric(w) && isNumeric(r) {
+		f := reflect.TypeOf(float64(0))
+		return w.Convert(f).Float() == r.Convert(f).Float()
+	}
+	if b, ok := written.([]byte); ok {
+		rb, ok := readBack.([]byte)
+		return ok && bytes.Equal(b, rb)
+	}
+	return reflect.DeepEqual(written, readBack)
+}
+
+// WritePropertyMultiple writes several properties of one or more objects in
+// a single request. Consecutive requests for the same object share a write
+// access specification; a priority set with WithPriority applies to the
+// requests that carry none.
+func (c *Client) WritePropertyMultiple(ctx context.Context, deviceID uint32, requests []WritePropertyRequest, opts ...WriteOption) error {
+	options := &WriteOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if len(requests) == 0 {
+		return nil
+	}
+	if c.isStandby() && options.DryRun == nil {
+		return ErrStandby
+	}
+	if options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, options.Timeout)
+		defer cancel()
+	}
+
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	// Build WritePropertyMultiple request
+	e := NewEncoder(make([]byte, 0, 64))
+	for i, req := range requests {
+		if i == 0 || req.ObjectID != requests[i-1].ObjectID {
+			if i > 0 {
+				e.Closing(1)
+			}
+			e.ContextObjectIdentifier(0, req.ObjectID)
+			e.Opening(1)
+		}
+
+		e.ContextEnumerated(0, uint32(req.PropertyID))
+		if req.ArrayIndex != nil {
+			e.ContextUnsigned(1, *req.ArrayIndex)
 		}
-		data := EncodeSigned(int32(v))
-		tag := EncodeTag(uint8(TagSignedInt), TagClassApplication, len(data))
-		return append(tag, data...), nil
-	case int32:
-		if v >= 0 {
-			return EncodeUnsignedTag(uint32(v)), nil
+		e.Opening(2)
+		if err := e.Value(req.Value); err != nil {
+			return fmt.Errorf("encode value of %s %s: %w", req.ObjectID, req.PropertyID, err)
 		}
-		data := EncodeSigned(v)
-		tag := EncodeTag(uint8(TagSignedInt), TagClassApplication, len(data))
-		return append(tag, data...), nil
-	case uint32:
-		return EncodeUnsignedTag(v), nil
-	case float32:
-		return EncodeRealTag(v), nil
-	case float64:
-		data := EncodeDouble(v)
-		tag := EncodeTag(uint8(TagDouble), TagClassApplication, len(data))
-		return append(tag, data...), nil
-	case string:
-		return EncodeCharacterStringTag(v), nil
-	case ObjectIdentifier:
-		return EncodeObjectIdentifierTag(v), nil
-	default:
-		return nil, fmt.Errorf("unsupported value type: %T", value)
+		e.Closing(2)
+
+		priority := req.Priority
+		if priority == nil {
+			priority = options.Priority
+		}
+		if priority != nil {
+			e.ContextUnsigned(3, uint32(*priority))
+		}
+	}
+	e.Closing(1)
+
+	if options.DryRun != nil {
+		*options.DryRun = c.newWritePlan(deviceID, addr, ServiceWritePropertyMultiple, e.Bytes())
+		return nil
+	}
+
+	_, err = c.sendRoutedRequest(ctx, &Request{
+		DeviceID: deviceID,
+		Address:  addr,
+		Service:  ServiceWritePropertyMultiple,
+		Priority: options.NetworkPriority,
+		Data:     e.Bytes(),
+	}, nil)
+
+	// The request succeeds or fails as a whole
+	for _, req := range requests {
+		priority := req.Priority
+		if priority == nil {
+			priority = options.Priority
+		}
+		c.journalWrite(JournalEntry{
+			DeviceID:   deviceID,
+			Service:    ServiceWritePropertyMultiple,
+			ObjectID:   req.ObjectID,
+			PropertyID: req.PropertyID,
+			ArrayIndex: req.ArrayIndex,
+			Value:      req.Value,
+			Priority:   priority,
+			Err:        err,
+		})
+	}
+	return err
+}
+
+// WritePlan is the outcome of a dry-run write: the request that would have
+// been sent and where to
+type WritePlan struct {
+	DeviceID uint32
+	Address  net.Addr
+	Service  ConfirmedServiceChoice
+
+	// APDU is the encoded confirmed request, with invoke ID 0
+	APDU []byte
+}
+
+// newWritePlan returns the plan of a write that is not sent
+func (c *Client) newWritePlan(deviceID uint32, addr net.Addr, service ConfirmedServiceChoice, data []byte) WritePlan {
+	return WritePlan{
+		DeviceID: deviceID,
+		Address:  addr,
+		Service:  service,
+		APDU:     EncodeConfirmedRequest(0, service, data, 0, EncodeMaxAPDU(int(c.opts.maxAPDULength))),
 	}
 }
 
-// ReadPropertyMultiple reads multiple properties from one or more objects
-func (c *Client) ReadPropertyMultiple(ctx context.Context, deviceID uint32, requests []ReadPropertyRequest) ([]PropertyValue, error) {
+// encodePropertyValue encodes a property value for writing
+func (c *Client) encodePropertyValue(value interface{}) ([]byte, error) {
+	var e Encoder
+	if err := e.Value(value); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+// ReadPropertyMultiple reads multiple properties from one or more objects.
+// Properties the device could not read are returned with their access error
+// rather than failing the request. Large batches are split into several
+// requests whose responses should fit the device's maximum APDU.
+func (c *Client) ReadPropertyMultiple(ctx context.Context, deviceID uint32, requests []ReadPropertyRequest) ([]PropertyResult, error) {
 	addr, err := c.resolveDevice(ctx, deviceID)
 	if err != nil {
 		return nil, err
 	}
 
-	// Build ReadPropertyMultiple request
-	data := make([]byte, 0, 64)
-
-	// Group requests by object
-	objectRequests := make(map[ObjectIdentifier][]ReadPropertyRequest)
-	for _, req := range requests {
-		objectRequests[req.ObjectID] = append(objectRequests[req.ObjectID], req)
+	var results []PropertyResult
+	for _, batch := range splitReadAccessSpecs(requests, c.deviceMaxAPDU(deviceID)) {
+		batchResults, err := c.readPropertyBatch(ctx, deviceID, addr, batch)
+		results = append(results, batchResults...)
+		if err != nil {
+			return results, err
+		}
 	}
+	return results, nil
+}
 
-	for oid, reqs := range objectRequests {
-		data = append(data, EncodeContextObjectIdentifier(0, oid)...)
-		data = append(data, EncodeOpeningTag(1)...)
-		for _, req := range reqs {
-			data = append(data, EncodeContextEnumerated(0, uint32(req.PropertyID))...)
-			if req.ArrayIndex != nil {
-				data = append(data, EncodeContextUnsigned(1, *req.ArrayIndex)...)
+// readPropertyBatch sends one ReadPropertyMultiple request. A batch whose
+// response still turns out too large for the device is halved and retried.
+func (c *Client) readPropertyBatch(ctx context.Context, deviceID uint32, addr net.Addr, requests []ReadPropertyRequest) ([]PropertyResult, error) {
+	// Build ReadPropertyMultiple request; requests are ordered by object
+	e := NewEncoder(make([]byte, 0, 64))
+	for i, req := range requests {
+		if i == 0 || req.ObjectID != requests[i-1].ObjectID {
+			if i > 0 {
+				e.Closing(1)
 			}
+			e.ContextObjectIdentifier(0, req.ObjectID)
+			e.Opening(1)
+		}
+		e.ContextEnumerated(0, uint32(req.PropertyID))
+		if req.ArrayIndex != nil {
+			e.ContextUnsigned(1, *req.ArrayIndex)
 		}
-		data = append(data, EncodeClosingTag(1)...)
 	}
+	e.Closing(1)
 
-	resp, err := c.sendRequest(ctx, addr, ServiceReadPropertyMultiple, data)
+	resp, err := c.sendRequest(ctx, deviceID, addr, ServiceReadPropertyMultiple, e.Bytes())
+	var abortErr *AbortError
+	if errors.As(err, &abortErr) && len(requests) > 1 &&
+		(abortErr.Reason == AbortReasonSegmentationNotSupported || abortErr.Reason == AbortReasonBufferOverflow) {
+		half := len(requests) / 2
+		results, err := c.readPropertyBatch(ctx, deviceID, addr, requests[:half])
+		if err != nil {
+			return results, err
+		}
+		rest, err := c.readPropertyBatch(ctx, deviceID, addr, requests[half:])
+		return append(results, rest...), err
+	}
 	if err != nil {
 		return nil, err
 	}
@@ -918,113 +1569,148 @@ func (c *Client) ReadPropertyMultiple(ctx context.Context, deviceID uint32, requ
 	return c.decodeReadPropertyMultipleResponse(resp.Data)
 }
 
-// decodeReadPropertyMultipleResponse decodes a ReadPropertyMultiple response
-func (c *Client) decodeReadPropertyMultipleResponse(data []byte) ([]PropertyValue, error) {
-	var results []PropertyValue
-	offset := 0
+// deviceMaxAPDU returns the largest APDU that can be exchanged with a device
+func (c *Client) deviceMaxAPDU(deviceID uint32) int {
+	limit := int(c.opts.maxAPDULength)
+	c.devicesMu.RLock()
+	dev, ok := c.devices[deviceID]
+	c.devicesMu.RUnlock()
+	if ok && dev.MaxAPDULength > 0 && int(dev.MaxAPDULength) < limit {
+		limit = int(dev.MaxAPDULength)
+	}
+	return limit
+}
 
-	for offset < len(data) {
-		// Object identifier [0]
-		tagNum, class, length, headerLen, err := DecodeTagNumber(data[offset:])
-		if err != nil {
-			break
-		}
-		if tagNum != 0 || class != TagClassContext {
-			break
-		}
+const (
+	// rpmHeaderLen is the length of a confirmed request or complex ack header
+	rpmHeaderLen = 4
 
-		oidValue := binary.BigEndian.Uint32(data[offset+headerLen:])
-		oid := DecodeObjectIdentifier(oidValue)
-		offset += headerLen + length
+	// rpmValueEstimate is the assumed encoded length of a property value in
+	// a ReadPropertyMultiple response
+	rpmValueEstimate = 16
+)
 
-		// List of results [1]
-		tagNum, class, length, _, err = DecodeTagNumber(data[offset:])
-		if err != nil || tagNum != 1 || class != TagClassContext || length != -1 {
-			break
+// splitReadAccessSpecs orders requests by object, keeping the order in which
+// objects first appear, and splits them into batches whose estimated response
+// fits in maxAPDU
+func splitReadAccessSpecs(requests []ReadPropertyRequest, maxAPDU int) [][]ReadPropertyRequest {
+	var order []ObjectIdentifier
+	byObject := make(map[ObjectIdentifier][]ReadPropertyRequest)
+	for _, req := range requests {
+		if _, ok := byObject[req.ObjectID]; !ok {
+			order = append(order, req.ObjectID)
 		}
-		offset++
-
-		// Parse property results
-		for offset < len(data) {
-			tagNum, class, length, _, err = DecodeTagNumber(data[offset:])
-			if err != nil {
-				break
+		byObject[req.ObjectID] = append(byObject[req.ObjectID], req)
+	}
+
+	var batches [][]ReadPropertyRequest
+	var batch []ReadPropertyRequest
+	size := rpmHeaderLen
+	for _, oid := range order {
+		objectStarted := false
+		for _, req := range byObject[oid] {
+			// Property identifier [2], array index [3] and the value or
+			// error within [4] or [5]
+			n := 1 + unsignedLen(uint32(req.PropertyID)) + 2 + rpmValueEstimate
+			if req.ArrayIndex != nil {
+				n += 1 + unsignedLen(*req.ArrayIndex)
 			}
-
-			// Closing tag
-			if length == -2 && tagNum == 1 {
-				offset++
-				break
+			if !objectStarted {
+				// Object identifier [0] and list of results [1]
+				n += 5 + 2
 			}
 
-			// Property identifier [2]
-			if tagNum != 2 || class != TagClassContext {
-				offset++
-				continue
+			if len(batch) > 0 && size+n > maxAPDU {
+				batches = append(batches, batch)
+				batch = nil
+				size = rpmHeaderLen
+				if objectStarted {
+					n += 5 + 2
+				}
 			}
-			offset += headerLen
-			propID := PropertyIdentifier(DecodeUnsigned(data[offset : offset+length]))
-			offset += length
+			batch = append(batch, req)
+			size += n
+			objectStarted = true
+		}
+	}
+	if len(batch) > 0 {
+		batches = append(batches, batch)
+	}
+	return batches
+}
 
-			// Optional array index [3]
+// decodeReadPropertyMultipleResponse decodes a ReadPropertyMultiple response
+func (c *Client) decodeReadPropertyMultipleResponse(data []byte) ([]PropertyResult, error) {
+	var results []PropertyResult
+	d := NewDecoder(data)
+
+	for d.Len() > 0 && d.Err() == nil {
+		// Object identifier [0] and list of results [1]
+		oid := d.ContextObjectIdentifier(0)
+		d.Opening(1)
+
+		for d.Err() == nil && !d.IsClosing(1) {
+			// Property identifier [2] and optional array index [3]
+			propID := PropertyIdentifier(d.ContextEnumerated(2))
 			var arrayIndex *uint32
-			tagNum, class, length, headerLen, err = DecodeTagNumber(data[offset:])
-			if err == nil && tagNum == 3 && class == TagClassContext {
-				idx := DecodeUnsigned(data[offset+headerLen : offset+headerLen+length])
+			if d.IsContext(3) {
+				idx := d.ContextUnsigned(3)
 				arrayIndex = &idx
-				offset += headerLen + length
 			}
 
-			// Property value [4] or property access error [5]
-			tagNum, class, length, _, err = DecodeTagNumber(data[offset:])
-			if err != nil {
-				break
-			}
-
-			if tagNum == 4 && class == TagClassContext && length == -1 {
+			switch {
+			case d.IsOpening(4):
 				// Property value
-				offset++
-				value, _ := c.decodePropertyValue(data[offset:])
-
-				// Skip to closing tag
-				for offset < len(data) {
-					_, _, l, h, _ := DecodeTagNumber(data[offset:])
-					offset += h
-					if l == -2 {
-						break
-					}
-					if l > 0 {
-						offset += l
-					}
+				d.Opening(4)
+				start := d.Offset()
+				for d.Err() == nil && !d.IsClosing(4) {
+					d.Skip()
+				}
+				end := d.Offset()
+				d.Closing(4)
+				if d.Err() != nil {
+					break
 				}
 
-				results = append(results, PropertyValue{
+				value, _ := c.decodePropertyValue(data[start:end])
+				results = append(results, PropertyResult{
 					ObjectID:   oid,
 					PropertyID: propID,
 					ArrayIndex: arrayIndex,
 					Value:      value,
 				})
-			} else if tagNum == 5 && class == TagClassContext && length == -1 {
-				// Property access error - skip
-				offset++
-				for offset < len(data) {
-					_, _, l, h, _ := DecodeTagNumber(data[offset:])
-					offset += h
-					if l == -2 {
-						break
-					}
-					if l > 0 {
-						offset += l
-					}
+			case d.IsOpening(5):
+				// Property access error
+				d.Opening(5)
+				class := ErrorClass(d.Enumerated())
+				code := ErrorCode(d.Enumerated())
+				d.Closing(5)
+				if d.Err() != nil {
+					break
 				}
+
+				results = append(results, PropertyResult{
+					ObjectID:   oid,
+					PropertyID: propID,
+					ArrayIndex: arrayIndex,
+					Err:        &BACnetError{Class: class, Code: code},
+				})
+			default:
+				d.failf("expected property value or access error")
 			}
 		}
+		d.Closing(1)
 	}
 
+	if err := d.Err(); err != nil {
+		return results, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
 	return results, nil
 }
 
-// SubscribeCOV subscribes to COV (Change of Value) notifications
+// SubscribeCOV subscribes to COV (Change of Value) notifications. With a
+// COV increment, the present value is subscribed to with
+// SubscribeCOVProperty, the service that carries the increment.
 func (c *Client) SubscribeCOV(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, handler COVHandler, opts ...SubscribeOption) (uint32, error) {
 	options := &SubscribeOptions{
 		Confirmed: false,
@@ -1033,56 +1719,154 @@ func (c *Client) SubscribeCOV(ctx context.Context, deviceID uint32, objectID Obj
 		opt(options)
 	}
 
-	addr, err := c.resolveDevice(ctx, deviceID)
-	if err != nil {
+	var property *PropertyIdentifier
+	if options.COVIncrement != nil {
+		presentValue := PropertyPresentValue
+		property = &presentValue
+	}
+
+	subID := c.nextSubscriberID()
+	if err := c.subscribeCOV(ctx, deviceID, objectID, property, subID, handler, options); err != nil {
+		c.releaseSubscriberID(subID)
 		return 0, err
 	}
+	return subID, nil
+}
+
+// SubscribeCOVProperty subscribes to the COV notifications of one property
+// of an object, notified when it changes by at least the increment set with
+// WithCOVIncrement or, without it, by the object's own COV increment
+func (c *Client) SubscribeCOVProperty(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, propertyID PropertyIdentifier, handler COVHandler, opts ...SubscribeOption) (uint32, error) {
+	options := &SubscribeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	subID := c.nextSubscriberID()
+	if err := c.subscribeCOV(ctx, deviceID, objectID, &propertyID, subID, handler, options); err != nil {
+		c.releaseSubscriberID(subID)
+		return 0, err
+	}
+	return subID, nil
+}
+
+// RenewCOV renews a subscription made by SubscribeCOV or
+// SubscribeCOVProperty before its lifetime expires, keeping its handler.
+// The options should be those of the subscription.
+func (c *Client) RenewCOV(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, subID uint32, opts ...SubscribeOption) error {
+	options := &SubscribeOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
 
-	// Generate subscription ID
-	subID := uint32(c.nextInvokeID())
+	c.covMu.RLock()
+	handler, ok := c.covSubs[subID]
+	propertyID, hasProperty := c.covProps[subID]
+	c.covMu.RUnlock()
+	if !ok || handler == nil {
+		return fmt.Errorf("bacnet: no COV subscription %d", subID)
+	}
 
-	// Build SubscribeCOV request
-	data := make([]byte, 0, 32)
-	data = append(data, EncodeContextUnsigned(0, subID)...)
-	data = append(data, EncodeContextObjectIdentifier(1, objectID)...)
+	var property *PropertyIdentifier
+	if hasProperty {
+		property = &propertyID
+	}
+	return c.subscribeCOV(ctx, deviceID, objectID, property, subID, handler, options)
+}
 
-	if options.Confirmed {
-		data = append(data, EncodeContextBoolean(2, true)...)
+// subscribeCOV subscribes to COV notifications with the given subscriber
+// process identifier, with SubscribeCOVProperty if property is set;
+// subscribing again with the same identifier renews the subscription
+func (c *Client) subscribeCOV(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, property *PropertyIdentifier, subID uint32, handler COVHandler, options *SubscribeOptions) error {
+	if c.isStandby() {
+		return ErrStandby
 	}
 
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	// Build SubscribeCOV or SubscribeCOVProperty request
+	e := NewEncoder(make([]byte, 0, 32))
+	e.ContextUnsigned(0, subID)
+	e.ContextObjectIdentifier(1, objectID)
+
+	// issueConfirmedNotifications is required; without it the request
+	// cancels the subscription
+	e.ContextBoolean(2, options.Confirmed)
+
 	if options.Lifetime != nil {
-		data = append(data, EncodeContextUnsigned(3, *options.Lifetime)...)
+		e.ContextUnsigned(3, *options.Lifetime)
 	}
 
-	_, err = c.sendRequest(ctx, addr, ServiceSubscribeCOV, data)
+	service := ServiceSubscribeCOV
+	if property != nil {
+		service = ServiceSubscribeCOVProperty
+		e.Opening(4)
+		e.ContextEnumerated(0, uint32(*property))
+		e.Closing(4)
+		if options.COVIncrement != nil {
+			e.ContextReal(5, *options.COVIncrement)
+		}
+	}
+
+	_, err = c.sendRequest(ctx, deviceID, addr, service, e.Bytes())
 	if err != nil {
-		return 0, err
+		return err
 	}
 
 	// Register handler
 	c.covMu.Lock()
 	c.covSubs[subID] = handler
+	if property != nil {
+		c.covProps[subID] = *property
+	}
 	c.covMu.Unlock()
 
+	if c.redundancy != nil {
+		c.redundancy.trackSubscription(subID, SharedSubscription{
+			DeviceID:     deviceID,
+			ObjectID:     objectID,
+			PropertyID:   property,
+			Confirmed:    options.Confirmed,
+			Lifetime:     options.Lifetime,
+			COVIncrement: options.COVIncrement,
+		})
+	}
+
 	c.metrics.COVSubscriptions.Inc()
 
-	return subID, nil
+	return nil
 }
 
-// UnsubscribeCOV unsubscribes from COV notifications
+// UnsubscribeCOV cancels a subscription made by SubscribeCOV or
+// SubscribeCOVProperty
 func (c *Client) UnsubscribeCOV(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, subID uint32) error {
 	addr, err := c.resolveDevice(ctx, deviceID)
 	if err != nil {
 		return err
 	}
 
+	c.covMu.RLock()
+	propertyID, hasProperty := c.covProps[subID]
+	c.covMu.RUnlock()
+
 	// Build SubscribeCOV request with cancel
-	data := make([]byte, 0, 16)
-	data = append(data, EncodeContextUnsigned(0, subID)...)
-	data = append(data, EncodeContextObjectIdentifier(1, objectID)...)
+	e := NewEncoder(make([]byte, 0, 16))
+	e.ContextUnsigned(0, subID)
+	e.ContextObjectIdentifier(1, objectID)
 	// No confirmed or lifetime = unsubscribe
 
-	_, err = c.sendRequest(ctx, addr, ServiceSubscribeCOV, data)
+	service := ServiceSubscribeCOV
+	if hasProperty {
+		service = ServiceSubscribeCOVProperty
+		e.Opening(4)
+		e.ContextEnumerated(0, uint32(propertyID))
+		e.Closing(4)
+	}
+
+	_, err = c.sendRequest(ctx, deviceID, addr, service, e.Bytes())
 	if err != nil {
 		return err
 	}
@@ -1090,44 +1874,17 @@ func (c *Client) UnsubscribeCOV(ctx context.Context, deviceID uint32, objectID O
 	// Remove handler
 	c.covMu.Lock()
 	delete(c.covSubs, subID)
+	delete(c.covProps, subID)
 	c.covMu.Unlock()
 
-	return nil
-}
-
-// GetObjectList retrieves the list of objects from a device
-func (c *Client) GetObjectList(ctx context.Context, deviceID uint32) ([]ObjectIdentifier, error) {
-	// First, read the object-list length
-	lengthVal, err := c.ReadProperty(ctx, deviceID,
-		NewObjectIdentifier(ObjectTypeDevice, deviceID),
-		PropertyObjectList,
-		WithArrayIndex(0),
-	)
-	if err != nil {
-		return nil, err
+	if c.redundancy != nil {
+		c.redundancy.untrackSubscription(subID)
 	}
 
-	length, ok := lengthVal.(uint32)
-	if !ok {
-		return nil, fmt.Errorf("unexpected object-list length type: %T", lengthVal)
-	}
-
-	// Read each object identifier
-	objects := make([]ObjectIdentifier, 0, length)
-	for i := uint32(1); i <= length; i++ {
-		val, err := c.ReadProperty(ctx, deviceID,
-			NewObjectIdentifier(ObjectTypeDevice, deviceID),
-			PropertyObjectList,
-			WithArrayIndex(i),
-		)
-		if err != nil {
-			continue
-		}
-
-		if oid, ok := val.(ObjectIdentifier); ok {
-			objects = append(objects, oid)
-		}
-	}
+	return nil
+}
 
-	return objects, nil
+// isStandby returns true if the client is the standby of a redundant pair
+func (c *Client) isStandby() bool {
+	return c.redundancy != nil && !c.redundancy.IsActive()
 }
@@ -0,0 +1,105 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"time"
+)
+
+// Clock is the time source of the client and everything driven by it:
+// request latency, discovery waits, COV subscription lifetimes, scheduled
+// writes, ramps, energy rollups and redundancy watchdogs. Tests substitute
+// a simulated clock (see bacnettest.Clock) so that time-based behavior runs
+// instantly and deterministically.
+type Clock interface {
+	// Now returns the current time
+	Now() time.Time
+
+	// NewTimer creates a timer firing once after d
+	NewTimer(d time.Duration) Timer
+
+	// NewTicker creates a ticker firing every d
+	NewTicker(d time.Duration) Ticker
+}
+
+// Timer is a single event timer created by a Clock
+type Timer interface {
+	// C returns the channel on which the time is delivered
+	C() <-chan time.Time
+
+	// Stop prevents the timer from firing
+	Stop() bool
+
+	// Reset changes the timer to fire after d
+	Reset(d time.Duration) bool
+}
+
+// Ticker delivers ticks at intervals, created by a Clock
+type Ticker interface {
+	// C returns the channel on which the ticks are delivered
+	C() <-chan time.Time
+
+	// Stop turns off the ticker
+	Stop()
+}
+
+// SystemClock is the Clock backed by the time package
+var SystemClock Clock = systemClock{}
+
+type systemClock struct{}
+
+func (systemClock) Now() time.Time { return time.Now() }
+
+func (systemClock) NewTimer(d time.Duration) Timer { return systemTimer{time.NewTimer(d)} }
+
+func (systemClock) NewTicker(d time.Duration) Ticker { return systemTicker{time.NewTicker(d)} }
+
+type systemTimer struct{ t *time.Timer }
+
+func (t systemTimer) C() <-chan time.Time        { return t.t.C }
+func (t systemTimer) Stop() bool                 { return t.t.Stop() }
+func (t systemTimer) Reset(d time.Duration) bool { return t.t.Reset(d) }
+
+type systemTicker struct{ t *time.Ticker }
+
+func (t systemTicker) C() <-chan time.Time { return t.t.C }
+func (t systemTicker) Stop()               { t.t.Stop() }
+
+// sleepDone waits for d on the clock, or until done is closed, returning
+// false if it was
+func sleepDone(done <-chan struct{}, clock Clock, d time.Duration) bool {
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return true
+	case <-done:
+		return false
+	}
+}
+
+// sleepContext waits for d on the clock, or until ctx ends, returning its
+// error
+func sleepContext(ctx context.Context, clock Clock, d time.Duration) error {
+	timer := clock.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-timer.C():
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,272 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	ackObject     string
+	ackTransition string
+	ackOperator   string
+	ackProcessID  uint32
+)
+
+var alarmsCmd = &cobra.Command{
+	Use:   "alarms",
+	Short: "List the active alarms of a device",
+	Long: `Alarms lists the objects of a device in an event state other than normal
+or with an unacknowledged transition, as reported by GetEventInformation.
+
+For each object, the time stamps and priorities are those of the last
+to-offnormal, to-fault and to-normal transitions. UNACKED lists the
+transitions waiting for an acknowledgement.
+
+Examples:
+  edgeo-bacnet alarms -d 1234
+  edgeo-bacnet alarms -d 1234 -o json`,
+	RunE: runAlarms,
+}
+
+var ackCmd = &cobra.Command{
+	Use:   "ack",
+	Short: "Acknowledge an alarm",
+	Long: `Ack acknowledges a transition of an object with AcknowledgeAlarm. The time
+stamp of the transition is read from the device with GetEventInformation.
+The transition defaults to the transition into the current event state.
+
+Examples:
+  # Acknowledge the current alarm of analog-input:1
+  edgeo-bacnet ack -d 1234 -O ai:1 --operator "J. Smith"
+
+  # Acknowledge the return to normal
+  edgeo-bacnet ack -d 1234 -O ai:1 --transition normal --operator "J. Smith"`,
+	RunE: runAck,
+}
+
+func init() {
+	ackCmd.Flags().StringVarP(&ackObject, "object", "O", "", "Object in alarm (e.g., analog-input:1 or ai:1)")
+	ackCmd.Flags().StringVar(&ackTransition, "transition", "", "Transition to acknowledge: offnormal, fault or normal (default: into the current state)")
+	ackCmd.Flags().StringVar(&ackOperator, "operator", "", "Name of the operator acknowledging the alarm")
+	ackCmd.Flags().Uint32Var(&ackProcessID, "process-id", 0, "Acknowledging process identifier")
+
+	ackCmd.MarkFlagRequired("object")
+	ackCmd.MarkFlagRequired("operator")
+}
+
+// AlarmEntry is an object of the event information of a device
+type AlarmEntry struct {
+	Object      string    `json:"object"`
+	EventState  string    `json:"event_state"`
+	NotifyType  string    `json:"notify_type"`
+	Priorities  [3]uint32 `json:"priorities"`
+	ToOffNormal string    `json:"to_offnormal,omitempty"`
+	ToFault     string    `json:"to_fault,omitempty"`
+	ToNormal    string    `json:"to_normal,omitempty"`
+	Unacked     []string  `json:"unacked,omitempty"`
+}
+
+func runAlarms(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	// The event information may come in several pages
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(4))
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	summaries, err := client.GetEventInformation(ctx, deviceID)
+	if err != nil {
+		return fmt.Errorf("get event information: %w", err)
+	}
+
+	entries := make([]AlarmEntry, 0, len(summaries))
+	for _, s := range summaries {
+		entry := AlarmEntry{
+			Object:      s.ObjectID.String(),
+			EventState:  s.EventState.String(),
+			NotifyType:  s.NotifyType.String(),
+			Priorities:  s.EventPriorities,
+			ToOffNormal: formatEventTimeStamp(s.EventTimeStamps[bacnet.TransitionToOffNormal]),
+			ToFault:     formatEventTimeStamp(s.EventTimeStamps[bacnet.TransitionToFault]),
+			ToNormal:    formatEventTimeStamp(s.EventTimeStamps[bacnet.TransitionToNormal]),
+		}
+		for _, tr := range []bacnet.Transition{bacnet.TransitionToOffNormal, bacnet.TransitionToFault, bacnet.TransitionToNormal} {
+			if !s.AckedTransitions.Has(tr) {
+				entry.Unacked = append(entry.Unacked, tr.String())
+			}
+		}
+		entries = append(entries, entry)
+	}
+
+	return outputAlarms(entries)
+}
+
+func outputAlarms(entries []AlarmEntry) error {
+	priorities := func(e AlarmEntry) string {
+		return fmt.Sprintf("%d/%d/%d", e.Priorities[0], e.Priorities[1], e.Priorities[2])
+	}
+
+	switch outputFmt {
+	case "json", "yaml":
+		return encodeOutput(os.Stdout, entries)
+	case "csv":
+		writer := csv.NewWriter(os.Stdout)
+		defer writer.Flush()
+		writer.Write([]string{"object", "event_state", "notify_type", "priorities", "to_offnormal", "to_fault", "to_normal", "unacked"})
+		for _, e := range entries {
+			writer.Write([]string{e.Object, e.EventState, e.NotifyType, priorities(e),
+				e.ToOffNormal, e.ToFault, e.ToNormal, strings.Join(e.Unacked, "|")})
+		}
+		return writer.Error()
+	default:
+		if len(entries) == 0 {
+			fmt.Println("No active alarms")
+			return nil
+		}
+		f := NewFormatter(outputFmt)
+		headers := []string{"OBJECT", "STATE", "NOTIFY", "PRIORITY", "TO-OFFNORMAL", "TO-FAULT", "TO-NORMAL", "UNACKED"}
+		rows := make([][]string, 0, len(entries))
+		for _, e := range entries {
+			rows = append(rows, []string{e.Object, e.EventState, e.NotifyType, priorities(e),
+				e.ToOffNormal, e.ToFault, e.ToNormal, strings.Join(e.Unacked, ",")})
+		}
+		f.PrintTable(headers, rows)
+		return nil
+	}
+}
+
+// formatEventTimeStamp formats a time stamp, leaving out an unspecified
+// date and time
+func formatEventTimeStamp(ts bacnet.EventTimeStamp) string {
+	switch {
+	case ts.TimeOfDay != nil:
+		return ts.TimeOfDay.String()
+	case ts.Sequence != nil:
+		return "#" + strconv.FormatUint(uint64(*ts.Sequence), 10)
+	case ts.DateTime.IsZero():
+		return ""
+	default:
+		return ts.DateTime.Format(time.RFC3339)
+	}
+}
+
+func runAck(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	objectID, err := parseObjectIdentifier(ackObject)
+	if err != nil {
+		return fmt.Errorf("invalid object: %w", err)
+	}
+
+	var transition *bacnet.Transition
+	if ackTransition != "" {
+		tr, err := parseTransition(ackTransition)
+		if err != nil {
+			return err
+		}
+		transition = &tr
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	// A page of event information and the acknowledgment
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(2))
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	summaries, err := client.GetEventInformation(ctx, deviceID)
+	if err != nil {
+		return fmt.Errorf("get event information: %w", err)
+	}
+
+	var summary *bacnet.EventSummary
+	for i := range summaries {
+		if summaries[i].ObjectID == objectID {
+			summary = &summaries[i]
+			break
+		}
+	}
+	if summary == nil {
+		return fmt.Errorf("%s has no active alarm", objectID)
+	}
+
+	tr := summary.EventState.Transition()
+	if transition != nil {
+		tr = *transition
+	}
+	if summary.AckedTransitions.Has(tr) {
+		return fmt.Errorf("the %s transition of %s is already acknowledged", tr, objectID)
+	}
+
+	ack := summary.Acknowledge(tr, ackOperator)
+	ack.ProcessID = ackProcessID
+	if err := client.AcknowledgeAlarm(ctx, deviceID, ack); err != nil {
+		return fmt.Errorf("acknowledge alarm: %w", err)
+	}
+
+	stamp := formatEventTimeStamp(ack.Timestamp)
+	if stamp == "" {
+		stamp = "an unspecified time"
+	}
+	fmt.Printf("Acknowledged the %s transition of %s (%s at %s) as %q\n",
+		tr, objectID, ack.EventState, stamp, ackOperator)
+	return nil
+}
+
+// parseTransition parses offnormal, fault or normal, with or without a
+// to- prefix
+func parseTransition(s string) (bacnet.Transition, error) {
+	switch strings.TrimPrefix(strings.ToLower(s), "to-") {
+	case "offnormal", "off-normal":
+		return bacnet.TransitionToOffNormal, nil
+	case "fault":
+		return bacnet.TransitionToFault, nil
+	case "normal":
+		return bacnet.TransitionToNormal, nil
+	default:
+		return 0, fmt.Errorf("unknown transition %q (expected offnormal, fault or normal)", s)
+	}
+}
@@ -0,0 +1,466 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	backupFile     string
+	backupPassword string
+	restoreForce   bool
+)
+
+var backupCmd = &cobra.Command{
+	Use:   "backup",
+	Short: "Back up the configuration files of a device",
+	Long: `Backup runs the backup procedure of a device: it starts the backup with
+ReinitializeDevice, downloads the File objects listed in the configuration-files
+of the device and ends the backup. The files are saved in a tar, tar.gz or
+zip archive, chosen by the extension of --file, with a manifest.json holding
+the device identity and the list of files.
+
+Examples:
+  edgeo-bacnet backup -d 1234 --file ahu1.tar.gz --password secret
+  edgeo-bacnet backup -d 1234 --file ahu1.zip`,
+
+	// Failed procedures are not a usage error
+	SilenceUsage: true,
+
+	RunE: runBackup,
+}
+
+var restoreCmd = &cobra.Command{
+	Use:   "restore",
+	Short: "Restore the configuration files of a device",
+	Long: `Restore runs the restore procedure of a device with an archive written by
+backup: it starts the restore with ReinitializeDevice, writes the files and
+ends the restore, or aborts it when a file cannot be written.
+
+The archive must have been taken from the same device instance, unless
+--force is given.
+
+Examples:
+  edgeo-bacnet restore -d 1234 --file ahu1.tar.gz --password secret`,
+
+	// Failed procedures are not a usage error
+	SilenceUsage: true,
+
+	RunE: runRestore,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{backupCmd, restoreCmd} {
+		cmd.Flags().StringVarP(&backupFile, "file", "f", "", "Archive file (.tar, .tar.gz, .tgz or .zip)")
+		cmd.Flags().StringVar(&backupPassword, "password", "", "Password of the ReinitializeDevice requests")
+		cmd.MarkFlagRequired("file")
+	}
+	restoreCmd.Flags().BoolVar(&restoreForce, "force", false, "Restore an archive taken from another device")
+}
+
+// BackupManifest describes the content of a backup archive
+type BackupManifest struct {
+	DeviceID                   uint32               `json:"device_id"`
+	DeviceName                 string               `json:"device_name,omitempty"`
+	VendorName                 string               `json:"vendor_name,omitempty"`
+	ModelName                  string               `json:"model_name,omitempty"`
+	FirmwareRevision           string               `json:"firmware_revision,omitempty"`
+	ApplicationSoftwareVersion string               `json:"application_software_version,omitempty"`
+	DatabaseRevision           *uint32              `json:"database_revision,omitempty"`
+	Created                    time.Time            `json:"created"`
+	Files                      []BackupManifestFile `json:"files"`
+}
+
+// BackupManifestFile is a configuration file of a backup archive
+type BackupManifestFile struct {
+	Object       string `json:"object"`
+	Path         string `json:"path"`
+	AccessMethod string `json:"access_method"`
+	Size         int    `json:"size"`
+
+	// RecordSizes splits the content of a record-access file into records
+	RecordSizes []int `json:"record_sizes,omitempty"`
+}
+
+const backupManifestName = "manifest.json"
+
+func runBackup(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+	if _, err := archiveKind(backupFile); err != nil {
+		return err
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	// The procedure waits for the device and transfers whole files; the
+	// deadline bounds the connection only
+	ctx := context.Background()
+	connectCtx, cancel := context.WithTimeout(ctx, operationDeadline(1))
+	defer cancel()
+
+	if err := client.Connect(connectCtx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	manifest := readBackupIdentity(ctx, client)
+
+	progress := newTransferProgress()
+	files, err := client.Backup(ctx, deviceID,
+		bacnet.WithBackupPassword(backupPassword),
+		bacnet.WithBackupProgress(progress.update))
+	progress.done()
+	if err != nil {
+		return fmt.Errorf("backup: %w", err)
+	}
+
+	entries := make(map[string][]byte, len(files))
+	for _, file := range files {
+		entry := BackupManifestFile{
+			Object:       file.ObjectID.String(),
+			Path:         fmt.Sprintf("files/file-%d.bin", file.ObjectID.Instance),
+			AccessMethod: file.AccessMethod.String(),
+		}
+		data := file.Data
+		if file.AccessMethod == bacnet.FileAccessRecord {
+			data = bytes.Join(file.Records, nil)
+			for _, record := range file.Records {
+				entry.RecordSizes = append(entry.RecordSizes, len(record))
+			}
+		}
+		entry.Size = len(data)
+		entries[entry.Path] = data
+		manifest.Files = append(manifest.Files, entry)
+	}
+
+	if err := writeBackupArchive(backupFile, manifest, entries); err != nil {
+		return err
+	}
+
+	if isStructuredOutput() {
+		return encodeOutput(os.Stdout, manifest)
+	}
+	printBackupFiles(manifest)
+	fmt.Printf("\nBacked up %d files of device %d to %s\n", len(manifest.Files), deviceID, backupFile)
+	return nil
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	manifest, files, err := readBackupArchive(backupFile)
+	if err != nil {
+		return err
+	}
+	if manifest.DeviceID != deviceID && !restoreForce {
+		return fmt.Errorf("%s was taken from device %d, not %d (use --force to restore it anyway)",
+			backupFile, manifest.DeviceID, deviceID)
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx := context.Background()
+	connectCtx, cancel := context.WithTimeout(ctx, operationDeadline(1))
+	defer cancel()
+
+	if err := client.Connect(connectCtx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	progress := newTransferProgress()
+	err = client.Restore(ctx, deviceID, files,
+		bacnet.WithBackupPassword(backupPassword),
+		bacnet.WithBackupProgress(progress.update))
+	progress.done()
+	if err != nil {
+		return fmt.Errorf("restore: %w", err)
+	}
+
+	if outputFmt != "json" {
+		printBackupFiles(manifest)
+		fmt.Println()
+	}
+	fmt.Printf("Restored %d files of %s to device %d\n", len(files), backupFile, deviceID)
+	return nil
+}
+
+// readBackupIdentity reads the identity of the device for the manifest.
+// Properties the device does not have are left out.
+func readBackupIdentity(ctx context.Context, client *bacnet.Client) BackupManifest {
+	manifest := BackupManifest{DeviceID: deviceID, Created: time.Now()}
+
+	deviceOID := bacnet.NewObjectIdentifier(bacnet.ObjectTypeDevice, deviceID)
+	var requests []bacnet.ReadPropertyRequest
+	for _, prop := range []bacnet.PropertyIdentifier{
+		bacnet.PropertyObjectName,
+		bacnet.PropertyVendorName,
+		bacnet.PropertyModelName,
+		bacnet.PropertyFirmwareRevision,
+		bacnet.PropertyApplicationSoftwareVersion,
+		bacnet.PropertyDatabaseRevision,
+	} {
+		requests = append(requests, bacnet.ReadPropertyRequest{ObjectID: deviceOID, PropertyID: prop})
+	}
+
+	results, err := client.Device(deviceID).ReadMultiple(ctx, requests)
+	if err != nil {
+		return manifest
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			continue
+		}
+		text, _ := r.Value.(string)
+		switch r.PropertyID {
+		case bacnet.PropertyObjectName:
+			manifest.DeviceName = text
+		case bacnet.PropertyVendorName:
+			manifest.VendorName = text
+		case bacnet.PropertyModelName:
+			manifest.ModelName = text
+		case bacnet.PropertyFirmwareRevision:
+			manifest.FirmwareRevision = text
+		case bacnet.PropertyApplicationSoftwareVersion:
+			manifest.ApplicationSoftwareVersion = text
+		case bacnet.PropertyDatabaseRevision:
+			if rev, ok := r.Value.(uint32); ok {
+				manifest.DatabaseRevision = &rev
+			}
+		}
+	}
+	return manifest
+}
+
+func printBackupFiles(manifest BackupManifest) {
+	f := NewFormatter(outputFmt)
+	headers := []string{"OBJECT", "ACCESS", "SIZE", "RECORDS", "PATH"}
+	rows := make([][]string, 0, len(manifest.Files))
+	for _, file := range manifest.Files {
+		records := ""
+		if file.RecordSizes != nil {
+			records = strconv.Itoa(len(file.RecordSizes))
+		}
+		rows = append(rows, []string{file.Object, file.AccessMethod, strconv.Itoa(file.Size), records, file.Path})
+	}
+	f.PrintTable(headers, rows)
+}
+
+// archiveKind returns the archive format of a path: tar, tgz or zip
+func archiveKind(path string) (string, error) {
+	lower := strings.ToLower(path)
+	switch {
+	case strings.HasSuffix(lower, ".tar.gz"), strings.HasSuffix(lower, ".tgz"):
+		return "tgz", nil
+	case strings.HasSuffix(lower, ".tar"):
+		return "tar", nil
+	case strings.HasSuffix(lower, ".zip"):
+		return "zip", nil
+	default:
+		return "", fmt.Errorf("unsupported archive %s (expected .tar, .tar.gz, .tgz or .zip)", path)
+	}
+}
+
+// writeBackupArchive writes the manifest and the files to an archive
+func writeBackupArchive(path string, manifest BackupManifest, entries map[string][]byte) error {
+	kind, err := archiveKind(path)
+	if err != nil {
+		return err
+	}
+
+	manifestData, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return fmt.Errorf("encode manifest: %w", err)
+	}
+
+	out, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("create archive: %w", err)
+	}
+	defer out.Close()
+
+	// The manifest comes first, then the files in manifest order
+	names := []string{backupManifestName}
+	entries[backupManifestName] = manifestData
+	for _, file := range manifest.Files {
+		names = append(names, file.Path)
+	}
+
+	if kind == "zip" {
+		zw := zip.NewWriter(out)
+		for _, name := range names {
+			w, err := zw.CreateHeader(&zip.FileHeader{Name: name, Method: zip.Deflate, Modified: manifest.Created})
+			if err != nil {
+				return fmt.Errorf("write archive: %w", err)
+			}
+			if _, err := w.Write(entries[name]); err != nil {
+				return fmt.Errorf("write archive: %w", err)
+			}
+		}
+		if err := zw.Close(); err != nil {
+			return fmt.Errorf("write archive: %w", err)
+		}
+		return out.Close()
+	}
+
+	var w io.Writer = out
+	var gz *gzip.Writer
+	if kind == "tgz" {
+		gz = gzip.NewWriter(out)
+		w = gz
+	}
+	tw := tar.NewWriter(w)
+	for _, name := range names {
+		header := &tar.Header{Name: name, Mode: 0644, Size: int64(len(entries[name])), ModTime: manifest.Created}
+		if err := tw.WriteHeader(header); err != nil {
+			return fmt.Errorf("write archive: %w", err)
+		}
+		if _, err := tw.Write(entries[name]); err != nil {
+			return fmt.Errorf("write archive: %w", err)
+		}
+	}
+	if err := tw.Close(); err != nil {
+		return fmt.Errorf("write archive: %w", err)
+	}
+	if gz != nil {
+		if err := gz.Close(); err != nil {
+			return fmt.Errorf("write archive: %w", err)
+		}
+	}
+	return out.Close()
+}
+
+// readBackupArchive reads an archive written by writeBackupArchive
+func readBackupArchive(path string) (BackupManifest, []bacnet.BackupFile, error) {
+	var manifest BackupManifest
+
+	kind, err := archiveKind(path)
+	if err != nil {
+		return manifest, nil, err
+	}
+
+	entries := make(map[string][]byte)
+	if kind == "zip" {
+		zr, err := zip.OpenReader(path)
+		if err != nil {
+			return manifest, nil, fmt.Errorf("open archive: %w", err)
+		}
+		defer zr.Close()
+		for _, f := range zr.File {
+			r, err := f.Open()
+			if err != nil {
+				return manifest, nil, fmt.Errorf("read archive: %w", err)
+			}
+			data, err := io.ReadAll(r)
+			r.Close()
+			if err != nil {
+				return manifest, nil, fmt.Errorf("read archive: %w", err)
+			}
+			entries[f.Name] = data
+		}
+	} else {
+		in, err := os.Open(path)
+		if err != nil {
+			return manifest, nil, fmt.Errorf("open archive: %w", err)
+		}
+		defer in.Close()
+
+		var r io.Reader = in
+		if kind == "tgz" {
+			gz, err := gzip.NewReader(in)
+			if err != nil {
+				return manifest, nil, fmt.Errorf("read archive: %w", err)
+			}
+			defer gz.Close()
+			r = gz
+		}
+		tr := tar.NewReader(r)
+		for {
+			header, err := tr.Next()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return manifest, nil, fmt.Errorf("read archive: %w", err)
+			}
+			data, err := io.ReadAll(tr)
+			if err != nil {
+				return manifest, nil, fmt.Errorf("read archive: %w", err)
+			}
+			entries[header.Name] = data
+		}
+	}
+
+	manifestData, ok := entries[backupManifestName]
+	if !ok {
+		return manifest, nil, fmt.Errorf("%s has no %s", path, backupManifestName)
+	}
+	if err := json.Unmarshal(manifestData, &manifest); err != nil {
+		return manifest, nil, fmt.Errorf("parse manifest: %w", err)
+	}
+
+	files := make([]bacnet.BackupFile, 0, len(manifest.Files))
+	for _, entry := range manifest.Files {
+		fileID, err := parseObjectIdentifier(entry.Object)
+		if err != nil || fileID.Type != bacnet.ObjectTypeFile {
+			return manifest, nil, fmt.Errorf("manifest: invalid file object %q", entry.Object)
+		}
+		data, ok := entries[entry.Path]
+		if !ok {
+			return manifest, nil, fmt.Errorf("%s has no %s", path, entry.Path)
+		}
+
+		file := bacnet.BackupFile{ObjectID: fileID, AccessMethod: bacnet.FileAccessStream, Data: data}
+		if entry.AccessMethod == bacnet.FileAccessRecord.String() {
+			file.AccessMethod = bacnet.FileAccessRecord
+			file.Data = nil
+			for _, size := range entry.RecordSizes {
+				if size < 0 || size > len(data) {
+					return manifest, nil, fmt.Errorf("manifest: record sizes of %s exceed its %d octets", entry.Object, entry.Size)
+				}
+				file.Records = append(file.Records, data[:size])
+				data = data[size:]
+			}
+		}
+		files = append(files, file)
+	}
+	return manifest, files, nil
+}
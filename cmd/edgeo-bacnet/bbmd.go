@@ -0,0 +1,294 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var bbmdCmd = &cobra.Command{
+	Use:   "bbmd",
+	Short: "Manage the broadcast and foreign device tables of a BBMD",
+	Long: `Bbmd reads and writes the Broadcast Distribution Table (BDT) of a BACnet
+Broadcast Management Device, which lists the peer BBMDs broadcasts are
+forwarded to, and reads and edits its Foreign Device Table (FDT), which
+lists the foreign devices registered with it.
+
+The BBMD is given with -H and -p.`,
+}
+
+var bbmdReadBDTCmd = &cobra.Command{
+	Use:   "read-bdt",
+	Short: "Print the Broadcast Distribution Table",
+	Long: `Read-bdt prints the peer BBMDs of the Broadcast Distribution Table with
+their broadcast distribution masks.
+
+Examples:
+  edgeo-bacnet bbmd read-bdt -H 192.168.1.10`,
+
+	Args: cobra.NoArgs,
+
+	// BBMD errors are not a usage error
+	SilenceUsage: true,
+
+	RunE: runBBMDReadBDT,
+}
+
+var bbmdWriteBDTCmd = &cobra.Command{
+	Use:   "write-bdt <address[:port][/mask]>...",
+	Short: "Replace the Broadcast Distribution Table",
+	Long: `Write-bdt replaces the Broadcast Distribution Table with the given
+entries, which should include the BBMD itself. The mask defaults to
+255.255.255.255, for peers forwarding broadcasts by unicast; it is given as
+a dotted mask or a prefix length.
+
+Examples:
+  edgeo-bacnet bbmd write-bdt -H 192.168.1.10 192.168.1.10 10.0.2.10 10.0.3.10:47809
+  edgeo-bacnet bbmd write-bdt -H 192.168.1.10 192.168.1.10/255.255.255.0 10.0.2.10/24`,
+
+	Args: cobra.MinimumNArgs(1),
+
+	// BBMD errors are not a usage error
+	SilenceUsage: true,
+
+	RunE: runBBMDWriteBDT,
+}
+
+var bbmdReadFDTCmd = &cobra.Command{
+	Use:   "read-fdt",
+	Short: "Print the Foreign Device Table",
+	Long: `Read-fdt prints the foreign devices registered with the BBMD, with their
+time-to-live and the time left before their registration expires.
+
+Examples:
+  edgeo-bacnet bbmd read-fdt -H 192.168.1.10`,
+
+	Args: cobra.NoArgs,
+
+	// BBMD errors are not a usage error
+	SilenceUsage: true,
+
+	RunE: runBBMDReadFDT,
+}
+
+var bbmdDeleteFDTCmd = &cobra.Command{
+	Use:   "delete-fdt <address[:port]>...",
+	Short: "Remove foreign devices from the Foreign Device Table",
+	Long: `Delete-fdt removes registrations from the Foreign Device Table.
+
+Examples:
+  edgeo-bacnet bbmd delete-fdt -H 192.168.1.10 10.8.0.5:47808`,
+
+	Args: cobra.MinimumNArgs(1),
+
+	// BBMD errors are not a usage error
+	SilenceUsage: true,
+
+	RunE: runBBMDDeleteFDT,
+}
+
+func init() {
+	bbmdCmd.AddCommand(bbmdReadBDTCmd, bbmdWriteBDTCmd, bbmdReadFDTCmd, bbmdDeleteFDTCmd)
+}
+
+// BDTRow is a Broadcast Distribution Table entry of the JSON output
+type BDTRow struct {
+	Address string `json:"address"`
+	Mask    string `json:"mask"`
+}
+
+// FDTRow is a Foreign Device Table entry of the JSON output
+type FDTRow struct {
+	Address   string `json:"address"`
+	TTL       int    `json:"ttl_seconds"`
+	Remaining int    `json:"remaining_seconds"`
+}
+
+// runBBMD connects a client and runs fn against the BBMD given with -H and
+// -p
+func runBBMD(fn func(ctx context.Context, client *bacnet.Client, bbmd string) error) error {
+	if host == "" {
+		return fmt.Errorf("BBMD address is required (-H or --host)")
+	}
+	bbmd := net.JoinHostPort(host, strconv.Itoa(port))
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(1))
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	return fn(ctx, client, bbmd)
+}
+
+func runBBMDReadBDT(cmd *cobra.Command, args []string) error {
+	return runBBMD(func(ctx context.Context, client *bacnet.Client, bbmd string) error {
+		entries, err := client.ReadBroadcastDistributionTable(ctx, bbmd)
+		if err != nil {
+			return fmt.Errorf("read BDT: %w", err)
+		}
+
+		rows := make([]BDTRow, 0, len(entries))
+		for _, entry := range entries {
+			rows = append(rows, BDTRow{Address: entry.Address.String(), Mask: net.IP(entry.Mask).String()})
+		}
+
+		if isStructuredOutput() {
+			return encodeOutput(os.Stdout, rows)
+		}
+		if len(rows) == 0 {
+			fmt.Println("The Broadcast Distribution Table is empty")
+			return nil
+		}
+		table := make([][]string, 0, len(rows))
+		for _, row := range rows {
+			table = append(table, []string{row.Address, row.Mask})
+		}
+		NewFormatter(outputFmt).PrintTable([]string{"ADDRESS", "MASK"}, table)
+		return nil
+	})
+}
+
+func runBBMDWriteBDT(cmd *cobra.Command, args []string) error {
+	entries := make([]bacnet.BDTEntry, 0, len(args))
+	for _, arg := range args {
+		entry, err := parseBDTEntry(arg)
+		if err != nil {
+			return err
+		}
+		entries = append(entries, entry)
+	}
+
+	return runBBMD(func(ctx context.Context, client *bacnet.Client, bbmd string) error {
+		if err := client.WriteBroadcastDistributionTable(ctx, bbmd, entries); err != nil {
+			return fmt.Errorf("write BDT: %w", err)
+		}
+		fmt.Printf("Wrote %d entries to the Broadcast Distribution Table of %s\n", len(entries), bbmd)
+		return nil
+	})
+}
+
+func runBBMDReadFDT(cmd *cobra.Command, args []string) error {
+	return runBBMD(func(ctx context.Context, client *bacnet.Client, bbmd string) error {
+		entries, err := client.ReadForeignDeviceTable(ctx, bbmd)
+		if err != nil {
+			return fmt.Errorf("read FDT: %w", err)
+		}
+
+		rows := make([]FDTRow, 0, len(entries))
+		for _, entry := range entries {
+			rows = append(rows, FDTRow{
+				Address:   entry.Address.String(),
+				TTL:       int(entry.TTL / time.Second),
+				Remaining: int(entry.Remaining / time.Second),
+			})
+		}
+
+		if isStructuredOutput() {
+			return encodeOutput(os.Stdout, rows)
+		}
+		if len(rows) == 0 {
+			fmt.Println("No foreign devices are registered")
+			return nil
+		}
+		table := make([][]string, 0, len(rows))
+		for _, entry := range entries {
+			table = append(table, []string{entry.Address.String(), entry.TTL.String(), entry.Remaining.String()})
+		}
+		NewFormatter(outputFmt).PrintTable([]string{"ADDRESS", "TTL", "REMAINING"}, table)
+		return nil
+	})
+}
+
+func runBBMDDeleteFDT(cmd *cobra.Command, args []string) error {
+	devices := make([]*net.UDPAddr, 0, len(args))
+	for _, arg := range args {
+		addr, err := parseBIPAddress(arg)
+		if err != nil {
+			return err
+		}
+		devices = append(devices, addr)
+	}
+
+	return runBBMD(func(ctx context.Context, client *bacnet.Client, bbmd string) error {
+		for _, device := range devices {
+			if err := client.DeleteForeignDeviceTableEntry(ctx, bbmd, device); err != nil {
+				return fmt.Errorf("delete %s: %w", device, err)
+			}
+			fmt.Printf("Deleted %s from the Foreign Device Table of %s\n", device, bbmd)
+		}
+		return nil
+	})
+}
+
+// parseBDTEntry parses a BDT entry as address[:port][/mask], the mask being
+// dotted or a prefix length
+func parseBDTEntry(s string) (bacnet.BDTEntry, error) {
+	addrPart, maskPart, hasMask := strings.Cut(s, "/")
+	addr, err := parseBIPAddress(addrPart)
+	if err != nil {
+		return bacnet.BDTEntry{}, err
+	}
+	entry := bacnet.BDTEntry{Address: addr}
+	if !hasMask {
+		return entry, nil
+	}
+
+	if bits, err := strconv.Atoi(maskPart); err == nil {
+		if bits < 0 || bits > 32 {
+			return bacnet.BDTEntry{}, fmt.Errorf("invalid mask in %q", s)
+		}
+		entry.Mask = net.CIDRMask(bits, 32)
+		return entry, nil
+	}
+	mask := net.ParseIP(maskPart).To4()
+	if mask == nil {
+		return bacnet.BDTEntry{}, fmt.Errorf("invalid mask in %q", s)
+	}
+	entry.Mask = net.IPMask(mask)
+	return entry, nil
+}
+
+// parseBIPAddress parses an IPv4 address[:port]; the port defaults to
+// 47808
+func parseBIPAddress(s string) (*net.UDPAddr, error) {
+	ipPart, portPart, err := net.SplitHostPort(s)
+	if err != nil {
+		ipPart, portPart = s, strconv.Itoa(bacnet.DefaultPort)
+	}
+	ip := net.ParseIP(ipPart).To4()
+	p, err := strconv.Atoi(portPart)
+	if ip == nil || err != nil || p < 1 || p > 65535 {
+		return nil, fmt.Errorf("invalid B/IP address %q", s)
+	}
+	return &net.UDPAddr{IP: ip, Port: p}, nil
+}
@@ -0,0 +1,396 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"os/signal"
+	"sort"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	benchDuration    time.Duration
+	benchConcurrency int
+	benchMix         map[string]int
+	benchObjects     []string
+	benchProperty    string
+	benchPriority    int
+)
+
+// benchOps are the requests of the bench mix, in report order
+var benchOps = []string{"rp", "rpm", "wp"}
+
+var benchCmd = &cobra.Command{
+	Use:   "bench",
+	Short: "Load-test a device with concurrent requests",
+	Long: `Bench sends requests to a device from --concurrency workers for --duration
+and reports the throughput, the latency percentiles of each kind of
+request and the errors by kind.
+
+The requests are drawn at random in the proportions of --mix:
+  rp   ReadProperty of --property of one of --objects, in turn
+  rpm  ReadPropertyMultiple of --property of all --objects
+  wp   WriteProperty of --property of one of --objects, in turn, with
+       the value read before the bench at --priority
+
+Writes command the objects: the value written is the value read, and the
+priority is relinquished after the bench when --property is present-value.
+
+Examples:
+  # Read the device object name for 10 seconds with 4 workers
+  edgeo-bacnet bench -d 1234
+
+  # A mix of reads and writes of present values, 16 workers, 1 minute
+  edgeo-bacnet bench -d 1234 --objects av:1,av:2,bv:1 --property present-value \
+      --mix rp=60,rpm=30,wp=10 --concurrency 16 --duration 1m
+
+  # Capacity report as JSON
+  edgeo-bacnet bench -d 1234 -o json`,
+
+	// Failed requests are not a usage error
+	SilenceUsage: true,
+
+	RunE: runBench,
+}
+
+func init() {
+	benchCmd.Flags().DurationVar(&benchDuration, "duration", 10*time.Second, "Duration of the bench")
+	benchCmd.Flags().IntVar(&benchConcurrency, "concurrency", 4, "Requests in flight at the same time")
+	benchCmd.Flags().StringToIntVar(&benchMix, "mix", map[string]int{"rp": 100}, "Proportions of the requests: rp, rpm and wp")
+	benchCmd.Flags().StringSliceVar(&benchObjects, "objects", nil, "Objects to read and write (default the device object)")
+	benchCmd.Flags().StringVar(&benchProperty, "property", "object-name", "Property to read and write")
+	benchCmd.Flags().IntVar(&benchPriority, "priority", 16, "Priority of the writes (1-16, 0 for no priority)")
+}
+
+// BenchResult is the outcome of a bench
+type BenchResult struct {
+	DeviceID    uint32                `json:"device_id"`
+	Duration    float64               `json:"duration_s"`
+	Concurrency int                   `json:"concurrency"`
+	Throughput  float64               `json:"throughput_rps"`
+	Total       BenchStats            `json:"total"`
+	Operations  map[string]BenchStats `json:"operations"`
+	Errors      map[string]int        `json:"errors,omitempty"`
+}
+
+// BenchStats are the request counts and latencies of one kind of request
+type BenchStats struct {
+	Requests int     `json:"requests"`
+	Errors   int     `json:"errors"`
+	MinMs    float64 `json:"min_ms"`
+	P50Ms    float64 `json:"p50_ms"`
+	P90Ms    float64 `json:"p90_ms"`
+	P99Ms    float64 `json:"p99_ms"`
+	MaxMs    float64 `json:"max_ms"`
+}
+
+// benchWorker holds what a worker measured, merged after the bench
+type benchWorker struct {
+	latencies map[string][]time.Duration
+	failures  map[string]int
+	errors    map[string]int
+}
+
+func runBench(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+	if benchConcurrency < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if benchPriority < 0 || benchPriority > 16 {
+		return fmt.Errorf("--priority must be 0-16")
+	}
+
+	weight := 0
+	for op, w := range benchMix {
+		switch op {
+		case "rp", "rpm", "wp":
+		default:
+			return fmt.Errorf("unknown request %q in --mix: use rp, rpm or wp", op)
+		}
+		if w < 0 {
+			return fmt.Errorf("negative proportion of %s in --mix", op)
+		}
+		weight += w
+	}
+	if weight == 0 {
+		return fmt.Errorf("--mix has no requests")
+	}
+
+	objects := []bacnet.ObjectIdentifier{bacnet.NewObjectIdentifier(bacnet.ObjectTypeDevice, deviceID)}
+	if len(benchObjects) > 0 {
+		objects = objects[:0]
+		for _, s := range benchObjects {
+			oid, err := parseObjectIdentifier(s)
+			if err != nil {
+				return err
+			}
+			objects = append(objects, oid)
+		}
+	}
+	prop, err := parsePropertyIdentifier(benchProperty)
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	// The writes write back the values read
+	values := make([]interface{}, len(objects))
+	if benchMix["wp"] > 0 {
+		for i, oid := range objects {
+			readCtx, cancel := context.WithTimeout(ctx, operationDeadline(1))
+			values[i], err = client.ReadProperty(readCtx, deviceID, oid, prop)
+			cancel()
+			if err != nil {
+				return fmt.Errorf("read %s %s: %w", oid, prop, err)
+			}
+		}
+		if prop == bacnet.PropertyPresentValue && benchPriority > 0 {
+			defer benchRelease(client, objects)
+		}
+	}
+
+	var writeOpts []bacnet.WriteOption
+	if benchPriority > 0 {
+		writeOpts = append(writeOpts, bacnet.WithPriority(uint8(benchPriority)))
+	}
+	requests := make([]bacnet.ReadPropertyRequest, len(objects))
+	for i, oid := range objects {
+		requests[i] = bacnet.ReadPropertyRequest{ObjectID: oid, PropertyID: prop}
+	}
+
+	// send sends one request of a kind, for the nth object
+	send := func(ctx context.Context, op string, n int) error {
+		i := n % len(objects)
+		switch op {
+		case "rp":
+			_, err := client.ReadProperty(ctx, deviceID, objects[i], prop)
+			return err
+		case "rpm":
+			_, err := client.ReadPropertyMultiple(ctx, deviceID, requests)
+			return err
+		default:
+			return client.WriteProperty(ctx, deviceID, objects[i], prop, values[i], writeOpts...)
+		}
+	}
+
+	if !isStructuredOutput() {
+		fmt.Fprintf(os.Stderr, "Benchmarking device %d for %s with %d workers...\n", deviceID, benchDuration, benchConcurrency)
+	}
+
+	benchCtx, cancel := context.WithTimeout(ctx, benchDuration)
+	defer cancel()
+
+	start := time.Now()
+	workers := make([]*benchWorker, benchConcurrency)
+	var wg sync.WaitGroup
+	for w := range workers {
+		worker := &benchWorker{
+			latencies: make(map[string][]time.Duration),
+			failures:  make(map[string]int),
+			errors:    make(map[string]int),
+		}
+		workers[w] = worker
+
+		wg.Add(1)
+		go func(seed int64) {
+			defer wg.Done()
+			rnd := rand.New(rand.NewSource(seed))
+			for n := 0; benchCtx.Err() == nil; n++ {
+				op := benchPick(rnd.Intn(weight))
+
+				reqCtx, reqCancel := context.WithTimeout(benchCtx, operationDeadline(1))
+				t := time.Now()
+				err := send(reqCtx, op, n)
+				latency := time.Since(t)
+				reqCancel()
+
+				// Requests cut short by the end of the bench are not counted
+				if benchCtx.Err() != nil {
+					break
+				}
+				worker.latencies[op] = append(worker.latencies[op], latency)
+				if err != nil {
+					worker.failures[op]++
+					worker.errors[benchErrorKind(err)]++
+				}
+			}
+		}(start.UnixNano() + int64(w))
+	}
+	wg.Wait()
+	elapsed := time.Since(start)
+
+	result := benchMerge(workers, elapsed)
+
+	if isStructuredOutput() {
+		return encodeOutput(os.Stdout, result)
+	}
+	outputBenchTable(result)
+	return nil
+}
+
+// benchPick returns the kind of request drawn by r, in [0, weight of the mix)
+func benchPick(r int) string {
+	for _, op := range benchOps {
+		if r < benchMix[op] {
+			return op
+		}
+		r -= benchMix[op]
+	}
+	return benchOps[len(benchOps)-1]
+}
+
+// benchErrorKind returns the kind of an error for the error breakdown: the
+// error type, with the BACnet error code or reject or abort reason
+func benchErrorKind(err error) string {
+	d := describeError(err)
+	switch {
+	case d.Code != "":
+		return d.Type + ": " + d.Class + "/" + d.Code
+	case d.Reason != "":
+		return d.Type + ": " + d.Reason
+	case d.Type == "error":
+		return err.Error()
+	default:
+		return d.Type
+	}
+}
+
+// benchRelease relinquishes the priority of the writes of the bench
+func benchRelease(client *bacnet.Client, objects []bacnet.ObjectIdentifier) {
+	for _, oid := range objects {
+		ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(1))
+		if err := client.ReleasePriority(ctx, deviceID, oid, uint8(benchPriority)); err != nil {
+			fmt.Fprintf(os.Stderr, "Warning: release priority %d of %s: %v\n", benchPriority, oid, err)
+		}
+		cancel()
+	}
+}
+
+// benchMerge merges the measures of the workers
+func benchMerge(workers []*benchWorker, elapsed time.Duration) BenchResult {
+	result := BenchResult{
+		DeviceID:    deviceID,
+		Duration:    elapsed.Seconds(),
+		Concurrency: len(workers),
+		Operations:  make(map[string]BenchStats),
+		Errors:      make(map[string]int),
+	}
+
+	var all []time.Duration
+	failures := 0
+	for _, op := range benchOps {
+		var latencies []time.Duration
+		opFailures := 0
+		for _, w := range workers {
+			latencies = append(latencies, w.latencies[op]...)
+			opFailures += w.failures[op]
+		}
+		if len(latencies) == 0 {
+			continue
+		}
+		result.Operations[op] = benchStats(latencies, opFailures)
+		all = append(all, latencies...)
+		failures += opFailures
+	}
+	for _, w := range workers {
+		for kind, n := range w.errors {
+			result.Errors[kind] += n
+		}
+	}
+
+	result.Total = benchStats(all, failures)
+	if elapsed > 0 {
+		result.Throughput = float64(result.Total.Requests) / elapsed.Seconds()
+	}
+	return result
+}
+
+// benchStats computes the statistics of the latencies of requests
+func benchStats(latencies []time.Duration, failures int) BenchStats {
+	stats := BenchStats{Requests: len(latencies), Errors: failures}
+	if len(latencies) == 0 {
+		return stats
+	}
+
+	sort.Slice(latencies, func(i, j int) bool { return latencies[i] < latencies[j] })
+	ms := func(d time.Duration) float64 { return float64(d) / float64(time.Millisecond) }
+	percentile := func(p float64) float64 {
+		return ms(latencies[int(p*float64(len(latencies)-1))])
+	}
+	stats.MinMs = ms(latencies[0])
+	stats.P50Ms = percentile(0.50)
+	stats.P90Ms = percentile(0.90)
+	stats.P99Ms = percentile(0.99)
+	stats.MaxMs = ms(latencies[len(latencies)-1])
+	return stats
+}
+
+func outputBenchTable(result BenchResult) {
+	f := NewFormatter(outputFmt)
+
+	f.Printf("Device %d: %d requests in %.1fs with %d workers, %.1f requests/s\n\n",
+		result.DeviceID, result.Total.Requests, result.Duration, result.Concurrency, result.Throughput)
+
+	headers := []string{"REQUEST", "COUNT", "ERRORS", "MIN", "P50", "P90", "P99", "MAX"}
+	row := func(name string, s BenchStats) []string {
+		return []string{name, fmt.Sprint(s.Requests), fmt.Sprint(s.Errors),
+			fmt.Sprintf("%.1f ms", s.MinMs), fmt.Sprintf("%.1f ms", s.P50Ms), fmt.Sprintf("%.1f ms", s.P90Ms),
+			fmt.Sprintf("%.1f ms", s.P99Ms), fmt.Sprintf("%.1f ms", s.MaxMs)}
+	}
+	var rows [][]string
+	for _, op := range benchOps {
+		if s, ok := result.Operations[op]; ok {
+			rows = append(rows, row(op, s))
+		}
+	}
+	rows = append(rows, row("total", result.Total))
+	f.PrintTable(headers, rows)
+
+	if len(result.Errors) > 0 {
+		kinds := make([]string, 0, len(result.Errors))
+		for kind := range result.Errors {
+			kinds = append(kinds, kind)
+		}
+		sort.Strings(kinds)
+		f.Println("\nErrors:")
+		for _, kind := range kinds {
+			f.Printf("  %6d  %s\n", result.Errors[kind], kind)
+		}
+	}
+}
@@ -0,0 +1,213 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var compareIgnore []string
+
+// errDrift is returned by compare when the snapshots differ
+var errDrift = errors.New("drift detected")
+
+var compareCmd = &cobra.Command{
+	Use:     "compare <before> [after]",
+	Aliases: []string{"diff"},
+	Short:   "Compare two device snapshots to detect configuration drift",
+	Long: `Compare diffs two snapshots of a device, each either a JSON file written by
+dump or an EPICS file, and reports the objects added and removed and the
+property values that changed. Properties found in only one snapshot are
+not compared. It exits with code 6 when drift is found.
+
+Given a single snapshot, compare reads the device instead, -d or the
+device of the snapshot, with the properties found in the snapshot.
+
+Properties that change in normal operation are ignored by default; pass
+--ignore "" to compare every property.
+
+Examples:
+  # Compare the commissioning dump with today's
+  edgeo-bacnet dump -d 1234 -o json -f now.json
+  edgeo-bacnet compare commissioning.json now.json
+
+  # Compare a dump with the vendor EPICS, ignoring descriptions too
+  edgeo-bacnet compare device.epics now.json --ignore present-value,status-flags,description
+
+  # Compare the commissioning dump with the device itself
+  edgeo-bacnet diff commissioning.json`,
+
+	Args: cobra.RangeArgs(1, 2),
+
+	// Drift is not a usage error
+	SilenceUsage: true,
+
+	RunE: runCompare,
+}
+
+func init() {
+	compareCmd.Flags().StringSliceVar(&compareIgnore, "ignore", []string{"present-value", "status-flags", "event-state"}, "Properties to ignore")
+}
+
+func runCompare(cmd *cobra.Command, args []string) error {
+	before, err := bacnet.ReadSnapshotFile(args[0])
+	if err != nil {
+		return err
+	}
+	var after *bacnet.DeviceSnapshot
+	if len(args) == 2 {
+		after, err = bacnet.ReadSnapshotFile(args[1])
+	} else {
+		after, err = readLiveSnapshot(before)
+	}
+	if err != nil {
+		return err
+	}
+
+	diff := bacnet.CompareSnapshots(before, after, compareIgnore...)
+
+	switch outputFmt {
+	case "json", "yaml":
+		err = encodeOutput(os.Stdout, diff)
+	case "csv":
+		err = outputCompareCSV(diff)
+	default:
+		outputCompareTable(diff)
+	}
+	if err != nil {
+		return err
+	}
+
+	if !diff.Empty() {
+		return fmt.Errorf("%w: %d added, %d removed, %d changed",
+			errDrift, len(diff.Added), len(diff.Removed), len(diff.Changed))
+	}
+	return nil
+}
+
+// readLiveSnapshot reads a snapshot of the device of before, or of -d,
+// with the properties before has for each object. Objects added since are
+// read for their name only: compare lists them without their properties.
+func readLiveSnapshot(before *bacnet.DeviceSnapshot) (*bacnet.DeviceSnapshot, error) {
+	if deviceID == 0 {
+		deviceID = before.DeviceID
+	}
+	if deviceID == 0 {
+		return nil, fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	props := make(map[string][]bacnet.PropertyIdentifier, len(before.Objects))
+	for _, obj := range before.Objects {
+		for name := range obj.Properties {
+			if prop, ok := bacnet.ParsePropertyIdentifier(name); ok {
+				props[obj.ObjectID] = append(props[obj.ObjectID], prop)
+			}
+		}
+	}
+	propsFor := func(obj bacnet.ObjectIdentifier) []bacnet.PropertyIdentifier {
+		if p, ok := props[obj.String()]; ok {
+			return p
+		}
+		return []bacnet.PropertyIdentifier{bacnet.PropertyObjectName}
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return nil, fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return nil, fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	objects, err := client.GetObjectList(ctx, deviceID)
+	if err != nil {
+		return nil, fmt.Errorf("get object list: %w", err)
+	}
+
+	// An object missing from a partial snapshot would show as removed
+	dumped, failed := readDumpObjects(ctx, client, objects, propsFor, nil)
+	if len(dumped) < len(objects) {
+		if err := ctx.Err(); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("read device: %d of %d objects not read: %w", len(objects)-len(dumped), len(objects), failed)
+	}
+
+	after := &bacnet.DeviceSnapshot{
+		DeviceID:  deviceID,
+		Timestamp: time.Now(),
+		Objects:   make([]bacnet.ObjectSnapshot, 0, len(objects)),
+	}
+	for _, obj := range objects {
+		after.Objects = append(after.Objects, dumped[obj.String()])
+	}
+	return after, nil
+}
+
+func outputCompareCSV(diff *bacnet.SnapshotDiff) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	writer.Write([]string{"change", "object", "property", "old", "new"})
+	for _, oid := range diff.Added {
+		writer.Write([]string{"added", oid, "", "", ""})
+	}
+	for _, oid := range diff.Removed {
+		writer.Write([]string{"removed", oid, "", "", ""})
+	}
+	for _, c := range diff.Changed {
+		writer.Write([]string{"changed", c.ObjectID, c.Property, fmt.Sprint(c.Old), fmt.Sprint(c.New)})
+	}
+
+	return writer.Error()
+}
+
+func outputCompareTable(diff *bacnet.SnapshotDiff) {
+	f := NewFormatter(outputFmt)
+
+	if diff.Empty() {
+		f.Println("No drift")
+		return
+	}
+
+	headers := []string{"CHANGE", "OBJECT", "PROPERTY", "OLD", "NEW"}
+	rows := make([][]string, 0, len(diff.Added)+len(diff.Removed)+len(diff.Changed))
+	for _, oid := range diff.Added {
+		rows = append(rows, []string{"added", oid, "", "", ""})
+	}
+	for _, oid := range diff.Removed {
+		rows = append(rows, []string{"removed", oid, "", "", ""})
+	}
+	for _, c := range diff.Changed {
+		rows = append(rows, []string{"changed", c.ObjectID, c.Property, fmt.Sprint(c.Old), fmt.Sprint(c.New)})
+	}
+	f.PrintTable(headers, rows)
+}
@@ -0,0 +1,368 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/pflag"
+	"github.com/spf13/viper"
+)
+
+var configCmd = &cobra.Command{
+	Use:   "config",
+	Short: "List and edit the configuration file",
+	Long: `Config lists and edits the configuration file, which gives the defaults of
+the global flags, named device aliases and site profiles:
+
+  timeout: 3s
+  devices:
+    ahu1: 1234
+    ahu2: 1235
+  sites:
+    plant-a:
+      bbmd: 10.1.0.1
+      bbmd-ttl: 5m
+      devices:
+        boiler: 20001
+
+A device alias is accepted wherever a device instance is, as in -d ahu1.
+--site plant-a applies the settings of the profile, which take precedence
+over the top-level ones but not over flags, and its device aliases.
+
+Keys are written with dots, e.g. devices.ahu1 or sites.plant-a.bbmd.`,
+}
+
+var configListCmd = &cobra.Command{
+	Use:   "list",
+	Short: "Print the device aliases, site profiles and settings",
+	Long: `List prints the configuration file used, its settings, device aliases and
+site profiles.
+
+Examples:
+  edgeo-bacnet config list
+  edgeo-bacnet config list -o json`,
+
+	Args: cobra.NoArgs,
+
+	RunE: runConfigList,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a setting, device alias or site profile setting",
+	Long: `Set writes a value to the configuration file, creating the file if needed.
+
+Examples:
+  edgeo-bacnet config set devices.ahu1 1234
+  edgeo-bacnet config set sites.plant-a.bbmd 10.1.0.1
+  edgeo-bacnet config set timeout 5s`,
+
+	Args: cobra.ExactArgs(2),
+
+	RunE: runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a setting, device alias or site profile",
+	Long: `Unset removes a key from the configuration file, with everything below it.
+
+Examples:
+  edgeo-bacnet config unset devices.ahu1
+  edgeo-bacnet config unset sites.plant-a`,
+
+	Args: cobra.ExactArgs(1),
+
+	RunE: runConfigUnset,
+}
+
+func init() {
+	configCmd.AddCommand(configListCmd, configSetCmd, configUnsetCmd)
+}
+
+// applyConfig fills in the global flags not given on the command line: from
+// the site profile first, then from the configuration file and environment
+func applyConfig(cmd *cobra.Command) error {
+	flags := cmd.Flags()
+	fill := func(name, value string) error {
+		if err := flags.Set(name, value); err != nil {
+			return fmt.Errorf("config %s: %w", name, err)
+		}
+		return nil
+	}
+
+	// The site may itself come from the configuration
+	if site := flags.Lookup("site"); site != nil && !site.Changed && viper.IsSet("site") {
+		if err := fill("site", viper.GetString("site")); err != nil {
+			return err
+		}
+	}
+
+	if siteName != "" && viper.IsSet("sites."+siteName) {
+		for name, value := range viper.GetStringMap("sites." + siteName) {
+			if name == "devices" {
+				continue
+			}
+			flag := flags.Lookup(name)
+			if flag == nil || cmd.Root().PersistentFlags().Lookup(name) == nil {
+				return fmt.Errorf("site %s: unknown setting %q", siteName, name)
+			}
+			if !flag.Changed {
+				if err := fill(name, fmt.Sprint(value)); err != nil {
+					return err
+				}
+			}
+		}
+	}
+
+	var err error
+	cmd.Root().PersistentFlags().VisitAll(func(f *pflag.Flag) {
+		if err != nil || f.Name == "config" {
+			return
+		}
+		if flag := flags.Lookup(f.Name); flag != nil && !flag.Changed && viper.IsSet(f.Name) {
+			err = fill(f.Name, viper.GetString(f.Name))
+		}
+	})
+	return err
+}
+
+// resolveDevice returns the instance of a device given by number or by an
+// alias of the site profile or of the configuration file
+func resolveDevice(name string) (uint32, error) {
+	if name == "" {
+		return 0, nil
+	}
+	if id, err := strconv.ParseUint(name, 10, 32); err == nil {
+		return uint32(id), nil
+	}
+
+	keys := []string{"devices." + name}
+	if siteName != "" {
+		keys = append([]string{"sites." + siteName + ".devices." + name}, keys...)
+	}
+	for _, key := range keys {
+		if !viper.IsSet(key) {
+			continue
+		}
+		id, err := strconv.ParseUint(viper.GetString(key), 10, 32)
+		if err != nil {
+			return 0, fmt.Errorf("device alias %s: invalid instance %q", name, viper.GetString(key))
+		}
+		return uint32(id), nil
+	}
+	return 0, fmt.Errorf("unknown device %q: not an instance or a device alias", name)
+}
+
+// configPath returns the configuration file to edit
+func configPath() (string, error) {
+	if cfgFile != "" {
+		return cfgFile, nil
+	}
+	if used := viper.ConfigFileUsed(); used != "" {
+		return used, nil
+	}
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", err
+	}
+	return filepath.Join(home, ".edgeo-bacnet.yaml"), nil
+}
+
+// readConfigFile reads the settings of the configuration file alone, without
+// the flags and environment bound to the global configuration
+func readConfigFile(path string) (map[string]interface{}, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		if errors.Is(err, fs.ErrNotExist) {
+			return map[string]interface{}{}, nil
+		}
+		return nil, fmt.Errorf("read config: %w", err)
+	}
+	return v.AllSettings(), nil
+}
+
+func writeConfigFile(path string, settings map[string]interface{}) error {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.MergeConfigMap(settings); err != nil {
+		return err
+	}
+	if err := v.WriteConfigAs(path); err != nil {
+		return fmt.Errorf("write config: %w", err)
+	}
+	return nil
+}
+
+func runConfigList(cmd *cobra.Command, args []string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	settings, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	if isStructuredOutput() {
+		return encodeOutput(os.Stdout, settings)
+	}
+
+	fmt.Printf("Config file: %s\n", path)
+	if len(settings) == 0 {
+		fmt.Println("\nNo settings")
+		return nil
+	}
+
+	devices, _ := settings["devices"].(map[string]interface{})
+	sites, _ := settings["sites"].(map[string]interface{})
+	var keys []string
+	for key := range settings {
+		if key != "devices" && key != "sites" {
+			keys = append(keys, key)
+		}
+	}
+	if len(keys) > 0 {
+		fmt.Println("\nSettings:")
+		printConfigMap("  ", settings, keys)
+	}
+
+	if len(devices) > 0 {
+		fmt.Println("\nDevices:")
+		printConfigMap("  ", devices, sortedKeys(devices))
+	}
+
+	for _, name := range sortedKeys(sites) {
+		fmt.Printf("\nSite %s:\n", name)
+		site, _ := sites[name].(map[string]interface{})
+		var keys []string
+		for key := range site {
+			if key != "devices" {
+				keys = append(keys, key)
+			}
+		}
+		printConfigMap("  ", site, keys)
+		if siteDevices, ok := site["devices"].(map[string]interface{}); ok && len(siteDevices) > 0 {
+			fmt.Println("  devices:")
+			printConfigMap("    ", siteDevices, sortedKeys(siteDevices))
+		}
+	}
+	return nil
+}
+
+// printConfigMap prints the keys of a settings map, aligned and sorted
+func printConfigMap(indent string, m map[string]interface{}, keys []string) {
+	sort.Strings(keys)
+	width := 0
+	for _, key := range keys {
+		if len(key) > width {
+			width = len(key)
+		}
+	}
+	for _, key := range keys {
+		fmt.Printf("%s%-*s  %v\n", indent, width, key, m[key])
+	}
+}
+
+func sortedKeys(m map[string]interface{}) []string {
+	keys := make([]string, 0, len(m))
+	for key := range m {
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	settings, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(strings.ToLower(args[0]), ".")
+	if len(parts) == 2 && parts[0] == "devices" {
+		if _, err := strconv.ParseUint(args[1], 10, 32); err != nil {
+			return fmt.Errorf("invalid device instance %q", args[1])
+		}
+	}
+
+	m := settings
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]interface{})
+		if !ok {
+			next = make(map[string]interface{})
+			m[part] = next
+		}
+		m = next
+	}
+	m[parts[len(parts)-1]] = configValue(args[1])
+
+	return writeConfigFile(path, settings)
+}
+
+// configValue keeps numbers and booleans typed in the configuration file
+func configValue(s string) interface{} {
+	if i, err := strconv.ParseInt(s, 10, 64); err == nil {
+		return i
+	}
+	switch s {
+	case "true":
+		return true
+	case "false":
+		return false
+	}
+	return s
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	path, err := configPath()
+	if err != nil {
+		return err
+	}
+	settings, err := readConfigFile(path)
+	if err != nil {
+		return err
+	}
+
+	parts := strings.Split(strings.ToLower(args[0]), ".")
+	m := settings
+	for _, part := range parts[:len(parts)-1] {
+		next, ok := m[part].(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("%s is not set", args[0])
+		}
+		m = next
+	}
+	if _, ok := m[parts[len(parts)-1]]; !ok {
+		return fmt.Errorf("%s is not set", args[0])
+	}
+	delete(m, parts[len(parts)-1])
+
+	return writeConfigFile(path, settings)
+}
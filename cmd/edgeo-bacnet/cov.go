@@ -0,0 +1,247 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	covObjects   []string
+	covLifetime  uint32
+	covConfirmed bool
+	covIncrement float32
+)
+
+var covCmd = &cobra.Command{
+	Use:   "cov",
+	Short: "Subscribe to the COV notifications of objects",
+	Long: `Cov subscribes to the change of value notifications of one or more
+objects and prints every notification as it arrives, until interrupted with
+Ctrl+C, when the subscriptions are cancelled.
+
+Subscriptions are renewed halfway through their --lifetime, so that they
+outlive it; a lifetime of 0 subscribes indefinitely, which not every device
+accepts. With --confirmed, the device sends confirmed notifications, which
+it retries until acknowledged. With --increment, the present value is
+subscribed to with SubscribeCOVProperty, which carries the increment and
+which the device must support.
+
+Examples:
+  # Notifications of an analog input
+  edgeo-bacnet cov -d 1234 -O ai:1 --lifetime 300
+
+  # Two objects, confirmed notifications, as JSON lines
+  edgeo-bacnet cov -d 1234 -O ai:1 -O bi:2 --confirmed -o json
+
+  # Notify changes of at least 0.5
+  edgeo-bacnet cov -d 1234 -O ai:1 --increment 0.5
+
+  # InfluxDB line protocol, tagged with the units of the objects
+  edgeo-bacnet cov -d 1234 -O ai:1 -O ai:2 -o influx >> zone.lp`,
+	RunE: runCOV,
+}
+
+func init() {
+	covCmd.Flags().StringArrayVarP(&covObjects, "object", "O", nil, "Object to subscribe to (repeatable, e.g. ai:1)")
+	covCmd.Flags().Uint32Var(&covLifetime, "lifetime", 300, "Subscription lifetime in seconds (0 = indefinite)")
+	covCmd.Flags().BoolVar(&covConfirmed, "confirmed", false, "Request confirmed notifications")
+	covCmd.Flags().Float32Var(&covIncrement, "increment", 0, "COV increment of analog objects (default: the object's own)")
+
+	covCmd.MarkFlagRequired("object")
+}
+
+// covSubscription is a subscription of the cov command
+type covSubscription struct {
+	objectID bacnet.ObjectIdentifier
+	subID    uint32
+}
+
+func runCOV(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	objectIDs := make([]bacnet.ObjectIdentifier, 0, len(covObjects))
+	for _, s := range covObjects {
+		objectID, err := parseObjectIdentifier(s)
+		if err != nil {
+			return fmt.Errorf("invalid object %q: %w", s, err)
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	subOpts := []bacnet.SubscribeOption{bacnet.WithConfirmedNotifications(covConfirmed)}
+	if covLifetime > 0 {
+		subOpts = append(subOpts, bacnet.WithSubscriptionLifetime(covLifetime))
+	}
+	if cmd.Flags().Changed("increment") {
+		subOpts = append(subOpts, bacnet.WithCOVIncrement(covIncrement))
+	}
+
+	// State texts are read up front: the COV handler must not block on
+	// further requests
+	texts := make(map[bacnet.ObjectIdentifier]*bacnet.StateTexts)
+	for _, objectID := range objectIDs {
+		textCtx, textCancel := context.WithTimeout(ctx, timeout)
+		texts[objectID] = readStateTexts(textCtx, client, deviceID, objectID, bacnet.PropertyPresentValue)
+		textCancel()
+	}
+
+	printer := newCOVPrinter(texts)
+	if outputFmt == "influx" {
+		printer.lines = newLineProtocolEncoder(ctx, client, deviceID, objectIDs...)
+	}
+	var subs []covSubscription
+	defer func() {
+		// Subscriptions are cancelled even after Ctrl+C
+		unsubCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		for _, sub := range subs {
+			if err := client.UnsubscribeCOV(unsubCtx, deviceID, sub.objectID, sub.subID); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to unsubscribe from %s: %v\n", sub.objectID, err)
+			}
+		}
+	}()
+
+	for _, objectID := range objectIDs {
+		subCtx, subCancel := context.WithTimeout(ctx, operationDeadline(1))
+		subID, err := client.SubscribeCOV(subCtx, deviceID, objectID, printer.notify, subOpts...)
+		subCancel()
+		if err != nil {
+			return fmt.Errorf("subscribe to %s: %w", objectID, err)
+		}
+		subs = append(subs, covSubscription{objectID: objectID, subID: subID})
+		if verbose {
+			fmt.Fprintf(os.Stderr, "Subscribed to %s (subscription ID %d)\n", objectID, subID)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Listening for COV notifications of device %d, press Ctrl+C to stop\n", deviceID)
+
+	// Without a lifetime there is nothing to renew
+	if covLifetime == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Duration(covLifetime) * time.Second / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, sub := range subs {
+				renewCtx, renewCancel := context.WithTimeout(ctx, operationDeadline(1))
+				err := client.RenewCOV(renewCtx, deviceID, sub.objectID, sub.subID, subOpts...)
+				renewCancel()
+				if err != nil && ctx.Err() == nil {
+					fmt.Fprintf(os.Stderr, "[%s] Failed to renew %s: %v\n", time.Now().Format("15:04:05.000"), sub.objectID, err)
+				} else if err == nil && verbose {
+					fmt.Fprintf(os.Stderr, "[%s] Renewed %s\n", time.Now().Format("15:04:05.000"), sub.objectID)
+				}
+			}
+		}
+	}
+}
+
+// COVRecord is a notified value printed by cov with -o json
+type COVRecord struct {
+	Time     time.Time   `json:"time"`
+	Device   uint32      `json:"device"`
+	Object   string      `json:"object"`
+	Property string      `json:"property"`
+	Value    interface{} `json:"value"`
+}
+
+// covPrinter prints the values of COV notifications, which arrive on the
+// receive goroutine of the client
+type covPrinter struct {
+	mu     sync.Mutex
+	texts  map[bacnet.ObjectIdentifier]*bacnet.StateTexts
+	csv    *csv.Writer
+	header bool
+
+	// lines encodes the values of -o influx
+	lines *bacnet.LineProtocolEncoder
+}
+
+func newCOVPrinter(texts map[bacnet.ObjectIdentifier]*bacnet.StateTexts) *covPrinter {
+	return &covPrinter{texts: texts, csv: csv.NewWriter(os.Stdout)}
+}
+
+func (p *covPrinter) notify(devID uint32, objectID bacnet.ObjectIdentifier, values []bacnet.PropertyValue) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for _, pv := range values {
+		switch outputFmt {
+		case "json", "yaml", "template":
+			err := encodeOutputRecord(os.Stdout, COVRecord{
+				Time:     now,
+				Device:   devID,
+				Object:   objectID.String(),
+				Property: pv.PropertyID.String(),
+				Value:    formatValueForDump(pv.Value),
+			})
+			if err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		case "influx":
+			if err := p.lines.Encode(devID, pv, now); err != nil {
+				fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+			}
+		case "csv":
+			if !p.header {
+				p.csv.Write([]string{"time", "device", "object", "property", "value"})
+				p.header = true
+			}
+			p.csv.Write([]string{now.Format(time.RFC3339Nano), fmt.Sprint(devID), objectID.String(),
+				pv.PropertyID.String(), formatValue(pv.Value)})
+			p.csv.Flush()
+		default:
+			value := formatValue(pv.Value)
+			if pv.PropertyID == bacnet.PropertyPresentValue {
+				value = formatDisplayValue(pv.Value, p.texts[objectID])
+			}
+			fmt.Printf("[%s] %s.%s = %s\n", now.Format("15:04:05.000"), objectID.String(), pv.PropertyID.String(), value)
+		}
+	}
+}
@@ -0,0 +1,238 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	cronAuditFile string
+	cronList      bool
+)
+
+var cronCmd = &cobra.Command{
+	Use:   "cron",
+	Short: "Run scheduled writes from the config file",
+	Long: `Cron runs the write jobs listed under "cron" in the config file and keeps
+running until interrupted. Every write is logged as an audit record.
+
+Config example:
+
+  cron:
+    - name: occupancy-on
+      schedule: "0 6 * * mon-fri"
+      device: 1234
+      object: binary-value:1
+      value: active
+      priority: 12
+    - name: occupancy-off
+      schedule: "0 19 * * mon-fri"
+      device: 1234
+      object: binary-value:1
+      value: inactive
+      priority: 12
+
+Examples:
+  # Run the jobs, appending audit records to a file
+  edgeo-bacnet cron --config site.yaml --audit audit.jsonl
+
+  # Show the next activation of each job
+  edgeo-bacnet cron --config site.yaml --list`,
+
+	RunE: runCron,
+}
+
+func init() {
+	cronCmd.Flags().StringVar(&cronAuditFile, "audit", "", "Append audit records as JSON lines to this file")
+	cronCmd.Flags().BoolVar(&cronList, "list", false, "List jobs and their next activation, then exit")
+}
+
+// cronJobConfig is a cron job entry in the config file
+type cronJobConfig struct {
+	Name     string `mapstructure:"name"`
+	Schedule string `mapstructure:"schedule"`
+	Device   uint32 `mapstructure:"device"`
+	Object   string `mapstructure:"object"`
+	Property string `mapstructure:"property"`
+	Value    string `mapstructure:"value"`
+	Priority uint8  `mapstructure:"priority"`
+}
+
+// cronAuditRecord is the JSON form of an audit record
+type cronAuditRecord struct {
+	Time       time.Time `json:"time"`
+	Job        string    `json:"job"`
+	DeviceID   uint32    `json:"device_id"`
+	Object     string    `json:"object"`
+	Property   string    `json:"property"`
+	Value      string    `json:"value"`
+	Priority   uint8     `json:"priority,omitempty"`
+	DurationMs int64     `json:"duration_ms"`
+	Error      string    `json:"error,omitempty"`
+}
+
+func runCron(cmd *cobra.Command, args []string) error {
+	var configs []cronJobConfig
+	if err := viper.UnmarshalKey("cron", &configs); err != nil {
+		return fmt.Errorf("parse cron config: %w", err)
+	}
+	if len(configs) == 0 {
+		return fmt.Errorf("no cron jobs configured")
+	}
+
+	jobs := make([]bacnet.CronJob, 0, len(configs))
+	for i, cfg := range configs {
+		job, err := cronJobFromConfig(cfg)
+		if err != nil {
+			return fmt.Errorf("cron job %d: %w", i+1, err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	var schedOpts []bacnet.SchedulerOption
+	if cronAuditFile != "" {
+		f, err := os.OpenFile(cronAuditFile, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			return fmt.Errorf("open audit file: %w", err)
+		}
+		defer f.Close()
+
+		encoder := json.NewEncoder(f)
+		schedOpts = append(schedOpts, bacnet.WithAuditHandler(func(a bacnet.CronAudit) {
+			record := cronAuditRecord{
+				Time:       a.Time,
+				Job:        a.Job.Name,
+				DeviceID:   a.Job.DeviceID,
+				Object:     a.Job.ObjectID.String(),
+				Property:   a.Job.PropertyID.String(),
+				Value:      formatValue(a.Job.Value),
+				Priority:   a.Job.Priority,
+				DurationMs: a.Duration.Milliseconds(),
+			}
+			if a.Err != nil {
+				record.Error = a.Err.Error()
+			}
+			encoder.Encode(record)
+		}))
+	}
+
+	scheduler := bacnet.NewScheduler(client, schedOpts...)
+	for _, job := range jobs {
+		if err := scheduler.Add(job); err != nil {
+			return err
+		}
+	}
+
+	if cronList {
+		next := scheduler.Next()
+		headers := []string{"JOB", "SCHEDULE", "DEVICE", "OBJECT", "VALUE", "NEXT"}
+		rows := make([][]string, 0, len(jobs))
+		for _, job := range jobs {
+			rows = append(rows, []string{
+				job.Name,
+				job.Schedule,
+				fmt.Sprintf("%d", job.DeviceID),
+				job.ObjectID.String() + "." + job.PropertyID.String(),
+				formatValue(job.Value),
+				next[job.Name].Format("2006-01-02 15:04 Mon"),
+			})
+		}
+		sort.Slice(rows, func(i, j int) bool { return rows[i][5] < rows[j][5] })
+		NewFormatter(outputFmt).PrintTable(headers, rows)
+		return nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	// Handle interrupt signal
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		fmt.Fprintln(os.Stderr, "\nStopping scheduler...")
+		cancel()
+	}()
+
+	fmt.Fprintf(os.Stderr, "Running %d scheduled jobs (Ctrl+C to stop)\n", len(jobs))
+
+	if err := scheduler.Run(ctx); err != nil && err != context.Canceled {
+		return err
+	}
+	return nil
+}
+
+func cronJobFromConfig(cfg cronJobConfig) (bacnet.CronJob, error) {
+	if cfg.Device == 0 {
+		return bacnet.CronJob{}, fmt.Errorf("device is required")
+	}
+
+	objectID, err := parseObjectIdentifier(cfg.Object)
+	if err != nil {
+		return bacnet.CronJob{}, fmt.Errorf("invalid object: %w", err)
+	}
+
+	property := cfg.Property
+	if property == "" {
+		property = "present-value"
+	}
+	propID, err := parsePropertyIdentifier(property)
+	if err != nil {
+		return bacnet.CronJob{}, fmt.Errorf("invalid property: %w", err)
+	}
+
+	value, err := parseValue(cfg.Value)
+	if err != nil {
+		return bacnet.CronJob{}, fmt.Errorf("invalid value: %w", err)
+	}
+
+	name := cfg.Name
+	if name == "" {
+		name = fmt.Sprintf("%d/%s", cfg.Device, objectID.String())
+	}
+
+	return bacnet.CronJob{
+		Name:       name,
+		Schedule:   cfg.Schedule,
+		DeviceID:   cfg.Device,
+		ObjectID:   objectID,
+		PropertyID: propID,
+		Value:      value,
+		Priority:   cfg.Priority,
+	}, nil
+}
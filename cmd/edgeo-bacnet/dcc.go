@@ -0,0 +1,129 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	dccDuration time.Duration
+	dccPassword string
+	dccYes      bool
+)
+
+var dccCmd = &cobra.Command{
+	Use:   "dcc <enable|disable|disable-initiation>",
+	Short: "Enable or disable the communications of a device",
+	Long: `Dcc sends a DeviceCommunicationControl request. A disabled device only
+answers DeviceCommunicationControl and ReinitializeDevice requests; with
+disable-initiation, it still answers requests but initiates none, such as
+I-Am or event notifications. The device enables itself again when the
+--duration expires, rounded up to minutes; without it, until enabled.
+
+Disabling asks for a confirmation unless --yes is given.
+
+Examples:
+  # Silence a device for 30 minutes
+  edgeo-bacnet dcc -d 1234 disable --duration 30m --password secret
+
+  # Enable it again
+  edgeo-bacnet dcc -d 1234 enable --password secret`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"enable", "disable", "disable-initiation"},
+
+	// A cancelled confirmation is not a usage error
+	SilenceUsage: true,
+
+	RunE: runDCC,
+}
+
+func init() {
+	dccCmd.Flags().DurationVar(&dccDuration, "duration", 0, "Time before the device enables itself again (default: until enabled)")
+	dccCmd.Flags().StringVar(&dccPassword, "password", "", "Password of the device")
+	dccCmd.Flags().BoolVarP(&dccYes, "yes", "y", false, "Do not ask for a confirmation")
+}
+
+func runDCC(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	var state bacnet.EnableDisable
+	switch strings.ToLower(args[0]) {
+	case "enable":
+		state = bacnet.CommunicationEnable
+	case "disable":
+		state = bacnet.CommunicationDisable
+	case "disable-initiation":
+		state = bacnet.CommunicationDisableInitiation
+	default:
+		return fmt.Errorf("unknown state %q (expected enable, disable or disable-initiation)", args[0])
+	}
+
+	if state != bacnet.CommunicationEnable && !dccYes {
+		period := "until enabled"
+		if dccDuration > 0 {
+			period = "for " + dccDuration.String()
+		}
+		if !confirm(fmt.Sprintf("Send %s to device %d %s?", state, deviceID, period)) {
+			return fmt.Errorf("cancelled")
+		}
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(1))
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.DeviceCommunicationControl(ctx, deviceID, state, dccDuration, dccPassword); err != nil {
+		return fmt.Errorf("device communication control: %w", err)
+	}
+
+	fmt.Printf("Device %d: %s\n", deviceID, state)
+	return nil
+}
+
+// confirm asks a yes/no question on the terminal and returns true for yes
+func confirm(question string) bool {
+	fmt.Fprintf(os.Stderr, "%s [y/N] ", question)
+	answer, err := bufio.NewReader(os.Stdin).ReadString('\n')
+	if err != nil {
+		return false
+	}
+	switch strings.ToLower(strings.TrimSpace(answer)) {
+	case "y", "yes":
+		return true
+	default:
+		return false
+	}
+}
@@ -17,9 +17,13 @@ package main
 import (
 	"context"
 	"encoding/csv"
-	"encoding/json"
 	"fmt"
+	"math"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -32,6 +36,8 @@ var (
 	dumpProperties []string
 	dumpObjects    []string
 	dumpAll        bool
+	dumpWorkers    int
+	dumpResume     bool
 )
 
 var dumpCmd = &cobra.Command{
@@ -52,7 +58,10 @@ Examples:
   edgeo-bacnet dump -d 1234 --objects analog-input,analog-output
 
   # Dump specific properties
-  edgeo-bacnet dump -d 1234 --props present-value,object-name,description`,
+  edgeo-bacnet dump -d 1234 --props present-value,object-name,description
+
+  # Finish an interrupted dump, reading only the missing objects
+  edgeo-bacnet dump -d 1234 -f device_backup.json -o json --resume`,
 
 	RunE: runDump,
 }
@@ -61,34 +70,35 @@ func init() {
 	dumpCmd.Flags().StringVarP(&dumpFile, "file", "f", "", "Output file (default: stdout)")
 	dumpCmd.Flags().StringSliceVar(&dumpProperties, "props", []string{"present-value", "object-name", "description", "units", "status-flags"}, "Properties to read")
 	dumpCmd.Flags().StringSliceVar(&dumpObjects, "objects", nil, "Object types to include (default: all)")
-	dumpCmd.Flags().BoolVar(&dumpAll, "all", false, "Dump all properties (may be slow)")
+	dumpCmd.Flags().BoolVar(&dumpAll, "all", false, "Dump the standard properties of each object type")
+	dumpCmd.Flags().IntVar(&dumpWorkers, "concurrency", 4, "Objects read at the same time")
+	dumpCmd.Flags().BoolVar(&dumpResume, "resume", false, "Keep the objects of a partial JSON dump in the --file and read only the others")
 }
 
-type DumpObject struct {
-	ObjectID   string                 `json:"object_id"`
-	ObjectType string                 `json:"object_type"`
-	Instance   uint32                 `json:"instance"`
-	Properties map[string]interface{} `json:"properties"`
-}
+// The dump is a snapshot that the compare command reads back
+type DumpObject = bacnet.ObjectSnapshot
 
-type DumpResult struct {
-	DeviceID   uint32       `json:"device_id"`
-	Timestamp  time.Time    `json:"timestamp"`
-	Objects    []DumpObject `json:"objects"`
-}
+type DumpResult = bacnet.DeviceSnapshot
 
 func runDump(cmd *cobra.Command, args []string) error {
 	if deviceID == 0 {
 		return fmt.Errorf("device ID is required (-d or --device)")
 	}
+	if dumpWorkers < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	if dumpResume && (dumpFile == "" || outputFmt != "json") {
+		return fmt.Errorf("--resume requires a JSON dump: -f <file> -o json")
+	}
 
 	client, err := createClient()
 	if err != nil {
 		return fmt.Errorf("create client: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Minute)
-	defer cancel()
+	// An interrupted dump still writes the objects read so far
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
 
 	if err := client.Connect(ctx); err != nil {
 		return fmt.Errorf("connect: %w", err)
@@ -121,69 +131,67 @@ func runDump(cmd *cobra.Command, args []string) error {
 		fmt.Fprintf(os.Stderr, "Filtered to %d objects\n", len(objects))
 	}
 
-	// Parse properties to read
-	props := make([]bacnet.PropertyIdentifier, 0, len(dumpProperties))
-	if dumpAll {
-		// Read common properties
-		props = []bacnet.PropertyIdentifier{
-			bacnet.PropertyObjectIdentifier,
-			bacnet.PropertyObjectName,
-			bacnet.PropertyObjectType,
-			bacnet.PropertyPresentValue,
-			bacnet.PropertyDescription,
-			bacnet.PropertyStatusFlags,
-			bacnet.PropertyEventState,
-			bacnet.PropertyReliability,
-			bacnet.PropertyOutOfService,
-			bacnet.PropertyUnits,
-			bacnet.PropertyPriorityArray,
-			bacnet.PropertyRelinquishDefault,
-			bacnet.PropertyCOVIncrement,
-			bacnet.PropertyHighLimit,
-			bacnet.PropertyLowLimit,
-		}
-	} else {
+	// Parse properties to read. With --all, each object type has its own.
+	propsFor := func(obj bacnet.ObjectIdentifier) []bacnet.PropertyIdentifier {
+		return dumpAllProperties(obj.Type)
+	}
+	if !dumpAll {
+		props := make([]bacnet.PropertyIdentifier, 0, len(dumpProperties))
 		for _, propStr := range dumpProperties {
 			prop, ok := bacnet.ParsePropertyIdentifier(propStr)
 			if ok {
 				props = append(props, prop)
 			}
 		}
+		propsFor = func(bacnet.ObjectIdentifier) []bacnet.PropertyIdentifier { return props }
 	}
 
-	// Read all objects
+	// Objects of a partial dump are kept
 	result := DumpResult{
 		DeviceID:  deviceID,
 		Timestamp: time.Now(),
 		Objects:   make([]DumpObject, 0, len(objects)),
 	}
-
-	for i, obj := range objects {
-		fmt.Fprintf(os.Stderr, "\rReading object %d/%d: %s", i+1, len(objects), obj.String())
-
-		dumpObj := DumpObject{
-			ObjectID:   obj.String(),
-			ObjectType: obj.Type.String(),
-			Instance:   obj.Instance,
-			Properties: make(map[string]interface{}),
+	done := make(map[string]DumpObject)
+	if dumpResume {
+		partial, err := bacnet.ReadSnapshotFile(dumpFile)
+		if err != nil && !os.IsNotExist(err) {
+			return fmt.Errorf("read partial dump: %w", err)
 		}
-
-		for _, prop := range props {
-			readCtx, readCancel := context.WithTimeout(ctx, timeout)
-			value, err := client.ReadProperty(readCtx, deviceID, obj, prop)
-			readCancel()
-
-			if err != nil {
-				continue // Skip properties that fail
+		if partial != nil {
+			if partial.DeviceID != deviceID {
+				return fmt.Errorf("%s is a dump of device %d", dumpFile, partial.DeviceID)
 			}
-
-			dumpObj.Properties[prop.String()] = formatValueForDump(value)
+			for _, obj := range partial.Objects {
+				done[obj.ObjectID] = obj
+			}
+			fmt.Fprintf(os.Stderr, "Resuming: %d objects already dumped\n", len(done))
 		}
+	}
 
-		result.Objects = append(result.Objects, dumpObj)
+	dumped, failed := readDumpObjects(ctx, client, objects, propsFor, done)
+	for _, obj := range objects {
+		if dumpObj, ok := dumped[obj.String()]; ok {
+			result.Objects = append(result.Objects, dumpObj)
+		}
 	}
 
-	fmt.Fprintln(os.Stderr, "\nDump complete")
+	// A partial dump goes to the file, to be finished with --resume
+	var incomplete error
+	if missing := len(objects) - len(result.Objects); missing > 0 {
+		incomplete = fmt.Errorf("dump incomplete: %d of %d objects not read", missing, len(objects))
+		if ctx.Err() == nil && failed != nil {
+			incomplete = fmt.Errorf("%w, last error: %v", incomplete, failed)
+		}
+		if dumpFile == "" {
+			return incomplete
+		}
+		if outputFmt == "json" {
+			fmt.Fprintln(os.Stderr, "Rerun with --resume to read the missing objects")
+		}
+	} else {
+		fmt.Fprintln(os.Stderr, "Dump complete")
+	}
 
 	// Output results
 	var out *os.File
@@ -198,30 +206,205 @@ func runDump(cmd *cobra.Command, args []string) error {
 	}
 
 	switch outputFmt {
-	case "json":
-		return outputDumpJSON(out, result)
+	case "json", "yaml":
+		err = outputDumpJSON(out, result)
 	case "csv":
-		return outputDumpCSV(out, result)
+		err = outputDumpCSV(out, result)
+	case "template":
+		for _, obj := range result.Objects {
+			if err = encodeTemplate(out, obj); err != nil {
+				break
+			}
+		}
 	default:
-		return outputDumpTable(out, result)
+		err = outputDumpTable(out, result)
 	}
+	if err != nil {
+		return err
+	}
+	return incomplete
 }
 
+// readDumpObjects reads the properties propsFor returns for each object
+// not already done, dumpWorkers objects at a time, each with
+// ReadPropertyMultiple where the device supports it. It returns the objects read, done included, keyed by
+// object identifier, and the last error of the objects that could not be
+// read.
+func readDumpObjects(ctx context.Context, client *bacnet.Client, objects []bacnet.ObjectIdentifier, propsFor func(bacnet.ObjectIdentifier) []bacnet.PropertyIdentifier, done map[string]DumpObject) (map[string]DumpObject, error) {
+	dumped := make(map[string]DumpObject, len(objects))
+	var pending []bacnet.ObjectIdentifier
+	for _, obj := range objects {
+		if dumpObj, ok := done[obj.String()]; ok {
+			dumped[obj.String()] = dumpObj
+		} else {
+			pending = append(pending, obj)
+		}
+	}
+
+	dev := client.Device(deviceID)
+	progress := newDumpProgress(len(pending))
+	defer progress.done()
+
+	var (
+		mu      sync.Mutex
+		wg      sync.WaitGroup
+		lastErr error
+	)
+	work := make(chan bacnet.ObjectIdentifier)
+	for i := 0; i < dumpWorkers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for obj := range work {
+				dumpObj, err := readDumpObject(ctx, dev, obj, propsFor(obj))
+				mu.Lock()
+				if err != nil {
+					lastErr = err
+				} else {
+					dumped[obj.String()] = dumpObj
+				}
+				progress.update(obj)
+				mu.Unlock()
+			}
+		}()
+	}
+
+feed:
+	for _, obj := range pending {
+		select {
+		case work <- obj:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	return dumped, lastErr
+}
+
+// dumpAllProperties returns the properties of an object type read by
+// --all: its standard properties, but for the log buffers of the logs,
+// which ReadProperty cannot read
+func dumpAllProperties(objectType bacnet.ObjectType) []bacnet.PropertyIdentifier {
+	standard, _ := bacnet.StandardProperties(objectType)
+	var props []bacnet.PropertyIdentifier
+	for _, prop := range standard.All() {
+		if prop != bacnet.PropertyLogBuffer {
+			props = append(props, prop)
+		}
+	}
+	return props
+}
+
+// readDumpObject reads the properties of one object. Properties the object
+// lacks are left out; an error means the object could not be read at all.
+func readDumpObject(ctx context.Context, dev *bacnet.DeviceHandle, obj bacnet.ObjectIdentifier, props []bacnet.PropertyIdentifier) (DumpObject, error) {
+	requests := make([]bacnet.ReadPropertyRequest, len(props))
+	for i, prop := range props {
+		requests[i] = bacnet.ReadPropertyRequest{ObjectID: obj, PropertyID: prop}
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, operationDeadline(len(props)))
+	results, err := dev.ReadMultiple(readCtx, requests)
+	cancel()
+	if err != nil {
+		return DumpObject{}, fmt.Errorf("%s: %w", obj, err)
+	}
+
+	dumpObj := DumpObject{
+		ObjectID:   obj.String(),
+		ObjectType: obj.Type.String(),
+		Instance:   obj.Instance,
+		Properties: make(map[string]interface{}),
+	}
+	for _, r := range results {
+		if r.Err != nil {
+			continue // Skip properties that fail
+		}
+		dumpObj.Properties[r.PropertyID.String()] = formatValueForDump(r.Value)
+	}
+	return dumpObj, nil
+}
+
+// dumpProgress prints the progress of a dump on stderr: a bar with the
+// estimated time left, redrawn in place on a terminal
+type dumpProgress struct {
+	terminal bool
+	total    int
+	count    int
+	start    time.Time
+}
+
+func newDumpProgress(total int) *dumpProgress {
+	p := &dumpProgress{total: total, start: time.Now()}
+	if info, err := os.Stderr.Stat(); err == nil {
+		p.terminal = info.Mode()&os.ModeCharDevice != 0
+	}
+	return p
+}
+
+// update counts one more object read, or failed
+func (p *dumpProgress) update(obj bacnet.ObjectIdentifier) {
+	p.count++
+	if !p.terminal || p.total == 0 {
+		return
+	}
+
+	filled := p.count * progressBarWidth / p.total
+	bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+	line := fmt.Sprintf("[%s] %3d%% %d/%d", bar, p.count*100/p.total, p.count, p.total)
+	if p.count < p.total {
+		elapsed := time.Since(p.start)
+		left := elapsed / time.Duration(p.count) * time.Duration(p.total-p.count)
+		line += fmt.Sprintf(" ETA %v", left.Round(time.Second))
+	}
+	fmt.Fprintf(os.Stderr, "\r%s %s\033[K", line, obj)
+}
+
+func (p *dumpProgress) done() {
+	if p.terminal && p.count > 0 {
+		fmt.Fprintln(os.Stderr)
+	}
+	fmt.Fprintf(os.Stderr, "Read %d objects in %v\n", p.count, time.Since(p.start).Round(time.Millisecond))
+}
+
+// formatValueForDump converts a property value to one that encodes to
+// JSON: identifiers, bit strings and times become strings, and so do the
+// floats JSON cannot represent
 func formatValueForDump(value interface{}) interface{} {
 	switch v := value.(type) {
 	case bacnet.ObjectIdentifier:
 		return v.String()
+	case bacnet.BitString:
+		return v.String()
+	case bacnet.TimeOfDay:
+		return v.String()
 	case []byte:
 		return fmt.Sprintf("%x", v)
+	case float32:
+		if math.IsNaN(float64(v)) || math.IsInf(float64(v), 0) {
+			return formatValue(v)
+		}
+		return v
+	case float64:
+		if math.IsNaN(v) || math.IsInf(v, 0) {
+			return formatValue(v)
+		}
+		return v
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = formatValueForDump(item)
+		}
+		return values
 	default:
 		return v
 	}
 }
 
 func outputDumpJSON(out *os.File, result DumpResult) error {
-	encoder := json.NewEncoder(out)
-	encoder.SetIndent("", "  ")
-	return encoder.Encode(result)
+	return encodeOutput(out, result)
 }
 
 func outputDumpCSV(out *os.File, result DumpResult) error {
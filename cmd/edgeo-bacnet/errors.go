@@ -0,0 +1,161 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+// Exit codes of the CLI, for scripts to branch on the kind of failure
+const (
+	exitError          = 1
+	exitUsage          = 2
+	exitTimeout        = 3
+	exitDeviceNotFound = 4
+	exitBACnetError    = 5
+	exitDrift          = 6
+)
+
+// usageError is an error of the flags, arguments or configuration of a
+// command, reported before it runs
+type usageError struct {
+	err error
+}
+
+func (e *usageError) Error() string { return e.err.Error() }
+func (e *usageError) Unwrap() error { return e.err }
+
+// asUsage marks err, if any, as a usage error
+func asUsage(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &usageError{err: err}
+}
+
+// markUsageErrors marks the flag and argument errors of cmd and the
+// commands below it as usage errors
+func markUsageErrors(cmd *cobra.Command) {
+	if !cmd.HasParent() {
+		cmd.SetFlagErrorFunc(func(cmd *cobra.Command, err error) error {
+			silenceJSONErrors(cmd)
+			return asUsage(err)
+		})
+	}
+
+	if args := cmd.Args; args != nil {
+		cmd.Args = func(cmd *cobra.Command, a []string) error {
+			return asUsage(args(cmd, a))
+		}
+	}
+
+	for _, sub := range cmd.Commands() {
+		markUsageErrors(sub)
+	}
+}
+
+// silenceJSONErrors leaves the errors of JSON output to main, which reports
+// them as a single envelope without the usage
+func silenceJSONErrors(cmd *cobra.Command) {
+	if outputFmt == "json" {
+		cmd.Root().SilenceErrors = true
+		cmd.Root().SilenceUsage = true
+	}
+}
+
+// markLookupError marks err as a usage error if it is the unknown command
+// error cobra returns while looking up the command of args, before any
+// hook of the command can mark it
+func markLookupError(root *cobra.Command, args []string, err error) error {
+	if _, _, findErr := root.Find(args); findErr != nil {
+		return asUsage(err)
+	}
+	return err
+}
+
+// ErrorEnvelope is the error of a failed command with -o json
+type ErrorEnvelope struct {
+	Error ErrorDetail `json:"error"`
+}
+
+// ErrorDetail describes an error: its type, which selects the exit code,
+// and the BACnet error class and code, or reject or abort reason
+type ErrorDetail struct {
+	Type     string `json:"type"`
+	Message  string `json:"message"`
+	Service  string `json:"service,omitempty"`
+	Class    string `json:"class,omitempty"`
+	Code     string `json:"code,omitempty"`
+	Reason   string `json:"reason,omitempty"`
+	ExitCode int    `json:"exit_code"`
+}
+
+// describeError classifies the error of a command. Only the devices that do
+// not answer or that a router reports unknown are not found; an unknown
+// object is an error of the device.
+func describeError(err error) ErrorDetail {
+	d := ErrorDetail{Type: "error", Message: err.Error(), ExitCode: exitError}
+
+	var bacnetErr *bacnet.BACnetError
+	var rejectErr *bacnet.RejectError
+	var abortErr *bacnet.AbortError
+	var usageErr *usageError
+	switch {
+	case errors.As(err, &usageErr):
+		d.Type, d.ExitCode = "usage", exitUsage
+	case bacnet.IsTimeout(err) || errors.Is(err, context.DeadlineExceeded):
+		d.Type, d.ExitCode = "timeout", exitTimeout
+	case errors.Is(err, errDrift):
+		d.Type, d.ExitCode = "drift", exitDrift
+	case errors.Is(err, bacnet.ErrDeviceNotFound):
+		d.Type, d.ExitCode = "device-not-found", exitDeviceNotFound
+	case errors.As(err, &bacnetErr):
+		d.Type, d.ExitCode = "bacnet-error", exitBACnetError
+		if bacnetErr.Code == bacnet.ErrorCodeUnknownDevice {
+			d.Type, d.ExitCode = "device-not-found", exitDeviceNotFound
+		}
+		d.Class = bacnetErr.Class.String()
+		d.Code = bacnetErr.Code.String()
+		if bacnetErr.HasService() {
+			d.Service = bacnetErr.Service.String()
+		}
+	case errors.As(err, &rejectErr):
+		d.Type, d.ExitCode = "reject", exitBACnetError
+		d.Reason = rejectErr.Reason.String()
+	case errors.As(err, &abortErr):
+		d.Type, d.ExitCode = "abort", exitBACnetError
+		d.Reason = abortErr.Reason.String()
+	}
+	return d
+}
+
+// reportError prints the error of a command as a JSON envelope with -o
+// json, cobra having printed it otherwise, and returns the exit code
+func reportError(err error) int {
+	d := describeError(err)
+	if outputFmt == "json" {
+		data, _ := json.Marshal(ErrorEnvelope{Error: d})
+		fmt.Fprintln(os.Stderr, string(data))
+	}
+	return d.ExitCode
+}
@@ -0,0 +1,246 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	fileObject    string
+	filePath      string
+	fileChunkSize int
+)
+
+var fileCmd = &cobra.Command{
+	Use:   "file",
+	Short: "Transfer the content of a File object",
+	Long: `File downloads or uploads the content of a File object with AtomicReadFile
+and AtomicWriteFile, for firmware and configuration transfers.
+
+Chunks are sized to the maximum APDU of the device and halved when the
+device aborts them as too large, unless --chunk-size is given. The records
+of a record-access file are transferred as the lines of the local file.`,
+}
+
+var fileGetCmd = &cobra.Command{
+	Use:   "get",
+	Short: "Download a File object to a local file",
+	Long: `Get downloads the content of a File object to --path.
+
+Examples:
+  edgeo-bacnet file get -d 1234 -O file:1 --path config.bin`,
+
+	// Failed transfers are not a usage error
+	SilenceUsage: true,
+
+	RunE: runFileGet,
+}
+
+var filePutCmd = &cobra.Command{
+	Use:   "put",
+	Short: "Upload a local file to a File object",
+	Long: `Put replaces the content of a File object with --path.
+
+Examples:
+  edgeo-bacnet file put -d 1234 -O file:2 --path firmware.bin`,
+
+	// Failed transfers are not a usage error
+	SilenceUsage: true,
+
+	RunE: runFilePut,
+}
+
+func init() {
+	for _, cmd := range []*cobra.Command{fileGetCmd, filePutCmd} {
+		cmd.Flags().StringVarP(&fileObject, "object", "O", "", "File object (e.g., file:1)")
+		cmd.Flags().StringVar(&filePath, "path", "", "Local file")
+		cmd.Flags().IntVar(&fileChunkSize, "chunk-size", 0, "Octets per request (default: sized to the device's max APDU)")
+		cmd.MarkFlagRequired("object")
+		cmd.MarkFlagRequired("path")
+		fileCmd.AddCommand(cmd)
+	}
+}
+
+func runFileGet(cmd *cobra.Command, args []string) error {
+	return runFileTransfer(func(ctx context.Context, client *bacnet.Client, fileID bacnet.ObjectIdentifier, method bacnet.FileAccessMethod, opts []bacnet.FileOption) (int, error) {
+		var data []byte
+		var err error
+		if method == bacnet.FileAccessRecord {
+			var records [][]byte
+			records, err = client.ReadFileRecords(ctx, deviceID, fileID.Instance, opts...)
+			for _, record := range records {
+				data = append(append(data, record...), '\n')
+			}
+		} else {
+			data, err = client.ReadFile(ctx, deviceID, fileID.Instance, opts...)
+		}
+		if err != nil {
+			return 0, err
+		}
+		if err := os.WriteFile(filePath, data, 0644); err != nil {
+			return 0, fmt.Errorf("write %s: %w", filePath, err)
+		}
+		return len(data), nil
+	})
+}
+
+func runFilePut(cmd *cobra.Command, args []string) error {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return fmt.Errorf("read %s: %w", filePath, err)
+	}
+
+	return runFileTransfer(func(ctx context.Context, client *bacnet.Client, fileID bacnet.ObjectIdentifier, method bacnet.FileAccessMethod, opts []bacnet.FileOption) (int, error) {
+		if method == bacnet.FileAccessRecord {
+			records := bytes.Split(bytes.TrimSuffix(data, []byte("\n")), []byte("\n"))
+			if len(data) == 0 {
+				records = nil
+			}
+			return len(data), client.WriteFileRecords(ctx, deviceID, fileID.Instance, records, opts...)
+		}
+		return len(data), client.WriteFile(ctx, deviceID, fileID.Instance, data, opts...)
+	})
+}
+
+// fileTransfer transfers a File object and returns the octets transferred
+type fileTransfer func(ctx context.Context, client *bacnet.Client, fileID bacnet.ObjectIdentifier, method bacnet.FileAccessMethod, opts []bacnet.FileOption) (int, error)
+
+// runFileTransfer connects, reads the access method of the File object and
+// runs the transfer with a progress bar
+func runFileTransfer(transfer fileTransfer) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	fileID, err := parseObjectIdentifier(fileObject)
+	if err != nil {
+		return fmt.Errorf("invalid object: %w", err)
+	}
+	if fileID.Type != bacnet.ObjectTypeFile {
+		return fmt.Errorf("%s is not a file object", fileID)
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	// Transfers take many requests; the deadline bounds the connection only
+	ctx := context.Background()
+	connectCtx, cancel := context.WithTimeout(ctx, operationDeadline(1))
+	defer cancel()
+
+	if err := client.Connect(connectCtx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	method, err := client.ReadFileAccessMethod(ctx, deviceID, fileID.Instance)
+	if err != nil {
+		return fmt.Errorf("read file-access-method: %w", err)
+	}
+
+	progress := newTransferProgress()
+	opts := []bacnet.FileOption{bacnet.WithFileProgress(func(done, total int64) {
+		progress.update(fileID, done, total)
+	})}
+	if fileChunkSize > 0 {
+		opts = append(opts, bacnet.WithFileChunkSize(fileChunkSize))
+	}
+
+	start := time.Now()
+	n, err := transfer(ctx, client, fileID, method, opts)
+	progress.done()
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s (%s): %d bytes in %v\n", fileID, method, n, time.Since(start).Round(time.Millisecond))
+	return nil
+}
+
+// progressBarWidth is the number of characters of a progress bar
+const progressBarWidth = 30
+
+// transferProgress prints the progress of file transfers on stderr: a bar
+// redrawn in place on a terminal, and one line per file otherwise
+type transferProgress struct {
+	terminal bool
+	file     bacnet.ObjectIdentifier
+	start    time.Time
+	line     string
+	active   bool
+}
+
+func newTransferProgress() *transferProgress {
+	p := &transferProgress{}
+	if info, err := os.Stderr.Stat(); err == nil {
+		p.terminal = info.Mode()&os.ModeCharDevice != 0
+	}
+	return p
+}
+
+func (p *transferProgress) update(file bacnet.ObjectIdentifier, done, total int64) {
+	if p.active && file != p.file {
+		p.done()
+	}
+	if !p.active {
+		p.file = file
+		p.start = time.Now()
+		p.active = true
+	}
+
+	var line string
+	if total > 0 {
+		filled := int(done * progressBarWidth / total)
+		if filled > progressBarWidth {
+			filled = progressBarWidth
+		}
+		bar := strings.Repeat("=", filled) + strings.Repeat(" ", progressBarWidth-filled)
+		line = fmt.Sprintf("%s [%s] %3d%% %d/%d", file, bar, done*100/total, done, total)
+	} else {
+		line = fmt.Sprintf("%s %d", file, done)
+	}
+	if elapsed := time.Since(p.start).Seconds(); elapsed > 0 {
+		line += fmt.Sprintf(" %.0f/s", float64(done)/elapsed)
+	}
+	p.line = line
+
+	if p.terminal {
+		fmt.Fprintf(os.Stderr, "\r%s\033[K", line)
+	}
+}
+
+func (p *transferProgress) done() {
+	if !p.active {
+		return
+	}
+	if p.terminal {
+		fmt.Fprintln(os.Stderr)
+	} else {
+		fmt.Fprintln(os.Stderr, p.line)
+	}
+	p.active = false
+}
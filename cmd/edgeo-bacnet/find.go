@@ -0,0 +1,188 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	findName      string
+	findObject    string
+	findWait      time.Duration
+	findLowLimit  uint32
+	findHighLimit uint32
+)
+
+var findCmd = &cobra.Command{
+	Use:   "find",
+	Short: "Find the devices holding an object, by name or identifier",
+	Long: `Find broadcasts a Who-Has for an object name or identifier and lists the
+devices that answer with an I-Have, to locate a point among many
+controllers. With -H the Who-Has is sent to that address only.
+
+It exits with an error when no device holds the object.
+
+Examples:
+  # Which controller has the point named "Zone Temp 3"?
+  edgeo-bacnet find --name "Zone Temp 3"
+
+  # Which devices have an analog-value:12, among instances 1000-1999?
+  edgeo-bacnet find --object av:12 --low 1000 --high 1999`,
+
+	// Objects not found are not a usage error
+	SilenceUsage: true,
+
+	RunE: runFind,
+}
+
+func init() {
+	findCmd.Flags().StringVar(&findName, "name", "", "Object name to look for")
+	findCmd.Flags().StringVar(&findObject, "object", "", "Object identifier to look for (e.g. analog-value:12)")
+	findCmd.Flags().DurationVar(&findWait, "wait", 3*time.Second, "Time to wait for answers")
+	findCmd.Flags().Uint32Var(&findLowLimit, "low", 0, "Low limit for device instance range (0 = no limit)")
+	findCmd.Flags().Uint32Var(&findHighLimit, "high", 0, "High limit for device instance range (0 = no limit)")
+
+	findCmd.MarkFlagsOneRequired("name", "object")
+	findCmd.MarkFlagsMutuallyExclusive("name", "object")
+}
+
+// FindResult is a device holding the object looked for
+type FindResult struct {
+	DeviceID uint32 `json:"device_id"`
+	Address  string `json:"address"`
+	Object   string `json:"object"`
+	Name     string `json:"name"`
+}
+
+func runFind(cmd *cobra.Command, args []string) error {
+	var objectID bacnet.ObjectIdentifier
+	if findObject != "" {
+		var err error
+		if objectID, err = parseObjectIdentifier(findObject); err != nil {
+			return err
+		}
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	opts := []bacnet.DiscoverOption{bacnet.WithDiscoveryTimeout(findWait)}
+	if findLowLimit > 0 || findHighLimit > 0 {
+		high := findHighLimit
+		if high == 0 {
+			high = 0x3FFFFF // Max device instance
+		}
+		opts = append(opts, bacnet.WithDeviceRange(findLowLimit, high))
+	}
+	if host != "" {
+		opts = append(opts, bacnet.WithDiscoveryAddress(fmt.Sprintf("%s:%d", host, port)))
+	}
+
+	what := fmt.Sprintf("%q", findName)
+	var holders []bacnet.ObjectHolder
+	if findObject != "" {
+		what = objectID.String()
+		holders, err = client.WhoHasObject(ctx, objectID, opts...)
+	} else {
+		holders, err = client.WhoHasName(ctx, findName, opts...)
+	}
+	// Interrupted, the devices found so far are listed
+	if err != nil && ctx.Err() == nil {
+		return fmt.Errorf("who-has: %w", err)
+	}
+
+	results := make([]FindResult, len(holders))
+	for i, h := range holders {
+		results[i] = FindResult{
+			DeviceID: h.DeviceID,
+			Address:  formatAddress(h.Address),
+			Object:   h.ObjectID.String(),
+			Name:     h.ObjectName,
+		}
+	}
+
+	switch outputFmt {
+	case "json", "yaml":
+		err = encodeOutput(os.Stdout, results)
+	case "csv":
+		err = outputFindCSV(results)
+	case "template":
+		for _, r := range results {
+			if err = encodeTemplate(os.Stdout, r); err != nil {
+				break
+			}
+		}
+	default:
+		outputFindTable(results, what)
+	}
+	if err != nil {
+		return err
+	}
+
+	if len(results) == 0 {
+		return fmt.Errorf("no device has %s", what)
+	}
+	return nil
+}
+
+func outputFindCSV(results []FindResult) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	writer.Write([]string{"device_id", "address", "object", "name"})
+	for _, r := range results {
+		writer.Write([]string{strconv.FormatUint(uint64(r.DeviceID), 10), r.Address, r.Object, r.Name})
+	}
+
+	return writer.Error()
+}
+
+func outputFindTable(results []FindResult, what string) {
+	f := NewFormatter(outputFmt)
+
+	if len(results) == 0 {
+		f.Printf("No device has %s\n", what)
+		return
+	}
+
+	headers := []string{"DEVICE", "ADDRESS", "OBJECT", "NAME"}
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		rows = append(rows, []string{strconv.FormatUint(uint64(r.DeviceID), 10), r.Address, r.Object, r.Name})
+	}
+	f.PrintTable(headers, rows)
+}
@@ -17,6 +17,7 @@ package main
 import (
 	"context"
 	"fmt"
+	"os"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -103,7 +104,7 @@ func runInfo(cmd *cobra.Command, args []string) error {
 
 	// Output results
 	switch outputFmt {
-	case "json":
+	case "json", "yaml":
 		return outputInfoJSON(info)
 	default:
 		return outputInfoTable(info)
@@ -142,27 +143,49 @@ func outputInfoTable(info map[string]interface{}) error {
 	return nil
 }
 
+// InfoOutput is the JSON output of info. Properties the device does not
+// support are left out.
+type InfoOutput struct {
+	DeviceID            uint32      `json:"device_id"`
+	Timestamp           string      `json:"timestamp"`
+	ObjectName          interface{} `json:"object_name,omitempty"`
+	Description         interface{} `json:"description,omitempty"`
+	Location            interface{} `json:"location,omitempty"`
+	VendorName          interface{} `json:"vendor_name,omitempty"`
+	VendorID            interface{} `json:"vendor_id,omitempty"`
+	ModelName           interface{} `json:"model_name,omitempty"`
+	FirmwareRevision    interface{} `json:"firmware_revision,omitempty"`
+	ApplicationSoftware interface{} `json:"application_software,omitempty"`
+	ProtocolVersion     interface{} `json:"protocol_version,omitempty"`
+	ProtocolRevision    interface{} `json:"protocol_revision,omitempty"`
+	SystemStatus        interface{} `json:"system_status,omitempty"`
+	MaxAPDULength       interface{} `json:"max_apdu_length,omitempty"`
+	Segmentation        interface{} `json:"segmentation,omitempty"`
+	ObjectCount         interface{} `json:"object_count,omitempty"`
+	DatabaseRevision    interface{} `json:"database_revision,omitempty"`
+}
+
 func outputInfoJSON(info map[string]interface{}) error {
-	fmt.Println("{")
-	fmt.Printf(`  "device_id": %d,`+"\n", deviceID)
-	fmt.Printf(`  "timestamp": "%s",`+"\n", time.Now().Format(time.RFC3339))
-
-	first := true
-	for key, val := range info {
-		if !first {
-			fmt.Println(",")
-		}
-		first = false
-
-		switch v := val.(type) {
-		case string:
-			fmt.Printf(`  "%s": "%s"`, key, v)
-		case bacnet.ObjectIdentifier:
-			fmt.Printf(`  "%s": "%s"`, key, v.String())
-		default:
-			fmt.Printf(`  "%s": %v`, key, v)
-		}
+	value := func(key string) interface{} {
+		return formatValueForDump(info[key])
 	}
-	fmt.Println("\n}")
-	return nil
+	return encodeOutput(os.Stdout, InfoOutput{
+		DeviceID:            deviceID,
+		Timestamp:           time.Now().Format(time.RFC3339),
+		ObjectName:          value("Object Name"),
+		Description:         value("Description"),
+		Location:            value("Location"),
+		VendorName:          value("Vendor Name"),
+		VendorID:            value("Vendor ID"),
+		ModelName:           value("Model Name"),
+		FirmwareRevision:    value("Firmware Revision"),
+		ApplicationSoftware: value("Application Software"),
+		ProtocolVersion:     value("Protocol Version"),
+		ProtocolRevision:    value("Protocol Revision"),
+		SystemStatus:        value("System Status"),
+		MaxAPDULength:       value("Max APDU Length"),
+		Segmentation:        value("Segmentation"),
+		ObjectCount:         value("Object Count"),
+		DatabaseRevision:    value("Database Revision"),
+	})
 }
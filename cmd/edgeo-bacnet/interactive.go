@@ -17,9 +17,12 @@ package main
 import (
 	"bufio"
 	"context"
+	"errors"
 	"fmt"
 	"os"
+	"sort"
 	"strings"
+	"sync"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -34,11 +37,19 @@ var interactiveCmd = &cobra.Command{
 
 Commands:
   scan                                  - Discover devices
-  use <device-id>                       - Select a device
+  use <device-id>...                    - Select one or more devices
+  devices                               - List known devices with names
   list                                  - List objects on current device
   read <object> <property>              - Read a property
   write <object> <property> <value>     - Write a property
+  sub <object>                          - Stream COV notifications
+  watch <object> [interval]             - Stream present value changes
   info                                  - Show device info
+  alias [name=<object>]                 - Define or list point aliases
+  unalias <name>                        - Remove a point alias
+  set <name> <value>                    - Set a variable, used as $name
+  assert <object> [property] <op> <val> - Check a value
+  source <file>                         - Run a file of commands
   metrics                               - Show client metrics
   help                                  - Show help
   exit                                  - Exit interactive mode
@@ -48,11 +59,72 @@ Examples:
   bacnet> use 1234
   bacnet[1234]> list
   bacnet[1234]> read ai:1 pv
-  bacnet[1234]> write ao:1 pv 75.5`,
+  bacnet[1234]> write ao:1 pv 75.5
+  bacnet[1234]> watch ai:1 5s
+  bacnet[1234]> alias zone=ai:3
+  bacnet[1234]> 5678:read zone pv`,
 
 	RunE: runInteractive,
 }
 
+// interactiveSession is the state of an interactive session or script: the
+// selected devices, the devices seen and their names, the point aliases and
+// the variables
+type interactiveSession struct {
+	client   *bacnet.Client
+	input    *bufio.Scanner
+	current  uint32
+	selected []uint32
+	known    map[uint32]bool
+	names    map[uint32]string
+	aliases  map[string]string
+	vars     map[string]string
+
+	// depth is the nesting of sourced files
+	depth int
+}
+
+func newInteractiveSession(client *bacnet.Client, input *bufio.Scanner) *interactiveSession {
+	return &interactiveSession{
+		client:  client,
+		input:   input,
+		known:   make(map[uint32]bool),
+		names:   make(map[uint32]string),
+		aliases: make(map[string]string),
+		vars:    make(map[string]string),
+	}
+}
+
+// use selects devices, the first of which becomes the current device
+func (s *interactiveSession) use(ids ...uint32) {
+	for _, id := range ids {
+		s.known[id] = true
+		found := false
+		for _, selected := range s.selected {
+			if selected == id {
+				found = true
+				break
+			}
+		}
+		if !found {
+			s.selected = append(s.selected, id)
+		}
+	}
+	s.current = ids[0]
+}
+
+// object resolves a point alias, returning other names as they are
+func (s *interactiveSession) object(name string) string {
+	if object, ok := s.aliases[name]; ok {
+		return object
+	}
+	return name
+}
+
+// interactiveCOVLifetime is the lifetime of the subscriptions of sub and
+// watch, which are renewed halfway through it
+const interactiveCOVLifetime = 300
+
 func runInteractive(cmd *cobra.Command, args []string) error {
 	client, err := createClient()
 	if err != nil {
@@ -71,12 +143,12 @@ func runInteractive(cmd *cobra.Command, args []string) error {
 	fmt.Println()
 
 	scanner := bufio.NewScanner(os.Stdin)
-	currentDevice := uint32(0)
+	session := newInteractiveSession(client, scanner)
 
 	for {
 		// Print prompt
-		if currentDevice > 0 {
-			fmt.Printf("bacnet[%d]> ", currentDevice)
+		if session.current > 0 {
+			fmt.Printf("bacnet[%d]> ", session.current)
 		} else {
 			fmt.Print("bacnet> ")
 		}
@@ -85,87 +157,214 @@ func runInteractive(cmd *cobra.Command, args []string) error {
 			break
 		}
 
-		line := strings.TrimSpace(scanner.Text())
-		if line == "" {
-			continue
+		err := session.exec(ctx, scanner.Text())
+		var message shellError
+		switch {
+		case err == nil:
+		case errors.Is(err, errExit):
+			fmt.Println("Goodbye!")
+			return nil
+		case errors.As(err, &message):
+			fmt.Println(message)
+		default:
+			fmt.Printf("Error: %v\n", err)
 		}
+	}
 
-		parts := strings.Fields(line)
-		command := strings.ToLower(parts[0])
+	return nil
+}
 
-		switch command {
-		case "exit", "quit", "q":
-			fmt.Println("Goodbye!")
-			return nil
+// shellError is a message of the shell itself, such as a usage line,
+// printed as it is
+type shellError string
 
-		case "help", "?":
-			printInteractiveHelp()
+func (e shellError) Error() string {
+	return string(e)
+}
 
-		case "scan":
-			runInteractiveScan(ctx, client)
+// errNoDevice is returned by the commands that need a device
+const errNoDevice = shellError("No device selected. Use 'use <device-id>' first.")
 
-		case "use":
-			if len(parts) < 2 {
-				fmt.Println("Usage: use <device-id>")
-				continue
-			}
-			var newDevice uint32
-			fmt.Sscanf(parts[1], "%d", &newDevice)
-			if newDevice > 0 {
-				currentDevice = newDevice
-				fmt.Printf("Selected device %d\n", currentDevice)
-			} else {
-				fmt.Println("Invalid device ID")
-			}
+// errExit ends a session
+var errExit = errors.New("exit")
 
-		case "list":
-			if currentDevice == 0 {
-				fmt.Println("No device selected. Use 'use <device-id>' first.")
-				continue
-			}
-			runInteractiveList(ctx, client, currentDevice)
+// exec runs one command line
+func (s *interactiveSession) exec(ctx context.Context, line string) error {
+	line, err := s.expand(line)
+	if err != nil {
+		return err
+	}
+	parts := strings.Fields(line)
+	if len(parts) == 0 {
+		return nil
+	}
+	command := strings.ToLower(parts[0])
+
+	// A device prefix, as in 1234:read or ahu1:read, targets a device for
+	// one command
+	currentDevice := s.current
+	if prefix, rest, ok := strings.Cut(command, ":"); ok && rest != "" {
+		id, err := resolveDevice(prefix)
+		if err != nil || id == 0 {
+			return shellError(fmt.Sprintf("Invalid device prefix: %s", prefix))
+		}
+		currentDevice = id
+		s.known[currentDevice] = true
+		command = rest
+	}
 
-		case "read":
-			if currentDevice == 0 {
-				fmt.Println("No device selected. Use 'use <device-id>' first.")
-				continue
-			}
-			if len(parts) < 2 {
-				fmt.Println("Usage: read <object> [property]")
-				continue
-			}
-			prop := "present-value"
-			if len(parts) >= 3 {
-				prop = parts[2]
-			}
-			runInteractiveRead(ctx, client, currentDevice, parts[1], prop)
+	switch command {
+	case "exit", "quit", "q":
+		return errExit
 
-		case "write":
-			if currentDevice == 0 {
-				fmt.Println("No device selected. Use 'use <device-id>' first.")
-				continue
-			}
-			if len(parts) < 4 {
-				fmt.Println("Usage: write <object> <property> <value>")
-				continue
+	case "help", "?":
+		printInteractiveHelp()
+
+	case "scan":
+		devices, err := runInteractiveScan(ctx, s.client)
+		if err != nil {
+			return err
+		}
+		for _, dev := range devices {
+			s.known[dev.ObjectID.Instance] = true
+		}
+
+	case "use":
+		if len(parts) < 2 {
+			return shellError("Usage: use <device-id>...")
+		}
+		ids := make([]uint32, 0, len(parts)-1)
+		for _, part := range parts[1:] {
+			id, err := resolveDevice(part)
+			if err != nil || id == 0 {
+				return shellError(fmt.Sprintf("Invalid device ID: %s", part))
 			}
-			runInteractiveWrite(ctx, client, currentDevice, parts[1], parts[2], strings.Join(parts[3:], " "))
+			ids = append(ids, id)
+		}
+		s.use(ids...)
+		if len(s.selected) > 1 {
+			fmt.Printf("Selected device %d (%d devices selected)\n", s.current, len(s.selected))
+		} else {
+			fmt.Printf("Selected device %d\n", s.current)
+		}
+
+	case "devices":
+		runInteractiveDevices(ctx, s.client, s)
 
-		case "info":
-			if currentDevice == 0 {
-				fmt.Println("No device selected. Use 'use <device-id>' first.")
-				continue
+	case "alias":
+		return runInteractiveAlias(s, parts[1:])
+
+	case "unalias":
+		if len(parts) < 2 {
+			return shellError("Usage: unalias <name>")
+		}
+		if _, ok := s.aliases[parts[1]]; !ok {
+			return shellError(fmt.Sprintf("No alias %s", parts[1]))
+		}
+		delete(s.aliases, parts[1])
+
+	case "list":
+		if currentDevice == 0 {
+			return errNoDevice
+		}
+		return runInteractiveList(ctx, s.client, currentDevice)
+
+	case "read":
+		if currentDevice == 0 {
+			return errNoDevice
+		}
+		if len(parts) < 2 {
+			return shellError("Usage: read <object> [property]")
+		}
+		prop := "present-value"
+		if len(parts) >= 3 {
+			prop = parts[2]
+		}
+		value, err := runInteractiveRead(ctx, s.client, currentDevice, s.object(parts[1]), prop)
+		if err != nil {
+			return err
+		}
+		s.vars["_"] = formatValue(value)
+
+	case "write":
+		if currentDevice == 0 {
+			return errNoDevice
+		}
+		if len(parts) < 4 {
+			return shellError("Usage: write <object> <property> <value>")
+		}
+		return runInteractiveWrite(ctx, s.client, currentDevice, s.object(parts[1]), parts[2], strings.Join(parts[3:], " "))
+
+	case "sub", "subscribe", "cov":
+		if currentDevice == 0 {
+			return errNoDevice
+		}
+		if len(parts) < 2 {
+			return shellError("Usage: sub <object>")
+		}
+		return runInteractiveStream(ctx, s.client, s.input, currentDevice, s.object(parts[1]), time.Second, true)
+
+	case "watch":
+		if currentDevice == 0 {
+			return errNoDevice
+		}
+		if len(parts) < 2 {
+			return shellError("Usage: watch <object> [interval]")
+		}
+		interval := time.Second
+		if len(parts) >= 3 {
+			d, err := time.ParseDuration(parts[2])
+			if err != nil || d <= 0 {
+				return shellError(fmt.Sprintf("Invalid interval: %s", parts[2]))
 			}
-			runInteractiveInfo(ctx, client, currentDevice)
+			interval = d
+		}
+		return runInteractiveStream(ctx, s.client, s.input, currentDevice, s.object(parts[1]), interval, false)
 
-		case "metrics":
-			runInteractiveMetrics(client)
+	case "info":
+		if currentDevice == 0 {
+			return errNoDevice
+		}
+		runInteractiveInfo(ctx, s.client, currentDevice)
 
-		default:
-			fmt.Printf("Unknown command: %s (type 'help' for available commands)\n", command)
+	case "metrics":
+		runInteractiveMetrics(s.client)
+
+	case "set":
+		return runInteractiveSet(s, parts[1:])
+
+	case "echo":
+		fmt.Println(strings.Join(parts[1:], " "))
+
+	case "sleep":
+		if len(parts) < 2 {
+			return shellError("Usage: sleep <duration>")
+		}
+		d, err := time.ParseDuration(parts[1])
+		if err != nil || d < 0 {
+			return shellError(fmt.Sprintf("Invalid duration: %s", parts[1]))
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(d):
 		}
-	}
 
+	case "assert":
+		if currentDevice == 0 {
+			return errNoDevice
+		}
+		return runInteractiveAssert(ctx, s, currentDevice, parts[1:])
+
+	case "source":
+		if len(parts) < 2 {
+			return shellError("Usage: source <file>")
+		}
+		return s.source(ctx, parts[1])
+
+	default:
+		return shellError(fmt.Sprintf("Unknown command: %s (type 'help' for available commands)", command))
+	}
 	return nil
 }
 
@@ -173,15 +372,34 @@ func printInteractiveHelp() {
 	fmt.Println(`
 Available commands:
   scan                              Discover BACnet devices on the network
-  use <device-id>                   Select a device to work with
+  use <device>...                   Select devices by ID or alias, the first becoming current
+  devices                           List the known devices and their names
   list                              List all objects on current device
   read <object> [property]          Read a property (default: present-value)
   write <object> <property> <value> Write a property value
+  sub <object>                      Stream the COV notifications of an object
+  watch <object> [interval]         Stream present value changes (default: 1s)
   info                              Show current device information
+  alias [name=<object>]             Define a point alias, or list them
+  unalias <name>                    Remove a point alias
+  set [<name> <value>]              Set a variable, used as $name, or list them
+  echo <text>                       Print a line
+  sleep <duration>                  Wait, e.g. 5s
+  assert <object> [property] <op> <value>
+                                    Check a value with ==, !=, <, <=, > or >=
+  source <file>                     Run a file of commands
   metrics                           Show client metrics
   help                              Show this help message
   exit                              Exit interactive mode
 
+sub and watch subscribe to COV notifications, or poll the present value
+at the interval when the device does not support them, until Enter is
+pressed.
+
+Prefix a command with a device ID to run it on that device without
+switching, e.g. 1234:read ai:1 pv. Aliases stand for objects wherever an
+object is expected, e.g. alias zone=ai:3 then read zone.
+
 Object format: <type>:<instance>
   Examples: analog-input:1, ai:1, binary-output:5, device:1234
 
@@ -190,11 +408,10 @@ Property shortcuts:
   name = object-name
   desc = description
   sf = status-flags
-  oos = out-of-service
-`)
+  oos = out-of-service`)
 }
 
-func runInteractiveScan(ctx context.Context, client *bacnet.Client) {
+func runInteractiveScan(ctx context.Context, client *bacnet.Client) ([]*bacnet.DeviceInfo, error) {
 	fmt.Println("Scanning for devices...")
 
 	scanCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
@@ -202,13 +419,12 @@ func runInteractiveScan(ctx context.Context, client *bacnet.Client) {
 
 	devices, err := client.WhoIs(scanCtx, bacnet.WithDiscoveryTimeout(3*time.Second))
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return nil, err
 	}
 
 	if len(devices) == 0 {
 		fmt.Println("No devices found")
-		return
+		return nil, nil
 	}
 
 	fmt.Printf("\nFound %d device(s):\n", len(devices))
@@ -220,16 +436,100 @@ func runInteractiveScan(ctx context.Context, client *bacnet.Client) {
 		)
 	}
 	fmt.Println()
+	return devices, nil
 }
 
-func runInteractiveList(ctx context.Context, client *bacnet.Client, devID uint32) {
+// runInteractiveDevices lists the devices scanned or used in the session,
+// reading the names not known yet
+func runInteractiveDevices(ctx context.Context, client *bacnet.Client, session *interactiveSession) {
+	if len(session.known) == 0 {
+		fmt.Println("No devices known. Use 'scan' or 'use <device-id>' first.")
+		return
+	}
+
+	ids := make([]uint32, 0, len(session.known))
+	for id := range session.known {
+		ids = append(ids, id)
+	}
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+	selected := make(map[uint32]bool, len(session.selected))
+	for _, id := range session.selected {
+		selected[id] = true
+	}
+
+	fmt.Println()
+	for _, id := range ids {
+		if _, ok := session.names[id]; !ok {
+			readCtx, cancel := context.WithTimeout(ctx, timeout)
+			name, err := client.ReadProperty(readCtx, id, bacnet.NewObjectIdentifier(bacnet.ObjectTypeDevice, id), bacnet.PropertyObjectName)
+			cancel()
+			if err == nil {
+				session.names[id] = formatValue(name)
+			}
+		}
+
+		marker := " "
+		switch {
+		case id == session.current:
+			marker = "*"
+		case selected[id]:
+			marker = "+"
+		}
+		address := "-"
+		if info, ok := client.GetDevice(id); ok {
+			address = formatAddress(info.Address)
+		}
+		name, ok := session.names[id]
+		if !ok {
+			name = "(unreachable)"
+		}
+		fmt.Printf("%s %-10d %-22s %s\n", marker, id, address, name)
+	}
+	fmt.Println()
+}
+
+// runInteractiveAlias defines the name=object aliases of the arguments, or
+// lists the aliases without arguments
+func runInteractiveAlias(session *interactiveSession, args []string) error {
+	if len(args) == 0 {
+		if len(session.aliases) == 0 {
+			fmt.Println("No aliases defined")
+			return nil
+		}
+		names := make([]string, 0, len(session.aliases))
+		for name := range session.aliases {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  %s = %s\n", name, session.aliases[name])
+		}
+		return nil
+	}
+
+	for _, arg := range args {
+		name, object, ok := strings.Cut(arg, "=")
+		if !ok || name == "" || strings.Contains(name, ":") {
+			return shellError("Usage: alias <name>=<object>")
+		}
+		objectID, err := parseObjectIdentifier(session.object(object))
+		if err != nil {
+			return err
+		}
+		session.aliases[name] = objectID.String()
+		fmt.Printf("%s = %s\n", name, objectID.String())
+	}
+	return nil
+}
+
+func runInteractiveList(ctx context.Context, client *bacnet.Client, devID uint32) error {
 	listCtx, cancel := context.WithTimeout(ctx, 30*time.Second)
 	defer cancel()
 
 	objects, err := client.GetObjectList(listCtx, devID)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
 	fmt.Printf("\nDevice %d has %d objects:\n", devID, len(objects))
@@ -247,19 +547,18 @@ func runInteractiveList(ctx context.Context, client *bacnet.Client, devID uint32
 		}
 	}
 	fmt.Println()
+	return nil
 }
 
-func runInteractiveRead(ctx context.Context, client *bacnet.Client, devID uint32, objStr, propStr string) {
+func runInteractiveRead(ctx context.Context, client *bacnet.Client, devID uint32, objStr, propStr string) (interface{}, error) {
 	objectID, err := parseObjectIdentifier(objStr)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return nil, err
 	}
 
 	propID, err := parsePropertyIdentifier(propStr)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return nil, err
 	}
 
 	readCtx, cancel := context.WithTimeout(ctx, timeout)
@@ -267,41 +566,182 @@ func runInteractiveRead(ctx context.Context, client *bacnet.Client, devID uint32
 
 	value, err := client.ReadProperty(readCtx, devID, objectID, propID)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return nil, err
 	}
 
-	fmt.Printf("%s.%s = %s\n", objectID.String(), propID.String(), formatValue(value))
+	texts := readStateTexts(readCtx, client, devID, objectID, propID)
+	fmt.Printf("%s.%s = %s\n", objectID.String(), propID.String(), formatDisplayValue(value, texts))
+	return value, nil
 }
 
-func runInteractiveWrite(ctx context.Context, client *bacnet.Client, devID uint32, objStr, propStr, valStr string) {
+func runInteractiveWrite(ctx context.Context, client *bacnet.Client, devID uint32, objStr, propStr, valStr string) error {
 	objectID, err := parseObjectIdentifier(objStr)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
 	propID, err := parsePropertyIdentifier(propStr)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
 	value, err := parseValue(valStr)
 	if err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
 	writeCtx, cancel := context.WithTimeout(ctx, timeout)
 	defer cancel()
 
 	if err := client.WriteProperty(writeCtx, devID, objectID, propID, value); err != nil {
-		fmt.Printf("Error: %v\n", err)
-		return
+		return err
 	}
 
 	fmt.Printf("OK: %s.%s = %s\n", objectID.String(), propID.String(), formatValue(value))
+	return nil
+}
+
+// runInteractiveStream prints the values of an object until Enter is
+// pressed: all the values of its COV notifications, or only its present
+// value. Devices that refuse the subscription are polled at the interval.
+func runInteractiveStream(ctx context.Context, client *bacnet.Client, input *bufio.Scanner, devID uint32, objStr string, interval time.Duration, all bool) error {
+	objectID, err := parseObjectIdentifier(objStr)
+	if err != nil {
+		return err
+	}
+
+	// State texts are read up front: the COV handler must not block on
+	// further requests
+	textCtx, textCancel := context.WithTimeout(ctx, timeout)
+	texts := readStateTexts(textCtx, client, devID, objectID, bacnet.PropertyPresentValue)
+	textCancel()
+
+	show := func(propID bacnet.PropertyIdentifier, value interface{}) {
+		display := formatValue(value)
+		if propID == bacnet.PropertyPresentValue {
+			display = formatDisplayValue(value, texts)
+		}
+		fmt.Printf("[%s] %s.%s = %s\n", time.Now().Format("15:04:05.000"), objectID.String(), propID.String(), display)
+	}
+
+	// The present value is printed when it changes, whether notified or
+	// polled
+	var mu sync.Mutex
+	var last interface{}
+	read := false
+	update := func(value interface{}) {
+		mu.Lock()
+		defer mu.Unlock()
+		if !read || !valuesEqual(last, value) {
+			show(bacnet.PropertyPresentValue, value)
+			last = value
+			read = true
+		}
+	}
+
+	handler := func(_ uint32, _ bacnet.ObjectIdentifier, values []bacnet.PropertyValue) {
+		for _, pv := range values {
+			switch {
+			case all:
+				show(pv.PropertyID, pv.Value)
+			case pv.PropertyID == bacnet.PropertyPresentValue:
+				update(pv.Value)
+			}
+		}
+	}
+
+	lifetime := bacnet.WithSubscriptionLifetime(interactiveCOVLifetime)
+	subCtx, subCancel := context.WithTimeout(ctx, operationDeadline(1))
+	subID, err := client.SubscribeCOV(subCtx, devID, objectID, handler, lifetime)
+	subCancel()
+	cov := err == nil
+	switch {
+	case cov:
+		fmt.Printf("Subscribed to %s, press Enter to stop\n", objectID.String())
+		if !all {
+			// Notifications only follow changes
+			readCtx, readCancel := context.WithTimeout(ctx, timeout)
+			if value, err := client.ReadProperty(readCtx, devID, objectID, bacnet.PropertyPresentValue); err == nil {
+				update(value)
+			}
+			readCancel()
+		}
+	case bacnet.IsTimeout(err) || bacnet.IsDeviceNotFound(err):
+		return err
+	default:
+		fmt.Printf("COV refused (%v), polling every %s, press Enter to stop\n", err, interval)
+	}
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	done := make(chan struct{})
+	go func() {
+		defer close(done)
+		if cov {
+			renewInteractiveSubscription(streamCtx, client, devID, objectID, subID, lifetime)
+		} else {
+			pollInteractiveValue(streamCtx, client, devID, objectID, interval, update)
+		}
+	}()
+
+	input.Scan()
+	cancel()
+	<-done
+
+	if cov {
+		unsubCtx, unsubCancel := context.WithTimeout(ctx, timeout)
+		defer unsubCancel()
+		if err := client.UnsubscribeCOV(unsubCtx, devID, objectID, subID); err != nil {
+			fmt.Printf("Warning: failed to unsubscribe: %v\n", err)
+		}
+	}
+	return nil
+}
+
+// renewInteractiveSubscription renews a subscription halfway through its
+// lifetime until the context is done
+func renewInteractiveSubscription(ctx context.Context, client *bacnet.Client, devID uint32, objectID bacnet.ObjectIdentifier, subID uint32, opts ...bacnet.SubscribeOption) {
+	ticker := time.NewTicker(interactiveCOVLifetime * time.Second / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			renewCtx, renewCancel := context.WithTimeout(ctx, operationDeadline(1))
+			err := client.RenewCOV(renewCtx, devID, objectID, subID, opts...)
+			renewCancel()
+			if err != nil && ctx.Err() == nil {
+				fmt.Printf("[%s] Failed to renew: %v\n", time.Now().Format("15:04:05.000"), err)
+			}
+		}
+	}
+}
+
+// pollInteractiveValue reads the present value at the interval until the
+// context is done
+func pollInteractiveValue(ctx context.Context, client *bacnet.Client, devID uint32, objectID bacnet.ObjectIdentifier, interval time.Duration, update func(interface{})) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		readCtx, readCancel := context.WithTimeout(ctx, timeout)
+		value, err := client.ReadProperty(readCtx, devID, objectID, bacnet.PropertyPresentValue)
+		readCancel()
+		switch {
+		case ctx.Err() != nil:
+			return
+		case err != nil:
+			fmt.Printf("[%s] Error: %v\n", time.Now().Format("15:04:05.000"), err)
+		default:
+			update(value)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
 }
 
 func runInteractiveInfo(ctx context.Context, client *bacnet.Client, devID uint32) {
@@ -330,6 +770,28 @@ func runInteractiveInfo(ctx context.Context, client *bacnet.Client, devID uint32
 	fmt.Println()
 }
 
+// printCounts prints labeled counters, most frequent first
+func printCounts(title string, counts map[string]int64) {
+	if len(counts) == 0 {
+		return
+	}
+	labels := make([]string, 0, len(counts))
+	for label := range counts {
+		labels = append(labels, label)
+	}
+	sort.Slice(labels, func(i, j int) bool {
+		if counts[labels[i]] != counts[labels[j]] {
+			return counts[labels[i]] > counts[labels[j]]
+		}
+		return labels[i] < labels[j]
+	})
+
+	fmt.Printf("\n%s:\n", title)
+	for _, label := range labels {
+		fmt.Printf("  %-40s %d\n", label, counts[label])
+	}
+}
+
 func runInteractiveMetrics(client *bacnet.Client) {
 	m := client.Metrics().Snapshot()
 
@@ -347,6 +809,33 @@ func runInteractiveMetrics(client *bacnet.Client) {
 		fmt.Printf("  Avg Latency:         %s\n", m.LatencyStats.Avg.Round(time.Microsecond))
 		fmt.Printf("  Min Latency:         %s\n", m.LatencyStats.Min.Round(time.Microsecond))
 		fmt.Printf("  Max Latency:         %s\n", m.LatencyStats.Max.Round(time.Microsecond))
+		fmt.Printf("  p50/p95/p99 Latency: %s / %s / %s\n", m.LatencyStats.P50.Round(time.Microsecond),
+			m.LatencyStats.P95.Round(time.Microsecond), m.LatencyStats.P99.Round(time.Microsecond))
+	}
+
+	printCounts("Errors", m.ErrorCodes)
+	printCounts("Rejects", m.RejectReasons)
+	printCounts("Aborts", m.AbortReasons)
+	printCounts("Abort origins", m.AbortOrigins)
+
+	if len(m.Devices) > 0 {
+		ids := make([]uint32, 0, len(m.Devices))
+		for id := range m.Devices {
+			ids = append(ids, id)
+		}
+		sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+
+		fmt.Println("\nPer Device:")
+		fmt.Printf("  %-10s %6s %6s %8s %10s  %-8s  %s\n", "Device", "Sent", "Failed", "TimedOut", "Avg", "Seen", "Last Error")
+		for _, id := range ids {
+			d := m.Devices[id]
+			seen := "-"
+			if !d.LastSeen.IsZero() {
+				seen = d.LastSeen.Format("15:04:05")
+			}
+			fmt.Printf("  %-10d %6d %6d %8d %10s  %-8s  %s\n", id, d.RequestsSent, d.RequestsFailed,
+				d.RequestsTimedOut, d.LatencyStats.Avg.Round(time.Microsecond), seen, d.LastError)
+		}
 	}
 	fmt.Println()
 }
@@ -0,0 +1,246 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	inventoryScanTimeout time.Duration
+	inventoryBaseline    string
+	inventoryMinFirmware string
+	inventoryMinProtocol uint32
+	inventoryStrict      bool
+)
+
+var inventoryCmd = &cobra.Command{
+	Use:   "inventory",
+	Short: "Report firmware and software versions across devices",
+	Long: `Inventory discovers devices and reports their vendor, model, firmware
+revision, application software version and protocol revision. Devices
+below a baseline are flagged.
+
+Baselines are given with --min-firmware/--min-protocol-revision for all
+devices, or per vendor and model in a baseline file (YAML, JSON or TOML).
+The first matching entry applies:
+
+  baselines:
+    - vendor: Acme Controls
+      model: AC-2000
+      firmware: 4.2.1
+      application-software: 2.0
+    - protocol-revision: 14
+
+Examples:
+  # Inventory all devices on the network
+  edgeo-bacnet inventory
+
+  # Inventory a single device
+  edgeo-bacnet inventory -d 1234
+
+  # Flag devices below the baselines, fail if any
+  edgeo-bacnet inventory --baseline baselines.yaml --strict -o csv`,
+
+	// Devices below baseline are not a usage error
+	SilenceUsage: true,
+
+	RunE: runInventory,
+}
+
+func init() {
+	inventoryCmd.Flags().DurationVar(&inventoryScanTimeout, "scan-timeout", 5*time.Second, "Discovery timeout")
+	inventoryCmd.Flags().StringVar(&inventoryBaseline, "baseline", "", "Baseline file")
+	inventoryCmd.Flags().StringVar(&inventoryMinFirmware, "min-firmware", "", "Minimum firmware revision for all devices")
+	inventoryCmd.Flags().Uint32Var(&inventoryMinProtocol, "min-protocol-revision", 0, "Minimum protocol revision for all devices")
+	inventoryCmd.Flags().BoolVar(&inventoryStrict, "strict", false, "Exit with an error if any device is below baseline")
+}
+
+func runInventory(cmd *cobra.Command, args []string) error {
+	baselines, err := loadBaselines()
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx := context.Background()
+	connectCtx, cancel := context.WithTimeout(ctx, timeout*2)
+	err = client.Connect(connectCtx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	var deviceIDs []uint32
+	if deviceID != 0 {
+		deviceIDs = []uint32{deviceID}
+	} else {
+		fmt.Fprintln(os.Stderr, "Scanning for BACnet devices...")
+		scanCtx, cancel := context.WithTimeout(ctx, timeout+inventoryScanTimeout)
+		_, err := client.WhoIs(scanCtx, bacnet.WithDiscoveryTimeout(inventoryScanTimeout))
+		cancel()
+		if err != nil {
+			return fmt.Errorf("discovery: %w", err)
+		}
+	}
+
+	// A device not answering ReadPropertyMultiple is given up on after its
+	// first single read
+	readCtx, cancel := context.WithTimeout(ctx, operationDeadline(2))
+	versions := client.Inventory(readCtx, deviceIDs)
+	cancel()
+
+	if len(versions) == 0 {
+		fmt.Println("No devices found")
+		return nil
+	}
+
+	below := 0
+	for i := range versions {
+		if versions[i].Err == nil && !versions[i].Check(baselines) {
+			below++
+		}
+	}
+
+	switch outputFmt {
+	case "json", "yaml":
+		err = outputInventoryJSON(versions)
+	case "csv":
+		err = outputInventoryCSV(versions)
+	default:
+		outputInventoryTable(versions, below)
+	}
+	if err != nil {
+		return err
+	}
+
+	if inventoryStrict && below > 0 {
+		return fmt.Errorf("%d of %d devices below baseline", below, len(versions))
+	}
+	return nil
+}
+
+// loadBaselines returns the baselines from the file, followed by the one
+// given with flags
+func loadBaselines() ([]bacnet.VersionBaseline, error) {
+	var baselines []bacnet.VersionBaseline
+
+	if inventoryBaseline != "" {
+		v := viper.New()
+		v.SetConfigFile(inventoryBaseline)
+		if err := v.ReadInConfig(); err != nil {
+			return nil, fmt.Errorf("read baseline: %w", err)
+		}
+		if err := v.UnmarshalKey("baselines", &baselines); err != nil {
+			return nil, fmt.Errorf("parse baseline: %w", err)
+		}
+	}
+
+	if inventoryMinFirmware != "" || inventoryMinProtocol > 0 {
+		baselines = append(baselines, bacnet.VersionBaseline{
+			FirmwareRevision: inventoryMinFirmware,
+			ProtocolRevision: inventoryMinProtocol,
+		})
+	}
+
+	return baselines, nil
+}
+
+// inventoryStatus returns the status column of a device
+func inventoryStatus(v bacnet.DeviceVersion) string {
+	switch {
+	case v.Err != nil:
+		return "ERROR: " + v.Err.Error()
+	case len(v.BelowBaseline) > 0:
+		return "BELOW: " + strings.Join(v.BelowBaseline, "; ")
+	default:
+		return "OK"
+	}
+}
+
+func outputInventoryTable(versions []bacnet.DeviceVersion, below int) {
+	f := NewFormatter(outputFmt)
+
+	headers := []string{"DEVICE", "VENDOR", "MODEL", "FIRMWARE", "APP SOFTWARE", "PROTOCOL REV", "STATUS"}
+	rows := make([][]string, 0, len(versions))
+	for _, v := range versions {
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", v.DeviceID),
+			v.VendorName,
+			v.ModelName,
+			v.FirmwareRevision,
+			v.ApplicationSoftware,
+			fmt.Sprintf("%d", v.ProtocolRevision),
+			inventoryStatus(v),
+		})
+	}
+	f.PrintTable(headers, rows)
+
+	f.Printf("\n%d devices, %d below baseline\n", len(versions), below)
+}
+
+func outputInventoryJSON(versions []bacnet.DeviceVersion) error {
+	type entry struct {
+		bacnet.DeviceVersion
+		Error string `json:"error,omitempty"`
+	}
+
+	entries := make([]entry, len(versions))
+	for i, v := range versions {
+		entries[i] = entry{DeviceVersion: v}
+		if v.Err != nil {
+			entries[i].Error = v.Err.Error()
+		}
+	}
+
+	return encodeOutput(os.Stdout, entries)
+}
+
+func outputInventoryCSV(versions []bacnet.DeviceVersion) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	writer.Write([]string{"device_id", "vendor_id", "vendor_name", "model_name", "firmware_revision",
+		"application_software_version", "protocol_revision", "status"})
+	for _, v := range versions {
+		writer.Write([]string{
+			fmt.Sprintf("%d", v.DeviceID),
+			fmt.Sprintf("%d", v.VendorID),
+			v.VendorName,
+			v.ModelName,
+			v.FirmwareRevision,
+			v.ApplicationSoftware,
+			fmt.Sprintf("%d", v.ProtocolRevision),
+			inventoryStatus(v),
+		})
+	}
+
+	return writer.Error()
+}
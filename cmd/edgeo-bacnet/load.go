@@ -0,0 +1,288 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	loadFile     string
+	loadInclude  []string
+	loadExclude  []string
+	loadPriority int
+	loadDryRun   bool
+)
+
+var loadCmd = &cobra.Command{
+	Use:   "load",
+	Short: "Write the properties of a dump back to a device",
+	Long: `Load replays the writable properties of a JSON dump to a device, one
+WritePropertyMultiple per object, and reports the outcome of every property.
+When the device rejects an object's batch, its properties are written one at
+a time.
+
+Properties that devices maintain themselves, such as status-flags or the
+object list, are never written. The present value is excluded by default so
+that loading a configuration does not override the running values; pass
+--exclude= to write it too. Properties holding lists or structures are
+skipped.
+
+The device defaults to the one the dump was taken from.
+
+Examples:
+  # Preview what would be written
+  edgeo-bacnet load -f device_backup.json --dry-run
+
+  # Restore the configuration to a replacement device
+  edgeo-bacnet load -d 5678 -f device_backup.json
+
+  # Only restore descriptions and alarm limits
+  edgeo-bacnet load -f device_backup.json --include description,high-limit,low-limit`,
+
+	// Failed writes are not a usage error
+	SilenceUsage: true,
+
+	RunE: runLoad,
+}
+
+func init() {
+	loadCmd.Flags().StringVarP(&loadFile, "file", "f", "", "Dump file (JSON, as written by dump -o json)")
+	loadCmd.Flags().StringSliceVar(&loadInclude, "include", nil, "Only write these properties")
+	loadCmd.Flags().StringSliceVar(&loadExclude, "exclude", []string{"present-value"}, "Properties not to write")
+	loadCmd.Flags().IntVar(&loadPriority, "priority", 0, "Write priority (1-16, default none)")
+	loadCmd.Flags().BoolVar(&loadDryRun, "dry-run", false, "List the writes without sending them")
+
+	loadCmd.MarkFlagRequired("file")
+}
+
+// loadReadOnly are the properties of a dump that devices maintain
+// themselves, which load never writes
+var loadReadOnly = map[bacnet.PropertyIdentifier]bool{
+	bacnet.PropertyObjectIdentifier:             true,
+	bacnet.PropertyObjectType:                   true,
+	bacnet.PropertyObjectList:                   true,
+	bacnet.PropertyStructuredObjectList:         true,
+	bacnet.PropertyStatusFlags:                  true,
+	bacnet.PropertyEventState:                   true,
+	bacnet.PropertyReliability:                  true,
+	bacnet.PropertyPriorityArray:                true,
+	bacnet.PropertyAckedTransitions:             true,
+	bacnet.PropertyEventTimeStamps:              true,
+	bacnet.PropertySystemStatus:                 true,
+	bacnet.PropertyVendorName:                   true,
+	bacnet.PropertyVendorIdentifier:             true,
+	bacnet.PropertyModelName:                    true,
+	bacnet.PropertyFirmwareRevision:             true,
+	bacnet.PropertyApplicationSoftwareVersion:   true,
+	bacnet.PropertyProtocolVersion:              true,
+	bacnet.PropertyProtocolRevision:             true,
+	bacnet.PropertyProtocolServicesSupported:    true,
+	bacnet.PropertyProtocolObjectTypesSupported: true,
+	bacnet.PropertyMaxApduLengthAccepted:        true,
+	bacnet.PropertySegmentationSupported:        true,
+	bacnet.PropertyDatabaseRevision:             true,
+	bacnet.PropertyDeviceAddressBinding:         true,
+	bacnet.PropertyActiveCOVSubscriptions:       true,
+	bacnet.PropertyLocalDate:                    true,
+	bacnet.PropertyLocalTime:                    true,
+	bacnet.PropertyLastRestoreTime:              true,
+	bacnet.PropertyBackupAndRestoreState:        true,
+	bacnet.PropertyLogBuffer:                    true,
+	bacnet.PropertyTotalRecordCount:             true,
+	bacnet.PropertyChangeOfStateTime:            true,
+	bacnet.PropertyInProcess:                    true,
+	bacnet.PropertyAllWritesSuccessful:          true,
+	bacnet.PropertyTrackingValue:                true,
+	bacnet.PropertyInProgress:                   true,
+}
+
+func runLoad(cmd *cobra.Command, args []string) error {
+	if loadPriority < 0 || loadPriority > 16 {
+		return fmt.Errorf("priority %d out of range 1-16", loadPriority)
+	}
+	include, err := loadPropertySet(loadInclude)
+	if err != nil {
+		return fmt.Errorf("--include: %w", err)
+	}
+	exclude, err := loadPropertySet(loadExclude)
+	if err != nil {
+		return fmt.Errorf("--exclude: %w", err)
+	}
+
+	snap, err := bacnet.ReadSnapshotFile(loadFile)
+	if err != nil {
+		return err
+	}
+	if deviceID == 0 {
+		deviceID = snap.DeviceID
+	}
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	// The writes of each object form one batch
+	var batches [][]bacnet.WritePropertyRequest
+	var results [][]WritemResult
+	row, skipped := 0, 0
+	for _, obj := range snap.Objects {
+		objectID, err := parseObjectIdentifier(obj.ObjectID)
+		if err != nil {
+			return fmt.Errorf("%s: %w", obj.ObjectID, err)
+		}
+
+		names := make([]string, 0, len(obj.Properties))
+		for name := range obj.Properties {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+
+		var requests []bacnet.WritePropertyRequest
+		var objResults []WritemResult
+		for _, name := range names {
+			propID, err := parsePropertyIdentifier(name)
+			if err != nil || loadReadOnly[propID] || exclude[propID] || (include != nil && !include[propID]) {
+				skipped++
+				continue
+			}
+			value, ok := loadValue(obj.Properties[name])
+			if !ok {
+				skipped++
+				continue
+			}
+
+			req := bacnet.WritePropertyRequest{
+				ObjectID:   objectID,
+				PropertyID: propID,
+				Value:      bacnet.CoerceValue(objectID, propID, value),
+			}
+			if loadPriority > 0 {
+				priority := uint8(loadPriority)
+				req.Priority = &priority
+			}
+			row++
+			requests = append(requests, req)
+			objResults = append(objResults, WritemResult{
+				Row:      row,
+				Object:   objectID.String(),
+				Property: propID.String(),
+				Value:    formatValue(req.Value),
+				Priority: loadPriority,
+			})
+		}
+		if len(requests) > 0 {
+			batches = append(batches, requests)
+			results = append(results, objResults)
+		}
+	}
+	if skipped > 0 {
+		fmt.Fprintf(os.Stderr, "Skipping %d read-only, excluded or structured properties\n", skipped)
+	}
+	if row == 0 {
+		return fmt.Errorf("%s has no properties to write", loadFile)
+	}
+
+	if loadDryRun {
+		var all []WritemResult
+		for _, objResults := range results {
+			for i := range objResults {
+				objResults[i].Result = "planned"
+			}
+			all = append(all, objResults...)
+		}
+		return outputWritem(all)
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx := context.Background()
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	var all []WritemResult
+	failed := 0
+	for i, requests := range batches {
+		// Each batch has its own deadline, so that a batch left unanswered
+		// does not use up the time of the others
+		batchCtx, cancel := context.WithTimeout(ctx, operationDeadline(len(requests)+1))
+		writemExecute(batchCtx, client, requests, results[i])
+		cancel()
+		for _, r := range results[i] {
+			if r.Error != "" {
+				failed++
+			}
+		}
+		all = append(all, results[i]...)
+	}
+
+	if err := outputWritem(all); err != nil {
+		return err
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d writes failed", failed, row)
+	}
+	return nil
+}
+
+// loadPropertySet parses a list of property names, returning nil for an
+// empty list
+func loadPropertySet(names []string) (map[bacnet.PropertyIdentifier]bool, error) {
+	if len(names) == 0 {
+		return nil, nil
+	}
+	set := make(map[bacnet.PropertyIdentifier]bool, len(names))
+	for _, name := range names {
+		propID, err := parsePropertyIdentifier(name)
+		if err != nil {
+			return nil, err
+		}
+		set[propID] = true
+	}
+	return set, nil
+}
+
+// loadValue converts a value of a JSON dump to the value to write: whole
+// numbers as Unsigned or INTEGER and others as REAL, before CoerceValue
+// picks the datatype of the property. Lists, structures and nulls cannot
+// be written back.
+func loadValue(value interface{}) (interface{}, bool) {
+	switch v := value.(type) {
+	case bool, string:
+		return v, true
+	case float64:
+		if v == math.Trunc(v) && v >= math.MinInt32 && v <= math.MaxUint32 {
+			if v < 0 {
+				return int32(v), true
+			}
+			return uint32(v), true
+		}
+		return float32(v), true
+	default:
+		return nil, false
+	}
+}
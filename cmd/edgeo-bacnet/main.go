@@ -19,7 +19,15 @@ import (
 )
 
 func main() {
-	if err := rootCmd.Execute(); err != nil {
-		os.Exit(1)
+	markUsageErrors(rootCmd)
+	err := rootCmd.Execute()
+	if err != nil {
+		err = markLookupError(rootCmd, os.Args[1:], err)
+	}
+	if closeErr := closeCapture(); err == nil {
+		err = closeErr
+	}
+	if err != nil {
+		os.Exit(reportError(err))
 	}
 }
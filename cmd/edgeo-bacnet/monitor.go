@@ -0,0 +1,629 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/gdamore/tcell/v2"
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	monitorObjects    []string
+	monitorPointsFile string
+	monitorProperty   string
+	monitorInterval   time.Duration
+	monitorCOV        bool
+)
+
+var monitorCmd = &cobra.Command{
+	Use:   "monitor",
+	Short: "Monitor a device in a terminal dashboard",
+	Long: `Monitor shows a device in a dashboard refreshed in place: the status of
+the device, the values of the watched points with their rates of COV
+notifications, and the metrics of the client.
+
+The points are given as for watch, with -O or a --points file. They are
+polled every --interval, or their objects are subscribed to with --cov.
+The device status is polled in both cases.
+
+Keys:
+  up/down, k/j    select a point
+  pgup/pgdn       scroll the points a page
+  home/end        first and last point
+  p               pause or resume polling
+  r               poll now
+  q, esc          quit
+
+Examples:
+  # Monitor a device and three points
+  edgeo-bacnet monitor -d 1234 -O ai:1 -O ai:2 -O bv:3
+
+  # Monitor the points of a file through COV notifications
+  edgeo-bacnet monitor -d 1234 --points points.csv --cov`,
+
+	RunE: runMonitor,
+}
+
+func init() {
+	monitorCmd.Flags().StringArrayVarP(&monitorObjects, "object", "O", nil, "Object to watch (repeatable, e.g. analog-input:1)")
+	monitorCmd.Flags().StringVar(&monitorPointsFile, "points", "", "Points file (CSV, YAML, JSON or TOML)")
+	monitorCmd.Flags().StringVarP(&monitorProperty, "property", "P", "present-value", "Property identifier of the -O objects")
+	monitorCmd.Flags().DurationVar(&monitorInterval, "interval", 2*time.Second, "Polling interval")
+	monitorCmd.Flags().BoolVar(&monitorCOV, "cov", false, "Use COV subscriptions for the points instead of polling")
+}
+
+// monitorDeviceProperties are the properties of the device object shown by
+// monitor, polled with the points
+var monitorDeviceProperties = []bacnet.PropertyIdentifier{
+	bacnet.PropertyObjectName,
+	bacnet.PropertyVendorName,
+	bacnet.PropertyModelName,
+	bacnet.PropertyFirmwareRevision,
+	bacnet.PropertySystemStatus,
+}
+
+// monitor is the state of the dashboard. The poller and the COV
+// notifications update it while the screen draws it, under mu.
+type monitor struct {
+	mu     sync.Mutex
+	client *bacnet.Client
+	points []*watchPoint
+	byKey  map[watchKey]*watchPoint
+
+	// Device object properties and the outcome of the last poll
+	device   map[bacnet.PropertyIdentifier]interface{}
+	online   bool
+	polled   time.Time
+	pollErr  error
+	paused   bool
+	covTimes map[bacnet.ObjectIdentifier][]time.Time
+	covSubs  int
+	covErr   error
+
+	// Selected point and first point shown
+	selected int
+	offset   int
+}
+
+func runMonitor(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+	requests, err := pointRequests(monitorObjects, []string{monitorProperty}, monitorPointsFile)
+	if err != nil {
+		return err
+	}
+	if info, err := os.Stdout.Stat(); err != nil || info.Mode()&os.ModeCharDevice == 0 {
+		return fmt.Errorf("monitor needs a terminal: use watch to record values")
+	}
+
+	// Log lines would scroll the dashboard away
+	logger = slog.New(slog.NewTextHandler(io.Discard, nil))
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	m := &monitor{
+		client:   client,
+		byKey:    make(map[watchKey]*watchPoint),
+		device:   make(map[bacnet.PropertyIdentifier]interface{}),
+		covTimes: make(map[bacnet.ObjectIdentifier][]time.Time),
+	}
+	m.points = newWatchTable(requests).points
+	for _, p := range m.points {
+		m.byKey[newWatchKey(p.request.ObjectID, p.request.PropertyID, p.request.ArrayIndex)] = p
+	}
+
+	screen, err := tcell.NewScreen()
+	if err != nil {
+		return fmt.Errorf("terminal: %w", err)
+	}
+	if err := screen.Init(); err != nil {
+		return fmt.Errorf("terminal: %w", err)
+	}
+	defer screen.Fini()
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	// Every update redraws the screen from its event loop
+	redraw := func() { screen.PostEvent(tcell.NewEventInterrupt(nil)) }
+	pollNow := make(chan struct{}, 1)
+
+	var wg sync.WaitGroup
+	wg.Add(2)
+	go func() {
+		defer wg.Done()
+		m.run(ctx, pollNow, redraw)
+	}()
+	go func() {
+		defer wg.Done()
+		// The clock and the rates move without updates
+		ticker := time.NewTicker(time.Second)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-ctx.Done():
+				redraw()
+				return
+			case <-ticker.C:
+				redraw()
+			}
+		}
+	}()
+	defer func() {
+		cancel()
+		wg.Wait()
+	}()
+
+	m.draw(screen)
+	for {
+		switch ev := screen.PollEvent().(type) {
+		case nil:
+			return nil
+		case *tcell.EventResize:
+			screen.Sync()
+		case *tcell.EventKey:
+			if !m.key(ev, screen, pollNow) {
+				return nil
+			}
+		}
+		if ctx.Err() != nil {
+			return nil
+		}
+		m.draw(screen)
+	}
+}
+
+// run reads the state texts of the points, subscribes to their objects with
+// --cov, and polls until ctx is done
+func (m *monitor) run(ctx context.Context, pollNow <-chan struct{}, redraw func()) {
+	for _, p := range m.points {
+		textCtx, cancel := context.WithTimeout(ctx, timeout)
+		texts := readStateTexts(textCtx, m.client, deviceID, p.request.ObjectID, p.request.PropertyID)
+		cancel()
+		m.mu.Lock()
+		p.texts = texts
+		m.mu.Unlock()
+	}
+
+	// The points are read once even with COV, for the properties that
+	// notifications do not report
+	m.poll(ctx, true)
+	redraw()
+
+	if monitorCOV {
+		subs := m.subscribe(ctx, redraw)
+		defer func() {
+			unsubCtx, cancel := context.WithTimeout(context.Background(), timeout)
+			defer cancel()
+			for _, sub := range subs {
+				m.client.UnsubscribeCOV(unsubCtx, deviceID, sub.objectID, sub.subID)
+			}
+		}()
+	}
+
+	ticker := time.NewTicker(monitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			m.mu.Lock()
+			paused := m.paused
+			m.mu.Unlock()
+			if paused {
+				continue
+			}
+		case <-pollNow:
+		}
+		m.poll(ctx, !monitorCOV)
+		redraw()
+	}
+}
+
+// poll reads the device object, and the points too when points is set
+func (m *monitor) poll(ctx context.Context, points bool) {
+	requests := make([]bacnet.ReadPropertyRequest, 0, len(monitorDeviceProperties)+len(m.points))
+	deviceObject := bacnet.NewObjectIdentifier(bacnet.ObjectTypeDevice, deviceID)
+	for _, prop := range monitorDeviceProperties {
+		requests = append(requests, bacnet.ReadPropertyRequest{ObjectID: deviceObject, PropertyID: prop})
+	}
+	if points {
+		for _, p := range m.points {
+			requests = append(requests, p.request)
+		}
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, operationDeadline(len(requests)+1))
+	results, err := m.client.Device(deviceID).ReadMultiple(readCtx, requests)
+	cancel()
+	if ctx.Err() != nil {
+		return
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.polled = now
+	m.pollErr = err
+	m.online = err == nil
+	if err != nil {
+		return
+	}
+	for _, r := range results {
+		if r.ObjectID == deviceObject {
+			if r.Err == nil {
+				m.device[r.PropertyID] = r.Value
+			}
+			continue
+		}
+		if p, ok := m.byKey[newWatchKey(r.ObjectID, r.PropertyID, r.ArrayIndex)]; ok {
+			m.set(now, p, r.Value, r.Err)
+		}
+	}
+}
+
+// subscribe subscribes to the objects of the points, counting the
+// notifications of each for its rate
+func (m *monitor) subscribe(ctx context.Context, redraw func()) []covSubscription {
+	notify := func(devID uint32, objectID bacnet.ObjectIdentifier, values []bacnet.PropertyValue) {
+		m.mu.Lock()
+		now := time.Now()
+		m.covTimes[objectID] = append(m.covTimes[objectID], now)
+		for _, pv := range values {
+			if p, ok := m.byKey[newWatchKey(objectID, pv.PropertyID, pv.ArrayIndex)]; ok {
+				m.set(now, p, pv.Value, nil)
+			}
+		}
+		m.mu.Unlock()
+		redraw()
+	}
+
+	var subs []covSubscription
+	seen := make(map[bacnet.ObjectIdentifier]bool)
+	for _, p := range m.points {
+		objectID := p.request.ObjectID
+		if seen[objectID] {
+			continue
+		}
+		seen[objectID] = true
+
+		subCtx, cancel := context.WithTimeout(ctx, operationDeadline(1))
+		subID, err := m.client.SubscribeCOV(subCtx, deviceID, objectID, notify)
+		cancel()
+		if err != nil {
+			m.mu.Lock()
+			m.covErr = fmt.Errorf("subscribe to %s: %w", objectID, err)
+			m.mu.Unlock()
+			continue
+		}
+		subs = append(subs, covSubscription{objectID: objectID, subID: subID})
+	}
+
+	m.mu.Lock()
+	m.covSubs = len(subs)
+	m.mu.Unlock()
+	return subs
+}
+
+// set records the value or error of a point
+func (m *monitor) set(now time.Time, p *watchPoint, value interface{}, err error) {
+	if !p.read || !valuesEqual(p.value, value) || errorText(p.err) != errorText(err) {
+		p.updated = now
+	}
+	p.read = true
+	p.value = value
+	p.err = err
+}
+
+// covRate returns the COV notifications of an object in the last minute
+func (m *monitor) covRate(now time.Time, objectID bacnet.ObjectIdentifier) int {
+	times := m.covTimes[objectID]
+	i := 0
+	for i < len(times) && now.Sub(times[i]) > time.Minute {
+		i++
+	}
+	m.covTimes[objectID] = times[i:]
+	return len(times) - i
+}
+
+// key handles a key press, returning false to quit
+func (m *monitor) key(ev *tcell.EventKey, screen tcell.Screen, pollNow chan<- struct{}) bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	_, h := screen.Size()
+	page := m.pointRows(h)
+	switch ev.Key() {
+	case tcell.KeyEscape, tcell.KeyCtrlC:
+		return false
+	case tcell.KeyUp:
+		m.selected--
+	case tcell.KeyDown:
+		m.selected++
+	case tcell.KeyPgUp:
+		m.selected -= page
+	case tcell.KeyPgDn:
+		m.selected += page
+	case tcell.KeyHome:
+		m.selected = 0
+	case tcell.KeyEnd:
+		m.selected = len(m.points) - 1
+	case tcell.KeyRune:
+		switch ev.Rune() {
+		case 'q':
+			return false
+		case 'k':
+			m.selected--
+		case 'j':
+			m.selected++
+		case 'p':
+			m.paused = !m.paused
+		case 'r':
+			select {
+			case pollNow <- struct{}{}:
+			default:
+			}
+		}
+	}
+
+	if m.selected >= len(m.points) {
+		m.selected = len(m.points) - 1
+	}
+	if m.selected < 0 {
+		m.selected = 0
+	}
+	return true
+}
+
+// monitorPanelHeight is the height of the device and client panels
+const monitorPanelHeight = 9
+
+// pointRows returns the number of points shown on a screen of height h:
+// all but the title, the panels, the status line and the points box
+// borders and header
+func (m *monitor) pointRows(h int) int {
+	rows := h - 2 - monitorPanelHeight - 3
+	if rows < 1 {
+		rows = 1
+	}
+	return rows
+}
+
+// draw draws the dashboard
+func (m *monitor) draw(screen tcell.Screen) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	screen.Clear()
+	w, h := screen.Size()
+	now := time.Now()
+	bold := tcell.StyleDefault.Bold(true)
+
+	state := "polling every " + monitorInterval.String()
+	if m.paused {
+		state = "paused"
+	}
+	drawText(screen, 0, 0, w, bold, fmt.Sprintf(" Device %d  %s  %s", deviceID, now.Format("15:04:05"), state))
+
+	// Device and client panels side by side
+	half := w / 2
+	m.drawDevice(screen, 0, 1, half, monitorPanelHeight, now)
+	m.drawClient(screen, half, 1, w-half, monitorPanelHeight, now)
+
+	top := 1 + monitorPanelHeight
+	m.drawPoints(screen, 0, top, w, h-top-1, now)
+
+	help := " ↑/↓ select  p pause  r poll now  q quit"
+	if len(m.points) > 0 {
+		p := m.points[m.selected]
+		help = fmt.Sprintf(" %s = %s  |%s", p.name, p.display(), help)
+	}
+	drawText(screen, 0, h-1, w, tcell.StyleDefault.Reverse(true), padRight(help, w))
+
+	screen.Show()
+}
+
+func (m *monitor) drawDevice(screen tcell.Screen, x, y, w, h int, now time.Time) {
+	drawBox(screen, x, y, w, h, "Device")
+
+	status, style := "offline", tcell.StyleDefault.Foreground(tcell.ColorRed)
+	if m.online {
+		status, style = "online", tcell.StyleDefault.Foreground(tcell.ColorGreen)
+	}
+	if m.polled.IsZero() {
+		status, style = "connecting", tcell.StyleDefault
+	}
+
+	text := func(prop bacnet.PropertyIdentifier) string {
+		if v, ok := m.device[prop]; ok {
+			return formatValue(v)
+		}
+		return ""
+	}
+	systemStatus := ""
+	if v, ok := m.device[bacnet.PropertySystemStatus].(uint32); ok {
+		systemStatus = bacnet.DeviceStatus(v).String()
+	}
+	address := ""
+	if info, ok := m.client.GetDevice(deviceID); ok {
+		address = formatAddress(info.Address)
+	}
+
+	lines := [][2]string{
+		{"Name", text(bacnet.PropertyObjectName)},
+		{"Address", address},
+		{"Vendor", text(bacnet.PropertyVendorName)},
+		{"Model", text(bacnet.PropertyModelName)},
+		{"Firmware", text(bacnet.PropertyFirmwareRevision)},
+		{"Status", systemStatus},
+	}
+	for i, line := range lines {
+		drawText(screen, x+2, y+1+i, w-4, tcell.StyleDefault, fmt.Sprintf("%-9s %s", line[0], line[1]))
+	}
+
+	// The outcome of the last poll on the last line
+	drawText(screen, x+2, y+h-2, w-4, style, status)
+	if m.pollErr != nil {
+		drawText(screen, x+2+len(status)+1, y+h-2, w-5-len(status), tcell.StyleDefault, m.pollErr.Error())
+	} else if !m.polled.IsZero() {
+		drawText(screen, x+2+len(status)+1, y+h-2, w-5-len(status), tcell.StyleDefault,
+			fmt.Sprintf("polled %s ago", now.Sub(m.polled).Truncate(time.Second)))
+	}
+}
+
+func (m *monitor) drawClient(screen tcell.Screen, x, y, w, h int, now time.Time) {
+	drawBox(screen, x, y, w, h, "Client")
+
+	snap := m.client.Metrics().Snapshot()
+	covRate := 0
+	for objectID := range m.covTimes {
+		covRate += m.covRate(now, objectID)
+	}
+
+	lines := []string{
+		fmt.Sprintf("Uptime      %s", snap.Uptime.Truncate(time.Second)),
+		fmt.Sprintf("Requests    %d sent, %d failed, %d timeouts",
+			snap.RequestsSent, snap.RequestsFailed, snap.RequestsTimedOut),
+		fmt.Sprintf("Latency     avg %s, p99 %s", formatLatency(snap.LatencyStats.Avg), formatLatency(snap.LatencyStats.P99)),
+		fmt.Sprintf("Traffic     %d B sent, %d B received", snap.BytesSent, snap.BytesReceived),
+		fmt.Sprintf("COV         %d subscribed, %d/min", m.covSubs, covRate),
+		fmt.Sprintf("Errors      %d errors, %d rejects, %d aborts",
+			snap.ErrorsReceived, snap.RejectsReceived, snap.AbortsReceived),
+	}
+	for i, line := range lines {
+		drawText(screen, x+2, y+1+i, w-4, tcell.StyleDefault, line)
+	}
+	if m.covErr != nil {
+		drawText(screen, x+2, y+h-2, w-4, tcell.StyleDefault.Foreground(tcell.ColorRed), m.covErr.Error())
+	}
+}
+
+func (m *monitor) drawPoints(screen tcell.Screen, x, y, w, h int, now time.Time) {
+	drawBox(screen, x, y, w, h, fmt.Sprintf("Points (%d)", len(m.points)))
+
+	rows := h - 3
+	if m.selected < m.offset {
+		m.offset = m.selected
+	}
+	if m.selected >= m.offset+rows {
+		m.offset = m.selected - rows + 1
+	}
+
+	nameWidth := 5
+	for _, p := range m.points {
+		if len(p.name) > nameWidth {
+			nameWidth = len(p.name)
+		}
+	}
+	format := fmt.Sprintf("%%-%ds  %%-24s  %%-8s  %%7s", nameWidth)
+	drawText(screen, x+2, y+1, w-4, tcell.StyleDefault.Bold(true), fmt.Sprintf(format, "POINT", "VALUE", "CHANGED", "COV/MIN"))
+
+	for i := 0; i < rows && m.offset+i < len(m.points); i++ {
+		n := m.offset + i
+		p := m.points[n]
+
+		updated, rate := "", ""
+		if p.read {
+			updated = p.updated.Format("15:04:05")
+		}
+		if monitorCOV {
+			rate = fmt.Sprint(m.covRate(now, p.request.ObjectID))
+		}
+
+		style := tcell.StyleDefault
+		if p.err != nil {
+			style = style.Foreground(tcell.ColorRed)
+		} else if now.Sub(p.updated) < 2*time.Second {
+			style = style.Foreground(tcell.ColorYellow)
+		}
+		if n == m.selected {
+			style = style.Reverse(true)
+		}
+		line := fmt.Sprintf(format, p.name, p.display(), updated, rate)
+		drawText(screen, x+2, y+2+i, w-4, style, padRight(line, w-4))
+	}
+}
+
+// formatLatency formats a latency in milliseconds
+func formatLatency(d time.Duration) string {
+	return fmt.Sprintf("%.1f ms", float64(d)/float64(time.Millisecond))
+}
+
+// drawBox draws a box with a title on its top border
+func drawBox(screen tcell.Screen, x, y, w, h int, title string) {
+	if w < 2 || h < 2 {
+		return
+	}
+	style := tcell.StyleDefault
+	for i := x + 1; i < x+w-1; i++ {
+		screen.SetContent(i, y, tcell.RuneHLine, nil, style)
+		screen.SetContent(i, y+h-1, tcell.RuneHLine, nil, style)
+	}
+	for j := y + 1; j < y+h-1; j++ {
+		screen.SetContent(x, j, tcell.RuneVLine, nil, style)
+		screen.SetContent(x+w-1, j, tcell.RuneVLine, nil, style)
+	}
+	screen.SetContent(x, y, tcell.RuneULCorner, nil, style)
+	screen.SetContent(x+w-1, y, tcell.RuneURCorner, nil, style)
+	screen.SetContent(x, y+h-1, tcell.RuneLLCorner, nil, style)
+	screen.SetContent(x+w-1, y+h-1, tcell.RuneLRCorner, nil, style)
+	drawText(screen, x+2, y, w-4, style.Bold(true), " "+title+" ")
+}
+
+// drawText draws text on one line, cut at max cells
+func drawText(screen tcell.Screen, x, y, max int, style tcell.Style, text string) {
+	i := 0
+	for _, r := range text {
+		if i >= max {
+			return
+		}
+		screen.SetContent(x+i, y, r, nil, style)
+		i++
+	}
+}
+
+// padRight pads text with spaces to n runes
+func padRight(text string, n int) string {
+	for l := len([]rune(text)); l < n; l++ {
+		text += " "
+	}
+	return text
+}
@@ -0,0 +1,191 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	networkTarget uint16
+	networkWait   time.Duration
+)
+
+var networkCmd = &cobra.Command{
+	Use:   "network",
+	Short: "Discover the routers and networks reachable from the local network",
+	Long: `Network broadcasts What-Is-Network-Number and Who-Is-Router-To-Network,
+then prints the number of the local network and the remote networks each
+router reaches.
+
+Besides table and json, the output format (-o) accepts dot, for a Graphviz
+graph of the topology.
+
+Examples:
+  # Routers and their networks
+  edgeo-bacnet network
+
+  # The router to network 5
+  edgeo-bacnet network --network 5
+
+  # Render the topology
+  edgeo-bacnet network -o dot | dot -Tsvg > topology.svg`,
+
+	Args: cobra.NoArgs,
+
+	RunE: runNetwork,
+}
+
+func init() {
+	networkCmd.Flags().Uint16Var(&networkTarget, "network", 0, "Only look for the router to this network (0 = all)")
+	networkCmd.Flags().DurationVar(&networkWait, "wait", 3*time.Second, "Time to wait for answers")
+}
+
+// NetworkTopology is the JSON output of the network command
+type NetworkTopology struct {
+	LocalNetworks []LocalNetwork `json:"local_networks"`
+	Routers       []RouterEntry  `json:"routers"`
+}
+
+// LocalNetwork is a Network-Number-Is announcement
+type LocalNetwork struct {
+	Network    uint16 `json:"network"`
+	Router     string `json:"router"`
+	Configured bool   `json:"configured"`
+}
+
+// RouterEntry is a router and the networks it reaches
+type RouterEntry struct {
+	Address  string   `json:"address"`
+	Networks []uint16 `json:"networks"`
+}
+
+func runNetwork(cmd *cobra.Command, args []string) error {
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout+2*networkWait)
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	if verbose {
+		fmt.Fprintln(os.Stderr, "Sending What-Is-Network-Number and Who-Is-Router-To-Network...")
+	}
+
+	numbers, err := client.WhatIsNetworkNumber(ctx, bacnet.WithDiscoveryTimeout(networkWait))
+	if err != nil {
+		return fmt.Errorf("what-is-network-number: %w", err)
+	}
+	routers, err := client.WhoIsRouterToNetwork(ctx,
+		bacnet.WithDiscoveryTimeout(networkWait),
+		bacnet.WithTargetNetwork(networkTarget),
+	)
+	if err != nil {
+		return fmt.Errorf("who-is-router-to-network: %w", err)
+	}
+
+	topology := NetworkTopology{LocalNetworks: []LocalNetwork{}, Routers: []RouterEntry{}}
+	for _, n := range numbers {
+		topology.LocalNetworks = append(topology.LocalNetworks, LocalNetwork{
+			Network:    n.Network,
+			Router:     n.Address.String(),
+			Configured: n.Configured,
+		})
+	}
+	for _, r := range routers {
+		topology.Routers = append(topology.Routers, RouterEntry{Address: r.Address.String(), Networks: r.Networks})
+	}
+	sort.Slice(topology.Routers, func(i, j int) bool { return topology.Routers[i].Address < topology.Routers[j].Address })
+
+	switch outputFmt {
+	case "json", "yaml":
+		return encodeOutput(os.Stdout, topology)
+	case "dot":
+		printNetworkDot(topology)
+		return nil
+	}
+
+	if len(topology.LocalNetworks) == 0 && len(topology.Routers) == 0 {
+		fmt.Println("No routers found")
+		return nil
+	}
+
+	type row struct {
+		network uint16
+		cells   []string
+	}
+	var rows []row
+	for _, n := range topology.LocalNetworks {
+		kind := "local (learned)"
+		if n.Configured {
+			kind = "local (configured)"
+		}
+		rows = append(rows, row{n.Network, []string{fmt.Sprintf("%d", n.Network), n.Router, kind}})
+	}
+	for _, r := range topology.Routers {
+		for _, network := range r.Networks {
+			rows = append(rows, row{network, []string{fmt.Sprintf("%d", network), r.Address, "remote"}})
+		}
+	}
+	sort.SliceStable(rows, func(i, j int) bool { return rows[i].network < rows[j].network })
+
+	table := make([][]string, 0, len(rows))
+	for _, r := range rows {
+		table = append(table, r.cells)
+	}
+	NewFormatter(outputFmt).PrintTable([]string{"NETWORK", "ROUTER", "TYPE"}, table)
+	return nil
+}
+
+// printNetworkDot prints the topology as a Graphviz graph: the local
+// network, the routers on it and the remote networks they reach
+func printNetworkDot(topology NetworkTopology) {
+	local := "local network"
+	if len(topology.LocalNetworks) > 0 {
+		local = fmt.Sprintf("network %d (local)", topology.LocalNetworks[0].Network)
+	}
+
+	fmt.Println("graph bacnet {")
+	fmt.Println("  node [fontname=\"Helvetica\"];")
+	fmt.Printf("  local [label=%q, shape=ellipse, style=bold];\n", local)
+
+	seen := make(map[uint16]bool)
+	for _, r := range topology.Routers {
+		fmt.Printf("  %q [label=%q, shape=box];\n", r.Address, "router\n"+r.Address)
+		fmt.Printf("  local -- %q;\n", r.Address)
+		for _, network := range r.Networks {
+			if !seen[network] {
+				seen[network] = true
+				fmt.Printf("  net%d [label=\"network %d\", shape=ellipse];\n", network, network)
+			}
+			fmt.Printf("  %q -- net%d;\n", r.Address, network)
+		}
+	}
+	fmt.Println("}")
+}
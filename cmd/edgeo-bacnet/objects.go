@@ -0,0 +1,255 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	objectsTypes   []string
+	objectsName    string
+	objectsSort    string
+	objectsReverse bool
+)
+
+var objectsCmd = &cobra.Command{
+	Use:   "objects",
+	Short: "List the objects of a device with their names and units",
+	Long: `Objects lists the objects of a device, with the name, description and
+units of each, read with ReadPropertyMultiple. It is an inventory of the
+device for scripts and spreadsheets: -o json and -o csv write one entry per
+object.
+
+--type keeps the objects of the given types, --name those whose name
+contains a string, ignoring case. --sort orders the objects by object
+identifier, type, instance, name or units.
+
+Examples:
+  # List the objects of a device
+  edgeo-bacnet objects -d 1234
+
+  # List the analog inputs and outputs, by name
+  edgeo-bacnet objects -d 1234 --type ai,ao --sort name
+
+  # Export the temperature points to a spreadsheet
+  edgeo-bacnet objects -d 1234 --name temp -o csv > temps.csv`,
+
+	RunE: runObjects,
+}
+
+func init() {
+	objectsCmd.Flags().StringSliceVar(&objectsTypes, "type", nil, "Object types to list (e.g., ai,av,binary-value)")
+	objectsCmd.Flags().StringVar(&objectsName, "name", "", "List the objects whose name contains this string")
+	objectsCmd.Flags().StringVar(&objectsSort, "sort", "object", "Sort by: object, type, instance, name, units")
+	objectsCmd.Flags().BoolVar(&objectsReverse, "reverse", false, "Reverse the sort order")
+	objectsCmd.Flags().IntVar(&dumpWorkers, "concurrency", 4, "Objects read at the same time")
+}
+
+// ObjectEntry is an object of a device listed by objects
+type ObjectEntry struct {
+	Object      string `json:"object"`
+	Type        string `json:"type"`
+	Instance    uint32 `json:"instance"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Units       string `json:"units,omitempty"`
+
+	id bacnet.ObjectIdentifier
+}
+
+func runObjects(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+	if dumpWorkers < 1 {
+		return fmt.Errorf("--concurrency must be at least 1")
+	}
+	less, ok := objectsLess[objectsSort]
+	if !ok {
+		return fmt.Errorf("invalid sort %q: use object, type, instance, name or units", objectsSort)
+	}
+	types := make(map[bacnet.ObjectType]bool, len(objectsTypes))
+	for _, typeStr := range objectsTypes {
+		objType, ok := bacnet.ParseObjectType(strings.ToLower(typeStr))
+		if !ok {
+			return fmt.Errorf("unknown object type: %s", typeStr)
+		}
+		types[objType] = true
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	objects, err := client.GetObjectList(ctx, deviceID)
+	if err != nil {
+		return fmt.Errorf("get object list: %w", err)
+	}
+	if len(types) > 0 {
+		filtered := make([]bacnet.ObjectIdentifier, 0, len(objects))
+		for _, obj := range objects {
+			if types[obj.Type] {
+				filtered = append(filtered, obj)
+			}
+		}
+		objects = filtered
+	}
+
+	read, err := readDumpObjects(ctx, client, objects, objectsProperties, nil)
+	if err != nil {
+		return fmt.Errorf("read objects: %w", err)
+	}
+
+	entries := make([]ObjectEntry, 0, len(objects))
+	name := strings.ToLower(objectsName)
+	for _, obj := range objects {
+		entry := newObjectEntry(obj, read[obj.String()].Properties)
+		if name != "" && !strings.Contains(strings.ToLower(entry.Name), name) {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+
+	sort.SliceStable(entries, func(i, j int) bool {
+		if objectsReverse {
+			return less(entries[j], entries[i])
+		}
+		return less(entries[i], entries[j])
+	})
+
+	switch outputFmt {
+	case "json", "yaml":
+		return encodeOutput(os.Stdout, entries)
+	case "csv":
+		return outputObjectsCSV(os.Stdout, entries)
+	case "template":
+		for _, entry := range entries {
+			if err := encodeTemplate(os.Stdout, entry); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		outputObjectsTable(entries)
+		return nil
+	}
+}
+
+// objectsProperties returns the properties read for an object: its name,
+// description and, for the types that have them, its units
+func objectsProperties(obj bacnet.ObjectIdentifier) []bacnet.PropertyIdentifier {
+	props := []bacnet.PropertyIdentifier{bacnet.PropertyObjectName, bacnet.PropertyDescription}
+	standard, _ := bacnet.StandardProperties(obj.Type)
+	for _, prop := range standard.All() {
+		if prop == bacnet.PropertyUnits {
+			props = append(props, prop)
+			break
+		}
+	}
+	return props
+}
+
+// newObjectEntry returns the entry of an object from the properties read
+func newObjectEntry(obj bacnet.ObjectIdentifier, props map[string]interface{}) ObjectEntry {
+	entry := ObjectEntry{
+		Object:   obj.String(),
+		Type:     obj.Type.String(),
+		Instance: obj.Instance,
+		id:       obj,
+	}
+	if name, ok := props["object-name"].(string); ok {
+		entry.Name = name
+	}
+	if desc, ok := props["description"].(string); ok {
+		entry.Description = desc
+	}
+	if units, ok := props["units"].(uint32); ok {
+		entry.Units = bacnet.EngineeringUnits(units).String()
+	}
+	return entry
+}
+
+// objectsLess are the orders of --sort
+var objectsLess = map[string]func(a, b ObjectEntry) bool{
+	"object": func(a, b ObjectEntry) bool {
+		if a.id.Type != b.id.Type {
+			return a.id.Type < b.id.Type
+		}
+		return a.id.Instance < b.id.Instance
+	},
+	"type": func(a, b ObjectEntry) bool {
+		return a.Type < b.Type
+	},
+	"instance": func(a, b ObjectEntry) bool {
+		return a.Instance < b.Instance
+	},
+	"name": func(a, b ObjectEntry) bool {
+		return strings.ToLower(a.Name) < strings.ToLower(b.Name)
+	},
+	"units": func(a, b ObjectEntry) bool {
+		return a.Units < b.Units
+	},
+}
+
+func outputObjectsCSV(w io.Writer, entries []ObjectEntry) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	writer.Write([]string{"object", "type", "instance", "name", "description", "units"})
+	for _, e := range entries {
+		writer.Write([]string{e.Object, e.Type, fmt.Sprint(e.Instance), e.Name, e.Description, e.Units})
+	}
+
+	return writer.Error()
+}
+
+func outputObjectsTable(entries []ObjectEntry) {
+	f := NewFormatter(outputFmt)
+
+	if len(entries) == 0 {
+		f.Println("No objects")
+		return
+	}
+
+	headers := []string{"OBJECT", "NAME", "DESCRIPTION", "UNITS"}
+	rows := make([][]string, 0, len(entries))
+	for _, e := range entries {
+		rows = append(rows, []string{e.Object, e.Name, e.Description, e.Units})
+	}
+	f.PrintTable(headers, rows)
+	f.Printf("\n%d objects\n", len(entries))
+}
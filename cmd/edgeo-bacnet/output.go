@@ -15,9 +15,17 @@
 package main
 
 import (
+	"context"
+	"encoding/json"
 	"fmt"
 	"io"
 	"os"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/edgeo-scada/bacnet"
 )
 
 // OutputFormat represents output format types
@@ -28,8 +36,19 @@ const (
 	FormatJSON  OutputFormat = "json"
 	FormatCSV   OutputFormat = "csv"
 	FormatRaw   OutputFormat = "raw"
+	FormatYAML  OutputFormat = "yaml"
+
+	// FormatInflux is InfluxDB line protocol, for the commands that print
+	// values as they are read
+	FormatInflux OutputFormat = "influx"
+
+	// FormatTemplate applies the --template Go template to each record
+	FormatTemplate OutputFormat = "template"
 )
 
+// outputTemplate is the parsed --template of -o template
+var outputTemplate *template.Template
+
 // Formatter handles output formatting
 type Formatter struct {
 	format OutputFormat
@@ -115,3 +134,125 @@ func (f *Formatter) PrintKeyValue(pairs map[string]interface{}, order []string)
 		}
 	}
 }
+
+// lineProtocolOptions returns the options of the -o influx encoders: lines
+// are tagged with the site given by --site
+func lineProtocolOptions() []bacnet.LineProtocolOption {
+	if siteName == "" {
+		return nil
+	}
+	return []bacnet.LineProtocolOption{bacnet.WithLineTag("site", siteName)}
+}
+
+// newLineProtocolEncoder creates the encoder of -o influx, tagging the
+// values of the objects with their units, which are read up front
+func newLineProtocolEncoder(ctx context.Context, client *bacnet.Client, devID uint32, objectIDs ...bacnet.ObjectIdentifier) *bacnet.LineProtocolEncoder {
+	encoder := bacnet.NewLineProtocolEncoder(os.Stdout, lineProtocolOptions()...)
+	for _, objectID := range objectIDs {
+		unitsCtx, cancel := context.WithTimeout(ctx, timeout)
+		value, err := client.ReadProperty(unitsCtx, devID, objectID, bacnet.PropertyUnits)
+		cancel()
+
+		// Objects without units are tagged without
+		if units, ok := value.(uint32); ok && err == nil {
+			encoder.SetUnits(devID, objectID, bacnet.EngineeringUnits(units))
+		}
+	}
+	return encoder
+}
+
+// isStructuredOutput reports whether the output format is JSON or YAML,
+// which encodeOutput writes
+func isStructuredOutput() bool {
+	switch OutputFormat(outputFmt) {
+	case FormatJSON, FormatYAML:
+		return true
+	}
+	return false
+}
+
+// encodeOutput writes v as indented JSON, or as a YAML document with -o yaml
+func encodeOutput(w io.Writer, v interface{}) error {
+	if OutputFormat(outputFmt) == FormatYAML {
+		return encodeYAML(w, v)
+	}
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// encodeOutputRecord writes one record of a stream of values: a JSON line,
+// a YAML document with -o yaml, or a line of -o template
+func encodeOutputRecord(w io.Writer, v interface{}) error {
+	switch OutputFormat(outputFmt) {
+	case FormatTemplate:
+		return encodeTemplate(w, v)
+	case FormatYAML:
+		if _, err := io.WriteString(w, "---\n"); err != nil {
+			return err
+		}
+		return encodeYAML(w, v)
+	}
+	return json.NewEncoder(w).Encode(v)
+}
+
+// parseOutputTemplate parses the --template of -o template
+func parseOutputTemplate() error {
+	if OutputFormat(outputFmt) != FormatTemplate {
+		return nil
+	}
+	if templateText == "" {
+		return fmt.Errorf("-o template requires --template")
+	}
+	t, err := template.New("output").Parse(templateText)
+	if err != nil {
+		return fmt.Errorf("invalid template: %w", err)
+	}
+	outputTemplate = t
+	return nil
+}
+
+// encodeTemplate writes v through the --template, ending the output with
+// a newline when the template does not
+func encodeTemplate(w io.Writer, v interface{}) error {
+	var b strings.Builder
+	if err := outputTemplate.Execute(&b, v); err != nil {
+		return err
+	}
+	out := b.String()
+	if !strings.HasSuffix(out, "\n") {
+		out += "\n"
+	}
+	_, err := io.WriteString(w, out)
+	return err
+}
+
+// encodeYAML writes v as YAML. The value goes through its JSON encoding, so
+// the keys and their order are the same as with -o json.
+func encodeYAML(w io.Writer, v interface{}) error {
+	data, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return err
+	}
+	clearYAMLStyle(&doc)
+
+	encoder := yaml.NewEncoder(w)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(&doc); err != nil {
+		return err
+	}
+	return encoder.Close()
+}
+
+// clearYAMLStyle resets the flow style and quoting that nodes decoded from
+// JSON carry, so they are written in block style
+func clearYAMLStyle(node *yaml.Node) {
+	node.Style = 0
+	for _, child := range node.Content {
+		clearYAMLStyle(child)
+	}
+}
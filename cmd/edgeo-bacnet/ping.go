@@ -0,0 +1,229 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	pingCount    int
+	pingInterval time.Duration
+)
+
+var pingCmd = &cobra.Command{
+	Use:   "ping",
+	Short: "Check that a device answers and measure its round-trip time",
+	Long: `Ping locates a device with a Who-Is, directed to --host when given, reports
+its vendor and segmentation from the I-Am, then reads its object identifier
+repeatedly and prints the round-trip time of each read, like ICMP ping.
+
+Examples:
+  # Ping device 1234 four times
+  edgeo-bacnet ping -d 1234
+
+  # Ping the device at an address, whatever its instance
+  edgeo-bacnet ping -H 192.168.1.10
+
+  # Ping until interrupted, every 5 seconds
+  edgeo-bacnet ping -d 1234 -c 0 -i 5s`,
+
+	// Lost replies are not a usage error
+	SilenceUsage: true,
+
+	RunE: runPing,
+}
+
+func init() {
+	pingCmd.Flags().IntVarP(&pingCount, "count", "c", 4, "Number of pings (0 = until interrupted)")
+	pingCmd.Flags().DurationVarP(&pingInterval, "interval", "i", time.Second, "Interval between pings")
+}
+
+// PingSummary is the outcome of a ping run
+type PingSummary struct {
+	DeviceID     uint32  `json:"device_id"`
+	Address      string  `json:"address"`
+	VendorID     uint16  `json:"vendor_id"`
+	Segmentation string  `json:"segmentation"`
+	MaxAPDU      uint16  `json:"max_apdu"`
+	Sent         int     `json:"sent"`
+	Received     int     `json:"received"`
+	MinRTT       float64 `json:"min_rtt_ms"`
+	AvgRTT       float64 `json:"avg_rtt_ms"`
+	MaxRTT       float64 `json:"max_rtt_ms"`
+}
+
+func runPing(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 && host == "" {
+		return fmt.Errorf("device ID (-d) or host (-H) is required")
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	sigCh := make(chan os.Signal, 1)
+	signal.Notify(sigCh, syscall.SIGINT, syscall.SIGTERM)
+	go func() {
+		<-sigCh
+		cancel()
+	}()
+
+	dev, answered, err := locateDevice(ctx, client)
+	if err != nil {
+		return err
+	}
+
+	summary := PingSummary{
+		DeviceID:     dev.ObjectID.Instance,
+		Address:      formatAddress(dev.Address),
+		VendorID:     dev.VendorID,
+		Segmentation: dev.Segmentation.String(),
+		MaxAPDU:      dev.MaxAPDULength,
+	}
+	switch {
+	case isStructuredOutput():
+	case answered:
+		fmt.Printf("PING device %d (%s): vendor %d, %s, max APDU %d\n",
+			summary.DeviceID, summary.Address, summary.VendorID, summary.Segmentation, summary.MaxAPDU)
+	default:
+		fmt.Printf("PING device %d (%s): no I-Am\n", summary.DeviceID, summary.Address)
+	}
+
+	var total time.Duration
+	for seq := 1; pingCount == 0 || seq <= pingCount; seq++ {
+		if seq > 1 {
+			select {
+			case <-ctx.Done():
+			case <-time.After(pingInterval):
+			}
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		pingCtx, pingCancel := context.WithTimeout(ctx, operationDeadline(1))
+		rtt, err := client.Ping(pingCtx, summary.DeviceID)
+		pingCancel()
+		if ctx.Err() != nil {
+			break
+		}
+		summary.Sent++
+
+		if err != nil {
+			if !isStructuredOutput() {
+				fmt.Printf("device %d: seq=%d %v\n", summary.DeviceID, seq, err)
+			}
+			continue
+		}
+
+		ms := float64(rtt) / float64(time.Millisecond)
+		if summary.Received == 0 || ms < summary.MinRTT {
+			summary.MinRTT = ms
+		}
+		if ms > summary.MaxRTT {
+			summary.MaxRTT = ms
+		}
+		summary.Received++
+		total += rtt
+
+		if !isStructuredOutput() {
+			fmt.Printf("reply from device %d: seq=%d time=%.1f ms\n", summary.DeviceID, seq, ms)
+		}
+	}
+	if summary.Received > 0 {
+		summary.AvgRTT = float64(total) / float64(summary.Received) / float64(time.Millisecond)
+	}
+
+	if isStructuredOutput() {
+		if err := encodeOutput(os.Stdout, summary); err != nil {
+			return err
+		}
+	} else {
+		loss := 0.0
+		if summary.Sent > 0 {
+			loss = float64(summary.Sent-summary.Received) * 100 / float64(summary.Sent)
+		}
+		fmt.Printf("\n--- device %d ping statistics ---\n", summary.DeviceID)
+		fmt.Printf("%d sent, %d received, %.0f%% loss\n", summary.Sent, summary.Received, loss)
+		if summary.Received > 0 {
+			fmt.Printf("rtt min/avg/max = %.1f/%.1f/%.1f ms\n", summary.MinRTT, summary.AvgRTT, summary.MaxRTT)
+		}
+	}
+
+	if summary.Sent > 0 && summary.Received == 0 {
+		return fmt.Errorf("no reply from device %d", summary.DeviceID)
+	}
+	return nil
+}
+
+// locateDevice finds the device to ping with a Who-Is, directed to the host
+// when given, and reports whether it answered. A device at a given host that
+// does not answer Who-Is is bound to it, without I-Am information.
+func locateDevice(ctx context.Context, client *bacnet.Client) (*bacnet.DeviceInfo, bool, error) {
+	opts := []bacnet.DiscoverOption{bacnet.WithDiscoveryTimeout(timeout)}
+	if deviceID != 0 {
+		opts = append(opts, bacnet.WithDeviceRange(deviceID, deviceID))
+	}
+	address := ""
+	if host != "" {
+		address = fmt.Sprintf("%s:%d", host, port)
+		opts = append(opts, bacnet.WithDiscoveryAddress(address))
+	}
+
+	devices, err := client.WhoIs(ctx, opts...)
+	if err != nil {
+		return nil, false, fmt.Errorf("who-is: %w", err)
+	}
+
+	for _, dev := range devices {
+		if deviceID != 0 && dev.ObjectID.Instance == deviceID {
+			return dev, true, nil
+		}
+		if deviceID == 0 && formatAddress(dev.Address) == address {
+			return dev, true, nil
+		}
+	}
+
+	if deviceID != 0 && address != "" {
+		if err := client.BindDevice(deviceID, address); err != nil {
+			return nil, false, err
+		}
+		dev, _ := client.GetDevice(deviceID)
+		return dev, false, nil
+	}
+	if deviceID != 0 {
+		return nil, false, fmt.Errorf("device %d did not answer Who-Is", deviceID)
+	}
+	return nil, false, fmt.Errorf("no device answered Who-Is at %s", address)
+}
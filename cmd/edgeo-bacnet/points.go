@@ -0,0 +1,483 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var pointsNamespace string
+
+var pointsCmd = &cobra.Command{
+	Use:   "points <dump>",
+	Short: "Export a points list with Haystack and Brick tags from a dump",
+	Long: `Points converts a device dump, a JSON file written by dump or an EPICS
+file, into a points list for analytics platforms. Each analog, binary and
+multi-state object becomes a point with Project Haystack marker tags and
+a Brick class, inferred from its object type, units and name.
+
+Names are split into words, "ZoneTemp" and "zone_temp" alike, and
+abbreviations common in building automation are recognized: SAT is a
+discharge air temperature, SP a setpoint, CMD a command.
+
+-o turtle writes the points as a Brick model in RDF Turtle, each point
+referencing its BACnet object.
+
+Examples:
+  # List the points of a dump with their tags
+  edgeo-bacnet dump -d 1234 -o json -f device.json
+  edgeo-bacnet points device.json
+
+  # Export for an analytics platform
+  edgeo-bacnet points device.json -o csv > points.csv
+
+  # Export a Brick model
+  edgeo-bacnet points device.json -o turtle --namespace http://example.com/building#`,
+
+	Args: cobra.ExactArgs(1),
+
+	RunE: runPoints,
+}
+
+func init() {
+	pointsCmd.Flags().StringVar(&pointsNamespace, "namespace", "urn:edgeo-bacnet:", "Namespace of the Brick model entities")
+}
+
+// Point is a point of a points list
+type Point struct {
+	ID          string   `json:"id"`
+	DeviceID    uint32   `json:"device_id"`
+	Object      string   `json:"object"`
+	Name        string   `json:"name"`
+	Description string   `json:"description,omitempty"`
+	Unit        string   `json:"unit,omitempty"`
+	Kind        string   `json:"kind"`
+	Tags        []string `json:"tags"`
+	BrickClass  string   `json:"brick_class"`
+
+	qudt string
+}
+
+// pointKind is the Haystack kind and role of the points of an object type
+type pointKind struct {
+	kind string
+	role string
+}
+
+var pointKinds = map[bacnet.ObjectType]pointKind{
+	bacnet.ObjectTypeAnalogInput:      {"Number", "sensor"},
+	bacnet.ObjectTypeAnalogOutput:     {"Number", "cmd"},
+	bacnet.ObjectTypeAnalogValue:      {"Number", "sp"},
+	bacnet.ObjectTypeBinaryInput:      {"Bool", "sensor"},
+	bacnet.ObjectTypeBinaryOutput:     {"Bool", "cmd"},
+	bacnet.ObjectTypeBinaryValue:      {"Bool", "sp"},
+	bacnet.ObjectTypeMultiStateInput:  {"Str", "sensor"},
+	bacnet.ObjectTypeMultiStateOutput: {"Str", "cmd"},
+	bacnet.ObjectTypeMultiStateValue:  {"Str", "sp"},
+}
+
+// pointUnit is the Haystack symbol, QUDT name and Haystack quantity tag of
+// engineering units
+type pointUnit struct {
+	symbol   string
+	qudt     string
+	quantity string
+}
+
+var pointUnits = map[bacnet.EngineeringUnits]pointUnit{
+	bacnet.UnitsDegreesCelsius:           {"°C", "DEG_C", "temp"},
+	bacnet.UnitsDegreesFahrenheit:        {"°F", "DEG_F", "temp"},
+	bacnet.UnitsDegreesKelvin:            {"K", "K", "temp"},
+	bacnet.UnitsPercentRelativeHumidity:  {"%RH", "PERCENT_RH", "humidity"},
+	bacnet.UnitsPercent:                  {"%", "PERCENT", ""},
+	bacnet.UnitsPascals:                  {"Pa", "PA", "pressure"},
+	bacnet.UnitsKilopascals:              {"kPa", "KiloPA", "pressure"},
+	bacnet.UnitsBars:                     {"bar", "BAR", "pressure"},
+	bacnet.UnitsPoundsForcePerSquareInch: {"psi", "PSI", "pressure"},
+	bacnet.UnitsInchesOfWater:            {"inH₂O", "IN_H2O", "pressure"},
+	bacnet.UnitsWatts:                    {"W", "W", "power"},
+	bacnet.UnitsKilowatts:                {"kW", "KiloW", "power"},
+	bacnet.UnitsMegawatts:                {"MW", "MegaW", "power"},
+	bacnet.UnitsWattHours:                {"Wh", "W-HR", "energy"},
+	bacnet.UnitsKilowattHours:            {"kWh", "KiloW-HR", "energy"},
+	bacnet.UnitsJoules:                   {"J", "J", "energy"},
+	bacnet.UnitsKilojoules:               {"kJ", "KiloJ", "energy"},
+	bacnet.UnitsBtus:                     {"BTU", "BTU_IT", "energy"},
+	bacnet.UnitsCubicFeetPerMinute:       {"cfm", "FT3-PER-MIN", "flow"},
+	bacnet.UnitsLitersPerSecond:          {"L/s", "L-PER-SEC", "flow"},
+	bacnet.UnitsLitersPerMinute:          {"L/min", "L-PER-MIN", "flow"},
+	bacnet.UnitsCubicMetersPerSecond:     {"m³/s", "M3-PER-SEC", "flow"},
+	bacnet.UnitsUsGallonsPerMinute:       {"gal/min", "GAL_US-PER-MIN", "flow"},
+	bacnet.UnitsRevolutionsPerMinute:     {"rpm", "REV-PER-MIN", "speed"},
+	bacnet.UnitsVolts:                    {"V", "V", "volt"},
+	bacnet.UnitsAmperes:                  {"A", "A", "current"},
+	bacnet.UnitsMilliamperes:             {"mA", "MilliA", ""},
+	bacnet.UnitsHertz:                    {"Hz", "HZ", "freq"},
+	bacnet.UnitsPartsPerMillion:          {"ppm", "PPM", ""},
+	bacnet.UnitsMetersPerSecond:          {"m/s", "M-PER-SEC", ""},
+	bacnet.UnitsSeconds:                  {"s", "SEC", ""},
+	bacnet.UnitsMinutes:                  {"min", "MIN", ""},
+	bacnet.UnitsHours:                    {"h", "HR", ""},
+}
+
+// pointWords maps the words of point names to Haystack tags. The roles
+// sensor, sp and cmd override the role of the object type.
+var pointWords = map[string]string{
+	"temp": "temp", "temperature": "temp", "tmp": "temp",
+	"humidity": "humidity", "humid": "humidity", "rh": "humidity",
+	"co2":      "co2",
+	"pressure": "pressure", "press": "pressure", "static": "pressure",
+	"flow": "flow", "airflow": "flow", "cfm": "flow",
+	"fan": "fan", "pump": "pump",
+	"damper": "damper", "dmp": "damper", "dpr": "damper",
+	"valve": "valve", "vlv": "valve",
+	"zone": "zone", "zn": "zone", "room": "zone", "space": "zone",
+	"supply": "discharge", "discharge": "discharge", "sa": "discharge", "da": "discharge",
+	"return": "return", "ra": "return",
+	"outside": "outside", "outdoor": "outside", "oa": "outside",
+	"mixed": "mixed", "ma": "mixed",
+	"water": "water", "chw": "water", "hw": "water", "cw": "water",
+	"occ": "occ", "occupied": "occ", "occupancy": "occ",
+	"alarm": "alarm", "alm": "alarm",
+	"fault": "fault", "flt": "fault",
+	"enable": "enable", "en": "enable", "enb": "enable",
+	"run": "run", "running": "run",
+	"speed": "speed", "spd": "speed", "vfd": "speed",
+	"power": "power", "kw": "power",
+	"energy": "energy", "kwh": "energy",
+	"status": "sensor", "sts": "sensor", "stat": "sensor",
+	"cmd": "cmd", "command": "cmd", "ss": "cmd",
+	"sp": "sp", "setpoint": "sp", "stpt": "sp", "spt": "sp",
+}
+
+// pointAbbreviations maps the abbreviations that stand for several words
+var pointAbbreviations = map[string][]string{
+	"sat": {"discharge", "temp"}, "dat": {"discharge", "temp"},
+	"rat": {"return", "temp"},
+	"oat": {"outside", "temp"},
+	"mat": {"mixed", "temp"},
+	"zat": {"zone", "temp"},
+}
+
+// pointAirLocations are the locations of air whose quantities get the air tag
+var pointAirLocations = map[string]string{
+	"discharge": "Discharge_Air_",
+	"zone":      "Zone_Air_",
+	"return":    "Return_Air_",
+	"outside":   "Outside_Air_",
+	"mixed":     "Mixed_Air_",
+}
+
+// pointQuantities maps the Haystack quantity tags to Brick quantities
+var pointQuantities = map[string]string{
+	"temp":     "Temperature",
+	"humidity": "Humidity",
+	"pressure": "Pressure",
+	"flow":     "Flow",
+	"co2":      "CO2",
+	"power":    "Power",
+	"energy":   "Energy",
+	"speed":    "Speed",
+	"volt":     "Voltage",
+	"current":  "Current",
+	"freq":     "Frequency",
+}
+
+func runPoints(cmd *cobra.Command, args []string) error {
+	snapshot, err := bacnet.ReadSnapshotFile(args[0])
+	if err != nil {
+		return err
+	}
+
+	points := tagPoints(snapshot)
+
+	switch outputFmt {
+	case "json", "yaml":
+		return encodeOutput(os.Stdout, points)
+	case "csv":
+		return outputPointsCSV(os.Stdout, points)
+	case "turtle":
+		return outputPointsTurtle(os.Stdout, snapshot.DeviceID, points)
+	case "template":
+		for _, p := range points {
+			if err := encodeTemplate(os.Stdout, p); err != nil {
+				return err
+			}
+		}
+		return nil
+	default:
+		outputPointsTable(points)
+		return nil
+	}
+}
+
+// tagPoints returns the points of the objects of a snapshot, in order
+func tagPoints(snapshot *bacnet.DeviceSnapshot) []Point {
+	points := make([]Point, 0, len(snapshot.Objects))
+	for _, obj := range snapshot.Objects {
+		objectType, ok := bacnet.ParseObjectType(obj.ObjectType)
+		if !ok {
+			continue
+		}
+		kind, ok := pointKinds[objectType]
+		if !ok {
+			continue
+		}
+
+		p := Point{
+			ID:       fmt.Sprintf("device-%d-%s-%d", snapshot.DeviceID, objectType, obj.Instance),
+			DeviceID: snapshot.DeviceID,
+			Object:   obj.ObjectID,
+			Kind:     kind.kind,
+		}
+		if name, ok := obj.Properties["object-name"].(string); ok {
+			p.Name = name
+		}
+		if desc, ok := obj.Properties["description"].(string); ok {
+			p.Description = desc
+		}
+
+		tags := map[string]bool{"point": true}
+		role := kind.role
+		for _, word := range pointNameWords(p.Name) {
+			for _, tag := range pointWordTags(word) {
+				switch tag {
+				case "sensor", "sp", "cmd":
+					role = tag
+				default:
+					tags[tag] = true
+				}
+			}
+		}
+		if units, ok := pointSnapshotUnits(obj.Properties["units"]); ok {
+			if u, ok := pointUnits[units]; ok {
+				p.Unit, p.qudt = u.symbol, u.qudt
+				if u.quantity != "" {
+					tags[u.quantity] = true
+				}
+			}
+		}
+		tags[role] = true
+		if tags["volt"] || tags["current"] {
+			tags["elec"] = true
+		}
+
+		quantity := ""
+		for tag := range tags {
+			if _, ok := pointQuantities[tag]; ok && (quantity == "" || tag < quantity) {
+				quantity = tag
+			}
+		}
+		location := ""
+		for tag := range tags {
+			if _, ok := pointAirLocations[tag]; ok && (location == "" || tag < location) {
+				location = tag
+			}
+		}
+		if location != "" && (quantity == "temp" || quantity == "humidity" || quantity == "pressure" || quantity == "flow" || quantity == "co2") {
+			tags["air"] = true
+		}
+
+		p.BrickClass = brickClass(tags, role, quantity, location, kind.kind)
+		for tag := range tags {
+			p.Tags = append(p.Tags, tag)
+		}
+		sort.Strings(p.Tags)
+		points = append(points, p)
+	}
+	return points
+}
+
+// pointNameWords splits a point name into lowercase words at spaces,
+// punctuation, and changes from lowercase to uppercase
+func pointNameWords(name string) []string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) > 0 {
+			words = append(words, strings.ToLower(string(word)))
+			word = word[:0]
+		}
+	}
+	var prev rune
+	for _, r := range name {
+		switch {
+		case !unicode.IsLetter(r) && !unicode.IsDigit(r):
+			flush()
+		case unicode.IsUpper(r) && unicode.IsLower(prev):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+		prev = r
+	}
+	flush()
+	return words
+}
+
+// pointWordTags returns the tags of a word of a point name
+func pointWordTags(word string) []string {
+	if tags, ok := pointAbbreviations[word]; ok {
+		return tags
+	}
+	if tag, ok := pointWords[word]; ok {
+		return []string{tag}
+	}
+	return nil
+}
+
+// pointSnapshotUnits returns the units of a snapshot, a number in a dump
+// or a symbol in an EPICS file
+func pointSnapshotUnits(v interface{}) (bacnet.EngineeringUnits, bool) {
+	switch v := v.(type) {
+	case float64:
+		return bacnet.EngineeringUnits(v), true
+	case string:
+		units, err := parseEngineeringUnits(v)
+		return units, err == nil
+	}
+	return 0, false
+}
+
+// brickClass infers the Brick class of a point from its Haystack tags
+func brickClass(tags map[string]bool, role, quantity, location, kind string) string {
+	suffix := map[string]string{"sensor": "_Sensor", "sp": "_Setpoint", "cmd": "_Command"}[role]
+
+	switch {
+	case tags["alarm"]:
+		return "Alarm"
+	case tags["fault"]:
+		return "Fault_Status"
+	case tags["occ"]:
+		if role == "sensor" {
+			return "Occupancy_Sensor"
+		}
+		return "Occupancy_Command"
+	case tags["enable"]:
+		if role == "sensor" {
+			return "Enable_Status"
+		}
+		return "Enable_Command"
+	case tags["run"] || kind == "Bool" && (tags["fan"] || tags["pump"]):
+		if role == "sensor" {
+			return "Run_Status"
+		}
+		return "Start_Stop_Command"
+	case tags["damper"] && quantity == "":
+		if role == "sensor" {
+			return "Damper_Position_Sensor"
+		}
+		return "Damper_Position_Command"
+	case tags["valve"] && quantity == "":
+		if role == "sensor" {
+			return "Valve_Position_Sensor"
+		}
+		return "Valve_Position_Command"
+	case quantity != "":
+		prefix := ""
+		if tags["air"] {
+			prefix = pointAirLocations[location]
+		}
+		return prefix + pointQuantities[quantity] + suffix
+	case kind == "Bool" && role == "sensor":
+		return "Status"
+	default:
+		return strings.TrimPrefix(suffix, "_")
+	}
+}
+
+func outputPointsCSV(w io.Writer, points []Point) error {
+	writer := csv.NewWriter(w)
+	defer writer.Flush()
+
+	writer.Write([]string{"id", "device_id", "object", "name", "description", "unit", "kind", "tags", "brick_class"})
+	for _, p := range points {
+		writer.Write([]string{p.ID, fmt.Sprint(p.DeviceID), p.Object, p.Name, p.Description, p.Unit, p.Kind, strings.Join(p.Tags, " "), p.BrickClass})
+	}
+
+	return writer.Error()
+}
+
+// outputPointsTurtle writes the points as a Brick model, each point with a
+// BACnet reference to its object
+func outputPointsTurtle(w io.Writer, device uint32, points []Point) error {
+	var b strings.Builder
+	b.WriteString("@prefix bacnet: <http://data.ashrae.org/bacnet/2020#> .\n")
+	b.WriteString("@prefix brick: <https://brickschema.org/schema/Brick#> .\n")
+	b.WriteString("@prefix rdfs: <http://www.w3.org/2000/01/rdf-schema#> .\n")
+	b.WriteString("@prefix ref: <https://brickschema.org/schema/Brick/ref#> .\n")
+	b.WriteString("@prefix unit: <http://qudt.org/vocab/unit/> .\n")
+	fmt.Fprintf(&b, "@prefix bldg: <%s> .\n\n", pointsNamespace)
+
+	fmt.Fprintf(&b, "bldg:device-%d a bacnet:BACnetDevice ;\n", device)
+	fmt.Fprintf(&b, "    bacnet:device-instance %d .\n", device)
+
+	for _, p := range points {
+		objectType, instance := p.Object, ""
+		if i := strings.LastIndex(p.Object, ":"); i >= 0 {
+			objectType, instance = p.Object[:i], p.Object[i+1:]
+		}
+
+		fmt.Fprintf(&b, "\nbldg:%s a brick:%s ;\n", p.ID, p.BrickClass)
+		fmt.Fprintf(&b, "    rdfs:label %s ;\n", turtleString(p.Name))
+		if p.Description != "" {
+			fmt.Fprintf(&b, "    rdfs:comment %s ;\n", turtleString(p.Description))
+		}
+		if p.qudt != "" {
+			fmt.Fprintf(&b, "    brick:hasUnit unit:%s ;\n", p.qudt)
+		}
+		b.WriteString("    ref:hasExternalReference [\n")
+		b.WriteString("        a ref:BACnetReference ;\n")
+		fmt.Fprintf(&b, "        bacnet:object-identifier %s ;\n", turtleString(objectType+","+instance))
+		fmt.Fprintf(&b, "        bacnet:objectOf bldg:device-%d\n", device)
+		b.WriteString("    ] .\n")
+	}
+
+	_, err := io.WriteString(w, b.String())
+	return err
+}
+
+// turtleString quotes a Turtle string literal
+func turtleString(s string) string {
+	r := strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`, "\r", `\r`, "\t", `\t`)
+	return `"` + r.Replace(s) + `"`
+}
+
+func outputPointsTable(points []Point) {
+	f := NewFormatter(outputFmt)
+
+	if len(points) == 0 {
+		f.Println("No points")
+		return
+	}
+
+	headers := []string{"OBJECT", "NAME", "UNIT", "KIND", "TAGS", "BRICK CLASS"}
+	rows := make([][]string, 0, len(points))
+	for _, p := range points {
+		rows = append(rows, []string{p.Object, p.Name, p.Unit, p.Kind, strings.Join(p.Tags, " "), p.BrickClass})
+	}
+	f.PrintTable(headers, rows)
+}
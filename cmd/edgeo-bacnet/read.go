@@ -16,7 +16,9 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
+	"os"
 	"strconv"
 	"strings"
 
@@ -126,14 +128,15 @@ func runRead(cmd *cobra.Command, args []string) error {
 
 	// Output result
 	switch outputFmt {
-	case "json":
+	case "json", "yaml", "template":
 		return outputValueJSON(objectID, propID, value)
 	case "csv":
 		return outputValueCSV(objectID, propID, value)
 	case "raw":
 		fmt.Println(formatValue(value))
 	default:
-		return outputValueTable(objectID, propID, value)
+		texts := readStateTexts(ctx, client, deviceID, objectID, propID)
+		return outputValueTable(objectID, propID, formatDisplayValue(value, texts))
 	}
 
 	return nil
@@ -208,36 +211,57 @@ func formatValue(value interface{}) string {
 	}
 }
 
-func outputValueTable(objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier, value interface{}) error {
+// readStateTexts returns the state texts used to display a property, or nil
+// when the property does not hold a named state or the texts cannot be read
+func readStateTexts(ctx context.Context, client *bacnet.Client, devID uint32, objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier) *bacnet.StateTexts {
+	switch propID {
+	case bacnet.PropertyPresentValue, bacnet.PropertyRelinquishDefault:
+	default:
+		return nil
+	}
+	texts, err := client.ReadStateTexts(ctx, devID, objectID)
+	if err != nil {
+		return nil
+	}
+	return texts
+}
+
+// formatDisplayValue formats a value followed by its state name, e.g.
+// "2 (Occupied)"
+func formatDisplayValue(value interface{}, texts *bacnet.StateTexts) string {
+	if texts != nil {
+		if name, ok := texts.Resolve(value); ok {
+			return fmt.Sprintf("%s (%s)", formatValue(value), name)
+		}
+	}
+	return formatValue(value)
+}
+
+func outputValueTable(objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier, display string) error {
 	fmt.Printf("Object:   %s\n", objectID.String())
 	fmt.Printf("Property: %s\n", propID.String())
-	fmt.Printf("Value:    %s\n", formatValue(value))
+	fmt.Printf("Value:    %s\n", display)
 	return nil
 }
 
-func outputValueJSON(objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier, value interface{}) error {
-	valStr := formatValue(value)
-
-	// Quote strings
-	switch value.(type) {
-	case string:
-		valStr = fmt.Sprintf("%q", valStr)
-	case nil:
-		valStr = "null"
-	case bool:
-		// Already formatted
-	case bacnet.ObjectIdentifier:
-		valStr = fmt.Sprintf("%q", valStr)
-	default:
-		// Numbers don't need quotes
-	}
+// ReadOutput is the JSON output of read
+type ReadOutput struct {
+	Object   string      `json:"object"`
+	Property string      `json:"property"`
+	Value    interface{} `json:"value"`
+}
 
-	fmt.Printf(`{"object": "%s", "property": "%s", "value": %s}`+"\n",
-		objectID.String(), propID.String(), valStr)
-	return nil
+func outputValueJSON(objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier, value interface{}) error {
+	return encodeOutputRecord(os.Stdout, ReadOutput{
+		Object:   objectID.String(),
+		Property: propID.String(),
+		Value:    formatValueForDump(value),
+	})
 }
 
 func outputValueCSV(objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier, value interface{}) error {
-	fmt.Printf("%s,%s,%s\n", objectID.String(), propID.String(), formatValue(value))
-	return nil
+	writer := csv.NewWriter(os.Stdout)
+	writer.Write([]string{objectID.String(), propID.String(), formatValue(value)})
+	writer.Flush()
+	return writer.Error()
 }
@@ -0,0 +1,334 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	readmObjects    []string
+	readmProperties []string
+	readmPointsFile string
+)
+
+var readmCmd = &cobra.Command{
+	Use:   "readm",
+	Short: "Read several object properties in one request",
+	Long: `Readm reads a batch of object/property pairs with ReadPropertyMultiple,
+falling back to one ReadProperty per point on devices without it, and
+prints every result, including the error of each point that failed.
+
+The points are the -O objects, each read for every -P property, and the
+points of a --points file. A CSV points file lists object,property[,index]
+per line; a YAML, JSON or TOML file lists them under points:
+
+  points:
+    - object: analog-input:1
+      property: present-value
+    - object: device:1234
+      property: object-list
+      index: 0
+
+Examples:
+  # Present value of three objects
+  edgeo-bacnet readm -d 1234 -O ai:1 -O ai:2 -O av:3
+
+  # Name and value of two objects
+  edgeo-bacnet readm -d 1234 -O ai:1 -O ai:2 -P object-name,pv
+
+  # Points from a file, as JSON
+  edgeo-bacnet readm -d 1234 --points points.csv -o json`,
+
+	// Failed points are not a usage error
+	SilenceUsage: true,
+
+	RunE: runReadm,
+}
+
+func init() {
+	readmCmd.Flags().StringArrayVarP(&readmObjects, "object", "O", nil, "Object to read (repeatable, e.g. ai:1)")
+	readmCmd.Flags().StringSliceVarP(&readmProperties, "property", "P", []string{"present-value"}, "Properties to read of each -O object")
+	readmCmd.Flags().StringVar(&readmPointsFile, "points", "", "Points file (CSV, YAML, JSON or TOML)")
+}
+
+// ReadPoint is an object property listed in a points file
+type ReadPoint struct {
+	Object   string  `mapstructure:"object"`
+	Property string  `mapstructure:"property"`
+	Index    *uint32 `mapstructure:"index"`
+}
+
+// ReadmResult is the outcome of reading one point
+type ReadmResult struct {
+	Object   string      `json:"object"`
+	Property string      `json:"property"`
+	Index    *uint32     `json:"index,omitempty"`
+	Value    interface{} `json:"value,omitempty"`
+	Error    string      `json:"error,omitempty"`
+}
+
+func runReadm(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	requests, err := pointRequests(readmObjects, readmProperties, readmPointsFile)
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		return fmt.Errorf("no points to read (-O or --points)")
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(len(requests)+1))
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	results, err := client.Device(deviceID).ReadMultiple(ctx, requests)
+	if err != nil {
+		return fmt.Errorf("read: %w", err)
+	}
+
+	// Results are matched to the requests, which the device may answer in
+	// another order or in part
+	type point struct {
+		objectID   bacnet.ObjectIdentifier
+		propertyID bacnet.PropertyIdentifier
+		index      uint32
+	}
+	key := func(o bacnet.ObjectIdentifier, p bacnet.PropertyIdentifier, index *uint32) point {
+		k := point{objectID: o, propertyID: p, index: ^uint32(0)}
+		if index != nil {
+			k.index = *index
+		}
+		return k
+	}
+	byPoint := make(map[point]bacnet.PropertyResult, len(results))
+	for _, r := range results {
+		byPoint[key(r.ObjectID, r.PropertyID, r.ArrayIndex)] = r
+	}
+
+	out := make([]ReadmResult, len(requests))
+	failed := 0
+	for i, req := range requests {
+		res := ReadmResult{Object: req.ObjectID.String(), Property: req.PropertyID.String(), Index: req.ArrayIndex}
+		r, ok := byPoint[key(req.ObjectID, req.PropertyID, req.ArrayIndex)]
+		switch {
+		case !ok:
+			res.Error = "not returned"
+		case r.Err != nil:
+			res.Error = r.Err.Error()
+		default:
+			res.Value = formatValueForDump(r.Value)
+		}
+		if res.Error != "" {
+			failed++
+		}
+		out[i] = res
+	}
+
+	switch outputFmt {
+	case "json", "yaml":
+		err = encodeOutput(os.Stdout, out)
+	case "csv":
+		err = outputReadmCSV(out)
+	default:
+		outputReadmTable(out)
+	}
+	if err != nil {
+		return err
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%d of %d points failed", failed, len(out))
+	}
+	return nil
+}
+
+// pointRequests builds the requests of the objects, each read for every
+// property, and of the points file
+func pointRequests(objects, properties []string, pointsFile string) ([]bacnet.ReadPropertyRequest, error) {
+	var requests []bacnet.ReadPropertyRequest
+
+	props := make([]bacnet.PropertyIdentifier, 0, len(properties))
+	for _, s := range properties {
+		prop, err := parsePropertyIdentifier(s)
+		if err != nil {
+			return nil, err
+		}
+		props = append(props, prop)
+	}
+	for _, s := range objects {
+		objectID, err := parseObjectIdentifier(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid object %q: %w", s, err)
+		}
+		for _, prop := range props {
+			requests = append(requests, bacnet.ReadPropertyRequest{ObjectID: objectID, PropertyID: prop})
+		}
+	}
+
+	if pointsFile != "" {
+		points, err := loadReadPoints(pointsFile)
+		if err != nil {
+			return nil, err
+		}
+		for i, p := range points {
+			objectID, err := parseObjectIdentifier(p.Object)
+			if err != nil {
+				return nil, fmt.Errorf("point %d: invalid object %q: %w", i+1, p.Object, err)
+			}
+			property := p.Property
+			if property == "" {
+				property = "present-value"
+			}
+			prop, err := parsePropertyIdentifier(property)
+			if err != nil {
+				return nil, fmt.Errorf("point %d: %w", i+1, err)
+			}
+			requests = append(requests, bacnet.ReadPropertyRequest{ObjectID: objectID, PropertyID: prop, ArrayIndex: p.Index})
+		}
+	}
+
+	return requests, nil
+}
+
+// loadReadPoints reads a CSV, YAML, JSON or TOML points file
+func loadReadPoints(path string) ([]ReadPoint, error) {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return loadReadPointsCSV(path)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read points: %w", err)
+	}
+
+	var file struct {
+		Points []ReadPoint `mapstructure:"points"`
+	}
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("parse points: %w", err)
+	}
+	return file.Points, nil
+}
+
+// loadReadPointsCSV reads object,property[,index] lines, skipping a header
+// line and # comments
+func loadReadPointsCSV(path string) ([]ReadPoint, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read points: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var points []ReadPoint
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse points: %w", err)
+		}
+		if len(points) == 0 && strings.EqualFold(record[0], "object") {
+			continue
+		}
+
+		p := ReadPoint{Object: record[0]}
+		if len(record) > 1 {
+			p.Property = record[1]
+		}
+		if len(record) > 2 && record[2] != "" {
+			index, err := strconv.ParseUint(record[2], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("parse points: invalid index %q", record[2])
+			}
+			i := uint32(index)
+			p.Index = &i
+		}
+		points = append(points, p)
+	}
+	return points, nil
+}
+
+func outputReadmCSV(results []ReadmResult) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	writer.Write([]string{"object", "property", "index", "value", "error"})
+	for _, r := range results {
+		writer.Write([]string{r.Object, r.Property, readmIndex(r.Index), readmValue(r), r.Error})
+	}
+
+	return writer.Error()
+}
+
+func outputReadmTable(results []ReadmResult) {
+	f := NewFormatter(outputFmt)
+
+	headers := []string{"OBJECT", "PROPERTY", "INDEX", "VALUE"}
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		value := readmValue(r)
+		if r.Error != "" {
+			value = "error: " + r.Error
+		}
+		rows = append(rows, []string{r.Object, r.Property, readmIndex(r.Index), value})
+	}
+	f.PrintTable(headers, rows)
+}
+
+func readmIndex(index *uint32) string {
+	if index == nil {
+		return ""
+	}
+	return strconv.FormatUint(uint64(*index), 10)
+}
+
+func readmValue(r ReadmResult) string {
+	if r.Error != "" {
+		return ""
+	}
+	return formatValue(r.Value)
+}
@@ -0,0 +1,98 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	reinitPassword string
+	reinitYes      bool
+)
+
+var reinitCmd = &cobra.Command{
+	Use:   "reinit <warmstart|coldstart|activate-changes>",
+	Short: "Restart a device",
+	Long: `Reinit sends a ReinitializeDevice request: warmstart restarts the device
+keeping its configuration, coldstart restarts it as after a power-up, and
+activate-changes applies pending network port changes.
+
+Asks for a confirmation unless --yes is given.
+
+Examples:
+  edgeo-bacnet reinit -d 1234 warmstart --password secret
+  edgeo-bacnet reinit -d 1234 coldstart --password secret --yes`,
+	Args:      cobra.ExactArgs(1),
+	ValidArgs: []string{"warmstart", "coldstart", "activate-changes"},
+
+	// A cancelled confirmation is not a usage error
+	SilenceUsage: true,
+
+	RunE: runReinit,
+}
+
+func init() {
+	reinitCmd.Flags().StringVar(&reinitPassword, "password", "", "Password of the device")
+	reinitCmd.Flags().BoolVarP(&reinitYes, "yes", "y", false, "Do not ask for a confirmation")
+}
+
+func runReinit(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	var state bacnet.ReinitializedState
+	switch strings.ToLower(args[0]) {
+	case "warmstart":
+		state = bacnet.ReinitWarmstart
+	case "coldstart":
+		state = bacnet.ReinitColdstart
+	case "activate-changes":
+		state = bacnet.ReinitActivateChanges
+	default:
+		return fmt.Errorf("unknown state %q (expected warmstart, coldstart or activate-changes)", args[0])
+	}
+
+	if !reinitYes && !confirm(fmt.Sprintf("Send %s to device %d?", state, deviceID)) {
+		return fmt.Errorf("cancelled")
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(1))
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	if err := client.ReinitializeDevice(ctx, deviceID, state, reinitPassword); err != nil {
+		return fmt.Errorf("reinitialize device: %w", err)
+	}
+
+	fmt.Printf("Device %d: %s\n", deviceID, state)
+	return nil
+}
@@ -0,0 +1,278 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var rolloutNoRollback bool
+
+var rolloutCmd = &cobra.Command{
+	Use:   "rollout <set> <schedule-file>",
+	Short: "Write one weekly schedule to a set of schedule objects",
+	Long: `Rollout writes the weekly schedule in a file to every schedule object of
+a named set from the "schedule-sets" section of the config file:
+
+  schedule-sets:
+    ahu-occupancy:
+      - device: 1001
+        object: schedule:1
+      - device: 1002
+        object: schedule:1
+
+The schedule file lists time-values per day; "weekdays" and "weekend"
+apply to several days and are overridden by individual days:
+
+  type: enumerated   # real, unsigned, signed, enumerated or boolean
+  weekdays:
+    - { time: "06:00", value: 1 }
+    - { time: "18:00", value: 0 }
+  friday:
+    - { time: "06:00", value: 1 }
+    - { time: "16:00", value: 0 }
+
+The current schedules are read first. If any object fails, the objects
+already written are restored unless --no-rollback is given.
+
+Examples:
+  # Roll out the summer schedule
+  edgeo-bacnet rollout ahu-occupancy summer.yaml --config site.yaml`,
+
+	Args: cobra.ExactArgs(2),
+
+	// Failed targets are not a usage error
+	SilenceUsage: true,
+
+	RunE: runRollout,
+}
+
+func init() {
+	rolloutCmd.Flags().BoolVar(&rolloutNoRollback, "no-rollback", false, "Keep the written schedules when some objects fail")
+}
+
+// scheduleTargetConfig is a schedule object entry of a schedule set
+type scheduleTargetConfig struct {
+	Device uint32 `mapstructure:"device"`
+	Object string `mapstructure:"object"`
+}
+
+// timeValueConfig is a time-value entry of a schedule file
+type timeValueConfig struct {
+	Time  string `mapstructure:"time" json:"time"`
+	Value string `mapstructure:"value" json:"value"`
+}
+
+// scheduleDays are the schedule file keys of each WeeklySchedule day
+var scheduleDays = []string{"monday", "tuesday", "wednesday", "thursday", "friday", "saturday", "sunday"}
+
+func runRollout(cmd *cobra.Command, args []string) error {
+	var configs []scheduleTargetConfig
+	if err := viper.UnmarshalKey("schedule-sets."+args[0], &configs); err != nil {
+		return fmt.Errorf("parse schedule set: %w", err)
+	}
+	if len(configs) == 0 {
+		return fmt.Errorf("schedule set %q not found or empty", args[0])
+	}
+
+	targets := make([]bacnet.ScheduleTarget, 0, len(configs))
+	for i, cfg := range configs {
+		objectID, err := parseObjectIdentifier(cfg.Object)
+		if err != nil {
+			return fmt.Errorf("schedule set entry %d: invalid object: %w", i+1, err)
+		}
+		if objectID.Type != bacnet.ObjectTypeSchedule {
+			return fmt.Errorf("schedule set entry %d: %s is not a schedule object", i+1, objectID)
+		}
+		targets = append(targets, bacnet.ScheduleTarget{DeviceID: cfg.Device, ObjectID: objectID})
+	}
+
+	schedule, err := loadWeeklySchedule(args[1])
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx := context.Background()
+	connectCtx, cancel := context.WithTimeout(ctx, timeout*2)
+	err = client.Connect(connectCtx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	rolloutCtx, cancel := context.WithTimeout(ctx, operationDeadline(len(targets)+2))
+	defer cancel()
+
+	results, err := client.RolloutSchedule(rolloutCtx, schedule, targets, bacnet.WithRollback(!rolloutNoRollback))
+	if err != nil && !errors.Is(err, bacnet.ErrRolloutFailed) {
+		return err
+	}
+
+	f := NewFormatter(outputFmt)
+	headers := []string{"DEVICE", "OBJECT", "RESULT"}
+	rows := make([][]string, 0, len(results))
+	for _, r := range results {
+		result := "OK"
+		switch {
+		case r.Err != nil:
+			result = "FAILED: " + r.Err.Error()
+		case r.RolledBack:
+			result = "ROLLED BACK"
+		case r.RollbackErr != nil:
+			result = "ROLLBACK FAILED: " + r.RollbackErr.Error()
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", r.Target.DeviceID),
+			r.Target.ObjectID.String(),
+			result,
+		})
+	}
+	f.PrintTable(headers, rows)
+
+	return err
+}
+
+// loadWeeklySchedule reads a weekly schedule from a YAML, JSON or TOML file
+func loadWeeklySchedule(path string) (bacnet.WeeklySchedule, error) {
+	v, err := readScheduleFile(path)
+	if err != nil {
+		return bacnet.WeeklySchedule{}, err
+	}
+	return weeklyScheduleConfig(v)
+}
+
+// readScheduleFile reads a YAML, JSON or TOML schedule file
+func readScheduleFile(path string) (*viper.Viper, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read schedule: %w", err)
+	}
+	return v, nil
+}
+
+// scheduleValueType returns the type of the values of a schedule file
+func scheduleValueType(v *viper.Viper) string {
+	if valueType := v.GetString("type"); valueType != "" {
+		return valueType
+	}
+	return "real"
+}
+
+// parseTimeValues converts the time-values of a schedule file entry
+func parseTimeValues(key string, entries []timeValueConfig, valueType string) (bacnet.DailySchedule, error) {
+	day := make(bacnet.DailySchedule, 0, len(entries))
+	for _, e := range entries {
+		t, err := bacnet.ParseTimeOfDay(e.Time)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", key, err)
+		}
+		value, err := parseTypedValue(e.Value, valueType)
+		if err != nil {
+			return nil, fmt.Errorf("%s %s: %w", key, e.Time, err)
+		}
+		day = append(day, bacnet.TimeValue{Time: t, Value: value})
+	}
+	return day, nil
+}
+
+// weeklyScheduleConfig converts the days of a schedule file
+func weeklyScheduleConfig(v *viper.Viper) (bacnet.WeeklySchedule, error) {
+	var schedule bacnet.WeeklySchedule
+	valueType := scheduleValueType(v)
+
+	parseDay := func(key string) (bacnet.DailySchedule, error) {
+		var entries []timeValueConfig
+		if err := v.UnmarshalKey(key, &entries); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", key, err)
+		}
+		return parseTimeValues(key, entries, valueType)
+	}
+
+	groups := map[string][]int{"weekdays": {0, 1, 2, 3, 4}, "weekend": {5, 6}}
+	for _, key := range []string{"weekdays", "weekend"} {
+		if !v.IsSet(key) {
+			continue
+		}
+		day, err := parseDay(key)
+		if err != nil {
+			return schedule, err
+		}
+		for _, i := range groups[key] {
+			schedule[i] = day
+		}
+	}
+	for i, key := range scheduleDays {
+		if !v.IsSet(key) {
+			continue
+		}
+		day, err := parseDay(key)
+		if err != nil {
+			return schedule, err
+		}
+		schedule[i] = day
+	}
+
+	return schedule, nil
+}
+
+// parseTypedValue parses a scheduled value as the given BACnet type; "null"
+// or an empty value relinquishes
+func parseTypedValue(s, valueType string) (interface{}, error) {
+	s = strings.TrimSpace(s)
+	if s == "" || strings.EqualFold(s, "null") {
+		return nil, nil
+	}
+
+	switch valueType {
+	case "real":
+		f, err := strconv.ParseFloat(s, 32)
+		return float32(f), err
+	case "unsigned":
+		n, err := strconv.ParseUint(s, 10, 32)
+		return uint32(n), err
+	case "signed":
+		n, err := strconv.ParseInt(s, 10, 32)
+		return int32(n), err
+	case "enumerated":
+		switch strings.ToLower(s) {
+		case "active", "on", "true":
+			return bacnet.Enumerated(1), nil
+		case "inactive", "off", "false":
+			return bacnet.Enumerated(0), nil
+		}
+		n, err := strconv.ParseUint(s, 10, 32)
+		return bacnet.Enumerated(n), err
+	case "boolean":
+		return strconv.ParseBool(s)
+	default:
+		return nil, fmt.Errorf("unknown value type %q", valueType)
+	}
+}
@@ -17,6 +17,7 @@ package main
 import (
 	"fmt"
 	"log/slog"
+	"net"
 	"os"
 	"time"
 
@@ -31,14 +32,21 @@ var (
 	host         string
 	port         int
 	deviceID     uint32
+	deviceArg    string
 	timeout      time.Duration
 	retries      int
+	outstanding  int
 	outputFmt    string
+	templateText string
 	verbose      bool
 	localAddress string
 	bbmdAddress  string
 	bbmdPort     int
 	bbmdTTL      time.Duration
+	ephemeral    bool
+	siteName     string
+	pcapFile     string
+	traceFrames  bool
 
 	client *bacnet.Client
 	logger *slog.Logger
@@ -66,6 +74,35 @@ Examples:
   edgeo-bacnet watch -d 1234 -o analog-input:1`,
 
 	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		// -o json may be given on the command line or by the configuration
+		silenceJSONErrors(cmd)
+
+		// The configuration file and site profile fill in the flags not
+		// given, including -d, which may be a device alias
+		if err := applyConfig(cmd); err != nil {
+			return asUsage(err)
+		}
+		silenceJSONErrors(cmd)
+
+		if err := parseOutputTemplate(); err != nil {
+			return asUsage(err)
+		}
+
+		id, err := resolveDevice(deviceArg)
+		if err != nil {
+			return asUsage(err)
+		}
+		deviceID = id
+
+		// Cobra checks the flags again before the command runs, with errors
+		// that cannot be told apart from those of the command
+		if err := cmd.ValidateRequiredFlags(); err != nil {
+			return asUsage(err)
+		}
+		if err := cmd.ValidateFlagGroups(); err != nil {
+			return asUsage(err)
+		}
+
 		// Setup logger
 		logLevel := slog.LevelInfo
 		if verbose {
@@ -86,15 +123,21 @@ func init() {
 	rootCmd.PersistentFlags().StringVar(&cfgFile, "config", "", "config file (default is $HOME/.edgeo-bacnet.yaml)")
 	rootCmd.PersistentFlags().StringVarP(&host, "host", "H", "", "Target device IP address")
 	rootCmd.PersistentFlags().IntVarP(&port, "port", "p", bacnet.DefaultPort, "BACnet/IP port")
-	rootCmd.PersistentFlags().Uint32VarP(&deviceID, "device", "d", 0, "Target device instance ID")
+	rootCmd.PersistentFlags().StringVarP(&deviceArg, "device", "d", "", "Target device instance ID or device alias")
 	rootCmd.PersistentFlags().DurationVarP(&timeout, "timeout", "t", 3*time.Second, "Request timeout")
-	rootCmd.PersistentFlags().IntVar(&retries, "retries", 3, "Number of retries")
-	rootCmd.PersistentFlags().StringVarP(&outputFmt, "output", "o", "table", "Output format (table, json, csv, raw)")
+	rootCmd.PersistentFlags().IntVar(&retries, "retries", 3, "Times an unanswered request is sent again")
+	rootCmd.PersistentFlags().IntVar(&outstanding, "max-outstanding", 0, "Maximum concurrent requests per device (default from the device's link and APDU size)")
+	rootCmd.PersistentFlags().StringVarP(&outputFmt, "output", "o", "table", "Output format (table, json, yaml, csv, raw, influx, template, turtle)")
+	rootCmd.PersistentFlags().StringVar(&templateText, "template", "", "Go template applied to each record with -o template (e.g., '{{.Object}} {{.Value}}')")
 	rootCmd.PersistentFlags().BoolVarP(&verbose, "verbose", "v", false, "Enable verbose output")
 	rootCmd.PersistentFlags().StringVar(&localAddress, "local", "", "Local address to bind to (e.g., 0.0.0.0:47808)")
 	rootCmd.PersistentFlags().StringVar(&bbmdAddress, "bbmd", "", "BBMD address for foreign device registration")
 	rootCmd.PersistentFlags().IntVar(&bbmdPort, "bbmd-port", bacnet.DefaultPort, "BBMD port")
 	rootCmd.PersistentFlags().DurationVar(&bbmdTTL, "bbmd-ttl", 60*time.Second, "BBMD registration TTL")
+	rootCmd.PersistentFlags().BoolVar(&ephemeral, "ephemeral-port", false, "Use an ephemeral local port, receiving broadcasts through the BBMD")
+	rootCmd.PersistentFlags().StringVar(&siteName, "site", "", "Site name used to namespace logs and metrics, and site profile to apply")
+	rootCmd.PersistentFlags().StringVar(&pcapFile, "pcap", "", "Write the BACnet/IP traffic to a pcap file")
+	rootCmd.PersistentFlags().BoolVar(&traceFrames, "trace", false, "Print a decoded breakdown of every frame sent or received")
 
 	// Bind flags to viper
 	viper.BindPFlag("host", rootCmd.PersistentFlags().Lookup("host"))
@@ -102,21 +145,59 @@ func init() {
 	viper.BindPFlag("device", rootCmd.PersistentFlags().Lookup("device"))
 	viper.BindPFlag("timeout", rootCmd.PersistentFlags().Lookup("timeout"))
 	viper.BindPFlag("retries", rootCmd.PersistentFlags().Lookup("retries"))
+	viper.BindPFlag("max-outstanding", rootCmd.PersistentFlags().Lookup("max-outstanding"))
 	viper.BindPFlag("output", rootCmd.PersistentFlags().Lookup("output"))
+	viper.BindPFlag("template", rootCmd.PersistentFlags().Lookup("template"))
 	viper.BindPFlag("verbose", rootCmd.PersistentFlags().Lookup("verbose"))
 	viper.BindPFlag("local", rootCmd.PersistentFlags().Lookup("local"))
 	viper.BindPFlag("bbmd", rootCmd.PersistentFlags().Lookup("bbmd"))
 	viper.BindPFlag("bbmd-port", rootCmd.PersistentFlags().Lookup("bbmd-port"))
 	viper.BindPFlag("bbmd-ttl", rootCmd.PersistentFlags().Lookup("bbmd-ttl"))
+	viper.BindPFlag("ephemeral-port", rootCmd.PersistentFlags().Lookup("ephemeral-port"))
+	viper.BindPFlag("site", rootCmd.PersistentFlags().Lookup("site"))
 
 	// Add subcommands
 	rootCmd.AddCommand(scanCmd)
+	rootCmd.AddCommand(findCmd)
 	rootCmd.AddCommand(readCmd)
+	rootCmd.AddCommand(readmCmd)
 	rootCmd.AddCommand(writeCmd)
+	rootCmd.AddCommand(writemCmd)
+	rootCmd.AddCommand(rampCmd)
 	rootCmd.AddCommand(watchCmd)
+	rootCmd.AddCommand(monitorCmd)
+	rootCmd.AddCommand(objectsCmd)
 	rootCmd.AddCommand(dumpCmd)
+	rootCmd.AddCommand(loadCmd)
+	rootCmd.AddCommand(trendCmd)
+	rootCmd.AddCommand(alarmsCmd)
+	rootCmd.AddCommand(ackCmd)
+	rootCmd.AddCommand(backupCmd)
+	rootCmd.AddCommand(restoreCmd)
+	rootCmd.AddCommand(fileCmd)
+	rootCmd.AddCommand(timesyncCmd)
+	rootCmd.AddCommand(dccCmd)
+	rootCmd.AddCommand(reinitCmd)
+	rootCmd.AddCommand(covCmd)
+	rootCmd.AddCommand(scheduleCmd)
+	rootCmd.AddCommand(bbmdCmd)
+	rootCmd.AddCommand(networkCmd)
+	rootCmd.AddCommand(serveCmd)
+	rootCmd.AddCommand(serveHTTPCmd)
+	rootCmd.AddCommand(serveGRPCCmd)
 	rootCmd.AddCommand(infoCmd)
+	rootCmd.AddCommand(pingCmd)
+	rootCmd.AddCommand(benchCmd)
+	rootCmd.AddCommand(verifyCmd)
+	rootCmd.AddCommand(compareCmd)
+	rootCmd.AddCommand(pointsCmd)
+	rootCmd.AddCommand(inventoryCmd)
+	rootCmd.AddCommand(cronCmd)
+	rootCmd.AddCommand(rolloutCmd)
+	rootCmd.AddCommand(selftestCmd)
 	rootCmd.AddCommand(interactiveCmd)
+	rootCmd.AddCommand(scriptCmd)
+	rootCmd.AddCommand(configCmd)
 	rootCmd.AddCommand(versionCmd)
 }
 
@@ -145,8 +226,16 @@ func initConfig() {
 	}
 }
 
-// createClient creates a BACnet client with current configuration
-func createClient() (*bacnet.Client, error) {
+// operationDeadline returns how long an operation of the given number of
+// confirmed requests may take. The client sends each request up to --retries
+// more times, --timeout apart, before giving up on it.
+func operationDeadline(requests int) time.Duration {
+	return operationDeadline(max(requests, 1))
+}
+
+// createClient creates a BACnet client with current configuration and the
+// extra options of the command
+func createClient(extra ...bacnet.Option) (*bacnet.Client, error) {
 	opts := []bacnet.Option{
 		bacnet.WithTimeout(timeout),
 		bacnet.WithRetries(retries),
@@ -157,11 +246,63 @@ func createClient() (*bacnet.Client, error) {
 		opts = append(opts, bacnet.WithLocalAddress(localAddress))
 	}
 
+	if outstanding > 0 {
+		opts = append(opts, bacnet.WithMaxOutstandingRequests(outstanding))
+	}
+
 	if bbmdAddress != "" {
 		opts = append(opts, bacnet.WithBBMD(bbmdAddress, bbmdPort, bbmdTTL))
 	}
 
-	return bacnet.NewClient(opts...)
+	if ephemeral {
+		opts = append(opts, bacnet.WithEphemeralPort())
+	}
+
+	if siteName != "" {
+		opts = append(opts, bacnet.WithSite(siteName))
+	}
+
+	if pcapFile != "" && captureFile == nil {
+		f, err := os.Create(pcapFile)
+		if err != nil {
+			return nil, fmt.Errorf("create capture file: %w", err)
+		}
+		captureFile = f
+		opts = append(opts, bacnet.WithPacketCapture(f))
+	}
+
+	if traceFrames {
+		opts = append(opts, bacnet.WithProtocolTraceFunc(func(sent bool, peer net.Addr, dump string) {
+			direction := "<-"
+			if sent {
+				direction = "->"
+			}
+			fmt.Fprintf(os.Stderr, "%s %v\n%s\n\n", direction, peer, dump)
+		}))
+	}
+
+	return bacnet.NewClient(append(opts, extra...)...)
+}
+
+// captureFile is the --pcap file, open from the creation of the first
+// client, the only one captured, until the command returns
+var captureFile *os.File
+
+// closeCapture flushes the --pcap file to disk and closes it
+func closeCapture() error {
+	if captureFile == nil {
+		return nil
+	}
+	f := captureFile
+	captureFile = nil
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return fmt.Errorf("flush capture file: %w", err)
+	}
+	if err := f.Close(); err != nil {
+		return fmt.Errorf("close capture file: %w", err)
+	}
+	return nil
 }
 
 var versionCmd = &cobra.Command{
@@ -16,8 +16,14 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
+	"errors"
 	"fmt"
 	"os"
+	"os/signal"
+	"strings"
+	"sync"
+	"syscall"
 	"time"
 
 	"github.com/spf13/cobra"
@@ -30,8 +36,25 @@ var (
 	scanLowLimit uint32
 	scanHighLimit uint32
 	scanNetwork  uint16
+	scanDetail   bool
+	scanStream   bool
+	scanCIDR     string
+	scanRate     int
+	scanWorkers  int
+	scanRead     bool
 )
 
+// maxScanDetailConcurrency bounds the devices read at the same time by
+// scan --detail
+const maxScanDetailConcurrency = 8
+
+// scanDetails are the names read from a device by scan --detail
+type scanDetails struct {
+	ObjectName string
+	ModelName  string
+	VendorName string
+}
+
 var scanCmd = &cobra.Command{
 	Use:   "scan",
 	Short: "Scan for BACnet devices on the network",
@@ -45,7 +68,19 @@ Examples:
   edgeo-bacnet scan --low 1 --high 100
 
   # Discover with extended timeout
-  edgeo-bacnet scan --scan-timeout 10s`,
+  edgeo-bacnet scan --scan-timeout 10s
+
+  # Add the name, model and vendor of each device
+  edgeo-bacnet scan --detail
+
+  # Print each device as its I-Am arrives, with the time it took
+  edgeo-bacnet scan --stream
+
+  # Probe each address of a subnet where broadcasts are blocked
+  edgeo-bacnet scan --cidr 10.1.0.0/24
+
+  # Probe with reads, 20 addresses per second
+  edgeo-bacnet scan --cidr 10.1.0.0/24 --probe-read --rate 20`,
 
 	RunE: runScan,
 }
@@ -55,6 +90,12 @@ func init() {
 	scanCmd.Flags().Uint32Var(&scanLowLimit, "low", 0, "Low limit for device instance range (0 = no limit)")
 	scanCmd.Flags().Uint32Var(&scanHighLimit, "high", 0, "High limit for device instance range (0 = no limit)")
 	scanCmd.Flags().Uint16Var(&scanNetwork, "network", 0, "Target network number (0 = local)")
+	scanCmd.Flags().BoolVar(&scanDetail, "detail", false, "Read object-name, model-name and vendor-name of each device")
+	scanCmd.Flags().BoolVar(&scanStream, "stream", false, "Print each device as it answers instead of at the end of the scan")
+	scanCmd.Flags().StringVar(&scanCIDR, "cidr", "", "Probe each address of an IPv4 range instead of broadcasting")
+	scanCmd.Flags().IntVar(&scanRate, "rate", 100, "Addresses probed per second with --cidr (0 = no limit)")
+	scanCmd.Flags().IntVar(&scanWorkers, "concurrency", 16, "Probes pending at the same time with --cidr")
+	scanCmd.Flags().BoolVar(&scanRead, "probe-read", false, "Probe with a read of the device object instead of a Who-Is")
 }
 
 func runScan(cmd *cobra.Command, args []string) error {
@@ -63,8 +104,15 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("create client: %w", err)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), timeout+scanTimeout)
-	defer cancel()
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	if scanCIDR == "" {
+		// Probing lasts as long as the range takes, a broadcast no longer
+		// than the discovery
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout+scanTimeout)
+		defer cancel()
+	}
 
 	if err := client.Connect(ctx); err != nil {
 		return fmt.Errorf("connect: %w", err)
@@ -91,7 +139,31 @@ func runScan(cmd *cobra.Command, args []string) error {
 		discoverOpts = append(discoverOpts, bacnet.WithTargetNetwork(scanNetwork))
 	}
 
-	devices, err := client.WhoIs(ctx, discoverOpts...)
+	var discover scanDiscovery = client.WhoIs
+	if scanCIDR != "" {
+		discoverOpts = append(discoverOpts,
+			bacnet.WithProbeRate(scanRate),
+			bacnet.WithProbeConcurrency(scanWorkers),
+			bacnet.WithProbePort(port),
+		)
+		if scanRead {
+			discoverOpts = append(discoverOpts, bacnet.WithProbeReadProperty())
+		}
+		// An interrupted probe lists the devices found so far
+		discover = func(ctx context.Context, opts ...bacnet.DiscoverOption) ([]*bacnet.DeviceInfo, error) {
+			devices, err := client.ProbeRange(ctx, scanCIDR, opts...)
+			if errors.Is(err, context.Canceled) {
+				err = nil
+			}
+			return devices, err
+		}
+	}
+
+	if scanStream {
+		return streamScan(ctx, client, discover, discoverOpts)
+	}
+
+	devices, err := discover(ctx, discoverOpts...)
 	if err != nil {
 		return fmt.Errorf("discovery: %w", err)
 	}
@@ -101,70 +173,309 @@ func runScan(cmd *cobra.Command, args []string) error {
 		return nil
 	}
 
+	var details map[uint32]scanDetails
+	if scanDetail {
+		fmt.Fprintf(os.Stderr, "Reading details of %d device(s)...\n", len(devices))
+		details = readScanDetails(client, devices)
+	}
+
 	// Output results
 	switch outputFmt {
-	case "json":
-		return outputDevicesJSON(devices)
+	case "json", "yaml":
+		return outputDevicesJSON(devices, details)
+	case "template":
+		for _, dev := range scanDevices(devices, details) {
+			if err := encodeTemplate(os.Stdout, dev); err != nil {
+				return err
+			}
+		}
+		return nil
 	case "csv":
-		return outputDevicesCSV(devices)
+		return outputDevicesCSV(devices, details)
 	default:
-		return outputDevicesTable(devices)
+		return outputDevicesTable(devices, details)
 	}
 }
 
-func outputDevicesTable(devices []*bacnet.DeviceInfo) error {
-	fmt.Printf("\n%-12s %-20s %-8s %-20s %-10s\n", "DEVICE ID", "ADDRESS", "VENDOR", "SEGMENTATION", "MAX APDU")
-	fmt.Println("------------ -------------------- -------- -------------------- ----------")
+// readScanDetails reads the names of the devices concurrently. Devices
+// that cannot be read are left out.
+func readScanDetails(client *bacnet.Client, devices []*bacnet.DeviceInfo) map[uint32]scanDetails {
+	details := make(map[uint32]scanDetails, len(devices))
+	var mu sync.Mutex
+	var wg sync.WaitGroup
+	sem := make(chan struct{}, maxScanDetailConcurrency)
 
 	for _, dev := range devices {
-		addr := formatAddress(dev.Address)
-		fmt.Printf("%-12d %-20s %-8d %-20s %-10d\n",
-			dev.ObjectID.Instance,
-			addr,
-			dev.VendorID,
-			dev.Segmentation.String(),
-			dev.MaxAPDULength,
-		)
+		wg.Add(1)
+		go func(deviceID uint32) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			d, ok := readScanDetail(client, deviceID)
+			if !ok {
+				return
+			}
+
+			mu.Lock()
+			details[deviceID] = d
+			mu.Unlock()
+		}(dev.ObjectID.Instance)
 	}
+	wg.Wait()
 
-	fmt.Printf("\nFound %d device(s)\n", len(devices))
-	return nil
+	return details
 }
 
-func outputDevicesJSON(devices []*bacnet.DeviceInfo) error {
-	fmt.Println("[")
-	for i, dev := range devices {
-		comma := ","
-		if i == len(devices)-1 {
-			comma = ""
+// readScanDetail reads the names of a device with a single
+// ReadPropertyMultiple where supported
+func readScanDetail(client *bacnet.Client, deviceID uint32) (scanDetails, bool) {
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(1))
+	defer cancel()
+
+	oid := bacnet.NewObjectIdentifier(bacnet.ObjectTypeDevice, deviceID)
+	results, err := client.Device(deviceID).ReadMultiple(ctx, []bacnet.ReadPropertyRequest{
+		{ObjectID: oid, PropertyID: bacnet.PropertyObjectName},
+		{ObjectID: oid, PropertyID: bacnet.PropertyModelName},
+		{ObjectID: oid, PropertyID: bacnet.PropertyVendorName},
+	})
+	if err != nil {
+		if verbose {
+			fmt.Fprintf(os.Stderr, "device %d: %v\n", deviceID, err)
 		}
-		fmt.Printf(`  {"device_id": %d, "address": "%s", "vendor_id": %d, "segmentation": "%s", "max_apdu": %d}%s`+"\n",
-			dev.ObjectID.Instance,
-			formatAddress(dev.Address),
-			dev.VendorID,
-			dev.Segmentation.String(),
-			dev.MaxAPDULength,
-			comma,
-		)
+		return scanDetails{}, false
 	}
-	fmt.Println("]")
-	return nil
+
+	var d scanDetails
+	for _, r := range results {
+		name, _ := r.Value.(string)
+		switch r.PropertyID {
+		case bacnet.PropertyObjectName:
+			d.ObjectName = name
+		case bacnet.PropertyModelName:
+			d.ModelName = name
+		case bacnet.PropertyVendorName:
+			d.VendorName = name
+		}
+	}
+	return d, true
+}
+
+// scanDiscovery discovers the devices: a Who-Is broadcast, or the probe of
+// a range with --cidr
+type scanDiscovery func(ctx context.Context, opts ...bacnet.DiscoverOption) ([]*bacnet.DeviceInfo, error)
+
+// streamScan prints each device as its I-Am arrives, with the time since
+// the Who-Is was sent. With --detail a device is printed once its names
+// are read.
+func streamScan(ctx context.Context, client *bacnet.Client, discover scanDiscovery, discoverOpts []bacnet.DiscoverOption) error {
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		count  int
+		outErr error
+	)
+	sem := make(chan struct{}, maxScanDetailConcurrency)
+	csvWriter := csv.NewWriter(os.Stdout)
+
+	emit := func(dev *bacnet.DeviceInfo, d *scanDetails, elapsed time.Duration) {
+		mu.Lock()
+		defer mu.Unlock()
+		count++
+		var err error
+		switch outputFmt {
+		case "json", "yaml", "template":
+			record := ScanEvent{ScanDevice: newScanDevice(dev, d), Elapsed: elapsed.Seconds()}
+			err = encodeOutputRecord(os.Stdout, record)
+		case "csv":
+			csvWriter.Write(append([]string{fmt.Sprintf("%.3f", elapsed.Seconds())}, scanCSVRow(dev, d)...))
+			csvWriter.Flush()
+			err = csvWriter.Error()
+		default:
+			fmt.Printf("%-10s %s\n", elapsed.Round(time.Millisecond), scanTableRow(dev, d))
+		}
+		if err != nil && outErr == nil {
+			outErr = err
+		}
+	}
+
+	switch outputFmt {
+	case "json", "yaml", "template":
+	case "csv":
+		csvWriter.Write(append([]string{"elapsed_s"}, scanCSVHeader(scanDetail)...))
+		csvWriter.Flush()
+	default:
+		header, rule := scanTableHeader(scanDetail)
+		fmt.Printf("\n%-10s %s\n", "ELAPSED", header)
+		fmt.Printf("%s %s\n", strings.Repeat("-", 10), rule)
+	}
+
+	start := time.Now()
+	discoverOpts = append(discoverOpts, bacnet.WithDeviceFound(func(dev *bacnet.DeviceInfo) {
+		elapsed := time.Since(start)
+		if !scanDetail {
+			emit(dev, nil, elapsed)
+			return
+		}
+		// Reading the names waits for answers: not on the receive loop
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			d, _ := readScanDetail(client, dev.ObjectID.Instance)
+			emit(dev, &d, elapsed)
+		}()
+	}))
+
+	_, err := discover(ctx, discoverOpts...)
+	wg.Wait()
+	if err != nil {
+		return fmt.Errorf("discovery: %w", err)
+	}
+
+	if !isStructuredOutput() && outputFmt != "template" && outputFmt != "csv" {
+		fmt.Printf("\nFound %d device(s)\n", count)
+	}
+	return outErr
 }
 
-func outputDevicesCSV(devices []*bacnet.DeviceInfo) error {
-	fmt.Println("device_id,address,vendor_id,segmentation,max_apdu")
+func outputDevicesTable(devices []*bacnet.DeviceInfo, details map[uint32]scanDetails) error {
+	header, rule := scanTableHeader(details != nil)
+	fmt.Printf("\n%s\n", header)
+	fmt.Println(rule)
+
 	for _, dev := range devices {
-		fmt.Printf("%d,%s,%d,%s,%d\n",
-			dev.ObjectID.Instance,
-			formatAddress(dev.Address),
-			dev.VendorID,
-			dev.Segmentation.String(),
-			dev.MaxAPDULength,
-		)
+		fmt.Println(scanTableRow(dev, scanDeviceDetails(dev, details)))
 	}
+
+	fmt.Printf("\nFound %d device(s)\n", len(devices))
 	return nil
 }
 
+// scanTableHeader returns the header of the device table and the rule
+// under it
+func scanTableHeader(details bool) (string, string) {
+	if details {
+		return fmt.Sprintf("%-12s %-20s %-8s %-20s %-10s %-24s %-20s %-20s", "DEVICE ID", "ADDRESS", "VENDOR", "SEGMENTATION", "MAX APDU", "NAME", "MODEL", "VENDOR NAME"),
+			"------------ -------------------- -------- -------------------- ---------- ------------------------ -------------------- --------------------"
+	}
+	return fmt.Sprintf("%-12s %-20s %-8s %-20s %-10s", "DEVICE ID", "ADDRESS", "VENDOR", "SEGMENTATION", "MAX APDU"),
+		"------------ -------------------- -------- -------------------- ----------"
+}
+
+// scanTableRow returns the row of a device in the device table, with its
+// names when read
+func scanTableRow(dev *bacnet.DeviceInfo, d *scanDetails) string {
+	row := fmt.Sprintf("%-12d %-20s %-8d %-20s %-10d",
+		dev.ObjectID.Instance,
+		formatAddress(dev.Address),
+		dev.VendorID,
+		dev.Segmentation.String(),
+		dev.MaxAPDULength,
+	)
+	if d != nil {
+		row += fmt.Sprintf(" %-24s %-20s %-20s", d.ObjectName, d.ModelName, d.VendorName)
+	}
+	return row
+}
+
+// scanDeviceDetails returns the names read of a device, nil without
+// --detail
+func scanDeviceDetails(dev *bacnet.DeviceInfo, details map[uint32]scanDetails) *scanDetails {
+	if details == nil {
+		return nil
+	}
+	d := details[dev.ObjectID.Instance]
+	return &d
+}
+
+// ScanDevice is a device in the JSON output of scan. The names are only
+// read with --details.
+type ScanDevice struct {
+	DeviceID     uint32 `json:"device_id"`
+	Address      string `json:"address"`
+	VendorID     uint16 `json:"vendor_id"`
+	Segmentation string `json:"segmentation"`
+	MaxAPDU      uint16 `json:"max_apdu"`
+	ObjectName   string `json:"object_name,omitempty"`
+	ModelName    string `json:"model_name,omitempty"`
+	VendorName   string `json:"vendor_name,omitempty"`
+	ForwardedBy  string `json:"forwarded_by,omitempty"`
+}
+
+// ScanEvent is a device printed by scan --stream, with the seconds between
+// the Who-Is and its I-Am
+type ScanEvent struct {
+	ScanDevice
+	Elapsed float64 `json:"elapsed_s"`
+}
+
+// newScanDevice converts a device found to its output record
+func newScanDevice(dev *bacnet.DeviceInfo, d *scanDetails) ScanDevice {
+	out := ScanDevice{
+		DeviceID:     dev.ObjectID.Instance,
+		Address:      formatAddress(dev.Address),
+		VendorID:     dev.VendorID,
+		Segmentation: dev.Segmentation.String(),
+		MaxAPDU:      dev.MaxAPDULength,
+		ForwardedBy:  dev.ForwardedBy,
+	}
+	if d != nil {
+		out.ObjectName, out.ModelName, out.VendorName = d.ObjectName, d.ModelName, d.VendorName
+	}
+	return out
+}
+
+// scanDevices converts the devices found to their output records
+func scanDevices(devices []*bacnet.DeviceInfo, details map[uint32]scanDetails) []ScanDevice {
+	out := make([]ScanDevice, 0, len(devices))
+	for _, dev := range devices {
+		out = append(out, newScanDevice(dev, scanDeviceDetails(dev, details)))
+	}
+	return out
+}
+
+func outputDevicesJSON(devices []*bacnet.DeviceInfo, details map[uint32]scanDetails) error {
+	return encodeOutput(os.Stdout, scanDevices(devices, details))
+}
+
+func outputDevicesCSV(devices []*bacnet.DeviceInfo, details map[uint32]scanDetails) error {
+	writer := csv.NewWriter(os.Stdout)
+	defer writer.Flush()
+
+	writer.Write(scanCSVHeader(details != nil))
+	for _, dev := range devices {
+		writer.Write(scanCSVRow(dev, scanDeviceDetails(dev, details)))
+	}
+	return writer.Error()
+}
+
+// scanCSVHeader returns the CSV header of the devices
+func scanCSVHeader(details bool) []string {
+	header := []string{"device_id", "address", "vendor_id", "segmentation", "max_apdu"}
+	if details {
+		header = append(header, "object_name", "model_name", "vendor_name")
+	}
+	return header
+}
+
+// scanCSVRow returns the CSV record of a device, with its names when read
+func scanCSVRow(dev *bacnet.DeviceInfo, d *scanDetails) []string {
+	row := []string{
+		fmt.Sprintf("%d", dev.ObjectID.Instance),
+		formatAddress(dev.Address),
+		fmt.Sprintf("%d", dev.VendorID),
+		dev.Segmentation.String(),
+		fmt.Sprintf("%d", dev.MaxAPDULength),
+	}
+	if d != nil {
+		row = append(row, d.ObjectName, d.ModelName, d.VendorName)
+	}
+	return row
+}
+
 func formatAddress(addr bacnet.Address) string {
 	if len(addr.Addr) == 4 {
 		return fmt.Sprintf("%d.%d.%d.%d", addr.Addr[0], addr.Addr[1], addr.Addr[2], addr.Addr[3])
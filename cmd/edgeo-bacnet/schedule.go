@@ -0,0 +1,517 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	scheduleObject string
+	scheduleWrite  string
+)
+
+var scheduleCmd = &cobra.Command{
+	Use:   "schedule",
+	Short: "View or edit the weekly and exception schedules of a schedule object",
+	Long: `Schedule prints the weekly schedule of a schedule object as a grid of
+time-values per day, followed by its exception schedule.
+
+With --write, the schedules in a file are written first. The file uses the
+format of the rollout command, with an optional "exceptions" list; the
+weekly schedule is written only when the file sets days, the exception
+schedule only when it sets "exceptions" (an empty list clears it):
+
+  type: enumerated
+  weekdays:
+    - { time: "06:00", value: 1 }
+    - { time: "18:00", value: 0 }
+  exceptions:
+    - date: "*-12-25"            # every Christmas
+      priority: 10
+      values:
+        - { time: "00:00", value: 0 }
+    - from: "2026-08-01"         # a date range
+      to: "2026-08-15"
+      priority: 12
+      values:
+        - { time: "00:00", value: 0 }
+    - month: "*"                 # the last Friday of every month
+      week: "6"
+      weekday: friday
+      priority: 14
+      values:
+        - { time: "16:00", value: 0 }
+    - calendar: calendar:1       # the days of a calendar object
+      priority: 8
+      values:
+        - { time: "00:00", value: 0 }
+
+The JSON output (-o json) uses the same format, so that a schedule can be
+saved, edited and written back.
+
+Examples:
+  # Show a schedule
+  edgeo-bacnet schedule -d 1234 -O schedule:1
+
+  # Save it, then write an edited copy back
+  edgeo-bacnet schedule -d 1234 -O schedule:1 -o json > schedule.json
+  edgeo-bacnet schedule -d 1234 -O schedule:1 --write schedule.json`,
+
+	// Device errors are not a usage error
+	SilenceUsage: true,
+
+	RunE: runSchedule,
+}
+
+func init() {
+	scheduleCmd.Flags().StringVarP(&scheduleObject, "object", "O", "", "Schedule object (e.g. schedule:1)")
+	scheduleCmd.Flags().StringVar(&scheduleWrite, "write", "", "Write the schedules of a YAML, JSON or TOML file")
+
+	scheduleCmd.MarkFlagRequired("object")
+}
+
+// specialEventConfig is an exception schedule entry of a schedule file. The
+// period is either a date, a from-to date range, a month-week-weekday
+// pattern or a calendar object.
+type specialEventConfig struct {
+	Date     string            `mapstructure:"date" json:"date,omitempty"`
+	From     string            `mapstructure:"from" json:"from,omitempty"`
+	To       string            `mapstructure:"to" json:"to,omitempty"`
+	Month    string            `mapstructure:"month" json:"month,omitempty"`
+	Week     string            `mapstructure:"week" json:"week,omitempty"`
+	Weekday  string            `mapstructure:"weekday" json:"weekday,omitempty"`
+	Calendar string            `mapstructure:"calendar" json:"calendar,omitempty"`
+	Priority uint8             `mapstructure:"priority" json:"priority"`
+	Values   []timeValueConfig `mapstructure:"values" json:"values"`
+}
+
+// scheduleFile is the JSON output of the schedule command, in the format
+// of schedule files
+type scheduleFile struct {
+	Type       string               `json:"type,omitempty"`
+	Monday     []timeValueConfig    `json:"monday"`
+	Tuesday    []timeValueConfig    `json:"tuesday"`
+	Wednesday  []timeValueConfig    `json:"wednesday"`
+	Thursday   []timeValueConfig    `json:"thursday"`
+	Friday     []timeValueConfig    `json:"friday"`
+	Saturday   []timeValueConfig    `json:"saturday"`
+	Sunday     []timeValueConfig    `json:"sunday"`
+	Exceptions []specialEventConfig `json:"exceptions"`
+}
+
+func runSchedule(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	objectID, err := parseObjectIdentifier(scheduleObject)
+	if err != nil {
+		return fmt.Errorf("invalid object: %w", err)
+	}
+	if objectID.Type != bacnet.ObjectTypeSchedule {
+		return fmt.Errorf("%s is not a schedule object", objectID)
+	}
+
+	// The file is checked before connecting
+	var weekly *bacnet.WeeklySchedule
+	var exceptions bacnet.ExceptionSchedule
+	writeExceptions := false
+	if scheduleWrite != "" {
+		v, err := readScheduleFile(scheduleWrite)
+		if err != nil {
+			return err
+		}
+		for _, key := range append([]string{"weekdays", "weekend"}, scheduleDays...) {
+			if v.IsSet(key) {
+				w, err := weeklyScheduleConfig(v)
+				if err != nil {
+					return err
+				}
+				weekly = &w
+				break
+			}
+		}
+		if v.IsSet("exceptions") {
+			exceptions, err = exceptionScheduleConfig(v)
+			if err != nil {
+				return err
+			}
+			writeExceptions = true
+		}
+		if weekly == nil && !writeExceptions {
+			return fmt.Errorf("%s sets neither days nor exceptions", scheduleWrite)
+		}
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	// Both schedules are written and read back
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(4))
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	if weekly != nil {
+		if err := client.WriteWeeklySchedule(ctx, deviceID, objectID, *weekly); err != nil {
+			return fmt.Errorf("write weekly schedule: %w", err)
+		}
+	}
+	if writeExceptions {
+		if err := client.WriteExceptionSchedule(ctx, deviceID, objectID, exceptions); err != nil {
+			return fmt.Errorf("write exception schedule: %w", err)
+		}
+	}
+	if scheduleWrite != "" {
+		fmt.Fprintf(os.Stderr, "Wrote the schedules of %s to %s\n", scheduleWrite, objectID)
+	}
+
+	// Schedule objects need not have both schedules
+	week, err := client.ReadWeeklySchedule(ctx, deviceID, objectID)
+	hasWeekly := err == nil
+	if err != nil && !bacnet.IsPropertyNotFound(err) {
+		return fmt.Errorf("read weekly schedule: %w", err)
+	}
+	exceptions, err = client.ReadExceptionSchedule(ctx, deviceID, objectID)
+	if err != nil && !bacnet.IsPropertyNotFound(err) {
+		return fmt.Errorf("read exception schedule: %w", err)
+	}
+
+	if isStructuredOutput() {
+		return encodeOutput(os.Stdout, newScheduleFile(week, exceptions))
+	}
+
+	f := NewFormatter(outputFmt)
+	if hasWeekly {
+		headers, rows := weeklyScheduleGrid(week)
+		f.PrintTable(headers, rows)
+	} else {
+		fmt.Println("No weekly schedule")
+	}
+	fmt.Println()
+
+	if len(exceptions) == 0 {
+		fmt.Println("No exceptions")
+		return nil
+	}
+	rows := make([][]string, 0, len(exceptions))
+	for _, ev := range exceptions {
+		var period string
+		if ev.Period != nil {
+			period = formatCalendarEntry(*ev.Period)
+		} else {
+			period = "calendar " + ev.CalendarReference.String()
+		}
+		tvs := make([]string, 0, len(ev.TimeValues))
+		for _, tv := range ev.TimeValues {
+			tvs = append(tvs, formatScheduleTime(tv.Time)+" "+formatScheduleValue(tv.Value))
+		}
+		rows = append(rows, []string{period, strconv.Itoa(int(ev.Priority)), strings.Join(tvs, ", ")})
+	}
+	f.PrintTable([]string{"EXCEPTION", "PRIORITY", "TIME-VALUES"}, rows)
+
+	return nil
+}
+
+// weeklyScheduleGrid returns a row per time of the weekly schedule, with
+// the value taking effect on each day at that time
+func weeklyScheduleGrid(week bacnet.WeeklySchedule) ([]string, [][]string) {
+	headers := []string{"TIME", "MON", "TUE", "WED", "THU", "FRI", "SAT", "SUN"}
+
+	cells := make(map[bacnet.TimeOfDay][]string)
+	var times []bacnet.TimeOfDay
+	for day, daily := range week {
+		for _, tv := range daily {
+			row, ok := cells[tv.Time]
+			if !ok {
+				row = make([]string, len(week))
+				cells[tv.Time] = row
+				times = append(times, tv.Time)
+			}
+			row[day] = formatScheduleValue(tv.Value)
+		}
+	}
+	sort.Slice(times, func(i, j int) bool {
+		a, b := times[i], times[j]
+		if a.Hour != b.Hour {
+			return a.Hour < b.Hour
+		}
+		if a.Minute != b.Minute {
+			return a.Minute < b.Minute
+		}
+		if a.Second != b.Second {
+			return a.Second < b.Second
+		}
+		return a.Hundredths < b.Hundredths
+	})
+
+	rows := make([][]string, 0, len(times))
+	for _, t := range times {
+		rows = append(rows, append([]string{formatScheduleTime(t)}, cells[t]...))
+	}
+	return headers, rows
+}
+
+// formatScheduleTime formats a time-value time, leaving out zero seconds
+func formatScheduleTime(t bacnet.TimeOfDay) string {
+	if t.Second == 0 && t.Hundredths == 0 {
+		return fmt.Sprintf("%02d:%02d", t.Hour, t.Minute)
+	}
+	return t.String()
+}
+
+// formatScheduleValue formats a scheduled value as in schedule files
+func formatScheduleValue(value interface{}) string {
+	switch v := value.(type) {
+	case nil:
+		return "null"
+	case float32:
+		return strconv.FormatFloat(float64(v), 'f', -1, 32)
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64)
+	case bacnet.Enumerated:
+		return strconv.FormatUint(uint64(v), 10)
+	default:
+		return formatValue(value)
+	}
+}
+
+// formatCalendarEntry formats the period of a special event
+func formatCalendarEntry(c bacnet.CalendarEntry) string {
+	switch {
+	case c.Date != nil:
+		return c.Date.String()
+	case c.DateRange != nil:
+		return c.DateRange.Start.String() + " to " + c.DateRange.End.String()
+	case c.WeekNDay != nil:
+		return formatWeekNDay(*c.WeekNDay)
+	default:
+		return c.String()
+	}
+}
+
+// formatWeekNDay describes a week-and-day pattern, e.g. "friday of the
+// last week of any month"
+func formatWeekNDay(w bacnet.WeekNDay) string {
+	day := "any day"
+	if w.DayOfWeek >= 1 && w.DayOfWeek <= 7 {
+		day = scheduleDays[w.DayOfWeek-1]
+	}
+	week := "any week"
+	switch {
+	case w.WeekOfMonth >= 1 && w.WeekOfMonth <= 5:
+		week = fmt.Sprintf("week %d", w.WeekOfMonth)
+	case w.WeekOfMonth == 6:
+		week = "the last week"
+	case w.WeekOfMonth >= 7 && w.WeekOfMonth <= 9:
+		week = fmt.Sprintf("week %d from the end", w.WeekOfMonth-5)
+	}
+	month := "any month"
+	switch {
+	case w.Month >= 1 && w.Month <= 12:
+		month = time.Month(w.Month).String()
+	case w.Month == 13:
+		month = "odd months"
+	case w.Month == 14:
+		month = "even months"
+	}
+	return fmt.Sprintf("%s of %s of %s", day, week, month)
+}
+
+// exceptionScheduleConfig converts the exceptions of a schedule file
+func exceptionScheduleConfig(v *viper.Viper) (bacnet.ExceptionSchedule, error) {
+	var configs []specialEventConfig
+	if err := v.UnmarshalKey("exceptions", &configs); err != nil {
+		return nil, fmt.Errorf("parse exceptions: %w", err)
+	}
+	valueType := scheduleValueType(v)
+
+	exceptions := make(bacnet.ExceptionSchedule, 0, len(configs))
+	for i, cfg := range configs {
+		key := fmt.Sprintf("exception %d", i+1)
+		ev := bacnet.SpecialEvent{Priority: cfg.Priority}
+
+		switch {
+		case cfg.Calendar != "":
+			ref, err := parseObjectIdentifier(cfg.Calendar)
+			if err != nil {
+				return nil, fmt.Errorf("%s: invalid calendar: %w", key, err)
+			}
+			ev.CalendarReference = &ref
+		case cfg.Date != "":
+			date, err := bacnet.ParseDate(cfg.Date)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			ev.Period = &bacnet.CalendarEntry{Date: &date}
+		case cfg.From != "" || cfg.To != "":
+			start, err := bacnet.ParseDate(cfg.From)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			end, err := bacnet.ParseDate(cfg.To)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			ev.Period = &bacnet.CalendarEntry{DateRange: &bacnet.DateRange{Start: start, End: end}}
+		case cfg.Month != "" || cfg.Week != "" || cfg.Weekday != "":
+			w, err := parseWeekNDay(cfg)
+			if err != nil {
+				return nil, fmt.Errorf("%s: %w", key, err)
+			}
+			ev.Period = &bacnet.CalendarEntry{WeekNDay: &w}
+		default:
+			return nil, fmt.Errorf("%s: no date, from-to range, month-week-weekday or calendar", key)
+		}
+		if ev.Priority < 1 || ev.Priority > 16 {
+			return nil, fmt.Errorf("%s: priority must be 1-16", key)
+		}
+
+		var err error
+		ev.TimeValues, err = parseTimeValues(key, cfg.Values, valueType)
+		if err != nil {
+			return nil, err
+		}
+		exceptions = append(exceptions, ev)
+	}
+	return exceptions, nil
+}
+
+// parseWeekNDay parses the month, week and weekday of an exception; empty
+// fields and "*" match any
+func parseWeekNDay(cfg specialEventConfig) (bacnet.WeekNDay, error) {
+	field := func(name, s string, max int) (uint8, error) {
+		if s == "" || s == "*" {
+			return 0xFF, nil
+		}
+		n, err := strconv.Atoi(s)
+		if err != nil || n < 1 || n > max {
+			return 0, fmt.Errorf("invalid %s %q", name, s)
+		}
+		return uint8(n), nil
+	}
+
+	month, err := field("month", cfg.Month, 14)
+	if err != nil {
+		return bacnet.WeekNDay{}, err
+	}
+	week, err := field("week", cfg.Week, 9)
+	if err != nil {
+		return bacnet.WeekNDay{}, err
+	}
+
+	day := uint8(0xFF)
+	for i, name := range scheduleDays {
+		if strings.EqualFold(cfg.Weekday, name) {
+			day = uint8(i + 1)
+		}
+	}
+	if day == 0xFF {
+		if day, err = field("weekday", cfg.Weekday, 7); err != nil {
+			return bacnet.WeekNDay{}, err
+		}
+	}
+
+	return bacnet.WeekNDay{Month: month, WeekOfMonth: week, DayOfWeek: day}, nil
+}
+
+// newScheduleFile converts schedules to the format of schedule files
+func newScheduleFile(week bacnet.WeeklySchedule, exceptions bacnet.ExceptionSchedule) scheduleFile {
+	file := scheduleFile{Exceptions: []specialEventConfig{}}
+
+	timeValues := func(daily bacnet.DailySchedule) []timeValueConfig {
+		entries := make([]timeValueConfig, 0, len(daily))
+		for _, tv := range daily {
+			if file.Type == "" {
+				file.Type = scheduleValueTypeOf(tv.Value)
+			}
+			entries = append(entries, timeValueConfig{Time: formatScheduleTime(tv.Time), Value: formatScheduleValue(tv.Value)})
+		}
+		return entries
+	}
+
+	days := []*[]timeValueConfig{&file.Monday, &file.Tuesday, &file.Wednesday, &file.Thursday, &file.Friday, &file.Saturday, &file.Sunday}
+	for i, daily := range week {
+		*days[i] = timeValues(daily)
+	}
+
+	for _, ev := range exceptions {
+		cfg := specialEventConfig{Priority: ev.Priority, Values: timeValues(ev.TimeValues)}
+		switch {
+		case ev.CalendarReference != nil:
+			cfg.Calendar = ev.CalendarReference.String()
+		case ev.Period.Date != nil:
+			cfg.Date = ev.Period.Date.String()
+		case ev.Period.DateRange != nil:
+			cfg.From = ev.Period.DateRange.Start.String()
+			cfg.To = ev.Period.DateRange.End.String()
+		case ev.Period.WeekNDay != nil:
+			w := ev.Period.WeekNDay
+			cfg.Month = formatWeekNDayField(w.Month)
+			cfg.Week = formatWeekNDayField(w.WeekOfMonth)
+			cfg.Weekday = "*"
+			if w.DayOfWeek >= 1 && w.DayOfWeek <= 7 {
+				cfg.Weekday = scheduleDays[w.DayOfWeek-1]
+			}
+		}
+		file.Exceptions = append(file.Exceptions, cfg)
+	}
+
+	return file
+}
+
+// formatWeekNDayField formats a week-and-day field, "*" matching any
+func formatWeekNDayField(v uint8) string {
+	if v == 0xFF {
+		return "*"
+	}
+	return strconv.Itoa(int(v))
+}
+
+// scheduleValueTypeOf returns the schedule file type of a value, or "" for
+// null
+func scheduleValueTypeOf(value interface{}) string {
+	switch value.(type) {
+	case float32:
+		return "real"
+	case uint32:
+		return "unsigned"
+	case int32:
+		return "signed"
+	case bacnet.Enumerated:
+		return "enumerated"
+	case bool:
+		return "boolean"
+	default:
+		return ""
+	}
+}
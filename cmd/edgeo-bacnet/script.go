@@ -0,0 +1,299 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"bufio"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"sort"
+	"strings"
+	"syscall"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+// maxSourceDepth bounds the nesting of sourced files, which would otherwise
+// recurse forever on a file sourcing itself
+const maxSourceDepth = 8
+
+var scriptFailFast bool
+
+var scriptCmd = &cobra.Command{
+	Use:   "script <file> [name=value...]",
+	Short: "Run a file of interactive shell commands",
+	Long: `Script runs a file of interactive shell commands, one per line, so that
+commissioning checklists can be automated and repeated. Blank lines and
+lines starting with # are skipped.
+
+Besides the commands of the interactive shell, scripts use:
+  set <name> <value>                        - Set a variable
+  echo <text>                               - Print a line
+  sleep <duration>                          - Wait, e.g. 5s
+  assert <object> [property] <op> <value>   - Check a value
+  source <file>                             - Run another script
+
+$name and ${name} are replaced by the variable, or else by the environment
+variable of that name; read stores the value it read in $_. The name=value
+arguments set variables before the script runs, and -d selects a device.
+
+assert compares numbers, booleans (active, inactive) and state names with
+==, !=, <, <=, > and >=. Every command that fails is reported with its line
+and the script goes on, unless --fail-fast is given; the command fails if
+any line did.
+
+Example script:
+  use $device
+  write av:1 pv 22.5
+  sleep 2s
+  assert av:1 pv == 22.5
+  assert bv:1 == active
+  assert msv:1 != Off
+
+Examples:
+  # Run a checklist against device 1234
+  edgeo-bacnet script checklist.bacnet device=1234
+
+  # Stop at the first failure
+  edgeo-bacnet script -d 1234 checklist.bacnet --fail-fast`,
+
+	Args: cobra.MinimumNArgs(1),
+
+	// A failing script is not a usage error
+	SilenceUsage: true,
+
+	RunE: runScript,
+}
+
+func init() {
+	scriptCmd.Flags().BoolVar(&scriptFailFast, "fail-fast", false, "Stop at the first failing line")
+}
+
+func runScript(cmd *cobra.Command, args []string) error {
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	session := newInteractiveSession(client, bufio.NewScanner(os.Stdin))
+	if deviceID != 0 {
+		session.use(deviceID)
+	}
+	for _, arg := range args[1:] {
+		name, value, ok := strings.Cut(arg, "=")
+		if !ok || name == "" {
+			return fmt.Errorf("invalid variable %q, expected name=value", arg)
+		}
+		session.vars[name] = value
+	}
+
+	return session.source(ctx, args[0])
+}
+
+// source runs the commands of a file, reporting the lines that fail
+func (s *interactiveSession) source(ctx context.Context, path string) error {
+	if s.depth >= maxSourceDepth {
+		return fmt.Errorf("%s: sourced files nested too deep", path)
+	}
+	f, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	s.depth++
+	defer func() { s.depth-- }()
+
+	scanner := bufio.NewScanner(f)
+	lineNo, commands, failed := 0, 0, 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		commands++
+		if verbose {
+			fmt.Printf("%s:%d: %s\n", path, lineNo, line)
+		}
+
+		err := s.exec(ctx, line)
+		if errors.Is(err, errExit) {
+			break
+		}
+		if err != nil {
+			failed++
+			fmt.Printf("%s:%d: %v\n", path, lineNo, err)
+			if scriptFailFast {
+				return fmt.Errorf("%s: stopped at line %d", path, lineNo)
+			}
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return fmt.Errorf("%s: %w", path, err)
+	}
+
+	if failed > 0 {
+		return fmt.Errorf("%s: %d of %d commands failed", path, failed, commands)
+	}
+	return nil
+}
+
+// expand replaces the $name and ${name} variables of a line
+func (s *interactiveSession) expand(line string) (string, error) {
+	var missing []string
+	line = os.Expand(line, func(name string) string {
+		if value, ok := s.vars[name]; ok {
+			return value
+		}
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		missing = append(missing, name)
+		return ""
+	})
+	if len(missing) > 0 {
+		return "", fmt.Errorf("undefined variable %s", missing[0])
+	}
+	return line, nil
+}
+
+// runInteractiveSet sets a variable, or lists them without arguments
+func runInteractiveSet(session *interactiveSession, args []string) error {
+	if len(args) == 0 {
+		names := make([]string, 0, len(session.vars))
+		for name := range session.vars {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Printf("  %s = %s\n", name, session.vars[name])
+		}
+		return nil
+	}
+	if len(args) < 2 {
+		return shellError("Usage: set <name> <value>")
+	}
+	session.vars[args[0]] = strings.Join(args[1:], " ")
+	return nil
+}
+
+// assertOperators are the comparisons of assert
+var assertOperators = map[string]bool{"==": true, "!=": true, "<": true, "<=": true, ">": true, ">=": true}
+
+// runInteractiveAssert reads a property and compares it with a value
+func runInteractiveAssert(ctx context.Context, session *interactiveSession, devID uint32, args []string) error {
+	op := -1
+	for i, arg := range args {
+		if assertOperators[arg] {
+			op = i
+			break
+		}
+	}
+	if op < 1 || op > 2 || op == len(args)-1 {
+		return shellError("Usage: assert <object> [property] <op> <value>")
+	}
+
+	objectID, err := parseObjectIdentifier(session.object(args[0]))
+	if err != nil {
+		return err
+	}
+	propID := bacnet.PropertyPresentValue
+	if op == 2 {
+		if propID, err = parsePropertyIdentifier(args[1]); err != nil {
+			return err
+		}
+	}
+	expected := strings.Join(args[op+1:], " ")
+
+	readCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+	actual, err := session.client.ReadProperty(readCtx, devID, objectID, propID)
+	if err != nil {
+		return err
+	}
+	texts := readStateTexts(readCtx, session.client, devID, objectID, propID)
+
+	check := fmt.Sprintf("%s.%s %s %s", objectID.String(), propID.String(), args[op], expected)
+	passed, err := assertCompare(actual, args[op], expected, texts)
+	if err != nil {
+		return fmt.Errorf("%s: %w", check, err)
+	}
+	display := formatDisplayValue(actual, texts)
+	if !passed {
+		return fmt.Errorf("FAIL %s (value %s)", check, display)
+	}
+	fmt.Printf("PASS %s (value %s)\n", check, display)
+	return nil
+}
+
+// assertCompare compares a value with the expected text: as numbers when
+// both are, otherwise as text, against the value or its state name
+func assertCompare(actual interface{}, op, expected string, texts *bacnet.StateTexts) (bool, error) {
+	e, err := parseValue(expected)
+	if err != nil {
+		return false, err
+	}
+	if ef, ok := verifyNumeric(e); ok {
+		if af, ok := verifyNumeric(actual); ok {
+			// A real compares with the expected value at its own precision
+			if _, ok := actual.(float32); ok {
+				ef = float64(float32(ef))
+			}
+			switch op {
+			case "==":
+				return af == ef, nil
+			case "!=":
+				return af != ef, nil
+			case "<":
+				return af < ef, nil
+			case "<=":
+				return af <= ef, nil
+			case ">":
+				return af > ef, nil
+			default:
+				return af >= ef, nil
+			}
+		}
+	}
+
+	if op != "==" && op != "!=" {
+		return false, fmt.Errorf("%s needs numbers", op)
+	}
+	text := formatValue(e)
+	equal := strings.EqualFold(formatValue(actual), text)
+	if texts != nil {
+		if name, ok := texts.Resolve(actual); ok && strings.EqualFold(name, text) {
+			equal = true
+		}
+	}
+	return equal == (op == "=="), nil
+}
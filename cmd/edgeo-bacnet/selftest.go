@@ -0,0 +1,70 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/spf13/cobra"
+)
+
+var selftestCmd = &cobra.Command{
+	Use:   "selftest",
+	Short: "Run the library self-test",
+	Long: `Selftest exercises encoding, discovery, reads, writes and COV notifications
+against an internal loopback device. Nothing is sent on the network, so it
+can be run anywhere as a sanity check or when collecting support details.
+
+Examples:
+  # Run the self-test
+  edgeo-bacnet selftest
+
+  # JSON report
+  edgeo-bacnet selftest -o json`,
+
+	// A failing check is not a usage error
+	SilenceUsage: true,
+
+	RunE: runSelftest,
+}
+
+func runSelftest(cmd *cobra.Command, args []string) error {
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), timeout*10)
+	defer cancel()
+
+	report, testErr := client.SelfTest(ctx)
+	if report == nil {
+		return testErr
+	}
+
+	headers := []string{"Check", "Result", "Duration", "Error"}
+	rows := make([][]string, 0, len(report.Checks))
+	for _, check := range report.Checks {
+		result, msg := "ok", ""
+		if check.Err != nil {
+			result, msg = "FAIL", check.Err.Error()
+		}
+		rows = append(rows, []string{check.Name, result, check.Duration.String(), msg})
+	}
+	NewFormatter(outputFmt).PrintTable(headers, rows)
+
+	return testErr
+}
@@ -0,0 +1,318 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"strings"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var serveCmd = &cobra.Command{
+	Use:   "serve <device-file>",
+	Short: "Run a virtual BACnet device",
+	Long: `Serve runs a virtual BACnet device with the objects defined in a YAML,
+JSON or TOML file, answering Who-Is, ReadProperty, ReadPropertyMultiple,
+WriteProperty and SubscribeCOV until interrupted with Ctrl+C. It is meant
+for testing front-ends without hardware.
+
+  device:
+    instance: 1234
+    name: Test Device
+    vendor-id: 999
+    vendor-name: Edgeo
+    model: Virtual
+    location: Lab
+  objects:
+    - object: av:1
+      name: Zone Temperature
+      units: 62              # number or symbol, e.g. "°C"
+      value: 21.5
+      cov-increment: 0.5
+    - object: av:2
+      name: Setpoint
+      value: 20
+      commandable: true      # written through the priority array
+    - object: bv:1
+      name: Fan
+      value: active          # active/inactive, on/off, true/false or 1/0
+    - object: msv:1
+      name: Mode
+      states: [Off, Auto, On]
+      value: Auto            # state number or name
+
+Analog, binary and multi-state value objects are supported. Writes from the
+network are printed as they arrive; with -v, so are value changes.
+--local sets the address the device listens on.
+
+Examples:
+  edgeo-bacnet serve device.yaml
+  edgeo-bacnet serve device.yaml --local 0.0.0.0:47809 -v`,
+
+	Args: cobra.ExactArgs(1),
+
+	// Device file errors are reported before serving
+	SilenceUsage: true,
+
+	RunE: runServe,
+}
+
+// ServeConfig is a virtual device file
+type ServeConfig struct {
+	Device  ServeDevice   `mapstructure:"device"`
+	Objects []ServeObject `mapstructure:"objects"`
+}
+
+// ServeDevice is the device object of a virtual device file
+type ServeDevice struct {
+	Instance    uint32 `mapstructure:"instance"`
+	Name        string `mapstructure:"name"`
+	VendorID    uint16 `mapstructure:"vendor-id"`
+	VendorName  string `mapstructure:"vendor-name"`
+	Model       string `mapstructure:"model"`
+	Description string `mapstructure:"description"`
+	Location    string `mapstructure:"location"`
+}
+
+// ServeObject is an object of a virtual device file
+type ServeObject struct {
+	Object       string      `mapstructure:"object"`
+	Name         string      `mapstructure:"name"`
+	Description  string      `mapstructure:"description"`
+	Units        string      `mapstructure:"units"`
+	Value        interface{} `mapstructure:"value"`
+	COVIncrement float32     `mapstructure:"cov-increment"`
+	Commandable  bool        `mapstructure:"commandable"`
+	States       []string    `mapstructure:"states"`
+}
+
+func runServe(cmd *cobra.Command, args []string) error {
+	config, err := loadServeConfig(args[0])
+	if err != nil {
+		return err
+	}
+
+	registry := bacnet.NewObjectRegistry()
+	onWrite := func(object *bacnet.LocalObject, value interface{}, priority uint8) error {
+		at := ""
+		if priority != 0 {
+			at = fmt.Sprintf(" at priority %d", priority)
+		}
+		fmt.Printf("[%s] write %s (%s) = %s%s\n",
+			time.Now().Format("15:04:05.000"), object.ObjectID(), object.Name(), formatValue(value), at)
+		return nil
+	}
+	for i, cfg := range config.Objects {
+		object, err := newServeObject(cfg, onWrite)
+		if err != nil {
+			return fmt.Errorf("object %d: %w", i+1, err)
+		}
+		if err := registry.Add(object); err != nil {
+			return fmt.Errorf("object %d: %w", i+1, err)
+		}
+	}
+	if verbose {
+		registry.OnCOV(func(object *bacnet.LocalObject) {
+			fmt.Printf("[%s] %s (%s) = %s\n",
+				time.Now().Format("15:04:05.000"), object.ObjectID(), object.Name(), formatValue(object.PresentValue()))
+		})
+	}
+
+	dev := config.Device
+	deviceOpts := []bacnet.DeviceOption{bacnet.WithObjectDatabase(registry)}
+	if dev.Name != "" {
+		deviceOpts = append(deviceOpts, bacnet.WithDeviceName(dev.Name))
+	}
+	if dev.VendorID != 0 || dev.VendorName != "" {
+		deviceOpts = append(deviceOpts, bacnet.WithDeviceVendor(dev.VendorID, dev.VendorName))
+	}
+	if dev.Model != "" {
+		deviceOpts = append(deviceOpts, bacnet.WithDeviceModel(dev.Model))
+	}
+	if dev.Description != "" {
+		deviceOpts = append(deviceOpts, bacnet.WithDeviceDescription(dev.Description))
+	}
+	if dev.Location != "" {
+		deviceOpts = append(deviceOpts, bacnet.WithDeviceLocation(dev.Location))
+	}
+	device := bacnet.NewDevice(dev.Instance, deviceOpts...)
+
+	client, err := createClient(bacnet.WithLocalDevice(device))
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	// Announce the device so that front-ends bind it without a Who-Is
+	if err := client.IAm(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "Warning: failed to broadcast I-Am: %v\n", err)
+	}
+
+	fmt.Fprintf(os.Stderr, "Serving device %d (%s) with %d objects, press Ctrl+C to stop\n",
+		dev.Instance, device.Name(), len(config.Objects))
+	if verbose {
+		for _, oid := range registry.Objects() {
+			object, _ := registry.Object(oid)
+			fmt.Fprintf(os.Stderr, "  %s (%s) = %s\n", oid, object.Name(), formatValue(object.PresentValue()))
+		}
+	}
+
+	<-ctx.Done()
+	return nil
+}
+
+// loadServeConfig reads a virtual device file
+func loadServeConfig(path string) (*ServeConfig, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read device file: %w", err)
+	}
+
+	var config ServeConfig
+	if err := v.Unmarshal(&config); err != nil {
+		return nil, fmt.Errorf("parse device file: %w", err)
+	}
+	if config.Device.Instance > 4194302 {
+		return nil, fmt.Errorf("device instance %d out of range 0-4194302", config.Device.Instance)
+	}
+	if !v.IsSet("device.instance") {
+		return nil, fmt.Errorf("%s sets no device instance", path)
+	}
+	return &config, nil
+}
+
+// newServeObject creates the local object of a virtual device file entry
+func newServeObject(cfg ServeObject, onWrite bacnet.WriteHandler) (*bacnet.LocalObject, error) {
+	oid, err := parseObjectIdentifier(cfg.Object)
+	if err != nil {
+		return nil, fmt.Errorf("invalid object %q: %w", cfg.Object, err)
+	}
+	name := cfg.Name
+	if name == "" {
+		name = oid.String()
+	}
+
+	opts := []bacnet.ObjectOption{bacnet.WithWriteHandler(onWrite)}
+	if cfg.Description != "" {
+		opts = append(opts, bacnet.WithObjectDescription(cfg.Description))
+	}
+	if cfg.Units != "" {
+		units, err := parseEngineeringUnits(cfg.Units)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", oid, err)
+		}
+		opts = append(opts, bacnet.WithUnits(units))
+	}
+	if cfg.COVIncrement != 0 {
+		opts = append(opts, bacnet.WithObjectCOVIncrement(cfg.COVIncrement))
+	}
+	if cfg.Commandable {
+		opts = append(opts, bacnet.WithCommandable())
+	}
+	if len(cfg.States) > 0 {
+		opts = append(opts, bacnet.WithStateText(cfg.States...))
+	}
+
+	var object *bacnet.LocalObject
+	switch oid.Type {
+	case bacnet.ObjectTypeAnalogValue:
+		object = bacnet.NewAnalogValue(oid.Instance, name, opts...)
+	case bacnet.ObjectTypeBinaryValue:
+		object = bacnet.NewBinaryValue(oid.Instance, name, opts...)
+	case bacnet.ObjectTypeMultiStateValue:
+		states := uint32(len(cfg.States))
+		if states == 0 {
+			return nil, fmt.Errorf("%s: states are required", oid)
+		}
+		object = bacnet.NewMultiStateValue(oid.Instance, name, states, opts...)
+	default:
+		return nil, fmt.Errorf("%s: only analog, binary and multi-state value objects can be served", oid)
+	}
+
+	if cfg.Value != nil {
+		value, err := serveInitialValue(oid.Type, cfg.Value, cfg.States)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", oid, err)
+		}
+		if err := object.SetPresentValue(value); err != nil {
+			return nil, fmt.Errorf("%s: invalid value %v: %w", oid, cfg.Value, err)
+		}
+	}
+	return object, nil
+}
+
+// serveInitialValue converts the value of a virtual device file entry:
+// binary values accept active/inactive, on/off and booleans, multi-state
+// values their state names
+func serveInitialValue(objectType bacnet.ObjectType, value interface{}, states []string) (interface{}, error) {
+	s, ok := value.(string)
+	if !ok {
+		return value, nil
+	}
+
+	switch objectType {
+	case bacnet.ObjectTypeBinaryValue:
+		switch strings.ToLower(s) {
+		case "active", "on", "true", "1":
+			return true, nil
+		case "inactive", "off", "false", "0":
+			return false, nil
+		}
+	case bacnet.ObjectTypeMultiStateValue:
+		for i, state := range states {
+			if strings.EqualFold(s, state) {
+				return uint32(i + 1), nil
+			}
+		}
+	}
+
+	f, err := strconv.ParseFloat(s, 64)
+	if err != nil {
+		return nil, fmt.Errorf("invalid value %q", s)
+	}
+	return f, nil
+}
+
+// parseEngineeringUnits parses units given as their number or symbol
+func parseEngineeringUnits(s string) (bacnet.EngineeringUnits, error) {
+	if n, err := strconv.ParseUint(s, 10, 16); err == nil {
+		return bacnet.EngineeringUnits(n), nil
+	}
+	for n := 0; n < 256; n++ {
+		units := bacnet.EngineeringUnits(n)
+		if strings.EqualFold(units.String(), s) {
+			return units, nil
+		}
+	}
+	return 0, fmt.Errorf("unknown units %q", s)
+}
@@ -0,0 +1,114 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/reflection"
+
+	"github.com/edgeo-scada/bacnet/grpcbacnet"
+)
+
+var (
+	grpcListen      string
+	grpcCOVLifetime uint32
+)
+
+var serveGRPCCmd = &cobra.Command{
+	Use:   "serve-grpc",
+	Short: "Run a gRPC service to BACnet devices",
+	Long: `Serve-grpc exposes BACnet devices through the gRPC service defined in
+grpcbacnet/bacnet.proto: Discover, ReadProperty, WriteProperty,
+ReadMultiple and a SubscribeCOV stream. All calls share one long-lived
+client, configured with the global flags. Server reflection is enabled,
+so the service can be explored with tools such as grpcurl.
+
+Examples:
+  edgeo-bacnet serve-grpc --listen :50051
+  grpcurl -plaintext -d '{"device_id": 1234, "object": {"type": 2, "instance": 1}, "property": 85}' \
+      localhost:50051 edgeo.bacnet.v1.BACnet/ReadProperty`,
+
+	// Service errors are not a usage error
+	SilenceUsage: true,
+
+	RunE: runServeGRPC,
+}
+
+func init() {
+	serveGRPCCmd.Flags().StringVar(&grpcListen, "listen", ":50051", "gRPC listen address")
+	serveGRPCCmd.Flags().Uint32Var(&grpcCOVLifetime, "cov-lifetime", 300, "Default lifetime in seconds of the COV subscriptions of SubscribeCOV streams")
+}
+
+func runServeGRPC(cmd *cobra.Command, args []string) error {
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	listener, err := net.Listen("tcp", grpcListen)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+
+	server := grpc.NewServer()
+	grpcbacnet.RegisterBACnetServer(server, grpcbacnet.NewServer(client,
+		grpcbacnet.WithCOVLifetime(time.Duration(grpcCOVLifetime)*time.Second),
+		grpcbacnet.WithRequestTimeout(operationDeadline(1)),
+	))
+	reflection.Register(server)
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.Serve(listener)
+	}()
+	fmt.Fprintf(os.Stderr, "Serving gRPC on %s, press Ctrl+C to stop\n", listener.Addr())
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serve grpc: %w", err)
+	case <-ctx.Done():
+	}
+
+	// COV streams only end with their calls, so they are cut after a grace
+	// period
+	stopped := make(chan struct{})
+	go func() {
+		server.GracefulStop()
+		close(stopped)
+	}()
+	select {
+	case <-stopped:
+	case <-time.After(5 * time.Second):
+		server.Stop()
+	}
+	return nil
+}
@@ -0,0 +1,546 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"strconv"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+	"github.com/edgeo-scada/bacnet/internal/transport"
+)
+
+var (
+	httpListen      string
+	httpCOVLifetime uint32
+)
+
+var serveHTTPCmd = &cobra.Command{
+	Use:   "serve-http",
+	Short: "Run a REST gateway to BACnet devices",
+	Long: `Serve-http exposes BACnet devices over HTTP so that applications without
+a BACnet stack can read, write and subscribe to them. All requests share
+one long-lived client, configured with the global flags.
+
+Endpoints:
+  GET /devices?low=&high=&wait=3s
+      Discover devices with Who-Is
+  GET /devices/{id}/objects/{object}/properties/{property}?index=
+      Read a property
+  PUT /devices/{id}/objects/{object}/properties/{property}
+      Write a property; the body is {"value": 21.5, "priority": 8},
+      with optional "index" and "type" (real, unsigned, signed,
+      enumerated, boolean or string)
+  GET /cov?device={id}&object={object}[&object=...][&increment=]
+      WebSocket streaming a JSON message for each COV notification; with
+      an increment, the present values are subscribed to with
+      SubscribeCOVProperty, which the device must support
+
+Objects and properties are given as in the other commands, e.g. av:1 and
+present-value. Errors are returned as {"error": "..."} with 404 for unknown
+devices, objects and properties, 502 for other errors, rejects and aborts
+from the device and 504 for timeouts.
+
+Examples:
+  edgeo-bacnet serve-http --listen :8080
+  curl localhost:8080/devices/1234/objects/av:1/properties/present-value
+  curl -X PUT -d '{"value": 21.5, "priority": 8}' \
+      localhost:8080/devices/1234/objects/av:1/properties/present-value`,
+
+	// Gateway errors are not a usage error
+	SilenceUsage: true,
+
+	RunE: runServeHTTP,
+}
+
+func init() {
+	serveHTTPCmd.Flags().StringVar(&httpListen, "listen", ":8080", "HTTP listen address")
+	serveHTTPCmd.Flags().Uint32Var(&httpCOVLifetime, "cov-lifetime", 300, "Lifetime in seconds of the COV subscriptions of /cov streams, renewed at half")
+}
+
+func runServeHTTP(cmd *cobra.Command, args []string) error {
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	gw := &gateway{client: client}
+	mux := http.NewServeMux()
+	mux.HandleFunc("GET /devices", gw.handleDevices)
+	mux.HandleFunc("GET /devices/{device}/objects/{object}/properties/{property}", gw.handleRead)
+	mux.HandleFunc("PUT /devices/{device}/objects/{object}/properties/{property}", gw.handleWrite)
+	mux.HandleFunc("GET /cov", gw.handleCOV)
+
+	// Requests, and WebSocket streams, end with the command
+	server := &http.Server{
+		Addr:              httpListen,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+		BaseContext:       func(net.Listener) context.Context { return ctx },
+	}
+
+	errCh := make(chan error, 1)
+	go func() {
+		errCh <- server.ListenAndServe()
+	}()
+	fmt.Fprintf(os.Stderr, "Serving REST gateway on %s, press Ctrl+C to stop\n", httpListen)
+
+	select {
+	case err := <-errCh:
+		return fmt.Errorf("serve http: %w", err)
+	case <-ctx.Done():
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	return server.Shutdown(shutdownCtx)
+}
+
+// gateway serves the endpoints of serve-http with a shared client
+type gateway struct {
+	client *bacnet.Client
+}
+
+// GatewayDevice is a device discovered by GET /devices
+type GatewayDevice struct {
+	Site          string `json:"site,omitempty"`
+	Device        uint32 `json:"device"`
+	Address       string `json:"address"`
+	MaxAPDULength uint16 `json:"max_apdu_length"`
+	Segmentation  string `json:"segmentation"`
+	VendorID      uint16 `json:"vendor_id"`
+}
+
+// GatewayProperty is a property read or written through the gateway
+type GatewayProperty struct {
+	Site     string      `json:"site,omitempty"`
+	Device   uint32      `json:"device"`
+	Object   string      `json:"object"`
+	Property string      `json:"property"`
+	Index    *uint32     `json:"index,omitempty"`
+	Value    interface{} `json:"value"`
+	Priority uint8       `json:"priority,omitempty"`
+}
+
+// GatewayWrite is the body of a PUT to a property
+type GatewayWrite struct {
+	Value    interface{} `json:"value"`
+	Priority uint8       `json:"priority"`
+	Index    *uint32     `json:"index"`
+	Type     string      `json:"type"`
+}
+
+// GatewayNotification is a message of a /cov stream
+type GatewayNotification struct {
+	Time     string      `json:"time"`
+	Site     string      `json:"site,omitempty"`
+	Device   uint32      `json:"device"`
+	Object   string      `json:"object"`
+	Property string      `json:"property"`
+	Value    interface{} `json:"value"`
+}
+
+func (g *gateway) handleDevices(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	wait := 3 * time.Second
+	if s := query.Get("wait"); s != "" {
+		d, err := time.ParseDuration(s)
+		if err != nil {
+			writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("invalid wait: %w", err))
+			return
+		}
+		wait = d
+	}
+
+	opts := []bacnet.DiscoverOption{bacnet.WithDiscoveryTimeout(wait)}
+	if query.Has("low") || query.Has("high") {
+		low, err := parseQueryUint(query.Get("low"), 0)
+		if err != nil {
+			writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("invalid low: %w", err))
+			return
+		}
+		high, err := parseQueryUint(query.Get("high"), 0x3FFFFF)
+		if err != nil {
+			writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("invalid high: %w", err))
+			return
+		}
+		opts = append(opts, bacnet.WithDeviceRange(low, high))
+	}
+
+	devices, err := g.client.WhoIs(r.Context(), opts...)
+	if err != nil {
+		writeGatewayBACnetError(w, err)
+		return
+	}
+
+	result := make([]GatewayDevice, 0, len(devices))
+	for _, dev := range devices {
+		result = append(result, GatewayDevice{
+			Site:          g.client.Site(),
+			Device:        dev.ObjectID.Instance,
+			Address:       formatAddress(dev.Address),
+			MaxAPDULength: dev.MaxAPDULength,
+			Segmentation:  dev.Segmentation.String(),
+			VendorID:      dev.VendorID,
+		})
+	}
+	writeGatewayJSON(w, http.StatusOK, result)
+}
+
+func (g *gateway) handleRead(w http.ResponseWriter, r *http.Request) {
+	prop, err := g.parseGatewayPath(r)
+	if err != nil {
+		writeGatewayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var opts []bacnet.ReadOption
+	if s := r.URL.Query().Get("index"); s != "" {
+		index, err := parseQueryUint(s, 0)
+		if err != nil {
+			writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("invalid index: %w", err))
+			return
+		}
+		prop.Index = &index
+		opts = append(opts, bacnet.WithArrayIndex(index))
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), operationDeadline(1))
+	defer cancel()
+
+	value, err := g.client.ReadProperty(ctx, prop.deviceID, prop.objectID, prop.propertyID, opts...)
+	if err != nil {
+		writeGatewayBACnetError(w, err)
+		return
+	}
+	prop.Value = gatewayValue(value)
+	writeGatewayJSON(w, http.StatusOK, prop.GatewayProperty)
+}
+
+func (g *gateway) handleWrite(w http.ResponseWriter, r *http.Request) {
+	prop, err := g.parseGatewayPath(r)
+	if err != nil {
+		writeGatewayError(w, http.StatusBadRequest, err)
+		return
+	}
+
+	var body GatewayWrite
+	decoder := json.NewDecoder(http.MaxBytesReader(w, r.Body, 1<<20))
+	decoder.UseNumber()
+	if err := decoder.Decode(&body); err != nil {
+		writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("invalid body: %w", err))
+		return
+	}
+	if body.Priority > 16 {
+		writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("priority %d out of range 1-16", body.Priority))
+		return
+	}
+	value, err := parseGatewayValue(body.Value, body.Type)
+	if err != nil {
+		writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("invalid value: %w", err))
+		return
+	}
+
+	var opts []bacnet.WriteOption
+	if body.Priority > 0 {
+		opts = append(opts, bacnet.WithPriority(body.Priority))
+	}
+	if body.Index != nil {
+		opts = append(opts, bacnet.WithWriteArrayIndex(*body.Index))
+	}
+
+	ctx, cancel := context.WithTimeout(r.Context(), operationDeadline(1))
+	defer cancel()
+
+	if err := g.client.WriteProperty(ctx, prop.deviceID, prop.objectID, prop.propertyID, value, opts...); err != nil {
+		writeGatewayBACnetError(w, err)
+		return
+	}
+	prop.Index = body.Index
+	prop.Value = gatewayValue(value)
+	prop.Priority = body.Priority
+	writeGatewayJSON(w, http.StatusOK, prop.GatewayProperty)
+}
+
+func (g *gateway) handleCOV(w http.ResponseWriter, r *http.Request) {
+	query := r.URL.Query()
+	devID, err := parseQueryUint(query.Get("device"), 0)
+	if err != nil || !query.Has("device") {
+		writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("device is required"))
+		return
+	}
+	if len(query["object"]) == 0 {
+		writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("at least one object is required"))
+		return
+	}
+	var objectIDs []bacnet.ObjectIdentifier
+	for _, s := range query["object"] {
+		objectID, err := parseObjectIdentifier(s)
+		if err != nil {
+			writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("invalid object %q: %w", s, err))
+			return
+		}
+		objectIDs = append(objectIDs, objectID)
+	}
+
+	subOpts := []bacnet.SubscribeOption{bacnet.WithSubscriptionLifetime(httpCOVLifetime)}
+	if s := query.Get("increment"); s != "" {
+		increment, err := strconv.ParseFloat(s, 32)
+		if err != nil {
+			writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("invalid increment: %w", err))
+			return
+		}
+		if !(increment >= 0) || math.IsInf(increment, 0) {
+			writeGatewayError(w, http.StatusBadRequest, fmt.Errorf("invalid increment %q", s))
+			return
+		}
+		subOpts = append(subOpts, bacnet.WithCOVIncrement(float32(increment)))
+	}
+
+	conn, err := transport.AcceptWebSocket(w, r, "")
+	if err != nil {
+		// The failed upgrade has been answered already
+		return
+	}
+	defer conn.Close()
+
+	// The client is closed by the peer or the command, whichever is first;
+	// reading also answers control frames
+	ctx, cancel := context.WithCancel(r.Context())
+	defer cancel()
+	go func() {
+		defer cancel()
+		for {
+			if _, err := conn.ReadMessage(); err != nil {
+				return
+			}
+		}
+	}()
+
+	// Notifications arrive on the receive goroutine of the client; a peer
+	// that stops reading must not hold it up
+	notify := func(devID uint32, objectID bacnet.ObjectIdentifier, values []bacnet.PropertyValue) {
+		now := time.Now().Format(time.RFC3339Nano)
+		for _, pv := range values {
+			message, err := json.Marshal(GatewayNotification{
+				Time:     now,
+				Site:     g.client.Site(),
+				Device:   devID,
+				Object:   objectID.String(),
+				Property: pv.PropertyID.String(),
+				Value:    gatewayValue(pv.Value),
+			})
+			if err != nil {
+				continue
+			}
+			conn.SetWriteDeadline(time.Now().Add(timeout))
+			if err := conn.WriteText(message); err != nil {
+				cancel()
+				return
+			}
+		}
+	}
+
+	subs := make(map[bacnet.ObjectIdentifier]uint32, len(objectIDs))
+	defer func() {
+		unsubCtx, unsubCancel := context.WithTimeout(context.Background(), timeout)
+		defer unsubCancel()
+		for objectID, subID := range subs {
+			g.client.UnsubscribeCOV(unsubCtx, devID, objectID, subID)
+		}
+	}()
+
+	for _, objectID := range objectIDs {
+		subCtx, subCancel := context.WithTimeout(ctx, operationDeadline(1))
+		subID, err := g.client.SubscribeCOV(subCtx, devID, objectID, notify, subOpts...)
+		subCancel()
+		if err != nil {
+			conn.CloseWithStatus(1011, fmt.Sprintf("subscribe to %s: %v", objectID, err)) // internal error
+			return
+		}
+		subs[objectID] = subID
+	}
+	if verbose {
+		fmt.Fprintf(os.Stderr, "[%s] %s subscribed to %d object(s) of device %d\n",
+			time.Now().Format("15:04:05.000"), r.RemoteAddr, len(subs), devID)
+	}
+
+	// Without a lifetime there is nothing to renew
+	if httpCOVLifetime == 0 {
+		<-ctx.Done()
+		return
+	}
+
+	ticker := time.NewTicker(time.Duration(httpCOVLifetime) * time.Second / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			for objectID, subID := range subs {
+				renewCtx, renewCancel := context.WithTimeout(ctx, operationDeadline(1))
+				err := g.client.RenewCOV(renewCtx, devID, objectID, subID, subOpts...)
+				renewCancel()
+				if err != nil && ctx.Err() == nil && verbose {
+					fmt.Fprintf(os.Stderr, "[%s] Failed to renew %s for %s: %v\n",
+						time.Now().Format("15:04:05.000"), objectID, r.RemoteAddr, err)
+				}
+			}
+		}
+	}
+}
+
+// gatewayProperty is the property addressed by the path of a request
+type gatewayProperty struct {
+	GatewayProperty
+
+	deviceID   uint32
+	objectID   bacnet.ObjectIdentifier
+	propertyID bacnet.PropertyIdentifier
+}
+
+// parseGatewayPath parses the device, object and property of a request path
+func (g *gateway) parseGatewayPath(r *http.Request) (*gatewayProperty, error) {
+	devID, err := parseQueryUint(r.PathValue("device"), 0)
+	if err != nil {
+		return nil, fmt.Errorf("invalid device: %w", err)
+	}
+	objectID, err := parseObjectIdentifier(r.PathValue("object"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid object: %w", err)
+	}
+	propID, err := parsePropertyIdentifier(r.PathValue("property"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid property: %w", err)
+	}
+
+	return &gatewayProperty{
+		GatewayProperty: GatewayProperty{
+			Site:     g.client.Site(),
+			Device:   devID,
+			Object:   objectID.String(),
+			Property: propID.String(),
+		},
+		deviceID:   devID,
+		objectID:   objectID,
+		propertyID: propID,
+	}, nil
+}
+
+// parseQueryUint parses an unsigned query or path parameter, def when empty
+func parseQueryUint(s string, def uint32) (uint32, error) {
+	if s == "" {
+		return def, nil
+	}
+	n, err := strconv.ParseUint(s, 10, 32)
+	return uint32(n), err
+}
+
+// parseGatewayValue converts the JSON value of a write, as the given BACnet
+// type or else detected like the values of the write command
+func parseGatewayValue(value interface{}, valueType string) (interface{}, error) {
+	if value == nil {
+		return nil, nil
+	}
+
+	switch valueType {
+	case "":
+	case "string":
+		return fmt.Sprint(value), nil
+	default:
+		return parseTypedValue(fmt.Sprint(value), valueType)
+	}
+
+	switch v := value.(type) {
+	case bool, string:
+		return v, nil
+	case json.Number:
+		return parseValue(v.String())
+	default:
+		return nil, fmt.Errorf("unsupported value %v", value)
+	}
+}
+
+// gatewayValue converts a decoded BACnet value for JSON encoding
+func gatewayValue(value interface{}) interface{} {
+	switch v := value.(type) {
+	case nil, bool, string, uint32, int32, float64:
+		return v
+	case float32:
+		// Shortest representation of the float32, not of its float64
+		return json.Number(strconv.FormatFloat(float64(v), 'g', -1, 32))
+	case []byte:
+		return fmt.Sprintf("%x", v)
+	case []interface{}:
+		values := make([]interface{}, len(v))
+		for i, item := range v {
+			values[i] = gatewayValue(item)
+		}
+		return values
+	case fmt.Stringer:
+		return v.String()
+	default:
+		return v
+	}
+}
+
+func writeGatewayJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+func writeGatewayError(w http.ResponseWriter, status int, err error) {
+	writeGatewayJSON(w, status, map[string]string{"error": err.Error()})
+}
+
+// writeGatewayBACnetError replies with the status matching a client error
+func writeGatewayBACnetError(w http.ResponseWriter, err error) {
+	var bacnetErr *bacnet.BACnetError
+	var rejectErr *bacnet.RejectError
+	var abortErr *bacnet.AbortError
+
+	switch {
+	case bacnet.IsDeviceNotFound(err), bacnet.IsPropertyNotFound(err):
+		writeGatewayError(w, http.StatusNotFound, err)
+	case bacnet.IsTimeout(err), errors.Is(err, context.DeadlineExceeded):
+		writeGatewayError(w, http.StatusGatewayTimeout, err)
+	case errors.As(err, &bacnetErr), errors.As(err, &rejectErr), errors.As(err, &abortErr):
+		writeGatewayError(w, http.StatusBadGateway, err)
+	default:
+		writeGatewayError(w, http.StatusInternalServerError, err)
+	}
+}
@@ -0,0 +1,103 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/spf13/cobra"
+)
+
+var (
+	timesyncTime string
+	timesyncUTC  bool
+)
+
+var timesyncCmd = &cobra.Command{
+	Use:   "timesync",
+	Short: "Synchronize the clock of devices",
+	Long: `Timesync sends a TimeSynchronization request with the current time of this
+host, or the --time given, to the device given with -d, or broadcasts it to
+every device of the network. With --utc, a UTCTimeSynchronization request
+is sent instead, for devices that apply their own time zone.
+
+--time takes an RFC 3339 time, or a local date and time such as
+"2026-01-05 08:00:00".
+
+Examples:
+  # Set the clock of every device of the network
+  edgeo-bacnet timesync
+
+  # Set the clock of one device in UTC
+  edgeo-bacnet timesync -d 1234 --utc
+
+  # Set a given time
+  edgeo-bacnet timesync -d 1234 --time "2026-01-05 08:00:00"`,
+	RunE: runTimesync,
+}
+
+func init() {
+	timesyncCmd.Flags().StringVar(&timesyncTime, "time", "", "Time to send (default now)")
+	timesyncCmd.Flags().BoolVar(&timesyncUTC, "utc", false, "Send a UTCTimeSynchronization request")
+}
+
+func runTimesync(cmd *cobra.Command, args []string) error {
+	t := time.Now()
+	if timesyncTime != "" {
+		var err error
+		if t, err = parseTimeFlag(timesyncTime, t); err != nil {
+			return fmt.Errorf("invalid --time: %w", err)
+		}
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(1))
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	service, sent := "TimeSynchronization", t
+	if timesyncUTC {
+		service, sent = "UTCTimeSynchronization", t.UTC()
+	}
+
+	switch {
+	case deviceID == 0:
+		err = client.BroadcastTimeSynchronization(ctx, t, timesyncUTC)
+	case timesyncUTC:
+		err = client.UTCTimeSynchronization(ctx, deviceID, t)
+	default:
+		err = client.TimeSynchronization(ctx, deviceID, t)
+	}
+	if err != nil {
+		return fmt.Errorf("%s: %w", service, err)
+	}
+
+	target := "broadcast"
+	if deviceID != 0 {
+		target = fmt.Sprintf("device %d", deviceID)
+	}
+	fmt.Printf("Sent %s to %s: %s\n", service, target, sent.Format("2006-01-02 15:04:05.00 MST"))
+	return nil
+}
@@ -0,0 +1,371 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/spf13/cobra"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	trendObject string
+	trendFrom   string
+	trendTo     string
+	trendUTC    bool
+)
+
+var trendCmd = &cobra.Command{
+	Use:   "trend",
+	Short: "Export the records of a trend log",
+	Long: `Trend downloads the records of a Trend Log or Trend Log Multiple object
+logged between --from and --to, paging through its log buffer with
+ReadRange, and prints them with RFC 3339 timestamps for import into a
+historian.
+
+--from and --to take an RFC 3339 time, a local date and time such as
+"2026-01-05 08:00", a local date, or a duration before now such as 24h.
+Times are sent to the device in the local time zone, which should be the
+time zone of the device.
+
+Examples:
+  # Records of the last 24 hours
+  edgeo-bacnet trend -d 1234 -O trend-log:3
+
+  # One day, as CSV
+  edgeo-bacnet trend -d 1234 -O tl:3 --from 2026-01-05 --to 2026-01-06 -o csv > tl3.csv
+
+  # A trend log multiple, as JSON with UTC timestamps
+  edgeo-bacnet trend -d 1234 -O tlm:1 --from 2h --utc -o json
+
+  # Backfill a historian with InfluxDB line protocol; only value records
+  # are written, those of a trend log multiple tagged with their index
+  edgeo-bacnet trend -d 1234 -O tl:3 --from 168h -o influx > tl3.lp`,
+	RunE: runTrend,
+}
+
+func init() {
+	trendCmd.Flags().StringVarP(&trendObject, "object", "O", "", "Trend log object (e.g., trend-log:3 or tlm:1)")
+	trendCmd.Flags().StringVar(&trendFrom, "from", "24h", "Start of the export")
+	trendCmd.Flags().StringVar(&trendTo, "to", "", "End of the export (default now)")
+	trendCmd.Flags().BoolVar(&trendUTC, "utc", false, "Print timestamps in UTC")
+
+	trendCmd.MarkFlagRequired("object")
+}
+
+// TrendRecord is an exported log record
+type TrendRecord struct {
+	Timestamp   string   `json:"timestamp"`
+	Kind        string   `json:"kind"`
+	Value       string   `json:"value,omitempty"`
+	Values      []string `json:"values,omitempty"`
+	StatusFlags string   `json:"status_flags,omitempty"`
+	Detail      string   `json:"detail,omitempty"`
+}
+
+func runTrend(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	objectID, err := parseObjectIdentifier(trendObject)
+	if err != nil {
+		return fmt.Errorf("invalid object: %w", err)
+	}
+	if objectID.Type != bacnet.ObjectTypeTrendLog && objectID.Type != bacnet.ObjectTypeTrendLogMultiple {
+		return fmt.Errorf("%s is not a trend-log or trend-log-multiple object", objectID)
+	}
+
+	now := time.Now()
+	from, err := parseTimeFlag(trendFrom, now)
+	if err != nil {
+		return fmt.Errorf("invalid --from: %w", err)
+	}
+	to := now
+	if trendTo != "" {
+		if to, err = parseTimeFlag(trendTo, now); err != nil {
+			return fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+	if !from.Before(to) {
+		return fmt.Errorf("--from %s is not before --to %s", from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	// A long export takes many ReadRange requests; the deadline bounds the
+	// connection only
+	ctx := context.Background()
+	connectCtx, cancel := context.WithTimeout(ctx, operationDeadline(1))
+	defer cancel()
+
+	if err := client.Connect(connectCtx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	var records []TrendRecord
+	if objectID.Type == bacnet.ObjectTypeTrendLog {
+		logRecords, err := client.ReadTrendLog(ctx, deviceID, objectID.Instance, from, to)
+		if err != nil {
+			return fmt.Errorf("read trend log: %w", err)
+		}
+		if outputFmt == "influx" {
+			return outputTrendLines(objectID, logRecords)
+		}
+		for _, rec := range logRecords {
+			records = append(records, trendRecord(rec))
+		}
+	} else {
+		logRecords, err := client.ReadTrendLogMultiple(ctx, deviceID, objectID.Instance, from, to)
+		if err != nil {
+			return fmt.Errorf("read trend log multiple: %w", err)
+		}
+		if outputFmt == "influx" {
+			return outputTrendMultipleLines(objectID, logRecords)
+		}
+		for _, rec := range logRecords {
+			records = append(records, trendMultipleRecord(rec))
+		}
+	}
+
+	if verbose {
+		fmt.Fprintf(os.Stderr, "%d records of %s from %s to %s\n", len(records), objectID,
+			from.Format(time.RFC3339), to.Format(time.RFC3339))
+	}
+
+	return outputTrend(records, objectID.Type == bacnet.ObjectTypeTrendLogMultiple)
+}
+
+// parseTimeFlag parses an RFC 3339 time, a local date and time, a local
+// date, or a duration before now
+func parseTimeFlag(s string, now time.Time) (time.Time, error) {
+	if d, err := time.ParseDuration(s); err == nil {
+		if d < 0 {
+			d = -d
+		}
+		return now.Add(-d), nil
+	}
+	if t, err := time.Parse(time.RFC3339, s); err == nil {
+		return t.In(time.Local), nil
+	}
+	for _, layout := range []string{"2006-01-02 15:04:05", "2006-01-02T15:04:05", "2006-01-02 15:04", "2006-01-02T15:04", "2006-01-02"} {
+		if t, err := time.ParseInLocation(layout, s, time.Local); err == nil {
+			return t, nil
+		}
+	}
+	return time.Time{}, fmt.Errorf("expected an RFC 3339 time, a date and time or a duration, got %q", s)
+}
+
+func trendTimestamp(t time.Time) string {
+	if trendUTC {
+		t = t.UTC()
+	}
+	return t.Format(time.RFC3339)
+}
+
+func trendRecord(rec bacnet.LogRecord) TrendRecord {
+	r := TrendRecord{
+		Timestamp: trendTimestamp(rec.Timestamp),
+		Kind:      rec.Kind.String(),
+	}
+	switch rec.Kind {
+	case bacnet.LogRecordValue:
+		r.Value = trendValue(rec.Value)
+	case bacnet.LogRecordFailure:
+		r.Detail = rec.Err.Error()
+	default:
+		r.Detail = trendDetail(rec.Kind, rec.Status, rec.TimeChange)
+	}
+	if rec.StatusFlags != nil {
+		r.StatusFlags = trendStatusFlags(*rec.StatusFlags)
+	}
+	return r
+}
+
+func trendMultipleRecord(rec bacnet.LogMultipleRecord) TrendRecord {
+	r := TrendRecord{
+		Timestamp: trendTimestamp(rec.Timestamp),
+		Kind:      rec.Kind.String(),
+	}
+	if rec.Kind != bacnet.LogRecordValue {
+		r.Detail = trendDetail(rec.Kind, rec.Status, rec.TimeChange)
+		return r
+	}
+	r.Values = make([]string, len(rec.Values))
+	for i, v := range rec.Values {
+		if err, ok := v.(error); ok {
+			r.Values[i] = "error: " + err.Error()
+			continue
+		}
+		r.Values[i] = trendValue(v)
+	}
+	return r
+}
+
+// trendValue formats a logged value for a historian: numbers in full
+// precision, nothing for null and the hex encoding of other datatypes
+func trendValue(v interface{}) string {
+	switch v := v.(type) {
+	case nil:
+		return ""
+	case float32:
+		return strconv.FormatFloat(float64(v), 'g', -1, 32)
+	case []byte:
+		return fmt.Sprintf("%x", v)
+	default:
+		return formatValue(v)
+	}
+}
+
+func trendDetail(kind bacnet.LogRecordKind, status bacnet.LogStatus, change time.Duration) string {
+	if kind == bacnet.LogRecordTimeChange {
+		return change.String()
+	}
+	var flags []string
+	if status.LogDisabled {
+		flags = append(flags, "log-disabled")
+	}
+	if status.BufferPurged {
+		flags = append(flags, "buffer-purged")
+	}
+	if status.LogInterrupted {
+		flags = append(flags, "log-interrupted")
+	}
+	return strings.Join(flags, "|")
+}
+
+func trendStatusFlags(s bacnet.StatusFlags) string {
+	var flags []string
+	if s.InAlarm {
+		flags = append(flags, "in-alarm")
+	}
+	if s.Fault {
+		flags = append(flags, "fault")
+	}
+	if s.Overridden {
+		flags = append(flags, "overridden")
+	}
+	if s.OutOfService {
+		flags = append(flags, "out-of-service")
+	}
+	return strings.Join(flags, "|")
+}
+
+// outputTrendLines prints the value records of a trend log as InfluxDB line
+// protocol, timestamped with their log time
+func outputTrendLines(objectID bacnet.ObjectIdentifier, records []bacnet.LogRecord) error {
+	lines := bacnet.NewLineProtocolEncoder(os.Stdout, lineProtocolOptions()...)
+	for _, rec := range records {
+		if rec.Kind != bacnet.LogRecordValue {
+			continue
+		}
+		pv := bacnet.PropertyValue{ObjectID: objectID, PropertyID: bacnet.PropertyLogBuffer, Value: rec.Value}
+		if err := lines.Encode(deviceID, pv, rec.Timestamp); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// outputTrendMultipleLines prints the values of a trend log multiple as
+// InfluxDB line protocol, indexed by their position in the record
+func outputTrendMultipleLines(objectID bacnet.ObjectIdentifier, records []bacnet.LogMultipleRecord) error {
+	lines := bacnet.NewLineProtocolEncoder(os.Stdout, lineProtocolOptions()...)
+	for _, rec := range records {
+		if rec.Kind != bacnet.LogRecordValue {
+			continue
+		}
+		for i, v := range rec.Values {
+			if _, ok := v.(error); ok {
+				continue
+			}
+			index := uint32(i + 1)
+			pv := bacnet.PropertyValue{ObjectID: objectID, PropertyID: bacnet.PropertyLogBuffer, ArrayIndex: &index, Value: v}
+			if err := lines.Encode(deviceID, pv, rec.Timestamp); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+func outputTrend(records []TrendRecord, multiple bool) error {
+	// Trend Log Multiple records have one value column per logged property
+	columns := 0
+	for _, r := range records {
+		if len(r.Values) > columns {
+			columns = len(r.Values)
+		}
+	}
+	valueHeaders := []string{"value"}
+	if multiple {
+		valueHeaders = make([]string, columns)
+		for i := range valueHeaders {
+			valueHeaders[i] = fmt.Sprintf("value_%d", i+1)
+		}
+	}
+	values := func(r TrendRecord) []string {
+		if !multiple {
+			return []string{r.Value}
+		}
+		v := make([]string, columns)
+		copy(v, r.Values)
+		return v
+	}
+
+	switch outputFmt {
+	case "json", "yaml":
+		if records == nil {
+			records = []TrendRecord{}
+		}
+		return encodeOutput(os.Stdout, records)
+	case "csv":
+		writer := csv.NewWriter(os.Stdout)
+		defer writer.Flush()
+		header := append([]string{"timestamp", "kind"}, valueHeaders...)
+		writer.Write(append(header, "status_flags", "detail"))
+		for _, r := range records {
+			row := append([]string{r.Timestamp, r.Kind}, values(r)...)
+			writer.Write(append(row, r.StatusFlags, r.Detail))
+		}
+		return writer.Error()
+	default:
+		f := NewFormatter(outputFmt)
+		headers := []string{"TIMESTAMP", "KIND"}
+		for _, h := range valueHeaders {
+			headers = append(headers, strings.ToUpper(h))
+		}
+		headers = append(headers, "STATUS FLAGS", "DETAIL")
+		rows := make([][]string, 0, len(records))
+		for _, r := range records {
+			row := append([]string{r.Timestamp, r.Kind}, values(r)...)
+			rows = append(rows, append(row, r.StatusFlags, r.Detail))
+		}
+		f.PrintTable(headers, rows)
+		return nil
+	}
+}
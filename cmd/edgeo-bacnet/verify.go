@@ -0,0 +1,418 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	verifySpecFile   string
+	verifyReportFile string
+	verifySkipWrites bool
+	verifySettle     time.Duration
+)
+
+var verifyCmd = &cobra.Command{
+	Use:   "verify",
+	Short: "Check devices against a commissioning specification",
+	Long: `Verify checks one or more devices against an expected specification and
+emits a commissioning report.
+
+For every point listed in the spec the following checks are run:
+  - the object exists on the device
+  - the object name matches (if given)
+  - the engineering units match (if given)
+  - writable points accept a command and read it back, then relinquish
+
+The spec file may be YAML, JSON or TOML:
+
+  devices:
+    - device: 1234
+      points:
+        - object: analog-input:1
+          name: ZN-T
+          units: 62
+        - object: analog-output:1
+          name: DMPR-CMD
+          units: 98
+          command: 50
+          priority: 8
+          tolerance: 0.5
+
+Examples:
+  # Run all checks and print a table
+  edgeo-bacnet verify --spec spec.yaml
+
+  # Read-only checks, JSON report to a file
+  edgeo-bacnet verify --spec spec.yaml --no-write -o json --report report.json`,
+
+	// A failing report is not a usage error
+	SilenceUsage: true,
+
+	RunE: runVerify,
+}
+
+func init() {
+	verifyCmd.Flags().StringVar(&verifySpecFile, "spec", "", "Specification file")
+	verifyCmd.Flags().StringVar(&verifyReportFile, "report", "", "Report file (default: stdout)")
+	verifyCmd.Flags().BoolVar(&verifySkipWrites, "no-write", false, "Skip command/relinquish checks")
+	verifyCmd.Flags().DurationVar(&verifySettle, "settle", time.Second, "Delay between a command and its read-back")
+
+	verifyCmd.MarkFlagRequired("spec")
+}
+
+// VerifySpec is the expected configuration of a set of devices
+type VerifySpec struct {
+	Devices []VerifyDevice `mapstructure:"devices"`
+}
+
+// VerifyDevice lists the expected points of a device
+type VerifyDevice struct {
+	Device uint32        `mapstructure:"device"`
+	Points []VerifyPoint `mapstructure:"points"`
+}
+
+// VerifyPoint is the expected configuration of a single object
+type VerifyPoint struct {
+	Object    string  `mapstructure:"object"`
+	Name      string  `mapstructure:"name"`
+	Units     *uint32 `mapstructure:"units"`
+	Command   string  `mapstructure:"command"`
+	Priority  int     `mapstructure:"priority"`
+	Tolerance float64 `mapstructure:"tolerance"`
+}
+
+// VerifyCheck is the outcome of a single commissioning check
+type VerifyCheck struct {
+	DeviceID uint32 `json:"device_id"`
+	Object   string `json:"object"`
+	Check    string `json:"check"`
+	Expected string `json:"expected,omitempty"`
+	Actual   string `json:"actual,omitempty"`
+	Passed   bool   `json:"passed"`
+	Error    string `json:"error,omitempty"`
+}
+
+// VerifyReport is the commissioning report
+type VerifyReport struct {
+	Timestamp time.Time     `json:"timestamp"`
+	Passed    int           `json:"passed"`
+	Failed    int           `json:"failed"`
+	Checks    []VerifyCheck `json:"checks"`
+}
+
+func (r *VerifyReport) add(check VerifyCheck) {
+	if check.Passed {
+		r.Passed++
+	} else {
+		r.Failed++
+	}
+	r.Checks = append(r.Checks, check)
+}
+
+func runVerify(cmd *cobra.Command, args []string) error {
+	spec, err := loadVerifySpec(verifySpecFile)
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	// An interrupted verification stops after relinquishing the point it
+	// commands, and reports the checks made
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+	connectCtx, cancel := context.WithTimeout(ctx, timeout*2)
+	err = client.Connect(connectCtx)
+	cancel()
+	if err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	report := &VerifyReport{Timestamp: time.Now()}
+
+	for _, dev := range spec.Devices {
+		fmt.Fprintf(os.Stderr, "Verifying device %d (%d points)\n", dev.Device, len(dev.Points))
+		for _, point := range dev.Points {
+			if ctx.Err() != nil {
+				break
+			}
+			verifyPoint(ctx, client, dev.Device, point, report)
+		}
+	}
+
+	var out *os.File
+	if verifyReportFile != "" {
+		out, err = os.Create(verifyReportFile)
+		if err != nil {
+			return fmt.Errorf("create report file: %w", err)
+		}
+		defer out.Close()
+	} else {
+		out = os.Stdout
+	}
+
+	switch outputFmt {
+	case "json", "yaml":
+		err = encodeOutput(out, report)
+	case "csv":
+		err = outputVerifyCSV(out, report)
+	default:
+		outputVerifyTable(out, report)
+	}
+	if err != nil {
+		return err
+	}
+
+	if report.Failed > 0 {
+		return fmt.Errorf("%d of %d checks failed", report.Failed, report.Passed+report.Failed)
+	}
+	return nil
+}
+
+func loadVerifySpec(path string) (*VerifySpec, error) {
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read spec: %w", err)
+	}
+
+	var spec VerifySpec
+	if err := v.Unmarshal(&spec); err != nil {
+		return nil, fmt.Errorf("parse spec: %w", err)
+	}
+	if len(spec.Devices) == 0 {
+		return nil, fmt.Errorf("spec %s lists no devices", path)
+	}
+	return &spec, nil
+}
+
+func verifyPoint(ctx context.Context, client *bacnet.Client, device uint32, point VerifyPoint, report *VerifyReport) {
+	check := func(name string) VerifyCheck {
+		return VerifyCheck{DeviceID: device, Object: point.Object, Check: name}
+	}
+
+	objectID, err := parseObjectIdentifier(point.Object)
+	if err != nil {
+		c := check("exists")
+		c.Error = err.Error()
+		report.add(c)
+		return
+	}
+
+	read := func(prop bacnet.PropertyIdentifier) (interface{}, error) {
+		readCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return client.ReadProperty(readCtx, device, objectID, prop)
+	}
+
+	// Existence is established by reading the object name, which every
+	// object is required to support
+	name, err := read(bacnet.PropertyObjectName)
+	c := check("exists")
+	if err != nil {
+		c.Error = err.Error()
+		report.add(c)
+		return
+	}
+	c.Passed = true
+	report.add(c)
+
+	if point.Name != "" {
+		c := check("name")
+		c.Expected = point.Name
+		c.Actual = formatValue(name)
+		c.Passed = c.Actual == point.Name
+		report.add(c)
+	}
+
+	if point.Units != nil {
+		c := check("units")
+		c.Expected = fmt.Sprintf("%d", *point.Units)
+		units, err := read(bacnet.PropertyUnits)
+		if err != nil {
+			c.Error = err.Error()
+		} else {
+			c.Actual = formatValue(units)
+			c.Passed = c.Actual == c.Expected
+		}
+		report.add(c)
+	}
+
+	if point.Command != "" && !verifySkipWrites {
+		verifyCommand(ctx, client, device, objectID, point, read, report)
+	}
+}
+
+func verifyCommand(ctx context.Context, client *bacnet.Client, device uint32, objectID bacnet.ObjectIdentifier,
+	point VerifyPoint, read func(bacnet.PropertyIdentifier) (interface{}, error), report *VerifyReport) {
+
+	check := func(name string) VerifyCheck {
+		return VerifyCheck{DeviceID: device, Object: point.Object, Check: name}
+	}
+
+	value, err := parseValue(point.Command)
+	if err != nil {
+		c := check("command")
+		c.Error = fmt.Sprintf("invalid command value: %v", err)
+		report.add(c)
+		return
+	}
+
+	var writeOpts []bacnet.WriteOption
+	if point.Priority > 0 && point.Priority <= 16 {
+		writeOpts = append(writeOpts, bacnet.WithPriority(uint8(point.Priority)))
+	}
+
+	write := func(ctx context.Context, v interface{}) error {
+		writeCtx, cancel := context.WithTimeout(ctx, timeout)
+		defer cancel()
+		return client.WriteProperty(writeCtx, device, objectID, bacnet.PropertyPresentValue, v, writeOpts...)
+	}
+
+	c := check("command")
+	c.Expected = formatValue(value)
+	if err := write(ctx, value); err != nil {
+		c.Error = err.Error()
+		report.add(c)
+		return
+	}
+	select {
+	case <-ctx.Done():
+		c.Error = ctx.Err().Error()
+	case <-time.After(verifySettle):
+		actual, err := read(bacnet.PropertyPresentValue)
+		if err != nil {
+			c.Error = err.Error()
+		} else {
+			c.Actual = formatValue(actual)
+			c.Passed = verifyValuesMatch(value, actual, point.Tolerance)
+		}
+	}
+	report.add(c)
+
+	// The command is relinquished even when the verification is interrupted
+	c = check("relinquish")
+	if err := write(context.WithoutCancel(ctx), nil); err != nil {
+		c.Error = err.Error()
+	} else {
+		c.Passed = true
+	}
+	report.add(c)
+}
+
+// verifyValuesMatch compares a commanded value with its read-back. Booleans
+// are compared against the enumerated binary PV returned by devices.
+func verifyValuesMatch(expected, actual interface{}, tolerance float64) bool {
+	e, ok := verifyNumeric(expected)
+	if !ok {
+		return formatValue(expected) == formatValue(actual)
+	}
+	a, ok := verifyNumeric(actual)
+	if !ok {
+		return false
+	}
+	return math.Abs(e-a) <= tolerance
+}
+
+func verifyNumeric(v interface{}) (float64, bool) {
+	switch n := v.(type) {
+	case bool:
+		if n {
+			return 1, true
+		}
+		return 0, true
+	case float32:
+		return float64(n), true
+	case float64:
+		return n, true
+	case uint32:
+		return float64(n), true
+	case int32:
+		return float64(n), true
+	default:
+		return 0, false
+	}
+}
+
+func outputVerifyCSV(out *os.File, report *VerifyReport) error {
+	writer := csv.NewWriter(out)
+	defer writer.Flush()
+
+	writer.Write([]string{"device_id", "object", "check", "expected", "actual", "result", "error"})
+	for _, c := range report.Checks {
+		writer.Write([]string{
+			fmt.Sprintf("%d", c.DeviceID),
+			c.Object,
+			c.Check,
+			c.Expected,
+			c.Actual,
+			verifyResult(c),
+			c.Error,
+		})
+	}
+
+	return writer.Error()
+}
+
+func outputVerifyTable(out *os.File, report *VerifyReport) {
+	f := NewFormatter(outputFmt)
+	f.SetWriter(out)
+
+	headers := []string{"DEVICE", "OBJECT", "CHECK", "EXPECTED", "ACTUAL", "RESULT"}
+	rows := make([][]string, 0, len(report.Checks))
+	for _, c := range report.Checks {
+		actual := c.Actual
+		if c.Error != "" {
+			actual = c.Error
+		}
+		rows = append(rows, []string{
+			fmt.Sprintf("%d", c.DeviceID),
+			c.Object,
+			c.Check,
+			c.Expected,
+			actual,
+			verifyResult(c),
+		})
+	}
+	f.PrintTable(headers, rows)
+
+	f.Printf("\nCommissioning report %s: %d passed, %d failed\n",
+		report.Timestamp.Format(time.RFC3339), report.Passed, report.Failed)
+}
+
+func verifyResult(c VerifyCheck) string {
+	if c.Passed {
+		return "PASS"
+	}
+	return "FAIL"
+}
@@ -16,9 +16,11 @@ package main
 
 import (
 	"context"
+	"encoding/csv"
 	"fmt"
 	"os"
 	"os/signal"
+	"strconv"
 	"syscall"
 	"time"
 
@@ -28,11 +30,18 @@ import (
 )
 
 var (
-	watchObjectType string
+	watchObjects    []string
+	watchPointsFile string
 	watchProperty   string
 	watchInterval   time.Duration
 	watchCOV        bool
 	watchCOVLifetime uint32
+
+	// watchLines encodes the values of -o influx
+	watchLines *bacnet.LineProtocolEncoder
+
+	// watchCSV writes the values of -o csv
+	watchCSV = csv.NewWriter(os.Stdout)
 )
 
 var watchCmd = &cobra.Command{
@@ -44,6 +53,12 @@ Two modes are available:
   - Polling: Periodically reads the property value
   - COV: Subscribes to Change of Value notifications (if supported)
 
+Several points are watched together when -O is repeated or a --points file
+is given, in the format of readm. They are polled with one
+ReadPropertyMultiple per interval, or their objects are subscribed to. On
+a terminal the points are shown as a table updated in place; otherwise
+every change is printed on its own line, with the point it belongs to.
+
 Examples:
   # Poll present value every second
   edgeo-bacnet watch -d 1234 -o analog-input:1 -p present-value --interval 1s
@@ -52,19 +67,27 @@ Examples:
   edgeo-bacnet watch -d 1234 -o analog-input:1 --cov
 
   # COV with custom lifetime
-  edgeo-bacnet watch -d 1234 -o analog-input:1 --cov --cov-lifetime 300`,
+  edgeo-bacnet watch -d 1234 -o analog-input:1 --cov --cov-lifetime 300
+
+  # Several points in a live table
+  edgeo-bacnet watch -d 1234 -O ai:1 -O ai:2 -O bv:3
+
+  # Points of a file, streamed as JSON lines
+  edgeo-bacnet watch -d 1234 --points points.csv --cov -o json
+
+  # Feed a historian with InfluxDB line protocol
+  edgeo-bacnet watch -d 1234 -O ai:1 -o influx | telegraf --config stdin.conf`,
 
 	RunE: runWatch,
 }
 
 func init() {
-	watchCmd.Flags().StringVarP(&watchObjectType, "object", "O", "", "Object type and instance (e.g., analog-input:1)")
-	watchCmd.Flags().StringVarP(&watchProperty, "property", "P", "present-value", "Property identifier")
+	watchCmd.Flags().StringArrayVarP(&watchObjects, "object", "O", nil, "Object to watch (repeatable, e.g. analog-input:1)")
+	watchCmd.Flags().StringVar(&watchPointsFile, "points", "", "Points file (CSV, YAML, JSON or TOML)")
+	watchCmd.Flags().StringVarP(&watchProperty, "property", "P", "present-value", "Property identifier of the -O objects")
 	watchCmd.Flags().DurationVar(&watchInterval, "interval", time.Second, "Polling interval")
 	watchCmd.Flags().BoolVar(&watchCOV, "cov", false, "Use COV subscription instead of polling")
 	watchCmd.Flags().Uint32Var(&watchCOVLifetime, "cov-lifetime", 0, "COV subscription lifetime in seconds (0 = indefinite)")
-
-	watchCmd.MarkFlagRequired("object")
 }
 
 func runWatch(cmd *cobra.Command, args []string) error {
@@ -72,8 +95,12 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		return fmt.Errorf("device ID is required (-d or --device)")
 	}
 
+	if len(watchObjects) != 1 || watchPointsFile != "" {
+		return runMultiWatch()
+	}
+
 	// Parse object identifier
-	objectID, err := parseObjectIdentifier(watchObjectType)
+	objectID, err := parseObjectIdentifier(watchObjects[0])
 	if err != nil {
 		return fmt.Errorf("invalid object: %w", err)
 	}
@@ -106,17 +133,34 @@ func runWatch(cmd *cobra.Command, args []string) error {
 		cancel()
 	}()
 
-	fmt.Printf("Watching %s.%s on device %d\n", objectID.String(), propID.String(), deviceID)
-	fmt.Println("Press Ctrl+C to stop")
-	fmt.Println()
+	// Line protocol goes to the historian untouched, and templated lines
+	// to the tool reading them
+	status := os.Stdout
+	switch OutputFormat(outputFmt) {
+	case FormatInflux:
+		status = os.Stderr
+		watchLines = newLineProtocolEncoder(ctx, client, deviceID, objectID)
+	case FormatTemplate:
+		status = os.Stderr
+	}
+
+	fmt.Fprintf(status, "Watching %s.%s on device %d\n", objectID.String(), propID.String(), deviceID)
+	fmt.Fprintln(status, "Press Ctrl+C to stop")
+	fmt.Fprintln(status)
+
+	// State texts are read up front: the COV handler must not block on
+	// further requests
+	textCtx, textCancel := context.WithTimeout(ctx, timeout)
+	texts := readStateTexts(textCtx, client, deviceID, objectID, propID)
+	textCancel()
 
 	if watchCOV {
-		return runCOVWatch(ctx, client, objectID, propID)
+		return runCOVWatch(ctx, client, objectID, propID, texts)
 	}
-	return runPollingWatch(ctx, client, objectID, propID)
+	return runPollingWatch(ctx, client, objectID, propID, texts)
 }
 
-func runPollingWatch(ctx context.Context, client *bacnet.Client, objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier) error {
+func runPollingWatch(ctx context.Context, client *bacnet.Client, objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier, texts *bacnet.StateTexts) error {
 	ticker := time.NewTicker(watchInterval)
 	defer ticker.Stop()
 
@@ -128,7 +172,7 @@ func runPollingWatch(ctx context.Context, client *bacnet.Client, objectID bacnet
 		return fmt.Errorf("initial read: %w", err)
 	}
 
-	outputWatchValue(time.Now(), objectID, propID, value, true)
+	outputWatchValue(time.Now(), objectID, propID, value, texts, true)
 	lastValue = value
 
 	for {
@@ -148,14 +192,14 @@ func runPollingWatch(ctx context.Context, client *bacnet.Client, objectID bacnet
 
 			changed := !valuesEqual(lastValue, value)
 			if changed || verbose {
-				outputWatchValue(time.Now(), objectID, propID, value, changed)
+				outputWatchValue(time.Now(), objectID, propID, value, texts, changed)
 				lastValue = value
 			}
 		}
 	}
 }
 
-func runCOVWatch(ctx context.Context, client *bacnet.Client, objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier) error {
+func runCOVWatch(ctx context.Context, client *bacnet.Client, objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier, texts *bacnet.StateTexts) error {
 	// Build subscription options
 	var subOpts []bacnet.SubscribeOption
 	if watchCOVLifetime > 0 {
@@ -166,7 +210,7 @@ func runCOVWatch(ctx context.Context, client *bacnet.Client, objectID bacnet.Obj
 	handler := func(devID uint32, oid bacnet.ObjectIdentifier, values []bacnet.PropertyValue) {
 		for _, pv := range values {
 			if pv.PropertyID == propID {
-				outputWatchValue(time.Now(), oid, pv.PropertyID, pv.Value, true)
+				outputWatchValue(time.Now(), oid, pv.PropertyID, pv.Value, texts, true)
 			}
 		}
 	}
@@ -176,7 +220,11 @@ func runCOVWatch(ctx context.Context, client *bacnet.Client, objectID bacnet.Obj
 		return fmt.Errorf("subscribe COV: %w", err)
 	}
 
-	fmt.Printf("Subscribed to COV (subscription ID: %d)\n", subID)
+	if watchLines != nil || OutputFormat(outputFmt) == FormatTemplate {
+		fmt.Fprintf(os.Stderr, "Subscribed to COV (subscription ID: %d)\n", subID)
+	} else {
+		fmt.Printf("Subscribed to COV (subscription ID: %d)\n", subID)
+	}
 
 	// Wait for context cancellation
 	<-ctx.Done()
@@ -192,58 +240,62 @@ func runCOVWatch(ctx context.Context, client *bacnet.Client, objectID bacnet.Obj
 	return nil
 }
 
-func outputWatchValue(t time.Time, objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier, value interface{}, changed bool) {
+// WatchRecord is a value printed by watch with -o json. Point is only set
+// when several points are watched, Error when a point cannot be read.
+type WatchRecord struct {
+	Time       time.Time   `json:"time"`
+	Point      string      `json:"point,omitempty"`
+	Object     string      `json:"object"`
+	Property   string      `json:"property"`
+	ArrayIndex *uint32     `json:"index,omitempty"`
+	Value      interface{} `json:"value"`
+	Error      string      `json:"error,omitempty"`
+	Changed    bool        `json:"changed"`
+}
+
+func outputWatchValue(t time.Time, objectID bacnet.ObjectIdentifier, propID bacnet.PropertyIdentifier, value interface{}, texts *bacnet.StateTexts, changed bool) {
 	changeMarker := " "
 	if changed {
 		changeMarker = "*"
 	}
 
 	switch outputFmt {
-	case "json":
-		fmt.Printf(`{"time": "%s", "object": "%s", "property": "%s", "value": %s, "changed": %v}`+"\n",
-			t.Format(time.RFC3339Nano),
-			objectID.String(),
-			propID.String(),
-			formatValueJSON(value),
-			changed,
-		)
+	case "json", "yaml", "template":
+		err := encodeOutputRecord(os.Stdout, WatchRecord{
+			Time:     t,
+			Object:   objectID.String(),
+			Property: propID.String(),
+			Value:    formatValueForDump(value),
+			Changed:  changed,
+		})
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	case "influx":
+		pv := bacnet.PropertyValue{ObjectID: objectID, PropertyID: propID, Value: value}
+		if err := watchLines.Encode(deviceID, pv, t); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
 	case "csv":
-		fmt.Printf("%s,%s,%s,%s,%v\n",
+		watchCSV.Write([]string{
 			t.Format(time.RFC3339Nano),
 			objectID.String(),
 			propID.String(),
 			formatValue(value),
-			changed,
-		)
+			strconv.FormatBool(changed),
+		})
+		watchCSV.Flush()
 	default:
 		fmt.Printf("[%s] %s %s.%s = %s\n",
 			t.Format("15:04:05.000"),
 			changeMarker,
 			objectID.String(),
 			propID.String(),
-			formatValue(value),
+			formatDisplayValue(value, texts),
 		)
 	}
 }
 
-func formatValueJSON(value interface{}) string {
-	switch v := value.(type) {
-	case nil:
-		return "null"
-	case bool:
-		if v {
-			return "true"
-		}
-		return "false"
-	case string:
-		return fmt.Sprintf("%q", v)
-	case bacnet.ObjectIdentifier:
-		return fmt.Sprintf("%q", v.String())
-	default:
-		return formatValue(value)
-	}
-}
-
 func valuesEqual(a, b interface{}) bool {
 	return fmt.Sprintf("%v", a) == fmt.Sprintf("%v", b)
 }
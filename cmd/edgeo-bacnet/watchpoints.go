@@ -0,0 +1,408 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"os"
+	"os/signal"
+	"strconv"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+// errNotReturned is the error of a point the device left out of its answer
+var errNotReturned = errors.New("not returned")
+
+// watchKey identifies a point of a multi-point watch
+type watchKey struct {
+	objectID   bacnet.ObjectIdentifier
+	propertyID bacnet.PropertyIdentifier
+	index      uint32
+}
+
+func newWatchKey(objectID bacnet.ObjectIdentifier, propertyID bacnet.PropertyIdentifier, index *uint32) watchKey {
+	k := watchKey{objectID: objectID, propertyID: propertyID, index: ^uint32(0)}
+	if index != nil {
+		k.index = *index
+	}
+	return k
+}
+
+// watchPoint is a watched point and its last value
+type watchPoint struct {
+	name    string
+	request bacnet.ReadPropertyRequest
+	texts   *bacnet.StateTexts
+	value   interface{}
+	err     error
+	updated time.Time
+	read    bool
+}
+
+// watchTable tracks the points of a multi-point watch and prints their
+// changes: as a table redrawn in place on a terminal, otherwise one line
+// per change. COV notifications arrive on the receive goroutine of the
+// client, so all updates are serialized.
+type watchTable struct {
+	mu     sync.Mutex
+	points []*watchPoint
+	byKey  map[watchKey]*watchPoint
+	live   bool
+	width  int
+	csv    *csv.Writer
+	header bool
+}
+
+func newWatchTable(requests []bacnet.ReadPropertyRequest) *watchTable {
+	t := &watchTable{byKey: make(map[watchKey]*watchPoint), csv: csv.NewWriter(os.Stdout)}
+	for _, req := range requests {
+		key := newWatchKey(req.ObjectID, req.PropertyID, req.ArrayIndex)
+		if _, ok := t.byKey[key]; ok {
+			continue
+		}
+		name := req.ObjectID.String() + "." + req.PropertyID.String()
+		if req.ArrayIndex != nil {
+			name += "[" + strconv.FormatUint(uint64(*req.ArrayIndex), 10) + "]"
+		}
+		p := &watchPoint{name: name, request: req}
+		t.points = append(t.points, p)
+		t.byKey[key] = p
+		if len(name) > t.width {
+			t.width = len(name)
+		}
+	}
+
+	switch OutputFormat(outputFmt) {
+	case FormatJSON, FormatYAML, FormatCSV, FormatInflux, FormatTemplate:
+	default:
+		if info, err := os.Stdout.Stat(); err == nil {
+			t.live = info.Mode()&os.ModeCharDevice != 0
+		}
+	}
+	return t
+}
+
+// requests returns the read requests of the points
+func (t *watchTable) requests() []bacnet.ReadPropertyRequest {
+	requests := make([]bacnet.ReadPropertyRequest, len(t.points))
+	for i, p := range t.points {
+		requests[i] = p.request
+	}
+	return requests
+}
+
+// objects returns the distinct objects of the points
+func (t *watchTable) objects() []bacnet.ObjectIdentifier {
+	var objectIDs []bacnet.ObjectIdentifier
+	seen := make(map[bacnet.ObjectIdentifier]bool)
+	for _, p := range t.points {
+		if !seen[p.request.ObjectID] {
+			seen[p.request.ObjectID] = true
+			objectIDs = append(objectIDs, p.request.ObjectID)
+		}
+	}
+	return objectIDs
+}
+
+// results records the results of a read of all points
+func (t *watchTable) results(now time.Time, results []bacnet.PropertyResult) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	byKey := make(map[watchKey]bacnet.PropertyResult, len(results))
+	for _, r := range results {
+		byKey[newWatchKey(r.ObjectID, r.PropertyID, r.ArrayIndex)] = r
+	}
+	for _, p := range t.points {
+		r, ok := byKey[newWatchKey(p.request.ObjectID, p.request.PropertyID, p.request.ArrayIndex)]
+		if !ok {
+			r.Err = errNotReturned
+		}
+		t.set(now, p, r.Value, r.Err)
+	}
+	t.draw(now)
+}
+
+// fail records a read of all points that failed as a whole
+func (t *watchTable) fail(now time.Time, err error) {
+	if !t.live {
+		fmt.Fprintf(os.Stderr, "[%s] Error: %v\n", now.Format("15:04:05.000"), err)
+		return
+	}
+
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	for _, p := range t.points {
+		t.set(now, p, p.value, err)
+	}
+	t.draw(now)
+}
+
+// notify records the values of a COV notification
+func (t *watchTable) notify(devID uint32, objectID bacnet.ObjectIdentifier, values []bacnet.PropertyValue) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	now := time.Now()
+	for _, pv := range values {
+		if p, ok := t.byKey[newWatchKey(objectID, pv.PropertyID, pv.ArrayIndex)]; ok {
+			t.set(now, p, pv.Value, nil)
+		}
+	}
+	t.draw(now)
+}
+
+// set records the value or error of a point, printing it when it changed
+func (t *watchTable) set(now time.Time, p *watchPoint, value interface{}, err error) {
+	changed := !p.read || !valuesEqual(p.value, value) || errorText(p.err) != errorText(err)
+	p.read = true
+	p.value = value
+	p.err = err
+	if changed {
+		p.updated = now
+	}
+	if !t.live && (changed || verbose) {
+		t.print(now, p, changed)
+	}
+}
+
+// print prints one change of a point
+func (t *watchTable) print(now time.Time, p *watchPoint, changed bool) {
+	switch OutputFormat(outputFmt) {
+	case FormatJSON, FormatYAML, FormatTemplate:
+		record := WatchRecord{
+			Time:       now,
+			Point:      p.name,
+			Object:     p.request.ObjectID.String(),
+			Property:   p.request.PropertyID.String(),
+			ArrayIndex: p.request.ArrayIndex,
+			Value:      formatValueForDump(p.value),
+			Error:      errorText(p.err),
+			Changed:    changed,
+		}
+		if err := encodeOutputRecord(os.Stdout, record); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	case FormatInflux:
+		if p.err != nil {
+			return
+		}
+		pv := bacnet.PropertyValue{ObjectID: p.request.ObjectID, PropertyID: p.request.PropertyID,
+			ArrayIndex: p.request.ArrayIndex, Value: p.value}
+		if err := watchLines.Encode(deviceID, pv, now); err != nil {
+			fmt.Fprintf(os.Stderr, "Error: %v\n", err)
+		}
+	case FormatCSV:
+		if !t.header {
+			t.csv.Write([]string{"time", "point", "value", "error", "changed"})
+			t.header = true
+		}
+		value := ""
+		if p.err == nil {
+			value = formatValue(p.value)
+		}
+		t.csv.Write([]string{now.Format(time.RFC3339Nano), p.name, value, errorText(p.err), strconv.FormatBool(changed)})
+		t.csv.Flush()
+	default:
+		changeMarker := " "
+		if changed {
+			changeMarker = "*"
+		}
+		fmt.Printf("[%s] %s %-*s = %s\n", now.Format("15:04:05.000"), changeMarker, t.width, p.name, p.display())
+	}
+}
+
+// draw redraws the table of a terminal
+func (t *watchTable) draw(now time.Time) {
+	if !t.live {
+		return
+	}
+
+	// Clear the screen and home the cursor
+	fmt.Print("\033[H\033[2J")
+	fmt.Printf("Device %d, %d points, %s (Ctrl+C to stop)\n\n", deviceID, len(t.points), now.Format("15:04:05"))
+
+	rows := make([][]string, 0, len(t.points))
+	for _, p := range t.points {
+		updated := ""
+		if p.read {
+			updated = p.updated.Format("15:04:05")
+		}
+		rows = append(rows, []string{p.name, p.display(), updated})
+	}
+	NewFormatter(outputFmt).PrintTable([]string{"POINT", "VALUE", "CHANGED"}, rows)
+}
+
+// display formats the value or error of a point
+func (p *watchPoint) display() string {
+	switch {
+	case p.err != nil:
+		return "error: " + p.err.Error()
+	case !p.read:
+		return ""
+	default:
+		return formatDisplayValue(p.value, p.texts)
+	}
+}
+
+func errorText(err error) string {
+	if err == nil {
+		return ""
+	}
+	return err.Error()
+}
+
+// runMultiWatch watches the points of the -O flags and the points file
+func runMultiWatch() error {
+	requests, err := pointRequests(watchObjects, []string{watchProperty}, watchPointsFile)
+	if err != nil {
+		return err
+	}
+	if len(requests) == 0 {
+		return fmt.Errorf("no points to watch (-O or --points)")
+	}
+	table := newWatchTable(requests)
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	// State texts and units are read up front: the COV handler must not
+	// block on further requests
+	for _, p := range table.points {
+		textCtx, textCancel := context.WithTimeout(ctx, timeout)
+		p.texts = readStateTexts(textCtx, client, deviceID, p.request.ObjectID, p.request.PropertyID)
+		textCancel()
+	}
+	if OutputFormat(outputFmt) == FormatInflux {
+		watchLines = newLineProtocolEncoder(ctx, client, deviceID, table.objects()...)
+	}
+
+	if !table.live {
+		fmt.Fprintf(os.Stderr, "Watching %d points on device %d, press Ctrl+C to stop\n", len(table.points), deviceID)
+	}
+
+	// The first read gives every point a value, including the points that
+	// COV notifications do not report
+	dev := client.Device(deviceID)
+	readTimeout := operationDeadline(len(requests) + 1)
+	readCtx, readCancel := context.WithTimeout(ctx, readTimeout)
+	results, err := dev.ReadMultiple(readCtx, table.requests())
+	readCancel()
+	if err != nil {
+		return fmt.Errorf("initial read: %w", err)
+	}
+	table.results(time.Now(), results)
+
+	if watchCOV {
+		return runMultiCOVWatch(ctx, client, table)
+	}
+
+	ticker := time.NewTicker(watchInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+
+		case <-ticker.C:
+			readCtx, readCancel := context.WithTimeout(ctx, readTimeout)
+			results, err := dev.ReadMultiple(readCtx, table.requests())
+			readCancel()
+
+			if err != nil {
+				if ctx.Err() == nil {
+					table.fail(time.Now(), err)
+				}
+				continue
+			}
+			table.results(time.Now(), results)
+		}
+	}
+}
+
+// runMultiCOVWatch subscribes to the objects of the points, renewing the
+// subscriptions halfway through their lifetime
+func runMultiCOVWatch(ctx context.Context, client *bacnet.Client, table *watchTable) error {
+	var subOpts []bacnet.SubscribeOption
+	if watchCOVLifetime > 0 {
+		subOpts = append(subOpts, bacnet.WithSubscriptionLifetime(watchCOVLifetime))
+	}
+
+	var subs []covSubscription
+	defer func() {
+		// Subscriptions are cancelled even after Ctrl+C
+		unsubCtx, cancel := context.WithTimeout(context.Background(), timeout)
+		defer cancel()
+		for _, sub := range subs {
+			if err := client.UnsubscribeCOV(unsubCtx, deviceID, sub.objectID, sub.subID); err != nil {
+				fmt.Fprintf(os.Stderr, "Warning: failed to unsubscribe from %s: %v\n", sub.objectID, err)
+			}
+		}
+	}()
+
+	for _, objectID := range table.objects() {
+		subCtx, subCancel := context.WithTimeout(ctx, operationDeadline(1))
+		subID, err := client.SubscribeCOV(subCtx, deviceID, objectID, table.notify, subOpts...)
+		subCancel()
+		if err != nil {
+			return fmt.Errorf("subscribe to %s: %w", objectID, err)
+		}
+		subs = append(subs, covSubscription{objectID: objectID, subID: subID})
+		if verbose && !table.live {
+			fmt.Fprintf(os.Stderr, "Subscribed to %s (subscription ID %d)\n", objectID, subID)
+		}
+	}
+
+	// Without a lifetime there is nothing to renew
+	if watchCOVLifetime == 0 {
+		<-ctx.Done()
+		return nil
+	}
+
+	ticker := time.NewTicker(time.Duration(watchCOVLifetime) * time.Second / 2)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-ticker.C:
+			for _, sub := range subs {
+				renewCtx, renewCancel := context.WithTimeout(ctx, operationDeadline(1))
+				err := client.RenewCOV(renewCtx, deviceID, sub.objectID, sub.subID, subOpts...)
+				renewCancel()
+				if err != nil && ctx.Err() == nil && !table.live {
+					fmt.Fprintf(os.Stderr, "[%s] Failed to renew %s: %v\n", time.Now().Format("15:04:05.000"), sub.objectID, err)
+				}
+			}
+		}
+	}
+}
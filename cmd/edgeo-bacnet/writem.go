@@ -0,0 +1,388 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package main
+
+import (
+	"context"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"github.com/spf13/viper"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+var (
+	writemFile   string
+	writemDryRun bool
+)
+
+var writemCmd = &cobra.Command{
+	Use:   "writem",
+	Short: "Write a batch of object properties from a file",
+	Long: `Writem writes the rows of a CSV, YAML, JSON or TOML file to a device with
+WritePropertyMultiple, and reports the outcome of every row. When the device
+rejects the batch, the rows are written one at a time so that each failure
+is reported against its row.
+
+All rows are validated before anything is written. Numbers and booleans are
+converted to the datatype of analog, binary and multi-state present values.
+With --dry-run, the rows and the encoded request are printed and nothing is
+written.
+
+A CSV file lists object,property,value[,priority[,index]] per line; a YAML,
+JSON or TOML file lists the rows under writes:
+
+  writes:
+    - object: analog-value:1
+      property: present-value
+      value: 21.5
+      priority: 8
+    - object: analog-value:1
+      property: description
+      value: Zone setpoint
+
+Examples:
+  # Preview the writes
+  edgeo-bacnet writem -d 1234 --file setpoints.csv --dry-run
+
+  # Write them
+  edgeo-bacnet writem -d 1234 --file setpoints.csv`,
+
+	// Failed rows are not a usage error
+	SilenceUsage: true,
+
+	RunE: runWritem,
+}
+
+func init() {
+	writemCmd.Flags().StringVarP(&writemFile, "file", "f", "", "Rows file (CSV, YAML, JSON or TOML)")
+	writemCmd.Flags().BoolVar(&writemDryRun, "dry-run", false, "Validate and preview the writes without sending them")
+
+	writemCmd.MarkFlagRequired("file")
+}
+
+// WriteRow is a write listed in a rows file
+type WriteRow struct {
+	Object   string  `mapstructure:"object"`
+	Property string  `mapstructure:"property"`
+	Value    string  `mapstructure:"value"`
+	Priority int     `mapstructure:"priority"`
+	Index    *uint32 `mapstructure:"index"`
+}
+
+// WritemResult is the outcome of one row
+type WritemResult struct {
+	Row      int     `json:"row"`
+	Object   string  `json:"object"`
+	Property string  `json:"property"`
+	Index    *uint32 `json:"index,omitempty"`
+	Value    string  `json:"value"`
+	Priority int     `json:"priority,omitempty"`
+	Result   string  `json:"result"`
+	Error    string  `json:"error,omitempty"`
+}
+
+func runWritem(cmd *cobra.Command, args []string) error {
+	if deviceID == 0 {
+		return fmt.Errorf("device ID is required (-d or --device)")
+	}
+
+	rows, err := loadWriteRows(writemFile)
+	if err != nil {
+		return err
+	}
+	if len(rows) == 0 {
+		return fmt.Errorf("%s lists no writes", writemFile)
+	}
+
+	requests, results, err := writemRequests(rows)
+	if err != nil {
+		return err
+	}
+
+	client, err := createClient()
+	if err != nil {
+		return fmt.Errorf("create client: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), operationDeadline(len(requests)+1))
+	defer cancel()
+
+	if err := client.Connect(ctx); err != nil {
+		return fmt.Errorf("connect: %w", err)
+	}
+	defer client.Close()
+
+	if writemDryRun {
+		var plan bacnet.WritePlan
+		if err := client.WritePropertyMultiple(ctx, deviceID, requests, bacnet.WithDryRun(&plan)); err != nil {
+			return fmt.Errorf("dry run: %w", err)
+		}
+		for i := range results {
+			results[i].Result = "planned"
+		}
+		if err := outputWritem(results); err != nil {
+			return err
+		}
+		fmt.Fprintf(os.Stderr, "\nDry run: %s of %d writes to device %d at %v, %d bytes: % x\n",
+			plan.Service, len(requests), plan.DeviceID, plan.Address, len(plan.APDU), plan.APDU)
+		return nil
+	}
+
+	writemExecute(ctx, client, requests, results)
+
+	if err := outputWritem(results); err != nil {
+		return err
+	}
+
+	failed := 0
+	for _, r := range results {
+		if r.Error != "" {
+			failed++
+		}
+	}
+	if failed > 0 {
+		return fmt.Errorf("%d of %d writes failed", failed, len(results))
+	}
+	return nil
+}
+
+// writemRequests validates the rows and returns their requests and results.
+// Every invalid row is reported in the error.
+func writemRequests(rows []WriteRow) ([]bacnet.WritePropertyRequest, []WritemResult, error) {
+	requests := make([]bacnet.WritePropertyRequest, 0, len(rows))
+	results := make([]WritemResult, 0, len(rows))
+	var errs []error
+
+	for i, row := range rows {
+		n := i + 1
+		objectID, err := parseObjectIdentifier(row.Object)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("row %d: invalid object %q: %w", n, row.Object, err))
+			continue
+		}
+		property := row.Property
+		if property == "" {
+			property = "present-value"
+		}
+		propID, err := parsePropertyIdentifier(property)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("row %d: %w", n, err))
+			continue
+		}
+		value, err := parseValue(row.Value)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("row %d: invalid value %q: %w", n, row.Value, err))
+			continue
+		}
+		if row.Priority < 0 || row.Priority > 16 {
+			errs = append(errs, fmt.Errorf("row %d: priority %d out of range 1-16", n, row.Priority))
+			continue
+		}
+
+		req := bacnet.WritePropertyRequest{
+			ObjectID:   objectID,
+			PropertyID: propID,
+			ArrayIndex: row.Index,
+			Value:      bacnet.CoerceValue(objectID, propID, value),
+		}
+		if row.Priority > 0 {
+			priority := uint8(row.Priority)
+			req.Priority = &priority
+		}
+		requests = append(requests, req)
+		results = append(results, WritemResult{
+			Row:      n,
+			Object:   objectID.String(),
+			Property: propID.String(),
+			Index:    row.Index,
+			Value:    formatValue(req.Value),
+			Priority: row.Priority,
+		})
+	}
+
+	if len(errs) > 0 {
+		return nil, nil, errors.Join(errs...)
+	}
+	return requests, results, nil
+}
+
+// writemExecute writes the requests with WritePropertyMultiple and sets the
+// results. When the device rejects the batch, the rows are written one at a
+// time.
+func writemExecute(ctx context.Context, client *bacnet.Client, requests []bacnet.WritePropertyRequest, results []WritemResult) {
+	err := client.WritePropertyMultiple(ctx, deviceID, requests)
+	if err == nil {
+		for i := range results {
+			results[i].Result = "ok"
+		}
+		return
+	}
+
+	// Without a reply of the device, the outcome of the batch is unknown
+	if !isDeviceReply(err) {
+		for i := range results {
+			results[i].Result = "failed"
+			results[i].Error = err.Error()
+		}
+		return
+	}
+
+	fmt.Fprintf(os.Stderr, "WritePropertyMultiple failed (%v), writing rows one at a time\n", err)
+	for i, req := range requests {
+		var opts []bacnet.WriteOption
+		if req.Priority != nil {
+			opts = append(opts, bacnet.WithPriority(*req.Priority))
+		}
+		if req.ArrayIndex != nil {
+			opts = append(opts, bacnet.WithWriteArrayIndex(*req.ArrayIndex))
+		}
+
+		writeCtx, cancel := context.WithTimeout(ctx, operationDeadline(1))
+		err := client.WriteProperty(writeCtx, deviceID, req.ObjectID, req.PropertyID, req.Value, opts...)
+		cancel()
+		if err != nil {
+			results[i].Result = "failed"
+			results[i].Error = err.Error()
+		} else {
+			results[i].Result = "ok"
+		}
+	}
+}
+
+// isDeviceReply returns true for errors carried by a reply of the device
+func isDeviceReply(err error) bool {
+	var bacnetErr *bacnet.BACnetError
+	var rejectErr *bacnet.RejectError
+	var abortErr *bacnet.AbortError
+	return errors.As(err, &bacnetErr) || errors.As(err, &rejectErr) || errors.As(err, &abortErr)
+}
+
+// loadWriteRows reads a CSV, YAML, JSON or TOML rows file
+func loadWriteRows(path string) ([]WriteRow, error) {
+	if strings.EqualFold(filepath.Ext(path), ".csv") {
+		return loadWriteRowsCSV(path)
+	}
+
+	v := viper.New()
+	v.SetConfigFile(path)
+	if err := v.ReadInConfig(); err != nil {
+		return nil, fmt.Errorf("read rows: %w", err)
+	}
+
+	var file struct {
+		Writes []WriteRow `mapstructure:"writes"`
+	}
+	if err := v.Unmarshal(&file); err != nil {
+		return nil, fmt.Errorf("parse rows: %w", err)
+	}
+	return file.Writes, nil
+}
+
+// loadWriteRowsCSV reads object,property,value[,priority[,index]] lines,
+// skipping a header line and # comments
+func loadWriteRowsCSV(path string) ([]WriteRow, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("read rows: %w", err)
+	}
+	defer f.Close()
+
+	reader := csv.NewReader(f)
+	reader.Comment = '#'
+	reader.FieldsPerRecord = -1
+	reader.TrimLeadingSpace = true
+
+	var rows []WriteRow
+	line := 0
+	for {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("parse rows: %w", err)
+		}
+		line++
+		if line == 1 && strings.EqualFold(record[0], "object") {
+			continue
+		}
+		if len(record) < 3 {
+			return nil, fmt.Errorf("parse rows: expected object,property,value in %q", strings.Join(record, ","))
+		}
+
+		row := WriteRow{Object: record[0], Property: record[1], Value: record[2]}
+		if len(record) > 3 && record[3] != "" {
+			if row.Priority, err = strconv.Atoi(record[3]); err != nil {
+				return nil, fmt.Errorf("parse rows: invalid priority %q", record[3])
+			}
+		}
+		if len(record) > 4 && record[4] != "" {
+			index, err := strconv.ParseUint(record[4], 10, 32)
+			if err != nil {
+				return nil, fmt.Errorf("parse rows: invalid index %q", record[4])
+			}
+			i := uint32(index)
+			row.Index = &i
+		}
+		rows = append(rows, row)
+	}
+	return rows, nil
+}
+
+func outputWritem(results []WritemResult) error {
+	switch outputFmt {
+	case "json", "yaml":
+		return encodeOutput(os.Stdout, results)
+	case "csv":
+		writer := csv.NewWriter(os.Stdout)
+		defer writer.Flush()
+		writer.Write([]string{"row", "object", "property", "index", "value", "priority", "result", "error"})
+		for _, r := range results {
+			writer.Write([]string{strconv.Itoa(r.Row), r.Object, r.Property, readmIndex(r.Index), r.Value,
+				writemPriority(r.Priority), r.Result, r.Error})
+		}
+		return writer.Error()
+	default:
+		f := NewFormatter(outputFmt)
+		headers := []string{"ROW", "OBJECT", "PROPERTY", "INDEX", "VALUE", "PRIORITY", "RESULT"}
+		rows := make([][]string, 0, len(results))
+		for _, r := range results {
+			result := r.Result
+			if r.Error != "" {
+				result += ": " + r.Error
+			}
+			rows = append(rows, []string{strconv.Itoa(r.Row), r.Object, r.Property, readmIndex(r.Index), r.Value,
+				writemPriority(r.Priority), result})
+		}
+		f.PrintTable(headers, rows)
+		return nil
+	}
+}
+
+func writemPriority(priority int) string {
+	if priority == 0 {
+		return ""
+	}
+	return strconv.Itoa(priority)
+}
@@ -0,0 +1,312 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"net/url"
+	"time"
+)
+
+// servedCOVKey identifies a COV subscription to a local object. Subscribers
+// are distinguished by address and process identifier.
+type servedCOVKey struct {
+	addr      string
+	processID uint32
+	objectID  ObjectIdentifier
+}
+
+// storageKey returns the key of a persisted subscription
+func (k servedCOVKey) storageKey() string {
+	return fmt.Sprintf("%s%s/%d/%s", storageCOVPrefix, url.PathEscape(k.addr), k.processID, k.objectID)
+}
+
+// servedCOVSubscription is a COV subscription from another device to a
+// local object
+type servedCOVSubscription struct {
+	processID uint32
+	objectID  ObjectIdentifier
+	addr      net.Addr
+	route     *NPDU
+	confirmed bool
+	expires   time.Time // zero for an indefinite subscription
+}
+
+// expired reports whether the subscription lifetime has elapsed
+func (s *servedCOVSubscription) expired(now time.Time) bool {
+	return !s.expires.IsZero() && now.After(s.expires)
+}
+
+// timeRemaining returns the remaining lifetime in seconds, 0 if indefinite
+func (s *servedCOVSubscription) timeRemaining(now time.Time) uint32 {
+	if s.expires.IsZero() {
+		return 0
+	}
+	remaining := s.expires.Sub(now)
+	if remaining < 0 {
+		return 0
+	}
+	return uint32(remaining.Round(time.Second) / time.Second)
+}
+
+// serveSubscribeCOV handles a SubscribeCOV request. A request without the
+// confirmed and lifetime parameters cancels the subscription.
+func (c *Client) serveSubscribeCOV(data []byte, addr net.Addr, npdu *NPDU) error {
+	d := NewDecoder(data)
+	processID := d.ContextUnsigned(0)
+	objectID := d.ContextObjectIdentifier(1)
+
+	cancel := d.Len() == 0
+	var (
+		confirmed bool
+		lifetime  uint32
+	)
+	if !cancel {
+		confirmed = d.ContextBoolean(2)
+		if d.Len() > 0 {
+			lifetime = d.ContextUnsigned(3)
+		}
+	}
+	if err := d.Err(); err != nil {
+		return err
+	}
+
+	if _, ok := c.server.Database().(COVReporter); !ok {
+		return NewBACnetError(ErrorClassServices, ErrorCodeCovSubscriptionFailed)
+	}
+	if _, err := c.server.readProperty(objectID, PropertyPresentValue); err != nil {
+		return err
+	}
+
+	key := servedCOVKey{addr: addr.String(), processID: processID, objectID: objectID}
+
+	c.servedCOVMu.Lock()
+	if cancel {
+		if _, ok := c.servedCOVSubs[key]; ok {
+			delete(c.servedCOVSubs, key)
+			c.metrics.ActiveSubscriptions.Dec()
+		}
+		c.servedCOVMu.Unlock()
+		c.storeDelete(key.storageKey())
+		c.logger.Debug("COV subscription cancelled",
+			slog.String("subscriber", addr.String()),
+			slog.String("object", objectID.String()),
+		)
+		return nil
+	}
+
+	sub := &servedCOVSubscription{
+		processID: processID,
+		objectID:  objectID,
+		addr:      addr,
+		route:     npdu,
+		confirmed: confirmed,
+	}
+	if lifetime > 0 {
+		sub.expires = c.opts.clock.Now().Add(time.Duration(lifetime) * time.Second)
+	}
+	if _, exists := c.servedCOVSubs[key]; !exists {
+		c.metrics.ActiveSubscriptions.Inc()
+	}
+	c.servedCOVSubs[key] = sub
+	c.servedCOVMu.Unlock()
+	c.storeCOVSubscription(key, sub)
+
+	c.logger.Debug("COV subscription accepted",
+		slog.String("subscriber", addr.String()),
+		slog.String("object", objectID.String()),
+		slog.Bool("confirmed", confirmed),
+		slog.Uint64("lifetime", uint64(lifetime)),
+	)
+
+	// A new subscription receives the current value straight away
+	go c.sendCOVNotification(sub)
+	return nil
+}
+
+// handleLocalCOV notifies the subscribers of a local object that changed
+func (c *Client) handleLocalCOV(object *LocalObject) {
+	now := c.opts.clock.Now()
+	objectID := object.ObjectID()
+
+	var (
+		subs    []*servedCOVSubscription
+		expired []servedCOVKey
+	)
+	c.servedCOVMu.Lock()
+	for key, sub := range c.servedCOVSubs {
+		if sub.expired(now) {
+			delete(c.servedCOVSubs, key)
+			c.metrics.ActiveSubscriptions.Dec()
+			expired = append(expired, key)
+			continue
+		}
+		if sub.objectID == objectID {
+			subs = append(subs, sub)
+		}
+	}
+	c.servedCOVMu.Unlock()
+
+	for _, key := range expired {
+		c.storeDelete(key.storageKey())
+	}
+
+	for _, sub := range subs {
+		go c.sendCOVNotification(sub)
+	}
+}
+
+// sendCOVNotification sends the present value and status flags of the
+// subscribed object to a subscriber
+func (c *Client) sendCOVNotification(sub *servedCOVSubscription) {
+	if c.State() != StateConnected {
+		return
+	}
+
+	data, err := c.encodeCOVNotification(sub, c.opts.clock.Now())
+	if err != nil {
+		c.logger.Debug("failed to encode COV notification",
+			slog.String("object", sub.objectID.String()),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.timeout)
+	defer cancel()
+
+	if sub.confirmed {
+		_, err = c.sendRoutedRequest(ctx, &Request{
+			DeviceID: wildcardDeviceInstance,
+			Address:  sub.addr,
+			Service:  ServiceConfirmedCOVNotification,
+			Data:     data,
+		}, sub.route)
+	} else {
+		err = c.sendResponse(ctx, sub.addr, sub.route, false, EncodeUnconfirmedRequest(ServiceUnconfirmedCOVNotification, data))
+	}
+	if err != nil {
+		c.logger.Debug("failed to send COV notification",
+			slog.String("subscriber", sub.addr.String()),
+			slog.String("object", sub.objectID.String()),
+			slog.String("error", err.Error()),
+		)
+		return
+	}
+
+	c.metrics.COVNotificationsSent.Inc()
+}
+
+// encodeCOVNotification encodes the COV notification service parameters
+func (c *Client) encodeCOVNotification(sub *servedCOVSubscription, now time.Time) ([]byte, error) {
+	e := NewEncoder(make([]byte, 0, 48))
+	e.ContextUnsigned(0, sub.processID)
+	e.ContextObjectIdentifier(1, c.server.ObjectID())
+	e.ContextObjectIdentifier(2, sub.objectID)
+	e.ContextUnsigned(3, sub.timeRemaining(now))
+
+	e.Opening(4)
+	for _, prop := range []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags} {
+		value, err := c.server.readProperty(sub.objectID, prop)
+		if err != nil {
+			return nil, err
+		}
+		e.ContextEnumerated(0, uint32(prop))
+		e.Opening(2)
+		if err := e.Value(value); err != nil {
+			return nil, err
+		}
+		e.Closing(2)
+	}
+	e.Closing(4)
+
+	return e.Bytes(), nil
+}
+
+// persistedCOVSubscription is the stored form of a served COV subscription
+type persistedCOVSubscription struct {
+	Address   string           `json:"address"`
+	SrcNet    uint16           `json:"src_net,omitempty"`
+	SrcAddr   []byte           `json:"src_addr,omitempty"`
+	ProcessID uint32           `json:"process_id"`
+	ObjectID  ObjectIdentifier `json:"object_id"`
+	Confirmed bool             `json:"confirmed"`
+	Expires   time.Time        `json:"expires,omitempty"`
+}
+
+// storeCOVSubscription persists a served COV subscription
+func (c *Client) storeCOVSubscription(key servedCOVKey, sub *servedCOVSubscription) {
+	p := persistedCOVSubscription{
+		Address:   key.addr,
+		ProcessID: sub.processID,
+		ObjectID:  sub.objectID,
+		Confirmed: sub.confirmed,
+		Expires:   sub.expires,
+	}
+	if sub.route != nil && sub.route.Control&NPDUControlSourceSpecifier != 0 {
+		p.SrcNet = sub.route.SrcNet
+		p.SrcAddr = sub.route.SrcAddr
+	}
+	c.storeJSON(key.storageKey(), p)
+}
+
+// restoreCOVSubscriptions loads the persisted COV subscriptions to local
+// objects. Expired subscriptions are dropped.
+func (c *Client) restoreCOVSubscriptions(ctx context.Context) error {
+	now := c.opts.clock.Now()
+	newSub := func() interface{} { return &persistedCOVSubscription{} }
+	return c.loadJSON(ctx, storageCOVPrefix, newSub, func(storageKey string, v interface{}) {
+		p := v.(*persistedCOVSubscription)
+
+		addr, err := c.link.ParseAddr(p.Address)
+		if err != nil {
+			c.logger.Warn("dropping persisted COV subscription",
+				slog.String("subscriber", p.Address),
+				slog.String("error", err.Error()),
+			)
+			c.storeDelete(storageKey)
+			return
+		}
+		sub := &servedCOVSubscription{
+			processID: p.ProcessID,
+			objectID:  p.ObjectID,
+			addr:      addr,
+			confirmed: p.Confirmed,
+			expires:   p.Expires,
+		}
+		if p.SrcNet != 0 {
+			sub.route = &NPDU{
+				Control: NPDUControlSourceSpecifier,
+				SrcNet:  p.SrcNet,
+				SrcAddr: p.SrcAddr,
+			}
+		}
+		if sub.expired(now) {
+			c.storeDelete(storageKey)
+			return
+		}
+
+		key := servedCOVKey{addr: p.Address, processID: p.ProcessID, objectID: p.ObjectID}
+		c.servedCOVMu.Lock()
+		if _, exists := c.servedCOVSubs[key]; !exists {
+			c.servedCOVSubs[key] = sub
+			c.metrics.ActiveSubscriptions.Inc()
+		}
+		c.servedCOVMu.Unlock()
+	})
+}
@@ -0,0 +1,397 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"log/slog"
+	"net"
+	"strings"
+
+	"github.com/edgeo-scada/bacnet/internal/transport"
+)
+
+// ProtocolTraceFunc receives a human-readable breakdown of every frame sent
+// or received, with the link address of the peer. It runs on the sending or
+// receiving goroutine and must not block.
+type ProtocolTraceFunc func(sent bool, peer net.Addr, dump string)
+
+// logProtocolTrace returns a trace function that logs each dump at Debug
+// level before passing it on to next, if any
+func logProtocolTrace(logger *slog.Logger, next ProtocolTraceFunc) ProtocolTraceFunc {
+	return func(sent bool, peer net.Addr, dump string) {
+		direction := "received"
+		if sent {
+			direction = "sent"
+		}
+		peerStr := "broadcast"
+		if peer != nil {
+			peerStr = peer.String()
+		}
+		logger.Debug("protocol trace",
+			slog.String("direction", direction),
+			slog.String("peer", peerStr),
+			slog.String("dump", dump))
+		if next != nil {
+			next(sent, peer, dump)
+		}
+	}
+}
+
+// udpCapture returns the function observing the datagrams of the UDP data
+// links, feeding the packet capture and the protocol trace, or nil if
+// neither is enabled
+func (o *clientOptions) udpCapture() transport.CaptureFunc {
+	capture, trace := o.capture, o.trace
+	if capture == nil && trace == nil {
+		return nil
+	}
+	return func(sent bool, local, remote *net.UDPAddr, data []byte) {
+		if capture != nil {
+			capture.capture(sent, local, remote, data)
+		}
+		if trace != nil {
+			trace(sent, remote, DumpFrame(data))
+		}
+	}
+}
+
+// traceNPDU passes an NPDU sent or received on a data link without a
+// datagram capture hook to the protocol trace
+func (c *Client) traceNPDU(sent bool, addr net.Addr, npdu []byte) {
+	if c.npduTrace != nil {
+		c.npduTrace(sent, addr, DumpNPDU(npdu))
+	}
+}
+
+var bvlcFunctionNames = map[BVLCFunction]string{
+	BVLCResult:                            "BVLC-Result",
+	BVLCWriteBroadcastDistributionTable:   "Write-Broadcast-Distribution-Table",
+	BVLCReadBroadcastDistributionTable:    "Read-Broadcast-Distribution-Table",
+	BVLCReadBroadcastDistributionTableAck: "Read-Broadcast-Distribution-Table-Ack",
+	BVLCForwardedNPDU:                     "Forwarded-NPDU",
+	BVLCRegisterForeignDevice:             "Register-Foreign-Device",
+	BVLCReadForeignDeviceTable:            "Read-Foreign-Device-Table",
+	BVLCReadForeignDeviceTableAck:         "Read-Foreign-Device-Table-Ack",
+	BVLCDeleteForeignDeviceTableEntry:     "Delete-Foreign-Device-Table-Entry",
+	BVLCDistributeBroadcastToNetwork:      "Distribute-Broadcast-To-Network",
+	BVLCOriginalUnicastNPDU:               "Original-Unicast-NPDU",
+	BVLCOriginalBroadcastNPDU:             "Original-Broadcast-NPDU",
+	BVLCSecureBVLL:                        "Secure-BVLL",
+}
+
+var bvlc6FunctionNames = map[BVLC6Function]string{
+	BVLC6Result:                       "BVLC-Result",
+	BVLC6OriginalUnicastNPDU:          "Original-Unicast-NPDU",
+	BVLC6OriginalBroadcastNPDU:        "Original-Broadcast-NPDU",
+	BVLC6AddressResolution:            "Address-Resolution",
+	BVLC6ForwardedAddressResolution:   "Forwarded-Address-Resolution",
+	BVLC6AddressResolutionAck:         "Address-Resolution-Ack",
+	BVLC6VirtualAddressResolution:     "Virtual-Address-Resolution",
+	BVLC6VirtualAddressResolutionAck:  "Virtual-Address-Resolution-Ack",
+	BVLC6ForwardedNPDU:                "Forwarded-NPDU",
+	BVLC6RegisterForeignDevice:        "Register-Foreign-Device",
+	BVLC6DeleteForeignDeviceEntry:     "Delete-Foreign-Device-Table-Entry",
+	BVLC6DistributeBroadcastToNetwork: "Distribute-Broadcast-To-Network",
+}
+
+var pduTypeNames = map[PDUType]string{
+	PDUTypeConfirmedRequest:   "Confirmed-Request",
+	PDUTypeUnconfirmedRequest: "Unconfirmed-Request",
+	PDUTypeSimpleAck:          "Simple-Ack",
+	PDUTypeComplexAck:         "Complex-Ack",
+	PDUTypeSegmentAck:         "Segment-Ack",
+	PDUTypeError:              "Error",
+	PDUTypeReject:             "Reject",
+	PDUTypeAbort:              "Abort",
+}
+
+var applicationTagNames = map[ApplicationTag]string{
+	TagNull:            "Null",
+	TagBoolean:         "Boolean",
+	TagUnsignedInt:     "Unsigned",
+	TagSignedInt:       "Signed",
+	TagReal:            "Real",
+	TagDouble:          "Double",
+	TagOctetString:     "OctetString",
+	TagCharacterString: "CharacterString",
+	TagBitString:       "BitString",
+	TagEnumerated:      "Enumerated",
+	TagDate:            "Date",
+	TagTime:            "Time",
+	TagObjectID:        "ObjectIdentifier",
+}
+
+var priorityNames = [...]string{"normal", "urgent", "critical", "life-safety"}
+
+// dumpWriter accumulates the indented lines of a dump
+type dumpWriter struct {
+	b strings.Builder
+}
+
+func (w *dumpWriter) line(indent int, format string, args ...interface{}) {
+	if w.b.Len() > 0 {
+		w.b.WriteByte('\n')
+	}
+	w.b.WriteString(strings.Repeat("  ", indent))
+	fmt.Fprintf(&w.b, format, args...)
+}
+
+// DumpFrame renders a BACnet/IP or BACnet/IPv6 datagram, BVLC header
+// included, as a human-readable breakdown
+func DumpFrame(frame []byte) string {
+	var w dumpWriter
+	w.frame(frame)
+	return w.b.String()
+}
+
+// DumpNPDU renders an NPDU and the APDU it carries as a human-readable
+// breakdown
+func DumpNPDU(npdu []byte) string {
+	var w dumpWriter
+	w.npdu(npdu)
+	return w.b.String()
+}
+
+func (w *dumpWriter) frame(data []byte) {
+	if len(data) < 4 {
+		w.line(0, "BVLC: truncated (% x)", data)
+		return
+	}
+	length := int(binary.BigEndian.Uint16(data[2:4]))
+
+	switch BVLCType(data[0]) {
+	case BVLCTypeBACnetIP:
+		fn := BVLCFunction(data[1])
+		w.line(0, "BVLC: %s (0x%02x), length %d", nameOr(bvlcFunctionNames[fn], "unknown"), data[1], length)
+		body := data[4:]
+		switch fn {
+		case BVLCOriginalUnicastNPDU, BVLCOriginalBroadcastNPDU, BVLCDistributeBroadcastToNetwork:
+			w.npdu(body)
+		case BVLCForwardedNPDU:
+			if len(body) < 6 {
+				w.line(1, "truncated (% x)", body)
+				return
+			}
+			w.line(1, "originator %s", bipAddrString(body[:6]))
+			w.npdu(body[6:])
+		case BVLCResult:
+			if len(body) >= 2 {
+				w.line(1, "result code 0x%04x", binary.BigEndian.Uint16(body))
+			}
+		default:
+			if len(body) > 0 {
+				w.line(1, "% x", body)
+			}
+		}
+
+	case BVLCTypeBACnetIPv6:
+		fn := BVLC6Function(data[1])
+		w.line(0, "BVLC6: %s (0x%02x), length %d", nameOr(bvlc6FunctionNames[fn], "unknown"), data[1], length)
+		if len(data) < 7 {
+			w.line(1, "truncated (% x)", data[4:])
+			return
+		}
+		w.line(1, "source VMAC % x", data[4:7])
+		body := data[7:]
+		switch fn {
+		case BVLC6OriginalUnicastNPDU:
+			if len(body) < 3 {
+				w.line(1, "truncated (% x)", body)
+				return
+			}
+			w.line(1, "destination VMAC % x", body[:3])
+			w.npdu(body[3:])
+		case BVLC6OriginalBroadcastNPDU, BVLC6DistributeBroadcastToNetwork:
+			w.npdu(body)
+		case BVLC6ForwardedNPDU:
+			if len(body) < 18 {
+				w.line(1, "truncated (% x)", body)
+				return
+			}
+			orig := &net.UDPAddr{IP: net.IP(body[:16]), Port: int(binary.BigEndian.Uint16(body[16:18]))}
+			w.line(1, "originator %s", orig)
+			w.npdu(body[18:])
+		default:
+			if len(body) > 0 {
+				w.line(1, "% x", body)
+			}
+		}
+
+	default:
+		w.line(0, "BVLC: unknown type 0x%02x (% x)", data[0], data)
+	}
+}
+
+func (w *dumpWriter) npdu(data []byte) {
+	npdu, offset, err := DecodeNPDU(data)
+	if err != nil {
+		w.line(0, "NPDU: %v (% x)", err, data)
+		return
+	}
+
+	flags := []string{fmt.Sprintf("version %d", npdu.Version)}
+	if npdu.Control&NPDUControlExpectingReply != 0 {
+		flags = append(flags, "expecting reply")
+	}
+	flags = append(flags, "priority "+priorityNames[npdu.Control&npduPriorityMask])
+	w.line(0, "NPDU: %s", strings.Join(flags, ", "))
+	if npdu.Control&NPDUControlDestSpecifier != 0 {
+		w.line(1, "DNET %d, DADR %s, hop count %d", npdu.DestNet, macString(npdu.DestAddr), npdu.DestHopCount)
+	}
+	if npdu.Control&NPDUControlSourceSpecifier != 0 {
+		w.line(1, "SNET %d, SADR %s", npdu.SrcNet, macString(npdu.SrcAddr))
+	}
+	if npdu.Control&NPDUControlNetworkLayerMessage != 0 {
+		w.line(1, "network message 0x%02x", uint8(npdu.MessageType))
+		if offset < len(data) {
+			w.line(1, "% x", data[offset:])
+		}
+		return
+	}
+	w.apdu(data[offset:])
+}
+
+func (w *dumpWriter) apdu(data []byte) {
+	apdu, err := DecodeAPDU(data)
+	if err != nil {
+		w.line(0, "APDU: %v (% x)", err, data)
+		return
+	}
+
+	name := nameOr(pduTypeNames[apdu.Type], "unknown")
+	switch apdu.Type {
+	case PDUTypeConfirmedRequest:
+		w.line(0, "APDU: %s, invoke ID %d, service %s (%d), max APDU %d, max segments %d",
+			name, apdu.InvokeID, ConfirmedServiceChoice(apdu.Service), apdu.Service, apdu.MaxAPDU, apdu.MaxSegments)
+	case PDUTypeUnconfirmedRequest:
+		w.line(0, "APDU: %s, service %s (%d)", name, UnconfirmedServiceChoice(apdu.Service), apdu.Service)
+	case PDUTypeSimpleAck, PDUTypeComplexAck, PDUTypeError:
+		w.line(0, "APDU: %s, invoke ID %d, service %s (%d)",
+			name, apdu.InvokeID, ConfirmedServiceChoice(apdu.Service), apdu.Service)
+	case PDUTypeReject:
+		w.line(0, "APDU: %s, invoke ID %d, reason %s", name, apdu.InvokeID, RejectReason(apdu.Service))
+		return
+	case PDUTypeAbort:
+		w.line(0, "APDU: %s, invoke ID %d, reason %s, from %s", name, apdu.InvokeID, AbortReason(apdu.Service), abortOrigin(apdu.Server))
+		return
+	default:
+		w.line(0, "APDU: %s", name)
+	}
+	if apdu.Segmented {
+		w.line(1, "segment %d, window size %d, more follows %t", apdu.SequenceNum, apdu.WindowSize, apdu.MoreFollows)
+		if len(apdu.Data) > 0 {
+			w.line(1, "% x", apdu.Data)
+		}
+		return
+	}
+	w.tags(apdu.Data)
+}
+
+// tags renders the tagged values of a service request or ack
+func (w *dumpWriter) tags(data []byte) {
+	d := NewDecoder(data)
+	depth := 1
+	for d.Len() > 0 {
+		t, ok := d.peek()
+		if !ok {
+			w.line(depth, "invalid tag (% x)", d.Rest())
+			return
+		}
+		switch {
+		case t.class == TagClassContext && t.length == -1:
+			w.line(depth, "[%d] {", t.num)
+			d.offset += t.headerLen
+			depth++
+		case t.class == TagClassContext && t.length == -2:
+			if depth > 1 {
+				depth--
+			}
+			w.line(depth, "} [%d]", t.num)
+			d.offset += t.headerLen
+		case t.class == TagClassContext:
+			content := d.content(t)
+			if d.Err() != nil {
+				w.line(depth, "[%d] truncated (% x)", t.num, d.Rest())
+				return
+			}
+			if len(content) > 0 && len(content) <= 4 {
+				w.line(depth, "[%d] % x (%d)", t.num, content, decodeUnsigned(content))
+			} else {
+				w.line(depth, "[%d] % x", t.num, content)
+			}
+		default:
+			tag := ApplicationTag(t.num)
+			var value interface{}
+			if tag == TagDate {
+				value = dateString(d.content(t))
+			} else {
+				value = d.Value()
+			}
+			if d.Err() != nil {
+				w.line(depth, "%s truncated (% x)", nameOr(applicationTagNames[tag], "tag"), d.Rest())
+				return
+			}
+			if b, ok := value.([]byte); ok {
+				value = fmt.Sprintf("% x", b)
+			}
+			w.line(depth, "%s %v", nameOr(applicationTagNames[tag], fmt.Sprintf("tag %d", t.num)), value)
+		}
+	}
+}
+
+// decodeUnsigned decodes a big-endian unsigned integer of up to 4 octets
+func decodeUnsigned(data []byte) uint32 {
+	var v uint32
+	for _, b := range data {
+		v = v<<8 | uint32(b)
+	}
+	return v
+}
+
+// dateString renders the content of a date, with * for unspecified fields
+func dateString(data []byte) string {
+	if len(data) != 4 {
+		return fmt.Sprintf("% x", data)
+	}
+	field := func(v byte, offset int) string {
+		if v == 0xFF {
+			return "*"
+		}
+		return fmt.Sprint(int(v) + offset)
+	}
+	return fmt.Sprintf("%s-%s-%s", field(data[0], 1900), field(data[1], 0), field(data[2], 0))
+}
+
+// bipAddrString renders a 6-octet B/IP address
+func bipAddrString(b []byte) string {
+	return (&net.UDPAddr{IP: net.IP(b[:4]), Port: int(binary.BigEndian.Uint16(b[4:6]))}).String()
+}
+
+// macString renders a MAC address, empty for a broadcast
+func macString(mac []byte) string {
+	if len(mac) == 0 {
+		return "broadcast"
+	}
+	return fmt.Sprintf("% x", mac)
+}
+
+func nameOr(name, fallback string) string {
+	if name == "" {
+		return fallback
+	}
+	return name
+}
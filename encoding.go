@@ -0,0 +1,776 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"encoding/binary"
+	"fmt"
+	"math"
+)
+
+// Encoder writes BACnet tagged values into a reusable buffer. The zero value
+// is ready to use. Service encoders append every parameter to one Encoder
+// instead of concatenating the slices of the Encode* helpers.
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder creates an encoder appending to buf[:0], reusing its capacity
+func NewEncoder(buf []byte) *Encoder {
+	return &Encoder{buf: buf[:0]}
+}
+
+// Bytes returns the encoded data. It aliases the encoder buffer until the
+// next Reset.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Len returns the number of encoded bytes
+func (e *Encoder) Len() int {
+	return len(e.buf)
+}
+
+// Reset empties the encoder, keeping its buffer
+func (e *Encoder) Reset() {
+	e.buf = e.buf[:0]
+}
+
+// truncate drops the data encoded after the first n bytes
+func (e *Encoder) truncate(n int) {
+	e.buf = e.buf[:n]
+}
+
+// Raw appends pre-encoded data
+func (e *Encoder) Raw(data []byte) {
+	e.buf = append(e.buf, data...)
+}
+
+// Tag appends a tag header
+func (e *Encoder) Tag(tagNum uint8, class TagClass, length int) {
+	e.buf = appendTag(e.buf, tagNum, class, length)
+}
+
+// Opening appends an opening tag
+func (e *Encoder) Opening(tagNum uint8) {
+	e.buf = appendOpeningTag(e.buf, tagNum)
+}
+
+// Closing appends a closing tag
+func (e *Encoder) Closing(tagNum uint8) {
+	e.buf = appendClosingTag(e.buf, tagNum)
+}
+
+// Null appends an application-tagged null
+func (e *Encoder) Null() {
+	e.buf = append(e.buf, 0x00)
+}
+
+// Boolean appends an application-tagged boolean
+func (e *Encoder) Boolean(v bool) {
+	if v {
+		e.buf = append(e.buf, 0x11)
+	} else {
+		e.buf = append(e.buf, 0x10)
+	}
+}
+
+// Unsigned appends an application-tagged unsigned integer
+func (e *Encoder) Unsigned(v uint32) {
+	e.buf = appendTag(e.buf, uint8(TagUnsignedInt), TagClassApplication, unsignedLen(v))
+	e.buf = appendUnsigned(e.buf, v)
+}
+
+// Signed appends an application-tagged signed integer
+func (e *Encoder) Signed(v int32) {
+	e.buf = appendTag(e.buf, uint8(TagSignedInt), TagClassApplication, signedLen(v))
+	e.buf = appendSigned(e.buf, v)
+}
+
+// Real appends an application-tagged float32
+func (e *Encoder) Real(v float32) {
+	e.buf = appendTag(e.buf, uint8(TagReal), TagClassApplication, 4)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(v))
+}
+
+// Double appends an application-tagged float64
+func (e *Encoder) Double(v float64) {
+	e.buf = appendTag(e.buf, uint8(TagDouble), TagClassApplication, 8)
+	e.buf = binary.BigEndian.AppendUint64(e.buf, math.Float64bits(v))
+}
+
+// OctetString appends an application-tagged octet string
+func (e *Encoder) OctetString(v []byte) {
+	e.buf = appendTag(e.buf, uint8(TagOctetString), TagClassApplication, len(v))
+	e.buf = append(e.buf, v...)
+}
+
+// CharacterString appends an application-tagged UTF-8 character string
+func (e *Encoder) CharacterString(v string) {
+	e.buf = appendTag(e.buf, uint8(TagCharacterString), TagClassApplication, 1+len(v))
+	e.buf = append(e.buf, 0) // UTF-8
+	e.buf = append(e.buf, v...)
+}
+
+// BitString appends an application-tagged bit string
+func (e *Encoder) BitString(v BitString) {
+	e.buf = appendTag(e.buf, uint8(TagBitString), TagClassApplication, bitStringLen(v))
+	e.buf = appendBitString(e.buf, v)
+}
+
+// Enumerated appends an application-tagged enumerated value
+func (e *Encoder) Enumerated(v uint32) {
+	e.buf = appendTag(e.buf, uint8(TagEnumerated), TagClassApplication, unsignedLen(v))
+	e.buf = appendUnsigned(e.buf, v)
+}
+
+// Time appends an application-tagged time
+func (e *Encoder) Time(v TimeOfDay) {
+	e.buf = appendTag(e.buf, uint8(TagTime), TagClassApplication, 4)
+	e.buf = append(e.buf, v.Hour, v.Minute, v.Second, v.Hundredths)
+}
+
+// ObjectIdentifier appends an application-tagged object identifier
+func (e *Encoder) ObjectIdentifier(v ObjectIdentifier) {
+	e.buf = appendTag(e.buf, uint8(TagObjectID), TagClassApplication, 4)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, v.Encode())
+}
+
+// ContextUnsigned appends a context-tagged unsigned integer
+func (e *Encoder) ContextUnsigned(tagNum uint8, v uint32) {
+	e.buf = appendTag(e.buf, tagNum, TagClassContext, unsignedLen(v))
+	e.buf = appendUnsigned(e.buf, v)
+}
+
+// ContextSigned appends a context-tagged signed integer
+func (e *Encoder) ContextSigned(tagNum uint8, v int32) {
+	e.buf = appendTag(e.buf, tagNum, TagClassContext, signedLen(v))
+	e.buf = appendSigned(e.buf, v)
+}
+
+// ContextReal appends a context-tagged float32
+func (e *Encoder) ContextReal(tagNum uint8, v float32) {
+	e.buf = appendTag(e.buf, tagNum, TagClassContext, 4)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, math.Float32bits(v))
+}
+
+// ContextBoolean appends a context-tagged boolean
+func (e *Encoder) ContextBoolean(tagNum uint8, v bool) {
+	e.buf = appendTag(e.buf, tagNum, TagClassContext, 1)
+	if v {
+		e.buf = append(e.buf, 1)
+	} else {
+		e.buf = append(e.buf, 0)
+	}
+}
+
+// ContextEnumerated appends a context-tagged enumerated value
+func (e *Encoder) ContextEnumerated(tagNum uint8, v uint32) {
+	e.ContextUnsigned(tagNum, v)
+}
+
+// ContextObjectIdentifier appends a context-tagged object identifier
+func (e *Encoder) ContextObjectIdentifier(tagNum uint8, v ObjectIdentifier) {
+	e.buf = appendTag(e.buf, tagNum, TagClassContext, 4)
+	e.buf = binary.BigEndian.AppendUint32(e.buf, v.Encode())
+}
+
+// ContextCharacterString appends a context-tagged UTF-8 character string
+func (e *Encoder) ContextCharacterString(tagNum uint8, v string) {
+	e.buf = appendTag(e.buf, tagNum, TagClassContext, 1+len(v))
+	e.buf = append(e.buf, 0) // UTF-8
+	e.buf = append(e.buf, v...)
+}
+
+// ContextOctetString appends context-tagged raw data
+func (e *Encoder) ContextOctetString(tagNum uint8, v []byte) {
+	e.buf = appendTag(e.buf, tagNum, TagClassContext, len(v))
+	e.buf = append(e.buf, v...)
+}
+
+// ContextBitString appends a context-tagged bit string
+func (e *Encoder) ContextBitString(tagNum uint8, v BitString) {
+	e.buf = appendTag(e.buf, tagNum, TagClassContext, bitStringLen(v))
+	e.buf = appendBitString(e.buf, v)
+}
+
+// Value appends a Go value as application-tagged data, using the mapping of
+// WriteProperty: integers, floats, strings, booleans, nil, BACnet
+// enumerations, bit strings, object identifiers, times, weekly and
+// exception schedules and slices of those
+func (e *Encoder) Value(value interface{}) error {
+	switch v := value.(type) {
+	case nil:
+		e.Null()
+	case bool:
+		e.Boolean(v)
+	case int:
+		if v >= 0 {
+			e.Unsigned(uint32(v))
+		} else {
+			e.Signed(int32(v))
+		}
+	case int32:
+		if v >= 0 {
+			e.Unsigned(uint32(v))
+		} else {
+			e.Signed(v)
+		}
+	case uint32:
+		e.Unsigned(v)
+	case float32:
+		e.Real(v)
+	case float64:
+		e.Double(v)
+	case string:
+		e.CharacterString(v)
+	case ObjectIdentifier:
+		e.ObjectIdentifier(v)
+	case uint8:
+		e.Unsigned(uint32(v))
+	case uint16:
+		e.Unsigned(uint32(v))
+	case []byte:
+		e.OctetString(v)
+	case Enumerated:
+		e.Enumerated(uint32(v))
+	case ObjectType:
+		e.Enumerated(uint32(v))
+	case EventState:
+		e.Enumerated(uint32(v))
+	case Reliability:
+		e.Enumerated(uint32(v))
+	case EngineeringUnits:
+		e.Enumerated(uint32(v))
+	case Segmentation:
+		e.Enumerated(uint32(v))
+	case DeviceStatus:
+		e.Enumerated(uint32(v))
+	case BitString:
+		e.BitString(v)
+	case TimeOfDay:
+		e.Time(v)
+	case WeeklySchedule:
+		return v.encode(e)
+	case ExceptionSchedule:
+		return v.encode(e)
+	case LightingCommand:
+		return v.encode(e)
+	case Destination:
+		return v.encode(e)
+	case []Destination:
+		for _, dst := range v {
+			if err := dst.encode(e); err != nil {
+				return err
+			}
+		}
+	case LightingOperation:
+		e.Enumerated(uint32(v))
+	case BinaryLightingValue:
+		e.Enumerated(uint32(v))
+	case StatusFlags:
+		bits := NewBitString(4)
+		bits.Set(0, v.InAlarm)
+		bits.Set(1, v.Fault)
+		bits.Set(2, v.Overridden)
+		bits.Set(3, v.OutOfService)
+		e.BitString(bits)
+	case []ObjectIdentifier:
+		for _, oid := range v {
+			e.ObjectIdentifier(oid)
+		}
+	case []string:
+		for _, str := range v {
+			e.CharacterString(str)
+		}
+	case []interface{}:
+		for _, elem := range v {
+			if err := e.Value(elem); err != nil {
+				return err
+			}
+		}
+	default:
+		return fmt.Errorf("unsupported value type: %T", value)
+	}
+	return nil
+}
+
+// appendTag appends a tag header
+func appendTag(buf []byte, tagNum uint8, class TagClass, length int) []byte {
+	if length < 5 && tagNum < 15 {
+		// Short form
+		return append(buf, (tagNum<<4)|(uint8(class)<<3)|uint8(length))
+	}
+
+	// Extended tag number
+	lvt := uint8(length)
+	if length >= 5 {
+		lvt = 5
+	}
+	if tagNum >= 15 {
+		buf = append(buf, 0xF0|(uint8(class)<<3)|lvt, tagNum)
+	} else {
+		buf = append(buf, (tagNum<<4)|(uint8(class)<<3)|lvt)
+	}
+
+	// Extended length
+	switch {
+	case length < 5:
+	case length < 254:
+		buf = append(buf, byte(length))
+	case length < 65536:
+		buf = append(buf, 254, byte(length>>8), byte(length))
+	default:
+		buf = append(buf, 255, byte(length>>24), byte(length>>16), byte(length>>8), byte(length))
+	}
+	return buf
+}
+
+// appendOpeningTag appends an opening tag
+func appendOpeningTag(buf []byte, tagNum uint8) []byte {
+	if tagNum < 15 {
+		return append(buf, (tagNum<<4)|0x0E)
+	}
+	return append(buf, 0xFE, tagNum)
+}
+
+// appendClosingTag appends a closing tag
+func appendClosingTag(buf []byte, tagNum uint8) []byte {
+	if tagNum < 15 {
+		return append(buf, (tagNum<<4)|0x0F)
+	}
+	return append(buf, 0xFF, tagNum)
+}
+
+// unsignedLen returns the encoded length of an unsigned integer
+func unsignedLen(v uint32) int {
+	switch {
+	case v < 0x100:
+		return 1
+	case v < 0x10000:
+		return 2
+	case v < 0x1000000:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// appendUnsigned appends an unsigned integer in the fewest octets
+func appendUnsigned(buf []byte, v uint32) []byte {
+	switch unsignedLen(v) {
+	case 1:
+		return append(buf, byte(v))
+	case 2:
+		return append(buf, byte(v>>8), byte(v))
+	case 3:
+		return append(buf, byte(v>>16), byte(v>>8), byte(v))
+	default:
+		return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+}
+
+// signedLen returns the encoded length of a signed integer
+func signedLen(v int32) int {
+	switch {
+	case v >= -128 && v < 128:
+		return 1
+	case v >= -32768 && v < 32768:
+		return 2
+	case v >= -8388608 && v < 8388608:
+		return 3
+	default:
+		return 4
+	}
+}
+
+// appendSigned appends a two's complement signed integer in the fewest
+// octets
+func appendSigned(buf []byte, v int32) []byte {
+	switch signedLen(v) {
+	case 1:
+		return append(buf, byte(v))
+	case 2:
+		return append(buf, byte(v>>8), byte(v))
+	case 3:
+		return append(buf, byte(v>>16), byte(v>>8), byte(v))
+	default:
+		return append(buf, byte(v>>24), byte(v>>16), byte(v>>8), byte(v))
+	}
+}
+
+// bitStringLen returns the encoded length of a bit string
+func bitStringLen(b BitString) int {
+	return 1 + (b.Length+7)/8
+}
+
+// appendBitString appends the unused-bits octet followed by the bits
+func appendBitString(buf []byte, b BitString) []byte {
+	n := (b.Length + 7) / 8
+	buf = append(buf, byte(n*8-b.Length))
+	for i := 0; i < n; i++ {
+		var octet byte
+		if i < len(b.Bits) {
+			octet = b.Bits[i]
+		}
+		buf = append(buf, octet)
+	}
+	return buf
+}
+
+// Decoder reads BACnet tagged values from a byte slice. The first error is
+// kept: once failed, every read returns a zero value, so a service decoder
+// reads all its parameters and checks Err once at the end. Errors wrap
+// ErrInvalidAPDU.
+type Decoder struct {
+	data   []byte
+	offset int
+	err    error
+}
+
+// NewDecoder creates a decoder reading data from the start
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Err returns the first decoding error
+func (d *Decoder) Err() error {
+	return d.err
+}
+
+// Offset returns the position of the next tag
+func (d *Decoder) Offset() int {
+	return d.offset
+}
+
+// Len returns the number of bytes left
+func (d *Decoder) Len() int {
+	return len(d.data) - d.offset
+}
+
+// Rest returns the bytes left
+func (d *Decoder) Rest() []byte {
+	return d.data[d.offset:]
+}
+
+// Fail records an error found by the caller, e.g. a value out of range. Only
+// the first error is kept.
+func (d *Decoder) Fail(err error) {
+	if d.err == nil {
+		d.err = err
+	}
+}
+
+// failf records a malformed data error at the current offset
+func (d *Decoder) failf(format string, args ...interface{}) {
+	if d.err == nil {
+		d.err = fmt.Errorf("%w: %s at offset %d", ErrInvalidAPDU, fmt.Sprintf(format, args...), d.offset)
+	}
+}
+
+// tagHeader is a decoded tag. Length is -1 for an opening tag and -2 for a
+// closing tag.
+type tagHeader struct {
+	num       uint8
+	class     TagClass
+	length    int
+	headerLen int
+}
+
+// peek decodes the next tag without consuming it
+func (d *Decoder) peek() (tagHeader, bool) {
+	if d.err != nil || d.offset >= len(d.data) {
+		return tagHeader{}, false
+	}
+	num, class, length, headerLen, err := DecodeTagNumber(d.data[d.offset:])
+	if err != nil {
+		return tagHeader{}, false
+	}
+	return tagHeader{num: num, class: class, length: length, headerLen: headerLen}, true
+}
+
+// IsContext reports whether the next tag is a primitive context tag with
+// the given number, for optional parameters
+func (d *Decoder) IsContext(tagNum uint8) bool {
+	t, ok := d.peek()
+	return ok && t.class == TagClassContext && t.num == tagNum && t.length >= 0
+}
+
+// IsOpening reports whether the next tag is the given opening tag
+func (d *Decoder) IsOpening(tagNum uint8) bool {
+	t, ok := d.peek()
+	return ok && t.class == TagClassContext && t.num == tagNum && t.length == -1
+}
+
+// IsClosing reports whether the next tag is the given closing tag
+func (d *Decoder) IsClosing(tagNum uint8) bool {
+	t, ok := d.peek()
+	return ok && t.class == TagClassContext && t.num == tagNum && t.length == -2
+}
+
+// Opening consumes the given opening tag
+func (d *Decoder) Opening(tagNum uint8) {
+	if !d.IsOpening(tagNum) {
+		d.failf("expected opening tag %d", tagNum)
+		return
+	}
+	t, _ := d.peek()
+	d.offset += t.headerLen
+}
+
+// Closing consumes the given closing tag
+func (d *Decoder) Closing(tagNum uint8) {
+	if !d.IsClosing(tagNum) {
+		d.failf("expected closing tag %d", tagNum)
+		return
+	}
+	t, _ := d.peek()
+	d.offset += t.headerLen
+}
+
+// Skip consumes the next element: a primitive value, or a constructed value
+// up to its matching closing tag
+func (d *Decoder) Skip() {
+	t, ok := d.peek()
+	if !ok {
+		d.failf("expected a value")
+		return
+	}
+	if t.length == -2 {
+		d.failf("unexpected closing tag %d", t.num)
+		return
+	}
+	if t.length == -1 {
+		d.offset += t.headerLen
+		for d.err == nil && !d.IsClosing(t.num) {
+			d.Skip()
+		}
+		d.Closing(t.num)
+		return
+	}
+	d.content(t)
+}
+
+// content consumes a primitive tag and returns its content
+func (d *Decoder) content(t tagHeader) []byte {
+	length := t.length
+	if t.class == TagClassApplication && ApplicationTag(t.num) == TagBoolean {
+		length = 0 // the value is the length field
+	}
+	end := d.offset + t.headerLen + length
+	if length < 0 || end > len(d.data) || end < d.offset {
+		d.failf("truncated tag %d", t.num)
+		return nil
+	}
+	data := d.data[d.offset+t.headerLen : end]
+	d.offset = end
+	return data
+}
+
+// context consumes a context tag with a content length in [minLen, maxLen]
+func (d *Decoder) context(tagNum uint8, minLen, maxLen int) []byte {
+	t, ok := d.peek()
+	if !ok || t.class != TagClassContext || t.num != tagNum || t.length < 0 {
+		d.failf("expected context tag %d", tagNum)
+		return nil
+	}
+	if t.length < minLen || t.length > maxLen {
+		d.failf("invalid length %d of context tag %d", t.length, tagNum)
+		return nil
+	}
+	return d.content(t)
+}
+
+// application consumes an application tag with a content length in
+// [minLen, maxLen]
+func (d *Decoder) application(tag ApplicationTag, minLen, maxLen int) []byte {
+	t, ok := d.peek()
+	if !ok || t.class != TagClassApplication || ApplicationTag(t.num) != tag {
+		d.failf("expected application tag %d", tag)
+		return nil
+	}
+	if t.length < minLen || t.length > maxLen {
+		d.failf("invalid length %d of application tag %d", t.length, tag)
+		return nil
+	}
+	return d.content(t)
+}
+
+// ContextUnsigned reads a context-tagged unsigned integer
+func (d *Decoder) ContextUnsigned(tagNum uint8) uint32 {
+	return DecodeUnsigned(d.context(tagNum, 1, 4))
+}
+
+// ContextEnumerated reads a context-tagged enumerated value
+func (d *Decoder) ContextEnumerated(tagNum uint8) uint32 {
+	return d.ContextUnsigned(tagNum)
+}
+
+// ContextSigned reads a context-tagged signed integer
+func (d *Decoder) ContextSigned(tagNum uint8) int32 {
+	return DecodeSigned(d.context(tagNum, 1, 4))
+}
+
+// ContextReal reads a context-tagged float32
+func (d *Decoder) ContextReal(tagNum uint8) float32 {
+	return DecodeReal(d.context(tagNum, 4, 4))
+}
+
+// ContextBoolean reads a context-tagged boolean
+func (d *Decoder) ContextBoolean(tagNum uint8) bool {
+	data := d.context(tagNum, 1, 1)
+	return len(data) == 1 && data[0] != 0
+}
+
+// ContextObjectIdentifier reads a context-tagged object identifier
+func (d *Decoder) ContextObjectIdentifier(tagNum uint8) ObjectIdentifier {
+	return DecodeObjectIdentifierFromBytes(d.context(tagNum, 4, 4))
+}
+
+// ContextCharacterString reads a context-tagged character string
+func (d *Decoder) ContextCharacterString(tagNum uint8) string {
+	return DecodeCharacterString(d.context(tagNum, 1, math.MaxInt32))
+}
+
+// ContextOctetString reads the raw content of a context tag. The result
+// aliases the decoded data.
+func (d *Decoder) ContextOctetString(tagNum uint8) []byte {
+	return d.context(tagNum, 0, math.MaxInt32)
+}
+
+// Null reads an application-tagged null
+func (d *Decoder) Null() {
+	d.application(TagNull, 0, 0)
+}
+
+// Boolean reads an application-tagged boolean
+func (d *Decoder) Boolean() bool {
+	t, ok := d.peek()
+	if !ok || t.class != TagClassApplication || ApplicationTag(t.num) != TagBoolean || t.length > 1 {
+		d.failf("expected boolean")
+		return false
+	}
+	d.offset += t.headerLen
+	return t.length == 1
+}
+
+// Unsigned reads an application-tagged unsigned integer
+func (d *Decoder) Unsigned() uint32 {
+	return DecodeUnsigned(d.application(TagUnsignedInt, 1, 4))
+}
+
+// Signed reads an application-tagged signed integer
+func (d *Decoder) Signed() int32 {
+	return DecodeSigned(d.application(TagSignedInt, 1, 4))
+}
+
+// Real reads an application-tagged float32
+func (d *Decoder) Real() float32 {
+	return DecodeReal(d.application(TagReal, 4, 4))
+}
+
+// Double reads an application-tagged float64
+func (d *Decoder) Double() float64 {
+	return DecodeDouble(d.application(TagDouble, 8, 8))
+}
+
+// OctetString reads an application-tagged octet string. The result aliases
+// the decoded data.
+func (d *Decoder) OctetString() []byte {
+	return d.application(TagOctetString, 0, math.MaxInt32)
+}
+
+// CharacterString reads an application-tagged character string
+func (d *Decoder) CharacterString() string {
+	return DecodeCharacterString(d.application(TagCharacterString, 1, math.MaxInt32))
+}
+
+// BitString reads an application-tagged bit string
+func (d *Decoder) BitString() BitString {
+	return decodeBitString(d.application(TagBitString, 1, math.MaxInt32))
+}
+
+// Enumerated reads an application-tagged enumerated value
+func (d *Decoder) Enumerated() uint32 {
+	return DecodeUnsigned(d.application(TagEnumerated, 1, 4))
+}
+
+// Time reads an application-tagged time
+func (d *Decoder) Time() TimeOfDay {
+	data := d.application(TagTime, 4, 4)
+	if len(data) != 4 {
+		return TimeOfDay{}
+	}
+	return TimeOfDay{Hour: data[0], Minute: data[1], Second: data[2], Hundredths: data[3]}
+}
+
+// ObjectIdentifier reads an application-tagged object identifier
+func (d *Decoder) ObjectIdentifier() ObjectIdentifier {
+	return DecodeObjectIdentifierFromBytes(d.application(TagObjectID, 4, 4))
+}
+
+// Value reads the next application-tagged value and returns it as the Go
+// type returned by ReadProperty. Octet strings and unknown types alias the
+// decoded data.
+func (d *Decoder) Value() interface{} {
+	t, ok := d.peek()
+	if !ok || t.class != TagClassApplication {
+		d.failf("expected an application value")
+		return nil
+	}
+
+	switch ApplicationTag(t.num) {
+	case TagNull:
+		d.Null()
+		return nil
+	case TagBoolean:
+		return d.Boolean()
+	case TagUnsignedInt:
+		return d.Unsigned()
+	case TagSignedInt:
+		return d.Signed()
+	case TagReal:
+		return d.Real()
+	case TagDouble:
+		return d.Double()
+	case TagOctetString:
+		return d.OctetString()
+	case TagCharacterString:
+		return d.CharacterString()
+	case TagBitString:
+		return d.BitString()
+	case TagEnumerated:
+		return d.Enumerated()
+	case TagTime:
+		return d.Time()
+	case TagObjectID:
+		return d.ObjectIdentifier()
+	default:
+		return d.content(t)
+	}
+}
+
+// decodeBitString decodes the unused-bits octet and the bits of a bit
+// string
+func decodeBitString(data []byte) BitString {
+	if len(data) < 1 {
+		return BitString{}
+	}
+	length := (len(data)-1)*8 - int(data[0]&0x07)
+	if length < 0 {
+		length = 0
+	}
+	return BitString{Length: length, Bits: data[1:]}
+}
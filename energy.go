@@ -0,0 +1,322 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// EnergyPoint is an accumulator or analog point holding a cumulative
+// energy reading (e.g. a kWh register)
+type EnergyPoint struct {
+	// Name identifies the point in readings and intervals
+	Name string
+
+	DeviceID uint32
+	ObjectID ObjectIdentifier
+
+	// Scale multiplies raw values, e.g. the pulse weight of an accumulator.
+	// Zero means 1.
+	Scale float64
+
+	// Rollover is the raw value at which the register wraps to zero, e.g.
+	// 1000000 for a six-digit register. Zero if the register does not wrap.
+	Rollover float64
+}
+
+// EnergyReading is a scaled cumulative reading of an EnergyPoint
+type EnergyReading struct {
+	Point string
+	Time  time.Time
+	Value float64
+	Err   error
+}
+
+// EnergyInterval is the consumption of a point between two interval
+// boundaries
+type EnergyInterval struct {
+	Point string
+	Start time.Time
+	End   time.Time
+
+	StartValue  float64
+	EndValue    float64
+	Consumption float64
+
+	// Rollover is set when the register wrapped or was reset during the interval
+	Rollover bool
+}
+
+// ReadEnergyPoints reads the present value of each point. Points on the same
+// device are read with a single ReadPropertyMultiple, falling back to
+// ReadProperty for devices that do not support it; devices are read
+// concurrently. Readings are returned in the order of points.
+func (c *Client) ReadEnergyPoints(ctx context.Context, points []EnergyPoint) []EnergyReading {
+	readings := make([]EnergyReading, len(points))
+
+	byDevice := make(map[uint32][]int)
+	for i, p := range points {
+		byDevice[p.DeviceID] = append(byDevice[p.DeviceID], i)
+	}
+
+	var wg sync.WaitGroup
+	for deviceID, indexes := range byDevice {
+		wg.Add(1)
+		go func(deviceID uint32, indexes []int) {
+			defer wg.Done()
+			c.readDeviceEnergyPoints(ctx, deviceID, points, indexes, readings)
+		}(deviceID, indexes)
+	}
+	wg.Wait()
+
+	return readings
+}
+
+// readDeviceEnergyPoints reads the points of one device into readings
+func (c *Client) readDeviceEnergyPoints(ctx context.Context, deviceID uint32, points []EnergyPoint, indexes []int, readings []EnergyReading) {
+	requests := make([]ReadPropertyRequest, 0, len(indexes))
+	for _, i := range indexes {
+		requests = append(requests, ReadPropertyRequest{
+			ObjectID:   points[i].ObjectID,
+			PropertyID: PropertyPresentValue,
+		})
+	}
+
+	values := make(map[ObjectIdentifier]interface{})
+	errs := make(map[ObjectIdentifier]error)
+	results, err := c.ReadPropertyMultiple(ctx, deviceID, requests)
+	if err == nil {
+		for _, r := range results {
+			switch {
+			case r.PropertyID != PropertyPresentValue:
+			case r.Err != nil:
+				errs[r.ObjectID] = r.Err
+			default:
+				values[r.ObjectID] = r.Value
+			}
+		}
+	}
+
+	for _, i := range indexes {
+		p := points[i]
+		reading := EnergyReading{Point: p.Name, Time: c.opts.clock.Now()}
+
+		if err, failed := errs[p.ObjectID]; failed {
+			reading.Err = fmt.Errorf("%s: %w", p.Name, err)
+			readings[i] = reading
+			continue
+		}
+
+		value, ok := values[p.ObjectID]
+		if !ok {
+			var readErr error
+			if value, readErr = c.ReadProperty(ctx, deviceID, p.ObjectID, PropertyPresentValue); readErr != nil {
+				reading.Err = fmt.Errorf("%s: %w", p.Name, readErr)
+				readings[i] = reading
+				continue
+			}
+		}
+
+		if raw, numeric := toFloat64(value); numeric {
+			reading.Value = raw * p.scale()
+		} else {
+			reading.Err = fmt.Errorf("%s: non-numeric present value %T", p.Name, value)
+		}
+
+		readings[i] = reading
+	}
+}
+
+// scale returns the scale factor of the point
+func (p EnergyPoint) scale() float64 {
+	if p.Scale == 0 {
+		return 1
+	}
+	return p.Scale
+}
+
+// AlignInterval returns the interval boundary at or before t. Boundaries are
+// counted from midnight in the location of t, so intervals that divide a day
+// align to wall-clock times (e.g. :00, :15, :30, :45).
+func AlignInterval(t time.Time, interval time.Duration) time.Time {
+	if interval <= 0 {
+		return t
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.Add(t.Sub(midnight).Truncate(interval))
+}
+
+// ConsumptionDelta returns the consumption between two cumulative readings.
+// If the register went backwards it either wrapped at rollover, or was reset
+// when rollover is zero; wrapped is then true.
+func ConsumptionDelta(prev, curr, rollover float64) (delta float64, wrapped bool) {
+	if curr >= prev {
+		return curr - prev, false
+	}
+	if rollover > 0 {
+		return rollover - prev + curr, true
+	}
+	return curr, true
+}
+
+// EnergyRollup turns cumulative readings into interval consumption. Readings
+// are attributed to the interval boundary at or before their time.
+type EnergyRollup struct {
+	interval time.Duration
+
+	mu   sync.Mutex
+	last map[string]EnergyReading
+}
+
+// NewEnergyRollup creates a rollup for the given interval length
+func NewEnergyRollup(interval time.Duration) *EnergyRollup {
+	return &EnergyRollup{
+		interval: interval,
+		last:     make(map[string]EnergyReading),
+	}
+}
+
+// Add records a reading of a point and returns the interval it completes,
+// if any. The first reading of a point and repeated readings within the same
+// interval complete no interval. Failed readings are ignored.
+func (r *EnergyRollup) Add(point EnergyPoint, reading EnergyReading) (EnergyInterval, bool) {
+	if reading.Err != nil {
+		return EnergyInterval{}, false
+	}
+	reading.Time = AlignInterval(reading.Time, r.interval)
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	prev, ok := r.last[point.Name]
+	if ok && !reading.Time.After(prev.Time) {
+		return EnergyInterval{}, false
+	}
+	r.last[point.Name] = reading
+	if !ok {
+		return EnergyInterval{}, false
+	}
+
+	delta, wrapped := ConsumptionDelta(prev.Value, reading.Value, point.Rollover*point.scale())
+	return EnergyInterval{
+		Point:       point.Name,
+		Start:       prev.Time,
+		End:         reading.Time,
+		StartValue:  prev.Value,
+		EndValue:    reading.Value,
+		Consumption: delta,
+		Rollover:    wrapped,
+	}, true
+}
+
+// cursor returns the last reading recorded for a point
+func (r *EnergyRollup) cursor(name string) (EnergyReading, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	reading, ok := r.last[name]
+	return reading, ok
+}
+
+// setCursor restores the last reading of a point
+func (r *EnergyRollup) setCursor(name string, reading EnergyReading) {
+	r.mu.Lock()
+	r.last[name] = reading
+	r.mu.Unlock()
+}
+
+// energyCursor is the stored form of the last reading of a point
+type energyCursor struct {
+	Time  time.Time `json:"time"`
+	Value float64   `json:"value"`
+}
+
+// restoreEnergyCursors loads the persisted last readings of points, so that
+// the first interval after a restart is not lost
+func (c *Client) restoreEnergyCursors(ctx context.Context, rollup *EnergyRollup, points []EnergyPoint) {
+	if c.opts.storage == nil {
+		return
+	}
+	for _, p := range points {
+		data, err := c.opts.storage.Get(ctx, storageEnergyPrefix+p.Name)
+		if errors.Is(err, ErrStorageNotFound) {
+			continue
+		}
+		var cur energyCursor
+		if err == nil {
+			err = json.Unmarshal(data, &cur)
+		}
+		if err != nil {
+			c.logger.Warn("failed to restore energy cursor",
+				slog.String("point", p.Name),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		rollup.setCursor(p.Name, EnergyReading{Point: p.Name, Time: cur.Time, Value: cur.Value})
+	}
+}
+
+// RunEnergyRollup reads points at every interval boundary and calls emit
+// with each completed interval until ctx is cancelled. Intervals spanning
+// missed boundaries cover the whole gap. With WithStorage, the last reading
+// of each point survives a restart.
+func (c *Client) RunEnergyRollup(ctx context.Context, points []EnergyPoint, interval time.Duration, emit func(EnergyInterval)) error {
+	if interval <= 0 {
+		return fmt.Errorf("energy rollup: interval must be positive")
+	}
+
+	rollup := NewEnergyRollup(interval)
+	byName := make(map[string]EnergyPoint, len(points))
+	for _, p := range points {
+		byName[p.Name] = p
+	}
+
+	c.restoreEnergyCursors(ctx, rollup, points)
+
+	for {
+		readCtx, cancel := context.WithTimeout(ctx, interval)
+		readings := c.ReadEnergyPoints(readCtx, points)
+		cancel()
+
+		for _, reading := range readings {
+			if reading.Err != nil {
+				c.logger.Warn("energy read failed",
+					slog.String("point", reading.Point),
+					slog.String("error", reading.Err.Error()),
+				)
+				continue
+			}
+			prev, _ := rollup.cursor(reading.Point)
+			if iv, ok := rollup.Add(byName[reading.Point], reading); ok {
+				emit(iv)
+			}
+			if cur, _ := rollup.cursor(reading.Point); !cur.Time.Equal(prev.Time) {
+				c.storeJSON(storageEnergyPrefix+reading.Point, energyCursor{Time: cur.Time, Value: cur.Value})
+			}
+		}
+
+		now := c.opts.clock.Now()
+		next := AlignInterval(now, interval).Add(interval)
+		if err := sleepContext(ctx, c.opts.clock, next.Sub(now)); err != nil {
+			return err
+		}
+	}
+}
@@ -15,6 +15,7 @@
 package bacnet
 
 import (
+	"context"
 	"errors"
 	"fmt"
 )
@@ -33,10 +34,19 @@ var (
 	ErrWriteFailed       = errors.New("bacnet: write failed")
 	ErrNotConnected      = errors.New("bacnet: not connected")
 	ErrAlreadyConnected  = errors.New("bacnet: already connected")
+	ErrNoLocalDevice     = errors.New("bacnet: no local device configured")
+	ErrNoReply           = errors.New("bacnet: request dropped without reply")
+	ErrStandby           = errors.New("bacnet: standby instance cannot issue writes or subscriptions")
+	ErrRolloutFailed     = errors.New("bacnet: schedule rollout failed")
+	ErrSelfTestFailed    = errors.New("bacnet: self-test failed")
+	ErrStorageNotFound   = errors.New("bacnet: key not found in storage")
+	ErrVerificationFailed = errors.New("bacnet: write verification failed")
+	ErrNoInvokeID        = errors.New("bacnet: all invoke IDs in use")
 )
 
-// ErrorClass represents BACnet error classes
-type ErrorClass uint8
+// ErrorClass represents BACnet error classes. Values 0-63 are reserved to
+// ASHRAE; vendors may use values 64-65535.
+type ErrorClass uint16
 
 const (
 	ErrorClassDevice          ErrorClass = 0
@@ -63,152 +73,468 @@ func (e ErrorClass) String() string {
 	if name, ok := names[e]; ok {
 		return name
 	}
+	if e >= 64 {
+		return fmt.Sprintf("proprietary-error-class(%d)", e)
+	}
 	return fmt.Sprintf("error-class(%d)", e)
 }
 
-// ErrorCode represents BACnet error codes
-type ErrorCode uint8
+// ErrorCode represents BACnet error codes. Values 0-255 are reserved to
+// ASHRAE; vendors may use values 256-65535.
+type ErrorCode uint16
 
 const (
-	// Device errors
-	ErrorCodeOther                        ErrorCode = 0
-	ErrorCodeConfigurationInProgress      ErrorCode = 2
-	ErrorCodeDeviceBusy                   ErrorCode = 3
-
-	// Object errors
-	ErrorCodeDynamicCreationNotSupported  ErrorCode = 4
-	ErrorCodeNoObjectsOfSpecifiedType     ErrorCode = 17
-	ErrorCodeObjectDeletionNotPermitted   ErrorCode = 23
-	ErrorCodeObjectIdentifierAlreadyExists ErrorCode = 24
-	ErrorCodeUnknownObject                ErrorCode = 31
-
-	// Property errors
-	ErrorCodeCharacterSetNotSupported     ErrorCode = 41
-	ErrorCodeDatatypeNotSupported         ErrorCode = 47
-	ErrorCodeInconsistentParameters       ErrorCode = 7
-	ErrorCodeInvalidArrayIndex            ErrorCode = 42
-	ErrorCodeInvalidDataType              ErrorCode = 9
-	ErrorCodeNotCovProperty               ErrorCode = 44
-	ErrorCodeOptionalFunctionalityNotSupported ErrorCode = 45
-	ErrorCodePropertyIsNotAList           ErrorCode = 22
-	ErrorCodePropertyIsNotAnArray         ErrorCode = 50
-	ErrorCodeReadAccessDenied             ErrorCode = 27
-	ErrorCodeUnknownProperty              ErrorCode = 32
-	ErrorCodeValueOutOfRange              ErrorCode = 37
-	ErrorCodeWriteAccessDenied            ErrorCode = 40
-
-	// Resources errors
-	ErrorCodeNoSpaceForObject             ErrorCode = 18
-	ErrorCodeNoSpaceToAddListElement      ErrorCode = 19
-	ErrorCodeNoSpaceToWriteProperty       ErrorCode = 20
-
-	// Security errors
-	ErrorCodeAuthenticationFailed         ErrorCode = 1
-	ErrorCodeIncompatibleSecurityLevels   ErrorCode = 6
-	ErrorCodeInvalidOperatorName          ErrorCode = 12
-	ErrorCodeKeyGenerationError           ErrorCode = 15
-	ErrorCodePasswordFailure              ErrorCode = 26
-	ErrorCodeSecurityNotSupported         ErrorCode = 28
-
-	// Services errors
-	ErrorCodeCovSubscriptionFailed        ErrorCode = 43
-	ErrorCodeDuplicateName                ErrorCode = 48
-	ErrorCodeDuplicateObjectId            ErrorCode = 49
-	ErrorCodeFileAccessDenied             ErrorCode = 5
-	ErrorCodeInconsistentSelectionCriterion ErrorCode = 8
-	ErrorCodeInvalidConfigurationData     ErrorCode = 46
-	ErrorCodeInvalidFileAccessMethod      ErrorCode = 10
-	ErrorCodeInvalidFileStartPosition     ErrorCode = 11
-	ErrorCodeInvalidParameterDataType     ErrorCode = 13
-	ErrorCodeInvalidTimeStamp             ErrorCode = 14
-	ErrorCodeMissingRequiredParameter     ErrorCode = 16
-	ErrorCodeNoAlarmsOfSpecifiedType      ErrorCode = 51
-	ErrorCodeNotConfiguredForTriggeredLogging ErrorCode = 21
-	ErrorCodeServiceRequestDenied         ErrorCode = 29
-	ErrorCodeUnknownSubscription          ErrorCode = 33
-	ErrorCodeUnknownVtClass               ErrorCode = 34
-	ErrorCodeUnknownVtSession             ErrorCode = 35
-
-	// Communication errors
-	ErrorCodeAbortBufferOverflow          ErrorCode = 51
-	ErrorCodeAbortInvalidApduInThisState  ErrorCode = 52
+	ErrorCodeOther                              ErrorCode = 0
+	ErrorCodeAuthenticationFailed               ErrorCode = 1
+	ErrorCodeConfigurationInProgress            ErrorCode = 2
+	ErrorCodeDeviceBusy                         ErrorCode = 3
+	ErrorCodeDynamicCreationNotSupported        ErrorCode = 4
+	ErrorCodeFileAccessDenied                   ErrorCode = 5
+	ErrorCodeIncompatibleSecurityLevels         ErrorCode = 6
+	ErrorCodeInconsistentParameters             ErrorCode = 7
+	ErrorCodeInconsistentSelectionCriterion     ErrorCode = 8
+	ErrorCodeInvalidDataType                    ErrorCode = 9
+	ErrorCodeInvalidFileAccessMethod            ErrorCode = 10
+	ErrorCodeInvalidFileStartPosition           ErrorCode = 11
+	ErrorCodeInvalidOperatorName                ErrorCode = 12
+	ErrorCodeInvalidParameterDataType           ErrorCode = 13
+	ErrorCodeInvalidTimeStamp                   ErrorCode = 14
+	ErrorCodeKeyGenerationError                 ErrorCode = 15
+	ErrorCodeMissingRequiredParameter           ErrorCode = 16
+	ErrorCodeNoObjectsOfSpecifiedType           ErrorCode = 17
+	ErrorCodeNoSpaceForObject                   ErrorCode = 18
+	ErrorCodeNoSpaceToAddListElement            ErrorCode = 19
+	ErrorCodeNoSpaceToWriteProperty             ErrorCode = 20
+	ErrorCodeNoVtSessionsAvailable              ErrorCode = 21
+	ErrorCodePropertyIsNotAList                 ErrorCode = 22
+	ErrorCodeObjectDeletionNotPermitted         ErrorCode = 23
+	ErrorCodeObjectIdentifierAlreadyExists      ErrorCode = 24
+	ErrorCodeOperationalProblem                 ErrorCode = 25
+	ErrorCodePasswordFailure                    ErrorCode = 26
+	ErrorCodeReadAccessDenied                   ErrorCode = 27
+	ErrorCodeSecurityNotSupported               ErrorCode = 28
+	ErrorCodeServiceRequestDenied               ErrorCode = 29
+	ErrorCodeTimeout                            ErrorCode = 30
+	ErrorCodeUnknownObject                      ErrorCode = 31
+	ErrorCodeUnknownProperty                    ErrorCode = 32
+	ErrorCodeUnknownVtClass                     ErrorCode = 34
+	ErrorCodeUnknownVtSession                   ErrorCode = 35
+	ErrorCodeUnsupportedObjectType              ErrorCode = 36
+	ErrorCodeValueOutOfRange                    ErrorCode = 37
+	ErrorCodeVtSessionAlreadyClosed             ErrorCode = 38
+	ErrorCodeVtSessionTerminationFailure        ErrorCode = 39
+	ErrorCodeWriteAccessDenied                  ErrorCode = 40
+	ErrorCodeCharacterSetNotSupported           ErrorCode = 41
+	ErrorCodeInvalidArrayIndex                  ErrorCode = 42
+	ErrorCodeCovSubscriptionFailed              ErrorCode = 43
+	ErrorCodeNotCovProperty                     ErrorCode = 44
+	ErrorCodeOptionalFunctionalityNotSupported  ErrorCode = 45
+	ErrorCodeInvalidConfigurationData           ErrorCode = 46
+	ErrorCodeDatatypeNotSupported               ErrorCode = 47
+	ErrorCodeDuplicateName                      ErrorCode = 48
+	ErrorCodeDuplicateObjectId                  ErrorCode = 49
+	ErrorCodePropertyIsNotAnArray               ErrorCode = 50
+	ErrorCodeAbortBufferOverflow                ErrorCode = 51
+	ErrorCodeAbortInvalidApduInThisState        ErrorCode = 52
 	ErrorCodeAbortPreemptedByHigherPriorityTask ErrorCode = 53
-	ErrorCodeAbortSegmentationNotSupported ErrorCode = 54
-	ErrorCodeAbortProprietary             ErrorCode = 55
-	ErrorCodeAbortOther                   ErrorCode = 56
-	ErrorCodeInvalidTag                   ErrorCode = 57
-	ErrorCodeNetworkDown                  ErrorCode = 58
-	ErrorCodeRejectBufferOverflow         ErrorCode = 59
-	ErrorCodeRejectInconsistentParameters ErrorCode = 60
-	ErrorCodeRejectInvalidParameterDataType ErrorCode = 61
-	ErrorCodeRejectInvalidTag             ErrorCode = 62
-	ErrorCodeRejectMissingRequiredParameter ErrorCode = 63
-	ErrorCodeRejectParameterOutOfRange    ErrorCode = 64
-	ErrorCodeRejectTooManyArguments       ErrorCode = 65
-	ErrorCodeRejectUndefinedEnumeration   ErrorCode = 66
-	ErrorCodeRejectUnrecognizedService    ErrorCode = 67
-	ErrorCodeRejectProprietary            ErrorCode = 68
-	ErrorCodeRejectOther                  ErrorCode = 69
-	ErrorCodeUnknownDevice                ErrorCode = 70
-	ErrorCodeUnknownRoute                 ErrorCode = 71
-	ErrorCodeValueTooLong                 ErrorCode = 72
-	ErrorCodeAbortApduTooLong             ErrorCode = 73
-	ErrorCodeAbortApplicationExceededReplyTime ErrorCode = 74
-	ErrorCodeAbortOutOfResources          ErrorCode = 75
-	ErrorCodeAbortTsmTimeout              ErrorCode = 76
-	ErrorCodeAbortWindowSizeOutOfRange    ErrorCode = 77
-	ErrorCodeListItemNotNumbered          ErrorCode = 123
+	ErrorCodeAbortSegmentationNotSupported      ErrorCode = 54
+	ErrorCodeAbortProprietary                   ErrorCode = 55
+	ErrorCodeAbortOther                         ErrorCode = 56
+	ErrorCodeInvalidTag                         ErrorCode = 57
+	ErrorCodeNetworkDown                        ErrorCode = 58
+	ErrorCodeRejectBufferOverflow               ErrorCode = 59
+	ErrorCodeRejectInconsistentParameters       ErrorCode = 60
+	ErrorCodeRejectInvalidParameterDataType     ErrorCode = 61
+	ErrorCodeRejectInvalidTag                   ErrorCode = 62
+	ErrorCodeRejectMissingRequiredParameter     ErrorCode = 63
+	ErrorCodeRejectParameterOutOfRange          ErrorCode = 64
+	ErrorCodeRejectTooManyArguments             ErrorCode = 65
+	ErrorCodeRejectUndefinedEnumeration         ErrorCode = 66
+	ErrorCodeRejectUnrecognizedService          ErrorCode = 67
+	ErrorCodeRejectProprietary                  ErrorCode = 68
+	ErrorCodeRejectOther                        ErrorCode = 69
+	ErrorCodeUnknownDevice                      ErrorCode = 70
+	ErrorCodeUnknownRoute                       ErrorCode = 71
+	ErrorCodeValueNotInitialized                ErrorCode = 72
+	ErrorCodeInvalidEventState                  ErrorCode = 73
+	ErrorCodeNoAlarmConfigured                  ErrorCode = 74
+	ErrorCodeLogBufferFull                      ErrorCode = 75
+	ErrorCodeLoggedValuePurged                  ErrorCode = 76
+	ErrorCodeNoPropertySpecified                ErrorCode = 77
+	ErrorCodeNotConfiguredForTriggeredLogging   ErrorCode = 78
+	ErrorCodeUnknownSubscription                ErrorCode = 79
+	ErrorCodeParameterOutOfRange                ErrorCode = 80
+	ErrorCodeListElementNotFound                ErrorCode = 81
+	ErrorCodeBusy                               ErrorCode = 82
+	ErrorCodeCommunicationDisabled              ErrorCode = 83
+	ErrorCodeSuccess                            ErrorCode = 84
+	ErrorCodeAccessDenied                       ErrorCode = 85
+	ErrorCodeBadDestinationAddress              ErrorCode = 86
+	ErrorCodeBadDestinationDeviceId             ErrorCode = 87
+	ErrorCodeBadSignature                       ErrorCode = 88
+	ErrorCodeBadSourceAddress                   ErrorCode = 89
+	ErrorCodeBadTimestamp                       ErrorCode = 90
+	ErrorCodeCannotUseKey                       ErrorCode = 91
+	ErrorCodeCannotVerifyMessageId              ErrorCode = 92
+	ErrorCodeCorrectKeyRevision                 ErrorCode = 93
+	ErrorCodeDestinationDeviceIdRequired        ErrorCode = 94
+	ErrorCodeDuplicateMessage                   ErrorCode = 95
+	ErrorCodeEncryptionNotConfigured            ErrorCode = 96
+	ErrorCodeEncryptionRequired                 ErrorCode = 97
+	ErrorCodeIncorrectKey                       ErrorCode = 98
+	ErrorCodeInvalidKeyData                     ErrorCode = 99
+	ErrorCodeKeyUpdateInProgress                ErrorCode = 100
+	ErrorCodeMalformedMessage                   ErrorCode = 101
+	ErrorCodeNotKeyServer                       ErrorCode = 102
+	ErrorCodeSecurityNotConfigured              ErrorCode = 103
+	ErrorCodeSourceSecurityRequired             ErrorCode = 104
+	ErrorCodeTooManyKeys                        ErrorCode = 105
+	ErrorCodeUnknownAuthenticationType          ErrorCode = 106
+	ErrorCodeUnknownKey                         ErrorCode = 107
+	ErrorCodeUnknownKeyRevision                 ErrorCode = 108
+	ErrorCodeUnknownSourceMessage               ErrorCode = 109
+	ErrorCodeNotRouterToDnet                    ErrorCode = 110
+	ErrorCodeRouterBusy                         ErrorCode = 111
+	ErrorCodeUnknownNetworkMessage              ErrorCode = 112
+	ErrorCodeMessageTooLong                     ErrorCode = 113
+	ErrorCodeSecurityError                      ErrorCode = 114
+	ErrorCodeAddressingError                    ErrorCode = 115
+	ErrorCodeWriteBdtFailed                     ErrorCode = 116
+	ErrorCodeReadBdtFailed                      ErrorCode = 117
+	ErrorCodeRegisterForeignDeviceFailed        ErrorCode = 118
+	ErrorCodeReadFdtFailed                      ErrorCode = 119
+	ErrorCodeDeleteFdtEntryFailed               ErrorCode = 120
+	ErrorCodeDistributeBroadcastFailed          ErrorCode = 121
+	ErrorCodeUnknownFileSize                    ErrorCode = 122
+	ErrorCodeAbortApduTooLong                   ErrorCode = 123
+	ErrorCodeAbortApplicationExceededReplyTime  ErrorCode = 124
+	ErrorCodeAbortOutOfResources                ErrorCode = 125
+	ErrorCodeAbortTsmTimeout                    ErrorCode = 126
+	ErrorCodeAbortWindowSizeOutOfRange          ErrorCode = 127
+	ErrorCodeFileFull                           ErrorCode = 128
+	ErrorCodeInconsistentConfiguration          ErrorCode = 129
+	ErrorCodeInconsistentObjectType             ErrorCode = 130
+	ErrorCodeInternalError                      ErrorCode = 131
+	ErrorCodeNotConfigured                      ErrorCode = 132
+	ErrorCodeOutOfMemory                        ErrorCode = 133
+	ErrorCodeValueTooLong                       ErrorCode = 134
+	ErrorCodeAbortInsufficientSecurity          ErrorCode = 135
+	ErrorCodeAbortSecurityError                 ErrorCode = 136
+	ErrorCodeDuplicateEntry                     ErrorCode = 137
+	ErrorCodeInvalidValueInThisState            ErrorCode = 138
+	ErrorCodeInvalidOperationInThisState        ErrorCode = 139
+	ErrorCodeListItemNotNumbered                ErrorCode = 140
+	ErrorCodeListItemNotTimestamped             ErrorCode = 141
+	ErrorCodeInvalidDataEncoding                ErrorCode = 142
+	ErrorCodeBvlcFunctionUnknown                ErrorCode = 143
+	ErrorCodeBvlcProprietaryFunctionUnknown     ErrorCode = 144
+	ErrorCodeHeaderEncodingError                ErrorCode = 145
+	ErrorCodeHeaderNotUnderstood                ErrorCode = 146
+	ErrorCodeMessageIncomplete                  ErrorCode = 147
+	ErrorCodeNotABacnetScHub                    ErrorCode = 148
+	ErrorCodePayloadExpected                    ErrorCode = 149
+	ErrorCodeUnexpectedData                     ErrorCode = 150
+	ErrorCodeNodeDuplicateVmac                  ErrorCode = 151
+	ErrorCodeHttpUnexpectedResponseCode         ErrorCode = 152
+	ErrorCodeHttpNoUpgrade                      ErrorCode = 153
+	ErrorCodeHttpResourceNotLocal               ErrorCode = 154
+	ErrorCodeHttpProxyAuthenticationFailed      ErrorCode = 155
+	ErrorCodeHttpResponseTimeout                ErrorCode = 156
+	ErrorCodeHttpResponseSyntaxError            ErrorCode = 157
+	ErrorCodeHttpResponseValueError             ErrorCode = 158
+	ErrorCodeHttpResponseMissingHeader          ErrorCode = 159
+	ErrorCodeHttpWebsocketHeaderError           ErrorCode = 160
+	ErrorCodeHttpUpgradeRequired                ErrorCode = 161
+	ErrorCodeHttpUpgradeError                   ErrorCode = 162
+	ErrorCodeHttpTemporaryUnavailable           ErrorCode = 163
+	ErrorCodeHttpNotAServer                     ErrorCode = 164
+	ErrorCodeHttpError                          ErrorCode = 165
+	ErrorCodeWebsocketSchemeNotSupported        ErrorCode = 166
+	ErrorCodeWebsocketUnknownControlMessage     ErrorCode = 167
+	ErrorCodeWebsocketCloseError                ErrorCode = 168
+	ErrorCodeWebsocketClosedByPeer              ErrorCode = 169
+	ErrorCodeWebsocketEndpointLeaves            ErrorCode = 170
+	ErrorCodeWebsocketProtocolError             ErrorCode = 171
+	ErrorCodeWebsocketDataNotAccepted           ErrorCode = 172
+	ErrorCodeWebsocketClosedAbnormally          ErrorCode = 173
+	ErrorCodeWebsocketDataInconsistent          ErrorCode = 174
+	ErrorCodeWebsocketDataAgainstPolicy         ErrorCode = 175
+	ErrorCodeWebsocketFrameTooLong              ErrorCode = 176
+	ErrorCodeWebsocketExtensionMissing          ErrorCode = 177
+	ErrorCodeWebsocketRequestUnavailable        ErrorCode = 178
+	ErrorCodeWebsocketError                     ErrorCode = 179
+	ErrorCodeTlsClientCertificateError          ErrorCode = 180
+	ErrorCodeTlsServerCertificateError          ErrorCode = 181
+	ErrorCodeTlsClientAuthenticationFailed      ErrorCode = 182
+	ErrorCodeTlsServerAuthenticationFailed      ErrorCode = 183
+	ErrorCodeTlsClientCertificateExpired        ErrorCode = 184
+	ErrorCodeTlsServerCertificateExpired        ErrorCode = 185
+	ErrorCodeTlsClientCertificateRevoked        ErrorCode = 186
+	ErrorCodeTlsServerCertificateRevoked        ErrorCode = 187
+	ErrorCodeTlsError                           ErrorCode = 188
+	ErrorCodeDnsUnavailable                     ErrorCode = 189
+	ErrorCodeDnsNameResolutionFailed            ErrorCode = 190
+	ErrorCodeDnsResolverFailure                 ErrorCode = 191
+	ErrorCodeDnsError                           ErrorCode = 192
+	ErrorCodeTcpConnectTimeout                  ErrorCode = 193
+	ErrorCodeTcpConnectionRefused               ErrorCode = 194
+	ErrorCodeTcpClosedByLocal                   ErrorCode = 195
+	ErrorCodeTcpClosedOther                     ErrorCode = 196
+	ErrorCodeTcpError                           ErrorCode = 197
+	ErrorCodeIpAddressNotReachable              ErrorCode = 198
+	ErrorCodeIpError                            ErrorCode = 199
+	ErrorCodeCertificateExpired                 ErrorCode = 200
+	ErrorCodeCertificateInvalid                 ErrorCode = 201
+	ErrorCodeCertificateMalformed               ErrorCode = 202
+	ErrorCodeCertificateRevoked                 ErrorCode = 203
+	ErrorCodeUnknownSecurityKey                 ErrorCode = 204
+	ErrorCodeReferencedPortInError              ErrorCode = 205
+
+	// Deprecated: ErrorCodeNoAlarmsOfSpecifiedType is not a standard error
+	// code; use ErrorCodeNoAlarmConfigured
+	ErrorCodeNoAlarmsOfSpecifiedType = ErrorCodeNoAlarmConfigured
 )
 
+var errorCodeNames = map[ErrorCode]string{
+	ErrorCodeOther:                              "other",
+	ErrorCodeAuthenticationFailed:               "authentication-failed",
+	ErrorCodeConfigurationInProgress:            "configuration-in-progress",
+	ErrorCodeDeviceBusy:                         "device-busy",
+	ErrorCodeDynamicCreationNotSupported:        "dynamic-creation-not-supported",
+	ErrorCodeFileAccessDenied:                   "file-access-denied",
+	ErrorCodeIncompatibleSecurityLevels:         "incompatible-security-levels",
+	ErrorCodeInconsistentParameters:             "inconsistent-parameters",
+	ErrorCodeInconsistentSelectionCriterion:     "inconsistent-selection-criterion",
+	ErrorCodeInvalidDataType:                    "invalid-data-type",
+	ErrorCodeInvalidFileAccessMethod:            "invalid-file-access-method",
+	ErrorCodeInvalidFileStartPosition:           "invalid-file-start-position",
+	ErrorCodeInvalidOperatorName:                "invalid-operator-name",
+	ErrorCodeInvalidParameterDataType:           "invalid-parameter-data-type",
+	ErrorCodeInvalidTimeStamp:                   "invalid-time-stamp",
+	ErrorCodeKeyGenerationError:                 "key-generation-error",
+	ErrorCodeMissingRequiredParameter:           "missing-required-parameter",
+	ErrorCodeNoObjectsOfSpecifiedType:           "no-objects-of-specified-type",
+	ErrorCodeNoSpaceForObject:                   "no-space-for-object",
+	ErrorCodeNoSpaceToAddListElement:            "no-space-to-add-list-element",
+	ErrorCodeNoSpaceToWriteProperty:             "no-space-to-write-property",
+	ErrorCodeNoVtSessionsAvailable:              "no-vt-sessions-available",
+	ErrorCodePropertyIsNotAList:                 "property-is-not-a-list",
+	ErrorCodeObjectDeletionNotPermitted:         "object-deletion-not-permitted",
+	ErrorCodeObjectIdentifierAlreadyExists:      "object-identifier-already-exists",
+	ErrorCodeOperationalProblem:                 "operational-problem",
+	ErrorCodePasswordFailure:                    "password-failure",
+	ErrorCodeReadAccessDenied:                   "read-access-denied",
+	ErrorCodeSecurityNotSupported:               "security-not-supported",
+	ErrorCodeServiceRequestDenied:               "service-request-denied",
+	ErrorCodeTimeout:                            "timeout",
+	ErrorCodeUnknownObject:                      "unknown-object",
+	ErrorCodeUnknownProperty:                    "unknown-property",
+	ErrorCodeUnknownVtClass:                     "unknown-vt-class",
+	ErrorCodeUnknownVtSession:                   "unknown-vt-session",
+	ErrorCodeUnsupportedObjectType:              "unsupported-object-type",
+	ErrorCodeValueOutOfRange:                    "value-out-of-range",
+	ErrorCodeVtSessionAlreadyClosed:             "vt-session-already-closed",
+	ErrorCodeVtSessionTerminationFailure:        "vt-session-termination-failure",
+	ErrorCodeWriteAccessDenied:                  "write-access-denied",
+	ErrorCodeCharacterSetNotSupported:           "character-set-not-supported",
+	ErrorCodeInvalidArrayIndex:                  "invalid-array-index",
+	ErrorCodeCovSubscriptionFailed:              "cov-subscription-failed",
+	ErrorCodeNotCovProperty:                     "not-cov-property",
+	ErrorCodeOptionalFunctionalityNotSupported:  "optional-functionality-not-supported",
+	ErrorCodeInvalidConfigurationData:           "invalid-configuration-data",
+	ErrorCodeDatatypeNotSupported:               "datatype-not-supported",
+	ErrorCodeDuplicateName:                      "duplicate-name",
+	ErrorCodeDuplicateObjectId:                  "duplicate-object-id",
+	ErrorCodePropertyIsNotAnArray:               "property-is-not-an-array",
+	ErrorCodeAbortBufferOverflow:                "abort-buffer-overflow",
+	ErrorCodeAbortInvalidApduInThisState:        "abort-invalid-apdu-in-this-state",
+	ErrorCodeAbortPreemptedByHigherPriorityTask: "abort-preempted-by-higher-priority-task",
+	ErrorCodeAbortSegmentationNotSupported:      "abort-segmentation-not-supported",
+	ErrorCodeAbortProprietary:                   "abort-proprietary",
+	ErrorCodeAbortOther:                         "abort-other",
+	ErrorCodeInvalidTag:                         "invalid-tag",
+	ErrorCodeNetworkDown:                        "network-down",
+	ErrorCodeRejectBufferOverflow:               "reject-buffer-overflow",
+	ErrorCodeRejectInconsistentParameters:       "reject-inconsistent-parameters",
+	ErrorCodeRejectInvalidParameterDataType:     "reject-invalid-parameter-data-type",
+	ErrorCodeRejectInvalidTag:                   "reject-invalid-tag",
+	ErrorCodeRejectMissingRequiredParameter:     "reject-missing-required-parameter",
+	ErrorCodeRejectParameterOutOfRange:          "reject-parameter-out-of-range",
+	ErrorCodeRejectTooManyArguments:             "reject-too-many-arguments",
+	ErrorCodeRejectUndefinedEnumeration:         "reject-undefined-enumeration",
+	ErrorCodeRejectUnrecognizedService:          "reject-unrecognized-service",
+	ErrorCodeRejectProprietary:                  "reject-proprietary",
+	ErrorCodeRejectOther:                        "reject-other",
+	ErrorCodeUnknownDevice:                      "unknown-device",
+	ErrorCodeUnknownRoute:                       "unknown-route",
+	ErrorCodeValueNotInitialized:                "value-not-initialized",
+	ErrorCodeInvalidEventState:                  "invalid-event-state",
+	ErrorCodeNoAlarmConfigured:                  "no-alarm-configured",
+	ErrorCodeLogBufferFull:                      "log-buffer-full",
+	ErrorCodeLoggedValuePurged:                  "logged-value-purged",
+	ErrorCodeNoPropertySpecified:                "no-property-specified",
+	ErrorCodeNotConfiguredForTriggeredLogging:   "not-configured-for-triggered-logging",
+	ErrorCodeUnknownSubscription:                "unknown-subscription",
+	ErrorCodeParameterOutOfRange:                "parameter-out-of-range",
+	ErrorCodeListElementNotFound:                "list-element-not-found",
+	ErrorCodeBusy:                               "busy",
+	ErrorCodeCommunicationDisabled:              "communication-disabled",
+	ErrorCodeSuccess:                            "success",
+	ErrorCodeAccessDenied:                       "access-denied",
+	ErrorCodeBadDestinationAddress:              "bad-destination-address",
+	ErrorCodeBadDestinationDeviceId:             "bad-destination-device-id",
+	ErrorCodeBadSignature:                       "bad-signature",
+	ErrorCodeBadSourceAddress:                   "bad-source-address",
+	ErrorCodeBadTimestamp:                       "bad-timestamp",
+	ErrorCodeCannotUseKey:                       "cannot-use-key",
+	ErrorCodeCannotVerifyMessageId:              "cannot-verify-message-id",
+	ErrorCodeCorrectKeyRevision:                 "correct-key-revision",
+	ErrorCodeDestinationDeviceIdRequired:        "destination-device-id-required",
+	ErrorCodeDuplicateMessage:                   "duplicate-message",
+	ErrorCodeEncryptionNotConfigured:            "encryption-not-configured",
+	ErrorCodeEncryptionRequired:                 "encryption-required",
+	ErrorCodeIncorrectKey:                       "incorrect-key",
+	ErrorCodeInvalidKeyData:                     "invalid-key-data",
+	ErrorCodeKeyUpdateInProgress:                "key-update-in-progress",
+	ErrorCodeMalformedMessage:                   "malformed-message",
+	ErrorCodeNotKeyServer:                       "not-key-server",
+	ErrorCodeSecurityNotConfigured:              "security-not-configured",
+	ErrorCodeSourceSecurityRequired:             "source-security-required",
+	ErrorCodeTooManyKeys:                        "too-many-keys",
+	ErrorCodeUnknownAuthenticationType:          "unknown-authentication-type",
+	ErrorCodeUnknownKey:                         "unknown-key",
+	ErrorCodeUnknownKeyRevision:                 "unknown-key-revision",
+	ErrorCodeUnknownSourceMessage:               "unknown-source-message",
+	ErrorCodeNotRouterToDnet:                    "not-router-to-dnet",
+	ErrorCodeRouterBusy:                         "router-busy",
+	ErrorCodeUnknownNetworkMessage:              "unknown-network-message",
+	ErrorCodeMessageTooLong:                     "message-too-long",
+	ErrorCodeSecurityError:                      "security-error",
+	ErrorCodeAddressingError:                    "addressing-error",
+	ErrorCodeWriteBdtFailed:                     "write-bdt-failed",
+	ErrorCodeReadBdtFailed:                      "read-bdt-failed",
+	ErrorCodeRegisterForeignDeviceFailed:        "register-foreign-device-failed",
+	ErrorCodeReadFdtFailed:                      "read-fdt-failed",
+	ErrorCodeDeleteFdtEntryFailed:               "delete-fdt-entry-failed",
+	ErrorCodeDistributeBroadcastFailed:          "distribute-broadcast-failed",
+	ErrorCodeUnknownFileSize:                    "unknown-file-size",
+	ErrorCodeAbortApduTooLong:                   "abort-apdu-too-long",
+	ErrorCodeAbortApplicationExceededReplyTime:  "abort-application-exceeded-reply-time",
+	ErrorCodeAbortOutOfResources:                "abort-out-of-resources",
+	ErrorCodeAbortTsmTimeout:                    "abort-tsm-timeout",
+	ErrorCodeAbortWindowSizeOutOfRange:          "abort-window-size-out-of-range",
+	ErrorCodeFileFull:                           "file-full",
+	ErrorCodeInconsistentConfiguration:          "inconsistent-configuration",
+	ErrorCodeInconsistentObjectType:             "inconsistent-object-type",
+	ErrorCodeInternalError:                      "internal-error",
+	ErrorCodeNotConfigured:                      "not-configured",
+	ErrorCodeOutOfMemory:                        "out-of-memory",
+	ErrorCodeValueTooLong:                       "value-too-long",
+	ErrorCodeAbortInsufficientSecurity:          "abort-insufficient-security",
+	ErrorCodeAbortSecurityError:                 "abort-security-error",
+	ErrorCodeDuplicateEntry:                     "duplicate-entry",
+	ErrorCodeInvalidValueInThisState:            "invalid-value-in-this-state",
+	ErrorCodeInvalidOperationInThisState:        "invalid-operation-in-this-state",
+	ErrorCodeListItemNotNumbered:                "list-item-not-numbered",
+	ErrorCodeListItemNotTimestamped:             "list-item-not-timestamped",
+	ErrorCodeInvalidDataEncoding:                "invalid-data-encoding",
+	ErrorCodeBvlcFunctionUnknown:                "bvlc-function-unknown",
+	ErrorCodeBvlcProprietaryFunctionUnknown:     "bvlc-proprietary-function-unknown",
+	ErrorCodeHeaderEncodingError:                "header-encoding-error",
+	ErrorCodeHeaderNotUnderstood:                "header-not-understood",
+	ErrorCodeMessageIncomplete:                  "message-incomplete",
+	ErrorCodeNotABacnetScHub:                    "not-a-bacnet-sc-hub",
+	ErrorCodePayloadExpected:                    "payload-expected",
+	ErrorCodeUnexpectedData:                     "unexpected-data",
+	ErrorCodeNodeDuplicateVmac:                  "node-duplicate-vmac",
+	ErrorCodeHttpUnexpectedResponseCode:         "http-unexpected-response-code",
+	ErrorCodeHttpNoUpgrade:                      "http-no-upgrade",
+	ErrorCodeHttpResourceNotLocal:               "http-resource-not-local",
+	ErrorCodeHttpProxyAuthenticationFailed:      "http-proxy-authentication-failed",
+	ErrorCodeHttpResponseTimeout:                "http-response-timeout",
+	ErrorCodeHttpResponseSyntaxError:            "http-response-syntax-error",
+	ErrorCodeHttpResponseValueError:             "http-response-value-error",
+	ErrorCodeHttpResponseMissingHeader:          "http-response-missing-header",
+	ErrorCodeHttpWebsocketHeaderError:           "http-websocket-header-error",
+	ErrorCodeHttpUpgradeRequired:                "http-upgrade-required",
+	ErrorCodeHttpUpgradeError:                   "http-upgrade-error",
+	ErrorCodeHttpTemporaryUnavailable:           "http-temporary-unavailable",
+	ErrorCodeHttpNotAServer:                     "http-not-a-server",
+	ErrorCodeHttpError:                          "http-error",
+	ErrorCodeWebsocketSchemeNotSupported:        "websocket-scheme-not-supported",
+	ErrorCodeWebsocketUnknownControlMessage:     "websocket-unknown-control-message",
+	ErrorCodeWebsocketCloseError:                "websocket-close-error",
+	ErrorCodeWebsocketClosedByPeer:              "websocket-closed-by-peer",
+	ErrorCodeWebsocketEndpointLeaves:            "websocket-endpoint-leaves",
+	ErrorCodeWebsocketProtocolError:             "websocket-protocol-error",
+	ErrorCodeWebsocketDataNotAccepted:           "websocket-data-not-accepted",
+	ErrorCodeWebsocketClosedAbnormally:          "websocket-closed-abnormally",
+	ErrorCodeWebsocketDataInconsistent:          "websocket-data-inconsistent",
+	ErrorCodeWebsocketDataAgainstPolicy:         "websocket-data-against-policy",
+	ErrorCodeWebsocketFrameTooLong:              "websocket-frame-too-long",
+	ErrorCodeWebsocketExtensionMissing:          "websocket-extension-missing",
+	ErrorCodeWebsocketRequestUnavailable:        "websocket-request-unavailable",
+	ErrorCodeWebsocketError:                     "websocket-error",
+	ErrorCodeTlsClientCertificateError:          "tls-client-certificate-error",
+	ErrorCodeTlsServerCertificateError:          "tls-server-certificate-error",
+	ErrorCodeTlsClientAuthenticationFailed:      "tls-client-authentication-failed",
+	ErrorCodeTlsServerAuthenticationFailed:      "tls-server-authentication-failed",
+	ErrorCodeTlsClientCertificateExpired:        "tls-client-certificate-expired",
+	ErrorCodeTlsServerCertificateExpired:        "tls-server-certificate-expired",
+	ErrorCodeTlsClientCertificateRevoked:        "tls-client-certificate-revoked",
+	ErrorCodeTlsServerCertificateRevoked:        "tls-server-certificate-revoked",
+	ErrorCodeTlsError:                           "tls-error",
+	ErrorCodeDnsUnavailable:                     "dns-unavailable",
+	ErrorCodeDnsNameResolutionFailed:            "dns-name-resolution-failed",
+	ErrorCodeDnsResolverFailure:                 "dns-resolver-failure",
+	ErrorCodeDnsError:                           "dns-error",
+	ErrorCodeTcpConnectTimeout:                  "tcp-connect-timeout",
+	ErrorCodeTcpConnectionRefused:               "tcp-connection-refused",
+	ErrorCodeTcpClosedByLocal:                   "tcp-closed-by-local",
+	ErrorCodeTcpClosedOther:                     "tcp-closed-other",
+	ErrorCodeTcpError:                           "tcp-error",
+	ErrorCodeIpAddressNotReachable:              "ip-address-not-reachable",
+	ErrorCodeIpError:                            "ip-error",
+	ErrorCodeCertificateExpired:                 "certificate-expired",
+	ErrorCodeCertificateInvalid:                 "certificate-invalid",
+	ErrorCodeCertificateMalformed:               "certificate-malformed",
+	ErrorCodeCertificateRevoked:                 "certificate-revoked",
+	ErrorCodeUnknownSecurityKey:                 "unknown-security-key",
+	ErrorCodeReferencedPortInError:              "referenced-port-in-error",
+}
+
 func (e ErrorCode) String() string {
-	names := map[ErrorCode]string{
-		ErrorCodeOther:                        "other",
-		ErrorCodeConfigurationInProgress:      "configuration-in-progress",
-		ErrorCodeDeviceBusy:                   "device-busy",
-		ErrorCodeDynamicCreationNotSupported:  "dynamic-creation-not-supported",
-		ErrorCodeNoObjectsOfSpecifiedType:     "no-objects-of-specified-type",
-		ErrorCodeObjectDeletionNotPermitted:   "object-deletion-not-permitted",
-		ErrorCodeObjectIdentifierAlreadyExists: "object-identifier-already-exists",
-		ErrorCodeUnknownObject:                "unknown-object",
-		ErrorCodeCharacterSetNotSupported:     "character-set-not-supported",
-		ErrorCodeDatatypeNotSupported:         "datatype-not-supported",
-		ErrorCodeInconsistentParameters:       "inconsistent-parameters",
-		ErrorCodeInvalidArrayIndex:            "invalid-array-index",
-		ErrorCodeInvalidDataType:              "invalid-data-type",
-		ErrorCodeNotCovProperty:               "not-cov-property",
-		ErrorCodeOptionalFunctionalityNotSupported: "optional-functionality-not-supported",
-		ErrorCodePropertyIsNotAList:           "property-is-not-a-list",
-		ErrorCodePropertyIsNotAnArray:         "property-is-not-an-array",
-		ErrorCodeReadAccessDenied:             "read-access-denied",
-		ErrorCodeUnknownProperty:              "unknown-property",
-		ErrorCodeValueOutOfRange:              "value-out-of-range",
-		ErrorCodeWriteAccessDenied:            "write-access-denied",
-		ErrorCodeNoSpaceForObject:             "no-space-for-object",
-		ErrorCodeNoSpaceToAddListElement:      "no-space-to-add-list-element",
-		ErrorCodeNoSpaceToWriteProperty:       "no-space-to-write-property",
-		ErrorCodeAuthenticationFailed:         "authentication-failed",
-		ErrorCodePasswordFailure:              "password-failure",
-		ErrorCodeSecurityNotSupported:         "security-not-supported",
-		ErrorCodeServiceRequestDenied:         "service-request-denied",
-		ErrorCodeUnknownDevice:                "unknown-device",
-		ErrorCodeUnknownRoute:                 "unknown-route",
-	}
-	if name, ok := names[e]; ok {
+	if name, ok := errorCodeNames[e]; ok {
 		return name
 	}
+	if e >= 256 {
+		return fmt.Sprintf("proprietary-error-code(%d)", e)
+	}
 	return fmt.Sprintf("error-code(%d)", e)
 }
 
-// BACnetError represents a BACnet protocol error
+// BACnetError represents a BACnet protocol error. Errors decoded from an
+// Error APDU also carry the confirmed service that failed.
 type BACnetError struct {
-	Class ErrorClass
-	Code  ErrorCode
+	Class   ErrorClass
+	Code    ErrorCode
+	Service ConfirmedServiceChoice
+
+	hasService bool
 }
 
 func (e *BACnetError) Error() string {
+	if e.hasService {
+		return fmt.Sprintf("bacnet error: service=%s, class=%s, code=%s", e.Service, e.Class, e.Code)
+	}
 	return fmt.Sprintf("bacnet error: class=%s, code=%s", e.Class, e.Code)
 }
 
+// HasService reports whether Service holds the service that failed
+func (e *BACnetError) HasService() bool {
+	return e.hasService
+}
+
 func (e *BACnetError) Is(target error) bool {
 	t, ok := target.(*BACnetError)
 	if !ok {
@@ -225,6 +551,16 @@ func NewBACnetError(class ErrorClass, code ErrorCode) *BACnetError {
 	}
 }
 
+// newServiceError creates the error reported by an Error APDU for service
+func newServiceError(service ConfirmedServiceChoice, class ErrorClass, code ErrorCode) *BACnetError {
+	return &BACnetError{
+		Class:      class,
+		Code:       code,
+		Service:    service,
+		hasService: true,
+	}
+}
+
 // RejectReason represents BACnet reject reasons
 type RejectReason uint8
 
@@ -267,7 +603,7 @@ type RejectError struct {
 }
 
 func (e *RejectError) Error() string {
-	return fmt.Sprintf("bacnet reject: invoke-id=%d, reason=%s", e.InvokeID, e.Reason)
+	return fmt.Sprintf("bacnet reject: invoke-id=%d, origin=server, reason=%s", e.InvokeID, e.Reason)
 }
 
 // AbortReason represents BACnet abort reasons
@@ -317,16 +653,95 @@ type AbortError struct {
 }
 
 func (e *AbortError) Error() string {
-	origin := "client"
-	if e.Server {
-		origin = "server"
+	return fmt.Sprintf("bacnet abort: invoke-id=%d, origin=%s, reason=%s", e.InvokeID, abortOrigin(e.Server), e.Reason)
+}
+
+// abortOrigin names the sender of an Abort from its SRV bit
+func abortOrigin(server bool) string {
+	if server {
+		return "server"
 	}
-	return fmt.Sprintf("bacnet abort: invoke-id=%d, origin=%s, reason=%s", e.InvokeID, origin, e.Reason)
+	return "client"
+}
+
+// BVLCResultCode is the result code of a BVLC-Result message (Annex J.2.1)
+type BVLCResultCode uint16
+
+const (
+	BVLCResultSuccessfulCompletion               BVLCResultCode = 0x0000
+	BVLCResultWriteBroadcastDistributionTableNAK BVLCResultCode = 0x0010
+	BVLCResultReadBroadcastDistributionTableNAK  BVLCResultCode = 0x0020
+	BVLCResultRegisterForeignDeviceNAK           BVLCResultCode = 0x0030
+	BVLCResultReadForeignDeviceTableNAK          BVLCResultCode = 0x0040
+	BVLCResultDeleteForeignDeviceTableEntryNAK   BVLCResultCode = 0x0050
+	BVLCResultDistributeBroadcastToNetworkNAK    BVLCResultCode = 0x0060
+)
+
+func (r BVLCResultCode) String() string {
+	names := map[BVLCResultCode]string{
+		BVLCResultSuccessfulCompletion:               "successful-completion",
+		BVLCResultWriteBroadcastDistributionTableNAK: "write-broadcast-distribution-table-nak",
+		BVLCResultReadBroadcastDistributionTableNAK:  "read-broadcast-distribution-table-nak",
+		BVLCResultRegisterForeignDeviceNAK:           "register-foreign-device-nak",
+		BVLCResultReadForeignDeviceTableNAK:          "read-foreign-device-table-nak",
+		BVLCResultDeleteForeignDeviceTableEntryNAK:   "delete-foreign-device-table-entry-nak",
+		BVLCResultDistributeBroadcastToNetworkNAK:    "distribute-broadcast-to-network-nak",
+	}
+	if name, ok := names[r]; ok {
+		return name
+	}
+	return fmt.Sprintf("bvlc-result(0x%04x)", uint16(r))
+}
+
+// BVLCResultError is a BVLC-Result NAK from a BBMD
+type BVLCResultError struct {
+	Code BVLCResultCode
+}
+
+func (e *BVLCResultError) Error() string {
+	return fmt.Sprintf("bacnet/ip: %s", e.Code)
+}
+
+// ServiceMismatchError is returned when the response to a confirmed request
+// is for another service, as when a late reply is matched to a reused invoke
+// ID
+type ServiceMismatchError struct {
+	InvokeID uint8
+	Expected ConfirmedServiceChoice
+	Received ConfirmedServiceChoice
+}
+
+func (e *ServiceMismatchError) Error() string {
+	return fmt.Sprintf("bacnet: response service mismatch: invoke-id=%d, expected=%s, received=%s",
+		e.InvokeID, e.Expected, e.Received)
+}
+
+func (e *ServiceMismatchError) Unwrap() error {
+	return ErrInvalidResponse
+}
+
+// VerificationError is returned by a verified write when the property read
+// back differs from the value written
+type VerificationError struct {
+	ObjectID   ObjectIdentifier
+	PropertyID PropertyIdentifier
+	Written    interface{}
+	ReadBack   interface{}
+}
+
+func (e *VerificationError) Error() string {
+	return fmt.Sprintf("bacnet: write verification failed: %s %s: wrote %v, read back %v",
+		e.ObjectID, e.PropertyID, e.Written, e.ReadBack)
+}
+
+func (e *VerificationError) Unwrap() error {
+	return ErrVerificationFailed
 }
 
-// IsTimeout returns true if the error is a timeout error
+// IsTimeout returns true if the error is a timeout error, including the
+// deadline of a context ending
 func IsTimeout(err error) bool {
-	return errors.Is(err, ErrTimeout)
+	return errors.Is(err, ErrTimeout) || errors.Is(err, context.DeadlineExceeded)
 }
 
 // IsDeviceNotFound returns true if the error indicates device not found
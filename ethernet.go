@@ -0,0 +1,291 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/edgeo-scada/bacnet/internal/ethernet"
+)
+
+// BACnet Ethernet framing (Clause 7)
+const (
+	// EthernetMaxAPDULength is the maximum APDU carried by ISO 8802-3
+	EthernetMaxAPDULength = 1476
+
+	// ethLSAP is the LLC service access point of BACnet
+	ethLSAP = 0x82
+
+	// ethLLCUI is the LLC control field of unnumbered information frames
+	ethLLCUI = 0x03
+
+	ethHeaderLen = 14
+	ethMinFrame  = 60
+	ethMaxNPDU   = 1497
+)
+
+// EthernetAddr is an Ethernet MAC address
+type EthernetAddr [6]byte
+
+// EthernetBroadcast is the Ethernet broadcast address
+var EthernetBroadcast = EthernetAddr{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+
+// Network implements net.Addr
+func (a EthernetAddr) Network() string { return "ethernet" }
+
+// String returns the address as colon-separated hex
+func (a EthernetAddr) String() string { return net.HardwareAddr(a[:]).String() }
+
+// encodeEthernetFrame encodes an NPDU as an 802.3 frame with an LLC header,
+// padded to the minimum frame size
+func encodeEthernetFrame(dest, src EthernetAddr, npdu []byte) []byte {
+	length := 3 + len(npdu)
+	buf := make([]byte, 0, max(ethHeaderLen+length, ethMinFrame))
+	buf = append(buf, dest[:]...)
+	buf = append(buf, src[:]...)
+	buf = append(buf, byte(length>>8), byte(length))
+	buf = append(buf, ethLSAP, ethLSAP, ethLLCUI)
+	buf = append(buf, npdu...)
+	for len(buf) < ethMinFrame {
+		buf = append(buf, 0)
+	}
+	return buf
+}
+
+// decodeEthernetFrame returns the destination, source and NPDU of a BACnet
+// frame; ok is false for any other frame
+func decodeEthernetFrame(frame []byte) (dest, src EthernetAddr, npdu []byte, ok bool) {
+	if len(frame) < ethHeaderLen+3 {
+		return dest, src, nil, false
+	}
+	copy(dest[:], frame[0:6])
+	copy(src[:], frame[6:12])
+
+	// An 802.3 length, not an Ethernet II type, followed by the BACnet LLC
+	length := int(frame[12])<<8 | int(frame[13])
+	if length < 3 || length > len(frame)-ethHeaderLen {
+		return dest, src, nil, false
+	}
+	llc := frame[ethHeaderLen:]
+	if llc[0] != ethLSAP || llc[1] != ethLSAP || llc[2] != ethLLCUI {
+		return dest, src, nil, false
+	}
+
+	return dest, src, llc[3:length], true
+}
+
+// ethLink is the ISO 8802-3 (Ethernet) data link, sending LLC frames on a
+// network interface through a raw socket
+type ethLink struct {
+	ifname      string
+	promiscuous bool
+	logger      *slog.Logger
+	clock       Clock
+
+	mu     sync.Mutex
+	conn   *os.File
+	mac    EthernetAddr
+	closed bool
+	wg     sync.WaitGroup
+
+	rx chan ethInbound
+}
+
+// ethInbound is an NPDU received over Ethernet
+type ethInbound struct {
+	npdu []byte
+	from EthernetAddr
+}
+
+// newEthLink creates an Ethernet data link from the client options
+func newEthLink(o *clientOptions) *ethLink {
+	return &ethLink{
+		ifname:      o.ethInterface,
+		promiscuous: o.ethPromiscuous,
+		logger:      o.logger,
+		clock:       o.clock,
+		rx:          make(chan ethInbound, 64),
+	}
+}
+
+func (l *ethLink) Open(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.conn != nil {
+		return nil
+	}
+
+	conn, hw, err := ethernet.Open(l.ifname, l.promiscuous)
+	if err != nil {
+		return err
+	}
+
+	l.conn = conn
+	copy(l.mac[:], hw)
+	l.closed = false
+
+	l.wg.Add(1)
+	go l.reader(conn)
+
+	return nil
+}
+
+func (l *ethLink) Close() error {
+	l.mu.Lock()
+	if l.closed || l.conn == nil {
+		l.closed = true
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	conn := l.conn
+	l.conn = nil
+	l.mu.Unlock()
+
+	err := conn.Close()
+	l.wg.Wait()
+	return err
+}
+
+func (l *ethLink) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// LocalAddr returns the interface MAC address
+func (l *ethLink) LocalAddr() net.Addr {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.mac
+}
+
+func (l *ethLink) Send(ctx context.Context, addr net.Addr, npdu []byte) error {
+	mac, ok := addr.(EthernetAddr)
+	if !ok {
+		return fmt.Errorf("ethernet: unsupported address %v", addr)
+	}
+	return l.write(mac, npdu)
+}
+
+func (l *ethLink) Broadcast(ctx context.Context, npdu []byte) error {
+	return l.write(EthernetBroadcast, npdu)
+}
+
+// write sends an NPDU in a single frame
+func (l *ethLink) write(dest EthernetAddr, npdu []byte) error {
+	if len(npdu) > ethMaxNPDU {
+		return fmt.Errorf("ethernet: NPDU of %d bytes exceeds %d", len(npdu), ethMaxNPDU)
+	}
+
+	l.mu.Lock()
+	conn, src := l.conn, l.mac
+	l.mu.Unlock()
+	if conn == nil {
+		return ErrNotConnected
+	}
+
+	_, err := conn.Write(encodeEthernetFrame(dest, src, npdu))
+	return err
+}
+
+func (l *ethLink) Receive(timeout time.Duration) ([]byte, net.Addr, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case in := <-l.rx:
+		return in.npdu, in.from, nil
+	case <-timer.C:
+		if l.IsClosed() {
+			return nil, nil, ErrConnectionClosed
+		}
+		return nil, nil, linkTimeoutError{}
+	}
+}
+
+// MAC returns the six-octet MAC address
+func (l *ethLink) MAC(addr net.Addr) []byte {
+	mac, ok := addr.(EthernetAddr)
+	if !ok {
+		return nil
+	}
+	return mac[:]
+}
+
+func (l *ethLink) Addr(mac []byte) (net.Addr, error) {
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("invalid Ethernet address length %d", len(mac))
+	}
+	var addr EthernetAddr
+	copy(addr[:], mac)
+	return addr, nil
+}
+
+// ParseAddr parses a MAC address such as "00:1a:2b:3c:4d:5e"
+func (l *ethLink) ParseAddr(s string) (net.Addr, error) {
+	hw, err := net.ParseMAC(s)
+	if err != nil || len(hw) != 6 {
+		return nil, fmt.Errorf("invalid Ethernet address %q", s)
+	}
+	var addr EthernetAddr
+	copy(addr[:], hw)
+	return addr, nil
+}
+
+// reader receives frames and queues the BACnet NPDUs addressed to the
+// station or broadcast
+func (l *ethLink) reader(conn *os.File) {
+	defer l.wg.Done()
+
+	buf := make([]byte, 1600)
+	for {
+		n, err := conn.Read(buf)
+		if err != nil {
+			if errors.Is(err, os.ErrClosed) || errors.Is(err, io.EOF) || l.IsClosed() {
+				return
+			}
+			l.logger.Debug("Ethernet read error", slog.String("error", err.Error()))
+			sleepContext(context.Background(), l.clock, 10*time.Millisecond)
+			continue
+		}
+
+		dest, src, npdu, ok := decodeEthernetFrame(buf[:n])
+		if !ok || src == l.mac {
+			// Not BACnet, or our own frame seen in promiscuous mode
+			continue
+		}
+		if dest != l.mac && dest != EthernetBroadcast {
+			continue
+		}
+
+		select {
+		case l.rx <- ethInbound{npdu: append([]byte(nil), npdu...), from: src}:
+		default:
+			l.logger.Warn("Ethernet receive queue full, dropping frame",
+				slog.String("from", src.String()),
+			)
+		}
+	}
+}
@@ -0,0 +1,251 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// NotifyType tells whether an event notification is an alarm, an event or
+// an acknowledgement
+type NotifyType uint8
+
+const (
+	NotifyTypeAlarm           NotifyType = 0
+	NotifyTypeEvent           NotifyType = 1
+	NotifyTypeAckNotification NotifyType = 2
+)
+
+func (t NotifyType) String() string {
+	switch t {
+	case NotifyTypeAlarm:
+		return "alarm"
+	case NotifyTypeEvent:
+		return "event"
+	case NotifyTypeAckNotification:
+		return "ack-notification"
+	default:
+		return fmt.Sprintf("notify-type(%d)", t)
+	}
+}
+
+// EventTimeStamp is a BACnetTimeStamp, which is either a time of day, a
+// sequence number or a date and time
+type EventTimeStamp struct {
+	// TimeOfDay is set for time stamps
+	TimeOfDay *TimeOfDay
+	// Sequence is set for sequence number stamps
+	Sequence *uint32
+	// DateTime is set for date and time stamps, and is the zero time for
+	// a date and time left unspecified
+	DateTime time.Time
+}
+
+func (ts EventTimeStamp) String() string {
+	switch {
+	case ts.TimeOfDay != nil:
+		return ts.TimeOfDay.String()
+	case ts.Sequence != nil:
+		return fmt.Sprintf("#%d", *ts.Sequence)
+	case ts.DateTime.IsZero():
+		return "unspecified"
+	default:
+		return ts.DateTime.String()
+	}
+}
+
+// decodeEventTimeStamp decodes a BACnetTimeStamp with date and times in loc
+func decodeEventTimeStamp(d *Decoder, loc *time.Location) EventTimeStamp {
+	var ts EventTimeStamp
+	switch {
+	case d.IsContext(0):
+		data := d.context(0, 4, 4)
+		if len(data) == 4 {
+			ts.TimeOfDay = &TimeOfDay{Hour: data[0], Minute: data[1], Second: data[2], Hundredths: data[3]}
+		}
+	case d.IsContext(1):
+		seq := d.ContextUnsigned(1)
+		ts.Sequence = &seq
+	case d.IsOpening(2):
+		d.Opening(2)
+		start := d.Offset()
+		ts.DateTime = decodeDateTime(d, loc)
+		// A date and time left unspecified is the zero time
+		if d.Err() == nil && unspecifiedDateTime(d.data[start:d.Offset()]) {
+			ts.DateTime = time.Time{}
+		}
+		d.Closing(2)
+	default:
+		d.failf("expected time stamp")
+	}
+	return ts
+}
+
+// unspecifiedDateTime returns true for the encoding of a BACnetDateTime
+// with every field unspecified
+func unspecifiedDateTime(data []byte) bool {
+	if len(data) != 10 {
+		return false
+	}
+	for i, b := range data {
+		if i != 0 && i != 5 && b != 0xFF {
+			return false
+		}
+	}
+	return true
+}
+
+// encodeEventTimeStamp appends a BACnetTimeStamp
+func encodeEventTimeStamp(e *Encoder, ts EventTimeStamp) {
+	switch {
+	case ts.TimeOfDay != nil:
+		e.Tag(0, TagClassContext, 4)
+		e.Raw([]byte{ts.TimeOfDay.Hour, ts.TimeOfDay.Minute, ts.TimeOfDay.Second, ts.TimeOfDay.Hundredths})
+	case ts.Sequence != nil:
+		e.ContextUnsigned(1, *ts.Sequence)
+	case ts.DateTime.IsZero():
+		e.Opening(2)
+		e.Tag(uint8(TagDate), TagClassApplication, 4)
+		e.Raw([]byte{0xFF, 0xFF, 0xFF, 0xFF})
+		e.Time(TimeOfDay{Hour: 0xFF, Minute: 0xFF, Second: 0xFF, Hundredths: 0xFF})
+		e.Closing(2)
+	default:
+		e.Opening(2)
+		encodeDateTime(e, ts.DateTime)
+		e.Closing(2)
+	}
+}
+
+// EventNotification is the content of an event notification, as recorded
+// by an Event Log object
+type EventNotification struct {
+	ProcessID         uint32
+	InitiatingDevice  ObjectIdentifier
+	EventObject       ObjectIdentifier
+	Timestamp         EventTimeStamp
+	NotificationClass uint32
+	Priority          uint8
+	EventType         uint32
+	MessageText       string
+	NotifyType        NotifyType
+	AckRequired       bool
+	FromState         EventState
+	ToState           EventState
+
+	// EventValues is the encoding of the notification parameters, if any
+	EventValues []byte
+}
+
+// EventLogRecord is a record of the log-buffer of an Event Log object
+type EventLogRecord struct {
+	Timestamp time.Time
+
+	// Kind is LogRecordValue for notifications, LogRecordStatus or
+	// LogRecordTimeChange
+	Kind LogRecordKind
+
+	// Notification is set for notification records
+	Notification *EventNotification
+
+	// Status is set for status records
+	Status LogStatus
+
+	// TimeChange is the clock adjustment of time-change records
+	TimeChange time.Duration
+}
+
+// ReadEventLog reads the records of an Event Log object logged between from
+// and to, paging through the log-buffer with ReadRange, and returns them
+// sorted by timestamp. Timestamps are sent and decoded in the location of
+// from, which should be the time zone of the device.
+func (c *Client) ReadEventLog(ctx context.Context, deviceID uint32, instance uint32, from, to time.Time) ([]EventLogRecord, error) {
+	objectID := NewObjectIdentifier(ObjectTypeEventLog, instance)
+	items, err := c.readLogBuffer(ctx, deviceID, objectID, from, to, func(d *Decoder, loc *time.Location) (time.Time, interface{}) {
+		rec := decodeEventLogRecord(d, loc)
+		return rec.Timestamp, rec
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]EventLogRecord, len(items))
+	for i, item := range items {
+		records[i] = item.(EventLogRecord)
+	}
+	return records, nil
+}
+
+// decodeEventLogRecord decodes a BACnetEventLogRecord with timestamps in
+// loc
+func decodeEventLogRecord(d *Decoder, loc *time.Location) EventLogRecord {
+	var rec EventLogRecord
+
+	d.Opening(0)
+	rec.Timestamp = decodeDateTime(d, loc)
+	d.Closing(0)
+
+	d.Opening(1)
+	switch {
+	case d.IsContext(0):
+		rec.Kind = LogRecordStatus
+		rec.Status = decodeLogStatus(d, 0)
+	case d.IsOpening(1):
+		d.Opening(1)
+		n := decodeEventNotification(d, loc)
+		d.Closing(1)
+		rec.Notification = &n
+	case d.IsContext(2):
+		rec.Kind = LogRecordTimeChange
+		rec.TimeChange = time.Duration(float64(d.ContextReal(2)) * float64(time.Second))
+	default:
+		d.failf("expected event log datum")
+	}
+	d.Closing(1)
+	return rec
+}
+
+// decodeEventNotification decodes the parameters of a
+// ConfirmedEventNotification request
+func decodeEventNotification(d *Decoder, loc *time.Location) EventNotification {
+	var n EventNotification
+	n.ProcessID = d.ContextUnsigned(0)
+	n.InitiatingDevice = d.ContextObjectIdentifier(1)
+	n.EventObject = d.ContextObjectIdentifier(2)
+
+	d.Opening(3)
+	n.Timestamp = decodeEventTimeStamp(d, loc)
+	d.Closing(3)
+
+	n.NotificationClass = d.ContextUnsigned(4)
+	n.Priority = uint8(d.ContextUnsigned(5))
+	n.EventType = d.ContextEnumerated(6)
+	if d.IsContext(7) {
+		n.MessageText = d.ContextCharacterString(7)
+	}
+	n.NotifyType = NotifyType(d.ContextEnumerated(8))
+	if d.IsContext(9) {
+		n.AckRequired = d.ContextBoolean(9)
+	}
+	if d.IsContext(10) {
+		n.FromState = EventState(d.ContextEnumerated(10))
+	}
+	n.ToState = EventState(d.ContextEnumerated(11))
+	if d.IsOpening(12) {
+		n.EventValues = decodeAnyValue(d, 12)
+	}
+	return n
+}
@@ -0,0 +1,367 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// FileAccessMethod is the access method of a File object
+type FileAccessMethod uint8
+
+const (
+	FileAccessRecord FileAccessMethod = 0
+	FileAccessStream FileAccessMethod = 1
+)
+
+func (m FileAccessMethod) String() string {
+	switch m {
+	case FileAccessRecord:
+		return "record-access"
+	case FileAccessStream:
+		return "stream-access"
+	default:
+		return fmt.Sprintf("file-access-method(%d)", m)
+	}
+}
+
+const (
+	// fileChunkOverhead is the length of an AtomicWriteFile request or an
+	// AtomicReadFile ack without its data
+	fileChunkOverhead = 24
+
+	// minFileChunk is the smallest chunk tried when a device aborts
+	// transfers as too large
+	minFileChunk = 32
+)
+
+// AtomicReadFile reads up to count octets of a stream-access File object
+// from start, and returns them with whether the end of file was reached
+func (c *Client) AtomicReadFile(ctx context.Context, deviceID uint32, instance uint32, start int32, count uint32) ([]byte, bool, error) {
+	d, eof, err := c.atomicReadFile(ctx, deviceID, instance, 0, start, count)
+	if err != nil {
+		return nil, false, err
+	}
+	d.Signed()
+	data := d.OctetString()
+	d.Closing(0)
+	if err := d.Err(); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return data, eof, nil
+}
+
+// AtomicReadFileRecords reads up to count records of a record-access File
+// object from start, and returns them with whether the end of file was
+// reached
+func (c *Client) AtomicReadFileRecords(ctx context.Context, deviceID uint32, instance uint32, start int32, count uint32) ([][]byte, bool, error) {
+	d, eof, err := c.atomicReadFile(ctx, deviceID, instance, 1, start, count)
+	if err != nil {
+		return nil, false, err
+	}
+	d.Signed()
+	n := d.Unsigned()
+	records := make([][]byte, 0, n)
+	for i := uint32(0); i < n && d.Err() == nil; i++ {
+		records = append(records, d.OctetString())
+	}
+	d.Closing(1)
+	if err := d.Err(); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return records, eof, nil
+}
+
+// atomicReadFile sends an AtomicReadFile request with stream (0) or record
+// (1) access, and returns the decoder positioned on the access data
+func (c *Client) atomicReadFile(ctx context.Context, deviceID uint32, instance uint32, access uint8, start int32, count uint32) (*Decoder, bool, error) {
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	e := NewEncoder(make([]byte, 0, 16))
+	e.ObjectIdentifier(NewObjectIdentifier(ObjectTypeFile, instance))
+	e.Opening(access)
+	e.Signed(start)
+	e.Unsigned(count)
+	e.Closing(access)
+
+	resp, err := c.sendRequest(ctx, deviceID, addr, ServiceAtomicReadFile, e.Bytes())
+	if err != nil {
+		return nil, false, err
+	}
+
+	d := NewDecoder(resp.Data)
+	eof := d.Boolean()
+	d.Opening(access)
+	if err := d.Err(); err != nil {
+		return nil, false, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return d, eof, nil
+}
+
+// AtomicWriteFile writes data to a stream-access File object at start, or
+// appends it when start is -1, and returns the position written to
+func (c *Client) AtomicWriteFile(ctx context.Context, deviceID uint32, instance uint32, start int32, data []byte) (int32, error) {
+	e := NewEncoder(make([]byte, 0, len(data)+16))
+	e.ObjectIdentifier(NewObjectIdentifier(ObjectTypeFile, instance))
+	e.Opening(0)
+	e.Signed(start)
+	e.OctetString(data)
+	e.Closing(0)
+	return c.atomicWriteFile(ctx, deviceID, e.Bytes())
+}
+
+// AtomicWriteFileRecords writes records to a record-access File object from
+// start, or appends them when start is -1, and returns the record written
+// to first
+func (c *Client) AtomicWriteFileRecords(ctx context.Context, deviceID uint32, instance uint32, start int32, records [][]byte) (int32, error) {
+	e := NewEncoder(make([]byte, 0, 64))
+	e.ObjectIdentifier(NewObjectIdentifier(ObjectTypeFile, instance))
+	e.Opening(1)
+	e.Signed(start)
+	e.Unsigned(uint32(len(records)))
+	for _, record := range records {
+		e.OctetString(record)
+	}
+	e.Closing(1)
+	return c.atomicWriteFile(ctx, deviceID, e.Bytes())
+}
+
+// atomicWriteFile sends an AtomicWriteFile request and decodes the start
+// position or record of the ack
+func (c *Client) atomicWriteFile(ctx context.Context, deviceID uint32, data []byte) (int32, error) {
+	if c.isStandby() {
+		return 0, ErrStandby
+	}
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return 0, err
+	}
+
+	resp, err := c.sendRequest(ctx, deviceID, addr, ServiceAtomicWriteFile, data)
+	if err != nil {
+		return 0, err
+	}
+
+	d := NewDecoder(resp.Data)
+	var start int32
+	if d.IsContext(1) {
+		start = d.ContextSigned(1)
+	} else {
+		start = d.ContextSigned(0)
+	}
+	if err := d.Err(); err != nil {
+		return 0, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return start, nil
+}
+
+// fileOptions applies the options of a transfer and sizes its chunks
+func (c *Client) fileOptions(deviceID uint32, opts []FileOption) *FileOptions {
+	options := &FileOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.ChunkSize <= 0 {
+		options.ChunkSize = c.deviceMaxAPDU(deviceID) - fileChunkOverhead
+	}
+	if options.ChunkSize < minFileChunk {
+		options.ChunkSize = minFileChunk
+	}
+	return options
+}
+
+// chunkTooLarge returns true for the aborts of a device that cannot send or
+// take a chunk in one APDU
+func chunkTooLarge(err error) bool {
+	var abortErr *AbortError
+	return errors.As(err, &abortErr) &&
+		(abortErr.Reason == AbortReasonSegmentationNotSupported || abortErr.Reason == AbortReasonBufferOverflow)
+}
+
+// ReadFileAccessMethod reads the access method of a File object
+func (c *Client) ReadFileAccessMethod(ctx context.Context, deviceID uint32, instance uint32) (FileAccessMethod, error) {
+	value, err := c.ReadProperty(ctx, deviceID, NewObjectIdentifier(ObjectTypeFile, instance), PropertyFileAccessMethod)
+	if err != nil {
+		return 0, err
+	}
+	method, ok := value.(uint32)
+	if !ok {
+		return 0, fmt.Errorf("%w: file-access-method is %T", ErrInvalidResponse, value)
+	}
+	return FileAccessMethod(method), nil
+}
+
+// fileSize reads the file-size of a File object, or returns -1
+func (c *Client) fileSize(ctx context.Context, deviceID uint32, instance uint32) int64 {
+	value, err := c.ReadProperty(ctx, deviceID, NewObjectIdentifier(ObjectTypeFile, instance), PropertyFileSize)
+	if err != nil {
+		return -1
+	}
+	if size, ok := value.(uint32); ok {
+		return int64(size)
+	}
+	return -1
+}
+
+// ReadFile reads the content of a stream-access File object with
+// AtomicReadFile. Chunks are sized to the maximum APDU of the device, and
+// halved when the device aborts them as too large.
+func (c *Client) ReadFile(ctx context.Context, deviceID uint32, instance uint32, opts ...FileOption) ([]byte, error) {
+	options := c.fileOptions(deviceID, opts)
+	total := c.fileSize(ctx, deviceID, instance)
+
+	var data []byte
+	if total > 0 {
+		data = make([]byte, 0, total)
+	}
+	chunk := options.ChunkSize
+	for {
+		part, eof, err := c.AtomicReadFile(ctx, deviceID, instance, int32(len(data)), uint32(chunk))
+		if chunkTooLarge(err) && chunk > minFileChunk {
+			chunk /= 2
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s at %d: %w", NewObjectIdentifier(ObjectTypeFile, instance), len(data), err)
+		}
+		data = append(data, part...)
+		if options.Progress != nil {
+			options.Progress(int64(len(data)), total)
+		}
+		// A device returning nothing before the end would be read forever
+		if eof || len(part) == 0 {
+			return data, nil
+		}
+	}
+}
+
+// WriteFile replaces the content of a stream-access File object with
+// AtomicWriteFile, truncating it first by writing a file-size of zero when
+// the device allows it. Chunks are sized as by ReadFile.
+func (c *Client) WriteFile(ctx context.Context, deviceID uint32, instance uint32, data []byte, opts ...FileOption) error {
+	options := c.fileOptions(deviceID, opts)
+	total := int64(len(data))
+	fileID := NewObjectIdentifier(ObjectTypeFile, instance)
+
+	if err := c.WriteProperty(ctx, deviceID, fileID, PropertyFileSize, uint32(0)); err != nil {
+		c.logger.Debug("file not truncated",
+			slog.String("file", fileID.String()),
+			slog.String("error", err.Error()))
+	}
+
+	chunk := options.ChunkSize
+	written := 0
+	for written < len(data) || len(data) == 0 {
+		end := written + chunk
+		if end > len(data) {
+			end = len(data)
+		}
+		_, err := c.AtomicWriteFile(ctx, deviceID, instance, int32(written), data[written:end])
+		if chunkTooLarge(err) && chunk > minFileChunk {
+			chunk /= 2
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("write %s at %d: %w", fileID, written, err)
+		}
+		written = end
+		if options.Progress != nil {
+			options.Progress(int64(written), total)
+		}
+		if len(data) == 0 {
+			break
+		}
+	}
+	return nil
+}
+
+// ReadFileRecords reads the records of a record-access File object with
+// AtomicReadFileRecords, asking for fewer records per request when the
+// device aborts them as too large. The progress counts records.
+func (c *Client) ReadFileRecords(ctx context.Context, deviceID uint32, instance uint32, opts ...FileOption) ([][]byte, error) {
+	options := c.fileOptions(deviceID, opts)
+	total := int64(-1)
+	if value, err := c.ReadProperty(ctx, deviceID, NewObjectIdentifier(ObjectTypeFile, instance), PropertyRecordCount); err == nil {
+		if n, ok := value.(uint32); ok {
+			total = int64(n)
+		}
+	}
+
+	var records [][]byte
+	count := uint32(16)
+	for {
+		part, eof, err := c.AtomicReadFileRecords(ctx, deviceID, instance, int32(len(records)), count)
+		if chunkTooLarge(err) && count > 1 {
+			count /= 2
+			continue
+		}
+		if err != nil {
+			return nil, fmt.Errorf("read %s at record %d: %w", NewObjectIdentifier(ObjectTypeFile, instance), len(records), err)
+		}
+		records = append(records, part...)
+		if options.Progress != nil {
+			options.Progress(int64(len(records)), total)
+		}
+		if eof || len(part) == 0 {
+			return records, nil
+		}
+	}
+}
+
+// WriteFileRecords replaces the records of a record-access File object with
+// AtomicWriteFileRecords, truncating it first by writing a record-count of
+// zero when the device allows it. Records are batched up to the chunk size.
+// The progress counts records.
+func (c *Client) WriteFileRecords(ctx context.Context, deviceID uint32, instance uint32, records [][]byte, opts ...FileOption) error {
+	options := c.fileOptions(deviceID, opts)
+	total := int64(len(records))
+	fileID := NewObjectIdentifier(ObjectTypeFile, instance)
+
+	if err := c.WriteProperty(ctx, deviceID, fileID, PropertyRecordCount, uint32(0)); err != nil {
+		c.logger.Debug("file not truncated",
+			slog.String("file", fileID.String()),
+			slog.String("error", err.Error()))
+	}
+
+	chunk := options.ChunkSize
+	written := 0
+	for written < len(records) {
+		// Each record takes its length and up to five octets of tag
+		end, size := written, 0
+		for end < len(records) && (end == written || size+len(records[end])+5 <= chunk) {
+			size += len(records[end]) + 5
+			end++
+		}
+		_, err := c.AtomicWriteFileRecords(ctx, deviceID, instance, int32(written), records[written:end])
+		if chunkTooLarge(err) && end-written > 1 {
+			chunk = size / 2
+			continue
+		}
+		if err != nil {
+			return fmt.Errorf("write %s at record %d: %w", fileID, written, err)
+		}
+		written = end
+		if options.Progress != nil {
+			options.Progress(int64(written), total)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// GlobalGroupValue is a BACnetPropertyAccessResult of the present-value of
+// a Global Group object: the value of one member, or the error reading it
+type GlobalGroupValue struct {
+	// DeviceID is the device holding the member
+	DeviceID   uint32
+	ObjectID   ObjectIdentifier
+	PropertyID PropertyIdentifier
+	ArrayIndex *uint32
+	Value      interface{}
+
+	// Err is the *BACnetError of a member that could not be read
+	Err error
+}
+
+// ReadGroup reads the present-value of a Group object, the values of all
+// its members taken by the device in a single request. Members that could
+// not be read carry their error.
+func (c *Client) ReadGroup(ctx context.Context, deviceID uint32, instance uint32) ([]PropertyResult, error) {
+	objectID := NewObjectIdentifier(ObjectTypeGroup, instance)
+	resp, err := c.readProperty(ctx, deviceID, objectID, PropertyPresentValue, &ReadOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	// The value is a list of ReadAccessResult, as in a
+	// ReadPropertyMultiple ack
+	data, err := propertyValueData(resp.Data)
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeReadPropertyMultipleResponse(data)
+}
+
+// ReadGlobalGroup reads the present-value of a Global Group object, the
+// values of its members across devices. When the device cannot return the
+// whole array in one APDU, the members are read one by one.
+func (c *Client) ReadGlobalGroup(ctx context.Context, deviceID uint32, instance uint32) ([]GlobalGroupValue, error) {
+	objectID := NewObjectIdentifier(ObjectTypeGlobalGroup, instance)
+	resp, err := c.readProperty(ctx, deviceID, objectID, PropertyPresentValue, &ReadOptions{})
+	var abortErr *AbortError
+	if errors.As(err, &abortErr) {
+		return c.readGlobalGroupByIndex(ctx, deviceID, objectID)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := propertyValueData(resp.Data)
+	if err != nil {
+		return nil, err
+	}
+	return c.decodeGlobalGroupValues(deviceID, data)
+}
+
+// readGlobalGroupByIndex reads the present-value of a Global Group one
+// array element at a time
+func (c *Client) readGlobalGroupByIndex(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) ([]GlobalGroupValue, error) {
+	lengthVal, err := c.ReadProperty(ctx, deviceID, objectID, PropertyPresentValue, WithArrayIndex(0))
+	if err != nil {
+		return nil, err
+	}
+	length, ok := lengthVal.(uint32)
+	if !ok {
+		return nil, fmt.Errorf("%w: array length of type %T", ErrInvalidResponse, lengthVal)
+	}
+
+	values := make([]GlobalGroupValue, 0, length)
+	for i := uint32(1); i <= length; i++ {
+		index := i
+		resp, err := c.readProperty(ctx, deviceID, objectID, PropertyPresentValue, &ReadOptions{ArrayIndex: &index})
+		if err != nil {
+			return nil, fmt.Errorf("element %d: %w", i, err)
+		}
+		data, err := propertyValueData(resp.Data)
+		if err != nil {
+			return nil, err
+		}
+		element, err := c.decodeGlobalGroupValues(deviceID, data)
+		if err != nil {
+			return nil, err
+		}
+		values = append(values, element...)
+	}
+	return values, nil
+}
+
+// decodeGlobalGroupValues decodes a sequence of
+// BACnetPropertyAccessResult. Members without a device identifier are in
+// deviceID.
+func (c *Client) decodeGlobalGroupValues(deviceID uint32, data []byte) ([]GlobalGroupValue, error) {
+	var values []GlobalGroupValue
+	d := NewDecoder(data)
+	for d.Err() == nil && d.Len() > 0 {
+		v := GlobalGroupValue{DeviceID: deviceID}
+		v.ObjectID = d.ContextObjectIdentifier(0)
+		v.PropertyID = PropertyIdentifier(d.ContextEnumerated(1))
+		if d.IsContext(2) {
+			index := d.ContextUnsigned(2)
+			v.ArrayIndex = &index
+		}
+		if d.IsContext(3) {
+			v.DeviceID = d.ContextObjectIdentifier(3).Instance
+		}
+
+		switch {
+		case d.IsOpening(4):
+			d.Opening(4)
+			start := d.Offset()
+			for d.Err() == nil && !d.IsClosing(4) {
+				d.Skip()
+			}
+			end := d.Offset()
+			d.Closing(4)
+			if d.Err() == nil {
+				v.Value, _ = c.decodePropertyValue(data[start:end])
+			}
+		case d.IsOpening(5):
+			d.Opening(5)
+			class := ErrorClass(d.Enumerated())
+			code := ErrorCode(d.Enumerated())
+			d.Closing(5)
+			v.Err = &BACnetError{Class: class, Code: code}
+		default:
+			d.failf("expected property value or access error")
+		}
+		values = append(values, v)
+	}
+
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return values, nil
+}
+
+// propertyValueData returns the encoding of the property value of a
+// ReadProperty ack
+func propertyValueData(data []byte) ([]byte, error) {
+	d := NewDecoder(data)
+	d.ContextObjectIdentifier(0)
+	d.ContextEnumerated(1)
+	if d.IsContext(2) {
+		d.ContextUnsigned(2)
+	}
+	d.Opening(3)
+	start := d.Offset()
+	for d.Err() == nil && d.Len() > 0 && !d.IsClosing(3) {
+		d.Skip()
+	}
+	end := d.Offset()
+	d.Closing(3)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return data[start:end], nil
+}
@@ -0,0 +1,1615 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.6
+// 	protoc        v5.29.3
+// source: bacnet.proto
+
+package grpcbacnet
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// ObjectIdentifier identifies an object within a device
+type ObjectIdentifier struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Object type, e.g. 0 for analog-input
+	Type          uint32 `protobuf:"varint,1,opt,name=type,proto3" json:"type,omitempty"`
+	Instance      uint32 `protobuf:"varint,2,opt,name=instance,proto3" json:"instance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ObjectIdentifier) Reset() {
+	*x = ObjectIdentifier{}
+	mi := &file_bacnet_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ObjectIdentifier) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ObjectIdentifier) ProtoMessage() {}
+
+func (x *ObjectIdentifier) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ObjectIdentifier.ProtoReflect.Descriptor instead.
+func (*ObjectIdentifier) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *ObjectIdentifier) GetType() uint32 {
+	if x != nil {
+		return x.Type
+	}
+	return 0
+}
+
+func (x *ObjectIdentifier) GetInstance() uint32 {
+	if x != nil {
+		return x.Instance
+	}
+	return 0
+}
+
+// BitString is a BACnet bit string, bit 0 being the high bit of the first byte
+type BitString struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Length        uint32                 `protobuf:"varint,1,opt,name=length,proto3" json:"length,omitempty"`
+	Bits          []byte                 `protobuf:"bytes,2,opt,name=bits,proto3" json:"bits,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *BitString) Reset() {
+	*x = BitString{}
+	mi := &file_bacnet_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *BitString) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*BitString) ProtoMessage() {}
+
+func (x *BitString) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use BitString.ProtoReflect.Descriptor instead.
+func (*BitString) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *BitString) GetLength() uint32 {
+	if x != nil {
+		return x.Length
+	}
+	return 0
+}
+
+func (x *BitString) GetBits() []byte {
+	if x != nil {
+		return x.Bits
+	}
+	return nil
+}
+
+// ValueList is the value of an array or list property
+type ValueList struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Values        []*Value               `protobuf:"bytes,1,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ValueList) Reset() {
+	*x = ValueList{}
+	mi := &file_bacnet_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ValueList) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ValueList) ProtoMessage() {}
+
+func (x *ValueList) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ValueList.ProtoReflect.Descriptor instead.
+func (*ValueList) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *ValueList) GetValues() []*Value {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+// Value is a BACnet application value
+type Value struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Types that are valid to be assigned to Kind:
+	//
+	//	*Value_Null
+	//	*Value_Boolean
+	//	*Value_Unsigned
+	//	*Value_Signed
+	//	*Value_Real
+	//	*Value_Double
+	//	*Value_OctetString
+	//	*Value_CharacterString
+	//	*Value_BitString
+	//	*Value_Enumerated
+	//	*Value_ObjectIdentifier
+	//	*Value_List
+	//	*Value_Text
+	Kind          isValue_Kind `protobuf_oneof:"kind"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Value) Reset() {
+	*x = Value{}
+	mi := &file_bacnet_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Value) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Value) ProtoMessage() {}
+
+func (x *Value) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Value.ProtoReflect.Descriptor instead.
+func (*Value) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *Value) GetKind() isValue_Kind {
+	if x != nil {
+		return x.Kind
+	}
+	return nil
+}
+
+func (x *Value) GetNull() structpb.NullValue {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_Null); ok {
+			return x.Null
+		}
+	}
+	return structpb.NullValue(0)
+}
+
+func (x *Value) GetBoolean() bool {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_Boolean); ok {
+			return x.Boolean
+		}
+	}
+	return false
+}
+
+func (x *Value) GetUnsigned() uint32 {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_Unsigned); ok {
+			return x.Unsigned
+		}
+	}
+	return 0
+}
+
+func (x *Value) GetSigned() int32 {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_Signed); ok {
+			return x.Signed
+		}
+	}
+	return 0
+}
+
+func (x *Value) GetReal() float32 {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_Real); ok {
+			return x.Real
+		}
+	}
+	return 0
+}
+
+func (x *Value) GetDouble() float64 {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_Double); ok {
+			return x.Double
+		}
+	}
+	return 0
+}
+
+func (x *Value) GetOctetString() []byte {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_OctetString); ok {
+			return x.OctetString
+		}
+	}
+	return nil
+}
+
+func (x *Value) GetCharacterString() string {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_CharacterString); ok {
+			return x.CharacterString
+		}
+	}
+	return ""
+}
+
+func (x *Value) GetBitString() *BitString {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_BitString); ok {
+			return x.BitString
+		}
+	}
+	return nil
+}
+
+func (x *Value) GetEnumerated() uint32 {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_Enumerated); ok {
+			return x.Enumerated
+		}
+	}
+	return 0
+}
+
+func (x *Value) GetObjectIdentifier() *ObjectIdentifier {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_ObjectIdentifier); ok {
+			return x.ObjectIdentifier
+		}
+	}
+	return nil
+}
+
+func (x *Value) GetList() *ValueList {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_List); ok {
+			return x.List
+		}
+	}
+	return nil
+}
+
+func (x *Value) GetText() string {
+	if x != nil {
+		if x, ok := x.Kind.(*Value_Text); ok {
+			return x.Text
+		}
+	}
+	return ""
+}
+
+type isValue_Kind interface {
+	isValue_Kind()
+}
+
+type Value_Null struct {
+	Null structpb.NullValue `protobuf:"varint,1,opt,name=null,proto3,enum=google.protobuf.NullValue,oneof"`
+}
+
+type Value_Boolean struct {
+	Boolean bool `protobuf:"varint,2,opt,name=boolean,proto3,oneof"`
+}
+
+type Value_Unsigned struct {
+	Unsigned uint32 `protobuf:"varint,3,opt,name=unsigned,proto3,oneof"`
+}
+
+type Value_Signed struct {
+	Signed int32 `protobuf:"varint,4,opt,name=signed,proto3,oneof"`
+}
+
+type Value_Real struct {
+	Real float32 `protobuf:"fixed32,5,opt,name=real,proto3,oneof"`
+}
+
+type Value_Double struct {
+	Double float64 `protobuf:"fixed64,6,opt,name=double,proto3,oneof"`
+}
+
+type Value_OctetString struct {
+	OctetString []byte `protobuf:"bytes,7,opt,name=octet_string,json=octetString,proto3,oneof"`
+}
+
+type Value_CharacterString struct {
+	CharacterString string `protobuf:"bytes,8,opt,name=character_string,json=characterString,proto3,oneof"`
+}
+
+type Value_BitString struct {
+	BitString *BitString `protobuf:"bytes,9,opt,name=bit_string,json=bitString,proto3,oneof"`
+}
+
+type Value_Enumerated struct {
+	Enumerated uint32 `protobuf:"varint,10,opt,name=enumerated,proto3,oneof"`
+}
+
+type Value_ObjectIdentifier struct {
+	ObjectIdentifier *ObjectIdentifier `protobuf:"bytes,11,opt,name=object_identifier,json=objectIdentifier,proto3,oneof"`
+}
+
+type Value_List struct {
+	List *ValueList `protobuf:"bytes,12,opt,name=list,proto3,oneof"`
+}
+
+type Value_Text struct {
+	// Other values, e.g. times, in their text form; read only
+	Text string `protobuf:"bytes,13,opt,name=text,proto3,oneof"`
+}
+
+func (*Value_Null) isValue_Kind() {}
+
+func (*Value_Boolean) isValue_Kind() {}
+
+func (*Value_Unsigned) isValue_Kind() {}
+
+func (*Value_Signed) isValue_Kind() {}
+
+func (*Value_Real) isValue_Kind() {}
+
+func (*Value_Double) isValue_Kind() {}
+
+func (*Value_OctetString) isValue_Kind() {}
+
+func (*Value_CharacterString) isValue_Kind() {}
+
+func (*Value_BitString) isValue_Kind() {}
+
+func (*Value_Enumerated) isValue_Kind() {}
+
+func (*Value_ObjectIdentifier) isValue_Kind() {}
+
+func (*Value_List) isValue_Kind() {}
+
+func (*Value_Text) isValue_Kind() {}
+
+// Device is a device that answered a Who-Is
+type Device struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Instance      uint32                 `protobuf:"varint,1,opt,name=instance,proto3" json:"instance,omitempty"`
+	Address       string                 `protobuf:"bytes,2,opt,name=address,proto3" json:"address,omitempty"`
+	MaxApduLength uint32                 `protobuf:"varint,3,opt,name=max_apdu_length,json=maxApduLength,proto3" json:"max_apdu_length,omitempty"`
+	Segmentation  uint32                 `protobuf:"varint,4,opt,name=segmentation,proto3" json:"segmentation,omitempty"`
+	VendorId      uint32                 `protobuf:"varint,5,opt,name=vendor_id,json=vendorId,proto3" json:"vendor_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Device) Reset() {
+	*x = Device{}
+	mi := &file_bacnet_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Device) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Device) ProtoMessage() {}
+
+func (x *Device) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Device.ProtoReflect.Descriptor instead.
+func (*Device) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *Device) GetInstance() uint32 {
+	if x != nil {
+		return x.Instance
+	}
+	return 0
+}
+
+func (x *Device) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *Device) GetMaxApduLength() uint32 {
+	if x != nil {
+		return x.MaxApduLength
+	}
+	return 0
+}
+
+func (x *Device) GetSegmentation() uint32 {
+	if x != nil {
+		return x.Segmentation
+	}
+	return 0
+}
+
+func (x *Device) GetVendorId() uint32 {
+	if x != nil {
+		return x.VendorId
+	}
+	return 0
+}
+
+type DiscoverRequest struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// Instance range of the Who-Is; all devices when unset
+	LowLimit  *uint32 `protobuf:"varint,1,opt,name=low_limit,json=lowLimit,proto3,oneof" json:"low_limit,omitempty"`
+	HighLimit *uint32 `protobuf:"varint,2,opt,name=high_limit,json=highLimit,proto3,oneof" json:"high_limit,omitempty"`
+	// Time to wait for I-Am replies; the server default when unset
+	Timeout       *durationpb.Duration `protobuf:"bytes,3,opt,name=timeout,proto3" json:"timeout,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverRequest) Reset() {
+	*x = DiscoverRequest{}
+	mi := &file_bacnet_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverRequest) ProtoMessage() {}
+
+func (x *DiscoverRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverRequest.ProtoReflect.Descriptor instead.
+func (*DiscoverRequest) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *DiscoverRequest) GetLowLimit() uint32 {
+	if x != nil && x.LowLimit != nil {
+		return *x.LowLimit
+	}
+	return 0
+}
+
+func (x *DiscoverRequest) GetHighLimit() uint32 {
+	if x != nil && x.HighLimit != nil {
+		return *x.HighLimit
+	}
+	return 0
+}
+
+func (x *DiscoverRequest) GetTimeout() *durationpb.Duration {
+	if x != nil {
+		return x.Timeout
+	}
+	return nil
+}
+
+type DiscoverResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Devices       []*Device              `protobuf:"bytes,1,rep,name=devices,proto3" json:"devices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DiscoverResponse) Reset() {
+	*x = DiscoverResponse{}
+	mi := &file_bacnet_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DiscoverResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DiscoverResponse) ProtoMessage() {}
+
+func (x *DiscoverResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DiscoverResponse.ProtoReflect.Descriptor instead.
+func (*DiscoverResponse) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *DiscoverResponse) GetDevices() []*Device {
+	if x != nil {
+		return x.Devices
+	}
+	return nil
+}
+
+type ReadPropertyRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId uint32                 `protobuf:"varint,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Object   *ObjectIdentifier      `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	// Property identifier, e.g. 85 for present-value
+	Property      uint32  `protobuf:"varint,3,opt,name=property,proto3" json:"property,omitempty"`
+	ArrayIndex    *uint32 `protobuf:"varint,4,opt,name=array_index,json=arrayIndex,proto3,oneof" json:"array_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadPropertyRequest) Reset() {
+	*x = ReadPropertyRequest{}
+	mi := &file_bacnet_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadPropertyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadPropertyRequest) ProtoMessage() {}
+
+func (x *ReadPropertyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadPropertyRequest.ProtoReflect.Descriptor instead.
+func (*ReadPropertyRequest) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *ReadPropertyRequest) GetDeviceId() uint32 {
+	if x != nil {
+		return x.DeviceId
+	}
+	return 0
+}
+
+func (x *ReadPropertyRequest) GetObject() *ObjectIdentifier {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *ReadPropertyRequest) GetProperty() uint32 {
+	if x != nil {
+		return x.Property
+	}
+	return 0
+}
+
+func (x *ReadPropertyRequest) GetArrayIndex() uint32 {
+	if x != nil && x.ArrayIndex != nil {
+		return *x.ArrayIndex
+	}
+	return 0
+}
+
+type ReadPropertyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Value         *Value                 `protobuf:"bytes,1,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadPropertyResponse) Reset() {
+	*x = ReadPropertyResponse{}
+	mi := &file_bacnet_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadPropertyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadPropertyResponse) ProtoMessage() {}
+
+func (x *ReadPropertyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadPropertyResponse.ProtoReflect.Descriptor instead.
+func (*ReadPropertyResponse) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ReadPropertyResponse) GetValue() *Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type WritePropertyRequest struct {
+	state      protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId   uint32                 `protobuf:"varint,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Object     *ObjectIdentifier      `protobuf:"bytes,2,opt,name=object,proto3" json:"object,omitempty"`
+	Property   uint32                 `protobuf:"varint,3,opt,name=property,proto3" json:"property,omitempty"`
+	ArrayIndex *uint32                `protobuf:"varint,4,opt,name=array_index,json=arrayIndex,proto3,oneof" json:"array_index,omitempty"`
+	Value      *Value                 `protobuf:"bytes,5,opt,name=value,proto3" json:"value,omitempty"`
+	// Priority 1-16, or 0 to write without priority
+	Priority      uint32 `protobuf:"varint,6,opt,name=priority,proto3" json:"priority,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WritePropertyRequest) Reset() {
+	*x = WritePropertyRequest{}
+	mi := &file_bacnet_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WritePropertyRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WritePropertyRequest) ProtoMessage() {}
+
+func (x *WritePropertyRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WritePropertyRequest.ProtoReflect.Descriptor instead.
+func (*WritePropertyRequest) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *WritePropertyRequest) GetDeviceId() uint32 {
+	if x != nil {
+		return x.DeviceId
+	}
+	return 0
+}
+
+func (x *WritePropertyRequest) GetObject() *ObjectIdentifier {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *WritePropertyRequest) GetProperty() uint32 {
+	if x != nil {
+		return x.Property
+	}
+	return 0
+}
+
+func (x *WritePropertyRequest) GetArrayIndex() uint32 {
+	if x != nil && x.ArrayIndex != nil {
+		return *x.ArrayIndex
+	}
+	return 0
+}
+
+func (x *WritePropertyRequest) GetValue() *Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *WritePropertyRequest) GetPriority() uint32 {
+	if x != nil {
+		return x.Priority
+	}
+	return 0
+}
+
+type WritePropertyResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *WritePropertyResponse) Reset() {
+	*x = WritePropertyResponse{}
+	mi := &file_bacnet_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *WritePropertyResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*WritePropertyResponse) ProtoMessage() {}
+
+func (x *WritePropertyResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use WritePropertyResponse.ProtoReflect.Descriptor instead.
+func (*WritePropertyResponse) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{10}
+}
+
+// PropertyReference is a property read by ReadMultiple
+type PropertyReference struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Object        *ObjectIdentifier      `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Property      uint32                 `protobuf:"varint,2,opt,name=property,proto3" json:"property,omitempty"`
+	ArrayIndex    *uint32                `protobuf:"varint,3,opt,name=array_index,json=arrayIndex,proto3,oneof" json:"array_index,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PropertyReference) Reset() {
+	*x = PropertyReference{}
+	mi := &file_bacnet_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyReference) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyReference) ProtoMessage() {}
+
+func (x *PropertyReference) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyReference.ProtoReflect.Descriptor instead.
+func (*PropertyReference) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *PropertyReference) GetObject() *ObjectIdentifier {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *PropertyReference) GetProperty() uint32 {
+	if x != nil {
+		return x.Property
+	}
+	return 0
+}
+
+func (x *PropertyReference) GetArrayIndex() uint32 {
+	if x != nil && x.ArrayIndex != nil {
+		return *x.ArrayIndex
+	}
+	return 0
+}
+
+type ReadMultipleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId      uint32                 `protobuf:"varint,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Properties    []*PropertyReference   `protobuf:"bytes,2,rep,name=properties,proto3" json:"properties,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadMultipleRequest) Reset() {
+	*x = ReadMultipleRequest{}
+	mi := &file_bacnet_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadMultipleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadMultipleRequest) ProtoMessage() {}
+
+func (x *ReadMultipleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadMultipleRequest.ProtoReflect.Descriptor instead.
+func (*ReadMultipleRequest) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *ReadMultipleRequest) GetDeviceId() uint32 {
+	if x != nil {
+		return x.DeviceId
+	}
+	return 0
+}
+
+func (x *ReadMultipleRequest) GetProperties() []*PropertyReference {
+	if x != nil {
+		return x.Properties
+	}
+	return nil
+}
+
+// Error is a BACnet error class and code
+type Error struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	ErrorClass    uint32                 `protobuf:"varint,1,opt,name=error_class,json=errorClass,proto3" json:"error_class,omitempty"`
+	ErrorCode     uint32                 `protobuf:"varint,2,opt,name=error_code,json=errorCode,proto3" json:"error_code,omitempty"`
+	Message       string                 `protobuf:"bytes,3,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Error) Reset() {
+	*x = Error{}
+	mi := &file_bacnet_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Error) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Error) ProtoMessage() {}
+
+func (x *Error) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Error.ProtoReflect.Descriptor instead.
+func (*Error) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *Error) GetErrorClass() uint32 {
+	if x != nil {
+		return x.ErrorClass
+	}
+	return 0
+}
+
+func (x *Error) GetErrorCode() uint32 {
+	if x != nil {
+		return x.ErrorCode
+	}
+	return 0
+}
+
+func (x *Error) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// PropertyResult is the value of a property, or the error of a property that
+// could not be read
+type PropertyResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Object        *ObjectIdentifier      `protobuf:"bytes,1,opt,name=object,proto3" json:"object,omitempty"`
+	Property      uint32                 `protobuf:"varint,2,opt,name=property,proto3" json:"property,omitempty"`
+	ArrayIndex    *uint32                `protobuf:"varint,3,opt,name=array_index,json=arrayIndex,proto3,oneof" json:"array_index,omitempty"`
+	Value         *Value                 `protobuf:"bytes,4,opt,name=value,proto3" json:"value,omitempty"`
+	Error         *Error                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PropertyResult) Reset() {
+	*x = PropertyResult{}
+	mi := &file_bacnet_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyResult) ProtoMessage() {}
+
+func (x *PropertyResult) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyResult.ProtoReflect.Descriptor instead.
+func (*PropertyResult) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *PropertyResult) GetObject() *ObjectIdentifier {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *PropertyResult) GetProperty() uint32 {
+	if x != nil {
+		return x.Property
+	}
+	return 0
+}
+
+func (x *PropertyResult) GetArrayIndex() uint32 {
+	if x != nil && x.ArrayIndex != nil {
+		return *x.ArrayIndex
+	}
+	return 0
+}
+
+func (x *PropertyResult) GetValue() *Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+func (x *PropertyResult) GetError() *Error {
+	if x != nil {
+		return x.Error
+	}
+	return nil
+}
+
+type ReadMultipleResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Results       []*PropertyResult      `protobuf:"bytes,1,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ReadMultipleResponse) Reset() {
+	*x = ReadMultipleResponse{}
+	mi := &file_bacnet_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ReadMultipleResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ReadMultipleResponse) ProtoMessage() {}
+
+func (x *ReadMultipleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ReadMultipleResponse.ProtoReflect.Descriptor instead.
+func (*ReadMultipleResponse) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *ReadMultipleResponse) GetResults() []*PropertyResult {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type SubscribeCOVRequest struct {
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	DeviceId uint32                 `protobuf:"varint,1,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Objects  []*ObjectIdentifier    `protobuf:"bytes,2,rep,name=objects,proto3" json:"objects,omitempty"`
+	// Subscription lifetime, renewed at half; the server default when unset
+	Lifetime  *durationpb.Duration `protobuf:"bytes,3,opt,name=lifetime,proto3" json:"lifetime,omitempty"`
+	Confirmed bool                 `protobuf:"varint,4,opt,name=confirmed,proto3" json:"confirmed,omitempty"`
+	// COV increment of the present values, sent with SubscribeCOVProperty
+	Increment     *float32 `protobuf:"fixed32,5,opt,name=increment,proto3,oneof" json:"increment,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeCOVRequest) Reset() {
+	*x = SubscribeCOVRequest{}
+	mi := &file_bacnet_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeCOVRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeCOVRequest) ProtoMessage() {}
+
+func (x *SubscribeCOVRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeCOVRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeCOVRequest) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SubscribeCOVRequest) GetDeviceId() uint32 {
+	if x != nil {
+		return x.DeviceId
+	}
+	return 0
+}
+
+func (x *SubscribeCOVRequest) GetObjects() []*ObjectIdentifier {
+	if x != nil {
+		return x.Objects
+	}
+	return nil
+}
+
+func (x *SubscribeCOVRequest) GetLifetime() *durationpb.Duration {
+	if x != nil {
+		return x.Lifetime
+	}
+	return nil
+}
+
+func (x *SubscribeCOVRequest) GetConfirmed() bool {
+	if x != nil {
+		return x.Confirmed
+	}
+	return false
+}
+
+func (x *SubscribeCOVRequest) GetIncrement() float32 {
+	if x != nil && x.Increment != nil {
+		return *x.Increment
+	}
+	return 0
+}
+
+// PropertyValue is a property value of a notification
+type PropertyValue struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Property      uint32                 `protobuf:"varint,1,opt,name=property,proto3" json:"property,omitempty"`
+	ArrayIndex    *uint32                `protobuf:"varint,2,opt,name=array_index,json=arrayIndex,proto3,oneof" json:"array_index,omitempty"`
+	Value         *Value                 `protobuf:"bytes,3,opt,name=value,proto3" json:"value,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PropertyValue) Reset() {
+	*x = PropertyValue{}
+	mi := &file_bacnet_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PropertyValue) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PropertyValue) ProtoMessage() {}
+
+func (x *PropertyValue) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PropertyValue.ProtoReflect.Descriptor instead.
+func (*PropertyValue) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *PropertyValue) GetProperty() uint32 {
+	if x != nil {
+		return x.Property
+	}
+	return 0
+}
+
+func (x *PropertyValue) GetArrayIndex() uint32 {
+	if x != nil && x.ArrayIndex != nil {
+		return *x.ArrayIndex
+	}
+	return 0
+}
+
+func (x *PropertyValue) GetValue() *Value {
+	if x != nil {
+		return x.Value
+	}
+	return nil
+}
+
+type COVNotification struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Time          *timestamppb.Timestamp `protobuf:"bytes,1,opt,name=time,proto3" json:"time,omitempty"`
+	DeviceId      uint32                 `protobuf:"varint,2,opt,name=device_id,json=deviceId,proto3" json:"device_id,omitempty"`
+	Object        *ObjectIdentifier      `protobuf:"bytes,3,opt,name=object,proto3" json:"object,omitempty"`
+	Values        []*PropertyValue       `protobuf:"bytes,4,rep,name=values,proto3" json:"values,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *COVNotification) Reset() {
+	*x = COVNotification{}
+	mi := &file_bacnet_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *COVNotification) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*COVNotification) ProtoMessage() {}
+
+func (x *COVNotification) ProtoReflect() protoreflect.Message {
+	mi := &file_bacnet_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use COVNotification.ProtoReflect.Descriptor instead.
+func (*COVNotification) Descriptor() ([]byte, []int) {
+	return file_bacnet_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *COVNotification) GetTime() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Time
+	}
+	return nil
+}
+
+func (x *COVNotification) GetDeviceId() uint32 {
+	if x != nil {
+		return x.DeviceId
+	}
+	return 0
+}
+
+func (x *COVNotification) GetObject() *ObjectIdentifier {
+	if x != nil {
+		return x.Object
+	}
+	return nil
+}
+
+func (x *COVNotification) GetValues() []*PropertyValue {
+	if x != nil {
+		return x.Values
+	}
+	return nil
+}
+
+var File_bacnet_proto protoreflect.FileDescriptor
+
+const file_bacnet_proto_rawDesc = "" +
+	"\n" +
+	"\fbacnet.proto\x12\x0fedgeo.bacnet.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"B\n" +
+	"\x10ObjectIdentifier\x12\x12\n" +
+	"\x04type\x18\x01 \x01(\rR\x04type\x12\x1a\n" +
+	"\binstance\x18\x02 \x01(\rR\binstance\"7\n" +
+	"\tBitString\x12\x16\n" +
+	"\x06length\x18\x01 \x01(\rR\x06length\x12\x12\n" +
+	"\x04bits\x18\x02 \x01(\fR\x04bits\";\n" +
+	"\tValueList\x12.\n" +
+	"\x06values\x18\x01 \x03(\v2\x16.edgeo.bacnet.v1.ValueR\x06values\"\x90\x04\n" +
+	"\x05Value\x120\n" +
+	"\x04null\x18\x01 \x01(\x0e2\x1a.google.protobuf.NullValueH\x00R\x04null\x12\x1a\n" +
+	"\aboolean\x18\x02 \x01(\bH\x00R\aboolean\x12\x1c\n" +
+	"\bunsigned\x18\x03 \x01(\rH\x00R\bunsigned\x12\x18\n" +
+	"\x06signed\x18\x04 \x01(\x05H\x00R\x06signed\x12\x14\n" +
+	"\x04real\x18\x05 \x01(\x02H\x00R\x04real\x12\x18\n" +
+	"\x06double\x18\x06 \x01(\x01H\x00R\x06double\x12#\n" +
+	"\foctet_string\x18\a \x01(\fH\x00R\voctetString\x12+\n" +
+	"\x10character_string\x18\b \x01(\tH\x00R\x0fcharacterString\x12;\n" +
+	"\n" +
+	"bit_string\x18\t \x01(\v2\x1a.edgeo.bacnet.v1.BitStringH\x00R\tbitString\x12 \n" +
+	"\n" +
+	"enumerated\x18\n" +
+	" \x01(\rH\x00R\n" +
+	"enumerated\x12P\n" +
+	"\x11object_identifier\x18\v \x01(\v2!.edgeo.bacnet.v1.ObjectIdentifierH\x00R\x10objectIdentifier\x120\n" +
+	"\x04list\x18\f \x01(\v2\x1a.edgeo.bacnet.v1.ValueListH\x00R\x04list\x12\x14\n" +
+	"\x04text\x18\r \x01(\tH\x00R\x04textB\x06\n" +
+	"\x04kind\"\xa7\x01\n" +
+	"\x06Device\x12\x1a\n" +
+	"\binstance\x18\x01 \x01(\rR\binstance\x12\x18\n" +
+	"\aaddress\x18\x02 \x01(\tR\aaddress\x12&\n" +
+	"\x0fmax_apdu_length\x18\x03 \x01(\rR\rmaxApduLength\x12\"\n" +
+	"\fsegmentation\x18\x04 \x01(\rR\fsegmentation\x12\x1b\n" +
+	"\tvendor_id\x18\x05 \x01(\rR\bvendorId\"\xa9\x01\n" +
+	"\x0fDiscoverRequest\x12 \n" +
+	"\tlow_limit\x18\x01 \x01(\rH\x00R\blowLimit\x88\x01\x01\x12\"\n" +
+	"\n" +
+	"high_limit\x18\x02 \x01(\rH\x01R\thighLimit\x88\x01\x01\x123\n" +
+	"\atimeout\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\atimeoutB\f\n" +
+	"\n" +
+	"_low_limitB\r\n" +
+	"\v_high_limit\"E\n" +
+	"\x10DiscoverResponse\x121\n" +
+	"\adevices\x18\x01 \x03(\v2\x17.edgeo.bacnet.v1.DeviceR\adevices\"\xbf\x01\n" +
+	"\x13ReadPropertyRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\rR\bdeviceId\x129\n" +
+	"\x06object\x18\x02 \x01(\v2!.edgeo.bacnet.v1.ObjectIdentifierR\x06object\x12\x1a\n" +
+	"\bproperty\x18\x03 \x01(\rR\bproperty\x12$\n" +
+	"\varray_index\x18\x04 \x01(\rH\x00R\n" +
+	"arrayIndex\x88\x01\x01B\x0e\n" +
+	"\f_array_index\"D\n" +
+	"\x14ReadPropertyResponse\x12,\n" +
+	"\x05value\x18\x01 \x01(\v2\x16.edgeo.bacnet.v1.ValueR\x05value\"\x8a\x02\n" +
+	"\x14WritePropertyRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\rR\bdeviceId\x129\n" +
+	"\x06object\x18\x02 \x01(\v2!.edgeo.bacnet.v1.ObjectIdentifierR\x06object\x12\x1a\n" +
+	"\bproperty\x18\x03 \x01(\rR\bproperty\x12$\n" +
+	"\varray_index\x18\x04 \x01(\rH\x00R\n" +
+	"arrayIndex\x88\x01\x01\x12,\n" +
+	"\x05value\x18\x05 \x01(\v2\x16.edgeo.bacnet.v1.ValueR\x05value\x12\x1a\n" +
+	"\bpriority\x18\x06 \x01(\rR\bpriorityB\x0e\n" +
+	"\f_array_index\"\x17\n" +
+	"\x15WritePropertyResponse\"\xa0\x01\n" +
+	"\x11PropertyReference\x129\n" +
+	"\x06object\x18\x01 \x01(\v2!.edgeo.bacnet.v1.ObjectIdentifierR\x06object\x12\x1a\n" +
+	"\bproperty\x18\x02 \x01(\rR\bproperty\x12$\n" +
+	"\varray_index\x18\x03 \x01(\rH\x00R\n" +
+	"arrayIndex\x88\x01\x01B\x0e\n" +
+	"\f_array_index\"v\n" +
+	"\x13ReadMultipleRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\rR\bdeviceId\x12B\n" +
+	"\n" +
+	"properties\x18\x02 \x03(\v2\".edgeo.bacnet.v1.PropertyReferenceR\n" +
+	"properties\"a\n" +
+	"\x05Error\x12\x1f\n" +
+	"\verror_class\x18\x01 \x01(\rR\n" +
+	"errorClass\x12\x1d\n" +
+	"\n" +
+	"error_code\x18\x02 \x01(\rR\terrorCode\x12\x18\n" +
+	"\amessage\x18\x03 \x01(\tR\amessage\"\xf9\x01\n" +
+	"\x0ePropertyResult\x129\n" +
+	"\x06object\x18\x01 \x01(\v2!.edgeo.bacnet.v1.ObjectIdentifierR\x06object\x12\x1a\n" +
+	"\bproperty\x18\x02 \x01(\rR\bproperty\x12$\n" +
+	"\varray_index\x18\x03 \x01(\rH\x00R\n" +
+	"arrayIndex\x88\x01\x01\x12,\n" +
+	"\x05value\x18\x04 \x01(\v2\x16.edgeo.bacnet.v1.ValueR\x05value\x12,\n" +
+	"\x05error\x18\x05 \x01(\v2\x16.edgeo.bacnet.v1.ErrorR\x05errorB\x0e\n" +
+	"\f_array_index\"Q\n" +
+	"\x14ReadMultipleResponse\x129\n" +
+	"\aresults\x18\x01 \x03(\v2\x1f.edgeo.bacnet.v1.PropertyResultR\aresults\"\xf5\x01\n" +
+	"\x13SubscribeCOVRequest\x12\x1b\n" +
+	"\tdevice_id\x18\x01 \x01(\rR\bdeviceId\x12;\n" +
+	"\aobjects\x18\x02 \x03(\v2!.edgeo.bacnet.v1.ObjectIdentifierR\aobjects\x125\n" +
+	"\blifetime\x18\x03 \x01(\v2\x19.google.protobuf.DurationR\blifetime\x12\x1c\n" +
+	"\tconfirmed\x18\x04 \x01(\bR\tconfirmed\x12!\n" +
+	"\tincrement\x18\x05 \x01(\x02H\x00R\tincrement\x88\x01\x01B\f\n" +
+	"\n" +
+	"_increment\"\x8f\x01\n" +
+	"\rPropertyValue\x12\x1a\n" +
+	"\bproperty\x18\x01 \x01(\rR\bproperty\x12$\n" +
+	"\varray_index\x18\x02 \x01(\rH\x00R\n" +
+	"arrayIndex\x88\x01\x01\x12,\n" +
+	"\x05value\x18\x03 \x01(\v2\x16.edgeo.bacnet.v1.ValueR\x05valueB\x0e\n" +
+	"\f_array_index\"\xd1\x01\n" +
+	"\x0fCOVNotification\x12.\n" +
+	"\x04time\x18\x01 \x01(\v2\x1a.google.protobuf.TimestampR\x04time\x12\x1b\n" +
+	"\tdevice_id\x18\x02 \x01(\rR\bdeviceId\x129\n" +
+	"\x06object\x18\x03 \x01(\v2!.edgeo.bacnet.v1.ObjectIdentifierR\x06object\x126\n" +
+	"\x06values\x18\x04 \x03(\v2\x1e.edgeo.bacnet.v1.PropertyValueR\x06values2\xcd\x03\n" +
+	"\x06BACnet\x12O\n" +
+	"\bDiscover\x12 .edgeo.bacnet.v1.DiscoverRequest\x1a!.edgeo.bacnet.v1.DiscoverResponse\x12[\n" +
+	"\fReadProperty\x12$.edgeo.bacnet.v1.ReadPropertyRequest\x1a%.edgeo.bacnet.v1.ReadPropertyResponse\x12^\n" +
+	"\rWriteProperty\x12%.edgeo.bacnet.v1.WritePropertyRequest\x1a&.edgeo.bacnet.v1.WritePropertyResponse\x12[\n" +
+	"\fReadMultiple\x12$.edgeo.bacnet.v1.ReadMultipleRequest\x1a%.edgeo.bacnet.v1.ReadMultipleResponse\x12X\n" +
+	"\fSubscribeCOV\x12$.edgeo.bacnet.v1.SubscribeCOVRequest\x1a .edgeo.bacnet.v1.COVNotification0\x01B*Z(github.com/edgeo-scada/bacnet/grpcbacnetb\x06proto3"
+
+var (
+	file_bacnet_proto_rawDescOnce sync.Once
+	file_bacnet_proto_rawDescData []byte
+)
+
+func file_bacnet_proto_rawDescGZIP() []byte {
+	file_bacnet_proto_rawDescOnce.Do(func() {
+		file_bacnet_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_bacnet_proto_rawDesc), len(file_bacnet_proto_rawDesc)))
+	})
+	return file_bacnet_proto_rawDescData
+}
+
+var file_bacnet_proto_msgTypes = make([]protoimpl.MessageInfo, 19)
+var file_bacnet_proto_goTypes = []any{
+	(*ObjectIdentifier)(nil),      // 0: edgeo.bacnet.v1.ObjectIdentifier
+	(*BitString)(nil),             // 1: edgeo.bacnet.v1.BitString
+	(*ValueList)(nil),             // 2: edgeo.bacnet.v1.ValueList
+	(*Value)(nil),                 // 3: edgeo.bacnet.v1.Value
+	(*Device)(nil),                // 4: edgeo.bacnet.v1.Device
+	(*DiscoverRequest)(nil),       // 5: edgeo.bacnet.v1.DiscoverRequest
+	(*DiscoverResponse)(nil),      // 6: edgeo.bacnet.v1.DiscoverResponse
+	(*ReadPropertyRequest)(nil),   // 7: edgeo.bacnet.v1.ReadPropertyRequest
+	(*ReadPropertyResponse)(nil),  // 8: edgeo.bacnet.v1.ReadPropertyResponse
+	(*WritePropertyRequest)(nil),  // 9: edgeo.bacnet.v1.WritePropertyRequest
+	(*WritePropertyResponse)(nil), // 10: edgeo.bacnet.v1.WritePropertyResponse
+	(*PropertyReference)(nil),     // 11: edgeo.bacnet.v1.PropertyReference
+	(*ReadMultipleRequest)(nil),   // 12: edgeo.bacnet.v1.ReadMultipleRequest
+	(*Error)(nil),                 // 13: edgeo.bacnet.v1.Error
+	(*PropertyResult)(nil),        // 14: edgeo.bacnet.v1.PropertyResult
+	(*ReadMultipleResponse)(nil),  // 15: edgeo.bacnet.v1.ReadMultipleResponse
+	(*SubscribeCOVRequest)(nil),   // 16: edgeo.bacnet.v1.SubscribeCOVRequest
+	(*PropertyValue)(nil),         // 17: edgeo.bacnet.v1.PropertyValue
+	(*COVNotification)(nil),       // 18: edgeo.bacnet.v1.COVNotification
+	(structpb.NullValue)(0),       // 19: google.protobuf.NullValue
+	(*durationpb.Duration)(nil),   // 20: google.protobuf.Duration
+	(*timestamppb.Timestamp)(nil), // 21: google.protobuf.Timestamp
+}
+var file_bacnet_proto_depIdxs = []int32{
+	3,  // 0: edgeo.bacnet.v1.ValueList.values:type_name -> edgeo.bacnet.v1.Value
+	19, // 1: edgeo.bacnet.v1.Value.null:type_name -> google.protobuf.NullValue
+	1,  // 2: edgeo.bacnet.v1.Value.bit_string:type_name -> edgeo.bacnet.v1.BitString
+	0,  // 3: edgeo.bacnet.v1.Value.object_identifier:type_name -> edgeo.bacnet.v1.ObjectIdentifier
+	2,  // 4: edgeo.bacnet.v1.Value.list:type_name -> edgeo.bacnet.v1.ValueList
+	20, // 5: edgeo.bacnet.v1.DiscoverRequest.timeout:type_name -> google.protobuf.Duration
+	4,  // 6: edgeo.bacnet.v1.DiscoverResponse.devices:type_name -> edgeo.bacnet.v1.Device
+	0,  // 7: edgeo.bacnet.v1.ReadPropertyRequest.object:type_name -> edgeo.bacnet.v1.ObjectIdentifier
+	3,  // 8: edgeo.bacnet.v1.ReadPropertyResponse.value:type_name -> edgeo.bacnet.v1.Value
+	0,  // 9: edgeo.bacnet.v1.WritePropertyRequest.object:type_name -> edgeo.bacnet.v1.ObjectIdentifier
+	3,  // 10: edgeo.bacnet.v1.WritePropertyRequest.value:type_name -> edgeo.bacnet.v1.Value
+	0,  // 11: edgeo.bacnet.v1.PropertyReference.object:type_name -> edgeo.bacnet.v1.ObjectIdentifier
+	11, // 12: edgeo.bacnet.v1.ReadMultipleRequest.properties:type_name -> edgeo.bacnet.v1.PropertyReference
+	0,  // 13: edgeo.bacnet.v1.PropertyResult.object:type_name -> edgeo.bacnet.v1.ObjectIdentifier
+	3,  // 14: edgeo.bacnet.v1.PropertyResult.value:type_name -> edgeo.bacnet.v1.Value
+	13, // 15: edgeo.bacnet.v1.PropertyResult.error:type_name -> edgeo.bacnet.v1.Error
+	14, // 16: edgeo.bacnet.v1.ReadMultipleResponse.results:type_name -> edgeo.bacnet.v1.PropertyResult
+	0,  // 17: edgeo.bacnet.v1.SubscribeCOVRequest.objects:type_name -> edgeo.bacnet.v1.ObjectIdentifier
+	20, // 18: edgeo.bacnet.v1.SubscribeCOVRequest.lifetime:type_name -> google.protobuf.Duration
+	3,  // 19: edgeo.bacnet.v1.PropertyValue.value:type_name -> edgeo.bacnet.v1.Value
+	21, // 20: edgeo.bacnet.v1.COVNotification.time:type_name -> google.protobuf.Timestamp
+	0,  // 21: edgeo.bacnet.v1.COVNotification.object:type_name -> edgeo.bacnet.v1.ObjectIdentifier
+	17, // 22: edgeo.bacnet.v1.COVNotification.values:type_name -> edgeo.bacnet.v1.PropertyValue
+	5,  // 23: edgeo.bacnet.v1.BACnet.Discover:input_type -> edgeo.bacnet.v1.DiscoverRequest
+	7,  // 24: edgeo.bacnet.v1.BACnet.ReadProperty:input_type -> edgeo.bacnet.v1.ReadPropertyRequest
+	9,  // 25: edgeo.bacnet.v1.BACnet.WriteProperty:input_type -> edgeo.bacnet.v1.WritePropertyRequest
+	12, // 26: edgeo.bacnet.v1.BACnet.ReadMultiple:input_type -> edgeo.bacnet.v1.ReadMultipleRequest
+	16, // 27: edgeo.bacnet.v1.BACnet.SubscribeCOV:input_type -> edgeo.bacnet.v1.SubscribeCOVRequest
+	6,  // 28: edgeo.bacnet.v1.BACnet.Discover:output_type -> edgeo.bacnet.v1.DiscoverResponse
+	8,  // 29: edgeo.bacnet.v1.BACnet.ReadProperty:output_type -> edgeo.bacnet.v1.ReadPropertyResponse
+	10, // 30: edgeo.bacnet.v1.BACnet.WriteProperty:output_type -> edgeo.bacnet.v1.WritePropertyResponse
+	15, // 31: edgeo.bacnet.v1.BACnet.ReadMultiple:output_type -> edgeo.bacnet.v1.ReadMultipleResponse
+	18, // 32: edgeo.bacnet.v1.BACnet.SubscribeCOV:output_type -> edgeo.bacnet.v1.COVNotification
+	28, // [28:33] is the sub-list for method output_type
+	23, // [23:28] is the sub-list for method input_type
+	23, // [23:23] is the sub-list for extension type_name
+	23, // [23:23] is the sub-list for extension extendee
+	0,  // [0:23] is the sub-list for field type_name
+}
+
+func init() { file_bacnet_proto_init() }
+func file_bacnet_proto_init() {
+	if File_bacnet_proto != nil {
+		return
+	}
+	file_bacnet_proto_msgTypes[3].OneofWrappers = []any{
+		(*Value_Null)(nil),
+		(*Value_Boolean)(nil),
+		(*Value_Unsigned)(nil),
+		(*Value_Signed)(nil),
+		(*Value_Real)(nil),
+		(*Value_Double)(nil),
+		(*Value_OctetString)(nil),
+		(*Value_CharacterString)(nil),
+		(*Value_BitString)(nil),
+		(*Value_Enumerated)(nil),
+		(*Value_ObjectIdentifier)(nil),
+		(*Value_List)(nil),
+		(*Value_Text)(nil),
+	}
+	file_bacnet_proto_msgTypes[5].OneofWrappers = []any{}
+	file_bacnet_proto_msgTypes[7].OneofWrappers = []any{}
+	file_bacnet_proto_msgTypes[9].OneofWrappers = []any{}
+	file_bacnet_proto_msgTypes[11].OneofWrappers = []any{}
+	file_bacnet_proto_msgTypes[14].OneofWrappers = []any{}
+	file_bacnet_proto_msgTypes[16].OneofWrappers = []any{}
+	file_bacnet_proto_msgTypes[17].OneofWrappers = []any{}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_bacnet_proto_rawDesc), len(file_bacnet_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   19,
+			NumExtensions: 0,
+			NumServices:   1,
+		},
+		GoTypes:           file_bacnet_proto_goTypes,
+		DependencyIndexes: file_bacnet_proto_depIdxs,
+		MessageInfos:      file_bacnet_proto_msgTypes,
+	}.Build()
+	File_bacnet_proto = out.File
+	file_bacnet_proto_goTypes = nil
+	file_bacnet_proto_depIdxs = nil
+}
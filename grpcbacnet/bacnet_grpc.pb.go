@@ -0,0 +1,307 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v5.29.3
+// source: bacnet.proto
+
+package grpcbacnet
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	BACnet_Discover_FullMethodName      = "/edgeo.bacnet.v1.BACnet/Discover"
+	BACnet_ReadProperty_FullMethodName  = "/edgeo.bacnet.v1.BACnet/ReadProperty"
+	BACnet_WriteProperty_FullMethodName = "/edgeo.bacnet.v1.BACnet/WriteProperty"
+	BACnet_ReadMultiple_FullMethodName  = "/edgeo.bacnet.v1.BACnet/ReadMultiple"
+	BACnet_SubscribeCOV_FullMethodName  = "/edgeo.bacnet.v1.BACnet/SubscribeCOV"
+)
+
+// BACnetClient is the client API for BACnet service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// BACnet gives access to the devices reachable by a BACnet client
+type BACnetClient interface {
+	// Discover finds devices with Who-Is
+	Discover(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*DiscoverResponse, error)
+	// ReadProperty reads a property of an object
+	ReadProperty(ctx context.Context, in *ReadPropertyRequest, opts ...grpc.CallOption) (*ReadPropertyResponse, error)
+	// WriteProperty writes a property of an object
+	WriteProperty(ctx context.Context, in *WritePropertyRequest, opts ...grpc.CallOption) (*WritePropertyResponse, error)
+	// ReadMultiple reads several properties of a device at once
+	ReadMultiple(ctx context.Context, in *ReadMultipleRequest, opts ...grpc.CallOption) (*ReadMultipleResponse, error)
+	// SubscribeCOV subscribes to objects of a device and streams their
+	// change-of-value notifications until the call is cancelled
+	SubscribeCOV(ctx context.Context, in *SubscribeCOVRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[COVNotification], error)
+}
+
+type bACnetClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewBACnetClient(cc grpc.ClientConnInterface) BACnetClient {
+	return &bACnetClient{cc}
+}
+
+func (c *bACnetClient) Discover(ctx context.Context, in *DiscoverRequest, opts ...grpc.CallOption) (*DiscoverResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DiscoverResponse)
+	err := c.cc.Invoke(ctx, BACnet_Discover_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bACnetClient) ReadProperty(ctx context.Context, in *ReadPropertyRequest, opts ...grpc.CallOption) (*ReadPropertyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReadPropertyResponse)
+	err := c.cc.Invoke(ctx, BACnet_ReadProperty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bACnetClient) WriteProperty(ctx context.Context, in *WritePropertyRequest, opts ...grpc.CallOption) (*WritePropertyResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(WritePropertyResponse)
+	err := c.cc.Invoke(ctx, BACnet_WriteProperty_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bACnetClient) ReadMultiple(ctx context.Context, in *ReadMultipleRequest, opts ...grpc.CallOption) (*ReadMultipleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ReadMultipleResponse)
+	err := c.cc.Invoke(ctx, BACnet_ReadMultiple_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bACnetClient) SubscribeCOV(ctx context.Context, in *SubscribeCOVRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[COVNotification], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BACnet_ServiceDesc.Streams[0], BACnet_SubscribeCOV_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeCOVRequest, COVNotification]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BACnet_SubscribeCOVClient = grpc.ServerStreamingClient[COVNotification]
+
+// BACnetServer is the server API for BACnet service.
+// All implementations must embed UnimplementedBACnetServer
+// for forward compatibility.
+//
+// BACnet gives access to the devices reachable by a BACnet client
+type BACnetServer interface {
+	// Discover finds devices with Who-Is
+	Discover(context.Context, *DiscoverRequest) (*DiscoverResponse, error)
+	// ReadProperty reads a property of an object
+	ReadProperty(context.Context, *ReadPropertyRequest) (*ReadPropertyResponse, error)
+	// WriteProperty writes a property of an object
+	WriteProperty(context.Context, *WritePropertyRequest) (*WritePropertyResponse, error)
+	// ReadMultiple reads several properties of a device at once
+	ReadMultiple(context.Context, *ReadMultipleRequest) (*ReadMultipleResponse, error)
+	// SubscribeCOV subscribes to objects of a device and streams their
+	// change-of-value notifications until the call is cancelled
+	SubscribeCOV(*SubscribeCOVRequest, grpc.ServerStreamingServer[COVNotification]) error
+	mustEmbedUnimplementedBACnetServer()
+}
+
+// UnimplementedBACnetServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedBACnetServer struct{}
+
+func (UnimplementedBACnetServer) Discover(context.Context, *DiscoverRequest) (*DiscoverResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Discover not implemented")
+}
+func (UnimplementedBACnetServer) ReadProperty(context.Context, *ReadPropertyRequest) (*ReadPropertyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadProperty not implemented")
+}
+func (UnimplementedBACnetServer) WriteProperty(context.Context, *WritePropertyRequest) (*WritePropertyResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method WriteProperty not implemented")
+}
+func (UnimplementedBACnetServer) ReadMultiple(context.Context, *ReadMultipleRequest) (*ReadMultipleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ReadMultiple not implemented")
+}
+func (UnimplementedBACnetServer) SubscribeCOV(*SubscribeCOVRequest, grpc.ServerStreamingServer[COVNotification]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeCOV not implemented")
+}
+func (UnimplementedBACnetServer) mustEmbedUnimplementedBACnetServer() {}
+func (UnimplementedBACnetServer) testEmbeddedByValue()                {}
+
+// UnsafeBACnetServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to BACnetServer will
+// result in compilation errors.
+type UnsafeBACnetServer interface {
+	mustEmbedUnimplementedBACnetServer()
+}
+
+func RegisterBACnetServer(s grpc.ServiceRegistrar, srv BACnetServer) {
+	// If the following call pancis, it indicates UnimplementedBACnetServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&BACnet_ServiceDesc, srv)
+}
+
+func _BACnet_Discover_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DiscoverRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BACnetServer).Discover(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BACnet_Discover_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BACnetServer).Discover(ctx, req.(*DiscoverRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BACnet_ReadProperty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadPropertyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BACnetServer).ReadProperty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BACnet_ReadProperty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BACnetServer).ReadProperty(ctx, req.(*ReadPropertyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BACnet_WriteProperty_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(WritePropertyRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BACnetServer).WriteProperty(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BACnet_WriteProperty_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BACnetServer).WriteProperty(ctx, req.(*WritePropertyRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BACnet_ReadMultiple_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ReadMultipleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BACnetServer).ReadMultiple(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BACnet_ReadMultiple_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BACnetServer).ReadMultiple(ctx, req.(*ReadMultipleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BACnet_SubscribeCOV_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeCOVRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BACnetServer).SubscribeCOV(m, &grpc.GenericServerStream[SubscribeCOVRequest, COVNotification]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BACnet_SubscribeCOVServer = grpc.ServerStreamingServer[COVNotification]
+
+// BACnet_ServiceDesc is the grpc.ServiceDesc for BACnet service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var BACnet_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "edgeo.bacnet.v1.BACnet",
+	HandlerType: (*BACnetServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "Discover",
+			Handler:    _BACnet_Discover_Handler,
+		},
+		{
+			MethodName: "ReadProperty",
+			Handler:    _BACnet_ReadProperty_Handler,
+		},
+		{
+			MethodName: "WriteProperty",
+			Handler:    _BACnet_WriteProperty_Handler,
+		},
+		{
+			MethodName: "ReadMultiple",
+			Handler:    _BACnet_ReadMultiple_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeCOV",
+			Handler:       _BACnet_SubscribeCOV_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "bacnet.proto",
+}
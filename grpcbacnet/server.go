@@ -0,0 +1,460 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package grpcbacnet serves a bacnet client over gRPC, so that services
+// without a BACnet stack can discover devices, read and write properties and
+// stream COV notifications.
+//
+// The service is defined in bacnet.proto:
+//
+//	server := grpc.NewServer()
+//	grpcbacnet.RegisterBACnetServer(server, grpcbacnet.NewServer(client))
+//	server.Serve(listener)
+package grpcbacnet
+
+//go:generate protoc --go_out=. --go_opt=paths=source_relative --go-grpc_out=. --go-grpc_opt=paths=source_relative bacnet.proto
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+// siteHeader is the response header carrying the site of the client, set
+// with bacnet.WithSite, so that callers of several gateways can tell the
+// devices of each site apart
+const siteHeader = "bacnet-site"
+
+// notificationBuffer is the number of notifications a COV stream holds for
+// a slow consumer before it is ended
+const notificationBuffer = 64
+
+type config struct {
+	discoveryTimeout time.Duration
+	covLifetime      time.Duration
+	requestTimeout   time.Duration
+}
+
+// Option configures a Server
+type Option func(*config)
+
+// WithDiscoveryTimeout sets the time Discover waits for I-Am replies when
+// the request sets none
+func WithDiscoveryTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.discoveryTimeout = d
+	}
+}
+
+// WithCOVLifetime sets the lifetime of the subscriptions of SubscribeCOV
+// when the request sets none
+func WithCOVLifetime(d time.Duration) Option {
+	return func(c *config) {
+		c.covLifetime = d
+	}
+}
+
+// WithRequestTimeout sets the time allowed to the renewals and
+// cancellations of COV subscriptions, which outlive no call
+func WithRequestTimeout(d time.Duration) Option {
+	return func(c *config) {
+		c.requestTimeout = d
+	}
+}
+
+// Server implements BACnetServer with a bacnet client
+type Server struct {
+	UnimplementedBACnetServer
+
+	client *bacnet.Client
+	cfg    config
+}
+
+// NewServer creates a server sharing a connected client between all calls
+func NewServer(client *bacnet.Client, opts ...Option) *Server {
+	cfg := config{
+		discoveryTimeout: 3 * time.Second,
+		covLifetime:      5 * time.Minute,
+		requestTimeout:   10 * time.Second,
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	return &Server{client: client, cfg: cfg}
+}
+
+// Discover finds devices with Who-Is
+func (s *Server) Discover(ctx context.Context, req *DiscoverRequest) (*DiscoverResponse, error) {
+	s.sendSite(ctx)
+
+	wait := s.cfg.discoveryTimeout
+	if req.Timeout != nil {
+		wait = req.Timeout.AsDuration()
+	}
+
+	opts := []bacnet.DiscoverOption{bacnet.WithDiscoveryTimeout(wait)}
+	if req.LowLimit != nil || req.HighLimit != nil {
+		high := uint32(0x3FFFFF)
+		if req.HighLimit != nil {
+			high = req.GetHighLimit()
+		}
+		opts = append(opts, bacnet.WithDeviceRange(req.GetLowLimit(), high))
+	}
+
+	devices, err := s.client.WhoIs(ctx, opts...)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &DiscoverResponse{Devices: make([]*Device, 0, len(devices))}
+	for _, dev := range devices {
+		device := &Device{
+			Instance:      dev.ObjectID.Instance,
+			MaxApduLength: uint32(dev.MaxAPDULength),
+			Segmentation:  uint32(dev.Segmentation),
+			VendorId:      uint32(dev.VendorID),
+		}
+		if addr, err := s.client.DeviceAddress(ctx, dev.ObjectID.Instance); err == nil {
+			device.Address = addr.String()
+		}
+		resp.Devices = append(resp.Devices, device)
+	}
+	return resp, nil
+}
+
+// ReadProperty reads a property of an object
+func (s *Server) ReadProperty(ctx context.Context, req *ReadPropertyRequest) (*ReadPropertyResponse, error) {
+	s.sendSite(ctx)
+
+	var opts []bacnet.ReadOption
+	if req.ArrayIndex != nil {
+		opts = append(opts, bacnet.WithArrayIndex(req.GetArrayIndex()))
+	}
+
+	value, err := s.client.ReadProperty(ctx, req.DeviceId, objectID(req.Object),
+		bacnet.PropertyIdentifier(req.Property), opts...)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &ReadPropertyResponse{Value: newValue(value)}, nil
+}
+
+// WriteProperty writes a property of an object
+func (s *Server) WriteProperty(ctx context.Context, req *WritePropertyRequest) (*WritePropertyResponse, error) {
+	s.sendSite(ctx)
+
+	if req.Priority > 16 {
+		return nil, status.Errorf(codes.InvalidArgument, "priority %d out of range 1-16", req.Priority)
+	}
+	value, err := req.Value.bacnetValue()
+	if err != nil {
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+
+	var opts []bacnet.WriteOption
+	if req.Priority > 0 {
+		opts = append(opts, bacnet.WithPriority(uint8(req.Priority)))
+	}
+	if req.ArrayIndex != nil {
+		opts = append(opts, bacnet.WithWriteArrayIndex(req.GetArrayIndex()))
+	}
+
+	err = s.client.WriteProperty(ctx, req.DeviceId, objectID(req.Object),
+		bacnet.PropertyIdentifier(req.Property), value, opts...)
+	if err != nil {
+		return nil, statusError(err)
+	}
+	return &WritePropertyResponse{}, nil
+}
+
+// ReadMultiple reads several properties of a device at once. Properties
+// that cannot be read carry their error rather than failing the call.
+func (s *Server) ReadMultiple(ctx context.Context, req *ReadMultipleRequest) (*ReadMultipleResponse, error) {
+	s.sendSite(ctx)
+
+	requests := make([]bacnet.ReadPropertyRequest, 0, len(req.Properties))
+	for _, ref := range req.Properties {
+		requests = append(requests, bacnet.ReadPropertyRequest{
+			ObjectID:   objectID(ref.Object),
+			PropertyID: bacnet.PropertyIdentifier(ref.Property),
+			ArrayIndex: ref.ArrayIndex,
+		})
+	}
+
+	results, err := s.client.Device(req.DeviceId).ReadMultiple(ctx, requests)
+	if err != nil {
+		return nil, statusError(err)
+	}
+
+	resp := &ReadMultipleResponse{Results: make([]*PropertyResult, 0, len(results))}
+	for _, r := range results {
+		result := &PropertyResult{
+			Object:     newObjectIdentifier(r.ObjectID),
+			Property:   uint32(r.PropertyID),
+			ArrayIndex: r.ArrayIndex,
+		}
+		if r.Err != nil {
+			result.Error = newError(r.Err)
+		} else {
+			result.Value = newValue(r.Value)
+		}
+		resp.Results = append(resp.Results, result)
+	}
+	return resp, nil
+}
+
+// SubscribeCOV subscribes to objects of a device and streams their
+// notifications. The subscriptions are renewed at half their lifetime and
+// cancelled when the call ends. With an increment, the present values are
+// subscribed to with SubscribeCOVProperty.
+func (s *Server) SubscribeCOV(req *SubscribeCOVRequest, stream grpc.ServerStreamingServer[COVNotification]) error {
+	if site := s.client.Site(); site != "" {
+		stream.SetHeader(metadata.Pairs(siteHeader, site))
+	}
+	if len(req.Objects) == 0 {
+		return status.Error(codes.InvalidArgument, "no objects to subscribe to")
+	}
+	lifetime := s.cfg.covLifetime
+	if req.Lifetime != nil {
+		lifetime = req.Lifetime.AsDuration()
+	}
+
+	subOpts := []bacnet.SubscribeOption{
+		bacnet.WithConfirmedNotifications(req.Confirmed),
+		bacnet.WithSubscriptionLifetime(uint32(lifetime / time.Second)),
+	}
+	if req.Increment != nil {
+		increment := req.GetIncrement()
+		if !(increment >= 0) || math.IsInf(float64(increment), 0) {
+			return status.Errorf(codes.InvalidArgument, "invalid increment %v", increment)
+		}
+		subOpts = append(subOpts, bacnet.WithCOVIncrement(increment))
+	}
+
+	ctx := stream.Context()
+	notifications := make(chan *COVNotification, notificationBuffer)
+	overflow := make(chan struct{})
+	var overflowOnce sync.Once
+
+	// Notifications arrive on the receive goroutine of the client, which must
+	// not block on a slow stream
+	notify := func(devID uint32, oid bacnet.ObjectIdentifier, values []bacnet.PropertyValue) {
+		n := &COVNotification{
+			Time:     timestamppb.Now(),
+			DeviceId: devID,
+			Object:   newObjectIdentifier(oid),
+			Values:   make([]*PropertyValue, 0, len(values)),
+		}
+		for _, pv := range values {
+			n.Values = append(n.Values, &PropertyValue{
+				Property:   uint32(pv.PropertyID),
+				ArrayIndex: pv.ArrayIndex,
+				Value:      newValue(pv.Value),
+			})
+		}
+		select {
+		case notifications <- n:
+		default:
+			overflowOnce.Do(func() { close(overflow) })
+		}
+	}
+
+	subs := make(map[bacnet.ObjectIdentifier]uint32, len(req.Objects))
+	defer func() {
+		unsubCtx, cancel := context.WithTimeout(context.Background(), s.cfg.requestTimeout)
+		defer cancel()
+		for oid, subID := range subs {
+			s.client.UnsubscribeCOV(unsubCtx, req.DeviceId, oid, subID)
+		}
+	}()
+
+	for _, o := range req.Objects {
+		oid := objectID(o)
+		subID, err := s.client.SubscribeCOV(ctx, req.DeviceId, oid, notify, subOpts...)
+		if err != nil {
+			return statusError(fmt.Errorf("subscribe to %s: %w", oid, err))
+		}
+		subs[oid] = subID
+	}
+
+	// Without a lifetime there is nothing to renew
+	var renew <-chan time.Time
+	if lifetime > 0 {
+		ticker := time.NewTicker(lifetime / 2)
+		defer ticker.Stop()
+		renew = ticker.C
+	}
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-overflow:
+			return status.Error(codes.ResourceExhausted, "notifications are not consumed fast enough")
+		case n := <-notifications:
+			if err := stream.Send(n); err != nil {
+				return err
+			}
+		case <-renew:
+			for oid, subID := range subs {
+				renewCtx, cancel := context.WithTimeout(ctx, s.cfg.requestTimeout)
+				err := s.client.RenewCOV(renewCtx, req.DeviceId, oid, subID, subOpts...)
+				cancel()
+				if err != nil && ctx.Err() == nil {
+					return statusError(fmt.Errorf("renew %s: %w", oid, err))
+				}
+			}
+		}
+	}
+}
+
+// objectID converts an object identifier of a request
+// sendSite sets the site header of a unary call when the client has a site
+func (s *Server) sendSite(ctx context.Context) {
+	if site := s.client.Site(); site != "" {
+		grpc.SetHeader(ctx, metadata.Pairs(siteHeader, site))
+	}
+}
+
+func objectID(o *ObjectIdentifier) bacnet.ObjectIdentifier {
+	return bacnet.NewObjectIdentifier(bacnet.ObjectType(o.GetType()), o.GetInstance())
+}
+
+func newObjectIdentifier(oid bacnet.ObjectIdentifier) *ObjectIdentifier {
+	return &ObjectIdentifier{Type: uint32(oid.Type), Instance: oid.Instance}
+}
+
+// newValue converts a value decoded by the client. Enumerated values decode
+// as unsigned integers.
+func newValue(value interface{}) *Value {
+	switch v := value.(type) {
+	case nil:
+		return &Value{Kind: &Value_Null{Null: structpb.NullValue_NULL_VALUE}}
+	case bool:
+		return &Value{Kind: &Value_Boolean{Boolean: v}}
+	case uint32:
+		return &Value{Kind: &Value_Unsigned{Unsigned: v}}
+	case int32:
+		return &Value{Kind: &Value_Signed{Signed: v}}
+	case float32:
+		return &Value{Kind: &Value_Real{Real: v}}
+	case float64:
+		return &Value{Kind: &Value_Double{Double: v}}
+	case []byte:
+		return &Value{Kind: &Value_OctetString{OctetString: v}}
+	case string:
+		return &Value{Kind: &Value_CharacterString{CharacterString: v}}
+	case bacnet.BitString:
+		return &Value{Kind: &Value_BitString{BitString: &BitString{Length: uint32(v.Length), Bits: v.Bits}}}
+	case bacnet.Enumerated:
+		return &Value{Kind: &Value_Enumerated{Enumerated: uint32(v)}}
+	case bacnet.ObjectIdentifier:
+		return &Value{Kind: &Value_ObjectIdentifier{ObjectIdentifier: newObjectIdentifier(v)}}
+	case []interface{}:
+		list := &ValueList{Values: make([]*Value, len(v))}
+		for i, item := range v {
+			list.Values[i] = newValue(item)
+		}
+		return &Value{Kind: &Value_List{List: list}}
+	case fmt.Stringer:
+		return &Value{Kind: &Value_Text{Text: v.String()}}
+	default:
+		return &Value{Kind: &Value_Text{Text: fmt.Sprint(v)}}
+	}
+}
+
+// bacnetValue converts the value of a write
+func (v *Value) bacnetValue() (interface{}, error) {
+	switch k := v.GetKind().(type) {
+	case nil:
+		return nil, errors.New("value is required")
+	case *Value_Null:
+		return nil, nil
+	case *Value_Boolean:
+		return k.Boolean, nil
+	case *Value_Unsigned:
+		return k.Unsigned, nil
+	case *Value_Signed:
+		return k.Signed, nil
+	case *Value_Real:
+		return k.Real, nil
+	case *Value_Double:
+		return k.Double, nil
+	case *Value_OctetString:
+		return k.OctetString, nil
+	case *Value_CharacterString:
+		return k.CharacterString, nil
+	case *Value_BitString:
+		return bacnet.BitString{Length: int(k.BitString.GetLength()), Bits: k.BitString.GetBits()}, nil
+	case *Value_Enumerated:
+		return bacnet.Enumerated(k.Enumerated), nil
+	case *Value_ObjectIdentifier:
+		return objectID(k.ObjectIdentifier), nil
+	default:
+		return nil, fmt.Errorf("%T values cannot be written", k)
+	}
+}
+
+// newError converts the access error of a property
+func newError(err error) *Error {
+	var bacnetErr *bacnet.BACnetError
+	if errors.As(err, &bacnetErr) {
+		return &Error{
+			ErrorClass: uint32(bacnetErr.Class),
+			ErrorCode:  uint32(bacnetErr.Code),
+			Message:    err.Error(),
+		}
+	}
+	return &Error{Message: err.Error()}
+}
+
+// statusError converts a client error to the gRPC status of its cause
+func statusError(err error) error {
+	var bacnetErr *bacnet.BACnetError
+	var rejectErr *bacnet.RejectError
+	var abortErr *bacnet.AbortError
+
+	switch {
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+		return status.FromContextError(err).Err()
+	case bacnet.IsDeviceNotFound(err), bacnet.IsPropertyNotFound(err):
+		return status.Error(codes.NotFound, err.Error())
+	case bacnet.IsTimeout(err):
+		return status.Error(codes.DeadlineExceeded, err.Error())
+	case bacnet.IsAccessDenied(err):
+		return status.Error(codes.PermissionDenied, err.Error())
+	case errors.Is(err, bacnet.ErrStandby), errors.Is(err, bacnet.ErrNotConnected):
+		return status.Error(codes.Unavailable, err.Error())
+	case errors.As(err, &rejectErr):
+		return status.Error(codes.InvalidArgument, err.Error())
+	case errors.As(err, &abortErr):
+		return status.Error(codes.Aborted, err.Error())
+	case errors.As(err, &bacnetErr):
+		return status.Error(codes.FailedPrecondition, err.Error())
+	default:
+		return status.Error(codes.Internal, err.Error())
+	}
+}
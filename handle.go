@@ -0,0 +1,212 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"net"
+	"sync"
+	"time"
+)
+
+// DeviceHandle gives access to one remote device without passing its
+// instance number to every call. It carries per-device defaults set with
+// HandleOptions and is safe for concurrent use.
+type DeviceHandle struct {
+	client   *Client
+	deviceID uint32
+	opts     handleOptions
+
+	mu   sync.Mutex
+	addr net.Addr
+}
+
+// Device returns a handle on a remote device. The device is resolved on the
+// first request.
+func (c *Client) Device(deviceID uint32, opts ...HandleOption) *DeviceHandle {
+	h := &DeviceHandle{client: c, deviceID: deviceID}
+	for _, opt := range opts {
+		opt(&h.opts)
+	}
+	return h
+}
+
+// ID returns the device instance number
+func (h *DeviceHandle) ID() uint32 {
+	return h.deviceID
+}
+
+// Read reads a property of an object of the device
+func (h *DeviceHandle) Read(ctx context.Context, objectID ObjectIdentifier, propertyID PropertyIdentifier, opts ...ReadOption) (interface{}, error) {
+	ctx, cancel, err := h.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return h.client.ReadProperty(ctx, h.deviceID, objectID, propertyID, opts...)
+}
+
+// Write writes a property of an object of the device
+func (h *DeviceHandle) Write(ctx context.Context, objectID ObjectIdentifier, propertyID PropertyIdentifier, value interface{}, opts ...WriteOption) error {
+	ctx, cancel, err := h.context(ctx)
+	if err != nil {
+		return err
+	}
+	defer cancel()
+	return h.client.WriteProperty(ctx, h.deviceID, objectID, propertyID, value, opts...)
+}
+
+// ReadMultiple reads several properties, with ReadPropertyMultiple requests
+// sized for the device or, if it lacks the service, one property at a time
+func (h *DeviceHandle) ReadMultiple(ctx context.Context, requests []ReadPropertyRequest) ([]PropertyResult, error) {
+	ctx, cancel, err := h.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	addr := boundAddr(ctx, h.deviceID)
+
+	if h.supportsRPM() {
+		maxAPDU := h.opts.maxAPDU
+		if maxAPDU == 0 {
+			maxAPDU = h.client.deviceMaxAPDU(h.deviceID)
+		}
+
+		var results []PropertyResult
+		for _, batch := range splitReadAccessSpecs(requests, maxAPDU) {
+			batchResults, err := h.client.readPropertyBatch(ctx, h.deviceID, addr, batch)
+			results = append(results, batchResults...)
+			if err == nil {
+				continue
+			}
+			if len(results) > 0 || !serviceUnsupported(err) {
+				return results, err
+			}
+			h.setRPM(false)
+			break
+		}
+		if h.supportsRPM() {
+			return results, nil
+		}
+	}
+
+	return h.client.readEach(ctx, h.deviceID, requests)
+}
+
+// Subscribe subscribes to COV notifications of an object of the device
+func (h *DeviceHandle) Subscribe(ctx context.Context, objectID ObjectIdentifier, handler COVHandler, opts ...SubscribeOption) (uint32, error) {
+	ctx, cancel, err := h.context(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+	return h.client.SubscribeCOV(ctx, h.deviceID, objectID, handler, opts...)
+}
+
+// ObjectList returns the objects of the device
+func (h *DeviceHandle) ObjectList(ctx context.Context) ([]ObjectIdentifier, error) {
+	ctx, cancel, err := h.context(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer cancel()
+	return h.client.GetObjectList(ctx, h.deviceID)
+}
+
+// Ping checks that the device is alive and returns the round-trip time
+func (h *DeviceHandle) Ping(ctx context.Context) (time.Duration, error) {
+	ctx, cancel, err := h.context(ctx)
+	if err != nil {
+		return 0, err
+	}
+	defer cancel()
+	return h.client.Ping(ctx, h.deviceID)
+}
+
+// context applies the handle timeout to ctx and binds the device address
+// to it, so the client methods send to that address without resolving the
+// device again
+func (h *DeviceHandle) context(ctx context.Context) (context.Context, context.CancelFunc, error) {
+	cancel := func() {}
+	if h.opts.timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, h.opts.timeout)
+	}
+	addr, err := h.bind(ctx)
+	if err != nil {
+		cancel()
+		return nil, nil, err
+	}
+	return withBoundAddr(ctx, h.deviceID, addr), cancel, nil
+}
+
+// bind resolves the device address once. Discovery runs without holding
+// the lock; if two callers race, the first address stored wins.
+func (h *DeviceHandle) bind(ctx context.Context) (net.Addr, error) {
+	h.mu.Lock()
+	addr := h.addr
+	h.mu.Unlock()
+	if addr != nil {
+		return addr, nil
+	}
+
+	addr, err := h.client.resolveDevice(ctx, h.deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	if h.addr == nil {
+		h.addr = addr
+	}
+	return h.addr, nil
+}
+
+// boundAddrKey is the context key of an address bound by a DeviceHandle
+type boundAddrKey struct{}
+
+// boundDevice is a device address carried by a context
+type boundDevice struct {
+	deviceID uint32
+	addr     net.Addr
+}
+
+// withBoundAddr returns a context that resolves deviceID to addr
+func withBoundAddr(ctx context.Context, deviceID uint32, addr net.Addr) context.Context {
+	return context.WithValue(ctx, boundAddrKey{}, boundDevice{deviceID: deviceID, addr: addr})
+}
+
+// boundAddr returns the address bound to deviceID in ctx, or nil
+func boundAddr(ctx context.Context, deviceID uint32) net.Addr {
+	if bound, ok := ctx.Value(boundAddrKey{}).(boundDevice); ok && bound.deviceID == deviceID {
+		return bound.addr
+	}
+	return nil
+}
+
+// supportsRPM returns true unless the device is known to lack
+// ReadPropertyMultiple
+func (h *DeviceHandle) supportsRPM() bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.opts.rpm == nil || *h.opts.rpm
+}
+
+// setRPM records whether the device executes ReadPropertyMultiple
+func (h *DeviceHandle) setRPM(supported bool) {
+	h.mu.Lock()
+	h.opts.rpm = &supported
+	h.mu.Unlock()
+}
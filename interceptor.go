@@ -0,0 +1,68 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"net"
+)
+
+// Request is an outgoing confirmed request as seen by interceptors
+type Request struct {
+	// DeviceID is the instance of the target device, or 4194303 when the
+	// request does not go to a device known by instance, such as a
+	// confirmed COV notification to a subscriber
+	DeviceID uint32
+	Address  net.Addr
+	Service  ConfirmedServiceChoice
+	Priority NPDUControl
+
+	// Data is the encoded service request, without the APDU header
+	Data []byte
+
+	// InvokeID is set when the request is sent
+	InvokeID uint8
+}
+
+// Invoker sends a confirmed request and returns the acknowledgement
+type Invoker func(ctx context.Context, req *Request) (*APDU, error)
+
+// Interceptor wraps the sending of confirmed requests. It may inspect or
+// modify the request, delay or refuse it, and observe the response, its
+// duration and the error; it calls next to send the request on.
+type Interceptor func(ctx context.Context, req *Request, next Invoker) (*APDU, error)
+
+// Use adds interceptors to the confirmed requests of the client. The first
+// interceptor added is the outermost.
+func (c *Client) Use(interceptors ...Interceptor) {
+	c.interceptorsMu.Lock()
+	defer c.interceptorsMu.Unlock()
+	c.interceptors = append(c.interceptors, interceptors...)
+}
+
+// intercept wraps invoke in the interceptors of the client
+func (c *Client) intercept(invoke Invoker) Invoker {
+	c.interceptorsMu.RLock()
+	interceptors := c.interceptors
+	c.interceptorsMu.RUnlock()
+
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, next := interceptors[i], invoke
+		invoke = func(ctx context.Context, req *Request) (*APDU, error) {
+			return interceptor(ctx, req, next)
+		}
+	}
+	return invoke
+}
@@ -0,0 +1,39 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package ethernet provides raw Ethernet frame access for the BACnet
+// Ethernet (ISO 8802-3) data link
+package ethernet
+
+import (
+	"errors"
+	"net"
+	"os"
+)
+
+// ErrUnsupported is returned on platforms without raw Ethernet support
+var ErrUnsupported = errors.New("raw Ethernet is not supported on this platform")
+
+// Open opens a raw socket on the named interface and returns it with the
+// interface hardware address. Reads return whole frames starting with the
+// Ethernet header and writes take whole frames; the file supports read
+// deadlines.
+//
+// The socket normally receives IEEE 802.2 LLC frames only. In promiscuous
+// mode it captures every frame on the interface, including frames to other
+// stations and those some drivers do not classify as LLC, and the caller
+// filters them.
+func Open(name string, promiscuous bool) (*os.File, net.HardwareAddr, error) {
+	return open(name, promiscuous)
+}
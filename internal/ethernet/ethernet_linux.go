@@ -0,0 +1,67 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build linux
+
+package ethernet
+
+import (
+	"fmt"
+	"net"
+	"os"
+
+	"golang.org/x/sys/unix"
+)
+
+// htons converts a protocol number to network byte order
+func htons(v uint16) uint16 {
+	return v<<8 | v>>8
+}
+
+func open(name string, promiscuous bool) (*os.File, net.HardwareAddr, error) {
+	iface, err := net.InterfaceByName(name)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(iface.HardwareAddr) != 6 {
+		return nil, nil, fmt.Errorf("%s is not an Ethernet interface", name)
+	}
+
+	proto := uint16(unix.ETH_P_802_2)
+	if promiscuous {
+		proto = unix.ETH_P_ALL
+	}
+
+	fd, err := unix.Socket(unix.AF_PACKET, unix.SOCK_RAW|unix.SOCK_NONBLOCK|unix.SOCK_CLOEXEC, int(htons(proto)))
+	if err != nil {
+		return nil, nil, fmt.Errorf("open raw socket: %w", err)
+	}
+
+	if err := unix.Bind(fd, &unix.SockaddrLinklayer{Protocol: htons(proto), Ifindex: iface.Index}); err != nil {
+		unix.Close(fd)
+		return nil, nil, fmt.Errorf("bind to %s: %w", name, err)
+	}
+
+	if promiscuous {
+		mreq := &unix.PacketMreq{Ifindex: int32(iface.Index), Type: unix.PACKET_MR_PROMISC}
+		if err := unix.SetsockoptPacketMreq(fd, unix.SOL_PACKET, unix.PACKET_ADD_MEMBERSHIP, mreq); err != nil {
+			unix.Close(fd)
+			return nil, nil, fmt.Errorf("enable promiscuous mode on %s: %w", name, err)
+		}
+	}
+
+	// The descriptor is non-blocking, so the runtime poller serves reads
+	// and deadlines
+	return os.NewFile(uintptr(fd), name), iface.HardwareAddr, nil
+}
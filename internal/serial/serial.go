@@ -0,0 +1,30 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package serial provides raw serial port access for the MS/TP data link
+package serial
+
+import (
+	"errors"
+	"os"
+)
+
+// ErrUnsupported is returned on platforms without serial port support
+var ErrUnsupported = errors.New("serial ports are not supported on this platform")
+
+// Open opens a serial port in raw 8N1 mode at the given baud rate. The
+// returned file supports read deadlines.
+func Open(name string, baud int) (*os.File, error) {
+	return open(name, baud)
+}
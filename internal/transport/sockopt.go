@@ -0,0 +1,58 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"fmt"
+	"syscall"
+)
+
+// SocketOptions are socket-level settings of a UDP transport
+type SocketOptions struct {
+	// ReuseAddr sets SO_REUSEADDR
+	ReuseAddr bool
+
+	// ReusePort sets SO_REUSEPORT so that several sockets, e.g. of another
+	// BACnet stack, can bind the same port
+	ReusePort bool
+
+	// Interface binds the socket to a network interface by name
+	Interface string
+
+	// DSCP is the Differentiated Services code point (0-63) of sent
+	// datagrams
+	DSCP uint8
+}
+
+// isZero returns true if no option is set
+func (o SocketOptions) isZero() bool {
+	return o == SocketOptions{}
+}
+
+// control applies the options to a socket before it is bound
+func (o SocketOptions) control(network string, c syscall.RawConn) error {
+	if o.DSCP > 63 {
+		return fmt.Errorf("DSCP %d out of range 0-63", o.DSCP)
+	}
+
+	var sockErr error
+	err := c.Control(func(fd uintptr) {
+		sockErr = o.apply(network, fd)
+	})
+	if err != nil {
+		return err
+	}
+	return sockErr
+}
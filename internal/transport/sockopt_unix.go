@@ -0,0 +1,59 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+//go:build unix
+
+package transport
+
+import (
+	"fmt"
+	"strings"
+
+	"golang.org/x/sys/unix"
+)
+
+func (o SocketOptions) apply(network string, fd uintptr) error {
+	s := int(fd)
+
+	if o.ReuseAddr {
+		if err := unix.SetsockoptInt(s, unix.SOL_SOCKET, unix.SO_REUSEADDR, 1); err != nil {
+			return fmt.Errorf("set SO_REUSEADDR: %w", err)
+		}
+	}
+	if o.ReusePort {
+		if err := unix.SetsockoptInt(s, unix.SOL_SOCKET, unix.SO_REUSEPORT, 1); err != nil {
+			return fmt.Errorf("set SO_REUSEPORT: %w", err)
+		}
+	}
+	if o.Interface != "" {
+		if err := bindToDevice(s, o.Interface); err != nil {
+			return fmt.Errorf("bind to interface %s: %w", o.Interface, err)
+		}
+	}
+	if o.DSCP > 0 {
+		// DSCP is the upper six bits of the TOS / traffic class octet
+		tos := int(o.DSCP) << 2
+		var err error
+		if strings.HasSuffix(network, "6") {
+			err = unix.SetsockoptInt(s, unix.IPPROTO_IPV6, unix.IPV6_TCLASS, tos)
+		} else {
+			err = unix.SetsockoptInt(s, unix.IPPROTO_IP, unix.IP_TOS, tos)
+		}
+		if err != nil {
+			return fmt.Errorf("set DSCP: %w", err)
+		}
+	}
+
+	return nil
+}
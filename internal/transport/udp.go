@@ -19,29 +19,60 @@ import (
 	"context"
 	"fmt"
 	"net"
+	"net/netip"
 	"sync"
+	"syscall"
 	"time"
 )
 
 // UDPTransport implements BACnet/IP transport over UDP
 type UDPTransport struct {
+	network      string
 	localAddr    string
+	iface        *net.Interface
+	group        *net.UDPAddr
 	conn         *net.UDPConn
 	mu           sync.RWMutex
 	readTimeout  time.Duration
 	writeTimeout time.Duration
+	sockOpts     SocketOptions
 	closed       bool
+	capture      CaptureFunc
+
+	// addrs caches the addresses of recent peers so that steady-state
+	// receives do not allocate
+	addrsMu sync.Mutex
+	addrs   map[netip.AddrPort]*net.UDPAddr
 }
 
+// CaptureFunc observes every datagram sent or received, with the local and
+// remote addresses. data is only valid during the call.
+type CaptureFunc func(sent bool, local, remote *net.UDPAddr, data []byte)
+
+// maxCachedAddrs bounds the peer address cache
+const maxCachedAddrs = 1024
+
 // NewUDPTransport creates a new UDP transport
 func NewUDPTransport(localAddr string) *UDPTransport {
 	return &UDPTransport{
+		network:      "udp4",
 		localAddr:    localAddr,
 		readTimeout:  3 * time.Second,
 		writeTimeout: 3 * time.Second,
 	}
 }
 
+// NewUDP6Transport creates a new IPv6 UDP transport that also receives
+// datagrams sent to the multicast group on iface (nil for the default
+// interface)
+func NewUDP6Transport(localAddr string, iface *net.Interface, group *net.UDPAddr) *UDPTransport {
+	t := NewUDPTransport(localAddr)
+	t.network = "udp6"
+	t.iface = iface
+	t.group = group
+	return t
+}
+
 // SetReadTimeout sets the read timeout
 func (t *UDPTransport) SetReadTimeout(d time.Duration) {
 	t.mu.Lock()
@@ -56,6 +87,21 @@ func (t *UDPTransport) SetWriteTimeout(d time.Duration) {
 	t.mu.Unlock()
 }
 
+// SetSocketOptions sets the socket options applied when the transport is
+// opened
+func (t *UDPTransport) SetSocketOptions(opts SocketOptions) {
+	t.mu.Lock()
+	t.sockOpts = opts
+	t.mu.Unlock()
+}
+
+// SetCapture sets the function that observes every datagram
+func (t *UDPTransport) SetCapture(fn CaptureFunc) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	t.capture = fn
+}
+
 // Open opens the UDP connection
 func (t *UDPTransport) Open(ctx context.Context) error {
 	t.mu.Lock()
@@ -69,13 +115,41 @@ func (t *UDPTransport) Open(ctx context.Context) error {
 	var err error
 
 	if t.localAddr != "" {
-		addr, err = net.ResolveUDPAddr("udp4", t.localAddr)
+		addr, err = net.ResolveUDPAddr(t.network, t.localAddr)
 		if err != nil {
 			return fmt.Errorf("resolve local address: %w", err)
 		}
 	}
 
-	conn, err := net.ListenUDP("udp4", addr)
+	var conn *net.UDPConn
+	switch {
+	case t.group != nil:
+		// Listens on all addresses of the group port, including the group.
+		// The socket is already bound, so options are applied afterwards.
+		conn, err = net.ListenMulticastUDP(t.network, t.iface, t.group)
+		if err == nil && !t.sockOpts.isZero() {
+			var raw syscall.RawConn
+			if raw, err = conn.SyscallConn(); err == nil {
+				err = t.sockOpts.control(t.network, raw)
+			}
+			if err != nil {
+				conn.Close()
+			}
+		}
+	case !t.sockOpts.isZero():
+		lc := net.ListenConfig{
+			Control: func(network, address string, c syscall.RawConn) error {
+				return t.sockOpts.control(network, c)
+			},
+		}
+		var pc net.PacketConn
+		pc, err = lc.ListenPacket(ctx, t.network, t.localAddr)
+		if err == nil {
+			conn = pc.(*net.UDPConn)
+		}
+	default:
+		conn, err = net.ListenUDP(t.network, addr)
+	}
 	if err != nil {
 		return fmt.Errorf("listen UDP: %w", err)
 	}
@@ -114,6 +188,7 @@ func (t *UDPTransport) Send(ctx context.Context, addr *net.UDPAddr, data []byte)
 	t.mu.RLock()
 	conn := t.conn
 	writeTimeout := t.writeTimeout
+	capture := t.capture
 	t.mu.RUnlock()
 
 	if conn == nil {
@@ -137,6 +212,10 @@ func (t *UDPTransport) Send(ctx context.Context, addr *net.UDPAddr, data []byte)
 		return fmt.Errorf("partial write: %d of %d bytes", n, len(data))
 	}
 
+	if capture != nil {
+		local, _ := conn.LocalAddr().(*net.UDPAddr)
+		capture(true, local, addr, data)
+	}
 	return nil
 }
 
@@ -154,6 +233,7 @@ func (t *UDPTransport) Receive(ctx context.Context) ([]byte, *net.UDPAddr, error
 	t.mu.RLock()
 	conn := t.conn
 	readTimeout := t.readTimeout
+	capture := t.capture
 	t.mu.RUnlock()
 
 	if conn == nil {
@@ -175,6 +255,10 @@ func (t *UDPTransport) Receive(ctx context.Context) ([]byte, *net.UDPAddr, error
 		return nil, nil, err
 	}
 
+	if capture != nil {
+		local, _ := conn.LocalAddr().(*net.UDPAddr)
+		capture(false, local, addr, buf[:n])
+	}
 	return buf[:n], addr, nil
 }
 
@@ -185,6 +269,53 @@ func (t *UDPTransport) ReceiveWithTimeout(timeout time.Duration) ([]byte, *net.U
 	return t.Receive(ctx)
 }
 
+// ReceiveInto receives a datagram into buf, waiting at most timeout. The
+// returned address is shared between calls and must not be modified.
+func (t *UDPTransport) ReceiveInto(buf []byte, timeout time.Duration) (int, *net.UDPAddr, error) {
+	t.mu.RLock()
+	conn := t.conn
+	capture := t.capture
+	t.mu.RUnlock()
+
+	if conn == nil {
+		return 0, nil, fmt.Errorf("transport not open")
+	}
+
+	if err := conn.SetReadDeadline(time.Now().Add(timeout)); err != nil {
+		return 0, nil, fmt.Errorf("set read deadline: %w", err)
+	}
+
+	n, ap, err := conn.ReadFromUDPAddrPort(buf)
+	if err != nil {
+		return 0, nil, err
+	}
+
+	addr := t.udpAddr(ap)
+	if capture != nil {
+		local, _ := conn.LocalAddr().(*net.UDPAddr)
+		capture(false, local, addr, buf[:n])
+	}
+	return n, addr, nil
+}
+
+// udpAddr returns the cached *net.UDPAddr of a peer
+func (t *UDPTransport) udpAddr(ap netip.AddrPort) *net.UDPAddr {
+	t.addrsMu.Lock()
+	defer t.addrsMu.Unlock()
+
+	if addr, ok := t.addrs[ap]; ok {
+		return addr
+	}
+	if t.addrs == nil || len(t.addrs) >= maxCachedAddrs {
+		t.addrs = make(map[netip.AddrPort]*net.UDPAddr)
+	}
+
+	// IPv4 peers are reported as IPv4-mapped on dual-stack sockets
+	addr := net.UDPAddrFromAddrPort(netip.AddrPortFrom(ap.Addr().Unmap(), ap.Port()))
+	t.addrs[ap] = addr
+	return addr
+}
+
 // IsClosed returns true if the transport is closed
 func (t *UDPTransport) IsClosed() bool {
 	t.mu.RLock()
@@ -0,0 +1,435 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package transport
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+// WebSocket opcodes (RFC 6455 section 5.2)
+const (
+	wsOpContinuation = 0x0
+	wsOpText         = 0x1
+	wsOpBinary       = 0x2
+	wsOpClose        = 0x8
+	wsOpPing         = 0x9
+	wsOpPong         = 0xA
+)
+
+// wsAcceptGUID is appended to the handshake key (RFC 6455 section 1.3)
+const wsAcceptGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+// maxWebSocketMessage bounds the size of a reassembled message
+const maxWebSocketMessage = 1 << 16
+
+// ErrWebSocketClosed is returned once the peer has closed the connection
+var ErrWebSocketClosed = errors.New("websocket closed")
+
+// WebSocketConn is a WebSocket connection exchanging binary messages, on
+// the client side when dialed and on the server side when accepted
+type WebSocketConn struct {
+	conn   net.Conn
+	br     *bufio.Reader
+	server bool
+
+	writeMu sync.Mutex
+
+	mu     sync.Mutex
+	closed bool
+}
+
+// DialWebSocket opens a WebSocket connection to a ws:// or wss:// URI and
+// negotiates the given subprotocol
+func DialWebSocket(ctx context.Context, uri string, tlsConfig *tls.Config, subprotocol string) (*WebSocketConn, error) {
+	u, err := url.Parse(uri)
+	if err != nil {
+		return nil, fmt.Errorf("parse URI: %w", err)
+	}
+
+	host := u.Host
+	if u.Port() == "" {
+		switch u.Scheme {
+		case "wss":
+			host = net.JoinHostPort(u.Hostname(), "443")
+		case "ws":
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+
+	var conn net.Conn
+	switch u.Scheme {
+	case "wss":
+		cfg := &tls.Config{}
+		if tlsConfig != nil {
+			cfg = tlsConfig.Clone()
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		dialer := &tls.Dialer{Config: cfg}
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	case "ws":
+		var dialer net.Dialer
+		conn, err = dialer.DialContext(ctx, "tcp", host)
+	default:
+		return nil, fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("dial %s: %w", host, err)
+	}
+
+	ws, err := handshake(ctx, conn, u, subprotocol)
+	if err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return ws, nil
+}
+
+// handshake performs the HTTP upgrade (RFC 6455 section 4.1)
+func handshake(ctx context.Context, conn net.Conn, u *url.URL, subprotocol string) (*WebSocketConn, error) {
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+		defer conn.SetDeadline(time.Time{})
+	}
+
+	nonce := make([]byte, 16)
+	if _, err := rand.Read(nonce); err != nil {
+		return nil, fmt.Errorf("generate key: %w", err)
+	}
+	key := base64.StdEncoding.EncodeToString(nonce)
+
+	req := &http.Request{
+		Method:     http.MethodGet,
+		URL:        u,
+		Proto:      "HTTP/1.1",
+		ProtoMajor: 1,
+		ProtoMinor: 1,
+		Header:     make(http.Header),
+		Host:       u.Host,
+	}
+	req.Header.Set("Upgrade", "websocket")
+	req.Header.Set("Connection", "Upgrade")
+	req.Header.Set("Sec-WebSocket-Key", key)
+	req.Header.Set("Sec-WebSocket-Version", "13")
+	if subprotocol != "" {
+		req.Header.Set("Sec-WebSocket-Protocol", subprotocol)
+	}
+
+	if err := req.Write(conn); err != nil {
+		return nil, fmt.Errorf("write handshake: %w", err)
+	}
+
+	br := bufio.NewReader(conn)
+	resp, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, fmt.Errorf("read handshake: %w", err)
+	}
+	resp.Body.Close()
+
+	if resp.StatusCode != http.StatusSwitchingProtocols {
+		return nil, fmt.Errorf("handshake rejected: %s", resp.Status)
+	}
+	if !strings.EqualFold(resp.Header.Get("Upgrade"), "websocket") {
+		return nil, fmt.Errorf("handshake: missing upgrade header")
+	}
+
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	if resp.Header.Get("Sec-WebSocket-Accept") != base64.StdEncoding.EncodeToString(sum[:]) {
+		return nil, fmt.Errorf("handshake: invalid accept key")
+	}
+	if subprotocol != "" && resp.Header.Get("Sec-WebSocket-Protocol") != subprotocol {
+		return nil, fmt.Errorf("handshake: subprotocol %q not accepted", subprotocol)
+	}
+
+	return &WebSocketConn{conn: conn, br: br}, nil
+}
+
+// AcceptWebSocket upgrades an HTTP request to a server-side WebSocket
+// connection (RFC 6455 section 4.2). A non-empty subprotocol is accepted
+// when the client offers it. Failed upgrades have been answered with an
+// HTTP error.
+func AcceptWebSocket(w http.ResponseWriter, r *http.Request, subprotocol string) (*WebSocketConn, error) {
+	if !headerContains(r.Header, "Connection", "upgrade") || !headerContains(r.Header, "Upgrade", "websocket") {
+		http.Error(w, "websocket upgrade required", http.StatusUpgradeRequired)
+		return nil, fmt.Errorf("handshake: not a websocket upgrade")
+	}
+	if r.Header.Get("Sec-WebSocket-Version") != "13" {
+		w.Header().Set("Sec-WebSocket-Version", "13")
+		http.Error(w, "unsupported websocket version", http.StatusBadRequest)
+		return nil, fmt.Errorf("handshake: unsupported version %q", r.Header.Get("Sec-WebSocket-Version"))
+	}
+	key := r.Header.Get("Sec-WebSocket-Key")
+	if key == "" {
+		http.Error(w, "missing websocket key", http.StatusBadRequest)
+		return nil, fmt.Errorf("handshake: missing key")
+	}
+
+	hijacker, ok := w.(http.Hijacker)
+	if !ok {
+		http.Error(w, "websocket not supported", http.StatusInternalServerError)
+		return nil, fmt.Errorf("handshake: connection cannot be hijacked")
+	}
+	conn, brw, err := hijacker.Hijack()
+	if err != nil {
+		return nil, fmt.Errorf("hijack: %w", err)
+	}
+
+	sum := sha1.Sum([]byte(key + wsAcceptGUID))
+	resp := "HTTP/1.1 101 Switching Protocols\r\n" +
+		"Upgrade: websocket\r\n" +
+		"Connection: Upgrade\r\n" +
+		"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(sum[:]) + "\r\n"
+	if subprotocol != "" && headerContains(r.Header, "Sec-WebSocket-Protocol", subprotocol) {
+		resp += "Sec-WebSocket-Protocol: " + subprotocol + "\r\n"
+	}
+	if _, err := conn.Write([]byte(resp + "\r\n")); err != nil {
+		conn.Close()
+		return nil, fmt.Errorf("write handshake: %w", err)
+	}
+
+	// The request may have been followed by the first frames already
+	return &WebSocketConn{conn: conn, br: brw.Reader, server: true}, nil
+}
+
+// headerContains reports whether a comma-separated header lists a token
+func headerContains(h http.Header, name, token string) bool {
+	for _, value := range h.Values(name) {
+		for _, v := range strings.Split(value, ",") {
+			if strings.EqualFold(strings.TrimSpace(v), token) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LocalAddr returns the local network address
+func (w *WebSocketConn) LocalAddr() net.Addr {
+	return w.conn.LocalAddr()
+}
+
+// RemoteAddr returns the remote network address
+func (w *WebSocketConn) RemoteAddr() net.Addr {
+	return w.conn.RemoteAddr()
+}
+
+// SetReadDeadline sets the deadline for ReadMessage
+func (w *WebSocketConn) SetReadDeadline(t time.Time) error {
+	return w.conn.SetReadDeadline(t)
+}
+
+// SetWriteDeadline sets the deadline for WriteMessage
+func (w *WebSocketConn) SetWriteDeadline(t time.Time) error {
+	return w.conn.SetWriteDeadline(t)
+}
+
+// WriteMessage sends data as a single binary message
+func (w *WebSocketConn) WriteMessage(data []byte) error {
+	return w.writeFrame(wsOpBinary, data)
+}
+
+// WriteText sends data as a single text message
+func (w *WebSocketConn) WriteText(data []byte) error {
+	return w.writeFrame(wsOpText, data)
+}
+
+// writeFrame writes a single frame, masked on the client side
+func (w *WebSocketConn) writeFrame(opcode byte, payload []byte) error {
+	w.writeMu.Lock()
+	defer w.writeMu.Unlock()
+
+	var maskBit byte = 0x80
+	if w.server {
+		maskBit = 0
+	}
+
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | opcode // FIN
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = maskBit | byte(n)
+	case n <= 0xFFFF:
+		header[1] = maskBit | 126
+		header = binary.BigEndian.AppendUint16(header, uint16(n))
+	default:
+		header[1] = maskBit | 127
+		header = binary.BigEndian.AppendUint64(header, uint64(n))
+	}
+
+	// Server frames are never masked
+	if w.server {
+		frame := make([]byte, 0, len(header)+len(payload))
+		frame = append(append(frame, header...), payload...)
+		_, err := w.conn.Write(frame)
+		return err
+	}
+
+	// Client frames are always masked
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	header = append(header, mask[:]...)
+
+	frame := make([]byte, len(header)+len(payload))
+	copy(frame, header)
+	for i, b := range payload {
+		frame[len(header)+i] = b ^ mask[i%4]
+	}
+
+	_, err := w.conn.Write(frame)
+	return err
+}
+
+// ReadMessage returns the next text or binary message. Ping frames are
+// answered and fragmented messages reassembled.
+func (w *WebSocketConn) ReadMessage() ([]byte, error) {
+	var message []byte
+	fragmented := false
+
+	for {
+		fin, opcode, payload, err := w.readFrame()
+		if err != nil {
+			return nil, err
+		}
+
+		switch opcode {
+		case wsOpPing:
+			if err := w.writeFrame(wsOpPong, payload); err != nil {
+				return nil, err
+			}
+		case wsOpPong:
+		case wsOpClose:
+			w.mu.Lock()
+			alreadyClosed := w.closed
+			w.closed = true
+			w.mu.Unlock()
+			if !alreadyClosed {
+				// Echo the status code back (RFC 6455 section 5.5.1)
+				var code []byte
+				if len(payload) >= 2 {
+					code = payload[:2]
+				}
+				w.writeFrame(wsOpClose, code)
+			}
+			return nil, ErrWebSocketClosed
+		case wsOpText, wsOpBinary, wsOpContinuation:
+			if (opcode == wsOpContinuation) != fragmented {
+				return nil, fmt.Errorf("websocket: unexpected opcode %d", opcode)
+			}
+			if len(message)+len(payload) > maxWebSocketMessage {
+				return nil, fmt.Errorf("websocket: message exceeds %d bytes", maxWebSocketMessage)
+			}
+			message = append(message, payload...)
+			if fin {
+				return message, nil
+			}
+			fragmented = true
+		default:
+			return nil, fmt.Errorf("websocket: unknown opcode %d", opcode)
+		}
+	}
+}
+
+// readFrame reads a single frame
+func (w *WebSocketConn) readFrame() (fin bool, opcode byte, payload []byte, err error) {
+	var header [2]byte
+	if _, err = io.ReadFull(w.br, header[:]); err != nil {
+		return
+	}
+
+	fin = header[0]&0x80 != 0
+	opcode = header[0] & 0x0F
+	masked := header[1]&0x80 != 0
+	length := uint64(header[1] & 0x7F)
+
+	switch length {
+	case 126:
+		var ext [2]byte
+		if _, err = io.ReadFull(w.br, ext[:]); err != nil {
+			return
+		}
+		length = uint64(binary.BigEndian.Uint16(ext[:]))
+	case 127:
+		var ext [8]byte
+		if _, err = io.ReadFull(w.br, ext[:]); err != nil {
+			return
+		}
+		length = binary.BigEndian.Uint64(ext[:])
+	}
+	if length > maxWebSocketMessage {
+		err = fmt.Errorf("websocket: frame exceeds %d bytes", maxWebSocketMessage)
+		return
+	}
+
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(w.br, mask[:]); err != nil {
+			return
+		}
+	}
+
+	payload = make([]byte, length)
+	if _, err = io.ReadFull(w.br, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+// Close sends a close frame and closes the underlying connection
+func (w *WebSocketConn) Close() error {
+	return w.CloseWithStatus(1000, "") // normal closure
+}
+
+// CloseWithStatus sends a close frame with a status code and reason (RFC
+// 6455 section 7.4) and closes the underlying connection. Reasons are cut to
+// fit a control frame.
+func (w *WebSocketConn) CloseWithStatus(code uint16, reason string) error {
+	w.mu.Lock()
+	alreadyClosed := w.closed
+	w.closed = true
+	w.mu.Unlock()
+
+	if !alreadyClosed {
+		if len(reason) > 123 {
+			reason = reason[:123]
+		}
+		payload := binary.BigEndian.AppendUint16(nil, code)
+		w.conn.SetWriteDeadline(time.Now().Add(time.Second))
+		w.writeFrame(wsOpClose, append(payload, reason...))
+	}
+	return w.conn.Close()
+}
@@ -0,0 +1,232 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// DeviceVersion is the version inventory of a device
+type DeviceVersion struct {
+	DeviceID            uint32 `json:"device_id"`
+	VendorID            uint16 `json:"vendor_id"`
+	VendorName          string `json:"vendor_name"`
+	ModelName           string `json:"model_name"`
+	FirmwareRevision    string `json:"firmware_revision"`
+	ApplicationSoftware string `json:"application_software_version"`
+	ProtocolRevision    uint32 `json:"protocol_revision"`
+
+	// BelowBaseline lists the versions under the matching baseline
+	BelowBaseline []string `json:"below_baseline,omitempty"`
+
+	Err error `json:"-"`
+}
+
+// VersionBaseline is the minimum versions expected of a set of devices.
+// Empty VendorName and ModelName match any device; a version left empty is
+// not checked.
+type VersionBaseline struct {
+	VendorName          string `json:"vendor_name" mapstructure:"vendor"`
+	ModelName           string `json:"model_name" mapstructure:"model"`
+	FirmwareRevision    string `json:"firmware_revision" mapstructure:"firmware"`
+	ApplicationSoftware string `json:"application_software_version" mapstructure:"application-software"`
+	ProtocolRevision    uint32 `json:"protocol_revision" mapstructure:"protocol-revision"`
+}
+
+// inventoryProperties are the device properties read for the inventory
+var inventoryProperties = []PropertyIdentifier{
+	PropertyVendorIdentifier,
+	PropertyVendorName,
+	PropertyModelName,
+	PropertyFirmwareRevision,
+	PropertyApplicationSoftwareVersion,
+	PropertyProtocolRevision,
+}
+
+// maxInventoryConcurrency bounds the devices read at the same time
+const maxInventoryConcurrency = 8
+
+// Inventory reads the version properties of the given devices, or of all
+// discovered devices when deviceIDs is empty. Each device is read with a
+// single ReadPropertyMultiple, falling back to ReadProperty. Results are
+// sorted by device ID.
+func (c *Client) Inventory(ctx context.Context, deviceIDs []uint32) []DeviceVersion {
+	if len(deviceIDs) == 0 {
+		c.devicesMu.RLock()
+		for id := range c.devices {
+			deviceIDs = append(deviceIDs, id)
+		}
+		c.devicesMu.RUnlock()
+	}
+
+	versions := make([]DeviceVersion, len(deviceIDs))
+	sem := make(chan struct{}, maxInventoryConcurrency)
+	var wg sync.WaitGroup
+
+	for i, id := range deviceIDs {
+		wg.Add(1)
+		go func(i int, id uint32) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+			versions[i] = c.readDeviceVersion(ctx, id)
+		}(i, id)
+	}
+	wg.Wait()
+
+	sort.Slice(versions, func(i, j int) bool { return versions[i].DeviceID < versions[j].DeviceID })
+	return versions
+}
+
+// readDeviceVersion reads the version properties of one device
+func (c *Client) readDeviceVersion(ctx context.Context, deviceID uint32) DeviceVersion {
+	v := DeviceVersion{DeviceID: deviceID}
+	deviceOID := NewObjectIdentifier(ObjectTypeDevice, deviceID)
+
+	requests := make([]ReadPropertyRequest, len(inventoryProperties))
+	for i, prop := range inventoryProperties {
+		requests[i] = ReadPropertyRequest{ObjectID: deviceOID, PropertyID: prop}
+	}
+
+	values := make(map[PropertyIdentifier]interface{})
+	if results, err := c.ReadPropertyMultiple(ctx, deviceID, requests); err == nil {
+		for _, r := range results {
+			if r.Err == nil {
+				values[r.PropertyID] = r.Value
+			}
+		}
+	} else {
+		for _, prop := range inventoryProperties {
+			value, err := c.ReadProperty(ctx, deviceID, deviceOID, prop)
+			if err != nil {
+				// Optional properties may be missing; a dead device fails all
+				if IsTimeout(err) || IsDeviceNotFound(err) {
+					v.Err = err
+					return v
+				}
+				continue
+			}
+			values[prop] = value
+		}
+	}
+
+	if id, ok := values[PropertyVendorIdentifier].(uint32); ok {
+		v.VendorID = uint16(id)
+	}
+	v.VendorName, _ = values[PropertyVendorName].(string)
+	v.ModelName, _ = values[PropertyModelName].(string)
+	v.FirmwareRevision, _ = values[PropertyFirmwareRevision].(string)
+	v.ApplicationSoftware, _ = values[PropertyApplicationSoftwareVersion].(string)
+	v.ProtocolRevision, _ = values[PropertyProtocolRevision].(uint32)
+
+	return v
+}
+
+// Check compares the device against the first baseline matching its vendor
+// and model, and records the versions below it in BelowBaseline. It returns
+// true if the device meets the baseline or none matches.
+func (v *DeviceVersion) Check(baselines []VersionBaseline) bool {
+	v.BelowBaseline = nil
+
+	for _, b := range baselines {
+		if !b.matches(v) {
+			continue
+		}
+
+		if b.FirmwareRevision != "" && CompareVersions(v.FirmwareRevision, b.FirmwareRevision) < 0 {
+			v.BelowBaseline = append(v.BelowBaseline,
+				fmt.Sprintf("firmware-revision %q < %q", v.FirmwareRevision, b.FirmwareRevision))
+		}
+		if b.ApplicationSoftware != "" && CompareVersions(v.ApplicationSoftware, b.ApplicationSoftware) < 0 {
+			v.BelowBaseline = append(v.BelowBaseline,
+				fmt.Sprintf("application-software-version %q < %q", v.ApplicationSoftware, b.ApplicationSoftware))
+		}
+		if b.ProtocolRevision > 0 && v.ProtocolRevision < b.ProtocolRevision {
+			v.BelowBaseline = append(v.BelowBaseline,
+				fmt.Sprintf("protocol-revision %d < %d", v.ProtocolRevision, b.ProtocolRevision))
+		}
+		break
+	}
+
+	return len(v.BelowBaseline) == 0
+}
+
+// matches returns true if the baseline applies to the device
+func (b VersionBaseline) matches(v *DeviceVersion) bool {
+	if b.VendorName != "" && !strings.EqualFold(b.VendorName, v.VendorName) {
+		return false
+	}
+	if b.ModelName != "" && !strings.EqualFold(b.ModelName, v.ModelName) {
+		return false
+	}
+	return true
+}
+
+// CompareVersions compares two version strings such as "2.10.3" and
+// "v2.9". Numeric components compare numerically, others lexically;
+// missing components count as zero. It returns -1, 0 or 1.
+func CompareVersions(a, b string) int {
+	as := splitVersion(a)
+	bs := splitVersion(b)
+
+	for i := 0; i < len(as) || i < len(bs); i++ {
+		var x, y string
+		if i < len(as) {
+			x = as[i]
+		}
+		if i < len(bs) {
+			y = bs[i]
+		}
+
+		xn, xErr := strconv.ParseUint(defaultZero(x), 10, 64)
+		yn, yErr := strconv.ParseUint(defaultZero(y), 10, 64)
+		switch {
+		case xErr == nil && yErr == nil:
+			if xn != yn {
+				if xn < yn {
+					return -1
+				}
+				return 1
+			}
+		case x != y:
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+// splitVersion splits a version on dots, dashes, underscores and spaces,
+// dropping a leading "v"
+func splitVersion(s string) []string {
+	s = strings.TrimPrefix(strings.ToLower(strings.TrimSpace(s)), "v")
+	return strings.FieldsFunc(s, func(r rune) bool {
+		return r == '.' || r == '-' || r == '_' || r == ' '
+	})
+}
+
+func defaultZero(s string) string {
+	if s == "" {
+		return "0"
+	}
+	return s
+}
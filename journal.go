@@ -0,0 +1,167 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// JournalEntry records one property write sent by the client
+type JournalEntry struct {
+	Time       time.Time
+	DeviceID   uint32
+	Service    ConfirmedServiceChoice
+	ObjectID   ObjectIdentifier
+	PropertyID PropertyIdentifier
+	ArrayIndex *uint32
+
+	// OldValue is the value read before a verified write, nil otherwise
+	OldValue interface{}
+	Value    interface{}
+	Priority *uint8
+
+	// Err is the result of the write, nil on success
+	Err error
+}
+
+// JournalSink receives the write journal. Record is called after each write
+// completes, on the writing goroutine; a failure is logged and never fails
+// the write. Implementations must be safe for concurrent use.
+type JournalSink interface {
+	Record(entry JournalEntry) error
+}
+
+// JournalFunc adapts a function to a JournalSink
+type JournalFunc func(entry JournalEntry) error
+
+// Record calls f
+func (f JournalFunc) Record(entry JournalEntry) error {
+	return f(entry)
+}
+
+// journalRecord is the JSON form of a journal entry
+type journalRecord struct {
+	Time       time.Time       `json:"time"`
+	DeviceID   uint32          `json:"device_id"`
+	Service    string          `json:"service"`
+	Object     string          `json:"object"`
+	Property   string          `json:"property"`
+	ArrayIndex *uint32         `json:"array_index,omitempty"`
+	OldValue   json.RawMessage `json:"old_value,omitempty"`
+	Value      json.RawMessage `json:"value"`
+	Priority   *uint8          `json:"priority,omitempty"`
+	Result     string          `json:"result"`
+}
+
+// MarshalJSON encodes the entry with readable object, property and service
+// names and a result of "ok" or the error message
+func (e JournalEntry) MarshalJSON() ([]byte, error) {
+	rec := journalRecord{
+		Time:       e.Time,
+		DeviceID:   e.DeviceID,
+		Service:    e.Service.String(),
+		Object:     e.ObjectID.String(),
+		Property:   e.PropertyID.String(),
+		ArrayIndex: e.ArrayIndex,
+		Value:      journalValue(e.Value),
+		Priority:   e.Priority,
+		Result:     "ok",
+	}
+	if e.OldValue != nil {
+		rec.OldValue = journalValue(e.OldValue)
+	}
+	if e.Err != nil {
+		rec.Result = e.Err.Error()
+	}
+	return json.Marshal(rec)
+}
+
+// journalValue encodes a property value as JSON, or as its text form when
+// it has no JSON encoding
+func journalValue(v interface{}) json.RawMessage {
+	if data, err := json.Marshal(v); err == nil {
+		return data
+	}
+	data, _ := json.Marshal(fmt.Sprint(v))
+	return data
+}
+
+// jsonJournal writes the journal as JSON lines
+type jsonJournal struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+// NewJSONJournal returns a journal sink that writes each entry to w as a
+// line of JSON, e.g. to an append-only file
+func NewJSONJournal(w io.Writer) JournalSink {
+	return &jsonJournal{w: w}
+}
+
+func (j *jsonJournal) Record(entry JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	_, err = j.w.Write(append(data, '\n'))
+	return err
+}
+
+// storageJournal appends the journal to a Storage log
+type storageJournal struct {
+	storage Storage
+	log     string
+	timeout time.Duration
+}
+
+// NewStorageJournal returns a journal sink that appends each entry as JSON
+// to the named log of storage
+func NewStorageJournal(storage Storage, log string) JournalSink {
+	return &storageJournal{storage: storage, log: log, timeout: 5 * time.Second}
+}
+
+func (j *storageJournal) Record(entry JournalEntry) error {
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), j.timeout)
+	defer cancel()
+	_, err = j.storage.Append(ctx, j.log, data)
+	return err
+}
+
+// journalWrite records a write to the journal, if any
+func (c *Client) journalWrite(entry JournalEntry) {
+	if c.opts.journal == nil {
+		return
+	}
+	entry.Time = c.opts.clock.Now()
+	if err := c.opts.journal.Record(entry); err != nil {
+		c.logger.Warn("failed to record write journal entry",
+			slog.Uint64("device_id", uint64(entry.DeviceID)),
+			slog.String("object", entry.ObjectID.String()),
+			slog.String("error", err.Error()),
+		)
+	}
+}
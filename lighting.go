@@ -0,0 +1,252 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// LightingOperation is the operation of a lighting command
+type LightingOperation uint8
+
+const (
+	LightingOperationNone           LightingOperation = 0
+	LightingOperationFadeTo         LightingOperation = 1
+	LightingOperationRampTo         LightingOperation = 2
+	LightingOperationStepUp         LightingOperation = 3
+	LightingOperationStepDown       LightingOperation = 4
+	LightingOperationStepOn         LightingOperation = 5
+	LightingOperationStepOff        LightingOperation = 6
+	LightingOperationWarn           LightingOperation = 7
+	LightingOperationWarnOff        LightingOperation = 8
+	LightingOperationWarnRelinquish LightingOperation = 9
+	LightingOperationStop           LightingOperation = 10
+)
+
+func (o LightingOperation) String() string {
+	names := map[LightingOperation]string{
+		LightingOperationNone:           "none",
+		LightingOperationFadeTo:         "fade-to",
+		LightingOperationRampTo:         "ramp-to",
+		LightingOperationStepUp:         "step-up",
+		LightingOperationStepDown:       "step-down",
+		LightingOperationStepOn:         "step-on",
+		LightingOperationStepOff:        "step-off",
+		LightingOperationWarn:           "warn",
+		LightingOperationWarnOff:        "warn-off",
+		LightingOperationWarnRelinquish: "warn-relinquish",
+		LightingOperationStop:           "stop",
+	}
+	if name, ok := names[o]; ok {
+		return name
+	}
+	return fmt.Sprintf("lighting-operation(%d)", o)
+}
+
+// LightingCommand is a BACnetLightingCommand. Written to the
+// lighting-command property of a Lighting Output object, it fades, ramps or
+// steps the light level. Optional fields left unset are omitted and the
+// object's defaults apply.
+type LightingCommand struct {
+	Operation LightingOperation
+
+	// TargetLevel is the level in percent reached by fade-to and ramp-to
+	TargetLevel *float32
+
+	// RampRate is the ramp-to rate in percent per second, 0 if unset
+	RampRate float32
+
+	// StepIncrement is the step of the step operations in percent, 0 if
+	// unset
+	StepIncrement float32
+
+	// FadeTime is the duration of a fade-to, from 100ms to 24h, 0 if unset
+	FadeTime time.Duration
+
+	// Priority is the priority (1-16) the command applies at, 0 for the
+	// object's lighting-command-default-priority
+	Priority uint8
+}
+
+// LightingFadeTo returns a command fading to level (0-100%) over fade, or over the
+// object's default-fade-time if fade is 0
+func LightingFadeTo(level float32, fade time.Duration) LightingCommand {
+	return LightingCommand{Operation: LightingOperationFadeTo, TargetLevel: &level, FadeTime: fade}
+}
+
+// LightingRampTo returns a command ramping to level (0-100%) at rate percent per
+// second, or at the object's default-ramp-rate if rate is 0
+func LightingRampTo(level, rate float32) LightingCommand {
+	return LightingCommand{Operation: LightingOperationRampTo, TargetLevel: &level, RampRate: rate}
+}
+
+// LightingStep returns a step-up, step-down, step-on or step-off command
+// with the given increment, or the object's default-step-increment if
+// increment is 0
+func LightingStep(op LightingOperation, increment float32) LightingCommand {
+	return LightingCommand{Operation: op, StepIncrement: increment}
+}
+
+// encode encodes the command as a BACnetLightingCommand
+func (l LightingCommand) encode(e *Encoder) error {
+	e.ContextEnumerated(0, uint32(l.Operation))
+	if l.TargetLevel != nil {
+		e.ContextReal(1, *l.TargetLevel)
+	}
+	if l.RampRate != 0 {
+		e.ContextReal(2, l.RampRate)
+	}
+	if l.StepIncrement != 0 {
+		e.ContextReal(3, l.StepIncrement)
+	}
+	if l.FadeTime != 0 {
+		ms := l.FadeTime.Milliseconds()
+		if ms < 100 || ms > 86400000 {
+			return fmt.Errorf("bacnet: fade time %v out of range 100ms-24h", l.FadeTime)
+		}
+		e.ContextUnsigned(4, uint32(ms))
+	}
+	if l.Priority != 0 {
+		if l.Priority > 16 {
+			return fmt.Errorf("bacnet: priority %d out of range 1-16", l.Priority)
+		}
+		e.ContextUnsigned(5, uint32(l.Priority))
+	}
+	return nil
+}
+
+// decodeLightingCommand decodes a BACnetLightingCommand
+func decodeLightingCommand(d *Decoder) LightingCommand {
+	l := LightingCommand{Operation: LightingOperation(d.ContextEnumerated(0))}
+	if d.IsContext(1) {
+		level := d.ContextReal(1)
+		l.TargetLevel = &level
+	}
+	if d.IsContext(2) {
+		l.RampRate = d.ContextReal(2)
+	}
+	if d.IsContext(3) {
+		l.StepIncrement = d.ContextReal(3)
+	}
+	if d.IsContext(4) {
+		l.FadeTime = time.Duration(d.ContextUnsigned(4)) * time.Millisecond
+	}
+	if d.IsContext(5) {
+		l.Priority = uint8(d.ContextUnsigned(5))
+	}
+	return l
+}
+
+// WriteLightingCommand writes a lighting command to a Lighting Output
+// object, e.g. LightingFadeTo(50, 2*time.Second)
+func (c *Client) WriteLightingCommand(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, cmd LightingCommand, opts ...WriteOption) error {
+	return c.WriteProperty(ctx, deviceID, objectID, PropertyLightingCommand, cmd, opts...)
+}
+
+// ReadLightingCommand reads the last lighting command written to a Lighting
+// Output object
+func (c *Client) ReadLightingCommand(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) (LightingCommand, error) {
+	resp, err := c.readProperty(ctx, deviceID, objectID, PropertyLightingCommand, &ReadOptions{})
+	if err != nil {
+		return LightingCommand{}, err
+	}
+
+	d := NewDecoder(resp.Data)
+	d.ContextObjectIdentifier(0)
+	d.ContextEnumerated(1)
+	d.Opening(3)
+	cmd := decodeLightingCommand(d)
+	d.Closing(3)
+	if err := d.Err(); err != nil {
+		return LightingCommand{}, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return cmd, nil
+}
+
+// LightingInProgress is the in-progress property of a Lighting Output
+// object
+type LightingInProgress uint8
+
+const (
+	LightingInProgressIdle          LightingInProgress = 0
+	LightingInProgressFadeActive    LightingInProgress = 1
+	LightingInProgressRampActive    LightingInProgress = 2
+	LightingInProgressNotControlled LightingInProgress = 3
+	LightingInProgressOther         LightingInProgress = 4
+)
+
+func (p LightingInProgress) String() string {
+	names := map[LightingInProgress]string{
+		LightingInProgressIdle:          "idle",
+		LightingInProgressFadeActive:    "fade-active",
+		LightingInProgressRampActive:    "ramp-active",
+		LightingInProgressNotControlled: "not-controlled",
+		LightingInProgressOther:         "other",
+	}
+	if name, ok := names[p]; ok {
+		return name
+	}
+	return fmt.Sprintf("in-progress(%d)", p)
+}
+
+// ReadLightingInProgress reads whether a Lighting Output object is fading
+// or ramping
+func (c *Client) ReadLightingInProgress(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) (LightingInProgress, error) {
+	v, err := c.ReadProperty(ctx, deviceID, objectID, PropertyInProgress)
+	if err != nil {
+		return 0, err
+	}
+	n, ok := v.(uint32)
+	if !ok {
+		return 0, fmt.Errorf("%w: in-progress is %T", ErrInvalidResponse, v)
+	}
+	return LightingInProgress(n), nil
+}
+
+// BinaryLightingValue is the present value of a Binary Lighting Output
+// object
+type BinaryLightingValue uint8
+
+const (
+	BinaryLightingOff            BinaryLightingValue = 0
+	BinaryLightingOn             BinaryLightingValue = 1
+	BinaryLightingWarn           BinaryLightingValue = 2
+	BinaryLightingWarnOff        BinaryLightingValue = 3
+	BinaryLightingWarnRelinquish BinaryLightingValue = 4
+	BinaryLightingStop           BinaryLightingValue = 5
+)
+
+func (v BinaryLightingValue) String() string {
+	names := map[BinaryLightingValue]string{
+		BinaryLightingOff:            "off",
+		BinaryLightingOn:             "on",
+		BinaryLightingWarn:           "warn",
+		BinaryLightingWarnOff:        "warn-off",
+		BinaryLightingWarnRelinquish: "warn-relinquish",
+		BinaryLightingStop:           "stop",
+	}
+	if name, ok := names[v]; ok {
+		return name
+	}
+	return fmt.Sprintf("binary-lighting-value(%d)", v)
+}
+
+// WriteBinaryLighting writes the present value of a Binary Lighting Output
+// object: on, off, or a warn operation that blinks before switching off
+func (c *Client) WriteBinaryLighting(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, value BinaryLightingValue, opts ...WriteOption) error {
+	return c.WriteProperty(ctx, deviceID, objectID, PropertyPresentValue, value, opts...)
+}
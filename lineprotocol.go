@@ -0,0 +1,238 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// defaultMeasurement is the measurement of lines without WithMeasurement
+const defaultMeasurement = "bacnet"
+
+// LineProtocolOption configures a LineProtocolEncoder
+type LineProtocolOption func(*LineProtocolEncoder)
+
+// WithMeasurement sets the measurement of the lines, "bacnet" by default
+func WithMeasurement(name string) LineProtocolOption {
+	return func(e *LineProtocolEncoder) {
+		e.measurement = name
+	}
+}
+
+// WithLineTag adds a tag to every line, e.g. the site of the devices
+func WithLineTag(key, value string) LineProtocolOption {
+	return func(e *LineProtocolEncoder) {
+		e.tags = append(e.tags, lineTag{key, value})
+	}
+}
+
+// lineTag is a tag of a line
+type lineTag struct {
+	key, value string
+}
+
+// lineObject is an object of a device, the key of the known units
+type lineObject struct {
+	deviceID uint32
+	objectID ObjectIdentifier
+}
+
+// LineProtocolEncoder writes property values to a historian as InfluxDB
+// line protocol, one line per value:
+//
+//	bacnet,device=1234,instance=1,object_type=analog-input,property=present-value,units=°C value_float=21.5 1767600000000000000
+//
+// The field is named after the type of the value, since a field keeps the
+// type of its first value: reals are written as value_float, unsigned,
+// signed and enumerated values as value_int, booleans as value_bool and
+// other values as value_str. Only numbers are tagged with units. Null
+// values and non-finite reals have no line. It is safe for concurrent use.
+type LineProtocolEncoder struct {
+	mu          sync.Mutex
+	w           io.Writer
+	measurement string
+	tags        []lineTag
+	units       map[lineObject]EngineeringUnits
+	buf         []byte
+}
+
+// NewLineProtocolEncoder creates an encoder writing to w
+func NewLineProtocolEncoder(w io.Writer, opts ...LineProtocolOption) *LineProtocolEncoder {
+	e := &LineProtocolEncoder{
+		w:           w,
+		measurement: defaultMeasurement,
+		units:       make(map[lineObject]EngineeringUnits),
+	}
+	for _, opt := range opts {
+		opt(e)
+	}
+	return e
+}
+
+// SetUnits sets the units tag of the values of an object
+func (e *LineProtocolEncoder) SetUnits(deviceID uint32, objectID ObjectIdentifier, units EngineeringUnits) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	e.units[lineObject{deviceID, objectID}] = units
+}
+
+// Encode writes the line of a property value of a device
+func (e *LineProtocolEncoder) Encode(deviceID uint32, pv PropertyValue, t time.Time) error {
+	return e.encode(deviceID, pv, "", t)
+}
+
+// EncodeUpdate writes the line of a Poller update, tagged with the name of
+// its point. Bad and offline updates have no value and no line.
+func (e *LineProtocolEncoder) EncodeUpdate(update PointUpdate) error {
+	if update.Quality != QualityGood {
+		return nil
+	}
+	point := update.Point
+	propID := point.PropertyID
+	if propID == 0 {
+		propID = PropertyPresentValue
+	}
+	pv := PropertyValue{ObjectID: point.ObjectID, PropertyID: propID, Value: update.Value}
+	return e.encode(point.DeviceID, pv, point.Name, update.Time)
+}
+
+func (e *LineProtocolEncoder) encode(deviceID uint32, pv PropertyValue, point string, t time.Time) error {
+	key, field, numeric, ok := lineFieldValue(pv.Value)
+	if !ok {
+		return nil
+	}
+
+	e.mu.Lock()
+	defer e.mu.Unlock()
+
+	tags := append([]lineTag(nil), e.tags...)
+	tags = append(tags,
+		lineTag{"device", strconv.FormatUint(uint64(deviceID), 10)},
+		lineTag{"object_type", pv.ObjectID.Type.String()},
+		lineTag{"instance", strconv.FormatUint(uint64(pv.ObjectID.Instance), 10)},
+		lineTag{"property", pv.PropertyID.String()},
+	)
+	if pv.ArrayIndex != nil {
+		tags = append(tags, lineTag{"index", strconv.FormatUint(uint64(*pv.ArrayIndex), 10)})
+	}
+	if point != "" {
+		tags = append(tags, lineTag{"point", point})
+	}
+	if units, ok := e.units[lineObject{deviceID, pv.ObjectID}]; ok && numeric {
+		tags = append(tags, lineTag{"units", units.String()})
+	}
+
+	// Tags sorted by key are the fastest for the database to index
+	sort.SliceStable(tags, func(i, j int) bool { return tags[i].key < tags[j].key })
+
+	b := e.buf[:0]
+	b = appendLineEscaped(b, e.measurement, ", ")
+	for _, tag := range tags {
+		if tag.value == "" {
+			continue
+		}
+		b = append(b, ',')
+		b = appendLineEscaped(b, tag.key, ",= ")
+		b = append(b, '=')
+		b = appendLineEscaped(b, tag.value, ",= ")
+	}
+	b = append(b, ' ')
+	b = append(b, key...)
+	b = append(b, '=')
+	b = append(b, field...)
+	b = append(b, ' ')
+	b = strconv.AppendInt(b, t.UnixNano(), 10)
+	b = append(b, '\n')
+	e.buf = b
+
+	_, err := e.w.Write(b)
+	return err
+}
+
+// Field keys of the line protocol values, one per field type
+const (
+	lineFieldFloat  = "value_float"
+	lineFieldInt    = "value_int"
+	lineFieldBool   = "value_bool"
+	lineFieldString = "value_str"
+)
+
+// lineFieldValue formats a value as a field of line protocol, with the key
+// of its type, and reports whether it is a number, the values that units
+// apply to
+func lineFieldValue(value interface{}) (key, field string, numeric, ok bool) {
+	switch v := value.(type) {
+	case nil:
+		return "", "", false, false
+	case bool:
+		return lineFieldBool, strconv.FormatBool(v), false, true
+	case float32:
+		return lineFloat(float64(v), 32)
+	case float64:
+		return lineFloat(v, 64)
+	case uint32:
+		return lineFieldInt, strconv.FormatUint(uint64(v), 10) + "i", true, true
+	case int32:
+		return lineFieldInt, strconv.FormatInt(int64(v), 10) + "i", true, true
+	case Enumerated:
+		return lineFieldInt, strconv.FormatUint(uint64(v), 10) + "i", false, true
+	case string:
+		return lineFieldString, lineString(v), false, true
+	case []byte:
+		return lineFieldString, lineString(fmt.Sprintf("%x", v)), false, true
+	case fmt.Stringer:
+		return lineFieldString, lineString(v.String()), false, true
+	default:
+		return lineFieldString, lineString(fmt.Sprint(v)), false, true
+	}
+}
+
+// lineFloat formats a float field value; line protocol has no NaN or
+// infinity
+func lineFloat(f float64, bitSize int) (string, string, bool, bool) {
+	if math.IsNaN(f) || math.IsInf(f, 0) {
+		return "", "", true, false
+	}
+	return lineFieldFloat, strconv.FormatFloat(f, 'g', -1, bitSize), true, true
+}
+
+// lineString quotes a string field value
+func lineString(s string) string {
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(s) + `"`
+}
+
+// appendLineEscaped appends s with the given characters escaped by a
+// backslash, as measurements, tag keys and tag values require
+func appendLineEscaped(b []byte, s, special string) []byte {
+	for i := 0; i < len(s); i++ {
+		c := s[i]
+		if c == '\n' {
+			// Lines cannot hold newlines, escaped or not
+			c = ' '
+		}
+		if strings.IndexByte(special, c) >= 0 {
+			b = append(b, '\\')
+		}
+		b = append(b, c)
+	}
+	return b
+}
@@ -0,0 +1,87 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"bytes"
+	"math"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLineProtocolEncoderMixedTypes(t *testing.T) {
+	var buf bytes.Buffer
+	enc := NewLineProtocolEncoder(&buf)
+	av := NewObjectIdentifier(ObjectTypeAnalogValue, 1)
+	msv := NewObjectIdentifier(ObjectTypeMultiStateValue, 2)
+	bv := NewObjectIdentifier(ObjectTypeBinaryValue, 3)
+	enc.SetUnits(1234, av, UnitsDegreesCelsius)
+	ts := time.Unix(1767600000, 0)
+
+	values := []PropertyValue{
+		{ObjectID: av, PropertyID: PropertyPresentValue, Value: float32(21.5)},
+		{ObjectID: msv, PropertyID: PropertyPresentValue, Value: uint32(3)},
+		{ObjectID: bv, PropertyID: PropertyPresentValue, Value: Enumerated(1)},
+		{ObjectID: bv, PropertyID: PropertyOutOfService, Value: true},
+		{ObjectID: av, PropertyID: PropertyObjectName, Value: `Zone "A"`},
+		{ObjectID: av, PropertyID: PropertyPresentValue, Value: int32(-4)},
+		{ObjectID: av, PropertyID: PropertyPresentValue, Value: nil},
+		{ObjectID: av, PropertyID: PropertyPresentValue, Value: float32(math.NaN())},
+	}
+	for _, pv := range values {
+		if err := enc.Encode(1234, pv, ts); err != nil {
+			t.Fatalf("Encode(%v) error = %v", pv.Value, err)
+		}
+	}
+
+	want := []string{
+		`bacnet,device=1234,instance=1,object_type=analog-value,property=present-value,units=°C value_float=21.5 1767600000000000000`,
+		`bacnet,device=1234,instance=2,object_type=multi-state-value,property=present-value value_int=3i 1767600000000000000`,
+		`bacnet,device=1234,instance=3,object_type=binary-value,property=present-value value_int=1i 1767600000000000000`,
+		`bacnet,device=1234,instance=3,object_type=binary-value,property=out-of-service value_bool=true 1767600000000000000`,
+		`bacnet,device=1234,instance=1,object_type=analog-value,property=object-name value_str="Zone \"A\"" 1767600000000000000`,
+		`bacnet,device=1234,instance=1,object_type=analog-value,property=present-value,units=°C value_int=-4i 1767600000000000000`,
+	}
+	got := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(got) != len(want) {
+		t.Fatalf("got %d lines, want %d:\n%s", len(got), len(want), buf.String())
+	}
+	for i := range want {
+		if got[i] != want[i] {
+			t.Errorf("line %d:\n got %s\nwant %s", i, got[i], want[i])
+		}
+	}
+
+	// A field key holds one type across the batch
+	types := make(map[string]string)
+	for _, line := range got {
+		field := line[strings.IndexByte(line, ' ')+1 : strings.LastIndexByte(line, ' ')]
+		key, value, _ := strings.Cut(field, "=")
+		kind := "float"
+		switch {
+		case strings.HasSuffix(value, "i"):
+			kind = "int"
+		case value == "true" || value == "false":
+			kind = "bool"
+		case strings.HasPrefix(value, `"`):
+			kind = "string"
+		}
+		if prev, ok := types[key]; ok && prev != kind {
+			t.Errorf("field %s holds %s and %s values", key, prev, kind)
+		}
+		types[key] = kind
+	}
+}
@@ -0,0 +1,280 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"sync"
+	"time"
+)
+
+// dataLink carries NPDUs between the client and the stations of its local
+// BACnet network. Each data link (BACnet/IP, BACnet/SC, ...) handles its own
+// framing and addressing.
+type dataLink interface {
+	// Open connects the data link
+	Open(ctx context.Context) error
+
+	// Close disconnects the data link
+	Close() error
+
+	// IsClosed returns true once the data link has been closed
+	IsClosed() bool
+
+	// LocalAddr returns the local link address
+	LocalAddr() net.Addr
+
+	// Send sends an NPDU to a single station
+	Send(ctx context.Context, addr net.Addr, npdu []byte) error
+
+	// Broadcast sends an NPDU to all stations of the local network
+	Broadcast(ctx context.Context, npdu []byte) error
+
+	// Receive returns the next NPDU addressed to the client and the link
+	// address of its sender. A timeout is reported as a net.Error.
+	Receive(timeout time.Duration) ([]byte, net.Addr, error)
+
+	// MAC returns the BACnet MAC address of a link address
+	MAC(addr net.Addr) []byte
+
+	// Addr returns the link address of a BACnet MAC address
+	Addr(mac []byte) (net.Addr, error)
+
+	// ParseAddr parses the textual form of a link address
+	ParseAddr(s string) (net.Addr, error)
+}
+
+// linkTimeoutError is returned by data links when Receive times out
+type linkTimeoutError struct{}
+
+func (linkTimeoutError) Error() string   { return "bacnet: receive timeout" }
+func (linkTimeoutError) Timeout() bool   { return true }
+func (linkTimeoutError) Temporary() bool { return true }
+
+// maxPacketSize is the largest datagram received by the IP data links
+const maxPacketSize = 1500
+
+// packetPool recycles receive buffers of maxPacketSize bytes
+var packetPool = sync.Pool{
+	New: func() any {
+		buf := make([]byte, maxPacketSize)
+		return &buf
+	},
+}
+
+// bufferedReceiver is implemented by data links that can receive into a
+// caller's buffer, letting the client recycle buffers through packetPool.
+// The returned NPDU is a slice of buf.
+type bufferedReceiver interface {
+	ReceiveInto(buf []byte, timeout time.Duration) ([]byte, net.Addr, error)
+}
+
+// deviceBinder is implemented by data links that complete a parsed address
+// with the device instance when a device is bound statically
+type deviceBinder interface {
+	bindDevice(deviceID uint32, addr net.Addr) net.Addr
+}
+
+// multiLink attaches the client to several data links at once, e.g.
+// BACnet/IP and BACnet/IPv6 in dual-stack mode. Addresses are dispatched
+// to the link that recognizes them; broadcasts go out on every link.
+type multiLink struct {
+	links []dataLink
+
+	rx     chan linkPacket
+	done   chan struct{}
+	wg     sync.WaitGroup
+	mu     sync.Mutex
+	closed bool
+}
+
+// linkPacket is an NPDU received on one of the links of a multiLink
+type linkPacket struct {
+	npdu []byte
+	from net.Addr
+}
+
+// newMultiLink combines data links
+func newMultiLink(links ...dataLink) *multiLink {
+	return &multiLink{
+		links: links,
+		rx:    make(chan linkPacket, 64),
+	}
+}
+
+func (m *multiLink) Open(ctx context.Context) error {
+	for i, link := range m.links {
+		if err := link.Open(ctx); err != nil {
+			for _, opened := range m.links[:i] {
+				opened.Close()
+			}
+			return err
+		}
+	}
+
+	m.mu.Lock()
+	m.closed = false
+	m.done = make(chan struct{})
+	m.mu.Unlock()
+
+	for _, link := range m.links {
+		m.wg.Add(1)
+		go m.forward(link)
+	}
+	return nil
+}
+
+// forward passes the NPDUs received on link to Receive
+func (m *multiLink) forward(link dataLink) {
+	defer m.wg.Done()
+
+	for {
+		npdu, from, err := link.Receive(100 * time.Millisecond)
+		if err != nil {
+			if link.IsClosed() {
+				return
+			}
+			continue
+		}
+		select {
+		case m.rx <- linkPacket{npdu: npdu, from: from}:
+		case <-m.done:
+			return
+		}
+	}
+}
+
+func (m *multiLink) Close() error {
+	m.mu.Lock()
+	if m.closed {
+		m.mu.Unlock()
+		return nil
+	}
+	m.closed = true
+	if m.done != nil {
+		close(m.done)
+	}
+	m.mu.Unlock()
+
+	var firstErr error
+	for _, link := range m.links {
+		if err := link.Close(); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	m.wg.Wait()
+	return firstErr
+}
+
+func (m *multiLink) IsClosed() bool {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.closed
+}
+
+// LocalAddr returns the address of the first link
+func (m *multiLink) LocalAddr() net.Addr {
+	return m.links[0].LocalAddr()
+}
+
+// linkFor returns the link that owns addr
+func (m *multiLink) linkFor(addr net.Addr) (dataLink, error) {
+	for _, link := range m.links {
+		if link.MAC(addr) != nil {
+			return link, nil
+		}
+	}
+	return nil, fmt.Errorf("no data link for address %v", addr)
+}
+
+func (m *multiLink) Send(ctx context.Context, addr net.Addr, npdu []byte) error {
+	link, err := m.linkFor(addr)
+	if err != nil {
+		return err
+	}
+	return link.Send(ctx, addr, npdu)
+}
+
+func (m *multiLink) Broadcast(ctx context.Context, npdu []byte) error {
+	var firstErr error
+	for _, link := range m.links {
+		if err := link.Broadcast(ctx, npdu); err != nil && firstErr == nil {
+			firstErr = err
+		}
+	}
+	return firstErr
+}
+
+func (m *multiLink) Receive(timeout time.Duration) ([]byte, net.Addr, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case p := <-m.rx:
+		return p.npdu, p.from, nil
+	case <-timer.C:
+		if m.IsClosed() {
+			return nil, nil, ErrConnectionClosed
+		}
+		return nil, nil, linkTimeoutError{}
+	}
+}
+
+func (m *multiLink) MAC(addr net.Addr) []byte {
+	for _, link := range m.links {
+		if mac := link.MAC(addr); mac != nil {
+			return mac
+		}
+	}
+	return nil
+}
+
+// Addr returns the address from the first link accepting the MAC; the
+// links have MAC addresses of distinct lengths
+func (m *multiLink) Addr(mac []byte) (net.Addr, error) {
+	for _, link := range m.links {
+		if addr, err := link.Addr(mac); err == nil {
+			return addr, nil
+		}
+	}
+	return nil, fmt.Errorf("invalid device address format")
+}
+
+func (m *multiLink) ParseAddr(s string) (net.Addr, error) {
+	var firstErr error
+	for _, link := range m.links {
+		addr, err := link.ParseAddr(s)
+		if err == nil {
+			return addr, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, firstErr
+}
+
+func (m *multiLink) bindDevice(deviceID uint32, addr net.Addr) net.Addr {
+	link, err := m.linkFor(addr)
+	if err != nil {
+		return addr
+	}
+	if binder, ok := link.(deviceBinder); ok {
+		return binder.bindDevice(deviceID, addr)
+	}
+	return addr
+}
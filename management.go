@@ -0,0 +1,170 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// ReinitializedState is the state requested by ReinitializeDevice
+type ReinitializedState uint8
+
+const (
+	ReinitColdstart       ReinitializedState = 0
+	ReinitWarmstart       ReinitializedState = 1
+	ReinitStartBackup     ReinitializedState = 2
+	ReinitEndBackup       ReinitializedState = 3
+	ReinitStartRestore    ReinitializedState = 4
+	ReinitEndRestore      ReinitializedState = 5
+	ReinitAbortRestore    ReinitializedState = 6
+	ReinitActivateChanges ReinitializedState = 7
+)
+
+func (s ReinitializedState) String() string {
+	names := map[ReinitializedState]string{
+		ReinitColdstart:       "coldstart",
+		ReinitWarmstart:       "warmstart",
+		ReinitStartBackup:     "start-backup",
+		ReinitEndBackup:       "end-backup",
+		ReinitStartRestore:    "start-restore",
+		ReinitEndRestore:      "end-restore",
+		ReinitAbortRestore:    "abort-restore",
+		ReinitActivateChanges: "activate-changes",
+	}
+	if name, ok := names[s]; ok {
+		return name
+	}
+	return fmt.Sprintf("reinitialized-state(%d)", s)
+}
+
+// EnableDisable is the communication state requested by
+// DeviceCommunicationControl
+type EnableDisable uint8
+
+const (
+	CommunicationEnable            EnableDisable = 0
+	CommunicationDisable           EnableDisable = 1
+	CommunicationDisableInitiation EnableDisable = 2
+)
+
+func (s EnableDisable) String() string {
+	switch s {
+	case CommunicationEnable:
+		return "enable"
+	case CommunicationDisable:
+		return "disable"
+	case CommunicationDisableInitiation:
+		return "disable-initiation"
+	default:
+		return fmt.Sprintf("enable-disable(%d)", s)
+	}
+}
+
+// maxPasswordLength is the longest password of device management services
+const maxPasswordLength = 20
+
+// ReinitializeDevice asks a device to restart, or to start or end a backup
+// or restore procedure. The password is left out when empty.
+func (c *Client) ReinitializeDevice(ctx context.Context, deviceID uint32, state ReinitializedState, password string) error {
+	if c.isStandby() {
+		return ErrStandby
+	}
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("bacnet: password longer than %d characters", maxPasswordLength)
+	}
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	e := NewEncoder(make([]byte, 0, 32))
+	e.ContextEnumerated(0, uint32(state))
+	if password != "" {
+		e.ContextCharacterString(1, password)
+	}
+
+	_, err = c.sendRequest(ctx, deviceID, addr, ServiceReinitializeDevice, e.Bytes())
+	return err
+}
+
+// DeviceCommunicationControl enables or disables the communications of a
+// device. A disabled device only answers DeviceCommunicationControl and
+// ReinitializeDevice requests until it is enabled or the duration, rounded
+// up to minutes, expires. A zero duration disables it indefinitely. The
+// password is left out when empty.
+func (c *Client) DeviceCommunicationControl(ctx context.Context, deviceID uint32, state EnableDisable, duration time.Duration, password string) error {
+	if c.isStandby() {
+		return ErrStandby
+	}
+	if len(password) > maxPasswordLength {
+		return fmt.Errorf("bacnet: password longer than %d characters", maxPasswordLength)
+	}
+	minutes := (duration + time.Minute - 1) / time.Minute
+	if minutes > 0xFFFF {
+		return fmt.Errorf("bacnet: duration %v longer than 65535 minutes", duration)
+	}
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	e := NewEncoder(make([]byte, 0, 32))
+	if minutes > 0 {
+		e.ContextUnsigned(0, uint32(minutes))
+	}
+	e.ContextEnumerated(1, uint32(state))
+	if password != "" {
+		e.ContextCharacterString(2, password)
+	}
+
+	_, err = c.sendRequest(ctx, deviceID, addr, ServiceDeviceCommunicationControl, e.Bytes())
+	return err
+}
+
+// TimeSynchronization sends t to a device as its local date and time
+func (c *Client) TimeSynchronization(ctx context.Context, deviceID uint32, t time.Time) error {
+	return c.sendTimeSynchronization(ctx, deviceID, ServiceTimeSynchronization, t)
+}
+
+// UTCTimeSynchronization sends t to a device as a UTC date and time
+func (c *Client) UTCTimeSynchronization(ctx context.Context, deviceID uint32, t time.Time) error {
+	return c.sendTimeSynchronization(ctx, deviceID, ServiceUTCTimeSynchronization, t.UTC())
+}
+
+// BroadcastTimeSynchronization broadcasts t as the local date and time of
+// t's location, or as a UTC date and time
+func (c *Client) BroadcastTimeSynchronization(ctx context.Context, t time.Time, utc bool) error {
+	service := ServiceTimeSynchronization
+	if utc {
+		service = ServiceUTCTimeSynchronization
+		t = t.UTC()
+	}
+	e := NewEncoder(make([]byte, 0, 10))
+	encodeDateTime(e, t)
+	return c.sendUnconfirmedRequest(ctx, nil, true, service, e.Bytes())
+}
+
+// sendTimeSynchronization sends a time synchronization request to a device
+func (c *Client) sendTimeSynchronization(ctx context.Context, deviceID uint32, service UnconfirmedServiceChoice, t time.Time) error {
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+	e := NewEncoder(make([]byte, 0, 10))
+	encodeDateTime(e, t)
+	return c.sendUnconfirmedRequest(ctx, addr, false, service, e.Bytes())
+}
@@ -15,6 +15,7 @@
 package bacnet
 
 import (
+	"slices"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -45,6 +46,61 @@ func (c *Counter) Reset() {
 	atomic.StoreInt64(&c.value, 0)
 }
 
+// CounterMap is a thread-safe set of counters keyed by label. The zero
+// value is ready to use.
+type CounterMap struct {
+	mu       sync.RWMutex
+	counters map[string]*Counter
+}
+
+// Inc increments the counter of a label by 1
+func (c *CounterMap) Inc(label string) {
+	c.mu.RLock()
+	counter, ok := c.counters[label]
+	c.mu.RUnlock()
+
+	if !ok {
+		c.mu.Lock()
+		if c.counters == nil {
+			c.counters = make(map[string]*Counter)
+		}
+		if counter, ok = c.counters[label]; !ok {
+			counter = &Counter{}
+			c.counters[label] = counter
+		}
+		c.mu.Unlock()
+	}
+	counter.Inc()
+}
+
+// Value returns the counter of a label
+func (c *CounterMap) Value(label string) int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	if counter, ok := c.counters[label]; ok {
+		return counter.Value()
+	}
+	return 0
+}
+
+// Values returns the counters by label
+func (c *CounterMap) Values() map[string]int64 {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	values := make(map[string]int64, len(c.counters))
+	for label, counter := range c.counters {
+		values[label] = counter.Value()
+	}
+	return values
+}
+
+// Reset removes all counters
+func (c *CounterMap) Reset() {
+	c.mu.Lock()
+	c.counters = nil
+	c.mu.Unlock()
+}
+
 // Gauge is a thread-safe gauge that can go up and down
 type Gauge struct {
 	value int64
@@ -75,6 +131,21 @@ func (g *Gauge) Value() int64 {
 	return atomic.LoadInt64(&g.value)
 }
 
+// DefaultLatencyBuckets are the upper bounds of the latency histogram
+// buckets unless set with WithLatencyBuckets. A last bucket holds the
+// measurements above the highest bound.
+var DefaultLatencyBuckets = []time.Duration{
+	time.Millisecond,
+	5 * time.Millisecond,
+	10 * time.Millisecond,
+	25 * time.Millisecond,
+	50 * time.Millisecond,
+	100 * time.Millisecond,
+	250 * time.Millisecond,
+	500 * time.Millisecond,
+	time.Second,
+}
+
 // LatencyHistogram tracks latency measurements
 type LatencyHistogram struct {
 	mu      sync.RWMutex
@@ -82,14 +153,27 @@ type LatencyHistogram struct {
 	sum     int64 // nanoseconds
 	min     int64
 	max     int64
-	buckets []int64 // counts for each bucket
+	bounds  []time.Duration
+	buckets []int64 // counts for each bucket, one more than bounds
 }
 
-// NewLatencyHistogram creates a new latency histogram
+// NewLatencyHistogram creates a new latency histogram with the default
+// buckets
 func NewLatencyHistogram() *LatencyHistogram {
+	return NewLatencyHistogramWithBuckets(DefaultLatencyBuckets)
+}
+
+// NewLatencyHistogramWithBuckets creates a latency histogram with the given
+// bucket upper bounds, which are sorted and deduplicated
+func NewLatencyHistogramWithBuckets(bounds []time.Duration) *LatencyHistogram {
+	sorted := append([]time.Duration(nil), bounds...)
+	slices.Sort(sorted)
+	sorted = slices.Compact(sorted)
+
 	return &LatencyHistogram{
 		min:     -1, // Indicates no measurements yet
-		buckets: make([]int64, 10), // <1ms, <5ms, <10ms, <25ms, <50ms, <100ms, <250ms, <500ms, <1s, >=1s
+		bounds:  sorted,
+		buckets: make([]int64, len(sorted)+1),
 	}
 }
 
@@ -110,30 +194,14 @@ func (h *LatencyHistogram) Record(d time.Duration) {
 		h.max = ns
 	}
 
-	// Update bucket
-	ms := d.Milliseconds()
-	switch {
-	case ms < 1:
-		h.buckets[0]++
-	case ms < 5:
-		h.buckets[1]++
-	case ms < 10:
-		h.buckets[2]++
-	case ms < 25:
-		h.buckets[3]++
-	case ms < 50:
-		h.buckets[4]++
-	case ms < 100:
-		h.buckets[5]++
-	case ms < 250:
-		h.buckets[6]++
-	case ms < 500:
-		h.buckets[7]++
-	case ms < 1000:
-		h.buckets[8]++
-	default:
-		h.buckets[9]++
-	}
+	// Bucket i counts durations below bounds[i]
+	i, _ := slices.BinarySearchFunc(h.bounds, d, func(bound, d time.Duration) int {
+		if bound <= d {
+			return -1
+		}
+		return 1
+	})
+	h.buckets[i]++
 }
 
 // Stats returns histogram statistics
@@ -143,6 +211,7 @@ func (h *LatencyHistogram) Stats() LatencyStats {
 
 	stats := LatencyStats{
 		Count:   h.count,
+		Bounds:  append([]time.Duration(nil), h.bounds...),
 		Buckets: make([]int64, len(h.buckets)),
 	}
 	copy(stats.Buckets, h.buckets)
@@ -151,11 +220,43 @@ func (h *LatencyHistogram) Stats() LatencyStats {
 		stats.Min = time.Duration(h.min)
 		stats.Max = time.Duration(h.max)
 		stats.Avg = time.Duration(h.sum / h.count)
+		stats.P50 = h.quantile(0.50)
+		stats.P90 = h.quantile(0.90)
+		stats.P95 = h.quantile(0.95)
+		stats.P99 = h.quantile(0.99)
 	}
 
 	return stats
 }
 
+// quantile estimates a quantile from the cumulative bucket counts,
+// interpolating linearly within the bucket that holds it. Bucket edges are
+// narrowed to the observed minimum and maximum.
+func (h *LatencyHistogram) quantile(q float64) time.Duration {
+	rank := q * float64(h.count)
+	var cumulative int64
+	for i, n := range h.buckets {
+		if n == 0 || float64(cumulative+n) < rank {
+			cumulative += n
+			continue
+		}
+
+		lower, upper := time.Duration(h.min), time.Duration(h.max)
+		if i > 0 && h.bounds[i-1] > lower {
+			lower = h.bounds[i-1]
+		}
+		if i < len(h.bounds) && h.bounds[i] < upper {
+			upper = h.bounds[i]
+		}
+		if upper < lower {
+			return lower
+		}
+		fraction := (rank - float64(cumulative)) / float64(n)
+		return lower + time.Duration(fraction*float64(upper-lower))
+	}
+	return time.Duration(h.max)
+}
+
 // Reset resets the histogram
 func (h *LatencyHistogram) Reset() {
 	h.mu.Lock()
@@ -172,10 +273,20 @@ func (h *LatencyHistogram) Reset() {
 
 // LatencyStats contains latency statistics
 type LatencyStats struct {
-	Count   int64
-	Min     time.Duration
-	Max     time.Duration
-	Avg     time.Duration
+	Count int64
+	Min   time.Duration
+	Max   time.Duration
+	Avg   time.Duration
+
+	// Percentiles estimated from the buckets
+	P50 time.Duration
+	P90 time.Duration
+	P95 time.Duration
+	P99 time.Duration
+
+	// Buckets[i] counts the measurements below Bounds[i]; the last bucket
+	// counts the rest
+	Bounds  []time.Duration
 	Buckets []int64
 }
 
@@ -199,6 +310,16 @@ type Metrics struct {
 	RejectsReceived  Counter
 	AbortsReceived   Counter
 
+	// Failed responses to our requests by "class/code" of Error responses,
+	// and by reason of Reject and Abort responses
+	ErrorCodes    CounterMap
+	RejectReasons CounterMap
+	AbortReasons  CounterMap
+
+	// Aborts received by origin: "server" for aborts of our requests,
+	// "client" for peers aborting their requests to our server
+	AbortOrigins CounterMap
+
 	// Discovery metrics
 	WhoIsSent        Counter
 	IAmReceived      Counter
@@ -207,6 +328,7 @@ type Metrics struct {
 	// COV metrics
 	COVSubscriptions Counter
 	COVNotifications Counter
+	COVNotificationsSent Counter
 
 	// Latency
 	RequestLatency *LatencyHistogram
@@ -218,20 +340,142 @@ type Metrics struct {
 	// Current state
 	ActiveRequests Gauge
 	ActiveSubscriptions Gauge
+	QueuedRequests Gauge
 
 	// Timestamps
 	startTime     time.Time
 	lastActivity  atomic.Int64
+
+	// Per-device breakdown
+	devicesMu sync.RWMutex
+	devices   map[uint32]*DeviceMetrics
+
+	// Latency bucket bounds shared by all histograms
+	latencyBuckets []time.Duration
+
+	// Site namespace label
+	site string
 }
 
-// NewMetrics creates a new Metrics instance
+// NewMetrics creates a new Metrics instance with the default latency
+// buckets
 func NewMetrics() *Metrics {
+	return newMetrics(DefaultLatencyBuckets)
+}
+
+// newMetrics creates a Metrics instance whose latency histograms use the
+// given bucket bounds
+func newMetrics(latencyBuckets []time.Duration) *Metrics {
 	return &Metrics{
-		RequestLatency: NewLatencyHistogram(),
+		RequestLatency: NewLatencyHistogramWithBuckets(latencyBuckets),
 		startTime:      time.Now(),
+		devices:        make(map[uint32]*DeviceMetrics),
+		latencyBuckets: latencyBuckets,
 	}
 }
 
+// DeviceMetrics holds the request metrics of one remote device
+type DeviceMetrics struct {
+	RequestsSent      Counter
+	RequestsSucceeded Counter
+	RequestsFailed    Counter
+	RequestsTimedOut  Counter
+	RequestLatency    *LatencyHistogram
+
+	mu            sync.Mutex
+	lastError     error
+	lastErrorTime time.Time
+	lastSeen      time.Time
+}
+
+// Device returns the metrics of a remote device, or nil if the client has
+// not exchanged anything with it
+func (m *Metrics) Device(deviceID uint32) *DeviceMetrics {
+	m.devicesMu.RLock()
+	defer m.devicesMu.RUnlock()
+	return m.devices[deviceID]
+}
+
+// device returns the metrics of a remote device, creating them on first use
+func (m *Metrics) device(deviceID uint32) *DeviceMetrics {
+	if dm := m.Device(deviceID); dm != nil {
+		return dm
+	}
+
+	m.devicesMu.Lock()
+	defer m.devicesMu.Unlock()
+	dm, ok := m.devices[deviceID]
+	if !ok {
+		dm = &DeviceMetrics{RequestLatency: NewLatencyHistogramWithBuckets(m.latencyBuckets)}
+		m.devices[deviceID] = dm
+	}
+	return dm
+}
+
+// RecordSeen records that the device sent something
+func (d *DeviceMetrics) RecordSeen() {
+	d.mu.Lock()
+	d.lastSeen = time.Now()
+	d.mu.Unlock()
+}
+
+// RecordError records the latest failure of a request to the device
+func (d *DeviceMetrics) RecordError(err error) {
+	d.mu.Lock()
+	d.lastError = err
+	d.lastErrorTime = time.Now()
+	d.mu.Unlock()
+}
+
+// LastError returns the latest failure of a request to the device, or nil
+func (d *DeviceMetrics) LastError() error {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastError
+}
+
+// LastSeen returns when the device last sent something, or the zero time
+func (d *DeviceMetrics) LastSeen() time.Time {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	return d.lastSeen
+}
+
+// Snapshot returns a snapshot of the device metrics
+func (d *DeviceMetrics) Snapshot() DeviceMetricsSnapshot {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+
+	snap := DeviceMetricsSnapshot{
+		RequestsSent:      d.RequestsSent.Value(),
+		RequestsSucceeded: d.RequestsSucceeded.Value(),
+		RequestsFailed:    d.RequestsFailed.Value(),
+		RequestsTimedOut:  d.RequestsTimedOut.Value(),
+		LatencyStats:      d.RequestLatency.Stats(),
+		LastErrorTime:     d.lastErrorTime,
+		LastSeen:          d.lastSeen,
+	}
+	if d.lastError != nil {
+		snap.LastError = d.lastError.Error()
+	}
+	return snap
+}
+
+// DeviceMetricsSnapshot is a point-in-time snapshot of the metrics of one
+// remote device
+type DeviceMetricsSnapshot struct {
+	RequestsSent      int64
+	RequestsSucceeded int64
+	RequestsFailed    int64
+	RequestsTimedOut  int64
+
+	LatencyStats LatencyStats
+
+	LastError     string
+	LastErrorTime time.Time
+	LastSeen      time.Time
+}
+
 // RecordActivity records the last activity time
 func (m *Metrics) RecordActivity() {
 	m.lastActivity.Store(time.Now().UnixNano())
@@ -265,23 +509,41 @@ func (m *Metrics) Reset() {
 	m.ErrorsReceived.Reset()
 	m.RejectsReceived.Reset()
 	m.AbortsReceived.Reset()
+	m.ErrorCodes.Reset()
+	m.RejectReasons.Reset()
+	m.AbortReasons.Reset()
+	m.AbortOrigins.Reset()
 	m.WhoIsSent.Reset()
 	m.IAmReceived.Reset()
 	m.DevicesDiscovered.Reset()
 	m.COVSubscriptions.Reset()
 	m.COVNotifications.Reset()
+	m.COVNotificationsSent.Reset()
 	m.RequestLatency.Reset()
 	m.BytesSent.Reset()
 	m.BytesReceived.Reset()
 	m.ActiveRequests.Set(0)
+	m.QueuedRequests.Set(0)
 	m.ActiveSubscriptions.Set(0)
 	m.startTime = time.Now()
 	m.lastActivity.Store(0)
+
+	m.devicesMu.Lock()
+	m.devices = make(map[uint32]*DeviceMetrics)
+	m.devicesMu.Unlock()
 }
 
 // Snapshot returns a snapshot of current metrics
 func (m *Metrics) Snapshot() MetricsSnapshot {
+	m.devicesMu.RLock()
+	devices := make(map[uint32]DeviceMetricsSnapshot, len(m.devices))
+	for id, dm := range m.devices {
+		devices[id] = dm.Snapshot()
+	}
+	m.devicesMu.RUnlock()
+
 	return MetricsSnapshot{
+		Site:   m.site,
 		Uptime: m.Uptime(),
 
 		ConnectAttempts:  m.ConnectAttempts.Value(),
@@ -299,12 +561,18 @@ func (m *Metrics) Snapshot() MetricsSnapshot {
 		RejectsReceived:   m.RejectsReceived.Value(),
 		AbortsReceived:    m.AbortsReceived.Value(),
 
+		ErrorCodes:    m.ErrorCodes.Values(),
+		RejectReasons: m.RejectReasons.Values(),
+		AbortReasons:  m.AbortReasons.Values(),
+		AbortOrigins:  m.AbortOrigins.Values(),
+
 		WhoIsSent:         m.WhoIsSent.Value(),
 		IAmReceived:       m.IAmReceived.Value(),
 		DevicesDiscovered: m.DevicesDiscovered.Value(),
 
 		COVSubscriptions: m.COVSubscriptions.Value(),
 		COVNotifications: m.COVNotifications.Value(),
+		COVNotificationsSent: m.COVNotificationsSent.Value(),
 
 		LatencyStats: m.RequestLatency.Stats(),
 
@@ -313,13 +581,17 @@ func (m *Metrics) Snapshot() MetricsSnapshot {
 
 		ActiveRequests:      m.ActiveRequests.Value(),
 		ActiveSubscriptions: m.ActiveSubscriptions.Value(),
+		QueuedRequests:      m.QueuedRequests.Value(),
 
 		LastActivity: m.LastActivity(),
+
+		Devices: devices,
 	}
 }
 
 // MetricsSnapshot is a point-in-time snapshot of metrics
 type MetricsSnapshot struct {
+	Site   string
 	Uptime time.Duration
 
 	ConnectAttempts  int64
@@ -337,12 +609,18 @@ type MetricsSnapshot struct {
 	RejectsReceived   int64
 	AbortsReceived    int64
 
+	ErrorCodes    map[string]int64
+	RejectReasons map[string]int64
+	AbortReasons  map[string]int64
+	AbortOrigins  map[string]int64
+
 	WhoIsSent         int64
 	IAmReceived       int64
 	DevicesDiscovered int64
 
 	COVSubscriptions int64
 	COVNotifications int64
+	COVNotificationsSent int64
 
 	LatencyStats LatencyStats
 
@@ -351,6 +629,10 @@ type MetricsSnapshot struct {
 
 	ActiveRequests      int64
 	ActiveSubscriptions int64
+	QueuedRequests      int64
 
 	LastActivity time.Time
+
+	// Devices breaks the request metrics down by device instance
+	Devices map[uint32]DeviceMetricsSnapshot
 }
@@ -0,0 +1,786 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net"
+	"os"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/edgeo-scada/bacnet/internal/serial"
+)
+
+// MS/TP frame types (Clause 9.3)
+const (
+	mstpToken                 = 0x00
+	mstpPollForMaster         = 0x01
+	mstpReplyToPollForMaster  = 0x02
+	mstpTestRequest           = 0x03
+	mstpTestResponse          = 0x04
+	mstpDataExpectingReply    = 0x05
+	mstpDataNotExpectingReply = 0x06
+	mstpReplyPostponed        = 0x07
+)
+
+// MS/TP parameters (Clause 9.5.3)
+const (
+	// MSTPBroadcast is the MS/TP broadcast address
+	MSTPBroadcast = 0xFF
+
+	// MSTPMaxAPDULength is the maximum APDU carried by MS/TP
+	MSTPMaxAPDULength = 480
+
+	// DefaultMSTPMaxMaster is the highest master address polled by default
+	DefaultMSTPMaxMaster = 127
+
+	mstpMaxData       = 501
+	mstpNpoll         = 50
+	mstpNretryToken   = 1
+	mstpTframeAbort   = 100 * time.Millisecond
+	mstpTnoToken      = 500 * time.Millisecond
+	mstpTreplyTimeout = 255 * time.Millisecond
+	mstpTreplyDelay   = 250 * time.Millisecond
+	mstpTslot         = 10 * time.Millisecond
+	mstpTusageTimeout = 50 * time.Millisecond
+)
+
+// MSTPAddr is an MS/TP station address
+type MSTPAddr uint8
+
+// Network implements net.Addr
+func (a MSTPAddr) Network() string { return "mstp" }
+
+// String returns the station address in decimal
+func (a MSTPAddr) String() string { return strconv.Itoa(int(a)) }
+
+// mstpFrame is a received MS/TP frame
+type mstpFrame struct {
+	Type        byte
+	Destination byte
+	Source      byte
+	Data        []byte
+}
+
+// mstpOutgoing is an NPDU waiting for the token
+type mstpOutgoing struct {
+	dest byte
+	npdu []byte
+}
+
+// mstpCRC8 updates the header CRC (Annex G.1)
+func mstpCRC8(data, crc byte) byte {
+	c := uint16(crc ^ data)
+	c = c ^ (c << 1) ^ (c << 2) ^ (c << 3) ^ (c << 4) ^ (c << 5) ^ (c << 6) ^ (c << 7)
+	return byte((c & 0xFE) ^ ((c >> 8) & 1))
+}
+
+// mstpCRC16 updates the data CRC (Annex G.2)
+func mstpCRC16(data byte, crc uint16) uint16 {
+	low := (crc & 0xFF) ^ uint16(data)
+	return (crc >> 8) ^ (low << 8) ^ (low << 3) ^ (low << 12) ^ (low >> 4) ^ (low & 0x0F) ^ ((low & 0x0F) << 7)
+}
+
+// encodeMSTPFrame encodes a frame with preamble and CRCs
+func encodeMSTPFrame(frameType, dest, src byte, data []byte) []byte {
+	buf := make([]byte, 0, 8+len(data)+2)
+	buf = append(buf, 0x55, 0xFF, frameType, dest, src, byte(len(data)>>8), byte(len(data)))
+
+	crc := byte(0xFF)
+	for _, b := range buf[2:] {
+		crc = mstpCRC8(b, crc)
+	}
+	buf = append(buf, ^crc)
+
+	if len(data) > 0 {
+		crc16 := uint16(0xFFFF)
+		for _, b := range data {
+			crc16 = mstpCRC16(b, crc16)
+		}
+		crc16 = ^crc16
+		buf = append(buf, data...)
+		buf = append(buf, byte(crc16), byte(crc16>>8))
+	}
+
+	return buf
+}
+
+// mstpReceiver assembles frames from the received byte stream (Clause 9.5.4)
+type mstpReceiver struct {
+	buf  []byte
+	last time.Time
+}
+
+// feed appends received bytes and returns the complete, valid frames
+func (r *mstpReceiver) feed(data []byte, now time.Time) []mstpFrame {
+	// A gap inside a frame aborts it
+	if len(r.buf) > 0 && now.Sub(r.last) > mstpTframeAbort {
+		r.buf = r.buf[:0]
+	}
+	r.last = now
+	r.buf = append(r.buf, data...)
+
+	var frames []mstpFrame
+	for {
+		// Find the preamble
+		start := -1
+		for i := 0; i+1 < len(r.buf); i++ {
+			if r.buf[i] == 0x55 && r.buf[i+1] == 0xFF {
+				start = i
+				break
+			}
+		}
+		if start < 0 {
+			if n := len(r.buf); n > 0 && r.buf[n-1] == 0x55 {
+				r.buf = append(r.buf[:0], 0x55)
+			} else {
+				r.buf = r.buf[:0]
+			}
+			return frames
+		}
+		r.buf = r.buf[start:]
+
+		if len(r.buf) < 8 {
+			return frames
+		}
+
+		crc := byte(0xFF)
+		for _, b := range r.buf[2:8] {
+			crc = mstpCRC8(b, crc)
+		}
+		length := int(r.buf[5])<<8 | int(r.buf[6])
+		if crc != 0x55 || length > mstpMaxData {
+			// Bad header: resynchronize after this preamble
+			r.buf = r.buf[2:]
+			continue
+		}
+
+		frame := mstpFrame{
+			Type:        r.buf[2],
+			Destination: r.buf[3],
+			Source:      r.buf[4],
+		}
+
+		if length == 0 {
+			r.buf = r.buf[8:]
+			frames = append(frames, frame)
+			continue
+		}
+
+		if len(r.buf) < 8+length+2 {
+			return frames
+		}
+
+		crc16 := uint16(0xFFFF)
+		for _, b := range r.buf[8 : 8+length+2] {
+			crc16 = mstpCRC16(b, crc16)
+		}
+		if crc16 == 0xF0B8 {
+			frame.Data = append([]byte(nil), r.buf[8:8+length]...)
+			frames = append(frames, frame)
+		}
+		r.buf = r.buf[8+length+2:]
+	}
+}
+
+// mstpLink is the MS/TP data link (Clause 9) running a master node on a
+// serial port
+type mstpLink struct {
+	portName      string
+	baud          int
+	mac           byte
+	maxMaster     byte
+	maxInfoFrames int
+	logger        *slog.Logger
+	clock         Clock
+
+	port *os.File
+
+	mu     sync.Mutex
+	closed bool
+	done   chan struct{}
+	wg     sync.WaitGroup
+
+	frames chan mstpFrame
+	tx     chan mstpOutgoing
+	urgent chan mstpOutgoing
+	rx     chan mstpInbound
+
+	// Station state (Clause 9.5.2)
+	silence     time.Time
+	silenceMu   sync.Mutex
+	nextStation byte
+	pollStation byte
+	tokenCount  int
+	frameCount  int
+	retryCount  int
+	soleMaster  bool
+	pending     *mstpOutgoing
+}
+
+// mstpInbound is an NPDU received over MS/TP
+type mstpInbound struct {
+	npdu []byte
+	from MSTPAddr
+}
+
+// newMSTPLink creates an MS/TP data link from the client options
+func newMSTPLink(o *clientOptions) *mstpLink {
+	return &mstpLink{
+		portName:      o.mstpPort,
+		baud:          o.mstpBaud,
+		mac:           o.mstpMAC,
+		maxMaster:     o.mstpMaxMaster,
+		maxInfoFrames: o.mstpMaxInfoFrames,
+		logger:        o.logger,
+		clock:         o.clock,
+		frames:        make(chan mstpFrame, 16),
+		tx:            make(chan mstpOutgoing, 64),
+		urgent:        make(chan mstpOutgoing, 16),
+		rx:            make(chan mstpInbound, 64),
+	}
+}
+
+func (l *mstpLink) Open(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.port != nil {
+		return nil
+	}
+	if l.mac > 127 {
+		return fmt.Errorf("MS/TP master address %d out of range 0-127", l.mac)
+	}
+
+	port, err := serial.Open(l.portName, l.baud)
+	if err != nil {
+		return err
+	}
+
+	l.port = port
+	l.closed = false
+	l.done = make(chan struct{})
+	l.touch()
+
+	l.wg.Add(2)
+	go l.reader()
+	go l.master()
+
+	return nil
+}
+
+func (l *mstpLink) Close() error {
+	l.mu.Lock()
+	if l.closed || l.port == nil {
+		l.closed = true
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	close(l.done)
+	port := l.port
+	l.port = nil
+	l.mu.Unlock()
+
+	err := port.Close()
+	l.wg.Wait()
+	return err
+}
+
+func (l *mstpLink) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// LocalAddr returns the station address
+func (l *mstpLink) LocalAddr() net.Addr {
+	return MSTPAddr(l.mac)
+}
+
+func (l *mstpLink) Send(ctx context.Context, addr net.Addr, npdu []byte) error {
+	mac, ok := addr.(MSTPAddr)
+	if !ok {
+		return fmt.Errorf("mstp: unsupported address %v", addr)
+	}
+	return l.enqueue(ctx, byte(mac), npdu)
+}
+
+func (l *mstpLink) Broadcast(ctx context.Context, npdu []byte) error {
+	return l.enqueue(ctx, MSTPBroadcast, npdu)
+}
+
+// enqueue queues an NPDU until the node holds the token
+func (l *mstpLink) enqueue(ctx context.Context, dest byte, npdu []byte) error {
+	if len(npdu) > mstpMaxData {
+		return fmt.Errorf("mstp: NPDU of %d bytes exceeds %d", len(npdu), mstpMaxData)
+	}
+	if l.IsClosed() {
+		return ErrNotConnected
+	}
+
+	select {
+	case l.queue(npdu) <- mstpOutgoing{dest: dest, npdu: npdu}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *mstpLink) Receive(timeout time.Duration) ([]byte, net.Addr, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case in := <-l.rx:
+		return in.npdu, in.from, nil
+	case <-timer.C:
+		if l.IsClosed() {
+			return nil, nil, ErrConnectionClosed
+		}
+		return nil, nil, linkTimeoutError{}
+	}
+}
+
+// MAC returns the one-octet station address
+func (l *mstpLink) MAC(addr net.Addr) []byte {
+	mac, ok := addr.(MSTPAddr)
+	if !ok {
+		return nil
+	}
+	return []byte{byte(mac)}
+}
+
+func (l *mstpLink) Addr(mac []byte) (net.Addr, error) {
+	if len(mac) != 1 {
+		return nil, fmt.Errorf("invalid MS/TP address length %d", len(mac))
+	}
+	return MSTPAddr(mac[0]), nil
+}
+
+// ParseAddr parses a decimal station address
+func (l *mstpLink) ParseAddr(s string) (net.Addr, error) {
+	n, err := strconv.ParseUint(s, 10, 8)
+	if err != nil || n == MSTPBroadcast {
+		return nil, fmt.Errorf("invalid MS/TP address %q", s)
+	}
+	return MSTPAddr(n), nil
+}
+
+// touch restarts the silence timer
+func (l *mstpLink) touch() {
+	l.silenceMu.Lock()
+	l.silence = time.Now()
+	l.silenceMu.Unlock()
+}
+
+// silent returns the time since the line was last active
+func (l *mstpLink) silent() time.Duration {
+	l.silenceMu.Lock()
+	defer l.silenceMu.Unlock()
+	return time.Since(l.silence)
+}
+
+// reader receives bytes from the port and passes valid frames to the
+// master state machine
+func (l *mstpLink) reader() {
+	defer l.wg.Done()
+
+	var receiver mstpReceiver
+	buf := make([]byte, 512)
+	for {
+		n, err := l.port.Read(buf)
+		if n > 0 {
+			l.touch()
+			for _, frame := range receiver.feed(buf[:n], time.Now()) {
+				if frame.Source == l.mac {
+					// Echo of our own transmission
+					continue
+				}
+				select {
+				case l.frames <- frame:
+				case <-l.done:
+					return
+				}
+			}
+		}
+		if err != nil {
+			if errors.Is(err, os.ErrClosed) || errors.Is(err, io.EOF) || l.IsClosed() {
+				return
+			}
+			l.logger.Debug("MS/TP read error", slog.String("error", err.Error()))
+			if !sleepDone(l.done, l.clock, mstpTslot) {
+				return
+			}
+		}
+	}
+}
+
+// write sends a frame
+func (l *mstpLink) write(frameType, dest byte, data []byte) {
+	if _, err := l.port.Write(encodeMSTPFrame(frameType, dest, l.mac, data)); err != nil {
+		l.logger.Debug("MS/TP write error", slog.String("error", err.Error()))
+	}
+	l.touch()
+}
+
+// master runs the master node state machine (Clause 9.5.6)
+func (l *mstpLink) master() {
+	defer l.wg.Done()
+
+	// INITIALIZE
+	l.nextStation = l.mac
+	l.pollStation = l.mac
+	l.tokenCount = mstpNpoll
+	l.soleMaster = false
+
+	state := l.idle
+	for state != nil {
+		select {
+		case <-l.done:
+			return
+		default:
+		}
+		state = state()
+	}
+}
+
+// mstpState is a state of the master node; it returns the next state
+type mstpState func() mstpState
+
+// waitFrame waits for a frame until the line has been silent for timeout
+func (l *mstpLink) waitFrame(timeout time.Duration) (mstpFrame, bool) {
+	for {
+		remaining := timeout - l.silent()
+		if remaining <= 0 {
+			return mstpFrame{}, false
+		}
+		timer := time.NewTimer(remaining)
+		select {
+		case frame := <-l.frames:
+			timer.Stop()
+			return frame, true
+		case <-timer.C:
+		case <-l.done:
+			timer.Stop()
+			return mstpFrame{}, false
+		}
+	}
+}
+
+// deliver passes a received NPDU to the client
+func (l *mstpLink) deliver(frame mstpFrame) {
+	select {
+	case l.rx <- mstpInbound{npdu: frame.Data, from: MSTPAddr(frame.Source)}:
+	default:
+		l.logger.Debug("MS/TP receive queue full, frame dropped")
+	}
+}
+
+// idle is the IDLE state: wait for frames addressed to this node
+func (l *mstpLink) idle() mstpState {
+	frame, ok := l.waitFrame(mstpTnoToken)
+	if !ok {
+		return l.noToken
+	}
+	return l.handleFrame(frame)
+}
+
+// handleFrame processes a frame received in IDLE
+func (l *mstpLink) handleFrame(frame mstpFrame) mstpState {
+	forUs := frame.Destination == l.mac
+	broadcast := frame.Destination == MSTPBroadcast
+
+	switch frame.Type {
+	case mstpToken:
+		if forUs {
+			l.frameCount = 0
+			l.soleMaster = false
+			return l.useToken
+		}
+	case mstpPollForMaster:
+		if forUs {
+			l.write(mstpReplyToPollForMaster, frame.Source, nil)
+		}
+	case mstpTestRequest:
+		if forUs {
+			l.write(mstpTestResponse, frame.Source, frame.Data)
+		}
+	case mstpDataNotExpectingReply:
+		if forUs || broadcast {
+			l.deliver(frame)
+		}
+	case mstpDataExpectingReply:
+		if broadcast {
+			l.deliver(frame)
+		} else if forUs {
+			l.deliver(frame)
+			return l.answerDataRequest(frame.Source)
+		}
+	}
+	return l.idle
+}
+
+// answerDataRequest is the ANSWER_DATA_REQUEST state: the reply is sent
+// right away if the client produces it within Treply_delay, otherwise the
+// reply is postponed until the node gets the token
+func (l *mstpLink) answerDataRequest(requester byte) mstpState {
+	timer := time.NewTimer(mstpTreplyDelay)
+	defer timer.Stop()
+
+	for {
+		select {
+		case out := <-l.tx:
+			if out.dest == requester {
+				l.write(mstpDataNotExpectingReply, out.dest, out.npdu)
+				return l.idle
+			}
+			// Unrelated traffic waits for the token
+			l.requeue(out)
+		case <-timer.C:
+			l.write(mstpReplyPostponed, requester, nil)
+			return l.idle
+		case <-l.done:
+			return nil
+		}
+	}
+}
+
+// queue returns the transmit queue of an NPDU: NPDUs with a network
+// priority above normal are sent before normal traffic
+func (l *mstpLink) queue(npdu []byte) chan mstpOutgoing {
+	if len(npdu) > 1 && NPDUControl(npdu[1])&npduPriorityMask != NPDUControlPriorityNormal {
+		return l.urgent
+	}
+	return l.tx
+}
+
+// requeue puts an NPDU back in front of the queue
+func (l *mstpLink) requeue(out mstpOutgoing) {
+	if l.pending == nil {
+		l.pending = &out
+		return
+	}
+	select {
+	case l.queue(out.npdu) <- out:
+	default:
+		l.logger.Debug("MS/TP transmit queue full, frame dropped")
+	}
+}
+
+// dequeue returns the next NPDU to send, if any
+func (l *mstpLink) dequeue() (mstpOutgoing, bool) {
+	if l.pending != nil {
+		out := *l.pending
+		l.pending = nil
+		return out, true
+	}
+	select {
+	case out := <-l.urgent:
+		return out, true
+	default:
+	}
+	select {
+	case out := <-l.tx:
+		return out, true
+	default:
+		return mstpOutgoing{}, false
+	}
+}
+
+// noToken is the NO_TOKEN state: wait a slot per station address before
+// generating a token
+func (l *mstpLink) noToken() mstpState {
+	slot := mstpTnoToken + mstpTslot*time.Duration(l.mac)
+	frame, ok := l.waitFrame(slot)
+	if ok {
+		return l.handleFrame(frame)
+	}
+
+	// GenerateToken: look for a successor
+	l.pollStation = l.next(l.mac)
+	l.nextStation = l.mac
+	l.tokenCount = 0
+	l.retryCount = 0
+	l.write(mstpPollForMaster, l.pollStation, nil)
+	return l.pollForMaster
+}
+
+// next returns the station following addr
+func (l *mstpLink) next(addr byte) byte {
+	return byte((int(addr) + 1) % (int(l.maxMaster) + 1))
+}
+
+// useToken is the USE_TOKEN state: send queued NPDUs
+func (l *mstpLink) useToken() mstpState {
+	out, ok := l.dequeue()
+	if !ok {
+		return l.doneWithToken
+	}
+
+	l.frameCount++
+	if out.dest != MSTPBroadcast && len(out.npdu) > 1 && out.npdu[1]&0x04 != 0 {
+		l.write(mstpDataExpectingReply, out.dest, out.npdu)
+		return l.waitForReply(out.dest)
+	}
+
+	l.write(mstpDataNotExpectingReply, out.dest, out.npdu)
+	return l.doneWithToken
+}
+
+// waitForReply is the WAIT_FOR_REPLY state
+func (l *mstpLink) waitForReply(dest byte) mstpState {
+	timer := time.NewTimer(mstpTreplyTimeout)
+	defer timer.Stop()
+
+	for {
+		select {
+		case frame := <-l.frames:
+			if frame.Destination != l.mac {
+				// Another node transmitting: the token was lost
+				return l.idle
+			}
+			switch frame.Type {
+			case mstpDataNotExpectingReply, mstpTestResponse:
+				if frame.Type == mstpDataNotExpectingReply {
+					l.deliver(frame)
+				}
+				return l.doneWithToken
+			case mstpReplyPostponed:
+				return l.doneWithToken
+			default:
+				return l.handleFrame(frame)
+			}
+		case <-timer.C:
+			return l.doneWithToken
+		case <-l.done:
+			return nil
+		}
+	}
+}
+
+// doneWithToken is the DONE_WITH_TOKEN state: decide whether to send
+// another frame, pass the token or poll for masters
+func (l *mstpLink) doneWithToken() mstpState {
+	if l.frameCount < l.maxInfoFrames && (l.pending != nil || len(l.urgent) > 0 || len(l.tx) > 0) {
+		return l.useToken
+	}
+
+	if l.tokenCount < mstpNpoll-1 {
+		l.tokenCount++
+		if l.soleMaster && l.nextStation == l.mac {
+			l.frameCount = 0
+			return l.useToken
+		}
+		l.write(mstpToken, l.nextStation, nil)
+		l.retryCount = 0
+		return l.passToken
+	}
+
+	if l.next(l.pollStation) != l.nextStation {
+		// Maintenance poll of the next address in the gap
+		l.pollStation = l.next(l.pollStation)
+		l.write(mstpPollForMaster, l.pollStation, nil)
+		l.retryCount = 0
+		return l.pollForMaster
+	}
+
+	if !l.soleMaster {
+		l.pollStation = l.mac
+		l.write(mstpToken, l.nextStation, nil)
+		l.retryCount = 0
+		l.tokenCount = 1
+		return l.passToken
+	}
+
+	// Sole master restarts the poll cycle
+	l.pollStation = l.next(l.nextStation)
+	l.nextStation = l.mac
+	l.retryCount = 0
+	l.tokenCount = 1
+	l.write(mstpPollForMaster, l.pollStation, nil)
+	return l.pollForMaster
+}
+
+// passToken is the PASS_TOKEN state: wait for the successor to use the
+// token
+func (l *mstpLink) passToken() mstpState {
+	frame, ok := l.waitFrame(mstpTusageTimeout)
+	if ok {
+		return l.handleFrame(frame)
+	}
+
+	if l.retryCount < mstpNretryToken {
+		l.retryCount++
+		l.write(mstpToken, l.nextStation, nil)
+		return l.passToken
+	}
+
+	// Successor is gone: find a new one
+	l.pollStation = l.next(l.nextStation)
+	l.nextStation = l.mac
+	l.retryCount = 0
+	l.tokenCount = 0
+	l.write(mstpPollForMaster, l.pollStation, nil)
+	return l.pollForMaster
+}
+
+// pollForMaster is the POLL_FOR_MASTER state
+func (l *mstpLink) pollForMaster() mstpState {
+	frame, ok := l.waitFrame(mstpTusageTimeout)
+	if ok {
+		if frame.Type == mstpReplyToPollForMaster && frame.Destination == l.mac {
+			l.soleMaster = false
+			l.nextStation = frame.Source
+			l.pollStation = l.mac
+			l.tokenCount = 0
+			l.retryCount = 0
+			l.write(mstpToken, l.nextStation, nil)
+			return l.passToken
+		}
+		return l.handleFrame(frame)
+	}
+
+	if l.soleMaster {
+		l.frameCount = 0
+		return l.useToken
+	}
+
+	if l.nextStation != l.mac {
+		// Maintenance poll done: pass the token on
+		l.retryCount = 0
+		l.write(mstpToken, l.nextStation, nil)
+		return l.passToken
+	}
+
+	if l.next(l.pollStation) != l.mac {
+		l.pollStation = l.next(l.pollStation)
+		l.retryCount = 0
+		l.write(mstpPollForMaster, l.pollStation, nil)
+		return l.pollForMaster
+	}
+
+	// Nobody answered: this node is the sole master
+	l.logger.Debug("MS/TP sole master", slog.Int("mac", int(l.mac)))
+	l.soleMaster = true
+	l.frameCount = 0
+	return l.useToken
+}
@@ -0,0 +1,222 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sort"
+	"sync"
+)
+
+// RouterInfo is a router announced with I-Am-Router-To-Network
+type RouterInfo struct {
+	// Address is the data link address of the router
+	Address net.Addr
+
+	// Networks are the network numbers reachable through the router
+	Networks []uint16
+}
+
+// NetworkNumberInfo is a Network-Number-Is announcement of the number of
+// the local network
+type NetworkNumberInfo struct {
+	// Address is the data link address of the announcing router
+	Address net.Addr
+
+	Network uint16
+
+	// Configured is true for a configured network number, false for one
+	// the router learned
+	Configured bool
+}
+
+// networkWatcher collects the network layer messages received while
+// waiting for answers
+type networkWatcher func(npdu *NPDU, addr net.Addr)
+
+// watchNetworkMessages registers a collector of network layer messages and
+// returns the function removing it
+func (c *Client) watchNetworkMessages(w networkWatcher) func() {
+	c.networkMu.Lock()
+	defer c.networkMu.Unlock()
+	if c.networkWatchers == nil {
+		c.networkWatchers = make(map[int]networkWatcher)
+	}
+	c.networkWatcherID++
+	id := c.networkWatcherID
+	c.networkWatchers[id] = w
+
+	return func() {
+		c.networkMu.Lock()
+		delete(c.networkWatchers, id)
+		c.networkMu.Unlock()
+	}
+}
+
+// dispatchNetworkMessage passes a received network layer message to the
+// collectors
+func (c *Client) dispatchNetworkMessage(npdu *NPDU, addr net.Addr) {
+	c.networkMu.RLock()
+	defer c.networkMu.RUnlock()
+	for _, w := range c.networkWatchers {
+		w(npdu, addr)
+	}
+}
+
+// sendNetworkMessage sends a network layer message; a nil addr broadcasts
+// it on the local network
+func (c *Client) sendNetworkMessage(ctx context.Context, addr net.Addr, msgType NetworkMessageType, data []byte) error {
+	if c.State() != StateConnected {
+		return ErrNotConnected
+	}
+
+	packet := make([]byte, 0, 3+len(data))
+	packet = append(packet, 0x01, byte(NPDUControlNetworkLayerMessage), byte(msgType))
+	packet = append(packet, data...)
+
+	var err error
+	if addr == nil {
+		c.traceNPDU(true, nil, packet)
+		err = c.link.Broadcast(ctx, packet)
+	} else {
+		c.traceNPDU(true, addr, packet)
+		err = c.link.Send(ctx, addr, packet)
+	}
+	if err != nil {
+		return fmt.Errorf("send network message: %w", err)
+	}
+	c.metrics.BytesSent.Add(int64(len(packet)))
+	return nil
+}
+
+// collectNetworkMessages sends a network layer message and passes the
+// answers of answerType to collect until the discovery timeout or ctx ends
+func (c *Client) collectNetworkMessages(ctx context.Context, options *DiscoverOptions, msgType NetworkMessageType, data []byte, answerType NetworkMessageType, collect func(npdu *NPDU, addr net.Addr)) error {
+	var addr net.Addr
+	if options.Address != "" {
+		var err error
+		if addr, err = c.link.ParseAddr(options.Address); err != nil {
+			return fmt.Errorf("invalid discovery address: %w", err)
+		}
+	}
+
+	stop := c.watchNetworkMessages(func(npdu *NPDU, from net.Addr) {
+		if npdu.MessageType == answerType {
+			collect(npdu, from)
+		}
+	})
+	defer stop()
+
+	if err := c.sendNetworkMessage(ctx, addr, msgType, data); err != nil {
+		return err
+	}
+
+	return sleepContext(ctx, c.opts.clock, options.Timeout)
+}
+
+// WhoIsRouterToNetwork asks the routers of the local network which remote
+// networks they reach, or, with WithTargetNetwork, which router reaches
+// that network. It returns the routers answering before the discovery
+// timeout, with their networks sorted.
+func (c *Client) WhoIsRouterToNetwork(ctx context.Context, opts ...DiscoverOption) ([]RouterInfo, error) {
+	options := defaultDiscoverOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var data []byte
+	if options.Network != 0 {
+		data = binary.BigEndian.AppendUint16(nil, options.Network)
+	}
+
+	var mu sync.Mutex
+	routers := make(map[string]*RouterInfo)
+	var order []string
+	err := c.collectNetworkMessages(ctx, options, NetworkMessageWhoIsRouterToNetwork, data, NetworkMessageIAmRouterToNetwork, func(npdu *NPDU, addr net.Addr) {
+		mu.Lock()
+		defer mu.Unlock()
+		key := addr.String()
+		router, ok := routers[key]
+		if !ok {
+			router = &RouterInfo{Address: addr}
+			routers[key] = router
+			order = append(order, key)
+		}
+		for i := 0; i+2 <= len(npdu.Data); i += 2 {
+			network := binary.BigEndian.Uint16(npdu.Data[i:])
+			if !containsNetwork(router.Networks, network) {
+				router.Networks = append(router.Networks, network)
+			}
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	result := make([]RouterInfo, 0, len(order))
+	for _, key := range order {
+		router := routers[key]
+		sort.Slice(router.Networks, func(i, j int) bool { return router.Networks[i] < router.Networks[j] })
+		result = append(result, *router)
+	}
+	return result, nil
+}
+
+// WhatIsNetworkNumber asks the routers of the local network for its network
+// number. It returns the announcements received before the discovery
+// timeout; routers that do not know the number stay silent.
+func (c *Client) WhatIsNetworkNumber(ctx context.Context, opts ...DiscoverOption) ([]NetworkNumberInfo, error) {
+	options := defaultDiscoverOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var mu sync.Mutex
+	var result []NetworkNumberInfo
+	err := c.collectNetworkMessages(ctx, options, NetworkMessageWhatIsNetworkNumber, nil, NetworkMessageNetworkNumberIs, func(npdu *NPDU, addr net.Addr) {
+		if len(npdu.Data) < 3 {
+			return
+		}
+		mu.Lock()
+		defer mu.Unlock()
+		result = append(result, NetworkNumberInfo{
+			Address:    addr,
+			Network:    binary.BigEndian.Uint16(npdu.Data),
+			Configured: npdu.Data[2] == 1,
+		})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	mu.Lock()
+	defer mu.Unlock()
+	return result, nil
+}
+
+// containsNetwork reports whether networks holds network
+func containsNetwork(networks []uint16, network uint16) bool {
+	for _, n := range networks {
+		if n == network {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,93 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"log/slog"
+	"sync"
+)
+
+// objectList is the cached object list of a device, valid as long as the
+// database revision of the device is the one it was read at
+type objectList struct {
+	// mu is held while the list is read, so that concurrent callers wait
+	// for one read instead of each reading the whole array
+	mu       sync.Mutex
+	valid    bool
+	revision uint32
+	objects  []ObjectIdentifier
+}
+
+// GetObjectList retrieves the list of objects from a device. The list is
+// cached with the database revision of the device, which the device
+// increments when objects are created, deleted or renamed: while the
+// revision is unchanged, a call reads it alone instead of the whole list.
+// Devices without a database revision are read in full each time.
+func (c *Client) GetObjectList(ctx context.Context, deviceID uint32) ([]ObjectIdentifier, error) {
+	c.objectListsMu.Lock()
+	list, ok := c.objectLists[deviceID]
+	if !ok {
+		list = &objectList{}
+		c.objectLists[deviceID] = list
+	}
+	c.objectListsMu.Unlock()
+
+	list.mu.Lock()
+	defer list.mu.Unlock()
+
+	device := NewObjectIdentifier(ObjectTypeDevice, deviceID)
+	revision, hasRevision := uint32(0), false
+	if value, err := c.ReadProperty(ctx, deviceID, device, PropertyDatabaseRevision); err == nil {
+		revision, hasRevision = value.(uint32)
+	} else if ctx.Err() != nil {
+		return nil, err
+	}
+	if hasRevision && list.valid && list.revision == revision {
+		return append([]ObjectIdentifier(nil), list.objects...), nil
+	}
+
+	values, err := c.ReadArray(ctx, deviceID, device, PropertyObjectList)
+	if err != nil {
+		return nil, err
+	}
+
+	objects := make([]ObjectIdentifier, 0, len(values))
+	for _, val := range values {
+		if oid, ok := val.(ObjectIdentifier); ok {
+			objects = append(objects, oid)
+		}
+	}
+
+	list.valid, list.revision, list.objects = hasRevision, revision, objects
+	if hasRevision {
+		c.logger.Debug("object list cached",
+			slog.Uint64("device_id", uint64(deviceID)),
+			slog.Uint64("database_revision", uint64(revision)),
+			slog.Int("objects", len(objects)),
+		)
+	}
+
+	return append([]ObjectIdentifier(nil), objects...), nil
+}
+
+// InvalidateObjectList drops the cached object list of a device, so that
+// the next GetObjectList reads it in full, for devices which change their
+// objects without incrementing their database revision
+func (c *Client) InvalidateObjectList(deviceID uint32) {
+	c.objectListsMu.Lock()
+	delete(c.objectLists, deviceID)
+	c.objectListsMu.Unlock()
+}
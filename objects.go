@@ -0,0 +1,562 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// WriteHandler is called when a present value write is received from the
+// network. A nil value relinquishes the given priority.
+type WriteHandler func(object *LocalObject, value interface{}, priority uint8) error
+
+// COVListener is called when the present value or status flags of a local
+// object change enough to warrant a COV notification
+type COVListener func(object *LocalObject)
+
+// LocalObject is an analog, binary or multi-state value object served by an
+// ObjectRegistry. Values are set by the application with SetPresentValue and
+// by other BACnet devices with WriteProperty.
+type LocalObject struct {
+	id   ObjectIdentifier
+	name string
+	opts *objectOptions
+
+	mu            sync.RWMutex
+	value         interface{}
+	priorityArray [16]interface{}
+	outOfService  bool
+	reliability   Reliability
+
+	covValue interface{}
+	covFlags StatusFlags
+
+	registry *ObjectRegistry
+}
+
+// NewAnalogValue creates an analog value object. Its present value is a float32.
+func NewAnalogValue(instance uint32, name string, opts ...ObjectOption) *LocalObject {
+	return newLocalObject(NewObjectIdentifier(ObjectTypeAnalogValue, instance), name, float32(0), opts)
+}
+
+// NewBinaryValue creates a binary value object. Its present value is an
+// Enumerated (0 inactive, 1 active); booleans are accepted when setting it.
+func NewBinaryValue(instance uint32, name string, opts ...ObjectOption) *LocalObject {
+	return newLocalObject(NewObjectIdentifier(ObjectTypeBinaryValue, instance), name, Enumerated(0), opts)
+}
+
+// NewMultiStateValue creates a multi-state value object with states numbered
+// from 1. Its present value is a uint32.
+func NewMultiStateValue(instance uint32, name string, numberOfStates uint32, opts ...ObjectOption) *LocalObject {
+	obj := newLocalObject(NewObjectIdentifier(ObjectTypeMultiStateValue, instance), name, uint32(1), opts)
+	if numberOfStates == 0 {
+		numberOfStates = 1
+	}
+	if len(obj.opts.stateText) == 0 {
+		obj.opts.stateText = make([]string, numberOfStates)
+	}
+	for uint32(len(obj.opts.stateText)) < numberOfStates {
+		obj.opts.stateText = append(obj.opts.stateText, "")
+	}
+	obj.opts.stateText = obj.opts.stateText[:numberOfStates]
+	return obj
+}
+
+func newLocalObject(id ObjectIdentifier, name string, initial interface{}, opts []ObjectOption) *LocalObject {
+	options := defaultObjectOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &LocalObject{
+		id:       id,
+		name:     name,
+		opts:     options,
+		value:    initial,
+		covValue: initial,
+	}
+}
+
+// ObjectID returns the object identifier
+func (o *LocalObject) ObjectID() ObjectIdentifier {
+	return o.id
+}
+
+// Name returns the object name
+func (o *LocalObject) Name() string {
+	return o.name
+}
+
+// PresentValue returns the effective present value: the highest priority
+// command for commandable objects, otherwise the value set locally
+func (o *LocalObject) PresentValue() interface{} {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.presentValueLocked()
+}
+
+func (o *LocalObject) presentValueLocked() interface{} {
+	if o.opts.commandable {
+		for _, v := range o.priorityArray {
+			if v != nil {
+				return v
+			}
+		}
+	}
+	return o.value
+}
+
+// StatusFlags returns the current status flags
+func (o *LocalObject) StatusFlags() StatusFlags {
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+	return o.statusFlagsLocked()
+}
+
+func (o *LocalObject) statusFlagsLocked() StatusFlags {
+	return StatusFlags{
+		Fault:        o.reliability != ReliabilityNoFaultDetected,
+		OutOfService: o.outOfService,
+	}
+}
+
+// SetPresentValue sets the value supplied by the application. For
+// commandable objects this is the relinquish default, which applies while
+// no priority is commanded.
+func (o *LocalObject) SetPresentValue(value interface{}) error {
+	normalized, err := o.normalize(value)
+	if err != nil {
+		return err
+	}
+
+	o.mu.Lock()
+	o.value = normalized
+	changed := o.checkCOVLocked()
+	o.mu.Unlock()
+
+	o.notifyCOV(changed)
+	return nil
+}
+
+// SetReliability sets the reliability of the object. Any value other than
+// no-fault-detected sets the fault status flag, e.g. when the data source
+// of a gateway point is unreachable.
+func (o *LocalObject) SetReliability(reliability Reliability) {
+	o.mu.Lock()
+	o.reliability = reliability
+	changed := o.checkCOVLocked()
+	o.mu.Unlock()
+
+	o.notifyCOV(changed)
+}
+
+// SetOutOfService sets the out-of-service flag
+func (o *LocalObject) SetOutOfService(outOfService bool) {
+	o.mu.Lock()
+	o.outOfService = outOfService
+	changed := o.checkCOVLocked()
+	o.mu.Unlock()
+
+	o.notifyCOV(changed)
+}
+
+// Command writes a value at a priority (1-16) of a commandable object. A nil
+// value relinquishes the priority.
+func (o *LocalObject) Command(value interface{}, priority uint8) error {
+	if !o.opts.commandable {
+		return NewBACnetError(ErrorClassProperty, ErrorCodeWriteAccessDenied)
+	}
+	if priority < 1 || priority > 16 {
+		return NewBACnetError(ErrorClassProperty, ErrorCodeValueOutOfRange)
+	}
+
+	var normalized interface{}
+	if value != nil {
+		var err error
+		if normalized, err = o.normalize(value); err != nil {
+			return err
+		}
+	}
+
+	o.mu.Lock()
+	o.priorityArray[priority-1] = normalized
+	changed := o.checkCOVLocked()
+	o.mu.Unlock()
+
+	o.notifyCOV(changed)
+	return nil
+}
+
+// normalize converts a value to the present value type of the object
+func (o *LocalObject) normalize(value interface{}) (interface{}, error) {
+	invalidType := NewBACnetError(ErrorClassProperty, ErrorCodeInvalidDataType)
+
+	switch o.id.Type {
+	case ObjectTypeAnalogValue:
+		f, ok := toFloat64(value)
+		if !ok {
+			return nil, invalidType
+		}
+		return float32(f), nil
+
+	case ObjectTypeBinaryValue:
+		if b, ok := value.(bool); ok {
+			if b {
+				return Enumerated(1), nil
+			}
+			return Enumerated(0), nil
+		}
+		f, ok := toFloat64(value)
+		if !ok {
+			return nil, invalidType
+		}
+		if f != 0 && f != 1 {
+			return nil, NewBACnetError(ErrorClassProperty, ErrorCodeValueOutOfRange)
+		}
+		return Enumerated(f), nil
+
+	case ObjectTypeMultiStateValue:
+		f, ok := toFloat64(value)
+		if !ok {
+			return nil, invalidType
+		}
+		if f < 1 || f > float64(len(o.opts.stateText)) || f != math.Trunc(f) {
+			return nil, NewBACnetError(ErrorClassProperty, ErrorCodeValueOutOfRange)
+		}
+		return uint32(f), nil
+	}
+
+	return value, nil
+}
+
+// checkCOVLocked reports whether the present value or status flags changed
+// past the COV increment since the last report, and records them if so
+func (o *LocalObject) checkCOVLocked() bool {
+	value := o.presentValueLocked()
+	flags := o.statusFlagsLocked()
+
+	changed := flags != o.covFlags
+	if !changed {
+		if o.id.Type == ObjectTypeAnalogValue {
+			prev, _ := toFloat64(o.covValue)
+			curr, _ := toFloat64(value)
+			delta := math.Abs(curr - prev)
+			changed = delta > 0 && delta >= float64(o.opts.covIncrement)
+		} else {
+			changed = value != o.covValue
+		}
+	}
+
+	if changed {
+		o.covValue = value
+		o.covFlags = flags
+	}
+	return changed
+}
+
+// notifyCOV calls the registry COV listeners if a change was detected
+func (o *LocalObject) notifyCOV(changed bool) {
+	if changed && o.registry != nil {
+		o.registry.notifyCOV(o)
+	}
+}
+
+// Properties returns the properties of the object
+func (o *LocalObject) Properties() []PropertyIdentifier {
+	props := []PropertyIdentifier{
+		PropertyObjectIdentifier,
+		PropertyObjectName,
+		PropertyObjectType,
+		PropertyPresentValue,
+		PropertyStatusFlags,
+		PropertyEventState,
+		PropertyOutOfService,
+		PropertyReliability,
+		PropertyDescription,
+	}
+
+	switch o.id.Type {
+	case ObjectTypeAnalogValue:
+		props = append(props, PropertyUnits, PropertyCOVIncrement)
+	case ObjectTypeMultiStateValue:
+		props = append(props, PropertyNumberOfStates, PropertyStateText)
+	}
+
+	if o.opts.commandable {
+		props = append(props, PropertyPriorityArray, PropertyRelinquishDefault)
+	}
+
+	// Custom properties
+	var extra []PropertyIdentifier
+	for prop := range o.opts.getters {
+		if !containsProperty(props, prop) {
+			extra = append(extra, prop)
+		}
+	}
+	sort.Slice(extra, func(i, j int) bool { return extra[i] < extra[j] })
+
+	return append(props, extra...)
+}
+
+// ReadProperty reads a property of the object
+func (o *LocalObject) ReadProperty(propertyID PropertyIdentifier) (interface{}, error) {
+	if getter, ok := o.opts.getters[propertyID]; ok {
+		return getter()
+	}
+
+	o.mu.RLock()
+	defer o.mu.RUnlock()
+
+	switch propertyID {
+	case PropertyObjectIdentifier:
+		return o.id, nil
+	case PropertyObjectName:
+		return o.name, nil
+	case PropertyObjectType:
+		return o.id.Type, nil
+	case PropertyPresentValue:
+		return o.presentValueLocked(), nil
+	case PropertyStatusFlags:
+		return o.statusFlagsLocked(), nil
+	case PropertyEventState:
+		return EventStateNormal, nil
+	case PropertyOutOfService:
+		return o.outOfService, nil
+	case PropertyReliability:
+		return o.reliability, nil
+	case PropertyDescription:
+		return o.opts.description, nil
+	}
+
+	switch {
+	case o.id.Type == ObjectTypeAnalogValue && propertyID == PropertyUnits:
+		return o.opts.units, nil
+	case o.id.Type == ObjectTypeAnalogValue && propertyID == PropertyCOVIncrement:
+		return o.opts.covIncrement, nil
+	case o.id.Type == ObjectTypeMultiStateValue && propertyID == PropertyNumberOfStates:
+		return uint32(len(o.opts.stateText)), nil
+	case o.id.Type == ObjectTypeMultiStateValue && propertyID == PropertyStateText:
+		return o.opts.stateText, nil
+	case o.opts.commandable && propertyID == PropertyPriorityArray:
+		array := make([]interface{}, len(o.priorityArray))
+		copy(array, o.priorityArray[:])
+		return array, nil
+	case o.opts.commandable && propertyID == PropertyRelinquishDefault:
+		return o.value, nil
+	}
+
+	return nil, NewBACnetError(ErrorClassProperty, ErrorCodeUnknownProperty)
+}
+
+// WriteProperty writes a property of the object on behalf of a remote
+// device. Priority 0 means no priority was given.
+func (o *LocalObject) WriteProperty(propertyID PropertyIdentifier, value interface{}, priority uint8, arrayIndex *uint32) error {
+	if setter, ok := o.opts.setters[propertyID]; ok {
+		if arrayIndex != nil {
+			return NewBACnetError(ErrorClassProperty, ErrorCodePropertyIsNotAnArray)
+		}
+		return setter(value)
+	}
+
+	switch propertyID {
+	case PropertyPresentValue:
+		if arrayIndex != nil {
+			return NewBACnetError(ErrorClassProperty, ErrorCodePropertyIsNotAnArray)
+		}
+		return o.writePresentValue(value, priority)
+
+	case PropertyOutOfService:
+		b, ok := value.(bool)
+		if !ok {
+			return NewBACnetError(ErrorClassProperty, ErrorCodeInvalidDataType)
+		}
+		o.SetOutOfService(b)
+		return nil
+
+	case PropertyRelinquishDefault:
+		if o.opts.commandable {
+			return o.SetPresentValue(value)
+		}
+	}
+
+	if containsProperty(o.Properties(), propertyID) {
+		return NewBACnetError(ErrorClassProperty, ErrorCodeWriteAccessDenied)
+	}
+	return NewBACnetError(ErrorClassProperty, ErrorCodeUnknownProperty)
+}
+
+// writePresentValue applies a present value write from the network
+func (o *LocalObject) writePresentValue(value interface{}, priority uint8) error {
+	if o.opts.commandable {
+		if priority == 0 {
+			priority = 16
+		}
+		if priority > 16 {
+			return NewBACnetError(ErrorClassProperty, ErrorCodeValueOutOfRange)
+		}
+	} else if value == nil {
+		return NewBACnetError(ErrorClassProperty, ErrorCodeInvalidDataType)
+	}
+
+	var normalized interface{}
+	if value != nil {
+		var err error
+		if normalized, err = o.normalize(value); err != nil {
+			return err
+		}
+	}
+
+	if o.opts.onWrite != nil {
+		if err := o.opts.onWrite(o, normalized, priority); err != nil {
+			return err
+		}
+	}
+
+	if o.opts.commandable {
+		return o.Command(normalized, priority)
+	}
+	return o.SetPresentValue(normalized)
+}
+
+// containsProperty reports whether props contains propertyID
+func containsProperty(props []PropertyIdentifier, propertyID PropertyIdentifier) bool {
+	for _, p := range props {
+		if p == propertyID {
+			return true
+		}
+	}
+	return false
+}
+
+// ObjectRegistry is an ObjectDatabase of LocalObjects. It serves reads,
+// accepts writes and reports value changes for COV notifications.
+type ObjectRegistry struct {
+	mu        sync.RWMutex
+	objects   map[ObjectIdentifier]*LocalObject
+	order     []ObjectIdentifier
+	listeners []COVListener
+}
+
+// NewObjectRegistry creates an empty object registry
+func NewObjectRegistry() *ObjectRegistry {
+	return &ObjectRegistry{
+		objects: make(map[ObjectIdentifier]*LocalObject),
+	}
+}
+
+// Add adds objects to the registry. Call IncrementDatabaseRevision on the
+// device when objects are added after it has been announced.
+func (r *ObjectRegistry) Add(objects ...*LocalObject) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for _, obj := range objects {
+		if _, exists := r.objects[obj.id]; exists {
+			return NewBACnetError(ErrorClassObject, ErrorCodeObjectIdentifierAlreadyExists)
+		}
+	}
+	for _, obj := range objects {
+		obj.registry = r
+		r.objects[obj.id] = obj
+		r.order = append(r.order, obj.id)
+	}
+	return nil
+}
+
+// Remove removes an object from the registry
+func (r *ObjectRegistry) Remove(objectID ObjectIdentifier) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	obj, ok := r.objects[objectID]
+	if !ok {
+		return false
+	}
+	obj.registry = nil
+	delete(r.objects, objectID)
+	for i, id := range r.order {
+		if id == objectID {
+			r.order = append(r.order[:i], r.order[i+1:]...)
+			break
+		}
+	}
+	return true
+}
+
+// Object returns an object by identifier
+func (r *ObjectRegistry) Object(objectID ObjectIdentifier) (*LocalObject, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	obj, ok := r.objects[objectID]
+	return obj, ok
+}
+
+// OnCOV registers a listener for value changes. Listeners are called
+// synchronously from the goroutine that changed the value and must not block.
+func (r *ObjectRegistry) OnCOV(listener COVListener) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.listeners = append(r.listeners, listener)
+}
+
+// notifyCOV calls the registered COV listeners
+func (r *ObjectRegistry) notifyCOV(obj *LocalObject) {
+	r.mu.RLock()
+	listeners := make([]COVListener, len(r.listeners))
+	copy(listeners, r.listeners)
+	r.mu.RUnlock()
+
+	for _, listener := range listeners {
+		listener(obj)
+	}
+}
+
+// Objects implements ObjectDatabase
+func (r *ObjectRegistry) Objects() []ObjectIdentifier {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	list := make([]ObjectIdentifier, len(r.order))
+	copy(list, r.order)
+	return list
+}
+
+// ReadProperty implements ObjectDatabase
+func (r *ObjectRegistry) ReadProperty(objectID ObjectIdentifier, propertyID PropertyIdentifier) (interface{}, error) {
+	obj, ok := r.Object(objectID)
+	if !ok {
+		return nil, NewBACnetError(ErrorClassObject, ErrorCodeUnknownObject)
+	}
+	return obj.ReadProperty(propertyID)
+}
+
+// Properties implements PropertyLister
+func (r *ObjectRegistry) Properties(objectID ObjectIdentifier) []PropertyIdentifier {
+	obj, ok := r.Object(objectID)
+	if !ok {
+		return nil
+	}
+	return obj.Properties()
+}
+
+// WriteProperty implements PropertyWriter
+func (r *ObjectRegistry) WriteProperty(objectID ObjectIdentifier, propertyID PropertyIdentifier, value interface{}, priority uint8, arrayIndex *uint32) error {
+	obj, ok := r.Object(objectID)
+	if !ok {
+		return NewBACnetError(ErrorClassObject, ErrorCodeUnknownObject)
+	}
+	return obj.WriteProperty(propertyID, value, priority, arrayIndex)
+}
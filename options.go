@@ -15,8 +15,13 @@
 package bacnet
 
 import (
+	"crypto/tls"
+	"fmt"
+	"io"
 	"log/slog"
 	"time"
+
+	"github.com/edgeo-scada/bacnet/internal/transport"
 )
 
 // ClientOptions holds configuration for the BACnet client
@@ -30,12 +35,18 @@ type clientOptions struct {
 	bbmdAddress   string
 	bbmdPort      int
 	foreignDeviceTTL time.Duration
+	ephemeralPort bool
 
 	// Timeouts
 	timeout        time.Duration
 	retries        int
 	retryDelay     time.Duration
 
+	// Outstanding confirmed requests per device, 0 for a default after the
+	// device's link and APDU size
+	maxOutstanding       int
+	deviceMaxOutstanding map[uint32]int
+
 	// APDU configuration
 	maxAPDULength  uint16
 	segmentation   Segmentation
@@ -47,6 +58,60 @@ type clientOptions struct {
 
 	// Logging
 	logger         *slog.Logger
+
+	// Site namespace
+	site string
+
+	// Server mode
+	localDevice *Device
+
+	// BACnet/SC
+	scHubURI    string
+	scTLSConfig *tls.Config
+	scVMAC      *VMAC
+	scUUID      *[16]byte
+	scHeartbeat time.Duration
+
+	// BACnet/IPv6
+	ipv6          bool
+	dualStack     bool
+	ipv6Address   string
+	ipv6Interface string
+	ipv6Multicast string
+	ipv6VMAC      *uint32
+
+	// MS/TP
+	mstpPort          string
+	mstpBaud          int
+	mstpMAC           uint8
+	mstpMaxMaster     uint8
+	mstpMaxInfoFrames int
+
+	// Ethernet
+	ethInterface   string
+	ethPromiscuous bool
+
+	// UDP socket options
+	socketOptions transport.SocketOptions
+
+	// Packet capture of the UDP data links
+	captureWriter io.Writer
+	capture       *pcapWriter
+
+	// Protocol decode tracing
+	trace    ProtocolTraceFunc
+	traceLog bool
+
+	clock Clock
+
+	// Upper bounds of the latency histogram buckets
+	latencyBuckets []time.Duration
+
+	// Persistence
+	storage Storage
+
+	// Audit trail of property writes
+	journal JournalSink
 }
 
 // defaultOptions returns the default client options
@@ -63,6 +128,11 @@ func defaultOptions() *clientOptions {
 		autoDiscover:      false,
 		discoverTimeout:   5 * time.Second,
 		logger:            slog.Default(),
+		ipv6Multicast:     DefaultIPv6Multicast,
+		mstpMaxMaster:     DefaultMSTPMaxMaster,
+		mstpMaxInfoFrames: 1,
+		clock:             SystemClock,
+		latencyBuckets:    DefaultLatencyBuckets,
 	}
 }
 
@@ -99,6 +169,17 @@ func WithBBMD(addr string, port int, ttl time.Duration) Option {
 	}
 }
 
+// WithEphemeralPort binds an ephemeral UDP port instead of 47808, for hosts
+// where another application owns the BACnet port. Broadcasts cannot reach
+// an ephemeral port, so the client relies on its BBMD: it must be
+// registered as a foreign device with WithBBMD, sends its broadcasts
+// through the BBMD and receives the network's broadcasts forwarded by it.
+func WithEphemeralPort() Option {
+	return func(o *clientOptions) {
+		o.ephemeralPort = true
+	}
+}
+
 // WithTimeout sets the request timeout
 func WithTimeout(d time.Duration) Option {
 	return func(o *clientOptions) {
@@ -106,7 +187,8 @@ func WithTimeout(d time.Duration) Option {
 	}
 }
 
-// WithRetries sets the number of retries for failed requests
+// WithRetries sets how many times a confirmed request is sent again when no
+// answer arrives within the request timeout
 func WithRetries(n int) Option {
 	return func(o *clientOptions) {
 		o.retries = n
@@ -120,6 +202,28 @@ func WithRetryDelay(d time.Duration) Option {
 	}
 }
 
+// WithMaxOutstandingRequests bounds the confirmed requests outstanding to
+// each device; further requests wait for an answer. By default devices on
+// the MS/TP link of the client get 1, devices with an APDU of 480 bytes or
+// less 2, others 4.
+func WithMaxOutstandingRequests(n int) Option {
+	return func(o *clientOptions) {
+		o.maxOutstanding = n
+	}
+}
+
+// WithDeviceMaxOutstandingRequests bounds the confirmed requests
+// outstanding to one device, overriding WithMaxOutstandingRequests. A
+// limit of 0 leaves the device unbounded.
+func WithDeviceMaxOutstandingRequests(deviceID uint32, n int) Option {
+	return func(o *clientOptions) {
+		if o.deviceMaxOutstanding == nil {
+			o.deviceMaxOutstanding = make(map[uint32]int)
+		}
+		o.deviceMaxOutstanding[deviceID] = n
+	}
+}
+
 // WithMaxAPDULength sets the maximum APDU length
 func WithMaxAPDULength(length uint16) Option {
 	return func(o *clientOptions) {
@@ -127,14 +231,18 @@ func WithMaxAPDULength(length uint16) Option {
 	}
 }
 
-// WithSegmentation sets the segmentation capability
+// WithSegmentation sets the segmentation capability. With
+// SegmentationReceive or SegmentationBoth, confirmed requests accept
+// segmented responses of up to 64 segments, reassembled before they are
+// returned; the client never sends segmented requests.
 func WithSegmentation(seg Segmentation) Option {
 	return func(o *clientOptions) {
 		o.segmentation = seg
 	}
 }
 
-// WithProposedWindowSize sets the proposed window size for segmentation
+// WithProposedWindowSize sets the number of segments of a response the
+// client acknowledges at once, 1 by default
 func WithProposedWindowSize(size uint8) Option {
 	return func(o *clientOptions) {
 		o.proposedWindowSize = size
@@ -162,6 +270,234 @@ func WithLogger(logger *slog.Logger) Option {
 	}
 }
 
+// WithSite sets the site name that namespaces this client when several
+// networks are aggregated. It is added to log records and metrics
+// snapshots, and prefixes names built with Client.Namespaced.
+func WithSite(name string) Option {
+	return func(o *clientOptions) {
+		o.site = name
+	}
+}
+
+// WithPacketCapture writes every UDP datagram the BACnet/IP and BACnet/IPv6
+// data links send or receive to w in pcap format, with IP and UDP headers,
+// so that it can be opened in Wireshark. Writes are serialized; capture
+// stops at the first write error.
+func WithPacketCapture(w io.Writer) Option {
+	return func(o *clientOptions) {
+		o.captureWriter = w
+	}
+}
+
+// WithProtocolTrace logs a human-readable breakdown of every frame sent or
+// received (BVLC function, NPDU routing, service and decoded tags) to the
+// client logger at Debug level
+func WithProtocolTrace() Option {
+	return func(o *clientOptions) {
+		o.traceLog = true
+	}
+}
+
+// WithProtocolTraceFunc passes a human-readable breakdown of every frame
+// sent or received to fn
+func WithProtocolTraceFunc(fn ProtocolTraceFunc) Option {
+	return func(o *clientOptions) {
+		o.trace = fn
+	}
+}
+
+// WithWriteJournal records every WriteProperty and WritePropertyMultiple
+// sent by the client, with its result, to sink for auditing. Dry runs are
+// not recorded. Verified writes also record the value they replaced.
+func WithWriteJournal(sink JournalSink) Option {
+	return func(o *clientOptions) {
+		o.journal = sink
+	}
+}
+
+// WithLatencyBuckets sets the upper bounds of the request latency histogram
+// buckets, e.g. sub-millisecond steps on a LAN or seconds on MS/TP, so that
+// percentiles are estimated at a useful resolution
+func WithLatencyBuckets(bounds ...time.Duration) Option {
+	return func(o *clientOptions) {
+		if len(bounds) > 0 {
+			o.latencyBuckets = bounds
+		}
+	}
+}
+
+// WithSecureConnect selects the BACnet/SC data link: the client connects to
+// the hub at hubURI (wss://...) instead of using BACnet/IP. tlsConfig carries
+// the operational certificate and the trusted CA, see
+// LoadSecureConnectTLSConfig.
+func WithSecureConnect(hubURI string, tlsConfig *tls.Config) Option {
+	return func(o *clientOptions) {
+		o.scHubURI = hubURI
+		o.scTLSConfig = tlsConfig
+	}
+}
+
+// WithSCVMAC sets the BACnet/SC virtual MAC address (random by default)
+func WithSCVMAC(vmac VMAC) Option {
+	return func(o *clientOptions) {
+		o.scVMAC = &vmac
+	}
+}
+
+// WithSCDeviceUUID sets the BACnet/SC device UUID (random by default)
+func WithSCDeviceUUID(uuid [16]byte) Option {
+	return func(o *clientOptions) {
+		o.scUUID = &uuid
+	}
+}
+
+// WithSCHeartbeat sets the BACnet/SC connection heartbeat timeout
+func WithSCHeartbeat(d time.Duration) Option {
+	return func(o *clientOptions) {
+		o.scHeartbeat = d
+	}
+}
+
+// WithIPv6 selects the BACnet/IPv6 data link (Annex U) instead of BACnet/IP.
+// localAddress sets the UDP port, e.g. "[::]:47808"; broadcasts use the
+// B/IPv6 multicast group.
+func WithIPv6(localAddress string) Option {
+	return func(o *clientOptions) {
+		o.ipv6 = true
+		o.ipv6Address = localAddress
+	}
+}
+
+// WithDualStack attaches the client to both BACnet/IP and BACnet/IPv6.
+// Broadcasts go out on both; devices are reached on the link they were
+// discovered on.
+func WithDualStack(ipv6Address string) Option {
+	return func(o *clientOptions) {
+		o.ipv6 = true
+		o.dualStack = true
+		o.ipv6Address = ipv6Address
+	}
+}
+
+// WithIPv6Interface sets the network interface joining the multicast group
+func WithIPv6Interface(name string) Option {
+	return func(o *clientOptions) {
+		o.ipv6Interface = name
+	}
+}
+
+// WithIPv6Multicast sets the B/IPv6 broadcast group, e.g. SiteIPv6Multicast
+func WithIPv6Multicast(group string) Option {
+	return func(o *clientOptions) {
+		o.ipv6Multicast = group
+	}
+}
+
+// WithIPv6VMAC sets the B/IPv6 virtual MAC address. It defaults to the
+// device instance.
+func WithIPv6VMAC(instance uint32) Option {
+	return func(o *clientOptions) {
+		o.ipv6VMAC = &instance
+	}
+}
+
+// WithReuseAddress sets SO_REUSEADDR on the UDP socket
+func WithReuseAddress(enabled bool) Option {
+	return func(o *clientOptions) {
+		o.socketOptions.ReuseAddr = enabled
+	}
+}
+
+// WithReusePort sets SO_REUSEADDR and SO_REUSEPORT on the UDP socket so
+// that the client can bind port 47808 alongside another BACnet stack on
+// the same host. Unicast datagrams are then delivered to only one of the
+// sockets; broadcasts reach all of them.
+func WithReusePort(enabled bool) Option {
+	return func(o *clientOptions) {
+		o.socketOptions.ReuseAddr = enabled
+		o.socketOptions.ReusePort = enabled
+	}
+}
+
+// WithBindInterface binds the UDP socket to a network interface by name, so
+// that traffic, including broadcasts, only uses that interface. Linux only;
+// requires CAP_NET_RAW on kernels before 5.7.
+func WithBindInterface(name string) Option {
+	return func(o *clientOptions) {
+		o.socketOptions.Interface = name
+	}
+}
+
+// WithDSCP sets the Differentiated Services code point (0-63) of sent
+// datagrams for QoS-managed networks, e.g. 46 for expedited forwarding
+func WithDSCP(dscp uint8) Option {
+	return func(o *clientOptions) {
+		o.socketOptions.DSCP = dscp
+	}
+}
+
+// WithClock sets the time source of the client, the schedulers and
+// redundancy coordinators built on it. Tests use a simulated clock to run
+// time-based behavior without sleeping.
+func WithClock(clock Clock) Option {
+	return func(o *clientOptions) {
+		if clock != nil {
+			o.clock = clock
+		}
+	}
+}
+
+// WithStorage persists the device cache, the COV subscriptions served by
+// the local device and energy rollup cursors, restoring them on Connect
+func WithStorage(storage Storage) Option {
+	return func(o *clientOptions) {
+		o.storage = storage
+	}
+}
+
+// WithMSTP selects the MS/TP data link: the client joins the RS-485 bus on
+// portName as a master node with the given MAC address (0-127). The maximum
+// APDU length is limited to 480 bytes.
+func WithMSTP(portName string, baud int, macAddress uint8) Option {
+	return func(o *clientOptions) {
+		o.mstpPort = portName
+		o.mstpBaud = baud
+		o.mstpMAC = macAddress
+	}
+}
+
+// WithMSTPMaxMaster sets the highest master address polled on the MS/TP bus
+func WithMSTPMaxMaster(n uint8) Option {
+	return func(o *clientOptions) {
+		o.mstpMaxMaster = n
+	}
+}
+
+// WithMSTPMaxInfoFrames sets the number of frames sent per token
+func WithMSTPMaxInfoFrames(n int) Option {
+	return func(o *clientOptions) {
+		o.mstpMaxInfoFrames = n
+	}
+}
+
+// WithEthernet selects the ISO 8802-3 (Ethernet) data link on the named
+// network interface, for devices without BACnet/IP. Raw sockets require
+// CAP_NET_RAW and are only supported on Linux.
+func WithEthernet(iface string) Option {
+	return func(o *clientOptions) {
+		o.ethInterface = iface
+	}
+}
+
+// WithEthernetPromiscuous captures every frame on the Ethernet interface
+// and filters BACnet frames in the client. Use it when the interface does
+// not deliver LLC frames otherwise, e.g. on some bridges and virtual NICs.
+func WithEthernetPromiscuous(enabled bool) Option {
+	return func(o *clientOptions) {
+		o.ethPromiscuous = enabled
+	}
+}
+
 // DiscoverOptions holds configuration for device discovery
 type DiscoverOptions struct {
 	// Range limits for WhoIs
@@ -173,6 +509,22 @@ type DiscoverOptions struct {
 
 	// Network to search (0 = local)
 	Network uint16
+
+	// Address to send a directed Who-Is to instead of broadcasting
+	Address string
+
+	// OnDevice is called with each device as its first I-Am arrives,
+	// before WhoIs returns. It runs on the receive loop and must not block.
+	OnDevice func(device *DeviceInfo)
+
+	// Probes of ProbeRange sent per second (0 = no limit), at most
+	// ProbeConcurrency at a time, to ProbePort
+	ProbeRate        int
+	ProbeConcurrency int
+	ProbePort        int
+
+	// Probe with a ReadProperty of the device object instead of a Who-Is
+	ProbeRead bool
 }
 
 // DiscoverOption is a functional option for discovery
@@ -181,8 +533,11 @@ type DiscoverOption func(*DiscoverOptions)
 // defaultDiscoverOptions returns default discovery options
 func defaultDiscoverOptions() *DiscoverOptions {
 	return &DiscoverOptions{
-		Timeout: 5 * time.Second,
-		Network: 0,
+		Timeout:          5 * time.Second,
+		Network:          0,
+		ProbeRate:        100,
+		ProbeConcurrency: 16,
+		ProbePort:        DefaultPort,
 	}
 }
 
@@ -208,9 +563,59 @@ func WithTargetNetwork(net uint16) DiscoverOption {
 	}
 }
 
+// WithDiscoveryAddress sends the Who-Is to a single address, in the form
+// accepted by BindDevice, instead of broadcasting it
+func WithDiscoveryAddress(address string) DiscoverOption {
+	return func(o *DiscoverOptions) {
+		o.Address = address
+	}
+}
+
+// WithDeviceFound sets a callback invoked with each device found, as its
+// I-Am arrives
+func WithDeviceFound(fn func(device *DeviceInfo)) DiscoverOption {
+	return func(o *DiscoverOptions) {
+		o.OnDevice = fn
+	}
+}
+
+// WithProbeRate limits the probes ProbeRange sends per second, 0 for no
+// limit
+func WithProbeRate(perSecond int) DiscoverOption {
+	return func(o *DiscoverOptions) {
+		o.ProbeRate = perSecond
+	}
+}
+
+// WithProbeConcurrency sets the probes of ProbeRange pending at the same
+// time
+func WithProbeConcurrency(n int) DiscoverOption {
+	return func(o *DiscoverOptions) {
+		o.ProbeConcurrency = n
+	}
+}
+
+// WithProbePort sets the UDP port probed by ProbeRange
+func WithProbePort(port int) DiscoverOption {
+	return func(o *DiscoverOptions) {
+		o.ProbePort = port
+	}
+}
+
+// WithProbeReadProperty makes ProbeRange read the identifier of the
+// device object of each address instead of sending it a Who-Is, for the
+// devices answering a directed Who-Is with a broadcast I-Am
+func WithProbeReadProperty() DiscoverOption {
+	return func(o *DiscoverOptions) {
+		o.ProbeRead = true
+	}
+}
+
 // ReadOptions holds configuration for read operations
 type ReadOptions struct {
-	ArrayIndex *uint32
+	ArrayIndex      *uint32
+	Timeout         time.Duration
+	NetworkPriority NPDUControl
 }
 
 // ReadOption is a functional option for read operations
@@ -223,10 +628,30 @@ func WithArrayIndex(index uint32) ReadOption {
 	}
 }
 
+// WithRequestTimeout bounds the read by d instead of the context deadline
+// alone
+func WithRequestTimeout(d time.Duration) ReadOption {
+	return func(o *ReadOptions) {
+		o.Timeout = d
+	}
+}
+
+// WithNetworkPriority sends the read with an NPDU network priority, one of
+// the NPDUControlPriority constants
+func WithNetworkPriority(priority NPDUControl) ReadOption {
+	return func(o *ReadOptions) {
+		o.NetworkPriority = priority & npduPriorityMask
+	}
+}
+
 // WriteOptions holds configuration for write operations
 type WriteOptions struct {
-	ArrayIndex *uint32
-	Priority   *uint8
+	ArrayIndex      *uint32
+	Priority        *uint8
+	DryRun          *WritePlan
+	Verify          bool
+	Timeout         time.Duration
+	NetworkPriority NPDUControl
 }
 
 // WriteOption is a functional option for write operations
@@ -248,6 +673,40 @@ func WithPriority(priority uint8) WriteOption {
 	}
 }
 
+// WithWriteTimeout bounds the write, including any read-back, by d instead
+// of the context deadline alone
+func WithWriteTimeout(d time.Duration) WriteOption {
+	return func(o *WriteOptions) {
+		o.Timeout = d
+	}
+}
+
+// WithWriteNetworkPriority sends the write with an NPDU network priority,
+// e.g. NPDUControlPriorityLifeSafety, so that it is transmitted ahead of
+// normal traffic on links that queue frames
+func WithWriteNetworkPriority(priority NPDUControl) WriteOption {
+	return func(o *WriteOptions) {
+		o.NetworkPriority = priority & npduPriorityMask
+	}
+}
+
+// WithVerify reads the property back after WriteProperty and fails with
+// ErrVerificationFailed if it does not hold the written value, as when a
+// higher priority is in control or the device clamped the value
+func WithVerify() WriteOption {
+	return func(o *WriteOptions) {
+		o.Verify = true
+	}
+}
+
+// WithDryRun resolves the device and encodes the write into plan without
+// sending it
+func WithDryRun(plan *WritePlan) WriteOption {
+	return func(o *WriteOptions) {
+		o.DryRun = plan
+	}
+}
+
 // SubscribeOptions holds configuration for COV subscriptions
 type SubscribeOptions struct {
 	Lifetime     *uint32
@@ -265,7 +724,9 @@ func WithSubscriptionLifetime(seconds uint32) SubscribeOption {
 	}
 }
 
-// WithCOVIncrement sets the COV increment for analog values
+// WithCOVIncrement sets the minimum change of an analog value that is
+// notified. The increment is carried by SubscribeCOVProperty only, so
+// SubscribeCOV uses that service, on the present value, when it is set.
 func WithCOVIncrement(increment float32) SubscribeOption {
 	return func(o *SubscribeOptions) {
 		o.COVIncrement = &increment
@@ -278,3 +739,528 @@ func WithConfirmedNotifications(confirmed bool) SubscribeOption {
 		o.Confirmed = confirmed
 	}
 }
+
+// WithLocalDevice attaches a local device to the client. The client then
+// answers Who-Is requests and serves property reads for the device.
+func WithLocalDevice(device *Device) Option {
+	return func(o *clientOptions) {
+		o.localDevice = device
+		if device != nil {
+			o.localDeviceID = device.opts.instance
+		}
+	}
+}
+
+// deviceOptions holds configuration for a local device
+type deviceOptions struct {
+	instance            uint32
+	name                string
+	vendorID            uint16
+	vendorName          string
+	modelName           string
+	firmwareRevision    string
+	applicationSoftware string
+	description         string
+	location            string
+	database            ObjectDatabase
+	maxAPDU             uint16
+	interceptor         RequestInterceptor
+}
+
+// defaultDeviceOptions returns the default local device options
+func defaultDeviceOptions(instance uint32) *deviceOptions {
+	return &deviceOptions{
+		instance:            instance,
+		name:                fmt.Sprintf("edgeo-bacnet-%d", instance),
+		vendorName:          "Edgeo SCADA",
+		modelName:           "edgeo-bacnet",
+		firmwareRevision:    Version,
+		applicationSoftware: Version,
+		maxAPDU:             MaxAPDULength,
+	}
+}
+
+// DeviceOption is a functional option for configuring a local device
+type DeviceOption func(*deviceOptions)
+
+// WithDeviceName sets the device object name
+func WithDeviceName(name string) DeviceOption {
+	return func(o *deviceOptions) {
+		o.name = name
+	}
+}
+
+// WithDeviceVendor sets the vendor identifier and vendor name
+func WithDeviceVendor(id uint16, name string) DeviceOption {
+	return func(o *deviceOptions) {
+		o.vendorID = id
+		o.vendorName = name
+	}
+}
+
+// WithDeviceModel sets the model name
+func WithDeviceModel(model string) DeviceOption {
+	return func(o *deviceOptions) {
+		o.modelName = model
+	}
+}
+
+// WithDeviceFirmware sets the firmware revision and application software version
+func WithDeviceFirmware(firmware, application string) DeviceOption {
+	return func(o *deviceOptions) {
+		o.firmwareRevision = firmware
+		o.applicationSoftware = application
+	}
+}
+
+// WithDeviceDescription sets the device description
+func WithDeviceDescription(description string) DeviceOption {
+	return func(o *deviceOptions) {
+		o.description = description
+	}
+}
+
+// WithDeviceLocation sets the device location
+func WithDeviceLocation(location string) DeviceOption {
+	return func(o *deviceOptions) {
+		o.location = location
+	}
+}
+
+// WithObjectDatabase sets the object database served by the device
+func WithObjectDatabase(db ObjectDatabase) DeviceOption {
+	return func(o *deviceOptions) {
+		o.database = db
+	}
+}
+
+// WithDeviceMaxAPDU sets the maximum APDU length accepted by the device.
+// Replies that do not fit are aborted, as segmentation is not supported.
+func WithDeviceMaxAPDU(length uint16) DeviceOption {
+	return func(o *deviceOptions) {
+		o.maxAPDU = length
+	}
+}
+
+// WithRequestInterceptor sets a function called before the device serves
+// each confirmed request
+func WithRequestInterceptor(interceptor RequestInterceptor) DeviceOption {
+	return func(o *deviceOptions) {
+		o.interceptor = interceptor
+	}
+}
+
+// RampOptions holds configuration for value ramping
+type RampOptions struct {
+	// Property to ramp (default present-value)
+	Property PropertyIdentifier
+
+	// Interval between intermediate writes
+	StepInterval time.Duration
+
+	// Called after each successful step write
+	OnStep func(value float32)
+}
+
+// RampOption is a functional option for value ramping
+type RampOption func(*RampOptions)
+
+// defaultRampOptions returns default ramp options
+func defaultRampOptions() *RampOptions {
+	return &RampOptions{
+		Property:     PropertyPresentValue,
+		StepInterval: time.Second,
+	}
+}
+
+// WithRampProperty sets the property to ramp
+func WithRampProperty(prop PropertyIdentifier) RampOption {
+	return func(o *RampOptions) {
+		o.Property = prop
+	}
+}
+
+// WithRampStepInterval sets the interval between intermediate writes
+func WithRampStepInterval(d time.Duration) RampOption {
+	return func(o *RampOptions) {
+		o.StepInterval = d
+	}
+}
+
+// WithRampProgress sets a callback invoked after each step write
+func WithRampProgress(fn func(value float32)) RampOption {
+	return func(o *RampOptions) {
+		o.OnStep = fn
+	}
+}
+
+// RolloutOptions holds configuration for a schedule rollout
+type RolloutOptions struct {
+	// Restore the previous schedules if any target fails
+	Rollback bool
+
+	// Number of targets written at the same time
+	Concurrency int
+}
+
+// RolloutOption is a functional option for schedule rollouts
+type RolloutOption func(*RolloutOptions)
+
+// defaultRolloutOptions returns default rollout options
+func defaultRolloutOptions() *RolloutOptions {
+	return &RolloutOptions{
+		Rollback:    true,
+		Concurrency: 8,
+	}
+}
+
+// WithRollback enables or disables restoring the previous schedules when
+// any target fails
+func WithRollback(enabled bool) RolloutOption {
+	return func(o *RolloutOptions) {
+		o.Rollback = enabled
+	}
+}
+
+// WithRolloutConcurrency sets the number of targets written at the same time
+func WithRolloutConcurrency(n int) RolloutOption {
+	return func(o *RolloutOptions) {
+		if n > 0 {
+			o.Concurrency = n
+		}
+	}
+}
+
+// objectOptions holds configuration for a local object
+type objectOptions struct {
+	description  string
+	units        EngineeringUnits
+	covIncrement float32
+	stateText    []string
+	commandable  bool
+	onWrite      WriteHandler
+	getters      map[PropertyIdentifier]func() (interface{}, error)
+	setters      map[PropertyIdentifier]func(value interface{}) error
+}
+
+// defaultObjectOptions returns the default local object options
+func defaultObjectOptions() *objectOptions {
+	return &objectOptions{
+		units:   UnitsNoUnits,
+		getters: make(map[PropertyIdentifier]func() (interface{}, error)),
+		setters: make(map[PropertyIdentifier]func(value interface{}) error),
+	}
+}
+
+// ObjectOption is a functional option for configuring a local object
+type ObjectOption func(*objectOptions)
+
+// WithObjectDescription sets the object description
+func WithObjectDescription(description string) ObjectOption {
+	return func(o *objectOptions) {
+		o.description = description
+	}
+}
+
+// WithUnits sets the engineering units of an analog object
+func WithUnits(units EngineeringUnits) ObjectOption {
+	return func(o *objectOptions) {
+		o.units = units
+	}
+}
+
+// WithObjectCOVIncrement sets the minimum present value change of an analog
+// object that triggers a COV notification
+func WithObjectCOVIncrement(increment float32) ObjectOption {
+	return func(o *objectOptions) {
+		o.covIncrement = increment
+	}
+}
+
+// WithStateText sets the state texts of a multi-state object
+func WithStateText(states ...string) ObjectOption {
+	return func(o *objectOptions) {
+		o.stateText = states
+	}
+}
+
+// WithCommandable makes the present value commandable through a 16-level
+// priority array
+func WithCommandable() ObjectOption {
+	return func(o *objectOptions) {
+		o.commandable = true
+	}
+}
+
+// WithWriteHandler sets a handler called before a present value write from
+// the network is applied. Returning an error rejects the write.
+func WithWriteHandler(handler WriteHandler) ObjectOption {
+	return func(o *objectOptions) {
+		o.onWrite = handler
+	}
+}
+
+// WithPropertyGetter serves a property from a getter function. A getter for
+// the present value replaces the stored value.
+func WithPropertyGetter(propertyID PropertyIdentifier, getter func() (interface{}, error)) ObjectOption {
+	return func(o *objectOptions) {
+		o.getters[propertyID] = getter
+	}
+}
+
+// WithPropertySetter makes a property writable through a setter function
+func WithPropertySetter(propertyID PropertyIdentifier, setter func(value interface{}) error) ObjectOption {
+	return func(o *objectOptions) {
+		o.setters[propertyID] = setter
+	}
+}
+
+// schedulerOptions holds configuration for a Scheduler
+type schedulerOptions struct {
+	location *time.Location
+	logger   *slog.Logger
+	audit    func(CronAudit)
+}
+
+// defaultSchedulerOptions returns the default scheduler options
+func defaultSchedulerOptions() *schedulerOptions {
+	return &schedulerOptions{
+		location: time.Local,
+	}
+}
+
+// SchedulerOption is a functional option for configuring a Scheduler
+type SchedulerOption func(*schedulerOptions)
+
+// WithSchedulerLocation sets the time zone cron expressions are evaluated in
+func WithSchedulerLocation(loc *time.Location) SchedulerOption {
+	return func(o *schedulerOptions) {
+		o.location = loc
+	}
+}
+
+// WithSchedulerLogger sets the logger used for audit logging. It defaults
+// to the client logger.
+func WithSchedulerLogger(logger *slog.Logger) SchedulerOption {
+	return func(o *schedulerOptions) {
+		o.logger = logger
+	}
+}
+
+// WithAuditHandler sets a handler called after every job execution, e.g.
+// to persist an audit trail
+func WithAuditHandler(handler func(CronAudit)) SchedulerOption {
+	return func(o *schedulerOptions) {
+		o.audit = handler
+	}
+}
+
+// redundancyOptions holds configuration for a Redundancy coordinator
+type redundancyOptions struct {
+	id                string
+	priority          uint8
+	heartbeatInterval time.Duration
+	failoverTimeout   time.Duration
+	covHandler        COVHandler
+	logger            *slog.Logger
+}
+
+// defaultRedundancyOptions returns the default redundancy options
+func defaultRedundancyOptions() *redundancyOptions {
+	return &redundancyOptions{
+		priority:          100,
+		heartbeatInterval: time.Second,
+		failoverTimeout:   5 * time.Second,
+	}
+}
+
+// RedundancyOption is a functional option for configuring a Redundancy
+// coordinator
+type RedundancyOption func(*redundancyOptions)
+
+// WithRedundancyID sets the instance identifier (random by default). It
+// breaks ties between instances of equal priority.
+func WithRedundancyID(id string) RedundancyOption {
+	return func(o *redundancyOptions) {
+		o.id = id
+	}
+}
+
+// WithRedundancyPriority sets the instance priority; the lower value is
+// preferred as the active instance
+func WithRedundancyPriority(priority uint8) RedundancyOption {
+	return func(o *redundancyOptions) {
+		o.priority = priority
+	}
+}
+
+// WithHeartbeatInterval sets the interval between heartbeats to the peer
+func WithHeartbeatInterval(d time.Duration) RedundancyOption {
+	return func(o *redundancyOptions) {
+		o.heartbeatInterval = d
+	}
+}
+
+// WithFailoverTimeout sets how long the peer may stay silent before the
+// standby takes over
+func WithFailoverTimeout(d time.Duration) RedundancyOption {
+	return func(o *redundancyOptions) {
+		o.failoverTimeout = d
+	}
+}
+
+// WithFailoverCOVHandler sets the handler of the COV subscriptions
+// re-established when the instance takes over
+func WithFailoverCOVHandler(handler COVHandler) RedundancyOption {
+	return func(o *redundancyOptions) {
+		o.covHandler = handler
+	}
+}
+
+// WithRedundancyLogger sets the logger. It defaults to the client logger.
+func WithRedundancyLogger(logger *slog.Logger) RedundancyOption {
+	return func(o *redundancyOptions) {
+		o.logger = logger
+	}
+}
+
+// handleOptions holds the per-device defaults of a DeviceHandle
+type handleOptions struct {
+	timeout time.Duration
+	maxAPDU int
+	rpm     *bool
+}
+
+// HandleOption is a functional option for configuring a DeviceHandle
+type HandleOption func(*handleOptions)
+
+// WithHandleTimeout bounds every request made through the handle
+func WithHandleTimeout(d time.Duration) HandleOption {
+	return func(o *handleOptions) {
+		o.timeout = d
+	}
+}
+
+// WithHandleMaxAPDU sets the maximum APDU used to size ReadMultiple requests,
+// overriding the value announced by the device
+func WithHandleMaxAPDU(length uint16) HandleOption {
+	return func(o *handleOptions) {
+		o.maxAPDU = int(length)
+	}
+}
+
+// WithHandleRPM sets whether the device executes ReadPropertyMultiple. By
+// default it is assumed to until the device rejects the service.
+func WithHandleRPM(supported bool) HandleOption {
+	return func(o *handleOptions) {
+		o.rpm = &supported
+	}
+}
+
+// pollerOptions holds configuration for a Poller
+type pollerOptions struct {
+	buffer      int
+	minBackoff  time.Duration
+	maxBackoff  time.Duration
+	covLifetime time.Duration
+	logger      *slog.Logger
+}
+
+// defaultPollerOptions returns the default poller options
+func defaultPollerOptions() *pollerOptions {
+	return &pollerOptions{
+		buffer:      256,
+		minBackoff:  5 * time.Second,
+		maxBackoff:  5 * time.Minute,
+		covLifetime: 5 * time.Minute,
+	}
+}
+
+// PollerOption is a functional option for configuring a Poller
+type PollerOption func(*pollerOptions)
+
+// WithPollerBuffer sets the capacity of the updates channel
+func WithPollerBuffer(n int) PollerOption {
+	return func(o *pollerOptions) {
+		o.buffer = n
+	}
+}
+
+// WithPollerBackoff sets the delay before retrying an offline device, which
+// doubles on every failure from initial up to limit
+func WithPollerBackoff(initial, limit time.Duration) PollerOption {
+	return func(o *pollerOptions) {
+		o.minBackoff = initial
+		o.maxBackoff = limit
+	}
+}
+
+// WithPollerCOVLifetime sets the lifetime of COV subscriptions, which are
+// renewed halfway through
+func WithPollerCOVLifetime(d time.Duration) PollerOption {
+	return func(o *pollerOptions) {
+		o.covLifetime = d
+	}
+}
+
+// WithPollerLogger sets the logger. It defaults to the client logger.
+func WithPollerLogger(logger *slog.Logger) PollerOption {
+	return func(o *pollerOptions) {
+		o.logger = logger
+	}
+}
+
+// FileOptions holds configuration for File object transfers
+type FileOptions struct {
+	// ChunkSize is the number of octets read or written per request. Zero
+	// sizes chunks to the maximum APDU of the device.
+	ChunkSize int
+
+	// Progress is called after each chunk with the octets transferred and
+	// the file size, or -1 when the size is unknown
+	Progress func(done, total int64)
+}
+
+// FileOption is a functional option for File object transfers
+type FileOption func(*FileOptions)
+
+// WithFileChunkSize sets the number of octets read or written per request
+func WithFileChunkSize(n int) FileOption {
+	return func(o *FileOptions) {
+		o.ChunkSize = n
+	}
+}
+
+// WithFileProgress sets a callback invoked after each chunk
+func WithFileProgress(fn func(done, total int64)) FileOption {
+	return func(o *FileOptions) {
+		o.Progress = fn
+	}
+}
+
+// BackupOptions holds configuration for device backups and restores
+type BackupOptions struct {
+	// Password sent with the ReinitializeDevice requests
+	Password string
+
+	// Progress is called after each chunk of a configuration file
+	Progress func(file ObjectIdentifier, done, total int64)
+}
+
+// BackupOption is a functional option for device backups and restores
+type BackupOption func(*BackupOptions)
+
+// WithBackupPassword sets the password of the ReinitializeDevice requests
+func WithBackupPassword(password string) BackupOption {
+	return func(o *BackupOptions) {
+		o.Password = password
+	}
+}
+
+// WithBackupProgress sets a callback invoked after each chunk of a
+// configuration file
+func WithBackupProgress(fn func(file ObjectIdentifier, done, total int64)) BackupOption {
+	return func(o *BackupOptions) {
+		o.Progress = fn
+	}
+}
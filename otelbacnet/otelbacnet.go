@@ -0,0 +1,162 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+// Package otelbacnet traces the confirmed requests of a bacnet client with
+// OpenTelemetry.
+//
+// The interceptor starts a client span per request, as a child of the span
+// in the request context, and passes the span context on to the inner
+// interceptors:
+//
+//	client.Use(otelbacnet.NewInterceptor(otelbacnet.WithTracerProvider(tp)))
+//	ctx, span := tracer.Start(ctx, "read zone temperatures")
+//	client.ReadProperty(ctx, 1234, oid, bacnet.PropertyPresentValue)
+//	span.End()
+package otelbacnet
+
+import (
+	"context"
+	"errors"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+
+	"github.com/edgeo-scada/bacnet"
+)
+
+// tracerName identifies the instrumentation library
+const tracerName = "github.com/edgeo-scada/bacnet/otelbacnet"
+
+// Span attributes
+const (
+	AttrDeviceID = attribute.Key("bacnet.device_id")
+	AttrService  = attribute.Key("bacnet.service")
+	AttrInvokeID = attribute.Key("bacnet.invoke_id")
+	AttrObject   = attribute.Key("bacnet.object")
+	AttrProperty = attribute.Key("bacnet.property")
+	AttrOutcome  = attribute.Key("bacnet.outcome")
+	AttrPeer     = attribute.Key("network.peer.address")
+)
+
+// wildcardDeviceInstance marks requests not sent to a known device
+const wildcardDeviceInstance = 4194303
+
+type config struct {
+	provider trace.TracerProvider
+}
+
+// Option configures the interceptor
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider (default: the global one)
+func WithTracerProvider(provider trace.TracerProvider) Option {
+	return func(c *config) {
+		if provider != nil {
+			c.provider = provider
+		}
+	}
+}
+
+// NewInterceptor returns an interceptor that records a span for each
+// confirmed request, to install with Client.Use
+func NewInterceptor(opts ...Option) bacnet.Interceptor {
+	cfg := config{provider: otel.GetTracerProvider()}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+	tracer := cfg.provider.Tracer(tracerName)
+
+	return func(ctx context.Context, req *bacnet.Request, next bacnet.Invoker) (*bacnet.APDU, error) {
+		attrs := []attribute.KeyValue{AttrService.String(req.Service.String())}
+		if req.DeviceID != wildcardDeviceInstance {
+			attrs = append(attrs, AttrDeviceID.Int64(int64(req.DeviceID)))
+		}
+		if req.Address != nil {
+			attrs = append(attrs, AttrPeer.String(req.Address.String()))
+		}
+		attrs = append(attrs, requestTarget(req)...)
+
+		ctx, span := tracer.Start(ctx, "BACnet "+req.Service.String(),
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(attrs...),
+		)
+		defer span.End()
+
+		resp, err := next(ctx, req)
+
+		outcome := Outcome(err)
+		span.SetAttributes(AttrInvokeID.Int(int(req.InvokeID)), AttrOutcome.String(outcome))
+		if err != nil {
+			span.RecordError(err)
+			span.SetStatus(codes.Error, outcome)
+		}
+		return resp, err
+	}
+}
+
+// Outcome classifies the result of a request as "ok", "timeout", "error"
+// (a BACnet Error response), "reject", "abort" or "failed"
+func Outcome(err error) string {
+	var bacErr *bacnet.BACnetError
+	var rejectErr *bacnet.RejectError
+	var abortErr *bacnet.AbortError
+	switch {
+	case err == nil:
+		return "ok"
+	case bacnet.IsTimeout(err):
+		return "timeout"
+	case errors.As(err, &bacErr):
+		return "error"
+	case errors.As(err, &rejectErr):
+		return "reject"
+	case errors.As(err, &abortErr):
+		return "abort"
+	default:
+		return "failed"
+	}
+}
+
+// requestTarget returns the object and property attributes of the services
+// that address one object
+func requestTarget(req *bacnet.Request) []attribute.KeyValue {
+	d := bacnet.NewDecoder(req.Data)
+	switch req.Service {
+	case bacnet.ServiceReadProperty, bacnet.ServiceWriteProperty, bacnet.ServiceReadRange:
+		oid := d.ContextObjectIdentifier(0)
+		prop := bacnet.PropertyIdentifier(d.ContextEnumerated(1))
+		if d.Err() != nil {
+			return nil
+		}
+		return []attribute.KeyValue{AttrObject.String(oid.String()), AttrProperty.String(prop.String())}
+
+	case bacnet.ServiceReadPropertyMultiple, bacnet.ServiceWritePropertyMultiple:
+		// The first object of the request
+		oid := d.ContextObjectIdentifier(0)
+		if d.Err() != nil {
+			return nil
+		}
+		return []attribute.KeyValue{AttrObject.String(oid.String())}
+
+	case bacnet.ServiceSubscribeCOV, bacnet.ServiceSubscribeCOVProperty:
+		d.ContextUnsigned(0)
+		oid := d.ContextObjectIdentifier(1)
+		if d.Err() != nil {
+			return nil
+		}
+		return []attribute.KeyValue{AttrObject.String(oid.String())}
+	}
+	return nil
+}
@@ -0,0 +1,181 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"encoding/binary"
+	"io"
+	"log/slog"
+	"net"
+	"sync"
+	"time"
+)
+
+// pcap file constants
+const (
+	pcapMagic       = 0xa1b2c3d4 // microsecond timestamps
+	pcapSnapLen     = 65535
+	pcapLinkTypeRaw = 101 // raw IPv4 or IPv6 packets
+	ipv4HeaderLen   = 20
+	udpHeaderLen    = 8
+	ipProtocolUDP   = 17
+	captureHopLimit = 64
+)
+
+// pcapWriter writes the UDP datagrams of the data link to a pcap stream as
+// raw IP packets, with IP and UDP headers rebuilt from the socket addresses
+type pcapWriter struct {
+	mu     sync.Mutex
+	w      io.Writer
+	header bool
+	failed bool
+	logger *slog.Logger
+	buf    []byte
+}
+
+// newPcapWriter creates a capture writer; the file header is written with
+// the first packet
+func newPcapWriter(w io.Writer, logger *slog.Logger) *pcapWriter {
+	return &pcapWriter{w: w, logger: logger}
+}
+
+// capture records a datagram; it matches transport.CaptureFunc
+func (p *pcapWriter) capture(sent bool, local, remote *net.UDPAddr, data []byte) {
+	if local == nil || remote == nil {
+		return
+	}
+	src, dst := local, remote
+	if !sent {
+		src, dst = remote, local
+	}
+	now := time.Now()
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failed {
+		return
+	}
+
+	p.buf = p.buf[:0]
+	if !p.header {
+		p.buf = binary.LittleEndian.AppendUint32(p.buf, pcapMagic)
+		p.buf = binary.LittleEndian.AppendUint16(p.buf, 2) // version 2.4
+		p.buf = binary.LittleEndian.AppendUint16(p.buf, 4)
+		p.buf = binary.LittleEndian.AppendUint32(p.buf, 0) // UTC
+		p.buf = binary.LittleEndian.AppendUint32(p.buf, 0) // timestamp accuracy
+		p.buf = binary.LittleEndian.AppendUint32(p.buf, pcapSnapLen)
+		p.buf = binary.LittleEndian.AppendUint32(p.buf, pcapLinkTypeRaw)
+		p.header = true
+	}
+
+	// Record header, with the packet length filled in below
+	record := len(p.buf)
+	p.buf = binary.LittleEndian.AppendUint32(p.buf, uint32(now.Unix()))
+	p.buf = binary.LittleEndian.AppendUint32(p.buf, uint32(now.Nanosecond()/1000))
+	p.buf = append(p.buf, make([]byte, 8)...)
+
+	packet := len(p.buf)
+	p.buf = appendIPUDP(p.buf, src, dst, data)
+	n := uint32(len(p.buf) - packet)
+	binary.LittleEndian.PutUint32(p.buf[record+8:], n)
+	binary.LittleEndian.PutUint32(p.buf[record+12:], n)
+
+	if _, err := p.w.Write(p.buf); err != nil {
+		p.failed = true
+		p.logger.Warn("packet capture stopped", slog.String("error", err.Error()))
+	}
+}
+
+// appendIPUDP appends an IPv4 or IPv6 packet carrying a UDP datagram. An
+// unspecified address of the other family, as on dual-stack sockets, is
+// mapped to the family of the peer.
+func appendIPUDP(buf []byte, src, dst *net.UDPAddr, payload []byte) []byte {
+	srcIP, dstIP := src.IP.To4(), dst.IP.To4()
+	v4 := srcIP != nil && dstIP != nil
+	if !v4 && (srcIP != nil || dstIP != nil) {
+		// Mixed families: one side is an unspecified wildcard
+		if srcIP != nil && dst.IP.IsUnspecified() {
+			dstIP, v4 = net.IPv4zero.To4(), true
+		} else if dstIP != nil && src.IP.IsUnspecified() {
+			srcIP, v4 = net.IPv4zero.To4(), true
+		}
+	}
+	if !v4 {
+		srcIP, dstIP = src.IP.To16(), dst.IP.To16()
+		if srcIP == nil {
+			srcIP = net.IPv6unspecified
+		}
+		if dstIP == nil {
+			dstIP = net.IPv6unspecified
+		}
+	}
+
+	udpLen := udpHeaderLen + len(payload)
+	if v4 {
+		start := len(buf)
+		buf = append(buf, 0x45, 0) // version 4, 20-byte header
+		buf = binary.BigEndian.AppendUint16(buf, uint16(ipv4HeaderLen+udpLen))
+		buf = append(buf, 0, 0, 0x40, 0) // identification, don't fragment
+		buf = append(buf, captureHopLimit, ipProtocolUDP, 0, 0)
+		buf = append(buf, srcIP...)
+		buf = append(buf, dstIP...)
+		binary.BigEndian.PutUint16(buf[start+10:], checksum(0, buf[start:]))
+	} else {
+		buf = append(buf, 0x60, 0, 0, 0) // version 6
+		buf = binary.BigEndian.AppendUint16(buf, uint16(udpLen))
+		buf = append(buf, ipProtocolUDP, captureHopLimit)
+		buf = append(buf, srcIP...)
+		buf = append(buf, dstIP...)
+	}
+
+	start := len(buf)
+	buf = binary.BigEndian.AppendUint16(buf, uint16(src.Port))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(dst.Port))
+	buf = binary.BigEndian.AppendUint16(buf, uint16(udpLen))
+	buf = append(buf, 0, 0)
+	buf = append(buf, payload...)
+
+	// The UDP checksum covers a pseudo header of the addresses, protocol
+	// and length
+	sum := sumWords(0, srcIP)
+	sum = sumWords(sum, dstIP)
+	sum += ipProtocolUDP + uint32(udpLen)
+	cs := checksum(sum, buf[start:])
+	if cs == 0 {
+		cs = 0xffff
+	}
+	binary.BigEndian.PutUint16(buf[start+6:], cs)
+	return buf
+}
+
+// sumWords adds the 16-bit big-endian words of b to a one's complement sum
+func sumWords(sum uint32, b []byte) uint32 {
+	for i := 0; i+1 < len(b); i += 2 {
+		sum += uint32(b[i])<<8 | uint32(b[i+1])
+	}
+	if len(b)%2 == 1 {
+		sum += uint32(b[len(b)-1]) << 8
+	}
+	return sum
+}
+
+// checksum returns the Internet checksum of b added to a partial sum
+func checksum(sum uint32, b []byte) uint16 {
+	sum = sumWords(sum, b)
+	for sum>>16 != 0 {
+		sum = sum&0xffff + sum>>16
+	}
+	return ^uint16(sum)
+}
@@ -0,0 +1,53 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// Ping checks that a device is alive by reading the object identifier of
+// its device object, and returns the round-trip time. An unknown device is
+// located with a directed Who-Is first, which is not counted in the
+// latency. Any reply counts as alive, including an Error, Reject or Abort,
+// since a device that refuses the read is still answering.
+func (c *Client) Ping(ctx context.Context, deviceID uint32) (time.Duration, error) {
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return 0, err
+	}
+
+	e := NewEncoder(make([]byte, 0, 8))
+	e.ContextObjectIdentifier(0, NewObjectIdentifier(ObjectTypeDevice, deviceID))
+	e.ContextEnumerated(1, uint32(PropertyObjectIdentifier))
+
+	start := c.opts.clock.Now()
+	_, err = c.sendRequest(ctx, deviceID, addr, ServiceReadProperty, e.Bytes())
+	rtt := c.opts.clock.Now().Sub(start)
+	if err != nil && !isDeviceReply(err) {
+		return 0, err
+	}
+	return rtt, nil
+}
+
+// isDeviceReply returns true for errors carried by a reply of the device
+func isDeviceReply(err error) bool {
+	var bacErr *BACnetError
+	var rejectErr *RejectError
+	var abortErr *AbortError
+	return errors.As(err, &bacErr) || errors.As(err, &rejectErr) || errors.As(err, &abortErr)
+}
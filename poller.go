@@ -0,0 +1,472 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// Point is a property of a remote object monitored by a Poller
+type Point struct {
+	// Name identifies the point in updates and logs
+	Name string
+
+	DeviceID uint32
+	ObjectID ObjectIdentifier
+
+	// PropertyID defaults to present-value
+	PropertyID PropertyIdentifier
+
+	// Interval is the polling interval. COV points are polled at this
+	// interval when the device refuses the subscription.
+	Interval time.Duration
+
+	// COV prefers a COV subscription to polling
+	COV bool
+}
+
+// Quality describes the validity of a PointUpdate
+type Quality uint8
+
+const (
+	// QualityGood is a value read from the device
+	QualityGood Quality = iota
+	// QualityBad is a property the device could not read
+	QualityBad
+	// QualityOffline is a device that does not answer
+	QualityOffline
+)
+
+func (q Quality) String() string {
+	switch q {
+	case QualityGood:
+		return "good"
+	case QualityBad:
+		return "bad"
+	case QualityOffline:
+		return "offline"
+	default:
+		return fmt.Sprintf("quality(%d)", q)
+	}
+}
+
+// PointUpdate is a value of a point delivered by a Poller
+type PointUpdate struct {
+	Point   Point
+	Value   interface{}
+	Time    time.Time
+	Quality Quality
+
+	// Err is the error of a bad or offline point
+	Err error
+}
+
+// polledPoint is a point and its polling state
+type polledPoint struct {
+	point Point
+
+	// next is when the point must be read or its subscription renewed
+	next time.Time
+
+	subscribed bool
+	subID      uint32
+
+	// covFailed is set when the device refused the subscription
+	covFailed bool
+}
+
+// polledDevice is the availability of a device
+type polledDevice struct {
+	offline  bool
+	failures int
+}
+
+// Poller monitors points, reading the points of each device together with
+// ReadPropertyMultiple or subscribing to COV, and delivers their values on
+// a channel. Devices that stop answering are reported offline and retried
+// with exponential backoff.
+type Poller struct {
+	client  *Client
+	opts    *pollerOptions
+	updates chan PointUpdate
+
+	mu      sync.Mutex
+	points  []*polledPoint
+	devices map[uint32]*polledDevice
+	busy    map[uint32]bool
+	wake    chan struct{}
+}
+
+// NewPoller creates a poller reading through client
+func NewPoller(client *Client, opts ...PollerOption) *Poller {
+	options := defaultPollerOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.logger == nil {
+		options.logger = client.logger
+	}
+
+	return &Poller{
+		client:  client,
+		opts:    options,
+		updates: make(chan PointUpdate, options.buffer),
+		devices: make(map[uint32]*polledDevice),
+		busy:    make(map[uint32]bool),
+		wake:    make(chan struct{}, 1),
+	}
+}
+
+// Add adds a point. It may be called while the poller runs.
+func (p *Poller) Add(point Point) error {
+	if point.Interval <= 0 {
+		return fmt.Errorf("point %q: interval must be positive", point.Name)
+	}
+	if point.PropertyID == 0 {
+		point.PropertyID = PropertyPresentValue
+	}
+
+	p.mu.Lock()
+	p.points = append(p.points, &polledPoint{point: point})
+	if _, ok := p.devices[point.DeviceID]; !ok {
+		p.devices[point.DeviceID] = &polledDevice{}
+	}
+	p.mu.Unlock()
+
+	p.signal()
+	return nil
+}
+
+// Updates returns the channel on which point values are delivered
+func (p *Poller) Updates() <-chan PointUpdate {
+	return p.updates
+}
+
+// Run polls the points as they become due until ctx is cancelled, then
+// cancels the COV subscriptions. Devices are polled concurrently, so that a
+// device that does not answer delays only its own points.
+func (p *Poller) Run(ctx context.Context) error {
+	var wg sync.WaitGroup
+	defer p.unsubscribeAll()
+	defer wg.Wait()
+
+	clock := p.client.opts.clock
+	for {
+		due, wait := p.due(clock.Now())
+		for deviceID, points := range due {
+			wg.Add(1)
+			go func(deviceID uint32, points []*polledPoint) {
+				defer wg.Done()
+				p.pollDevice(ctx, deviceID, points)
+
+				p.mu.Lock()
+				delete(p.busy, deviceID)
+				p.mu.Unlock()
+				p.signal()
+			}(deviceID, points)
+		}
+
+		// Without idle points, sleep until one is added or a device is done
+		var (
+			timer Timer
+			fire  <-chan time.Time
+		)
+		if wait >= 0 {
+			timer = clock.NewTimer(wait)
+			fire = timer.C()
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return ctx.Err()
+		case <-p.wake:
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-fire:
+		}
+	}
+}
+
+// due returns the points due at now by device, marking their devices busy,
+// and the time until the next point of an idle device is due, or -1 if there
+// is none
+func (p *Poller) due(now time.Time) (map[uint32][]*polledPoint, time.Duration) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	due := make(map[uint32][]*polledPoint)
+	var wait time.Duration = -1
+	for _, pp := range p.points {
+		if p.busy[pp.point.DeviceID] {
+			continue
+		}
+		if !pp.next.After(now) {
+			due[pp.point.DeviceID] = append(due[pp.point.DeviceID], pp)
+		} else if d := pp.next.Sub(now); wait < 0 || d < wait {
+			wait = d
+		}
+	}
+	for deviceID := range due {
+		p.busy[deviceID] = true
+	}
+	return due, wait
+}
+
+// signal wakes Run up to recompute the due points
+func (p *Poller) signal() {
+	select {
+	case p.wake <- struct{}{}:
+	default:
+	}
+}
+
+// pollDevice subscribes or reads the due points of one device
+func (p *Poller) pollDevice(ctx context.Context, deviceID uint32, points []*polledPoint) {
+	var reads, initial []*polledPoint
+	for _, pp := range points {
+		if pp.point.COV && !pp.covFailed {
+			p.mu.Lock()
+			renewal := pp.subscribed
+			p.mu.Unlock()
+
+			err := p.subscribe(ctx, pp)
+			if err == nil {
+				if !renewal {
+					// Read the value the notifications will update
+					initial = append(initial, pp)
+				}
+				continue
+			}
+			if !IsTimeout(err) && !IsDeviceNotFound(err) {
+				// Refused by the device: poll instead
+				p.opts.logger.Info("COV subscription refused, polling",
+					slog.String("point", pp.point.Name),
+					slog.String("error", err.Error()),
+				)
+				p.mu.Lock()
+				pp.covFailed = true
+				if !pp.subscribed {
+					p.client.releaseSubscriberID(pp.subID)
+					pp.subID = 0
+				}
+				p.mu.Unlock()
+			}
+		}
+		reads = append(reads, pp)
+	}
+
+	// Polled points are rescheduled, newly subscribed points are read once
+	all := append(reads[:len(reads):len(reads)], initial...)
+	if len(all) == 0 {
+		return
+	}
+
+	requests := make([]ReadPropertyRequest, len(all))
+	for i, pp := range all {
+		requests[i] = ReadPropertyRequest{ObjectID: pp.point.ObjectID, PropertyID: pp.point.PropertyID}
+	}
+
+	readCtx, cancel := context.WithTimeout(ctx, p.client.opts.timeout)
+	results, err := p.client.ReadPropertyMultiple(readCtx, deviceID, requests)
+	if serviceUnsupported(err) {
+		results, err = p.client.readEach(readCtx, deviceID, requests)
+	}
+	cancel()
+
+	now := p.client.opts.clock.Now()
+	if err != nil {
+		if ctx.Err() == nil && len(reads) > 0 {
+			p.deviceFailed(ctx, deviceID, reads, err, now)
+		}
+		return
+	}
+	p.deviceAnswered(deviceID)
+
+	type key struct {
+		objectID   ObjectIdentifier
+		propertyID PropertyIdentifier
+	}
+	byKey := make(map[key]PropertyResult, len(results))
+	for _, r := range results {
+		byKey[key{r.ObjectID, r.PropertyID}] = r
+	}
+
+	for i, pp := range all {
+		update := PointUpdate{Point: pp.point, Time: now}
+		if r, ok := byKey[key{pp.point.ObjectID, pp.point.PropertyID}]; !ok {
+			update.Quality = QualityBad
+			update.Err = fmt.Errorf("%w: not returned by the device", ErrPropertyNotFound)
+		} else if r.Err != nil {
+			update.Quality = QualityBad
+			update.Err = r.Err
+		} else {
+			update.Value = r.Value
+		}
+
+		if i < len(reads) {
+			p.mu.Lock()
+			pp.next = now.Add(pp.point.Interval)
+			p.mu.Unlock()
+		}
+
+		p.deliver(ctx, update)
+	}
+}
+
+// subscribe subscribes to COV notifications of a point, or renews its
+// subscription
+func (p *Poller) subscribe(ctx context.Context, pp *polledPoint) error {
+	p.mu.Lock()
+	if pp.subID == 0 {
+		pp.subID = p.client.nextSubscriberID()
+	}
+	subID := pp.subID
+	p.mu.Unlock()
+
+	point := pp.point
+	handler := func(_ uint32, objectID ObjectIdentifier, values []PropertyValue) {
+		if objectID != point.ObjectID {
+			return
+		}
+		for _, v := range values {
+			if v.PropertyID == point.PropertyID {
+				p.notify(PointUpdate{Point: point, Value: v.Value, Time: p.client.opts.clock.Now()})
+			}
+		}
+	}
+
+	lifetime := uint32(p.opts.covLifetime / time.Second)
+	subCtx, cancel := context.WithTimeout(ctx, p.client.opts.timeout)
+	defer cancel()
+	if err := p.client.subscribeCOV(subCtx, point.DeviceID, point.ObjectID, nil, subID, handler, &SubscribeOptions{Lifetime: &lifetime}); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	pp.subscribed = true
+	pp.next = p.client.opts.clock.Now().Add(p.opts.covLifetime / 2)
+	p.mu.Unlock()
+	p.deviceAnswered(point.DeviceID)
+	return nil
+}
+
+// deviceFailed reports the points of a device that did not answer as
+// offline and delays them with exponential backoff
+func (p *Poller) deviceFailed(ctx context.Context, deviceID uint32, points []*polledPoint, err error, now time.Time) {
+	p.mu.Lock()
+	dev := p.devices[deviceID]
+	dev.failures++
+	// Doubling stops at the maximum, before the shift could overflow
+	backoff := p.opts.minBackoff
+	for i := 1; i < dev.failures && backoff < p.opts.maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > p.opts.maxBackoff {
+		backoff = p.opts.maxBackoff
+	}
+	wentOffline := !dev.offline
+	dev.offline = true
+	for _, pp := range points {
+		pp.next = now.Add(backoff)
+	}
+	p.mu.Unlock()
+
+	if !wentOffline {
+		return
+	}
+
+	p.opts.logger.Warn("device offline",
+		slog.Uint64("device_id", uint64(deviceID)),
+		slog.String("error", err.Error()),
+		slog.Duration("retry", backoff),
+	)
+	for _, pp := range points {
+		p.deliver(ctx, PointUpdate{Point: pp.point, Time: now, Quality: QualityOffline, Err: err})
+	}
+}
+
+// deviceAnswered clears the backoff of a device
+func (p *Poller) deviceAnswered(deviceID uint32) {
+	p.mu.Lock()
+	dev := p.devices[deviceID]
+	wasOffline := dev.offline
+	dev.offline = false
+	dev.failures = 0
+	p.mu.Unlock()
+
+	if wasOffline {
+		p.opts.logger.Info("device online", slog.Uint64("device_id", uint64(deviceID)))
+	}
+}
+
+// deliver sends an update, waiting for room in the channel
+func (p *Poller) deliver(ctx context.Context, update PointUpdate) {
+	select {
+	case p.updates <- update:
+	case <-ctx.Done():
+	}
+}
+
+// notify sends a COV update without blocking the receive goroutine
+func (p *Poller) notify(update PointUpdate) {
+	select {
+	case p.updates <- update:
+	default:
+		p.opts.logger.Warn("poller updates channel full, dropping COV update",
+			slog.String("point", update.Point.Name),
+		)
+	}
+}
+
+// unsubscribeAll cancels the COV subscriptions of the points
+func (p *Poller) unsubscribeAll() {
+	type subscription struct {
+		point *polledPoint
+		subID uint32
+	}
+
+	p.mu.Lock()
+	var subscribed []subscription
+	for _, pp := range p.points {
+		if pp.subscribed {
+			subscribed = append(subscribed, subscription{point: pp, subID: pp.subID})
+			pp.subscribed = false
+		} else if pp.subID != 0 {
+			p.client.releaseSubscriberID(pp.subID)
+		}
+		pp.subID = 0
+	}
+	p.mu.Unlock()
+
+	for _, sub := range subscribed {
+		pp := sub.point
+		ctx, cancel := context.WithTimeout(context.Background(), p.client.opts.timeout)
+		if err := p.client.UnsubscribeCOV(ctx, pp.point.DeviceID, pp.point.ObjectID, sub.subID); err != nil {
+			p.opts.logger.Debug("failed to cancel COV subscription",
+				slog.String("point", pp.point.Name),
+				slog.String("error", err.Error()),
+			)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,60 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+)
+
+// PriorityArray is the priority-array of a commandable object. Slot i holds
+// the command at priority i+1, nil when relinquished.
+type PriorityArray [16]interface{}
+
+// Active returns the highest priority holding a command and its value, or
+// false when all are relinquished and the relinquish default applies
+func (a PriorityArray) Active() (priority uint8, value interface{}, ok bool) {
+	for i, v := range a {
+		if v != nil {
+			return uint8(i + 1), v, true
+		}
+	}
+	return 0, nil, false
+}
+
+// ReleasePriority relinquishes the command at the given priority (1-16) of
+// the present value of a commandable object by writing null to it
+func (c *Client) ReleasePriority(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, priority uint8) error {
+	if priority < 1 || priority > 16 {
+		return fmt.Errorf("bacnet: priority %d out of range 1-16", priority)
+	}
+	return c.WriteProperty(ctx, deviceID, objectID, PropertyPresentValue, nil, WithPriority(priority))
+}
+
+// ReadPriorityArray reads the priority-array of a commandable object
+func (c *Client) ReadPriorityArray(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) (PriorityArray, error) {
+	var array PriorityArray
+
+	values, err := c.ReadArray(ctx, deviceID, objectID, PropertyPriorityArray)
+	if err != nil {
+		return array, err
+	}
+	if len(values) != len(array) {
+		return array, fmt.Errorf("%w: priority-array of %d elements", ErrInvalidResponse, len(values))
+	}
+
+	copy(array[:], values)
+	return array, nil
+}
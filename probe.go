@@ -0,0 +1,235 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// maxProbeHosts bounds the addresses of a range probed by ProbeRange
+const maxProbeHosts = 65536
+
+// ProbeRange discovers the devices of an IPv4 range, such as 10.1.0.0/24,
+// by sending a directed Who-Is to each of its addresses, for networks where
+// broadcasts are blocked. The probes are sent at the rate set by
+// WithProbeRate, WithProbeConcurrency at a time; WithProbeReadProperty
+// reads the device object of each address instead. It returns the devices
+// found, in the order they answered, until the discovery timeout after the
+// last probe, or until ctx ends with the devices found so far.
+func (c *Client) ProbeRange(ctx context.Context, cidr string, opts ...DiscoverOption) ([]*DeviceInfo, error) {
+	options := defaultDiscoverOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.ProbeConcurrency < 1 {
+		options.ProbeConcurrency = 1
+	}
+
+	hosts, err := probeHosts(cidr)
+	if err != nil {
+		return nil, err
+	}
+	addrs := make([]net.Addr, len(hosts))
+	for i, host := range hosts {
+		addr, err := c.link.ParseAddr(net.JoinHostPort(host.String(), strconv.Itoa(options.ProbePort)))
+		if err != nil {
+			return nil, fmt.Errorf("invalid probe address: %w", err)
+		}
+		addrs[i] = addr
+	}
+
+	var e Encoder
+	if options.LowLimit != nil && options.HighLimit != nil {
+		e.ContextUnsigned(0, *options.LowLimit)
+		e.ContextUnsigned(1, *options.HighLimit)
+	}
+	whoIs := e.Bytes()
+
+	found := newDiscovery(options)
+	stop := c.watchIAm(found.add)
+	defer stop()
+
+	var wg sync.WaitGroup
+	work := make(chan net.Addr)
+	for i := 0; i < options.ProbeConcurrency; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for addr := range work {
+				if options.ProbeRead {
+					c.probeRead(ctx, addr, found)
+				} else if err := c.sendUnconfirmedRequest(ctx, addr, false, ServiceWhoIs, whoIs); err != nil {
+					c.logger.Debug("probe failed", slog.String("address", addr.String()), slog.Any("error", err))
+				} else {
+					c.metrics.WhoIsSent.Inc()
+				}
+			}
+		}()
+	}
+
+	var tick <-chan time.Time
+	if options.ProbeRate > 0 {
+		ticker := c.opts.clock.NewTicker(time.Second / time.Duration(options.ProbeRate))
+		defer ticker.Stop()
+		tick = ticker.C()
+	}
+
+feed:
+	for _, addr := range addrs {
+		if tick != nil {
+			select {
+			case <-tick:
+			case <-ctx.Done():
+				break feed
+			}
+		}
+		select {
+		case work <- addr:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(work)
+	wg.Wait()
+
+	if err := ctx.Err(); err != nil {
+		return found.devices(), err
+	}
+
+	// A read answers with the device, a Who-Is with an I-Am later
+	if !options.ProbeRead {
+		err = sleepContext(ctx, c.opts.clock, options.Timeout)
+	}
+
+	return found.devices(), err
+}
+
+// probeHosts returns the host addresses of an IPv4 range, without its
+// network and broadcast addresses but for /31 and /32
+func probeHosts(cidr string) ([]net.IP, error) {
+	_, network, err := net.ParseCIDR(cidr)
+	if err != nil {
+		return nil, fmt.Errorf("invalid address range: %w", err)
+	}
+	base := network.IP.To4()
+	if base == nil {
+		return nil, fmt.Errorf("invalid address range %s: not IPv4", cidr)
+	}
+	ones, bits := network.Mask.Size()
+	size := 1 << uint(bits-ones)
+	if size > maxProbeHosts {
+		return nil, fmt.Errorf("address range %s too large: at most %d addresses", cidr, maxProbeHosts)
+	}
+
+	first, last := 0, size
+	if size > 2 {
+		first, last = 1, size-1
+	}
+	start := uint32(base[0])<<24 | uint32(base[1])<<16 | uint32(base[2])<<8 | uint32(base[3])
+	hosts := make([]net.IP, 0, last-first)
+	for i := first; i < last; i++ {
+		n := start + uint32(i)
+		hosts = append(hosts, net.IPv4(byte(n>>24), byte(n>>16), byte(n>>8), byte(n)))
+	}
+	return hosts, nil
+}
+
+// probeRead reads the object identifier of the device object at an
+// address, addressed by the wildcard instance, adding the device answering
+// to the devices found
+func (c *Client) probeRead(ctx context.Context, addr net.Addr, found *discovery) {
+	reqCtx, cancel := context.WithTimeout(ctx, c.opts.timeout)
+	defer cancel()
+
+	e := NewEncoder(make([]byte, 0, 16))
+	e.ContextObjectIdentifier(0, NewObjectIdentifier(ObjectTypeDevice, wildcardDeviceInstance))
+	e.ContextEnumerated(1, uint32(PropertyObjectIdentifier))
+	resp, err := c.sendRequest(reqCtx, wildcardDeviceInstance, addr, ServiceReadProperty, e.Bytes())
+	if err != nil {
+		c.logger.Debug("probe failed", slog.String("address", addr.String()), slog.Any("error", err))
+		return
+	}
+	value, err := c.decodeReadPropertyResponse(resp.Data)
+	oid, ok := value.(ObjectIdentifier)
+	if err != nil || !ok || oid.Type != ObjectTypeDevice {
+		return
+	}
+
+	found.add(c.probedDevice(reqCtx, oid.Instance, addr))
+}
+
+// probedDevice records a device found by a read at an address, reading
+// what its I-Am would have told: the maximum APDU length it accepts, its
+// segmentation and its vendor. A device already known at the address is
+// kept as is.
+func (c *Client) probedDevice(ctx context.Context, deviceID uint32, addr net.Addr) *DeviceInfo {
+	mac := c.link.MAC(addr)
+
+	c.devicesMu.Lock()
+	prev, exists := c.devices[deviceID]
+	if exists && prev.Address.Net == 0 && bytes.Equal(prev.Address.Addr, mac) {
+		c.devicesMu.Unlock()
+		return prev
+	}
+	device := &DeviceInfo{
+		ObjectID:      NewObjectIdentifier(ObjectTypeDevice, deviceID),
+		Address:       Address{Addr: mac},
+		MaxAPDULength: MaxAPDULength,
+	}
+	c.devices[deviceID] = device
+	c.devicesMu.Unlock()
+	if !exists {
+		c.metrics.DevicesDiscovered.Inc()
+	}
+
+	oid := device.ObjectID
+	results, err := c.Device(deviceID).ReadMultiple(ctx, []ReadPropertyRequest{
+		{ObjectID: oid, PropertyID: PropertyMaxApduLengthAccepted},
+		{ObjectID: oid, PropertyID: PropertySegmentationSupported},
+		{ObjectID: oid, PropertyID: PropertyVendorIdentifier},
+	})
+	if err == nil {
+		read := *device
+		for _, r := range results {
+			v, ok := r.Value.(uint32)
+			if r.Err != nil || !ok {
+				continue
+			}
+			switch r.PropertyID {
+			case PropertyMaxApduLengthAccepted:
+				read.MaxAPDULength = uint16(v)
+			case PropertySegmentationSupported:
+				read.Segmentation = Segmentation(v)
+			case PropertyVendorIdentifier:
+				read.VendorID = uint16(v)
+			}
+		}
+		device = &read
+
+		c.devicesMu.Lock()
+		c.devices[deviceID] = device
+		c.devicesMu.Unlock()
+	}
+	c.storeDevice(device)
+
+	return device
+}
@@ -0,0 +1,377 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+// ObjectProperties are the standard properties of an object type, as listed
+// by ASHRAE 135
+type ObjectProperties struct {
+	Required []PropertyIdentifier
+	Optional []PropertyIdentifier
+}
+
+// All returns the required properties followed by the optional ones
+func (p ObjectProperties) All() []PropertyIdentifier {
+	all := make([]PropertyIdentifier, 0, len(p.Required)+len(p.Optional))
+	all = append(all, p.Required...)
+	return append(all, p.Optional...)
+}
+
+// commonObjectProperties are the properties of every object
+var commonObjectProperties = []PropertyIdentifier{
+	PropertyObjectIdentifier,
+	PropertyObjectName,
+	PropertyObjectType,
+}
+
+// Intrinsic reporting properties, optional for most object types
+var intrinsicReportingProperties = []PropertyIdentifier{
+	PropertyTimeDelay,
+	PropertyNotificationClass,
+	PropertyEventEnable,
+	PropertyAckedTransitions,
+	PropertyNotifyType,
+	PropertyEventTimeStamps,
+}
+
+// objectTypeProperties lists the standard properties of the object types
+// this package knows about, besides the common ones
+var objectTypeProperties = map[ObjectType]ObjectProperties{
+	ObjectTypeAnalogInput: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService, PropertyUnits},
+		Optional: withReporting(PropertyDescription, PropertyDeviceType, PropertyReliability, PropertyUpdateInterval,
+			PropertyMinPresValue, PropertyMaxPresValue, PropertyResolution, PropertyCOVIncrement,
+			PropertyHighLimit, PropertyLowLimit, PropertyDeadband, PropertyLimitEnable, PropertyProfileName),
+	},
+	ObjectTypeAnalogOutput: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService, PropertyUnits,
+			PropertyPriorityArray, PropertyRelinquishDefault},
+		Optional: withReporting(PropertyDescription, PropertyDeviceType, PropertyReliability,
+			PropertyMinPresValue, PropertyMaxPresValue, PropertyResolution, PropertyCOVIncrement,
+			PropertyHighLimit, PropertyLowLimit, PropertyDeadband, PropertyLimitEnable, PropertyProfileName),
+	},
+	ObjectTypeAnalogValue: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService, PropertyUnits},
+		Optional: withReporting(PropertyDescription, PropertyReliability, PropertyPriorityArray, PropertyRelinquishDefault,
+			PropertyMinPresValue, PropertyMaxPresValue, PropertyResolution, PropertyCOVIncrement,
+			PropertyHighLimit, PropertyLowLimit, PropertyDeadband, PropertyLimitEnable, PropertyProfileName),
+	},
+	ObjectTypeBinaryInput: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService, PropertyPolarity},
+		Optional: withReporting(PropertyDescription, PropertyDeviceType, PropertyReliability, PropertyInactiveText, PropertyActiveText,
+			PropertyChangeOfStateTime, PropertyChangeOfStateCount, PropertyTimeOfStateCountReset,
+			PropertyElapsedActiveTime, PropertyTimeOfActiveTimeReset, PropertyAlarmValue, PropertyProfileName),
+	},
+	ObjectTypeBinaryOutput: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService, PropertyPolarity,
+			PropertyPriorityArray, PropertyRelinquishDefault},
+		Optional: withReporting(PropertyDescription, PropertyDeviceType, PropertyReliability, PropertyInactiveText, PropertyActiveText,
+			PropertyChangeOfStateTime, PropertyChangeOfStateCount, PropertyTimeOfStateCountReset,
+			PropertyElapsedActiveTime, PropertyTimeOfActiveTimeReset, PropertyMinimumOffTime, PropertyMinimumOnTime,
+			PropertyFeedbackValue, PropertyProfileName),
+	},
+	ObjectTypeBinaryValue: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService},
+		Optional: withReporting(PropertyDescription, PropertyReliability, PropertyInactiveText, PropertyActiveText,
+			PropertyChangeOfStateTime, PropertyChangeOfStateCount, PropertyTimeOfStateCountReset,
+			PropertyElapsedActiveTime, PropertyTimeOfActiveTimeReset, PropertyMinimumOffTime, PropertyMinimumOnTime,
+			PropertyPriorityArray, PropertyRelinquishDefault, PropertyAlarmValue, PropertyProfileName),
+	},
+	ObjectTypeMultiStateInput: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService, PropertyNumberOfStates},
+		Optional: withReporting(PropertyDescription, PropertyDeviceType, PropertyReliability, PropertyStateText,
+			PropertyAlarmValues, PropertyFaultValues, PropertyProfileName),
+	},
+	ObjectTypeMultiStateOutput: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService, PropertyNumberOfStates,
+			PropertyPriorityArray, PropertyRelinquishDefault},
+		Optional: withReporting(PropertyDescription, PropertyDeviceType, PropertyReliability, PropertyStateText,
+			PropertyFeedbackValue, PropertyProfileName),
+	},
+	ObjectTypeMultiStateValue: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService, PropertyNumberOfStates},
+		Optional: withReporting(PropertyDescription, PropertyReliability, PropertyStateText, PropertyPriorityArray, PropertyRelinquishDefault,
+			PropertyAlarmValues, PropertyFaultValues, PropertyProfileName),
+	},
+	ObjectTypeCalendar: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyDateList},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyProfileName},
+	},
+	ObjectTypeCommand: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyInProcess, PropertyAllWritesSuccessful, PropertyAction},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyActionText, PropertyProfileName},
+	},
+	ObjectTypeDevice: {
+		Required: []PropertyIdentifier{PropertySystemStatus, PropertyVendorName, PropertyVendorIdentifier, PropertyModelName,
+			PropertyFirmwareRevision, PropertyApplicationSoftwareVersion, PropertyProtocolVersion, PropertyProtocolRevision,
+			PropertyProtocolServicesSupported, PropertyProtocolObjectTypesSupported, PropertyObjectList,
+			PropertyMaxApduLengthAccepted, PropertySegmentationSupported, PropertyApduTimeout, PropertyNumberOfApduRetries,
+			PropertyDeviceAddressBinding, PropertyDatabaseRevision},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyLocation, PropertyVtClassesSupported, PropertyActiveVtSessions,
+			PropertyLocalTime, PropertyLocalDate, PropertyUtcOffset, PropertyDaylightSavingsStatus, PropertyApduSegmentTimeout,
+			PropertyMaxSegmentsAccepted, PropertyTimeSynchronizationRecipients, PropertyMaxMaster, PropertyMaxInfoFrames,
+			PropertyConfigurationFiles, PropertyLastRestoreTime, PropertyBackupFailureTimeout, PropertyBackupPreparationTime,
+			PropertyRestorePreparationTime, PropertyRestoreCompletionTime, PropertyBackupAndRestoreState,
+			PropertyActiveCOVSubscriptions, PropertyStructuredObjectList, PropertyProfileName},
+	},
+	ObjectTypeEventEnrollment: {
+		Required: []PropertyIdentifier{PropertyEventType, PropertyNotifyType, PropertyEventParameters, PropertyObjectPropertyReference,
+			PropertyEventState, PropertyEventEnable, PropertyAckedTransitions, PropertyNotificationClass, PropertyEventTimeStamps,
+			PropertyStatusFlags, PropertyReliability},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyProfileName},
+	},
+	ObjectTypeFile: {
+		Required: []PropertyIdentifier{PropertyFileType, PropertyFileSize, PropertyModificationDate, PropertyArchive,
+			PropertyReadOnly, PropertyFileAccessMethod},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyProfileName},
+	},
+	ObjectTypeGroup: {
+		Required: []PropertyIdentifier{PropertyListOfGroupMembers, PropertyPresentValue},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyProfileName},
+	},
+	ObjectTypeLoop: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService,
+			PropertyOutputUnits, PropertyManipulatedVariableReference, PropertyControlledVariableReference,
+			PropertyControlledVariableValue, PropertyControlledVariableUnits, PropertySetpointReference, PropertySetpoint,
+			PropertyAction, PropertyPriorityForWriting},
+		Optional: withReporting(PropertyDescription, PropertyReliability, PropertyUpdateInterval,
+			PropertyProportionalConstant, PropertyProportionalConstantUnits, PropertyIntegralConstant, PropertyIntegralConstantUnits,
+			PropertyDerivativeConstant, PropertyDerivativeConstantUnits, PropertyBias, PropertyMaximumOutput, PropertyMinimumOutput,
+			PropertyCOVIncrement, PropertyErrorLimit, PropertyProfileName),
+	},
+	ObjectTypeNotificationClass: {
+		Required: []PropertyIdentifier{PropertyNotificationClass, PropertyPriority, PropertyAckRequired, PropertyRecipientList},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyProfileName},
+	},
+	ObjectTypeProgram: {
+		Required: []PropertyIdentifier{PropertyProgramState, PropertyProgramChange, PropertyStatusFlags, PropertyOutOfService},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyReasonForHalt, PropertyDescriptionOfHalt, PropertyProgramLocation,
+			PropertyInstanceOf, PropertyReliability, PropertyProfileName},
+	},
+	ObjectTypeSchedule: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyEffectivePeriod, PropertyListOfObjectPropertyReferences,
+			PropertyPriorityForWriting, PropertyStatusFlags, PropertyReliability, PropertyOutOfService},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyWeeklySchedule, PropertyExceptionSchedule, PropertyProfileName},
+	},
+	ObjectTypeAveraging: {
+		Required: []PropertyIdentifier{PropertyMinimumValue, PropertyAverageValue, PropertyMaximumValue, PropertyAttemptedSamples,
+			PropertyValidSamples, PropertyObjectPropertyReference, PropertyWindowInterval, PropertyWindowSamples},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyMinimumValueTimestamp, PropertyVarianceValue,
+			PropertyMaximumValueTimestamp, PropertyProfileName},
+	},
+	ObjectTypeTrendLog: {
+		Required: []PropertyIdentifier{PropertyStatusFlags, PropertyEventState, PropertyLogEnable, PropertyStopWhenFull,
+			PropertyBufferSize, PropertyLogBuffer, PropertyRecordCount, PropertyTotalRecordCount},
+		Optional: withReporting(PropertyDescription, PropertyReliability, PropertyStartTime, PropertyStopTime,
+			PropertyLogDeviceObjectProperty, PropertyLogInterval, PropertyCOVResubscriptionInterval, PropertyClientCovIncrement,
+			PropertyNotificationThreshold, PropertyRecordsSinceNotification, PropertyProfileName),
+	},
+	ObjectTypeEventLog: {
+		Required: []PropertyIdentifier{PropertyStatusFlags, PropertyEventState, PropertyLogEnable, PropertyStopWhenFull,
+			PropertyBufferSize, PropertyLogBuffer, PropertyRecordCount, PropertyTotalRecordCount},
+		Optional: withReporting(PropertyDescription, PropertyReliability, PropertyStartTime, PropertyStopTime,
+			PropertyNotificationThreshold, PropertyRecordsSinceNotification, PropertyProfileName),
+	},
+	ObjectTypeTrendLogMultiple: {
+		Required: []PropertyIdentifier{PropertyStatusFlags, PropertyEventState, PropertyLogEnable, PropertyLogDeviceObjectProperty,
+			PropertyLogInterval, PropertyStopWhenFull, PropertyBufferSize, PropertyLogBuffer, PropertyRecordCount,
+			PropertyTotalRecordCount},
+		Optional: withReporting(PropertyDescription, PropertyReliability, PropertyStartTime, PropertyStopTime,
+			PropertyNotificationThreshold, PropertyRecordsSinceNotification, PropertyProfileName),
+	},
+	ObjectTypeAccumulator: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyStatusFlags, PropertyEventState, PropertyOutOfService,
+			PropertyUnits, PropertyMaxPresValue},
+		Optional: withReporting(PropertyDescription, PropertyDeviceType, PropertyReliability, PropertyProfileName),
+	},
+	ObjectTypeGlobalGroup: {
+		Required: []PropertyIdentifier{PropertyGroupMembers, PropertyPresentValue, PropertyStatusFlags, PropertyEventState,
+			PropertyOutOfService},
+		Optional: withReporting(PropertyDescription, PropertyGroupMemberNames, PropertyReliability, PropertyUpdateInterval,
+			PropertyCOVResubscriptionInterval, PropertyClientCovIncrement, PropertyProfileName),
+	},
+	ObjectTypeStructuredView: {
+		Required: []PropertyIdentifier{PropertyNodeType, PropertySubordinateList},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyNodeSubtype, PropertySubordinateAnnotations, PropertyProfileName},
+	},
+	ObjectTypeLightingOutput: {
+		Required: []PropertyIdentifier{PropertyPresentValue, PropertyTrackingValue, PropertyLightingCommand, PropertyInProgress,
+			PropertyStatusFlags, PropertyOutOfService, PropertyBlinkWarnEnable, PropertyEgressTime, PropertyEgressActive,
+			PropertyDefaultFadeTime, PropertyDefaultRampRate, PropertyDefaultStepIncrement, PropertyPriorityArray,
+			PropertyRelinquishDefault, PropertyLightingCommandDefaultPriority},
+		Optional: []PropertyIdentifier{PropertyDescription, PropertyReliability, PropertyTransition, PropertyMinActualValue,
+			PropertyMaxActualValue, PropertyPower, PropertyInstantaneousPower, PropertyCOVIncrement, PropertyProfileName},
+	},
+}
+
+// genericObjectProperties are read from the objects of types missing from
+// the table, such as proprietary ones
+var genericObjectProperties = ObjectProperties{
+	Optional: []PropertyIdentifier{
+		PropertyPresentValue,
+		PropertyDescription,
+		PropertyStatusFlags,
+		PropertyEventState,
+		PropertyReliability,
+		PropertyOutOfService,
+		PropertyUnits,
+		PropertyPriorityArray,
+		PropertyRelinquishDefault,
+		PropertyProfileName,
+	},
+}
+
+// withReporting appends the intrinsic reporting properties to optional
+func withReporting(optional ...PropertyIdentifier) []PropertyIdentifier {
+	return append(optional, intrinsicReportingProperties...)
+}
+
+// StandardProperties returns the standard properties of an object type,
+// with the properties every object has first among the required ones. For
+// a type missing from the table, known reports false and the properties
+// are the usual ones of a point, as optional.
+func StandardProperties(objectType ObjectType) (props ObjectProperties, known bool) {
+	typeProps, known := objectTypeProperties[objectType]
+	if !known {
+		typeProps = genericObjectProperties
+	}
+	props.Required = append(append([]PropertyIdentifier{}, commonObjectProperties...), typeProps.Required...)
+	props.Optional = append([]PropertyIdentifier{}, typeProps.Optional...)
+	return props, known
+}
+
+// propertyDatatype is the datatype of the numeric and boolean properties
+// whose encoding CoerceValue picks
+type propertyDatatype uint8
+
+const (
+	datatypeReal propertyDatatype = iota + 1
+	datatypeEnumerated
+	datatypeBoolean
+	datatypeUnsigned
+	datatypeSigned
+)
+
+// propertyDatatypes are the datatypes of the properties with a single
+// numeric or boolean datatype, whatever the object type
+var propertyDatatypes = map[PropertyIdentifier]propertyDatatype{
+	PropertyCOVIncrement:            datatypeReal,
+	PropertyHighLimit:               datatypeReal,
+	PropertyLowLimit:                datatypeReal,
+	PropertyDeadband:                datatypeReal,
+	PropertyMinPresValue:            datatypeReal,
+	PropertyMaxPresValue:            datatypeReal,
+	PropertyResolution:              datatypeReal,
+	PropertyProportionalConstant:    datatypeReal,
+	PropertyIntegralConstant:        datatypeReal,
+	PropertyDerivativeConstant:      datatypeReal,
+	PropertyBias:                    datatypeReal,
+	PropertyMaximumOutput:           datatypeReal,
+	PropertyMinimumOutput:           datatypeReal,
+	PropertyErrorLimit:              datatypeReal,
+	PropertySetpoint:                datatypeReal,
+	PropertyControlledVariableValue: datatypeReal,
+	PropertyDefaultRampRate:         datatypeReal,
+	PropertyDefaultStepIncrement:    datatypeReal,
+	PropertyTrackingValue:           datatypeReal,
+	PropertyPower:                   datatypeReal,
+	PropertyInstantaneousPower:      datatypeReal,
+	PropertyMinActualValue:          datatypeReal,
+	PropertyMaxActualValue:          datatypeReal,
+	PropertyAverageValue:            datatypeReal,
+	PropertyMinimumValue:            datatypeReal,
+	PropertyMaximumValue:            datatypeReal,
+	PropertyVarianceValue:           datatypeReal,
+
+	PropertyUnits:                     datatypeEnumerated,
+	PropertyOutputUnits:               datatypeEnumerated,
+	PropertyControlledVariableUnits:   datatypeEnumerated,
+	PropertyProportionalConstantUnits: datatypeEnumerated,
+	PropertyIntegralConstantUnits:     datatypeEnumerated,
+	PropertyDerivativeConstantUnits:   datatypeEnumerated,
+	PropertyEventState:                datatypeEnumerated,
+	PropertyReliability:               datatypeEnumerated,
+	PropertyPolarity:                  datatypeEnumerated,
+	PropertyNotifyType:                datatypeEnumerated,
+	PropertyEventType:                 datatypeEnumerated,
+	PropertyObjectType:                datatypeEnumerated,
+	PropertySystemStatus:              datatypeEnumerated,
+	PropertySegmentationSupported:     datatypeEnumerated,
+	PropertyProgramState:              datatypeEnumerated,
+	PropertyProgramChange:             datatypeEnumerated,
+	PropertyReasonForHalt:             datatypeEnumerated,
+	PropertyFileAccessMethod:          datatypeEnumerated,
+	PropertyNodeType:                  datatypeEnumerated,
+	PropertyBackupAndRestoreState:     datatypeEnumerated,
+	PropertyMode:                      datatypeEnumerated,
+	PropertySilenced:                  datatypeEnumerated,
+	PropertyOperationExpected:         datatypeEnumerated,
+	PropertyMaintenanceRequired:       datatypeEnumerated,
+	PropertyTransition:                datatypeEnumerated,
+	PropertyInProgress:                datatypeEnumerated,
+
+	PropertyOutOfService:          datatypeBoolean,
+	PropertyArchive:               datatypeBoolean,
+	PropertyReadOnly:              datatypeBoolean,
+	PropertyLogEnable:             datatypeBoolean,
+	PropertyStopWhenFull:          datatypeBoolean,
+	PropertyBlinkWarnEnable:       datatypeBoolean,
+	PropertyEgressActive:          datatypeBoolean,
+	PropertyInProcess:             datatypeBoolean,
+	PropertyAllWritesSuccessful:   datatypeBoolean,
+	PropertyDaylightSavingsStatus: datatypeBoolean,
+
+	PropertyUpdateInterval:                 datatypeUnsigned,
+	PropertyTimeDelay:                      datatypeUnsigned,
+	PropertyNotificationClass:              datatypeUnsigned,
+	PropertyNumberOfStates:                 datatypeUnsigned,
+	PropertyMinimumOffTime:                 datatypeUnsigned,
+	PropertyMinimumOnTime:                  datatypeUnsigned,
+	PropertyApduTimeout:                    datatypeUnsigned,
+	PropertyApduSegmentTimeout:             datatypeUnsigned,
+	PropertyNumberOfApduRetries:            datatypeUnsigned,
+	PropertyMaxMaster:                      datatypeUnsigned,
+	PropertyMaxInfoFrames:                  datatypeUnsigned,
+	PropertyMaxApduLengthAccepted:          datatypeUnsigned,
+	PropertyMaxSegmentsAccepted:            datatypeUnsigned,
+	PropertyDatabaseRevision:               datatypeUnsigned,
+	PropertyVendorIdentifier:               datatypeUnsigned,
+	PropertyProtocolVersion:                datatypeUnsigned,
+	PropertyProtocolRevision:               datatypeUnsigned,
+	PropertyBufferSize:                     datatypeUnsigned,
+	PropertyRecordCount:                    datatypeUnsigned,
+	PropertyTotalRecordCount:               datatypeUnsigned,
+	PropertyLogInterval:                    datatypeUnsigned,
+	PropertyCOVResubscriptionInterval:      datatypeUnsigned,
+	PropertyNotificationThreshold:          datatypeUnsigned,
+	PropertyRecordsSinceNotification:       datatypeUnsigned,
+	PropertyWindowInterval:                 datatypeUnsigned,
+	PropertyWindowSamples:                  datatypeUnsigned,
+	PropertyAttemptedSamples:               datatypeUnsigned,
+	PropertyValidSamples:                   datatypeUnsigned,
+	PropertyPriorityForWriting:             datatypeUnsigned,
+	PropertyChangeOfStateCount:             datatypeUnsigned,
+	PropertyElapsedActiveTime:              datatypeUnsigned,
+	PropertyFileSize:                       datatypeUnsigned,
+	PropertyEgressTime:                     datatypeUnsigned,
+	PropertyDefaultFadeTime:                datatypeUnsigned,
+	PropertyLightingCommandDefaultPriority: datatypeUnsigned,
+	PropertyBackupFailureTimeout:           datatypeUnsigned,
+	PropertyBackupPreparationTime:          datatypeUnsigned,
+	PropertyRestorePreparationTime:         datatypeUnsigned,
+	PropertyRestoreCompletionTime:          datatypeUnsigned,
+	PropertyProcessIdentifier:              datatypeUnsigned,
+
+	PropertyUtcOffset: datatypeSigned,
+}
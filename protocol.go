@@ -186,6 +186,10 @@ type APDU struct {
 	WindowSize   uint8
 	Service      uint8
 	Data         []byte
+
+	// Server is the SRV bit of an Abort: set when the server of the
+	// transaction sent it, clear when the client did
+	Server bool
 }
 
 // EncodeConfirmedRequest encodes a confirmed service request APDU
@@ -346,10 +350,12 @@ func decodeRejectAPDU(data []byte) (*APDU, error) {
 		return nil, ErrInvalidAPDU
 	}
 
+	// A Reject has no SRV bit: only the server of a transaction sends one
 	return &APDU{
 		Type:     PDUTypeReject,
 		InvokeID: data[1],
 		Service:  data[2], // Reject reason is in service field
+		Server:   true,
 	}, nil
 }
 
@@ -362,6 +368,7 @@ func decodeAbortAPDU(data []byte) (*APDU, error) {
 		Type:     PDUTypeAbort,
 		InvokeID: data[1],
 		Service:  data[2], // Abort reason is in service field
+		Server:   data[0]&0x01 != 0,
 	}, nil
 }
 
@@ -369,95 +376,48 @@ func decodeAbortAPDU(data []byte) (*APDU, error) {
 
 // EncodeTag encodes a BACnet tag
 func EncodeTag(tagNum uint8, class TagClass, length int) []byte {
-	if length < 5 && tagNum < 15 {
-		// Short form
-		tag := (tagNum << 4) | (uint8(class) << 3) | uint8(length)
-		return []byte{tag}
-	}
-
-	buf := make([]byte, 0, 6)
-
-	// Extended tag number
-	if tagNum >= 15 {
-		buf = append(buf, 0xF0|(uint8(class)<<3))
-		buf = append(buf, tagNum)
-	} else {
-		buf = append(buf, (tagNum<<4)|(uint8(class)<<3)|0x05)
-	}
-
-	// Extended length
-	if length >= 5 {
-		if length < 254 {
-			buf = append(buf, byte(length))
-		} else if length < 65536 {
-			buf = append(buf, 254)
-			buf = append(buf, byte(length>>8), byte(length))
-		} else {
-			buf = append(buf, 255)
-			buf = append(buf, byte(length>>24), byte(length>>16), byte(length>>8), byte(length))
-		}
-	}
-
-	return buf
+	return appendTag(make([]byte, 0, 7), tagNum, class, length)
 }
 
 // EncodeContextTag encodes a context-specific tag
 func EncodeContextTag(tagNum uint8, data []byte) []byte {
-	tag := EncodeTag(tagNum, TagClassContext, len(data))
-	return append(tag, data...)
+	e := NewEncoder(make([]byte, 0, 7+len(data)))
+	e.ContextOctetString(tagNum, data)
+	return e.Bytes()
 }
 
 // EncodeOpeningTag encodes an opening tag for constructed data
 func EncodeOpeningTag(tagNum uint8) []byte {
-	if tagNum < 15 {
-		return []byte{(tagNum << 4) | 0x0E}
-	}
-	return []byte{0xFE, tagNum}
+	return appendOpeningTag(nil, tagNum)
 }
 
 // EncodeClosingTag encodes a closing tag for constructed data
 func EncodeClosingTag(tagNum uint8) []byte {
-	if tagNum < 15 {
-		return []byte{(tagNum << 4) | 0x0F}
-	}
-	return []byte{0xFF, tagNum}
+	return appendClosingTag(nil, tagNum)
 }
 
 // EncodeUnsigned encodes an unsigned integer
 func EncodeUnsigned(value uint32) []byte {
-	if value < 0x100 {
-		return []byte{byte(value)}
-	} else if value < 0x10000 {
-		return []byte{byte(value >> 8), byte(value)}
-	} else if value < 0x1000000 {
-		return []byte{byte(value >> 16), byte(value >> 8), byte(value)}
-	}
-	return []byte{byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value)}
+	return appendUnsigned(make([]byte, 0, 4), value)
 }
 
 // EncodeUnsignedTag encodes an unsigned integer with application tag
 func EncodeUnsignedTag(value uint32) []byte {
-	data := EncodeUnsigned(value)
-	tag := EncodeTag(uint8(TagUnsignedInt), TagClassApplication, len(data))
-	return append(tag, data...)
+	e := NewEncoder(make([]byte, 0, 5))
+	e.Unsigned(value)
+	return e.Bytes()
 }
 
 // EncodeContextUnsigned encodes an unsigned integer with context tag
 func EncodeContextUnsigned(tagNum uint8, value uint32) []byte {
-	data := EncodeUnsigned(value)
-	return EncodeContextTag(tagNum, data)
+	e := NewEncoder(make([]byte, 0, 6))
+	e.ContextUnsigned(tagNum, value)
+	return e.Bytes()
 }
 
 // EncodeSigned encodes a signed integer
 func EncodeSigned(value int32) []byte {
-	if value >= -128 && value < 128 {
-		return []byte{byte(value)}
-	} else if value >= -32768 && value < 32768 {
-		return []byte{byte(value >> 8), byte(value)}
-	} else if value >= -8388608 && value < 8388608 {
-		return []byte{byte(value >> 16), byte(value >> 8), byte(value)}
-	}
-	return []byte{byte(value >> 24), byte(value >> 16), byte(value >> 8), byte(value)}
+	return appendSigned(make([]byte, 0, 4), value)
 }
 
 // EncodeReal encodes a float32
@@ -468,9 +428,9 @@ func EncodeReal(value float32) []byte {
 
 // EncodeRealTag encodes a float32 with application tag
 func EncodeRealTag(value float32) []byte {
-	data := EncodeReal(value)
-	tag := EncodeTag(uint8(TagReal), TagClassApplication, 4)
-	return append(tag, data...)
+	e := NewEncoder(make([]byte, 0, 5))
+	e.Real(value)
+	return e.Bytes()
 }
 
 // EncodeDouble encodes a float64
@@ -483,19 +443,16 @@ func EncodeDouble(value float64) []byte {
 
 // EncodeBooleanTag encodes a boolean with application tag
 func EncodeBooleanTag(value bool) []byte {
-	if value {
-		return []byte{0x11} // Boolean true, length 1, value 1
-	}
-	return []byte{0x10} // Boolean false, length 1, value 0
+	e := NewEncoder(make([]byte, 0, 1))
+	e.Boolean(value)
+	return e.Bytes()
 }
 
 // EncodeContextBoolean encodes a boolean with context tag
 func EncodeContextBoolean(tagNum uint8, value bool) []byte {
-	v := byte(0)
-	if value {
-		v = 1
-	}
-	return EncodeContextTag(tagNum, []byte{v})
+	e := NewEncoder(make([]byte, 0, 3))
+	e.ContextBoolean(tagNum, value)
+	return e.Bytes()
 }
 
 // EncodeEnumerated encodes an enumerated value
@@ -505,15 +462,16 @@ func EncodeEnumerated(value uint32) []byte {
 
 // EncodeEnumeratedTag encodes an enumerated value with application tag
 func EncodeEnumeratedTag(value uint32) []byte {
-	data := EncodeEnumerated(value)
-	tag := EncodeTag(uint8(TagEnumerated), TagClassApplication, len(data))
-	return append(tag, data...)
+	e := NewEncoder(make([]byte, 0, 5))
+	e.Enumerated(value)
+	return e.Bytes()
 }
 
 // EncodeContextEnumerated encodes an enumerated value with context tag
 func EncodeContextEnumerated(tagNum uint8, value uint32) []byte {
-	data := EncodeEnumerated(value)
-	return EncodeContextTag(tagNum, data)
+	e := NewEncoder(make([]byte, 0, 6))
+	e.ContextEnumerated(tagNum, value)
+	return e.Bytes()
 }
 
 // EncodeObjectIdentifier encodes an object identifier
@@ -524,15 +482,16 @@ func EncodeObjectIdentifier(oid ObjectIdentifier) []byte {
 
 // EncodeObjectIdentifierTag encodes an object identifier with application tag
 func EncodeObjectIdentifierTag(oid ObjectIdentifier) []byte {
-	data := EncodeObjectIdentifier(oid)
-	tag := EncodeTag(uint8(TagObjectID), TagClassApplication, 4)
-	return append(tag, data...)
+	e := NewEncoder(make([]byte, 0, 5))
+	e.ObjectIdentifier(oid)
+	return e.Bytes()
 }
 
 // EncodeContextObjectIdentifier encodes an object identifier with context tag
 func EncodeContextObjectIdentifier(tagNum uint8, oid ObjectIdentifier) []byte {
-	data := EncodeObjectIdentifier(oid)
-	return EncodeContextTag(tagNum, data)
+	e := NewEncoder(make([]byte, 0, 6))
+	e.ContextObjectIdentifier(tagNum, oid)
+	return e.Bytes()
 }
 
 // EncodeCharacterString encodes a character string (UTF-8)
@@ -546,9 +505,94 @@ func EncodeCharacterString(s string) []byte {
 
 // EncodeCharacterStringTag encodes a character string with application tag
 func EncodeCharacterStringTag(s string) []byte {
-	data := EncodeCharacterString(s)
-	tag := EncodeTag(uint8(TagCharacterString), TagClassApplication, len(data))
-	return append(tag, data...)
+	e := NewEncoder(make([]byte, 0, 7+len(s)))
+	e.CharacterString(s)
+	return e.Bytes()
+}
+
+// EncodeBitString encodes a bit string (unused-bits octet followed by the bits)
+func EncodeBitString(b BitString) []byte {
+	return appendBitString(make([]byte, 0, bitStringLen(b)), b)
+}
+
+// EncodeBitStringTag encodes a bit string with application tag
+func EncodeBitStringTag(b BitString) []byte {
+	e := NewEncoder(make([]byte, 0, 2+bitStringLen(b)))
+	e.BitString(b)
+	return e.Bytes()
+}
+
+// EncodeSimpleAck encodes a SimpleACK APDU
+func EncodeSimpleAck(invokeID uint8, service ConfirmedServiceChoice) []byte {
+	return []byte{byte(PDUTypeSimpleAck), invokeID, byte(service)}
+}
+
+// EncodeComplexAck encodes an unsegmented ComplexACK APDU
+func EncodeComplexAck(invokeID uint8, service ConfirmedServiceChoice, data []byte) []byte {
+	buf := make([]byte, 0, 3+len(data))
+	buf = append(buf, byte(PDUTypeComplexAck), invokeID, byte(service))
+	buf = append(buf, data...)
+	return buf
+}
+
+// EncodeErrorAPDU encodes an Error APDU
+func EncodeErrorAPDU(invokeID uint8, service ConfirmedServiceChoice, class ErrorClass, code ErrorCode) []byte {
+	buf := []byte{byte(PDUTypeError), invokeID, byte(service)}
+	buf = append(buf, EncodeEnumeratedTag(uint32(class))...)
+	buf = append(buf, EncodeEnumeratedTag(uint32(code))...)
+	return buf
+}
+
+// EncodeRejectAPDU encodes a Reject APDU
+func EncodeRejectAPDU(invokeID uint8, reason RejectReason) []byte {
+	return []byte{byte(PDUTypeReject), invokeID, byte(reason)}
+}
+
+// EncodeAbortAPDU encodes an Abort APDU
+func EncodeAbortAPDU(invokeID uint8, server bool, reason AbortReason) []byte {
+	pduType := byte(PDUTypeAbort)
+	if server {
+		pduType |= 0x01
+	}
+	return []byte{pduType, invokeID, byte(reason)}
+}
+
+// DecodeMaxAPDU converts the max-APDU-length-accepted field of a confirmed
+// request to a length in octets
+func DecodeMaxAPDU(code uint8) int {
+	switch code & 0x0F {
+	case 0:
+		return 50
+	case 1:
+		return 128
+	case 2:
+		return 206
+	case 3:
+		return 480
+	case 4:
+		return 1024
+	default:
+		return MaxAPDULength
+	}
+}
+
+// EncodeMaxAPDU converts a length in octets to the max-APDU-length-accepted
+// field of a confirmed request: the largest standard length not above it
+func EncodeMaxAPDU(length int) uint8 {
+	switch {
+	case length >= MaxAPDULength:
+		return 5
+	case length >= 1024:
+		return 4
+	case length >= 480:
+		return 3
+	case length >= 206:
+		return 2
+	case length >= 128:
+		return 1
+	default:
+		return 0
+	}
 }
 
 // DecodeTagNumber decodes a tag from data
@@ -606,6 +650,14 @@ func DecodeTagNumber(data []byte) (tagNum uint8, class TagClass, length int, hea
 		}
 	}
 
+	// The content must be within data; an application boolean carries its
+	// value in the length field instead
+	if !(class == TagClassApplication && tagNum == uint8(TagBoolean)) {
+		if length < 0 || length > len(data)-headerLen {
+			return 0, 0, 0, 0, ErrInvalidAPDU
+		}
+	}
+
 	return tagNum, class, length, headerLen, nil
 }
 
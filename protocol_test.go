@@ -0,0 +1,317 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"bytes"
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestDecodeNPDU(t *testing.T) {
+	tests := []struct {
+		name   string
+		data   []byte
+		err    bool
+		offset int
+		check  func(t *testing.T, npdu *NPDU)
+	}{
+		{name: "empty", data: nil, err: true},
+		{name: "truncated control", data: []byte{0x01}, err: true},
+		{name: "bad version", data: []byte{0x02, 0x00}, err: true},
+		{name: "local", data: []byte{0x01, 0x04, 0xAA}, offset: 2},
+		{name: "truncated destination", data: []byte{0x01, 0x20, 0x00}, err: true},
+		{name: "destination address overlong", data: []byte{0x01, 0x20, 0x00, 0x05, 0x06, 0x01, 0x02}, err: true},
+		{name: "destination without hop count", data: []byte{0x01, 0x20, 0x00, 0x05, 0x01, 0x07}, err: true},
+		{name: "destination address length 255", data: []byte{0x01, 0x20, 0xFF, 0xFF, 0xFF, 0x01}, err: true},
+		{
+			name:   "destination",
+			data:   []byte{0x01, 0x20, 0x00, 0x05, 0x01, 0x07, 0xFF, 0x10},
+			offset: 7,
+			check: func(t *testing.T, npdu *NPDU) {
+				if npdu.DestNet != 5 || !bytes.Equal(npdu.DestAddr, []byte{0x07}) || npdu.DestHopCount != 0xFF {
+					t.Errorf("destination = %d %x %d", npdu.DestNet, npdu.DestAddr, npdu.DestHopCount)
+				}
+			},
+		},
+		{
+			name:   "broadcast destination",
+			data:   []byte{0x01, 0x20, 0xFF, 0xFF, 0x00, 0xFF},
+			offset: 6,
+			check: func(t *testing.T, npdu *NPDU) {
+				if npdu.DestNet != 0xFFFF || len(npdu.DestAddr) != 0 {
+					t.Errorf("destination = %d %x", npdu.DestNet, npdu.DestAddr)
+				}
+			},
+		},
+		{name: "truncated source", data: []byte{0x01, 0x08, 0x00, 0x05}, err: true},
+		{name: "source address overlong", data: []byte{0x01, 0x08, 0x00, 0x05, 0x02, 0x01}, err: true},
+		{
+			name:   "source",
+			data:   []byte{0x01, 0x08, 0x00, 0x05, 0x01, 0x09, 0xAA},
+			offset: 6,
+			check: func(t *testing.T, npdu *NPDU) {
+				if npdu.SrcNet != 5 || !bytes.Equal(npdu.SrcAddr, []byte{0x09}) {
+					t.Errorf("source = %d %x", npdu.SrcNet, npdu.SrcAddr)
+				}
+				if !bytes.Equal(npdu.Data, []byte{0xAA}) {
+					t.Errorf("data = %x", npdu.Data)
+				}
+			},
+		},
+		{name: "truncated network message", data: []byte{0x01, 0x80}, err: true},
+		{name: "truncated vendor message", data: []byte{0x01, 0x80, 0x80, 0x01}, err: true},
+		{
+			name:   "vendor message",
+			data:   []byte{0x01, 0x80, 0x80, 0x01, 0x04},
+			offset: 5,
+			check: func(t *testing.T, npdu *NPDU) {
+				if npdu.MessageType != 0x80 || npdu.VendorID != 0x0104 {
+					t.Errorf("message = %02x vendor %d", npdu.MessageType, npdu.VendorID)
+				}
+			},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			npdu, offset, err := DecodeNPDU(tt.data)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidNPDU) {
+					t.Fatalf("err = %v, want ErrInvalidNPDU", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if offset != tt.offset {
+				t.Errorf("offset = %d, want %d", offset, tt.offset)
+			}
+			if tt.check != nil {
+				tt.check(t, npdu)
+			}
+		})
+	}
+}
+
+func TestDecodeAPDU(t *testing.T) {
+	tests := []struct {
+		name string
+		data []byte
+		err  bool
+		want APDU
+	}{
+		{name: "empty", data: nil, err: true},
+		{name: "unknown type", data: []byte{0x80, 0x00, 0x00}, err: true},
+		{name: "truncated confirmed request", data: []byte{0x00, 0x05, 0x01}, err: true},
+		{name: "truncated segmented request", data: []byte{0x08, 0x05, 0x01, 0x0C, 0x00}, err: true},
+		{
+			name: "confirmed request",
+			data: []byte{0x00, 0x35, 0x07, 0x0C, 0xAA},
+			want: APDU{Type: PDUTypeConfirmedRequest, MaxSegments: 3, MaxAPDU: 5, InvokeID: 7, Service: 0x0C, Data: []byte{0xAA}},
+		},
+		{
+			name: "segmented confirmed request",
+			data: []byte{0x0C, 0x05, 0x07, 0x0C, 0x02, 0x04, 0xAA},
+			want: APDU{Type: PDUTypeConfirmedRequest, Segmented: true, MoreFollows: true, MaxAPDU: 5, InvokeID: 7,
+				Service: 0x0C, SequenceNum: 2, WindowSize: 4, Data: []byte{0xAA}},
+		},
+		{name: "truncated unconfirmed request", data: []byte{0x10}, err: true},
+		{
+			name: "unconfirmed request",
+			data: []byte{0x10, 0x08},
+			want: APDU{Type: PDUTypeUnconfirmedRequest, Service: 0x08, Data: []byte{}},
+		},
+		{name: "truncated simple ack", data: []byte{0x20, 0x01}, err: true},
+		{
+			name: "simple ack",
+			data: []byte{0x20, 0x01, 0x0F},
+			want: APDU{Type: PDUTypeSimpleAck, InvokeID: 1, Service: 0x0F},
+		},
+		{name: "truncated complex ack", data: []byte{0x30, 0x01}, err: true},
+		{name: "truncated segmented complex ack", data: []byte{0x38, 0x01, 0x0C, 0x00}, err: true},
+		{
+			name: "segmented complex ack",
+			data: []byte{0x3C, 0x01, 0x0C, 0x00, 0x02, 0xAA, 0xBB},
+			want: APDU{Type: PDUTypeComplexAck, Segmented: true, MoreFollows: true, InvokeID: 1, Service: 0x0C,
+				WindowSize: 2, Data: []byte{0xAA, 0xBB}},
+		},
+		{name: "truncated error", data: []byte{0x50, 0x01}, err: true},
+		{name: "truncated reject", data: []byte{0x60, 0x01}, err: true},
+		{
+			name: "reject",
+			data: []byte{0x60, 0x01, 0x09},
+			want: APDU{Type: PDUTypeReject, InvokeID: 1, Service: 0x09, Server: true},
+		},
+		{name: "truncated abort", data: []byte{0x71}, err: true},
+		{
+			name: "client abort",
+			data: []byte{0x70, 0x01, 0x04},
+			want: APDU{Type: PDUTypeAbort, InvokeID: 1, Service: 0x04},
+		},
+		{
+			name: "server abort",
+			data: []byte{0x71, 0x01, 0x04},
+			want: APDU{Type: PDUTypeAbort, InvokeID: 1, Service: 0x04, Server: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			apdu, err := DecodeAPDU(tt.data)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidAPDU) {
+					t.Fatalf("err = %v, want ErrInvalidAPDU", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			got, want := *apdu, tt.want
+			if !bytes.Equal(got.Data, want.Data) {
+				t.Errorf("data = %x, want %x", got.Data, want.Data)
+			}
+			got.Data, want.Data = nil, nil
+			if !reflect.DeepEqual(got, want) {
+				t.Errorf("APDU = %+v, want %+v", got, want)
+			}
+		})
+	}
+}
+
+func TestDecodeTagNumber(t *testing.T) {
+	tests := []struct {
+		name      string
+		data      []byte
+		err       bool
+		tagNum    uint8
+		class     TagClass
+		length    int
+		headerLen int
+	}{
+		{name: "empty", data: nil, err: true},
+		{name: "application unsigned", data: []byte{0x21, 0x05}, tagNum: 2, class: TagClassApplication, length: 1, headerLen: 1},
+		{name: "content overlong", data: []byte{0x22, 0x05}, err: true},
+		{name: "application boolean", data: []byte{0x11}, tagNum: 1, class: TagClassApplication, length: 1, headerLen: 1},
+		{name: "context", data: []byte{0x19, 0x55}, tagNum: 1, class: TagClassContext, length: 1, headerLen: 1},
+		{name: "opening", data: []byte{0x3E}, tagNum: 3, class: TagClassContext, length: -1, headerLen: 1},
+		{name: "closing", data: []byte{0x3F}, tagNum: 3, class: TagClassContext, length: -2, headerLen: 1},
+		{name: "extended tag number", data: []byte{0xF9, 0x20, 0x01}, tagNum: 0x20, class: TagClassContext, length: 1, headerLen: 2},
+		{name: "truncated extended tag number", data: []byte{0xF9}, err: true},
+		{name: "extended length", data: append([]byte{0x65, 0x06}, make([]byte, 6)...), tagNum: 6, class: TagClassApplication, length: 6, headerLen: 2},
+		{name: "truncated extended length", data: []byte{0x65}, err: true},
+		{name: "extended length overlong", data: []byte{0x65, 0x06, 0x00}, err: true},
+		{name: "16-bit length", data: append([]byte{0x65, 0xFE, 0x01, 0x00}, make([]byte, 256)...), tagNum: 6, class: TagClassApplication, length: 256, headerLen: 4},
+		{name: "truncated 16-bit length", data: []byte{0x65, 0xFE, 0x01}, err: true},
+		{name: "16-bit length overlong", data: []byte{0x65, 0xFE, 0x01, 0x00, 0x00}, err: true},
+		{name: "32-bit length", data: append([]byte{0x65, 0xFF, 0x00, 0x01, 0x00, 0x00}, make([]byte, 65536)...), tagNum: 6, class: TagClassApplication, length: 65536, headerLen: 6},
+		{name: "truncated 32-bit length", data: []byte{0x65, 0xFF, 0x00, 0x00, 0x01}, err: true},
+		{name: "32-bit length overlong", data: []byte{0x65, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0x00}, err: true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tagNum, class, length, headerLen, err := DecodeTagNumber(tt.data)
+			if tt.err {
+				if !errors.Is(err, ErrInvalidAPDU) {
+					t.Fatalf("err = %v, want ErrInvalidAPDU", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("err = %v", err)
+			}
+			if tagNum != tt.tagNum || class != tt.class || length != tt.length || headerLen != tt.headerLen {
+				t.Errorf("tag = %d %d %d %d, want %d %d %d %d",
+					tagNum, class, length, headerLen, tt.tagNum, tt.class, tt.length, tt.headerLen)
+			}
+		})
+	}
+}
+
+func FuzzDecodeNPDU(f *testing.F) {
+	f.Add([]byte{0x01, 0x00})
+	f.Add([]byte{0x01, 0x20, 0xFF, 0xFF, 0x00, 0xFF})
+	f.Add([]byte{0x01, 0x28, 0x00, 0x05, 0x01, 0x07, 0x00, 0x06, 0x01, 0x09, 0xFF})
+	f.Add([]byte{0x01, 0x80, 0x80, 0x01, 0x04})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		npdu, offset, err := DecodeNPDU(data)
+		if err != nil {
+			return
+		}
+		if offset < 2 || offset > len(data) {
+			t.Fatalf("offset %d out of %d bytes", offset, len(data))
+		}
+		if !bytes.Equal(npdu.Data, data[offset:]) {
+			t.Fatalf("data %x does not follow offset %d", npdu.Data, offset)
+		}
+	})
+}
+
+func FuzzDecodeAPDU(f *testing.F) {
+	f.Add(EncodeConfirmedRequest(1, ServiceReadProperty, []byte{0x0C, 0x00, 0x00, 0x00, 0x01, 0x19, 0x55}, 0, 5))
+	f.Add([]byte{0x0C, 0x05, 0x07, 0x0C, 0x02, 0x04, 0xAA})
+	f.Add(EncodeComplexAck(1, ServiceReadProperty, []byte{0x3E, 0x44, 0x41, 0xA8, 0x00, 0x00, 0x3F}))
+	f.Add(EncodeErrorAPDU(1, ServiceReadProperty, ErrorClassObject, 31))
+	f.Add(EncodeAbortAPDU(1, true, 4))
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		apdu, err := DecodeAPDU(data)
+		if err != nil {
+			return
+		}
+		if len(apdu.Data) > len(data) {
+			t.Fatalf("data of %d bytes from %d", len(apdu.Data), len(data))
+		}
+
+		// Service data of any APDU must decode without panicking
+		d := NewDecoder(apdu.Data)
+		for d.Err() == nil && d.Len() > 0 {
+			before := d.Len()
+			d.Value()
+			if d.Err() == nil && d.Len() >= before {
+				t.Fatalf("decoder made no progress at offset %d", d.Offset())
+			}
+		}
+	})
+}
+
+func FuzzDecodeTagNumber(f *testing.F) {
+	f.Add([]byte{0x21, 0x05})
+	f.Add([]byte{0x3E})
+	f.Add([]byte{0xF9, 0x20, 0x01})
+	f.Add([]byte{0x65, 0xFE, 0x00, 0x01, 0x00})
+	f.Add([]byte{0x65, 0xFF, 0x00, 0x00, 0x00, 0x01, 0x00})
+
+	f.Fuzz(func(t *testing.T, data []byte) {
+		tagNum, class, length, headerLen, err := DecodeTagNumber(data)
+		if err != nil {
+			return
+		}
+		if headerLen < 1 || headerLen > len(data) {
+			t.Fatalf("header of %d bytes from %d", headerLen, len(data))
+		}
+		if length < 0 || (class == TagClassApplication && tagNum == uint8(TagBoolean)) {
+			return
+		}
+		if headerLen+length > len(data) {
+			t.Fatalf("content of %d bytes after %d from %d", length, headerLen, len(data))
+		}
+	})
+}
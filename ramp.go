@@ -0,0 +1,96 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"math"
+)
+
+// RampTo moves an analog property towards target at rate units per second,
+// writing intermediate values every step interval until the target is
+// reached. A priority of 0 writes without a priority. The starting value is
+// read from the device before the first step.
+func (c *Client) RampTo(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, target, rate float32, priority uint8, opts ...RampOption) error {
+	options := defaultRampOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if rate <= 0 {
+		return fmt.Errorf("ramp rate must be positive")
+	}
+	if options.StepInterval <= 0 {
+		return fmt.Errorf("ramp step interval must be positive")
+	}
+
+	var writeOpts []WriteOption
+	if priority > 0 {
+		writeOpts = append(writeOpts, WithPriority(priority))
+	}
+
+	// Read starting value
+	value, err := c.ReadProperty(ctx, deviceID, objectID, options.Property)
+	if err != nil {
+		return fmt.Errorf("read starting value: %w", err)
+	}
+	start, ok := toFloat64(value)
+	if !ok {
+		return fmt.Errorf("cannot ramp non-numeric value of type %T", value)
+	}
+
+	current := start
+	step := float64(rate) * options.StepInterval.Seconds()
+
+	ticker := c.opts.clock.NewTicker(options.StepInterval)
+	defer ticker.Stop()
+
+	for current != float64(target) {
+		remaining := float64(target) - current
+		if math.Abs(remaining) <= step {
+			current = float64(target)
+		} else {
+			current += math.Copysign(step, remaining)
+		}
+
+		if err := c.WriteProperty(ctx, deviceID, objectID, options.Property, float32(current), writeOpts...); err != nil {
+			return fmt.Errorf("write ramp step %v: %w", float32(current), err)
+		}
+
+		if options.OnStep != nil {
+			options.OnStep(float32(current))
+		}
+
+		c.logger.Debug("ramp step",
+			slog.Uint64("device_id", uint64(deviceID)),
+			slog.String("object", objectID.String()),
+			slog.Float64("value", current),
+		)
+
+		if current == float64(target) {
+			break
+		}
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+	}
+
+	return nil
+}
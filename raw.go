@@ -0,0 +1,65 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"net"
+)
+
+// PacketHandler receives every APDU the client decodes, before the client
+// handles it. The NPDU and APDU share the receive buffer: data kept after
+// the handler returns must be copied. Handlers run on the receive path and
+// must not block.
+type PacketHandler func(npdu *NPDU, apdu *APDU, addr net.Addr)
+
+// OnPacket adds a handler of received APDUs, so that services the package
+// does not implement can be observed or answered with the raw senders
+func (c *Client) OnPacket(handler PacketHandler) {
+	c.packetHandlersMu.Lock()
+	defer c.packetHandlersMu.Unlock()
+	c.packetHandlers = append(c.packetHandlers, handler)
+}
+
+// dispatchPacket passes a received APDU to the packet handlers
+func (c *Client) dispatchPacket(npdu *NPDU, apdu *APDU, addr net.Addr) {
+	c.packetHandlersMu.RLock()
+	handlers := c.packetHandlers
+	c.packetHandlersMu.RUnlock()
+
+	for _, handler := range handlers {
+		handler(npdu, apdu, addr)
+	}
+}
+
+// DeviceAddress returns the data link address of a device, locating it with
+// Who-Is if it is not known yet
+func (c *Client) DeviceAddress(ctx context.Context, deviceID uint32) (net.Addr, error) {
+	return c.resolveDevice(ctx, deviceID)
+}
+
+// SendConfirmedRaw sends a confirmed request with an encoded service
+// payload and waits for the acknowledgement. The ack's Data holds the
+// encoded service ack of a ComplexAck; Error, Reject and Abort responses
+// are returned as errors. The request passes through the interceptors.
+func (c *Client) SendConfirmedRaw(ctx context.Context, addr net.Addr, service ConfirmedServiceChoice, payload []byte) (*APDU, error) {
+	return c.sendRequest(ctx, wildcardDeviceInstance, addr, service, payload)
+}
+
+// SendUnconfirmedRaw sends an unconfirmed request with an encoded service
+// payload. A nil addr broadcasts the request on the local network.
+func (c *Client) SendUnconfirmedRaw(ctx context.Context, addr net.Addr, service UnconfirmedServiceChoice, payload []byte) error {
+	return c.sendUnconfirmedRequest(ctx, addr, addr == nil, service, payload)
+}
@@ -0,0 +1,177 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"time"
+)
+
+// rangeKind selects the items of a ReadRange request
+type rangeKind uint8
+
+const (
+	rangeAll rangeKind = iota
+	rangeByPosition
+	rangeBySequence
+	rangeByTime
+)
+
+// Range selects the items read by ReadRange. A negative count reads the
+// items before the reference instead of after it.
+type Range struct {
+	kind     rangeKind
+	position uint32
+	time     time.Time
+	count    int32
+}
+
+// RangeAll reads the whole list, which may not fit in one response
+func RangeAll() Range {
+	return Range{kind: rangeAll}
+}
+
+// RangeByPosition reads count items from the 1-based index
+func RangeByPosition(index uint32, count int32) Range {
+	return Range{kind: rangeByPosition, position: index, count: count}
+}
+
+// RangeBySequence reads count log records from a sequence number
+func RangeBySequence(seq uint32, count int32) Range {
+	return Range{kind: rangeBySequence, position: seq, count: count}
+}
+
+// RangeByTime reads count log records from a timestamp. The time is sent
+// as a date and time in its own location.
+func RangeByTime(t time.Time, count int32) Range {
+	return Range{kind: rangeByTime, time: t, count: count}
+}
+
+// RangeResult is the acknowledgement of a ReadRange request
+type RangeResult struct {
+	FirstItem bool
+	LastItem  bool
+	MoreItems bool
+	ItemCount uint32
+
+	// ItemData is the encoding of the items read
+	ItemData []byte
+
+	// FirstSequenceNumber is the sequence number of the first log record,
+	// set when reading a log buffer by sequence number or time
+	FirstSequenceNumber *uint32
+}
+
+// ReadRange reads part of a list or array property, such as the log-buffer
+// of a Trend Log object
+func (c *Client) ReadRange(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, propertyID PropertyIdentifier, rng Range) (*RangeResult, error) {
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	e := NewEncoder(make([]byte, 0, 32))
+	e.ContextObjectIdentifier(0, objectID)
+	e.ContextEnumerated(1, uint32(propertyID))
+	switch rng.kind {
+	case rangeByPosition:
+		e.Opening(3)
+		e.Unsigned(rng.position)
+		e.Signed(rng.count)
+		e.Closing(3)
+	case rangeBySequence:
+		e.Opening(6)
+		e.Unsigned(rng.position)
+		e.Signed(rng.count)
+		e.Closing(6)
+	case rangeByTime:
+		e.Opening(7)
+		encodeDateTime(e, rng.time)
+		e.Signed(rng.count)
+		e.Closing(7)
+	}
+
+	resp, err := c.sendRequest(ctx, deviceID, addr, ServiceReadRange, e.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	d := NewDecoder(resp.Data)
+	d.ContextObjectIdentifier(0)
+	d.ContextEnumerated(1)
+	if d.IsContext(2) {
+		d.ContextUnsigned(2)
+	}
+	flags := decodeBitString(d.context(3, 1, 2))
+	result := &RangeResult{
+		FirstItem: flags.Bit(0),
+		LastItem:  flags.Bit(1),
+		MoreItems: flags.Bit(2),
+		ItemCount: d.ContextUnsigned(4),
+	}
+	d.Opening(5)
+	start := d.Offset()
+	for d.Err() == nil && d.Len() > 0 && !d.IsClosing(5) {
+		d.Skip()
+	}
+	if d.Err() == nil {
+		result.ItemData = resp.Data[start:d.Offset()]
+	}
+	d.Closing(5)
+	if d.IsContext(6) {
+		seq := d.ContextUnsigned(6)
+		result.FirstSequenceNumber = &seq
+	}
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return result, nil
+}
+
+// encodeDateTime appends a BACnetDateTime: an application-tagged date and
+// time
+func encodeDateTime(e *Encoder, t time.Time) {
+	e.Tag(uint8(TagDate), TagClassApplication, 4)
+	weekday := uint8(t.Weekday())
+	if weekday == 0 {
+		weekday = 7
+	}
+	e.Raw([]byte{uint8(t.Year() - 1900), uint8(t.Month()), uint8(t.Day()), weekday})
+	e.Time(TimeOfDay{
+		Hour:       uint8(t.Hour()),
+		Minute:     uint8(t.Minute()),
+		Second:     uint8(t.Second()),
+		Hundredths: uint8(t.Nanosecond() / 1e7),
+	})
+}
+
+// decodeDateTime decodes a BACnetDateTime in loc. Unspecified fields are
+// taken as zero.
+func decodeDateTime(d *Decoder, loc *time.Location) time.Time {
+	date := d.application(TagDate, 4, 4)
+	tod := d.Time()
+	if len(date) != 4 {
+		return time.Time{}
+	}
+	field := func(v uint8) int {
+		if v == 0xFF {
+			return 0
+		}
+		return int(v)
+	}
+	return time.Date(1900+field(date[0]), time.Month(field(date[1])), field(date[2]),
+		field(tod.Hour), field(tod.Minute), field(tod.Second), field(tod.Hundredths)*1e7, loc)
+}
@@ -0,0 +1,218 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+)
+
+// EventTransitions selects event transitions, as in the transitions of a
+// recipient or the ack-required of a Notification Class object
+type EventTransitions struct {
+	ToOffNormal bool
+	ToFault     bool
+	ToNormal    bool
+}
+
+// AllTransitions selects every event transition
+var AllTransitions = EventTransitions{ToOffNormal: true, ToFault: true, ToNormal: true}
+
+// bits returns the transitions as a BACnetEventTransitionBits
+func (t EventTransitions) bits() BitString {
+	b := NewBitString(3)
+	b.Set(0, t.ToOffNormal)
+	b.Set(1, t.ToFault)
+	b.Set(2, t.ToNormal)
+	return b
+}
+
+// eventTransitionsFromBits decodes a BACnetEventTransitionBits
+func eventTransitionsFromBits(b BitString) EventTransitions {
+	return EventTransitions{ToOffNormal: b.Bit(0), ToFault: b.Bit(1), ToNormal: b.Bit(2)}
+}
+
+// RecipientAddress is the network address of a recipient. An empty MAC
+// addresses every station of the network.
+type RecipientAddress struct {
+	Network uint16
+	MAC     []byte
+}
+
+// Recipient is a BACnetRecipient: a device, or a network address when
+// Address is set
+type Recipient struct {
+	Device  ObjectIdentifier
+	Address *RecipientAddress
+}
+
+// DeviceRecipient returns the recipient of a device instance
+func DeviceRecipient(instance uint32) Recipient {
+	return Recipient{Device: NewObjectIdentifier(ObjectTypeDevice, instance)}
+}
+
+// String returns the device identifier or the address as "network:mac"
+func (r Recipient) String() string {
+	if r.Address != nil {
+		return fmt.Sprintf("%d:%x", r.Address.Network, r.Address.MAC)
+	}
+	return r.Device.String()
+}
+
+// Destination is a BACnetDestination of the recipient-list of a
+// Notification Class object: a recipient of event notifications and when it
+// receives them
+type Destination struct {
+	// ValidDays holds the days the destination is used, from Monday
+	// (index 0) to Sunday (index 6)
+	ValidDays [7]bool
+	FromTime  TimeOfDay
+	ToTime    TimeOfDay
+	Recipient Recipient
+	ProcessID uint32
+
+	// Confirmed issues confirmed event notifications
+	Confirmed   bool
+	Transitions EventTransitions
+}
+
+// NewDestination returns a destination that notifies recipient of every
+// transition, every day at any time
+func NewDestination(recipient Recipient, processID uint32, confirmed bool) Destination {
+	return Destination{
+		ValidDays:   [7]bool{true, true, true, true, true, true, true},
+		FromTime:    TimeOfDay{},
+		ToTime:      TimeOfDay{Hour: 23, Minute: 59, Second: 59, Hundredths: 99},
+		Recipient:   recipient,
+		ProcessID:   processID,
+		Confirmed:   confirmed,
+		Transitions: AllTransitions,
+	}
+}
+
+// encode encodes the destination as a BACnetDestination
+func (dst Destination) encode(e *Encoder) error {
+	days := NewBitString(7)
+	for i, valid := range dst.ValidDays {
+		days.Set(i, valid)
+	}
+	e.BitString(days)
+	e.Time(dst.FromTime)
+	e.Time(dst.ToTime)
+	if a := dst.Recipient.Address; a != nil {
+		e.Opening(1)
+		e.Unsigned(uint32(a.Network))
+		e.OctetString(a.MAC)
+		e.Closing(1)
+	} else {
+		e.ContextObjectIdentifier(0, dst.Recipient.Device)
+	}
+	e.Unsigned(dst.ProcessID)
+	e.Boolean(dst.Confirmed)
+	e.BitString(dst.Transitions.bits())
+	return nil
+}
+
+// decodeDestination decodes a BACnetDestination
+func decodeDestination(d *Decoder) Destination {
+	var dst Destination
+	days := d.BitString()
+	for i := range dst.ValidDays {
+		dst.ValidDays[i] = days.Bit(i)
+	}
+	dst.FromTime = d.Time()
+	dst.ToTime = d.Time()
+	switch {
+	case d.IsContext(0):
+		dst.Recipient.Device = d.ContextObjectIdentifier(0)
+	case d.IsOpening(1):
+		d.Opening(1)
+		network := uint16(d.Unsigned())
+		mac := bytes.Clone(d.OctetString())
+		d.Closing(1)
+		dst.Recipient.Address = &RecipientAddress{Network: network, MAC: mac}
+	default:
+		d.failf("expected recipient")
+	}
+	dst.ProcessID = d.Unsigned()
+	dst.Confirmed = d.Boolean()
+	dst.Transitions = eventTransitionsFromBits(d.BitString())
+	return dst
+}
+
+// ReadRecipientList reads the recipient-list of a Notification Class object
+func (c *Client) ReadRecipientList(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) ([]Destination, error) {
+	resp, err := c.readProperty(ctx, deviceID, objectID, PropertyRecipientList, &ReadOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	d := NewDecoder(resp.Data)
+	d.ContextObjectIdentifier(0)
+	d.ContextEnumerated(1)
+	d.Opening(3)
+	var list []Destination
+	for d.Err() == nil && d.Len() > 0 && !d.IsClosing(3) {
+		list = append(list, decodeDestination(d))
+	}
+	d.Closing(3)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return list, nil
+}
+
+// WriteRecipientList replaces the recipient-list of a Notification Class
+// object
+func (c *Client) WriteRecipientList(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, list []Destination) error {
+	return c.WriteProperty(ctx, deviceID, objectID, PropertyRecipientList, list)
+}
+
+// AddRecipients adds destinations to the recipient-list of a Notification
+// Class object with AddListElement, leaving the others in place
+func (c *Client) AddRecipients(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, destinations ...Destination) error {
+	return c.modifyList(ctx, ServiceAddListElement, deviceID, objectID, PropertyRecipientList, destinations)
+}
+
+// RemoveRecipients removes destinations from the recipient-list of a
+// Notification Class object with RemoveListElement. A destination must
+// match an element in every field to be removed.
+func (c *Client) RemoveRecipients(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, destinations ...Destination) error {
+	return c.modifyList(ctx, ServiceRemoveListElement, deviceID, objectID, PropertyRecipientList, destinations)
+}
+
+// modifyList sends an AddListElement or RemoveListElement request
+func (c *Client) modifyList(ctx context.Context, service ConfirmedServiceChoice, deviceID uint32, objectID ObjectIdentifier, propertyID PropertyIdentifier, elements interface{}) error {
+	if c.isStandby() {
+		return ErrStandby
+	}
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return err
+	}
+
+	e := NewEncoder(make([]byte, 0, 64))
+	e.ContextObjectIdentifier(0, objectID)
+	e.ContextEnumerated(1, uint32(propertyID))
+	e.Opening(3)
+	if err := e.Value(elements); err != nil {
+		return fmt.Errorf("encode elements: %w", err)
+	}
+	e.Closing(3)
+
+	_, err = c.sendRequest(ctx, deviceID, addr, service, e.Bytes())
+	return err
+}
@@ -0,0 +1,444 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// RedundancyRole is the role of an instance in a hot-standby pair
+type RedundancyRole int32
+
+const (
+	RoleStandby RedundancyRole = iota
+	RoleActive
+)
+
+// String returns the role name
+func (r RedundancyRole) String() string {
+	if r == RoleActive {
+		return "active"
+	}
+	return "standby"
+}
+
+// SharedSubscription is a COV subscription replicated to the standby so it
+// can re-establish it on takeover
+type SharedSubscription struct {
+	DeviceID     uint32              `json:"device_id"`
+	ObjectID     ObjectIdentifier    `json:"object_id"`
+	PropertyID   *PropertyIdentifier `json:"property_id,omitempty"` // SubscribeCOVProperty only
+	Confirmed    bool                `json:"confirmed,omitempty"`
+	Lifetime     *uint32             `json:"lifetime,omitempty"`
+	COVIncrement *float32            `json:"cov_increment,omitempty"`
+}
+
+// RedundancyState is the state the active instance replicates to the standby
+type RedundancyState struct {
+	Devices       []DeviceInfo         `json:"devices,omitempty"`
+	Subscriptions []SharedSubscription `json:"subscriptions,omitempty"`
+	Cursors       map[string]time.Time `json:"cursors,omitempty"`
+}
+
+// redundancyMessage is the heartbeat exchanged between the instances
+type redundancyMessage struct {
+	ID       string           `json:"id"`
+	Priority uint8            `json:"priority"`
+	Role     RedundancyRole   `json:"role"`
+	State    *RedundancyState `json:"state,omitempty"`
+}
+
+// redundancyStateEvery is the number of heartbeats between full state
+// transfers when nothing changed
+const redundancyStateEvery = 10
+
+// maxRedundancyMessage bounds the heartbeat datagram size
+const maxRedundancyMessage = 65000
+
+// Redundancy coordinates two instances in hot standby. Instances exchange
+// heartbeats over UDP; only the active one issues writes and COV
+// subscriptions, and the standby takes over when heartbeats stop.
+type Redundancy struct {
+	client *Client
+	opts   *redundancyOptions
+	logger *slog.Logger
+
+	localAddr string
+	peerAddr  string
+
+	role     atomic.Int32
+	lastPeer atomic.Int64
+
+	mu            sync.Mutex
+	subscriptions map[uint32]SharedSubscription
+	cursors       map[string]time.Time
+	version       uint64
+	sentVersion   uint64
+	listeners     []func(RedundancyRole)
+}
+
+// NewRedundancy creates a coordinator for client. localAddr is the UDP
+// address heartbeats are received on, peerAddr the address of the other
+// instance. The instance starts as standby; call Run to start coordinating.
+func NewRedundancy(client *Client, localAddr, peerAddr string, opts ...RedundancyOption) *Redundancy {
+	options := defaultRedundancyOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.id == "" {
+		b := make([]byte, 8)
+		rand.Read(b)
+		options.id = hex.EncodeToString(b)
+	}
+	logger := options.logger
+	if logger == nil {
+		logger = client.logger
+	}
+
+	r := &Redundancy{
+		client:        client,
+		opts:          options,
+		logger:        logger,
+		localAddr:     localAddr,
+		peerAddr:      peerAddr,
+		subscriptions: make(map[uint32]SharedSubscription),
+		cursors:       make(map[string]time.Time),
+	}
+	client.redundancy = r
+	return r
+}
+
+// Role returns the current role
+func (r *Redundancy) Role() RedundancyRole {
+	return RedundancyRole(r.role.Load())
+}
+
+// IsActive returns true if the instance is active
+func (r *Redundancy) IsActive() bool {
+	return r.Role() == RoleActive
+}
+
+// OnRoleChange registers a function called when the role changes
+func (r *Redundancy) OnRoleChange(fn func(RedundancyRole)) {
+	r.mu.Lock()
+	r.listeners = append(r.listeners, fn)
+	r.mu.Unlock()
+}
+
+// SetCursor records a named position, e.g. the last timestamp written to a
+// historian. Cursors are replicated to the standby.
+func (r *Redundancy) SetCursor(name string, t time.Time) {
+	r.mu.Lock()
+	r.cursors[name] = t
+	r.version++
+	r.mu.Unlock()
+}
+
+// Cursor returns a named position set locally or replicated from the peer
+func (r *Redundancy) Cursor(name string) (time.Time, bool) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	t, ok := r.cursors[name]
+	return t, ok
+}
+
+// Subscriptions returns the shared COV subscriptions
+func (r *Redundancy) Subscriptions() []SharedSubscription {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	subs := make([]SharedSubscription, 0, len(r.subscriptions))
+	for _, sub := range r.subscriptions {
+		subs = append(subs, sub)
+	}
+	return subs
+}
+
+// trackSubscription records a subscription made by the active instance
+func (r *Redundancy) trackSubscription(subID uint32, sub SharedSubscription) {
+	r.mu.Lock()
+	r.subscriptions[subID] = sub
+	r.version++
+	r.mu.Unlock()
+}
+
+// untrackSubscription forgets a cancelled subscription
+func (r *Redundancy) untrackSubscription(subID uint32) {
+	r.mu.Lock()
+	delete(r.subscriptions, subID)
+	r.version++
+	r.mu.Unlock()
+}
+
+// Run exchanges heartbeats with the peer and manages failover until ctx is
+// cancelled
+func (r *Redundancy) Run(ctx context.Context) error {
+	laddr, err := net.ResolveUDPAddr("udp", r.localAddr)
+	if err != nil {
+		return fmt.Errorf("resolve local address: %w", err)
+	}
+	peer, err := net.ResolveUDPAddr("udp", r.peerAddr)
+	if err != nil {
+		return fmt.Errorf("resolve peer address: %w", err)
+	}
+	conn, err := net.ListenUDP("udp", laddr)
+	if err != nil {
+		return fmt.Errorf("listen: %w", err)
+	}
+	defer conn.Close()
+
+	// Without coordination the instance must not act as active
+	defer r.setRole(ctx, RoleStandby)
+
+	go func() {
+		<-ctx.Done()
+		conn.Close()
+	}()
+
+	r.lastPeer.Store(r.client.opts.clock.Now().UnixNano())
+	go r.receive(ctx, conn)
+
+	ticker := r.client.opts.clock.NewTicker(r.opts.heartbeatInterval)
+	defer ticker.Stop()
+
+	for beat := 0; ; beat++ {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C():
+		}
+
+		silent := r.client.opts.clock.Now().Sub(time.Unix(0, r.lastPeer.Load()))
+		if !r.IsActive() && silent >= r.opts.failoverTimeout {
+			r.logger.Warn("peer heartbeat lost, taking over",
+				slog.String("peer", r.peerAddr),
+				slog.Duration("silent", silent),
+			)
+			r.setRole(ctx, RoleActive)
+		}
+
+		if err := r.sendHeartbeat(conn, peer, beat%redundancyStateEvery == 0); err != nil {
+			r.logger.Debug("heartbeat send failed", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// sendHeartbeat sends a heartbeat, with the replicated state when active
+// and the state changed or full is set
+func (r *Redundancy) sendHeartbeat(conn *net.UDPConn, peer *net.UDPAddr, full bool) error {
+	msg := redundancyMessage{
+		ID:       r.opts.id,
+		Priority: r.opts.priority,
+		Role:     r.Role(),
+	}
+
+	if msg.Role == RoleActive {
+		r.mu.Lock()
+		changed := r.version != r.sentVersion
+		version := r.version
+		r.mu.Unlock()
+
+		if changed || full {
+			msg.State = r.snapshot()
+			r.mu.Lock()
+			r.sentVersion = version
+			r.mu.Unlock()
+		}
+	}
+
+	data, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+	if len(data) > maxRedundancyMessage {
+		// Fall back to a plain heartbeat rather than losing it
+		r.logger.Warn("replicated state too large, not sent", slog.Int("size", len(data)))
+		msg.State = nil
+		if data, err = json.Marshal(msg); err != nil {
+			return err
+		}
+	}
+
+	_, err = conn.WriteToUDP(data, peer)
+	return err
+}
+
+// snapshot returns the state to replicate. Object lists are left out of
+// the device cache to keep heartbeats small.
+func (r *Redundancy) snapshot() *RedundancyState {
+	state := &RedundancyState{
+		Subscriptions: r.Subscriptions(),
+		Cursors:       make(map[string]time.Time),
+	}
+
+	r.mu.Lock()
+	for name, t := range r.cursors {
+		state.Cursors[name] = t
+	}
+	r.mu.Unlock()
+
+	r.client.devicesMu.RLock()
+	for _, dev := range r.client.devices {
+		info := *dev
+		info.ObjectList = nil
+		state.Devices = append(state.Devices, info)
+	}
+	r.client.devicesMu.RUnlock()
+
+	return state
+}
+
+// receive handles heartbeats from the peer
+func (r *Redundancy) receive(ctx context.Context, conn *net.UDPConn) {
+	buf := make([]byte, maxRedundancyMessage+1)
+	for {
+		n, _, err := conn.ReadFromUDP(buf)
+		if err != nil {
+			if ctx.Err() != nil {
+				return
+			}
+			continue
+		}
+
+		var msg redundancyMessage
+		if err := json.Unmarshal(buf[:n], &msg); err != nil {
+			r.logger.Debug("invalid heartbeat", slog.String("error", err.Error()))
+			continue
+		}
+		if msg.ID == r.opts.id {
+			continue
+		}
+		r.lastPeer.Store(r.client.opts.clock.Now().UnixNano())
+
+		if msg.State != nil && !r.IsActive() {
+			r.apply(msg.State)
+		}
+
+		switch {
+		case msg.Role == RoleActive && r.IsActive() && !r.preferredOver(&msg):
+			// Both active after a partition heals: the less preferred yields
+			r.logger.Warn("peer is active and preferred, stepping down", slog.String("peer", msg.ID))
+			r.setRole(ctx, RoleStandby)
+		case msg.Role == RoleStandby && !r.IsActive() && r.preferredOver(&msg):
+			// Both standby at startup: the preferred instance activates
+			r.setRole(ctx, RoleActive)
+		}
+	}
+}
+
+// preferredOver returns true if this instance wins the election against
+// the peer
+func (r *Redundancy) preferredOver(peer *redundancyMessage) bool {
+	if r.opts.priority != peer.Priority {
+		return r.opts.priority < peer.Priority
+	}
+	return r.opts.id < peer.ID
+}
+
+// apply merges state replicated from the active instance
+func (r *Redundancy) apply(state *RedundancyState) {
+	r.mu.Lock()
+	r.subscriptions = make(map[uint32]SharedSubscription, len(state.Subscriptions))
+	for i, sub := range state.Subscriptions {
+		r.subscriptions[uint32(i)] = sub
+	}
+	for name, t := range state.Cursors {
+		r.cursors[name] = t
+	}
+	r.mu.Unlock()
+
+	r.client.devicesMu.Lock()
+	for i := range state.Devices {
+		dev := state.Devices[i]
+		if existing, ok := r.client.devices[dev.ObjectID.Instance]; ok {
+			dev.ObjectList = existing.ObjectList
+		}
+		r.client.devices[dev.ObjectID.Instance] = &dev
+	}
+	r.client.devicesMu.Unlock()
+}
+
+// setRole switches role, re-establishing shared subscriptions on takeover
+func (r *Redundancy) setRole(ctx context.Context, role RedundancyRole) {
+	if RedundancyRole(r.role.Swap(int32(role))) == role {
+		return
+	}
+
+	r.logger.Info("redundancy role changed", slog.String("role", role.String()))
+
+	r.mu.Lock()
+	listeners := append([]func(RedundancyRole){}, r.listeners...)
+	r.mu.Unlock()
+
+	if role == RoleActive {
+		go r.resubscribe(ctx)
+	}
+
+	for _, fn := range listeners {
+		fn(role)
+	}
+}
+
+// resubscribe re-establishes the subscriptions replicated from the former
+// active instance
+func (r *Redundancy) resubscribe(ctx context.Context) {
+	r.mu.Lock()
+	subs := make([]SharedSubscription, 0, len(r.subscriptions))
+	for _, sub := range r.subscriptions {
+		subs = append(subs, sub)
+	}
+	r.subscriptions = make(map[uint32]SharedSubscription)
+	r.mu.Unlock()
+
+	handler := r.opts.covHandler
+	if handler == nil {
+		handler = func(uint32, ObjectIdentifier, []PropertyValue) {}
+	}
+
+	for _, sub := range subs {
+		opts := []SubscribeOption{}
+		if sub.Confirmed {
+			opts = append(opts, WithConfirmedNotifications(true))
+		}
+		if sub.Lifetime != nil {
+			opts = append(opts, WithSubscriptionLifetime(*sub.Lifetime))
+		}
+		if sub.COVIncrement != nil {
+			opts = append(opts, WithCOVIncrement(*sub.COVIncrement))
+		}
+
+		var err error
+		if sub.PropertyID != nil {
+			_, err = r.client.SubscribeCOVProperty(ctx, sub.DeviceID, sub.ObjectID, *sub.PropertyID, handler, opts...)
+		} else {
+			_, err = r.client.SubscribeCOV(ctx, sub.DeviceID, sub.ObjectID, handler, opts...)
+		}
+		if err != nil {
+			r.logger.Warn("failed to restore subscription",
+				slog.Uint64("device_id", uint64(sub.DeviceID)),
+				slog.String("object", sub.ObjectID.String()),
+				slog.String("error", err.Error()),
+			)
+		}
+	}
+}
@@ -0,0 +1,685 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/edgeo-scada/bacnet/internal/transport"
+)
+
+// BACnet/SC (Annex AB) constants
+const (
+	// SCHubSubprotocol is the WebSocket subprotocol of hub connections
+	SCHubSubprotocol = "hub.bsc.bacnet.org"
+
+	// DefaultSCHeartbeat is the default connection heartbeat timeout
+	DefaultSCHeartbeat = 300 * time.Second
+
+	// SCMaxBVLCLength is the maximum BVLC-SC message size accepted
+	SCMaxBVLCLength = 1600
+
+	// SCMaxNPDULength is the maximum NPDU size accepted
+	SCMaxNPDULength = 1497
+
+	// scReconnectDelay is the wait before reconnecting to the hub
+	scReconnectDelay = 10 * time.Second
+)
+
+// SCFunction is a BVLC-SC message function
+type SCFunction uint8
+
+const (
+	SCBVLCResult                SCFunction = 0x00
+	SCEncapsulatedNPDU          SCFunction = 0x01
+	SCAddressResolution         SCFunction = 0x02
+	SCAddressResolutionACK      SCFunction = 0x03
+	SCAdvertisement             SCFunction = 0x04
+	SCAdvertisementSolicitation SCFunction = 0x05
+	SCConnectRequest            SCFunction = 0x06
+	SCConnectAccept             SCFunction = 0x07
+	SCDisconnectRequest         SCFunction = 0x08
+	SCDisconnectACK             SCFunction = 0x09
+	SCHeartbeatRequest          SCFunction = 0x0A
+	SCHeartbeatACK              SCFunction = 0x0B
+	SCProprietaryMessage        SCFunction = 0x0C
+)
+
+// BVLC-SC control flags
+const (
+	scControlOrigVMAC    = 0x08
+	scControlDestVMAC    = 0x04
+	scControlDestOptions = 0x02
+	scControlDataOptions = 0x01
+)
+
+// BVLC-SC header option marker bits
+const (
+	scOptionMore           = 0x80
+	scOptionMustUnderstand = 0x40
+	scOptionHasData        = 0x20
+)
+
+// ErrSCConnectRejected is returned when the hub refuses the connection
+var ErrSCConnectRejected = errors.New("bacnet: BACnet/SC connect rejected")
+
+// VMAC is a BACnet/SC virtual MAC address
+type VMAC [6]byte
+
+// SCBroadcastVMAC is the local broadcast VMAC
+var SCBroadcastVMAC = VMAC{0xFF, 0xFF, 0xFF, 0xFF, 0xFF, 0xFF}
+
+// RandomVMAC returns a random-48 VMAC (Annex AB.1.5.2)
+func RandomVMAC() VMAC {
+	var v VMAC
+	rand.Read(v[:])
+	v[0] = (v[0] & 0xF0) | 0x02
+	return v
+}
+
+// ParseVMAC parses a VMAC written as 12 hex digits, optionally separated
+// by colons
+func ParseVMAC(s string) (VMAC, error) {
+	var v VMAC
+	b, err := hex.DecodeString(strings.ReplaceAll(s, ":", ""))
+	if err != nil || len(b) != len(v) {
+		return v, fmt.Errorf("invalid VMAC %q", s)
+	}
+	copy(v[:], b)
+	return v, nil
+}
+
+// Network implements net.Addr
+func (v VMAC) Network() string { return "bacnet-sc" }
+
+// String returns the VMAC as colon separated hex
+func (v VMAC) String() string {
+	return fmt.Sprintf("%02x:%02x:%02x:%02x:%02x:%02x", v[0], v[1], v[2], v[3], v[4], v[5])
+}
+
+// scMessage is a decoded BVLC-SC message
+type scMessage struct {
+	Function    SCFunction
+	MessageID   uint16
+	OrigVMAC    *VMAC
+	DestVMAC    *VMAC
+	DataOptions []byte
+	Payload     []byte
+}
+
+// encodeSCMessage encodes a BVLC-SC message without header options
+func encodeSCMessage(m *scMessage) []byte {
+	buf := make([]byte, 4, 16+len(m.Payload))
+	buf[0] = byte(m.Function)
+	binary.BigEndian.PutUint16(buf[2:], m.MessageID)
+	if m.OrigVMAC != nil {
+		buf[1] |= scControlOrigVMAC
+		buf = append(buf, m.OrigVMAC[:]...)
+	}
+	if m.DestVMAC != nil {
+		buf[1] |= scControlDestVMAC
+		buf = append(buf, m.DestVMAC[:]...)
+	}
+	return append(buf, m.Payload...)
+}
+
+// decodeSCMessage decodes a BVLC-SC message, skipping header options
+func decodeSCMessage(data []byte) (*scMessage, error) {
+	if len(data) < 4 {
+		return nil, ErrInvalidBVLC
+	}
+
+	m := &scMessage{
+		Function:  SCFunction(data[0]),
+		MessageID: binary.BigEndian.Uint16(data[2:]),
+	}
+	control := data[1]
+	offset := 4
+
+	if control&scControlOrigVMAC != 0 {
+		if len(data) < offset+6 {
+			return nil, ErrInvalidBVLC
+		}
+		v := VMAC(data[offset : offset+6])
+		m.OrigVMAC = &v
+		offset += 6
+	}
+	if control&scControlDestVMAC != 0 {
+		if len(data) < offset+6 {
+			return nil, ErrInvalidBVLC
+		}
+		v := VMAC(data[offset : offset+6])
+		m.DestVMAC = &v
+		offset += 6
+	}
+
+	if control&scControlDestOptions != 0 {
+		end, err := skipSCOptions(data, offset)
+		if err != nil {
+			return nil, err
+		}
+		offset = end
+	}
+	if control&scControlDataOptions != 0 {
+		end, err := skipSCOptions(data, offset)
+		if err != nil {
+			return nil, err
+		}
+		m.DataOptions = data[offset:end]
+		offset = end
+	}
+
+	m.Payload = data[offset:]
+	return m, nil
+}
+
+// skipSCOptions returns the offset following a list of header options. No
+// options are understood, so a must-understand option is an error.
+func skipSCOptions(data []byte, offset int) (int, error) {
+	for {
+		if offset >= len(data) {
+			return 0, ErrInvalidBVLC
+		}
+		marker := data[offset]
+		offset++
+		if marker&scOptionMustUnderstand != 0 {
+			return 0, fmt.Errorf("bacnet/sc: unsupported header option %d", marker&0x1F)
+		}
+		if marker&scOptionHasData != 0 {
+			if len(data) < offset+2 {
+				return 0, ErrInvalidBVLC
+			}
+			offset += 2 + int(binary.BigEndian.Uint16(data[offset:]))
+		}
+		if marker&scOptionMore == 0 {
+			return offset, nil
+		}
+	}
+}
+
+// scInbound is an NPDU received over BACnet/SC
+type scInbound struct {
+	npdu []byte
+	from VMAC
+}
+
+// scLink is the BACnet/SC data link (Annex AB). The client is a node
+// connected to a single hub over a secure WebSocket.
+type scLink struct {
+	hubURI    string
+	tlsConfig *tls.Config
+	vmac      VMAC
+	uuid      [16]byte
+	heartbeat time.Duration
+	timeout   time.Duration
+	logger    *slog.Logger
+	clock     Clock
+
+	mu     sync.Mutex
+	ws     *transport.WebSocketConn
+	closed bool
+	done   chan struct{}
+
+	messageID atomic.Uint32
+	lastRecv  atomic.Int64
+	rx        chan scInbound
+	wg        sync.WaitGroup
+}
+
+// newSCLink creates a BACnet/SC data link from the client options
+func newSCLink(o *clientOptions) *scLink {
+	l := &scLink{
+		hubURI:    o.scHubURI,
+		tlsConfig: o.scTLSConfig,
+		heartbeat: o.scHeartbeat,
+		timeout:   o.timeout,
+		logger:    o.logger,
+		clock:     o.clock,
+		rx:        make(chan scInbound, 64),
+	}
+
+	if o.scVMAC != nil {
+		l.vmac = *o.scVMAC
+	} else {
+		l.vmac = RandomVMAC()
+	}
+	if o.scUUID != nil {
+		l.uuid = *o.scUUID
+	} else {
+		rand.Read(l.uuid[:])
+		l.uuid[6] = (l.uuid[6] & 0x0F) | 0x40 // Version 4
+		l.uuid[8] = (l.uuid[8] & 0x3F) | 0x80 // RFC 4122 variant
+	}
+	if l.heartbeat <= 0 {
+		l.heartbeat = DefaultSCHeartbeat
+	}
+
+	return l
+}
+
+func (l *scLink) Open(ctx context.Context) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.ws != nil {
+		return nil
+	}
+
+	ws, err := l.connect(ctx)
+	if err != nil {
+		return err
+	}
+
+	l.ws = ws
+	l.closed = false
+	l.done = make(chan struct{})
+
+	l.wg.Add(2)
+	go l.reader(ws)
+	go l.heartbeatLoop()
+
+	return nil
+}
+
+// connect opens the WebSocket and performs the connect handshake
+func (l *scLink) connect(ctx context.Context) (*transport.WebSocketConn, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, l.timeout)
+		defer cancel()
+	}
+
+	ws, err := transport.DialWebSocket(ctx, l.hubURI, l.tlsConfig, SCHubSubprotocol)
+	if err != nil {
+		return nil, fmt.Errorf("connect hub: %w", err)
+	}
+
+	payload := make([]byte, 26)
+	copy(payload, l.vmac[:])
+	copy(payload[6:], l.uuid[:])
+	binary.BigEndian.PutUint16(payload[22:], SCMaxBVLCLength)
+	binary.BigEndian.PutUint16(payload[24:], SCMaxNPDULength)
+
+	id := l.nextMessageID()
+	request := encodeSCMessage(&scMessage{
+		Function:  SCConnectRequest,
+		MessageID: id,
+		Payload:   payload,
+	})
+
+	deadline, _ := ctx.Deadline()
+	ws.SetWriteDeadline(deadline)
+	ws.SetReadDeadline(deadline)
+	defer ws.SetWriteDeadline(time.Time{})
+	defer ws.SetReadDeadline(time.Time{})
+
+	if err := ws.WriteMessage(request); err != nil {
+		ws.Close()
+		return nil, fmt.Errorf("send connect request: %w", err)
+	}
+
+	for {
+		data, err := ws.ReadMessage()
+		if err != nil {
+			ws.Close()
+			return nil, fmt.Errorf("read connect accept: %w", err)
+		}
+
+		m, err := decodeSCMessage(data)
+		if err != nil || m.MessageID != id {
+			continue
+		}
+
+		switch m.Function {
+		case SCConnectAccept:
+			l.lastRecv.Store(time.Now().UnixNano())
+			return ws, nil
+		case SCBVLCResult:
+			ws.Close()
+			return nil, fmt.Errorf("%w: %s", ErrSCConnectRejected, describeSCResult(m.Payload))
+		}
+	}
+}
+
+// describeSCResult formats the error of a BVLC-Result NAK
+func describeSCResult(payload []byte) string {
+	// Result for function (1), result code (1), error header marker (1),
+	// error class (2), error code (2), error details
+	if len(payload) < 7 || payload[1] == 0 {
+		return "no details"
+	}
+	class := binary.BigEndian.Uint16(payload[3:])
+	code := binary.BigEndian.Uint16(payload[5:])
+	s := fmt.Sprintf("%s: %s", ErrorClass(class), ErrorCode(code))
+	if details := string(payload[7:]); details != "" {
+		s += " (" + details + ")"
+	}
+	return s
+}
+
+func (l *scLink) nextMessageID() uint16 {
+	return uint16(l.messageID.Add(1))
+}
+
+// reader receives messages from the hub until the connection drops, then
+// reconnects
+func (l *scLink) reader(ws *transport.WebSocketConn) {
+	defer l.wg.Done()
+
+	for {
+		err := l.readMessages(ws)
+
+		select {
+		case <-l.done:
+			return
+		default:
+		}
+
+		l.logger.Warn("BACnet/SC hub connection lost",
+			slog.String("hub", l.hubURI),
+			slog.String("error", err.Error()),
+		)
+
+		ws = l.reconnect()
+		if ws == nil {
+			return
+		}
+	}
+}
+
+// reconnect re-establishes the hub connection, returning nil once the link
+// is closed
+func (l *scLink) reconnect() *transport.WebSocketConn {
+	l.mu.Lock()
+	if l.ws != nil {
+		l.ws.Close()
+		l.ws = nil
+	}
+	l.mu.Unlock()
+
+	for {
+		if !sleepDone(l.done, l.clock, scReconnectDelay) {
+			return nil
+		}
+
+		ws, err := l.connect(context.Background())
+		if err != nil {
+			l.logger.Debug("BACnet/SC reconnect failed", slog.String("error", err.Error()))
+			continue
+		}
+
+		l.mu.Lock()
+		if l.closed {
+			l.mu.Unlock()
+			ws.Close()
+			return nil
+		}
+		l.ws = ws
+		l.mu.Unlock()
+
+		l.logger.Info("BACnet/SC hub connection restored", slog.String("hub", l.hubURI))
+		return ws
+	}
+}
+
+// readMessages dispatches incoming messages until an error occurs
+func (l *scLink) readMessages(ws *transport.WebSocketConn) error {
+	for {
+		data, err := ws.ReadMessage()
+		if err != nil {
+			return err
+		}
+		l.lastRecv.Store(time.Now().UnixNano())
+
+		m, err := decodeSCMessage(data)
+		if err != nil {
+			l.logger.Debug("invalid BVLC-SC message", slog.String("error", err.Error()))
+			continue
+		}
+
+		switch m.Function {
+		case SCEncapsulatedNPDU:
+			from := SCBroadcastVMAC
+			if m.OrigVMAC != nil {
+				from = *m.OrigVMAC
+			}
+			select {
+			case l.rx <- scInbound{npdu: m.Payload, from: from}:
+			case <-l.done:
+				return ErrConnectionClosed
+			}
+
+		case SCHeartbeatRequest:
+			l.write(ws, &scMessage{Function: SCHeartbeatACK, MessageID: m.MessageID})
+
+		case SCAdvertisementSolicitation:
+			// Connected to the primary hub, direct connections not accepted
+			payload := []byte{1, 0, 0, 0, 0, 0}
+			binary.BigEndian.PutUint16(payload[2:], SCMaxBVLCLength)
+			binary.BigEndian.PutUint16(payload[4:], SCMaxNPDULength)
+			l.write(ws, &scMessage{
+				Function:  SCAdvertisement,
+				MessageID: m.MessageID,
+				DestVMAC:  m.OrigVMAC,
+				Payload:   payload,
+			})
+
+		case SCAddressResolution:
+			// No direct connect URIs are offered
+			l.write(ws, &scMessage{
+				Function:  SCAddressResolutionACK,
+				MessageID: m.MessageID,
+				DestVMAC:  m.OrigVMAC,
+			})
+
+		case SCDisconnectRequest:
+			l.write(ws, &scMessage{Function: SCDisconnectACK, MessageID: m.MessageID})
+			return fmt.Errorf("disconnected by hub")
+
+		case SCBVLCResult:
+			if len(m.Payload) >= 2 && m.Payload[1] != 0 {
+				l.logger.Debug("BVLC-SC NAK",
+					slog.String("function", fmt.Sprintf("0x%02x", m.Payload[0])),
+					slog.String("error", describeSCResult(m.Payload)),
+				)
+			}
+		}
+	}
+}
+
+// heartbeatLoop sends Heartbeat-Request when the connection has been idle
+// for the heartbeat timeout, and drops it when the hub stays silent
+func (l *scLink) heartbeatLoop() {
+	defer l.wg.Done()
+
+	ticker := time.NewTicker(l.heartbeat / 2)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-l.done:
+			return
+		case <-ticker.C:
+		}
+
+		l.mu.Lock()
+		ws := l.ws
+		l.mu.Unlock()
+		if ws == nil {
+			continue
+		}
+
+		idle := time.Since(time.Unix(0, l.lastRecv.Load()))
+		switch {
+		case idle >= 2*l.heartbeat:
+			// Unblocks the reader, which reconnects
+			ws.Close()
+		case idle >= l.heartbeat:
+			l.write(ws, &scMessage{Function: SCHeartbeatRequest, MessageID: l.nextMessageID()})
+		}
+	}
+}
+
+// write sends a BVLC-SC message
+func (l *scLink) write(ws *transport.WebSocketConn, m *scMessage) error {
+	ws.SetWriteDeadline(time.Now().Add(l.timeout))
+	return ws.WriteMessage(encodeSCMessage(m))
+}
+
+func (l *scLink) Close() error {
+	l.mu.Lock()
+	if l.closed {
+		l.mu.Unlock()
+		return nil
+	}
+	l.closed = true
+	ws := l.ws
+	l.ws = nil
+	if l.done != nil {
+		close(l.done)
+	}
+	l.mu.Unlock()
+
+	var err error
+	if ws != nil {
+		// The hub acknowledges with Disconnect-ACK; the reader is not
+		// waited for as the socket is closed right after
+		l.write(ws, &scMessage{Function: SCDisconnectRequest, MessageID: l.nextMessageID()})
+		err = ws.Close()
+	}
+	l.wg.Wait()
+	return err
+}
+
+func (l *scLink) IsClosed() bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	return l.closed
+}
+
+// LocalAddr returns the local VMAC
+func (l *scLink) LocalAddr() net.Addr {
+	return l.vmac
+}
+
+func (l *scLink) Send(ctx context.Context, addr net.Addr, npdu []byte) error {
+	vmac, ok := addr.(VMAC)
+	if !ok {
+		return fmt.Errorf("bacnet/sc: unsupported address %v", addr)
+	}
+	return l.send(ctx, vmac, npdu)
+}
+
+func (l *scLink) Broadcast(ctx context.Context, npdu []byte) error {
+	return l.send(ctx, SCBroadcastVMAC, npdu)
+}
+
+// send encapsulates an NPDU for the hub to forward to dest
+func (l *scLink) send(ctx context.Context, dest VMAC, npdu []byte) error {
+	l.mu.Lock()
+	ws := l.ws
+	l.mu.Unlock()
+
+	if ws == nil {
+		return ErrNotConnected
+	}
+
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		deadline = time.Now().Add(l.timeout)
+	}
+	ws.SetWriteDeadline(deadline)
+
+	return ws.WriteMessage(encodeSCMessage(&scMessage{
+		Function:  SCEncapsulatedNPDU,
+		MessageID: l.nextMessageID(),
+		DestVMAC:  &dest,
+		Payload:   npdu,
+	}))
+}
+
+func (l *scLink) Receive(timeout time.Duration) ([]byte, net.Addr, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case in := <-l.rx:
+		return in.npdu, in.from, nil
+	case <-timer.C:
+		if l.IsClosed() {
+			return nil, nil, ErrConnectionClosed
+		}
+		return nil, nil, linkTimeoutError{}
+	}
+}
+
+// MAC returns the VMAC bytes
+func (l *scLink) MAC(addr net.Addr) []byte {
+	vmac, ok := addr.(VMAC)
+	if !ok {
+		return nil
+	}
+	return append([]byte(nil), vmac[:]...)
+}
+
+func (l *scLink) Addr(mac []byte) (net.Addr, error) {
+	if len(mac) != 6 {
+		return nil, fmt.Errorf("invalid VMAC length %d", len(mac))
+	}
+	return VMAC(mac), nil
+}
+
+func (l *scLink) ParseAddr(s string) (net.Addr, error) {
+	return ParseVMAC(s)
+}
+
+// LoadSecureConnectTLSConfig builds the TLS configuration of a BACnet/SC
+// node from its operational certificate, private key and the issuer CA
+// certificate (PEM files). BACnet/SC requires TLS 1.3.
+func LoadSecureConnectTLSConfig(certFile, keyFile, caFile string) (*tls.Config, error) {
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("load operational certificate: %w", err)
+	}
+
+	caPEM, err := os.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("read CA certificate: %w", err)
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(caPEM) {
+		return nil, fmt.Errorf("no certificates found in %s", caFile)
+	}
+
+	return &tls.Config{
+		Certificates: []tls.Certificate{cert},
+		RootCAs:      pool,
+		MinVersion:   tls.VersionTLS13,
+	}, nil
+}
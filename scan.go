@@ -0,0 +1,376 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+// scanField is a struct field bound to a property with a `bacnet` tag
+type scanField struct {
+	index      int
+	name       string
+	objectID   ObjectIdentifier
+	propertyID PropertyIdentifier
+	priority   *uint8
+}
+
+// ReadInto reads the properties bound to the fields of the struct pointed to
+// by dst. Fields are bound with a tag of the form
+//
+//	Temp float64 `bacnet:"ai:1,present-value"`
+//
+// where the property defaults to present-value. The properties are read
+// with ReadPropertyMultiple, or one at a time from devices without it, and
+// converted to the field types. Fields that cannot be read or converted are
+// reported in the returned error; the other fields are still set.
+func (c *Client) ReadInto(ctx context.Context, deviceID uint32, dst interface{}) error {
+	v := reflect.ValueOf(dst)
+	if v.Kind() != reflect.Pointer || v.IsNil() || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bacnet: ReadInto needs a non-nil struct pointer, got %T", dst)
+	}
+	v = v.Elem()
+
+	fields, err := scanFields(v.Type())
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	requests := make([]ReadPropertyRequest, len(fields))
+	for i, f := range fields {
+		requests[i] = ReadPropertyRequest{ObjectID: f.objectID, PropertyID: f.propertyID}
+	}
+
+	results, err := c.ReadPropertyMultiple(ctx, deviceID, requests)
+	if serviceUnsupported(err) {
+		results, err = c.readEach(ctx, deviceID, requests)
+	}
+	if err != nil {
+		return err
+	}
+
+	type point struct {
+		objectID   ObjectIdentifier
+		propertyID PropertyIdentifier
+	}
+	byPoint := make(map[point]PropertyResult, len(results))
+	for _, r := range results {
+		byPoint[point{r.ObjectID, r.PropertyID}] = r
+	}
+
+	var errs []error
+	for _, f := range fields {
+		r, ok := byPoint[point{f.objectID, f.propertyID}]
+		switch {
+		case !ok:
+			errs = append(errs, fmt.Errorf("%s: %s %s not returned", f.name, f.objectID, f.propertyID))
+		case r.Err != nil:
+			errs = append(errs, fmt.Errorf("%s: %w", f.name, r.Err))
+		default:
+			if err := assignValue(v.Field(f.index), r.Value); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", f.name, err))
+			}
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// WriteFrom writes the fields of the struct src, or of the struct it points
+// to, to the properties they are bound to with `bacnet` tags as described by
+// ReadInto. A tag may add a priority, as in `bacnet:"ao:1,present-value,priority=8"`;
+// otherwise the priority of opts applies. Values are coerced to the datatype
+// of the property where it is known from the object type, such as REAL for
+// the present value of analog objects. The fields are written with
+// WritePropertyMultiple, or one at a time to devices without it.
+func (c *Client) WriteFrom(ctx context.Context, deviceID uint32, src interface{}, opts ...WriteOption) error {
+	v := reflect.ValueOf(src)
+	if v.Kind() == reflect.Pointer && !v.IsNil() {
+		v = v.Elem()
+	}
+	if v.Kind() != reflect.Struct {
+		return fmt.Errorf("bacnet: WriteFrom needs a struct or struct pointer, got %T", src)
+	}
+
+	fields, err := scanFields(v.Type())
+	if err != nil {
+		return err
+	}
+	if len(fields) == 0 {
+		return nil
+	}
+
+	requests := make([]WritePropertyRequest, len(fields))
+	for i, f := range fields {
+		requests[i] = WritePropertyRequest{
+			ObjectID:   f.objectID,
+			PropertyID: f.propertyID,
+			Value:      coerceValue(f.objectID, f.propertyID, v.Field(f.index)),
+			Priority:   f.priority,
+		}
+	}
+
+	err = c.WritePropertyMultiple(ctx, deviceID, requests, opts...)
+	if !serviceUnsupported(err) {
+		return err
+	}
+
+	// The device lacks WritePropertyMultiple
+	for i, req := range requests {
+		writeOpts := opts
+		if req.Priority != nil {
+			writeOpts = append(writeOpts[:len(writeOpts):len(writeOpts)], WithPriority(*req.Priority))
+		}
+		if err := c.WriteProperty(ctx, deviceID, req.ObjectID, req.PropertyID, req.Value, writeOpts...); err != nil {
+			return fmt.Errorf("%s: %w", fields[i].name, err)
+		}
+	}
+	return nil
+}
+
+// readEach reads properties one ReadProperty at a time, reporting property
+// errors in the results
+func (c *Client) readEach(ctx context.Context, deviceID uint32, requests []ReadPropertyRequest) ([]PropertyResult, error) {
+	results := make([]PropertyResult, len(requests))
+	for i, req := range requests {
+		var opts []ReadOption
+		if req.ArrayIndex != nil {
+			opts = append(opts, WithArrayIndex(*req.ArrayIndex))
+		}
+		value, err := c.ReadProperty(ctx, deviceID, req.ObjectID, req.PropertyID, opts...)
+		results[i] = PropertyResult{
+			ObjectID:   req.ObjectID,
+			PropertyID: req.PropertyID,
+			ArrayIndex: req.ArrayIndex,
+			Value:      value,
+		}
+		if err != nil {
+			// Errors of the property are reported in its result
+			var bacnetErr *BACnetError
+			if !errors.As(err, &bacnetErr) {
+				return results[:i], err
+			}
+			results[i].Err = err
+		}
+	}
+	return results, nil
+}
+
+// scanFields returns the exported fields of t with a `bacnet` tag
+func scanFields(t reflect.Type) ([]scanField, error) {
+	var fields []scanField
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		tag, ok := sf.Tag.Lookup("bacnet")
+		if !ok || tag == "-" || !sf.IsExported() {
+			continue
+		}
+
+		f, err := parseScanTag(tag)
+		if err != nil {
+			return nil, fmt.Errorf("bacnet: field %s: %w", sf.Name, err)
+		}
+		f.index = i
+		f.name = sf.Name
+		fields = append(fields, f)
+	}
+	return fields, nil
+}
+
+// parseScanTag parses "object[,property][,priority=N]"
+func parseScanTag(tag string) (scanField, error) {
+	var f scanField
+	parts := strings.Split(tag, ",")
+
+	typ, inst, ok := strings.Cut(strings.TrimSpace(parts[0]), ":")
+	if !ok {
+		return f, fmt.Errorf("object %q is not type:instance", parts[0])
+	}
+	instance, err := strconv.ParseUint(inst, 10, 22)
+	if err != nil {
+		return f, fmt.Errorf("invalid instance %q", inst)
+	}
+	objectType, ok := ParseObjectType(strings.ToLower(typ))
+	if !ok {
+		n, err := strconv.ParseUint(typ, 10, 10)
+		if err != nil {
+			return f, fmt.Errorf("unknown object type %q", typ)
+		}
+		objectType = ObjectType(n)
+	}
+	f.objectID = NewObjectIdentifier(objectType, uint32(instance))
+	f.propertyID = PropertyPresentValue
+
+	for i, part := range parts[1:] {
+		part = strings.TrimSpace(part)
+		if p, ok := strings.CutPrefix(part, "priority="); ok {
+			priority, err := strconv.ParseUint(p, 10, 8)
+			if err != nil || priority < 1 || priority > 16 {
+				return f, fmt.Errorf("invalid priority %q", p)
+			}
+			pr := uint8(priority)
+			f.priority = &pr
+			continue
+		}
+		if i > 0 {
+			return f, fmt.Errorf("unexpected %q", part)
+		}
+
+		propertyID, ok := ParsePropertyIdentifier(strings.ToLower(part))
+		if !ok {
+			n, err := strconv.ParseUint(part, 10, 22)
+			if err != nil {
+				return f, fmt.Errorf("unknown property %q", part)
+			}
+			propertyID = PropertyIdentifier(n)
+		}
+		f.propertyID = propertyID
+	}
+	return f, nil
+}
+
+// assignValue stores a decoded property value in a field, converting
+// between numeric types
+func assignValue(field reflect.Value, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if rv.Type().AssignableTo(field.Type()) {
+		field.Set(rv)
+		return nil
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		// Binary present values are enumerated
+		if isNumeric(rv) {
+			field.SetBool(rv.Convert(reflect.TypeOf(float64(0))).Float() != 0)
+			return nil
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if isNumeric(rv) {
+			field.Set(rv.Convert(field.Type()))
+			return nil
+		}
+		if rv.Kind() == reflect.Bool {
+			n := 0
+			if rv.Bool() {
+				n = 1
+			}
+			field.Set(reflect.ValueOf(n).Convert(field.Type()))
+			return nil
+		}
+	}
+	return fmt.Errorf("cannot store %T in %s", value, field.Type())
+}
+
+// isNumeric returns true for integer and floating-point values
+func isNumeric(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return true
+	}
+	return false
+}
+
+// CoerceValue converts a boolean or number to the Go type encoding the
+// datatype of the property, as WriteFrom does for struct fields, so that
+// for example 75 is written to an analog present value as a REAL. Other
+// values are returned unchanged.
+func CoerceValue(objectID ObjectIdentifier, propertyID PropertyIdentifier, value interface{}) interface{} {
+	if value == nil {
+		return nil
+	}
+	return coerceValue(objectID, propertyID, reflect.ValueOf(value))
+}
+
+// coerceValue converts a field to the Go type encoding the datatype of the
+// property: REAL, ENUMERATED or Unsigned for the present value and
+// relinquish default of analog, binary and multi-state objects, the
+// datatype of the standard properties with a single one, and the natural
+// encoding of the field type otherwise
+func coerceValue(objectID ObjectIdentifier, propertyID PropertyIdentifier, field reflect.Value) interface{} {
+	value := field.Interface()
+	if !isNumeric(field) && field.Kind() != reflect.Bool {
+		return value
+	}
+	switch value.(type) {
+	case Enumerated, ObjectType, EventState, Reliability, EngineeringUnits, Segmentation, DeviceStatus:
+		return value
+	}
+
+	number := 0.0
+	if field.Kind() == reflect.Bool {
+		if field.Bool() {
+			number = 1
+		}
+	} else {
+		number = field.Convert(reflect.TypeOf(float64(0))).Float()
+	}
+
+	if propertyID == PropertyPresentValue || propertyID == PropertyRelinquishDefault {
+		switch objectID.Type {
+		case ObjectTypeAnalogInput, ObjectTypeAnalogOutput, ObjectTypeAnalogValue:
+			return float32(number)
+		case ObjectTypeBinaryInput, ObjectTypeBinaryOutput, ObjectTypeBinaryValue:
+			return Enumerated(number)
+		case ObjectTypeMultiStateInput, ObjectTypeMultiStateOutput, ObjectTypeMultiStateValue:
+			return uint32(number)
+		}
+	}
+
+	switch propertyDatatypes[propertyID] {
+	case datatypeReal:
+		return float32(number)
+	case datatypeEnumerated:
+		return Enumerated(number)
+	case datatypeBoolean:
+		return number != 0
+	case datatypeUnsigned:
+		return uint32(number)
+	case datatypeSigned:
+		return int32(number)
+	}
+
+	switch field.Kind() {
+	case reflect.Bool:
+		return field.Bool()
+	case reflect.Float32:
+		return float32(field.Float())
+	case reflect.Float64:
+		return field.Float()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		if field.Int() < 0 {
+			return int32(field.Int())
+		}
+		return uint32(field.Int())
+	default:
+		return uint32(field.Uint())
+	}
+}
@@ -0,0 +1,640 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// TimeOfDay is a BACnet time. Fields set to 255 are unspecified.
+type TimeOfDay struct {
+	Hour       uint8
+	Minute     uint8
+	Second     uint8
+	Hundredths uint8
+}
+
+// ParseTimeOfDay parses a time in the form "15:04" or "15:04:05"
+func ParseTimeOfDay(s string) (TimeOfDay, error) {
+	parts := strings.Split(strings.TrimSpace(s), ":")
+	if len(parts) < 2 || len(parts) > 3 {
+		return TimeOfDay{}, fmt.Errorf("invalid time %q", s)
+	}
+
+	limits := []int{23, 59, 59}
+	var fields [3]uint8
+	for i, p := range parts {
+		n, err := strconv.Atoi(p)
+		if err != nil || n < 0 || n > limits[i] {
+			return TimeOfDay{}, fmt.Errorf("invalid time %q", s)
+		}
+		fields[i] = uint8(n)
+	}
+
+	return TimeOfDay{Hour: fields[0], Minute: fields[1], Second: fields[2]}, nil
+}
+
+// String returns the time as "15:04:05"
+func (t TimeOfDay) String() string {
+	return fmt.Sprintf("%02d:%02d:%02d", t.Hour, t.Minute, t.Second)
+}
+
+// TimeValue is a scheduled value taking effect at a time of day
+type TimeValue struct {
+	Time TimeOfDay
+
+	// Value is written to the schedule's target properties; nil relinquishes
+	Value interface{}
+}
+
+// DailySchedule is the ordered list of time-values of one day
+type DailySchedule []TimeValue
+
+// WeeklySchedule is the weekly-schedule property of a Schedule object, one
+// DailySchedule per day from Monday (index 0) to Sunday (index 6)
+type WeeklySchedule [7]DailySchedule
+
+// WeekdayIndex returns the WeeklySchedule index of a weekday
+func WeekdayIndex(day time.Weekday) int {
+	return (int(day) + 6) % 7
+}
+
+// encode encodes the schedule as an array of BACnetDailySchedule
+func (w WeeklySchedule) encode(e *Encoder) error {
+	for _, day := range w {
+		e.Opening(0)
+		for _, tv := range day {
+			e.Time(tv.Time)
+			if err := e.Value(tv.Value); err != nil {
+				return fmt.Errorf("%s: %w", tv.Time, err)
+			}
+		}
+		e.Closing(0)
+	}
+	return nil
+}
+
+// decodeWeeklySchedule decodes an array of BACnetDailySchedule up to the
+// closing tag [3] of a property value
+func decodeWeeklySchedule(data []byte) (WeeklySchedule, error) {
+	var w WeeklySchedule
+	offset := 0
+
+	for day := 0; day < len(w); day++ {
+		tagNum, class, length, headerLen, err := DecodeTagNumber(data[offset:])
+		if err != nil || tagNum != 0 || class != TagClassContext || length != -1 {
+			return w, ErrInvalidResponse
+		}
+		offset += headerLen
+
+		for {
+			if offset >= len(data) {
+				return w, ErrInvalidResponse
+			}
+			tagNum, class, length, headerLen, err = DecodeTagNumber(data[offset:])
+			if err != nil {
+				return w, ErrInvalidResponse
+			}
+			if class == TagClassContext && tagNum == 0 && length == -2 {
+				offset += headerLen
+				break
+			}
+			if class != TagClassApplication || ApplicationTag(tagNum) != TagTime || length != 4 ||
+				offset+headerLen+length > len(data) {
+				return w, ErrInvalidResponse
+			}
+			t := data[offset+headerLen:]
+			tv := TimeValue{Time: TimeOfDay{Hour: t[0], Minute: t[1], Second: t[2], Hundredths: t[3]}}
+			offset += headerLen + length
+
+			if offset >= len(data) {
+				return w, ErrInvalidResponse
+			}
+			tv.Value, headerLen, err = decodeScheduleValue(data[offset:])
+			if err != nil {
+				return w, err
+			}
+			offset += headerLen
+
+			w[day] = append(w[day], tv)
+		}
+	}
+
+	return w, nil
+}
+
+// decodeScheduleValue decodes the application-tagged value of a time-value,
+// keeping enumerated values as Enumerated so that they are written back
+// unchanged. It returns the value and its encoded length.
+func decodeScheduleValue(data []byte) (interface{}, int, error) {
+	tagNum, class, length, headerLen, err := DecodeTagNumber(data)
+	if err != nil || class != TagClassApplication {
+		return nil, 0, ErrInvalidResponse
+	}
+
+	switch ApplicationTag(tagNum) {
+	case TagBoolean:
+		return length == 1, headerLen, nil
+	}
+
+	if headerLen+length > len(data) {
+		return nil, 0, ErrInvalidResponse
+	}
+	valueData := data[headerLen : headerLen+length]
+
+	switch ApplicationTag(tagNum) {
+	case TagNull:
+		return nil, headerLen, nil
+	case TagUnsignedInt:
+		return DecodeUnsigned(valueData), headerLen + length, nil
+	case TagSignedInt:
+		return DecodeSigned(valueData), headerLen + length, nil
+	case TagReal:
+		return DecodeReal(valueData), headerLen + length, nil
+	case TagDouble:
+		return DecodeDouble(valueData), headerLen + length, nil
+	case TagEnumerated:
+		return Enumerated(DecodeUnsigned(valueData)), headerLen + length, nil
+	case TagCharacterString:
+		return DecodeCharacterString(valueData), headerLen + length, nil
+	default:
+		return nil, 0, NewBACnetError(ErrorClassProperty, ErrorCodeDatatypeNotSupported)
+	}
+}
+
+// ReadWeeklySchedule reads the weekly-schedule of a Schedule object
+func (c *Client) ReadWeeklySchedule(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) (WeeklySchedule, error) {
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return WeeklySchedule{}, err
+	}
+
+	var e Encoder
+	e.ContextObjectIdentifier(0, objectID)
+	e.ContextEnumerated(1, uint32(PropertyWeeklySchedule))
+
+	resp, err := c.sendRequest(ctx, deviceID, addr, ServiceReadProperty, e.Bytes())
+	if err != nil {
+		return WeeklySchedule{}, err
+	}
+
+	// Skip object identifier [0] and property identifier [1]
+	offset := 0
+	for _, expected := range []uint8{0, 1} {
+		if offset >= len(resp.Data) {
+			return WeeklySchedule{}, ErrInvalidResponse
+		}
+		tagNum, class, length, headerLen, err := DecodeTagNumber(resp.Data[offset:])
+		if err != nil || tagNum != expected || class != TagClassContext || length < 0 {
+			return WeeklySchedule{}, ErrInvalidResponse
+		}
+		offset += headerLen + length
+	}
+
+	if offset >= len(resp.Data) || resp.Data[offset] != EncodeOpeningTag(3)[0] {
+		return WeeklySchedule{}, ErrInvalidResponse
+	}
+	return decodeWeeklySchedule(resp.Data[offset+1:])
+}
+
+// WriteWeeklySchedule writes the weekly-schedule of a Schedule object
+func (c *Client) WriteWeeklySchedule(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, schedule WeeklySchedule) error {
+	return c.WriteProperty(ctx, deviceID, objectID, PropertyWeeklySchedule, schedule)
+}
+
+// Date is a BACnet date. Year counts from 1900; fields set to 255 are
+// unspecified and match any date. Month also takes 13 (odd months) and 14
+// (even months), Day 32 (last day of the month), 33 (odd days) and 34 (even
+// days). Weekday runs from 1 (Monday) to 7 (Sunday).
+type Date struct {
+	Year    uint8
+	Month   uint8
+	Day     uint8
+	Weekday uint8
+}
+
+// ParseDate parses a date in the form "2006-01-02", where "*" leaves a field
+// unspecified, e.g. "*-12-25" for every Christmas
+func ParseDate(s string) (Date, error) {
+	parts := strings.Split(strings.TrimSpace(s), "-")
+	if len(parts) != 3 {
+		return Date{}, fmt.Errorf("invalid date %q", s)
+	}
+
+	limits := [][2]int{{1900, 2154}, {1, 14}, {1, 34}}
+	var fields [3]int
+	for i, p := range parts {
+		if p == "*" {
+			fields[i] = -1
+			continue
+		}
+		n, err := strconv.Atoi(p)
+		if err != nil || n < limits[i][0] || n > limits[i][1] {
+			return Date{}, fmt.Errorf("invalid date %q", s)
+		}
+		fields[i] = n
+	}
+
+	date := Date{Year: 0xFF, Month: 0xFF, Day: 0xFF, Weekday: 0xFF}
+	if fields[0] >= 0 {
+		date.Year = uint8(fields[0] - 1900)
+	}
+	if fields[1] >= 0 {
+		date.Month = uint8(fields[1])
+	}
+	if fields[2] >= 0 {
+		date.Day = uint8(fields[2])
+	}
+	// A fully specified date is given its weekday
+	if fields[0] >= 0 && fields[1] <= 12 && fields[1] >= 1 && fields[2] >= 1 && fields[2] <= 31 {
+		t := time.Date(fields[0], time.Month(fields[1]), fields[2], 0, 0, 0, 0, time.UTC)
+		if t.Day() != fields[2] {
+			return Date{}, fmt.Errorf("invalid date %q", s)
+		}
+		date.Weekday = uint8(WeekdayIndex(t.Weekday()) + 1)
+	}
+	return date, nil
+}
+
+// String returns the date as "2006-01-02", with "*" for unspecified fields
+func (d Date) String() string {
+	field := func(v uint8, format string, offset int) string {
+		if v == 0xFF {
+			return "*"
+		}
+		return fmt.Sprintf(format, int(v)+offset)
+	}
+	return field(d.Year, "%04d", 1900) + "-" + field(d.Month, "%02d", 0) + "-" + field(d.Day, "%02d", 0)
+}
+
+// encode appends the application-tagged date
+func (d Date) encode(e *Encoder) {
+	e.Tag(uint8(TagDate), TagClassApplication, 4)
+	e.Raw([]byte{d.Year, d.Month, d.Day, d.Weekday})
+}
+
+// decodeDate decodes an application-tagged date
+func decodeDate(d *Decoder) Date {
+	data := d.application(TagDate, 4, 4)
+	if len(data) != 4 {
+		return Date{}
+	}
+	return Date{Year: data[0], Month: data[1], Day: data[2], Weekday: data[3]}
+}
+
+// DateRange is an inclusive range of dates
+type DateRange struct {
+	Start Date
+	End   Date
+}
+
+// String returns the range as "start..end"
+func (r DateRange) String() string {
+	return r.Start.String() + ".." + r.End.String()
+}
+
+// WeekNDay matches days by month, week of the month and weekday. Fields set
+// to 255 match any value. Month also takes 13 (odd months) and 14 (even
+// months); WeekOfMonth runs from 1 (days 1-7) to 5 (days 29-31), and 6 to 9
+// count weeks from the end of the month; DayOfWeek runs from 1 (Monday) to
+// 7 (Sunday).
+type WeekNDay struct {
+	Month       uint8
+	WeekOfMonth uint8
+	DayOfWeek   uint8
+}
+
+// String returns the pattern as "month/week/weekday", with "*" for any
+func (w WeekNDay) String() string {
+	field := func(v uint8) string {
+		if v == 0xFF {
+			return "*"
+		}
+		return strconv.Itoa(int(v))
+	}
+	return field(w.Month) + "/" + field(w.WeekOfMonth) + "/" + field(w.DayOfWeek)
+}
+
+// CalendarEntry is a BACnetCalendarEntry, which is either a date, a date
+// range or a week-and-day pattern
+type CalendarEntry struct {
+	// Date is set for single dates
+	Date *Date
+	// DateRange is set for date ranges
+	DateRange *DateRange
+	// WeekNDay is set for week-and-day patterns
+	WeekNDay *WeekNDay
+}
+
+func (c CalendarEntry) String() string {
+	switch {
+	case c.Date != nil:
+		return c.Date.String()
+	case c.DateRange != nil:
+		return c.DateRange.String()
+	case c.WeekNDay != nil:
+		return c.WeekNDay.String()
+	default:
+		return "none"
+	}
+}
+
+// encode appends the calendar entry
+func (c CalendarEntry) encode(e *Encoder) error {
+	switch {
+	case c.Date != nil:
+		e.Tag(0, TagClassContext, 4)
+		e.Raw([]byte{c.Date.Year, c.Date.Month, c.Date.Day, c.Date.Weekday})
+	case c.DateRange != nil:
+		e.Opening(1)
+		c.DateRange.Start.encode(e)
+		c.DateRange.End.encode(e)
+		e.Closing(1)
+	case c.WeekNDay != nil:
+		e.ContextOctetString(2, []byte{c.WeekNDay.Month, c.WeekNDay.WeekOfMonth, c.WeekNDay.DayOfWeek})
+	default:
+		return fmt.Errorf("empty calendar entry")
+	}
+	return nil
+}
+
+// decodeCalendarEntry decodes a BACnetCalendarEntry
+func decodeCalendarEntry(d *Decoder) CalendarEntry {
+	var c CalendarEntry
+	switch {
+	case d.IsContext(0):
+		data := d.context(0, 4, 4)
+		if len(data) == 4 {
+			c.Date = &Date{Year: data[0], Month: data[1], Day: data[2], Weekday: data[3]}
+		}
+	case d.IsOpening(1):
+		d.Opening(1)
+		c.DateRange = &DateRange{Start: decodeDate(d), End: decodeDate(d)}
+		d.Closing(1)
+	case d.IsContext(2):
+		data := d.context(2, 3, 3)
+		if len(data) == 3 {
+			c.WeekNDay = &WeekNDay{Month: data[0], WeekOfMonth: data[1], DayOfWeek: data[2]}
+		}
+	default:
+		d.failf("expected calendar entry")
+	}
+	return c
+}
+
+// SpecialEvent is an entry of the exception-schedule of a Schedule object:
+// time-values applying on the days of a calendar entry or of a Calendar
+// object, taking precedence over the weekly schedule
+type SpecialEvent struct {
+	// Period is set for events applying on a calendar entry
+	Period *CalendarEntry
+	// CalendarReference is set for events applying on the days of a
+	// Calendar object
+	CalendarReference *ObjectIdentifier
+
+	TimeValues DailySchedule
+
+	// Priority ranks overlapping events, from 1 (highest) to 16
+	Priority uint8
+}
+
+// ExceptionSchedule is the exception-schedule property of a Schedule object
+type ExceptionSchedule []SpecialEvent
+
+// encode encodes the schedule as an array of BACnetSpecialEvent
+func (x ExceptionSchedule) encode(e *Encoder) error {
+	for i, ev := range x {
+		if ev.Priority < 1 || ev.Priority > 16 {
+			return fmt.Errorf("special event %d: priority %d out of range 1-16", i+1, ev.Priority)
+		}
+
+		switch {
+		case ev.Period != nil:
+			e.Opening(0)
+			if err := ev.Period.encode(e); err != nil {
+				return fmt.Errorf("special event %d: %w", i+1, err)
+			}
+			e.Closing(0)
+		case ev.CalendarReference != nil:
+			e.ContextObjectIdentifier(1, *ev.CalendarReference)
+		default:
+			return fmt.Errorf("special event %d: no period", i+1)
+		}
+
+		e.Opening(2)
+		for _, tv := range ev.TimeValues {
+			e.Time(tv.Time)
+			if err := e.Value(tv.Value); err != nil {
+				return fmt.Errorf("special event %d: %s: %w", i+1, tv.Time, err)
+			}
+		}
+		e.Closing(2)
+		e.ContextUnsigned(3, uint32(ev.Priority))
+	}
+	return nil
+}
+
+// decodeExceptionSchedule decodes an array of BACnetSpecialEvent up to the
+// closing tag [3] of a property value
+func decodeExceptionSchedule(d *Decoder) ExceptionSchedule {
+	var x ExceptionSchedule
+	for d.Err() == nil && !d.IsClosing(3) {
+		var ev SpecialEvent
+		switch {
+		case d.IsOpening(0):
+			d.Opening(0)
+			period := decodeCalendarEntry(d)
+			ev.Period = &period
+			d.Closing(0)
+		case d.IsContext(1):
+			ref := d.ContextObjectIdentifier(1)
+			ev.CalendarReference = &ref
+		default:
+			d.failf("expected special event period")
+			return nil
+		}
+
+		d.Opening(2)
+		for d.Err() == nil && !d.IsClosing(2) {
+			tv := TimeValue{Time: d.Time()}
+			if d.Err() != nil {
+				break
+			}
+			value, n, err := decodeScheduleValue(d.Rest())
+			if err != nil {
+				d.Fail(err)
+				break
+			}
+			d.offset += n
+			tv.Value = value
+			ev.TimeValues = append(ev.TimeValues, tv)
+		}
+		d.Closing(2)
+
+		ev.Priority = uint8(d.ContextUnsigned(3))
+		x = append(x, ev)
+	}
+	return x
+}
+
+// ReadExceptionSchedule reads the exception-schedule of a Schedule object
+func (c *Client) ReadExceptionSchedule(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) (ExceptionSchedule, error) {
+	addr, err := c.resolveDevice(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var e Encoder
+	e.ContextObjectIdentifier(0, objectID)
+	e.ContextEnumerated(1, uint32(PropertyExceptionSchedule))
+
+	resp, err := c.sendRequest(ctx, deviceID, addr, ServiceReadProperty, e.Bytes())
+	if err != nil {
+		return nil, err
+	}
+
+	d := NewDecoder(resp.Data)
+	d.ContextObjectIdentifier(0)
+	d.ContextEnumerated(1)
+	d.Opening(3)
+	x := decodeExceptionSchedule(d)
+	d.Closing(3)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return x, nil
+}
+
+// WriteExceptionSchedule writes the exception-schedule of a Schedule object
+func (c *Client) WriteExceptionSchedule(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, schedule ExceptionSchedule) error {
+	return c.WriteProperty(ctx, deviceID, objectID, PropertyExceptionSchedule, schedule)
+}
+
+// ScheduleTarget is a Schedule object on a device
+type ScheduleTarget struct {
+	DeviceID uint32
+	ObjectID ObjectIdentifier
+}
+
+// String returns the target as "device/object"
+func (t ScheduleTarget) String() string {
+	return fmt.Sprintf("%d/%s", t.DeviceID, t.ObjectID)
+}
+
+// ScheduleRolloutResult is the outcome of a schedule rollout on one target
+type ScheduleRolloutResult struct {
+	Target ScheduleTarget
+
+	// Err is the error reading or writing the schedule, if any
+	Err error
+
+	// RolledBack is set when the previous schedule was restored after
+	// another target failed; RollbackErr is the error restoring it
+	RolledBack  bool
+	RollbackErr error
+}
+
+// RolloutSchedule writes one weekly schedule to many Schedule objects. The
+// current schedule of every target is read first; if any target fails and
+// rollback is enabled, the targets already written are restored. It returns
+// the per-target results and ErrRolloutFailed if any target failed.
+func (c *Client) RolloutSchedule(ctx context.Context, schedule WeeklySchedule, targets []ScheduleTarget, opts ...RolloutOption) ([]ScheduleRolloutResult, error) {
+	options := defaultRolloutOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	if c.isStandby() {
+		return nil, ErrStandby
+	}
+
+	// Fail before touching any device if the schedule cannot be encoded
+	if err := schedule.encode(&Encoder{}); err != nil {
+		return nil, fmt.Errorf("encode schedule: %w", err)
+	}
+
+	results := make([]ScheduleRolloutResult, len(targets))
+	previous := make([]WeeklySchedule, len(targets))
+	written := make([]bool, len(targets))
+
+	sem := make(chan struct{}, options.Concurrency)
+	var wg sync.WaitGroup
+
+	for i, target := range targets {
+		results[i].Target = target
+
+		wg.Add(1)
+		go func(i int, target ScheduleTarget) {
+			defer wg.Done()
+			sem <- struct{}{}
+			defer func() { <-sem }()
+
+			if options.Rollback {
+				prev, err := c.ReadWeeklySchedule(ctx, target.DeviceID, target.ObjectID)
+				if err != nil {
+					results[i].Err = fmt.Errorf("read current schedule: %w", err)
+					return
+				}
+				previous[i] = prev
+			}
+
+			if err := c.WriteWeeklySchedule(ctx, target.DeviceID, target.ObjectID, schedule); err != nil {
+				results[i].Err = err
+				return
+			}
+			written[i] = true
+		}(i, target)
+	}
+	wg.Wait()
+
+	failed := 0
+	for _, r := range results {
+		if r.Err != nil {
+			failed++
+		}
+	}
+	if failed == 0 {
+		return results, nil
+	}
+
+	if options.Rollback {
+		for i, target := range targets {
+			if !written[i] {
+				continue
+			}
+			// The rollout context may be what failed; restore regardless
+			rollbackCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), c.opts.timeout*time.Duration(c.opts.retries+1))
+			err := c.WriteWeeklySchedule(rollbackCtx, target.DeviceID, target.ObjectID, previous[i])
+			cancel()
+
+			results[i].RolledBack = err == nil
+			results[i].RollbackErr = err
+			if err != nil {
+				c.logger.Error("schedule rollback failed",
+					slog.String("target", target.String()),
+					slog.String("error", err.Error()),
+				)
+			}
+		}
+	}
+
+	return results, fmt.Errorf("%w: %d of %d targets", ErrRolloutFailed, failed, len(targets))
+}
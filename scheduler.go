@@ -0,0 +1,224 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// CronJob is a property write executed on a cron schedule, e.g. writing
+// occupied to an occupancy point at 06:00 on weekdays
+type CronJob struct {
+	// Name identifies the job in audit records
+	Name string
+
+	// Schedule is a five-field cron expression ("0 6 * * mon-fri") or one
+	// of the macros @hourly, @daily, @weekly, @monthly and @yearly
+	Schedule string
+
+	DeviceID   uint32
+	ObjectID   ObjectIdentifier
+	PropertyID PropertyIdentifier
+	Value      interface{}
+
+	// Priority is the write priority (1-16), 0 for none
+	Priority uint8
+}
+
+// CronAudit records the execution of a CronJob
+type CronAudit struct {
+	Job      CronJob
+	Time     time.Time
+	Duration time.Duration
+	Err      error
+}
+
+// scheduledJob is a CronJob with its parsed schedule
+type scheduledJob struct {
+	job      CronJob
+	schedule *cronSchedule
+	next     time.Time
+}
+
+// Scheduler executes CronJobs through a client, providing simple
+// supervisory scheduling. Every execution is logged and passed to the audit
+// handler, if any.
+type Scheduler struct {
+	client *Client
+	opts   *schedulerOptions
+
+	mu   sync.Mutex
+	jobs []*scheduledJob
+	wake chan struct{}
+}
+
+// NewScheduler creates a scheduler that writes through client
+func NewScheduler(client *Client, opts ...SchedulerOption) *Scheduler {
+	options := defaultSchedulerOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+	if options.logger == nil {
+		options.logger = client.logger
+	}
+
+	return &Scheduler{
+		client: client,
+		opts:   options,
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+// Add adds a job to the scheduler. It may be called while the scheduler runs.
+func (s *Scheduler) Add(job CronJob) error {
+	schedule, err := parseCron(job.Schedule)
+	if err != nil {
+		return err
+	}
+	if job.Priority > 16 {
+		return fmt.Errorf("job %q: priority must be between 1 and 16", job.Name)
+	}
+
+	sj := &scheduledJob{
+		job:      job,
+		schedule: schedule,
+		next:     schedule.next(s.client.opts.clock.Now().In(s.opts.location)),
+	}
+	if sj.next.IsZero() {
+		return fmt.Errorf("job %q: schedule %q never fires", job.Name, job.Schedule)
+	}
+
+	s.mu.Lock()
+	s.jobs = append(s.jobs, sj)
+	s.mu.Unlock()
+
+	// Recompute the next wake-up
+	select {
+	case s.wake <- struct{}{}:
+	default:
+	}
+	return nil
+}
+
+// Next returns the next activation time of each job by name
+func (s *Scheduler) Next() map[string]time.Time {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	next := make(map[string]time.Time, len(s.jobs))
+	for _, sj := range s.jobs {
+		next[sj.job.Name] = sj.next
+	}
+	return next
+}
+
+// Run executes jobs as they become due until ctx is cancelled
+func (s *Scheduler) Run(ctx context.Context) error {
+	for {
+		s.mu.Lock()
+		var wait time.Duration = -1
+		now := s.client.opts.clock.Now()
+		for _, sj := range s.jobs {
+			if d := sj.next.Sub(now); wait < 0 || d < wait {
+				wait = d
+			}
+		}
+		s.mu.Unlock()
+
+		// Without jobs, sleep until one is added
+		var (
+			timer Timer
+			fire  <-chan time.Time
+		)
+		if wait >= 0 {
+			timer = s.client.opts.clock.NewTimer(wait)
+			fire = timer.C()
+		}
+
+		select {
+		case <-ctx.Done():
+			if timer != nil {
+				timer.Stop()
+			}
+			return ctx.Err()
+		case <-s.wake:
+			if timer != nil {
+				timer.Stop()
+			}
+		case <-fire:
+			s.runDue(ctx, s.client.opts.clock.Now())
+		}
+	}
+}
+
+// runDue executes the jobs due at now and schedules their next activation
+func (s *Scheduler) runDue(ctx context.Context, now time.Time) {
+	var due []CronJob
+
+	s.mu.Lock()
+	for _, sj := range s.jobs {
+		if !sj.next.After(now) {
+			due = append(due, sj.job)
+			sj.next = sj.schedule.next(now.In(s.opts.location))
+		}
+	}
+	s.mu.Unlock()
+
+	for _, job := range due {
+		s.execute(ctx, job)
+	}
+}
+
+// execute performs the write of a job and audits the result
+func (s *Scheduler) execute(ctx context.Context, job CronJob) {
+	var opts []WriteOption
+	if job.Priority > 0 {
+		opts = append(opts, WithPriority(job.Priority))
+	}
+
+	start := s.client.opts.clock.Now()
+	writeCtx, cancel := context.WithTimeout(ctx, s.client.opts.timeout)
+	err := s.client.WriteProperty(writeCtx, job.DeviceID, job.ObjectID, job.PropertyID, job.Value, opts...)
+	cancel()
+
+	record := CronAudit{
+		Job:      job,
+		Time:     start,
+		Duration: s.client.opts.clock.Now().Sub(start),
+		Err:      err,
+	}
+
+	attrs := []any{
+		slog.String("job", job.Name),
+		slog.Uint64("device", uint64(job.DeviceID)),
+		slog.String("object", job.ObjectID.String()),
+		slog.String("property", job.PropertyID.String()),
+		slog.Any("value", job.Value),
+		slog.Int("priority", int(job.Priority)),
+	}
+	if err != nil {
+		s.opts.logger.Warn("scheduled write failed", append(attrs, slog.String("error", err.Error()))...)
+	} else {
+		s.opts.logger.Info("scheduled write", attrs...)
+	}
+
+	if s.opts.audit != nil {
+		s.opts.audit(record)
+	}
+}
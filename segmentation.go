@@ -0,0 +1,211 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"bytes"
+	"log/slog"
+	"net"
+)
+
+// maxResponseSegments is the number of segments of a response the client
+// accepts, advertised in its confirmed requests
+const maxResponseSegments = 64
+
+// segmentedResponseAccepted is the SA bit of a confirmed request
+const segmentedResponseAccepted = 0x02
+
+// segmentedResponse reassembles a segmented ComplexACK. The first segment
+// is acknowledged on its own, as the server waits for the window size of
+// the client, then segments are acknowledged once per window; those
+// received ahead of a missing one wait in ahead until it arrives.
+type segmentedResponse struct {
+	service     uint8
+	window      uint8
+	windowStart uint8
+	next        uint8
+	count       int
+	ahead       map[uint8]*APDU
+	data        []byte
+}
+
+// acceptsSegments reports whether the client accepts segmented responses
+func (c *Client) acceptsSegments() bool {
+	return c.opts.segmentation == SegmentationBoth || c.opts.segmentation == SegmentationReceive
+}
+
+// EncodeMaxSegments converts a number of segments to the
+// max-segments-accepted field of a confirmed request
+func EncodeMaxSegments(segments int) uint8 {
+	switch {
+	case segments > 64:
+		return 7
+	case segments == 64:
+		return 6
+	case segments >= 32:
+		return 5
+	case segments >= 16:
+		return 4
+	case segments >= 8:
+		return 3
+	case segments >= 4:
+		return 2
+	case segments >= 2:
+		return 1
+	default:
+		return 0
+	}
+}
+
+// EncodeSegmentAck encodes a Segment-ACK APDU. nak requests the segments
+// following sequenceNum again; server is set when the server of the
+// transaction sends it.
+func EncodeSegmentAck(nak, server bool, invokeID, sequenceNum, windowSize uint8) []byte {
+	pduType := byte(PDUTypeSegmentAck)
+	if nak {
+		pduType |= 0x02
+	}
+	if server {
+		pduType |= 0x01
+	}
+	return []byte{pduType, invokeID, sequenceNum, windowSize}
+}
+
+// handleSegment handles a segment of a ComplexACK answering a pending
+// request. Complete responses are passed on as one unsegmented ComplexACK.
+// Without segmentation accepted, or past maxResponseSegments, the
+// transaction is aborted.
+func (c *Client) handleSegment(apdu *APDU, addr net.Addr, npdu *NPDU) {
+	// Another station may use the same invoke ID for its own transactions
+	c.pendingMu.RLock()
+	_, pending := c.pending[apdu.InvokeID]
+	to := c.pendingTo[apdu.InvokeID]
+	c.pendingMu.RUnlock()
+	if !pending || to != addr.String() {
+		return
+	}
+
+	if !c.acceptsSegments() {
+		c.abortSegmented(apdu, addr, npdu, AbortReasonSegmentationNotSupported)
+		return
+	}
+
+	c.segmentsMu.Lock()
+	seg := c.segments[apdu.InvokeID]
+	if seg == nil {
+		if apdu.SequenceNum != 0 {
+			c.segmentsMu.Unlock()
+			return
+		}
+		window := apdu.WindowSize
+		if c.opts.proposedWindowSize < window {
+			window = c.opts.proposedWindowSize
+		}
+		if window < 1 {
+			window = 1
+		} else if window > 127 {
+			window = 127
+		}
+		seg = &segmentedResponse{service: apdu.Service, window: window, ahead: make(map[uint8]*APDU)}
+		c.segments[apdu.InvokeID] = seg
+	}
+
+	// Sequence numbers wrap around: those within the window are ahead,
+	// others are duplicates of segments already acknowledged
+	if offset := apdu.SequenceNum - seg.windowStart; offset >= seg.window {
+		last := seg.windowStart - 1
+		c.segmentsMu.Unlock()
+		c.sendServerReply(addr, npdu, EncodeSegmentAck(false, false, apdu.InvokeID, last, seg.window))
+		return
+	}
+	// The receive buffer is recycled before the segments are reassembled
+	apdu.Data = bytes.Clone(apdu.Data)
+	seg.ahead[apdu.SequenceNum] = apdu
+
+	complete := false
+	for {
+		next, ok := seg.ahead[seg.next]
+		if !ok {
+			break
+		}
+		delete(seg.ahead, seg.next)
+		seg.data = append(seg.data, next.Data...)
+		seg.next++
+		seg.count++
+		if !next.MoreFollows {
+			complete = true
+			break
+		}
+	}
+	if seg.count > maxResponseSegments {
+		delete(c.segments, apdu.InvokeID)
+		c.segmentsMu.Unlock()
+		c.abortSegmented(apdu, addr, npdu, AbortReasonBufferOverflow)
+		return
+	}
+
+	var ack []byte
+	if complete || seg.next-seg.windowStart >= seg.window || (seg.windowStart == 0 && seg.next > 0) {
+		ack = EncodeSegmentAck(false, false, apdu.InvokeID, seg.next-1, seg.window)
+		seg.windowStart = seg.next
+	}
+	if complete {
+		delete(c.segments, apdu.InvokeID)
+	}
+	c.segmentsMu.Unlock()
+
+	if ack != nil {
+		c.sendServerReply(addr, npdu, ack)
+	}
+	if complete {
+		c.handleResponse(&APDU{
+			Type:     PDUTypeComplexAck,
+			InvokeID: apdu.InvokeID,
+			Service:  seg.service,
+			Data:     seg.data,
+		})
+	}
+}
+
+// abortSegmented aborts the transaction of a segmented response, and fails
+// the request waiting for it with the abort
+func (c *Client) abortSegmented(apdu *APDU, addr net.Addr, npdu *NPDU, reason AbortReason) {
+	c.logger.Debug("aborting segmented response",
+		slog.String("address", addr.String()),
+		slog.Uint64("invoke_id", uint64(apdu.InvokeID)),
+		slog.String("reason", reason.String()),
+	)
+	c.sendServerReply(addr, npdu, EncodeAbortAPDU(apdu.InvokeID, false, reason))
+	c.handleResponse(&APDU{
+		Type:     PDUTypeAbort,
+		InvokeID: apdu.InvokeID,
+		Service:  uint8(reason),
+	})
+}
+
+// receivingSegments reports whether segments of the response to an invoke
+// ID are being received
+func (c *Client) receivingSegments(invokeID uint8) bool {
+	c.segmentsMu.Lock()
+	defer c.segmentsMu.Unlock()
+	return c.segments[invokeID] != nil
+}
+
+// dropSegments forgets the segments received for an invoke ID
+func (c *Client) dropSegments(invokeID uint8) {
+	c.segmentsMu.Lock()
+	delete(c.segments, invokeID)
+	c.segmentsMu.Unlock()
+}
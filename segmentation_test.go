@@ -0,0 +1,239 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"bytes"
+	"context"
+	"net"
+	"reflect"
+	"sync"
+	"testing"
+)
+
+// recordingLink is a data link recording the NPDUs sent
+type recordingLink struct {
+	dataLink
+
+	mu   sync.Mutex
+	sent [][]byte
+}
+
+func (l *recordingLink) Send(_ context.Context, _ net.Addr, npdu []byte) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	l.sent = append(l.sent, bytes.Clone(npdu))
+	return nil
+}
+
+// apdus returns the APDUs of the NPDUs sent
+func (l *recordingLink) apdus(t *testing.T) [][]byte {
+	t.Helper()
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	var apdus [][]byte
+	for _, npdu := range l.sent {
+		_, offset, err := DecodeNPDU(npdu)
+		if err != nil {
+			t.Fatal(err)
+		}
+		apdus = append(apdus, npdu[offset:])
+	}
+	return apdus
+}
+
+// segment is a segment of a response and whether it is the last
+type segment struct {
+	seq  uint8
+	last bool
+}
+
+// inOrder returns the segments 0 to n-1, the last one final when last is set
+func inOrder(n int, last bool) []segment {
+	segments := make([]segment, n)
+	for i := range segments {
+		segments[i] = segment{seq: uint8(i)}
+	}
+	segments[n-1].last = last
+	return segments
+}
+
+func TestHandleSegment(t *testing.T) {
+	device := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 10), Port: 47808}
+	other := &net.UDPAddr{IP: net.IPv4(192, 168, 1, 11), Port: 47808}
+
+	overLimit := inOrder(maxResponseSegments+1, false)
+	overLimitAcks := make([]uint8, maxResponseSegments)
+	for i := range overLimitAcks {
+		overLimitAcks[i] = uint8(i)
+	}
+
+	tests := []struct {
+		name     string
+		window   uint8
+		from     net.Addr
+		refuse   bool
+		segments []segment
+
+		// acks are the sequence numbers acknowledged, in order
+		acks []uint8
+		// data is the reassembled response, nil if none is expected
+		data []byte
+		// abort is the reason the transaction is aborted with
+		abort AbortReason
+	}{
+		{
+			name:     "in order",
+			window:   1,
+			segments: inOrder(3, true),
+			acks:     []uint8{0, 1, 2},
+			data:     []byte{0, 1, 2},
+		},
+		{
+			name:     "out of order",
+			window:   4,
+			segments: []segment{{seq: 0}, {seq: 2}, {seq: 3, last: true}, {seq: 1}},
+			acks:     []uint8{0, 3},
+			data:     []byte{0, 1, 2, 3},
+		},
+		{
+			name:     "duplicate",
+			window:   1,
+			segments: []segment{{seq: 0}, {seq: 1}, {seq: 1}, {seq: 2, last: true}},
+			acks:     []uint8{0, 1, 1, 2},
+			data:     []byte{0, 1, 2},
+		},
+		{
+			name:     "duplicate ahead",
+			window:   4,
+			segments: []segment{{seq: 0}, {seq: 2}, {seq: 2}, {seq: 1, last: true}},
+			acks:     []uint8{0, 1},
+			data:     []byte{0, 1},
+		},
+		{
+			name:     "window",
+			window:   2,
+			segments: inOrder(5, true),
+			acks:     []uint8{0, 2, 4},
+			data:     []byte{0, 1, 2, 3, 4},
+		},
+		{
+			name:     "incomplete window",
+			window:   4,
+			segments: []segment{{seq: 0}, {seq: 1}, {seq: 3}},
+			acks:     []uint8{0},
+		},
+		{
+			name:     "first segment missing",
+			window:   1,
+			segments: []segment{{seq: 1}, {seq: 2, last: true}},
+		},
+		{
+			name:     "over the segment limit",
+			window:   1,
+			segments: overLimit,
+			acks:     overLimitAcks,
+			abort:    AbortReasonBufferOverflow,
+		},
+		{
+			name:     "segmentation not accepted",
+			window:   1,
+			refuse:   true,
+			segments: []segment{{seq: 0}},
+			abort:    AbortReasonSegmentationNotSupported,
+		},
+		{
+			name:     "other station",
+			window:   1,
+			from:     other,
+			segments: inOrder(2, true),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			segmentation := SegmentationReceive
+			if tt.refuse {
+				segmentation = SegmentationNone
+			}
+			c, err := NewClient(WithSegmentation(segmentation), WithProposedWindowSize(tt.window))
+			if err != nil {
+				t.Fatal(err)
+			}
+			link := &recordingLink{}
+			c.link = link
+			c.state.Store(int32(StateConnected))
+
+			respCh := make(chan *APDU, 1)
+			invokeID, err := c.allocInvokeID(respCh)
+			if err != nil {
+				t.Fatal(err)
+			}
+			c.pendingTo[invokeID] = device.String()
+
+			from := tt.from
+			if from == nil {
+				from = device
+			}
+			for _, s := range tt.segments {
+				// The receive buffer is reused by the next packet
+				buf := []byte{s.seq}
+				c.handleSegment(&APDU{
+					Type:        PDUTypeComplexAck,
+					Segmented:   true,
+					MoreFollows: !s.last,
+					InvokeID:    invokeID,
+					SequenceNum: s.seq,
+					WindowSize:  tt.window,
+					Service:     uint8(ServiceReadProperty),
+					Data:        buf,
+				}, from, &NPDU{Version: 1})
+				buf[0] = 0xFF
+			}
+
+			var want [][]byte
+			for _, seq := range tt.acks {
+				want = append(want, EncodeSegmentAck(false, false, invokeID, seq, tt.window))
+			}
+			if tt.abort != 0 {
+				want = append(want, EncodeAbortAPDU(invokeID, false, tt.abort))
+			}
+			if got := link.apdus(t); !reflect.DeepEqual(got, want) {
+				t.Errorf("sent %x, want %x", got, want)
+			}
+
+			var resp *APDU
+			select {
+			case resp = <-respCh:
+			default:
+			}
+			switch {
+			case tt.abort != 0:
+				if resp == nil || resp.Type != PDUTypeAbort || AbortReason(resp.Service) != tt.abort {
+					t.Fatalf("response = %+v, want abort %s", resp, tt.abort)
+				}
+			case tt.data != nil:
+				if resp == nil || resp.Type != PDUTypeComplexAck || resp.Segmented {
+					t.Fatalf("response = %+v, want a complete ComplexACK", resp)
+				}
+				if !bytes.Equal(resp.Data, tt.data) {
+					t.Errorf("data = %x, want %x", resp.Data, tt.data)
+				}
+			case resp != nil:
+				t.Fatalf("unexpected response %+v", resp)
+			}
+		})
+	}
+}
@@ -0,0 +1,377 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"math"
+	"net"
+	"reflect"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// selfTestDeviceID is the instance of the loopback responder
+const selfTestDeviceID = 4194302
+
+// selfTestCOVWait bounds the wait for a COV notification
+const selfTestCOVWait = time.Second
+
+// SelfTestCheck is the outcome of one self-test check
+type SelfTestCheck struct {
+	Name     string
+	Duration time.Duration
+	Err      error
+}
+
+// SelfTestReport is the outcome of a self-test
+type SelfTestReport struct {
+	Checks []SelfTestCheck
+}
+
+// Passed returns true if every check succeeded
+func (r *SelfTestReport) Passed() bool {
+	for _, check := range r.Checks {
+		if check.Err != nil {
+			return false
+		}
+	}
+	return true
+}
+
+// SelfTest exercises the encoders, discovery, reads, writes and COV
+// notifications end-to-end against an internal responder. Both ends are
+// connected by an in-memory link, so nothing is sent on the network and the
+// client's own connection is not used. It returns ErrSelfTestFailed if any
+// check fails; the report details every check.
+func (c *Client) SelfTest(ctx context.Context) (*SelfTestReport, error) {
+	report := &SelfTestReport{}
+
+	run := func(name string, check func() error) bool {
+		start := time.Now()
+		err := check()
+		report.Checks = append(report.Checks, SelfTestCheck{
+			Name:     name,
+			Duration: time.Since(start),
+			Err:      err,
+		})
+		return err == nil
+	}
+
+	run("encode/decode", c.selfTestCodec)
+
+	value := NewAnalogValue(1, "SELF-TEST", WithCommandable(), WithObjectCOVIncrement(0))
+	registry := NewObjectRegistry()
+	if err := registry.Add(value); err != nil {
+		return nil, fmt.Errorf("self-test: %w", err)
+	}
+	device := NewDevice(selfTestDeviceID,
+		WithDeviceName("self-test"),
+		WithObjectDatabase(registry),
+	)
+
+	responderLink, probeLink := newPipeLinks("responder", "probe")
+	responder, err := c.newSelfTestClient(responderLink, WithLocalDevice(device))
+	if err != nil {
+		return nil, fmt.Errorf("self-test: %w", err)
+	}
+	probe, err := c.newSelfTestClient(probeLink)
+	if err != nil {
+		return nil, fmt.Errorf("self-test: %w", err)
+	}
+
+	if !run("connect", func() error {
+		if err := responder.Connect(ctx); err != nil {
+			return err
+		}
+		return probe.Connect(ctx)
+	}) {
+		responder.Close()
+		return report, c.selfTestResult(report)
+	}
+	defer responder.Close()
+	defer probe.Close()
+
+	if !run("discovery", func() error {
+		devices, err := probe.WhoIs(ctx,
+			WithDeviceRange(selfTestDeviceID, selfTestDeviceID),
+			WithDiscoveryTimeout(200*time.Millisecond),
+		)
+		if err != nil {
+			return err
+		}
+		if len(devices) != 1 || devices[0].ObjectID.Instance != selfTestDeviceID {
+			return fmt.Errorf("found %d devices, want the responder", len(devices))
+		}
+		return nil
+	}) {
+		return report, c.selfTestResult(report)
+	}
+
+	run("read", func() error {
+		name, err := probe.ReadProperty(ctx, selfTestDeviceID, device.ObjectID(), PropertyObjectName)
+		if err != nil {
+			return err
+		}
+		if name != "self-test" {
+			return fmt.Errorf("object name %v, want %q", name, "self-test")
+		}
+		results, err := probe.ReadPropertyMultiple(ctx, selfTestDeviceID, []ReadPropertyRequest{
+			{ObjectID: value.ObjectID(), PropertyID: PropertyObjectName},
+			{ObjectID: value.ObjectID(), PropertyID: PropertyPresentValue},
+		})
+		if err != nil {
+			return err
+		}
+		if len(results) != 2 {
+			return fmt.Errorf("read multiple returned %d values, want 2", len(results))
+		}
+		for _, r := range results {
+			if r.Err != nil {
+				return fmt.Errorf("read multiple %s: %w", r.PropertyID, r.Err)
+			}
+		}
+		return nil
+	})
+
+	run("write", func() error {
+		if err := probe.WriteProperty(ctx, selfTestDeviceID, value.ObjectID(), PropertyPresentValue, float32(21.5), WithPriority(8)); err != nil {
+			return err
+		}
+		got, err := probe.ReadProperty(ctx, selfTestDeviceID, value.ObjectID(), PropertyPresentValue)
+		if err != nil {
+			return err
+		}
+		if got != float32(21.5) {
+			return fmt.Errorf("read back %v, want 21.5", got)
+		}
+		return probe.WriteProperty(ctx, selfTestDeviceID, value.ObjectID(), PropertyPresentValue, nil, WithPriority(8))
+	})
+
+	run("cov", func() error {
+		received := probe.metrics.COVNotifications.Value()
+		subID, err := probe.SubscribeCOV(ctx, selfTestDeviceID, value.ObjectID(), func(uint32, ObjectIdentifier, []PropertyValue) {},
+			WithSubscriptionLifetime(60))
+		if err != nil {
+			return err
+		}
+		defer probe.UnsubscribeCOV(context.WithoutCancel(ctx), selfTestDeviceID, value.ObjectID(), subID)
+
+		// The initial notification follows the subscription
+		if err := waitForCount(ctx, &probe.metrics.COVNotifications, received+1); err != nil {
+			return fmt.Errorf("initial notification: %w", err)
+		}
+		if err := value.SetPresentValue(float32(42)); err != nil {
+			return err
+		}
+		if err := waitForCount(ctx, &probe.metrics.COVNotifications, received+2); err != nil {
+			return fmt.Errorf("change notification: %w", err)
+		}
+		return nil
+	})
+
+	return report, c.selfTestResult(report)
+}
+
+// selfTestResult returns ErrSelfTestFailed naming the first failed check
+func (c *Client) selfTestResult(report *SelfTestReport) error {
+	for _, check := range report.Checks {
+		if check.Err != nil {
+			c.logger.Warn("self-test failed",
+				slog.String("check", check.Name),
+				slog.String("error", check.Err.Error()),
+			)
+			return fmt.Errorf("%w: %s: %w", ErrSelfTestFailed, check.Name, check.Err)
+		}
+	}
+	return nil
+}
+
+// newSelfTestClient creates a quiet client on a loopback link, using the
+// timeouts and APDU settings of c
+func (c *Client) newSelfTestClient(link dataLink, opts ...Option) (*Client, error) {
+	opts = append([]Option{
+		WithLogger(slog.New(slog.NewTextHandler(io.Discard, nil))),
+		WithTimeout(c.opts.timeout),
+		WithMaxAPDULength(c.opts.maxAPDULength),
+	}, opts...)
+
+	client, err := NewClient(opts...)
+	if err != nil {
+		return nil, err
+	}
+	client.link = link
+	return client, nil
+}
+
+// selfTestCodec round-trips application values through the property
+// encoder and decoder
+func (c *Client) selfTestCodec() error {
+	values := []struct {
+		in   interface{}
+		want interface{}
+	}{
+		{nil, nil},
+		{true, true},
+		{false, false},
+		{uint32(0), uint32(0)},
+		{uint32(math.MaxUint32), uint32(math.MaxUint32)},
+		{int32(-40), int32(-40)},
+		{float32(21.5), float32(21.5)},
+		{float64(-1e10), float64(-1e10)},
+		{"Zone Temp °C", "Zone Temp °C"},
+		{[]byte{0x00, 0xFF}, []byte{0x00, 0xFF}},
+		{Enumerated(3), uint32(3)},
+		{NewObjectIdentifier(ObjectTypeAnalogInput, 4194303), NewObjectIdentifier(ObjectTypeAnalogInput, 4194303)},
+	}
+
+	for _, v := range values {
+		data, err := c.encodePropertyValue(v.in)
+		if err != nil {
+			return fmt.Errorf("encode %T: %w", v.in, err)
+		}
+		got, err := c.decodePropertyValue(data)
+		if err != nil {
+			return fmt.Errorf("decode %T: %w", v.in, err)
+		}
+		if !reflect.DeepEqual(got, v.want) {
+			return fmt.Errorf("%T round-trip: got %v, want %v", v.in, got, v.want)
+		}
+	}
+
+	request := EncodeConfirmedRequest(42, ServiceReadProperty, []byte{0x0C}, 0, 5)
+	apdu, err := DecodeAPDU(request)
+	if err != nil {
+		return fmt.Errorf("decode APDU: %w", err)
+	}
+	if apdu.Type != PDUTypeConfirmedRequest || apdu.InvokeID != 42 || apdu.Service != uint8(ServiceReadProperty) {
+		return errors.New("confirmed request APDU round-trip mismatch")
+	}
+	return nil
+}
+
+// waitForCount waits until counter reaches n
+func waitForCount(ctx context.Context, counter *Counter, n int64) error {
+	ctx, cancel := context.WithTimeout(ctx, selfTestCOVWait)
+	defer cancel()
+
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+
+	for counter.Value() < n {
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+	return nil
+}
+
+// pipeAddr is the address of one end of an in-memory link
+type pipeAddr string
+
+func (a pipeAddr) Network() string { return "pipe" }
+func (a pipeAddr) String() string  { return string(a) }
+
+// pipeLink is one end of an in-memory data link connecting two clients
+type pipeLink struct {
+	addr   pipeAddr
+	peer   *pipeLink
+	rx     chan linkPacket
+	done   chan struct{}
+	once   sync.Once
+	closed atomic.Bool
+}
+
+// newPipeLinks returns the two connected ends of an in-memory link
+func newPipeLinks(a, b string) (*pipeLink, *pipeLink) {
+	la := &pipeLink{addr: pipeAddr(a), rx: make(chan linkPacket, 64), done: make(chan struct{})}
+	lb := &pipeLink{addr: pipeAddr(b), rx: make(chan linkPacket, 64), done: make(chan struct{})}
+	la.peer, lb.peer = lb, la
+	return la, lb
+}
+
+func (l *pipeLink) Open(ctx context.Context) error {
+	return nil
+}
+
+func (l *pipeLink) Close() error {
+	l.once.Do(func() {
+		l.closed.Store(true)
+		close(l.done)
+	})
+	return nil
+}
+
+func (l *pipeLink) IsClosed() bool {
+	return l.closed.Load()
+}
+
+func (l *pipeLink) LocalAddr() net.Addr {
+	return l.addr
+}
+
+func (l *pipeLink) Send(ctx context.Context, addr net.Addr, npdu []byte) error {
+	if addr.String() != string(l.peer.addr) {
+		return fmt.Errorf("pipe: unknown address %v", addr)
+	}
+	return l.Broadcast(ctx, npdu)
+}
+
+func (l *pipeLink) Broadcast(ctx context.Context, npdu []byte) error {
+	if l.IsClosed() {
+		return net.ErrClosed
+	}
+	select {
+	case l.peer.rx <- linkPacket{npdu: append([]byte(nil), npdu...), from: l.addr}:
+		return nil
+	case <-l.peer.done:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l *pipeLink) Receive(timeout time.Duration) ([]byte, net.Addr, error) {
+	timer := time.NewTimer(timeout)
+	defer timer.Stop()
+
+	select {
+	case pkt := <-l.rx:
+		return pkt.npdu, pkt.from, nil
+	case <-l.done:
+		return nil, nil, net.ErrClosed
+	case <-timer.C:
+		return nil, nil, linkTimeoutError{}
+	}
+}
+
+func (l *pipeLink) MAC(addr net.Addr) []byte {
+	return []byte(addr.String())
+}
+
+func (l *pipeLink) Addr(mac []byte) (net.Addr, error) {
+	return pipeAddr(mac), nil
+}
+
+func (l *pipeLink) ParseAddr(s string) (net.Addr, error) {
+	return pipeAddr(s), nil
+}
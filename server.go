@@ -0,0 +1,776 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net"
+	"reflect"
+	"sync/atomic"
+)
+
+// ObjectDatabase supplies the objects served by a local Device
+type ObjectDatabase interface {
+	// Objects returns the identifiers of all objects in the database
+	Objects() []ObjectIdentifier
+
+	// ReadProperty returns the value of a property. Implementations should
+	// return a *BACnetError for unknown objects or properties. Array
+	// properties are returned as slices; the device applies array indexes.
+	ReadProperty(objectID ObjectIdentifier, propertyID PropertyIdentifier) (interface{}, error)
+}
+
+// PropertyLister is implemented by object databases that can enumerate the
+// properties of an object, enabling ReadPropertyMultiple with the special
+// property identifiers all, required and optional
+type PropertyLister interface {
+	Properties(objectID ObjectIdentifier) []PropertyIdentifier
+}
+
+// PropertyWriter is implemented by object databases that accept
+// WriteProperty requests. Priority is 0 when the request carries none.
+type PropertyWriter interface {
+	WriteProperty(objectID ObjectIdentifier, propertyID PropertyIdentifier, value interface{}, priority uint8, arrayIndex *uint32) error
+}
+
+// COVReporter is implemented by object databases that detect changes of
+// value, enabling SubscribeCOV from other devices
+type COVReporter interface {
+	OnCOV(listener COVListener)
+}
+
+// ServerRequest describes a confirmed request received by the local device
+type ServerRequest struct {
+	Service  ConfirmedServiceChoice
+	InvokeID uint8
+	Source   net.Addr
+
+	// Data is the service request, valid until the interceptor returns
+	Data []byte
+}
+
+// RequestInterceptor is called before the local device serves a confirmed
+// request. It may delay the request, e.g. to simulate a slow device. A
+// non-nil error is returned to the requester instead of serving the
+// request: a *BACnetError as an Error PDU, a *RejectError as a Reject PDU and
+// an *AbortError as an Abort PDU. ErrNoReply drops the request.
+type RequestInterceptor func(req *ServerRequest) error
+
+// Device is a local BACnet device. When attached to a client with
+// WithLocalDevice, the client answers Who-Is with I-Am and serves
+// ReadProperty and ReadPropertyMultiple requests from other BACnet devices
+// using the device's object database. WriteProperty is served when the
+// database implements PropertyWriter, and SubscribeCOV when it implements
+// COVReporter.
+type Device struct {
+	opts *deviceOptions
+
+	databaseRevision atomic.Uint32
+}
+
+// NewDevice creates a new local device with the given instance number
+func NewDevice(instance uint32, opts ...DeviceOption) *Device {
+	options := defaultDeviceOptions(instance)
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	return &Device{opts: options}
+}
+
+// ObjectID returns the device object identifier
+func (d *Device) ObjectID() ObjectIdentifier {
+	return NewObjectIdentifier(ObjectTypeDevice, d.opts.instance)
+}
+
+// Name returns the device object name
+func (d *Device) Name() string {
+	return d.opts.name
+}
+
+// VendorID returns the device vendor identifier
+func (d *Device) VendorID() uint16 {
+	return d.opts.vendorID
+}
+
+// Database returns the object database served by the device
+func (d *Device) Database() ObjectDatabase {
+	return d.opts.database
+}
+
+// IncrementDatabaseRevision increments the Database_Revision property. It
+// should be called whenever objects are added, removed or renamed.
+func (d *Device) IncrementDatabaseRevision() {
+	d.databaseRevision.Add(1)
+}
+
+// ObjectList returns the device object followed by the database objects
+func (d *Device) ObjectList() []ObjectIdentifier {
+	list := []ObjectIdentifier{d.ObjectID()}
+	if d.opts.database != nil {
+		list = append(list, d.opts.database.Objects()...)
+	}
+	return list
+}
+
+// deviceProperties lists the properties served for the device object
+var deviceProperties = []PropertyIdentifier{
+	PropertyObjectIdentifier,
+	PropertyObjectName,
+	PropertyObjectType,
+	PropertySystemStatus,
+	PropertyVendorName,
+	PropertyVendorIdentifier,
+	PropertyModelName,
+	PropertyFirmwareRevision,
+	PropertyApplicationSoftwareVersion,
+	PropertyDescription,
+	PropertyLocation,
+	PropertyProtocolVersion,
+	PropertyProtocolRevision,
+	PropertyProtocolServicesSupported,
+	PropertyProtocolObjectTypesSupported,
+	PropertyObjectList,
+	PropertyMaxApduLengthAccepted,
+	PropertySegmentationSupported,
+	PropertyApduTimeout,
+	PropertyNumberOfApduRetries,
+	PropertyDeviceAddressBinding,
+	PropertyDatabaseRevision,
+}
+
+// readDeviceProperty reads a property of the device object
+func (d *Device) readDeviceProperty(propertyID PropertyIdentifier) (interface{}, error) {
+	switch propertyID {
+	case PropertyObjectIdentifier:
+		return d.ObjectID(), nil
+	case PropertyObjectName:
+		return d.opts.name, nil
+	case PropertyObjectType:
+		return ObjectTypeDevice, nil
+	case PropertySystemStatus:
+		return DeviceStatusOperational, nil
+	case PropertyVendorName:
+		return d.opts.vendorName, nil
+	case PropertyVendorIdentifier:
+		return uint32(d.opts.vendorID), nil
+	case PropertyModelName:
+		return d.opts.modelName, nil
+	case PropertyFirmwareRevision:
+		return d.opts.firmwareRevision, nil
+	case PropertyApplicationSoftwareVersion:
+		return d.opts.applicationSoftware, nil
+	case PropertyDescription:
+		return d.opts.description, nil
+	case PropertyLocation:
+		return d.opts.location, nil
+	case PropertyProtocolVersion:
+		return uint32(1), nil
+	case PropertyProtocolRevision:
+		return uint32(14), nil
+	case PropertyProtocolServicesSupported:
+		return d.servicesSupported(), nil
+	case PropertyProtocolObjectTypesSupported:
+		return d.objectTypesSupported(), nil
+	case PropertyObjectList:
+		return d.ObjectList(), nil
+	case PropertyMaxApduLengthAccepted:
+		return uint32(d.opts.maxAPDU), nil
+	case PropertySegmentationSupported:
+		return SegmentationNone, nil
+	case PropertyApduTimeout:
+		return uint32(3000), nil
+	case PropertyNumberOfApduRetries:
+		return uint32(3), nil
+	case PropertyDeviceAddressBinding:
+		return []interface{}{}, nil
+	case PropertyDatabaseRevision:
+		return d.databaseRevision.Load(), nil
+	default:
+		return nil, NewBACnetError(ErrorClassProperty, ErrorCodeUnknownProperty)
+	}
+}
+
+// servicesSupported returns the Protocol_Services_Supported bit string
+func (d *Device) servicesSupported() BitString {
+	bits := NewBitString(41)
+	bits.Set(int(ServiceReadProperty), true)
+	bits.Set(int(ServiceReadPropertyMultiple), true)
+	if _, ok := d.opts.database.(PropertyWriter); ok {
+		bits.Set(int(ServiceWriteProperty), true)
+	}
+	if _, ok := d.opts.database.(COVReporter); ok {
+		bits.Set(int(ServiceSubscribeCOV), true)
+	}
+	// Unconfirmed services follow the 26 confirmed service bits
+	bits.Set(26+int(ServiceIAm), true)
+	bits.Set(26+int(ServiceWhoIs), true)
+	bits.Set(26+int(ServiceIHave), true)
+	bits.Set(26+int(ServiceWhoHas), true)
+	return bits
+}
+
+// objectTypesSupported returns the Protocol_Object_Types_Supported bit string
+func (d *Device) objectTypesSupported() BitString {
+	bits := NewBitString(int(ObjectTypeLift) + 1)
+	bits.Set(int(ObjectTypeDevice), true)
+	for _, oid := range d.ObjectList() {
+		bits.Set(int(oid.Type), true)
+	}
+	return bits
+}
+
+// resolveObjectID maps the wildcard device instance to the local device
+func (d *Device) resolveObjectID(objectID ObjectIdentifier) ObjectIdentifier {
+	if objectID.Type == ObjectTypeDevice && objectID.Instance == wildcardDeviceInstance {
+		return d.ObjectID()
+	}
+	return objectID
+}
+
+// readProperty reads a property from the device object or the database
+func (d *Device) readProperty(objectID ObjectIdentifier, propertyID PropertyIdentifier) (interface{}, error) {
+	if objectID == d.ObjectID() {
+		return d.readDeviceProperty(propertyID)
+	}
+
+	if d.opts.database == nil {
+		return nil, NewBACnetError(ErrorClassObject, ErrorCodeUnknownObject)
+	}
+	return d.opts.database.ReadProperty(objectID, propertyID)
+}
+
+// writeProperty writes a property of a database object. The device object
+// itself is read-only.
+func (d *Device) writeProperty(objectID ObjectIdentifier, propertyID PropertyIdentifier, value interface{}, priority uint8, arrayIndex *uint32) error {
+	if objectID == d.ObjectID() {
+		if _, err := d.readDeviceProperty(propertyID); err != nil {
+			return err
+		}
+		return NewBACnetError(ErrorClassProperty, ErrorCodeWriteAccessDenied)
+	}
+
+	writer, ok := d.opts.database.(PropertyWriter)
+	if !ok {
+		if _, err := d.readProperty(objectID, propertyID); err != nil {
+			return err
+		}
+		return NewBACnetError(ErrorClassProperty, ErrorCodeWriteAccessDenied)
+	}
+	return writer.WriteProperty(objectID, propertyID, value, priority, arrayIndex)
+}
+
+// properties returns the properties of an object, if they can be listed
+func (d *Device) properties(objectID ObjectIdentifier) ([]PropertyIdentifier, bool) {
+	if objectID == d.ObjectID() {
+		return deviceProperties, true
+	}
+	if lister, ok := d.opts.database.(PropertyLister); ok {
+		return lister.Properties(objectID), true
+	}
+	return nil, false
+}
+
+// wildcardDeviceInstance addresses the local device object regardless of
+// its instance number
+const wildcardDeviceInstance = 4194303
+
+// applyArrayIndex selects an element of an array property value
+func applyArrayIndex(value interface{}, arrayIndex *uint32) (interface{}, error) {
+	if arrayIndex == nil {
+		return value, nil
+	}
+
+	rv := reflect.ValueOf(value)
+	if value == nil || rv.Kind() != reflect.Slice || rv.Type().Elem().Kind() == reflect.Uint8 {
+		return nil, NewBACnetError(ErrorClassProperty, ErrorCodePropertyIsNotAnArray)
+	}
+
+	if *arrayIndex == 0 {
+		return uint32(rv.Len()), nil
+	}
+	if int(*arrayIndex) > rv.Len() {
+		return nil, NewBACnetError(ErrorClassProperty, ErrorCodeInvalidArrayIndex)
+	}
+	return rv.Index(int(*arrayIndex) - 1).Interface(), nil
+}
+
+// asBACnetError converts an error returned by an object database to a
+// BACnet error suitable for an Error PDU
+func asBACnetError(err error) *BACnetError {
+	var bacnetErr *BACnetError
+	if errors.As(err, &bacnetErr) {
+		return bacnetErr
+	}
+	return NewBACnetError(ErrorClassDevice, ErrorCodeOther)
+}
+
+// IAm broadcasts an I-Am for the local device
+func (c *Client) IAm(ctx context.Context) error {
+	if c.server == nil {
+		return ErrNoLocalDevice
+	}
+	return c.sendUnconfirmedRequest(ctx, nil, true, ServiceIAm, c.encodeIAm())
+}
+
+// encodeIAm encodes the I-Am service parameters for the local device
+func (c *Client) encodeIAm() []byte {
+	e := NewEncoder(make([]byte, 0, 16))
+	e.ObjectIdentifier(c.server.ObjectID())
+	e.Unsigned(uint32(c.server.opts.maxAPDU))
+	e.Enumerated(uint32(SegmentationNone))
+	e.Unsigned(uint32(c.server.opts.vendorID))
+	return e.Bytes()
+}
+
+// handleWhoIs answers a Who-Is request on behalf of the local device
+func (c *Client) handleWhoIs(data []byte, addr net.Addr, npdu *NPDU) {
+	if c.server == nil {
+		return
+	}
+
+	// Optional device instance range limits
+	if len(data) > 0 {
+		d := NewDecoder(data)
+		low := d.ContextUnsigned(0)
+		high := d.ContextUnsigned(1)
+		if d.Err() != nil {
+			return
+		}
+		instance := c.server.opts.instance
+		if instance < low || instance > high {
+			return
+		}
+	}
+
+	apdu := EncodeUnconfirmedRequest(ServiceIAm, c.encodeIAm())
+	if err := c.sendResponse(context.Background(), addr, npdu, false, apdu); err != nil {
+		c.logger.Debug("failed to send I-Am", slog.String("error", err.Error()))
+	}
+}
+
+// handleWhoHas answers a Who-Has request on behalf of the local device when
+// it holds the object, by identifier or name
+func (c *Client) handleWhoHas(data []byte, addr net.Addr, npdu *NPDU) {
+	if c.server == nil {
+		return
+	}
+
+	d := NewDecoder(data)
+	if d.IsContext(0) {
+		low := d.ContextUnsigned(0)
+		high := d.ContextUnsigned(1)
+		instance := c.server.opts.instance
+		if d.Err() != nil || instance < low || instance > high {
+			return
+		}
+	}
+
+	var (
+		objectID ObjectIdentifier
+		name     string
+		found    bool
+	)
+	if d.IsContext(2) {
+		want := c.server.resolveObjectID(d.ContextObjectIdentifier(2))
+		for _, oid := range c.server.ObjectList() {
+			if oid == want {
+				objectID, found = oid, true
+				break
+			}
+		}
+		if found {
+			v, _ := c.server.readProperty(objectID, PropertyObjectName)
+			name, _ = v.(string)
+		}
+	} else {
+		want := d.ContextCharacterString(3)
+		if d.Err() != nil {
+			return
+		}
+		for _, oid := range c.server.ObjectList() {
+			if v, err := c.server.readProperty(oid, PropertyObjectName); err == nil && v == want {
+				objectID, name, found = oid, want, true
+				break
+			}
+		}
+	}
+	if d.Err() != nil || !found {
+		return
+	}
+
+	e := NewEncoder(make([]byte, 0, 32))
+	e.ObjectIdentifier(c.server.ObjectID())
+	e.ObjectIdentifier(objectID)
+	e.CharacterString(name)
+	apdu := EncodeUnconfirmedRequest(ServiceIHave, e.Bytes())
+	if err := c.sendResponse(context.Background(), addr, npdu, false, apdu); err != nil {
+		c.logger.Debug("failed to send I-Have", slog.String("error", err.Error()))
+	}
+}
+
+// handleConfirmedRequest serves a confirmed request addressed to the local device
+func (c *Client) handleConfirmedRequest(apdu *APDU, addr net.Addr, npdu *NPDU) {
+	// Notifications of our own COV subscriptions are acknowledged with or
+	// without a local device
+	if ConfirmedServiceChoice(apdu.Service) == ServiceConfirmedCOVNotification && !apdu.Segmented {
+		c.handleCOVNotification(apdu.Data)
+		c.sendServerReply(addr, npdu, EncodeSimpleAck(apdu.InvokeID, ServiceConfirmedCOVNotification))
+		return
+	}
+
+	if apdu.Segmented {
+		c.sendServerReply(addr, npdu, EncodeAbortAPDU(apdu.InvokeID, true, AbortReasonSegmentationNotSupported))
+		return
+	}
+
+	// Without a local device, a peer probing the client is answered at
+	// once instead of retrying until its timeout
+	service := ConfirmedServiceChoice(apdu.Service)
+	if c.server == nil {
+		c.rejectUnrecognizedService(apdu, addr, npdu)
+		return
+	}
+
+	var (
+		ack    []byte
+		err    error
+		simple bool
+	)
+
+	if c.server.opts.interceptor != nil {
+		err = c.server.opts.interceptor(&ServerRequest{
+			Service:  service,
+			InvokeID: apdu.InvokeID,
+			Source:   addr,
+			Data:     apdu.Data,
+		})
+	}
+
+	switch {
+	case err != nil:
+		// Answered on behalf of the interceptor
+	case service == ServiceReadProperty:
+		ack, err = c.serveReadProperty(apdu.Data)
+	case service == ServiceReadPropertyMultiple:
+		ack, err = c.serveReadPropertyMultiple(apdu.Data)
+	case service == ServiceWriteProperty:
+		err = c.serveWriteProperty(apdu.Data)
+		simple = true
+	case service == ServiceSubscribeCOV:
+		err = c.serveSubscribeCOV(apdu.Data, addr, npdu)
+		simple = true
+	default:
+		c.rejectUnrecognizedService(apdu, addr, npdu)
+		return
+	}
+
+	var (
+		reply     []byte
+		rejectErr *RejectError
+		abortErr  *AbortError
+	)
+
+	switch {
+	case errors.Is(err, ErrNoReply):
+		return
+	case errors.Is(err, ErrInvalidAPDU):
+		reply = EncodeRejectAPDU(apdu.InvokeID, RejectReasonInvalidTag)
+	case errors.As(err, &rejectErr):
+		reply = EncodeRejectAPDU(apdu.InvokeID, rejectErr.Reason)
+	case errors.As(err, &abortErr):
+		reply = EncodeAbortAPDU(apdu.InvokeID, true, abortErr.Reason)
+	case err != nil:
+		bacnetErr := asBACnetError(err)
+		reply = EncodeErrorAPDU(apdu.InvokeID, service, bacnetErr.Class, bacnetErr.Code)
+	case simple:
+		reply = EncodeSimpleAck(apdu.InvokeID, service)
+	default:
+		reply = EncodeComplexAck(apdu.InvokeID, service, ack)
+		if len(reply) > DecodeMaxAPDU(apdu.MaxAPDU) || len(reply) > int(c.server.opts.maxAPDU) {
+			reply = EncodeAbortAPDU(apdu.InvokeID, true, AbortReasonSegmentationNotSupported)
+		}
+	}
+
+	c.sendServerReply(addr, npdu, reply)
+}
+
+// rejectUnrecognizedService answers a confirmed request for a service the
+// client does not serve with a Reject
+func (c *Client) rejectUnrecognizedService(apdu *APDU, addr net.Addr, npdu *NPDU) {
+	c.logger.Debug("rejecting unrecognized service",
+		slog.String("address", addr.String()),
+		slog.String("service", ConfirmedServiceChoice(apdu.Service).String()),
+	)
+	c.sendServerReply(addr, npdu, EncodeRejectAPDU(apdu.InvokeID, RejectReasonUnrecognizedService))
+}
+
+// sendServerReply sends a reply APDU to the originator of a confirmed request
+func (c *Client) sendServerReply(addr net.Addr, npdu *NPDU, apdu []byte) {
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.timeout)
+	defer cancel()
+
+	if err := c.sendResponse(ctx, addr, npdu, false, apdu); err != nil {
+		c.logger.Debug("failed to send reply", slog.String("error", err.Error()))
+	}
+}
+
+// sendResponse sends an APDU back to the source of a received NPDU, routing
+// it through the originating network when the request was routed
+func (c *Client) sendResponse(ctx context.Context, addr net.Addr, src *NPDU, expectingReply bool, apdu []byte) error {
+	if c.State() != StateConnected {
+		return ErrNotConnected
+	}
+
+	npdu := encodeRoutedNPDU(src, expectingReply, NPDUControlPriorityNormal)
+
+	packet := make([]byte, 0, len(npdu)+len(apdu))
+	packet = append(packet, npdu...)
+	packet = append(packet, apdu...)
+
+	c.traceNPDU(true, addr, packet)
+	if err := c.link.Send(ctx, addr, packet); err != nil {
+		return fmt.Errorf("send response: %w", err)
+	}
+
+	c.metrics.BytesSent.Add(int64(len(packet)))
+	return nil
+}
+
+// encodeRoutedNPDU encodes an NPDU addressed to the source of a received
+// NPDU. Sources on remote networks are reached through their router.
+func encodeRoutedNPDU(src *NPDU, expectingReply bool, priority NPDUControl) []byte {
+	if src != nil && src.Control&NPDUControlSourceSpecifier != 0 {
+		return EncodeNPDUWithDest(src.SrcNet, src.SrcAddr, 255, expectingReply, priority)
+	}
+	return EncodeNPDU(expectingReply, priority)
+}
+
+// serveReadProperty handles a ReadProperty request
+func (c *Client) serveReadProperty(data []byte) ([]byte, error) {
+	d := NewDecoder(data)
+	objectID := d.ContextObjectIdentifier(0)
+	propertyID := PropertyIdentifier(d.ContextEnumerated(1))
+
+	var arrayIndex *uint32
+	if d.Len() > 0 {
+		idx := d.ContextUnsigned(2)
+		arrayIndex = &idx
+	}
+	if err := d.Err(); err != nil {
+		return nil, err
+	}
+	objectID = c.server.resolveObjectID(objectID)
+
+	value, err := c.server.readProperty(objectID, propertyID)
+	if err != nil {
+		return nil, err
+	}
+	value, err = applyArrayIndex(value, arrayIndex)
+	if err != nil {
+		return nil, err
+	}
+
+	e := NewEncoder(make([]byte, 0, 32))
+	e.ContextObjectIdentifier(0, objectID)
+	e.ContextEnumerated(1, uint32(propertyID))
+	if arrayIndex != nil {
+		e.ContextUnsigned(2, *arrayIndex)
+	}
+	e.Opening(3)
+	if err := e.Value(value); err != nil {
+		return nil, NewBACnetError(ErrorClassProperty, ErrorCodeDatatypeNotSupported)
+	}
+	e.Closing(3)
+	return e.Bytes(), nil
+}
+
+// serveReadPropertyMultiple handles a ReadPropertyMultiple request
+func (c *Client) serveReadPropertyMultiple(data []byte) ([]byte, error) {
+	d := NewDecoder(data)
+	var ack Encoder
+
+	for d.Len() > 0 {
+		objectID := d.ContextObjectIdentifier(0)
+		d.Opening(1)
+		if err := d.Err(); err != nil {
+			return nil, err
+		}
+		objectID = c.server.resolveObjectID(objectID)
+
+		ack.ContextObjectIdentifier(0, objectID)
+		ack.Opening(1)
+
+		for !d.IsClosing(1) {
+			propertyID := PropertyIdentifier(d.ContextEnumerated(0))
+
+			var arrayIndex *uint32
+			if d.IsContext(1) {
+				idx := d.ContextUnsigned(1)
+				arrayIndex = &idx
+			}
+			if err := d.Err(); err != nil {
+				return nil, err
+			}
+
+			switch propertyID {
+			case PropertyAll, PropertyRequired, PropertyOptional:
+				props, ok := c.server.properties(objectID)
+				if !ok {
+					c.appendReadAccessResult(&ack, objectID, propertyID, nil,
+						NewBACnetError(ErrorClassProperty, ErrorCodeUnknownProperty))
+					continue
+				}
+				for _, prop := range props {
+					c.appendReadAccessResult(&ack, objectID, prop, nil, nil)
+				}
+			default:
+				c.appendReadAccessResult(&ack, objectID, propertyID, arrayIndex, nil)
+			}
+		}
+		d.Closing(1)
+
+		ack.Closing(1)
+	}
+
+	return ack.Bytes(), nil
+}
+
+// serveWriteProperty handles a WriteProperty request
+func (c *Client) serveWriteProperty(data []byte) error {
+	d := NewDecoder(data)
+	objectID := d.ContextObjectIdentifier(0)
+	propertyID := PropertyIdentifier(d.ContextEnumerated(1))
+
+	var arrayIndex *uint32
+	if d.IsContext(2) {
+		idx := d.ContextUnsigned(2)
+		arrayIndex = &idx
+	}
+
+	d.Opening(3)
+	if err := d.Err(); err != nil {
+		return err
+	}
+	objectID = c.server.resolveObjectID(objectID)
+
+	value, err := c.decodeApplicationValues(d, 3)
+	if err != nil {
+		return err
+	}
+
+	var priority uint8
+	if d.Len() > 0 {
+		p := d.ContextUnsigned(4)
+		if err := d.Err(); err != nil {
+			return err
+		}
+		if p < 1 || p > 16 {
+			return NewBACnetError(ErrorClassProperty, ErrorCodeValueOutOfRange)
+		}
+		priority = uint8(p)
+	}
+
+	if err := c.server.writeProperty(objectID, propertyID, value, priority, arrayIndex); err != nil {
+		return err
+	}
+
+	c.logger.Debug("served WriteProperty",
+		slog.String("object", objectID.String()),
+		slog.String("property", propertyID.String()),
+		slog.Any("value", value),
+		slog.Int("priority", int(priority)),
+	)
+	return nil
+}
+
+// decodeApplicationValues decodes the application-tagged values up to and
+// including the closing tag with the given number. A single value is
+// returned as is, several values as a []interface{}. Constructed values are
+// not supported.
+func (c *Client) decodeApplicationValues(d *Decoder, closingTag uint8) (interface{}, error) {
+	var values []interface{}
+
+	for !d.IsClosing(closingTag) {
+		t, ok := d.peek()
+		if !ok {
+			d.failf("expected closing tag %d", closingTag)
+			return nil, d.Err()
+		}
+		if t.class == TagClassContext {
+			return nil, NewBACnetError(ErrorClassProperty, ErrorCodeDatatypeNotSupported)
+		}
+
+		value := d.Value()
+		if err := d.Err(); err != nil {
+			return nil, err
+		}
+		// Values would otherwise alias the receive buffer
+		switch v := value.(type) {
+		case []byte:
+			value = bytes.Clone(v)
+		case BitString:
+			v.Bits = bytes.Clone(v.Bits)
+			value = v
+		}
+		values = append(values, value)
+	}
+	d.Closing(closingTag)
+
+	switch len(values) {
+	case 0:
+		return nil, ErrInvalidAPDU
+	case 1:
+		return values[0], nil
+	default:
+		return values, nil
+	}
+}
+
+// appendReadAccessResult reads one property and appends its read access
+// result (value or property access error) to an RPM acknowledgement
+func (c *Client) appendReadAccessResult(ack *Encoder, objectID ObjectIdentifier, propertyID PropertyIdentifier, arrayIndex *uint32, accessErr *BACnetError) {
+	ack.ContextEnumerated(2, uint32(propertyID))
+	if arrayIndex != nil {
+		ack.ContextUnsigned(3, *arrayIndex)
+	}
+
+	if accessErr == nil {
+		value, err := c.server.readProperty(objectID, propertyID)
+		if err == nil {
+			value, err = applyArrayIndex(value, arrayIndex)
+		}
+		if err == nil {
+			mark := ack.Len()
+			ack.Opening(4)
+			if err = ack.Value(value); err == nil {
+				ack.Closing(4)
+				return
+			}
+			ack.truncate(mark)
+			err = NewBACnetError(ErrorClassProperty, ErrorCodeDatatypeNotSupported)
+		}
+		accessErr = asBACnetError(err)
+	}
+
+	ack.Opening(5)
+	ack.Enumerated(uint32(accessErr.Class))
+	ack.Enumerated(uint32(accessErr.Code))
+	ack.Closing(5)
+}
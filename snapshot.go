@@ -0,0 +1,284 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// DeviceSnapshot is the configuration of a device at a point in time, as
+// saved by the dump command or read from an EPICS file
+type DeviceSnapshot struct {
+	DeviceID  uint32           `json:"device_id"`
+	Timestamp time.Time        `json:"timestamp"`
+	Objects   []ObjectSnapshot `json:"objects"`
+}
+
+// ObjectSnapshot holds the property values of one object of a snapshot,
+// keyed by property name
+type ObjectSnapshot struct {
+	ObjectID   string                 `json:"object_id"`
+	ObjectType string                 `json:"object_type"`
+	Instance   uint32                 `json:"instance"`
+	Properties map[string]interface{} `json:"properties"`
+}
+
+// ReadSnapshotFile reads a snapshot from a JSON dump or an EPICS file
+func ReadSnapshotFile(path string) (*DeviceSnapshot, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	if bytes.HasPrefix(bytes.TrimSpace(data), []byte("{")) {
+		var snap DeviceSnapshot
+		if err := json.Unmarshal(data, &snap); err != nil {
+			return nil, fmt.Errorf("parse %s: %w", path, err)
+		}
+		return &snap, nil
+	}
+
+	snap, err := ParseEPICS(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("parse %s: %w", path, err)
+	}
+	return snap, nil
+}
+
+// ParseEPICS reads the object database of an EPICS (Electronic PICS) file.
+// Property values are kept as their EPICS text, except object identifiers
+// which are written as in dumps.
+func ParseEPICS(r io.Reader) (*DeviceSnapshot, error) {
+	snap := &DeviceSnapshot{}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 64*1024), 1024*1024)
+
+	inList := false
+	depth := 0
+	var obj *ObjectSnapshot
+	var prop, value string
+	line := 0
+
+	flush := func() {
+		if obj == nil || prop == "" {
+			return
+		}
+		value = strings.TrimSpace(value)
+		obj.Properties[prop] = value
+		if prop == PropertyObjectIdentifier.String() {
+			// Written as in dumps
+			if oid, ok := parseEPICSObjectID(value); ok {
+				obj.ObjectID = oid.String()
+				obj.ObjectType = oid.Type.String()
+				obj.Instance = oid.Instance
+				obj.Properties[prop] = obj.ObjectID
+			}
+		}
+		prop, value = "", ""
+	}
+
+	for scanner.Scan() {
+		line++
+		text := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(text, "--"); i >= 0 && !strings.Contains(text[:i], "\"") {
+			text = strings.TrimSpace(text[:i])
+		}
+		if !inList {
+			if strings.HasPrefix(strings.ToLower(text), "list of objects in") {
+				inList = true
+			}
+			continue
+		}
+		if text == "" {
+			continue
+		}
+
+		// A property value may span lines until its braces balance
+		if prop != "" && strings.Count(value, "{") > strings.Count(value, "}") {
+			value += " " + text
+			continue
+		}
+
+		switch {
+		case text == "{" && depth == 0:
+			depth = 1
+		case text == "{" && depth == 1:
+			depth = 2
+			obj = &ObjectSnapshot{Properties: make(map[string]interface{})}
+		case text == "}" && depth == 2:
+			flush()
+			if obj.ObjectID == "" {
+				return nil, fmt.Errorf("line %d: object without object-identifier", line)
+			}
+			snap.Objects = append(snap.Objects, *obj)
+			obj = nil
+			depth = 1
+		case text == "}" && depth == 1:
+			depth = 0
+			inList = false
+		case depth == 2:
+			name, val, ok := strings.Cut(text, ":")
+			if !ok {
+				return nil, fmt.Errorf("line %d: expected property: value", line)
+			}
+			flush()
+			prop = strings.ToLower(strings.TrimSpace(name))
+			value = strings.TrimSpace(val)
+		default:
+			return nil, fmt.Errorf("line %d: unexpected %q", line, text)
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if depth != 0 {
+		return nil, fmt.Errorf("unterminated object list")
+	}
+
+	for _, o := range snap.Objects {
+		if o.ObjectType == ObjectTypeDevice.String() {
+			snap.DeviceID = o.Instance
+			break
+		}
+	}
+	return snap, nil
+}
+
+// parseEPICSObjectID parses an EPICS object identifier such as
+// "(analog-input, 1)"
+func parseEPICSObjectID(s string) (ObjectIdentifier, bool) {
+	s = strings.TrimSuffix(strings.TrimPrefix(strings.TrimSpace(s), "("), ")")
+	typ, inst, ok := strings.Cut(s, ",")
+	if !ok {
+		return ObjectIdentifier{}, false
+	}
+	objectType, ok := ParseObjectType(strings.ToLower(strings.TrimSpace(typ)))
+	if !ok {
+		return ObjectIdentifier{}, false
+	}
+	instance, err := strconv.ParseUint(strings.TrimSpace(inst), 10, 22)
+	if err != nil {
+		return ObjectIdentifier{}, false
+	}
+	return NewObjectIdentifier(objectType, uint32(instance)), true
+}
+
+// SnapshotDiff is the drift between two snapshots of a device
+type SnapshotDiff struct {
+	// Added lists the objects only in the second snapshot
+	Added []string `json:"added,omitempty"`
+	// Removed lists the objects only in the first snapshot
+	Removed []string `json:"removed,omitempty"`
+	// Changed lists the properties whose values differ
+	Changed []PropertyChange `json:"changed,omitempty"`
+}
+
+// PropertyChange is a property whose value differs between two snapshots
+type PropertyChange struct {
+	ObjectID string      `json:"object_id"`
+	Property string      `json:"property"`
+	Old      interface{} `json:"old"`
+	New      interface{} `json:"new"`
+}
+
+// Empty reports whether the snapshots match
+func (d *SnapshotDiff) Empty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 && len(d.Changed) == 0
+}
+
+// CompareSnapshots returns the objects added and removed between before and
+// after, and the changed values of the properties present in both, skipping
+// the ignored properties. Snapshots often hold different sets of
+// properties, so a property missing from one is not a change. Values are
+// compared by their text, so that a JSON dump can be compared with an EPICS
+// file.
+func CompareSnapshots(before, after *DeviceSnapshot, ignore ...string) *SnapshotDiff {
+	skip := make(map[string]bool, len(ignore))
+	for _, name := range ignore {
+		skip[strings.ToLower(name)] = true
+	}
+
+	index := func(snap *DeviceSnapshot) map[string]*ObjectSnapshot {
+		m := make(map[string]*ObjectSnapshot, len(snap.Objects))
+		for i := range snap.Objects {
+			m[snap.Objects[i].ObjectID] = &snap.Objects[i]
+		}
+		return m
+	}
+	old, cur := index(before), index(after)
+
+	diff := &SnapshotDiff{}
+	for _, o := range before.Objects {
+		if _, ok := cur[o.ObjectID]; !ok {
+			diff.Removed = append(diff.Removed, o.ObjectID)
+		}
+	}
+	for _, o := range after.Objects {
+		prev, ok := old[o.ObjectID]
+		if !ok {
+			diff.Added = append(diff.Added, o.ObjectID)
+			continue
+		}
+
+		names := make([]string, 0, len(o.Properties))
+		for name := range o.Properties {
+			if _, ok := prev.Properties[name]; ok && !skip[name] {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+
+		for _, name := range names {
+			a, b := prev.Properties[name], o.Properties[name]
+			if !snapshotValuesEqual(a, b) {
+				diff.Changed = append(diff.Changed, PropertyChange{ObjectID: o.ObjectID, Property: name, Old: a, New: b})
+			}
+		}
+	}
+	return diff
+}
+
+// snapshotValuesEqual compares two snapshot values by their text, ignoring
+// case and quotes, and numerically when both are numbers
+func snapshotValuesEqual(a, b interface{}) bool {
+	sa, sb := snapshotText(a), snapshotText(b)
+	if strings.EqualFold(sa, sb) {
+		return true
+	}
+	fa, errA := strconv.ParseFloat(sa, 64)
+	fb, errB := strconv.ParseFloat(sb, 64)
+	return errA == nil && errB == nil && fa == fb
+}
+
+// snapshotText returns the text of a snapshot value
+func snapshotText(v interface{}) string {
+	switch v := v.(type) {
+	case string:
+		return strings.Trim(strings.TrimSpace(v), "\"")
+	case float64:
+		return strconv.FormatFloat(v, 'g', -1, 64)
+	default:
+		return fmt.Sprint(v)
+	}
+}
@@ -0,0 +1,175 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+)
+
+// StateTexts are the names of the states of a binary or multi-state object
+type StateTexts struct {
+	// Inactive and Active are the inactive-text and active-text of a binary
+	// object
+	Inactive string
+	Active   string
+
+	// States is the state-text of a multi-state object; state n is
+	// States[n-1]
+	States []string
+}
+
+// Text returns the name of a state, or false if the object does not name it.
+// Binary states are 0 (inactive) and 1 (active); multi-state states start
+// at 1.
+func (t *StateTexts) Text(state uint32) (string, bool) {
+	if t == nil {
+		return "", false
+	}
+	if len(t.States) > 0 {
+		if state >= 1 && int(state) <= len(t.States) {
+			return t.States[state-1], true
+		}
+		return "", false
+	}
+	switch {
+	case state == 0 && t.Inactive != "":
+		return t.Inactive, true
+	case state == 1 && t.Active != "":
+		return t.Active, true
+	}
+	return "", false
+}
+
+// Resolve returns the name of the state held by a present value, which may
+// be a bool, an enumerated or any other whole number
+func (t *StateTexts) Resolve(value interface{}) (string, bool) {
+	var state uint32
+	switch v := value.(type) {
+	case bool:
+		if v {
+			state = 1
+		}
+	default:
+		f, ok := toFloat64(value)
+		if !ok || f < 0 || f != float64(uint32(f)) {
+			return "", false
+		}
+		state = uint32(f)
+	}
+	return t.Text(state)
+}
+
+// stateTextKey identifies an object of a remote device
+type stateTextKey struct {
+	deviceID uint32
+	objectID ObjectIdentifier
+}
+
+// hasStateTexts returns true for the object types whose present value is
+// a named state
+func hasStateTexts(t ObjectType) bool {
+	switch t {
+	case ObjectTypeBinaryInput, ObjectTypeBinaryOutput, ObjectTypeBinaryValue,
+		ObjectTypeMultiStateInput, ObjectTypeMultiStateOutput, ObjectTypeMultiStateValue:
+		return true
+	}
+	return false
+}
+
+// ReadStateTexts reads the active-text and inactive-text of a binary object
+// or the state-text of a multi-state object. The texts are cached for the
+// life of the client; properties the object lacks are left empty.
+func (c *Client) ReadStateTexts(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) (*StateTexts, error) {
+	if !hasStateTexts(objectID.Type) {
+		return nil, fmt.Errorf("bacnet: %s has no state texts", objectID.Type)
+	}
+
+	key := stateTextKey{deviceID, objectID}
+	c.stateTextsMu.Lock()
+	texts, ok := c.stateTexts[key]
+	c.stateTextsMu.Unlock()
+	if ok {
+		return texts, nil
+	}
+
+	texts = &StateTexts{}
+	var err error
+	switch objectID.Type {
+	case ObjectTypeMultiStateInput, ObjectTypeMultiStateOutput, ObjectTypeMultiStateValue:
+		var values []interface{}
+		values, err = c.ReadArray(ctx, deviceID, objectID, PropertyStateText)
+		for _, v := range values {
+			s, _ := v.(string)
+			texts.States = append(texts.States, s)
+		}
+	default:
+		var results []PropertyResult
+		results, err = c.ReadPropertyMultiple(ctx, deviceID, []ReadPropertyRequest{
+			{ObjectID: objectID, PropertyID: PropertyInactiveText},
+			{ObjectID: objectID, PropertyID: PropertyActiveText},
+		})
+		if serviceUnsupported(err) {
+			results, err = c.readEach(ctx, deviceID, []ReadPropertyRequest{
+				{ObjectID: objectID, PropertyID: PropertyInactiveText},
+				{ObjectID: objectID, PropertyID: PropertyActiveText},
+			})
+		}
+		for _, r := range results {
+			s, _ := r.Value.(string)
+			switch r.PropertyID {
+			case PropertyInactiveText:
+				texts.Inactive = s
+			case PropertyActiveText:
+				texts.Active = s
+			}
+		}
+	}
+
+	// An object without the optional properties is cached as such, but
+	// communication failures are not
+	var bacnetErr *BACnetError
+	if err != nil && !errors.As(err, &bacnetErr) {
+		return nil, err
+	}
+
+	c.stateTextsMu.Lock()
+	c.stateTexts[key] = texts
+	c.stateTextsMu.Unlock()
+	return texts, nil
+}
+
+// ResolveDisplayValue returns the state name of the present value of a
+// binary or multi-state object, such as "Occupied" for 2, reading the state
+// texts on first use. It returns false for other objects, values and when
+// the texts cannot be read.
+func (c *Client) ResolveDisplayValue(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, value interface{}) (string, bool) {
+	if !hasStateTexts(objectID.Type) {
+		return "", false
+	}
+
+	texts, err := c.ReadStateTexts(ctx, deviceID, objectID)
+	if err != nil {
+		c.logger.Debug("failed to read state texts",
+			slog.Uint64("device_id", uint64(deviceID)),
+			slog.String("object", objectID.String()),
+			slog.String("error", err.Error()),
+		)
+		return "", false
+	}
+	return texts.Resolve(value)
+}
@@ -0,0 +1,473 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/url"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// Storage persists client state across restarts: the device cache, COV
+// subscriptions served by the local device, energy rollup cursors and write
+// queues. It
+// offers a key/value store and named append-only logs. Implementations must
+// be safe for concurrent use.
+type Storage interface {
+	// Get returns the value of a key, or ErrStorageNotFound
+	Get(ctx context.Context, key string) ([]byte, error)
+
+	// Put sets the value of a key
+	Put(ctx context.Context, key string, value []byte) error
+
+	// Delete removes a key. Removing a missing key is not an error.
+	Delete(ctx context.Context, key string) error
+
+	// List returns the keys starting with prefix, in order
+	List(ctx context.Context, prefix string) ([]string, error)
+
+	// Append adds a record to a log and returns its sequence number.
+	// Sequence numbers start at 1 and are never reused, even after
+	// truncation.
+	Append(ctx context.Context, log string, record []byte) (uint64, error)
+
+	// ReadLog calls fn with the records of a log from sequence number from
+	// onwards, in order. An error returned by fn stops the read.
+	ReadLog(ctx context.Context, log string, from uint64, fn func(seq uint64, record []byte) error) error
+
+	// TruncateLog removes the records of a log before sequence number
+	// before
+	TruncateLog(ctx context.Context, log string, before uint64) error
+
+	// Close releases the storage
+	Close() error
+}
+
+// Storage key prefixes of the client
+const (
+	storageDevicesPrefix = "devices/"
+	storageCOVPrefix     = "cov-subscriptions/"
+	storageEnergyPrefix  = "energy/"
+)
+
+// storeJSON writes a JSON encoded value to the client storage, if any.
+// Failures are logged: persistence never fails the operation it records.
+func (c *Client) storeJSON(key string, v interface{}) {
+	if c.opts.storage == nil {
+		return
+	}
+	data, err := json.Marshal(v)
+	if err == nil {
+		ctx, cancel := context.WithTimeout(context.Background(), c.opts.timeout)
+		err = c.opts.storage.Put(ctx, key, data)
+		cancel()
+	}
+	if err != nil {
+		c.logger.Warn("failed to persist state",
+			slog.String("key", key),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// storeDelete removes a key from the client storage, if any
+func (c *Client) storeDelete(key string) {
+	if c.opts.storage == nil {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), c.opts.timeout)
+	defer cancel()
+	if err := c.opts.storage.Delete(ctx, key); err != nil {
+		c.logger.Warn("failed to delete persisted state",
+			slog.String("key", key),
+			slog.String("error", err.Error()),
+		)
+	}
+}
+
+// loadJSON calls fn with every value stored under prefix. Undecodable
+// values are logged and skipped.
+func (c *Client) loadJSON(ctx context.Context, prefix string, newValue func() interface{}, fn func(key string, v interface{})) error {
+	keys, err := c.opts.storage.List(ctx, prefix)
+	if err != nil {
+		return err
+	}
+	for _, key := range keys {
+		data, err := c.opts.storage.Get(ctx, key)
+		if errors.Is(err, ErrStorageNotFound) {
+			continue
+		}
+		if err != nil {
+			return err
+		}
+		v := newValue()
+		if err := json.Unmarshal(data, v); err != nil {
+			c.logger.Warn("skipping unreadable persisted state",
+				slog.String("key", key),
+				slog.String("error", err.Error()),
+			)
+			continue
+		}
+		fn(key, v)
+	}
+	return nil
+}
+
+// deviceKey returns the storage key of a cached device
+func deviceKey(deviceID uint32) string {
+	return storageDevicesPrefix + strconv.FormatUint(uint64(deviceID), 10)
+}
+
+// storeDevice persists a device of the cache
+func (c *Client) storeDevice(device *DeviceInfo) {
+	c.storeJSON(deviceKey(device.ObjectID.Instance), device)
+}
+
+// restoreDevices loads the persisted device cache. Devices already known
+// are kept.
+func (c *Client) restoreDevices(ctx context.Context) error {
+	newDevice := func() interface{} { return &DeviceInfo{} }
+	return c.loadJSON(ctx, storageDevicesPrefix, newDevice, func(key string, v interface{}) {
+		device := v.(*DeviceInfo)
+		c.devicesMu.Lock()
+		if _, ok := c.devices[device.ObjectID.Instance]; !ok {
+			c.devices[device.ObjectID.Instance] = device
+		}
+		c.devicesMu.Unlock()
+	})
+}
+
+// restoreState loads the persisted client state when connecting
+func (c *Client) restoreState(ctx context.Context) {
+	if c.opts.storage == nil {
+		return
+	}
+	if err := c.restoreDevices(ctx); err != nil {
+		c.logger.Warn("failed to restore device cache", slog.String("error", err.Error()))
+	}
+	if c.server != nil {
+		if err := c.restoreCOVSubscriptions(ctx); err != nil {
+			c.logger.Warn("failed to restore COV subscriptions", slog.String("error", err.Error()))
+		}
+	}
+}
+
+// FileStorage is a Storage keeping each key in a file and each log in an
+// append-only file under a directory
+type FileStorage struct {
+	dir string
+
+	mu   sync.Mutex
+	seqs map[string]uint64 // last sequence number of opened logs
+}
+
+// NewFileStorage creates a file storage in dir, creating it if needed
+func NewFileStorage(dir string) (*FileStorage, error) {
+	for _, sub := range []string{"kv", "log", "seq"} {
+		if err := os.MkdirAll(filepath.Join(dir, sub), 0o755); err != nil {
+			return nil, fmt.Errorf("file storage: %w", err)
+		}
+	}
+	return &FileStorage{dir: dir, seqs: make(map[string]uint64)}, nil
+}
+
+// keyPath returns the file holding a key
+func (s *FileStorage) keyPath(key string) string {
+	return filepath.Join(s.dir, "kv", url.PathEscape(key))
+}
+
+// logPath returns the file holding a log
+func (s *FileStorage) logPath(log string) string {
+	return filepath.Join(s.dir, "log", url.PathEscape(log))
+}
+
+// seqPath returns the file holding the last sequence number of a truncated
+// log
+func (s *FileStorage) seqPath(log string) string {
+	return filepath.Join(s.dir, "seq", url.PathEscape(log))
+}
+
+// Get returns the value of a key
+func (s *FileStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	data, err := os.ReadFile(s.keyPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, ErrStorageNotFound
+	}
+	return data, err
+}
+
+// Put sets the value of a key. The file is replaced atomically.
+func (s *FileStorage) Put(ctx context.Context, key string, value []byte) error {
+	return writeFileAtomic(s.keyPath(key), value)
+}
+
+// Delete removes a key
+func (s *FileStorage) Delete(ctx context.Context, key string) error {
+	err := os.Remove(s.keyPath(key))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// List returns the keys starting with prefix
+func (s *FileStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	entries, err := os.ReadDir(filepath.Join(s.dir, "kv"))
+	if err != nil {
+		return nil, err
+	}
+	var keys []string
+	for _, entry := range entries {
+		key, err := url.PathUnescape(entry.Name())
+		if err != nil || strings.HasPrefix(entry.Name(), ".tmp-") {
+			continue
+		}
+		if strings.HasPrefix(key, prefix) {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys, nil
+}
+
+// Append adds a record to a log. The record is synced to disk before its
+// sequence number is returned.
+func (s *FileStorage) Append(ctx context.Context, log string, record []byte) (uint64, error) {
+	if len(record) > maxLogRecordSize {
+		return 0, fmt.Errorf("file storage: record of %d bytes exceeds %d", len(record), maxLogRecordSize)
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seq, err := s.lastSeq(log)
+	if err != nil {
+		return 0, err
+	}
+	seq++
+
+	f, err := os.OpenFile(s.logPath(log), os.O_CREATE|os.O_WRONLY|os.O_APPEND, 0o644)
+	if err != nil {
+		return 0, err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return 0, err
+	}
+
+	// A record written in part is cut off, so that the next one follows the
+	// last complete record
+	if _, err := f.Write(encodeLogRecord(seq, record)); err != nil {
+		f.Truncate(info.Size())
+		f.Close()
+		return 0, err
+	}
+	if err := f.Sync(); err != nil {
+		f.Truncate(info.Size())
+		f.Close()
+		return 0, err
+	}
+	if err := f.Close(); err != nil {
+		return 0, err
+	}
+
+	s.seqs[log] = seq
+	return seq, nil
+}
+
+// ReadLog calls fn with the records of a log from sequence number from
+func (s *FileStorage) ReadLog(ctx context.Context, log string, from uint64, fn func(seq uint64, record []byte) error) error {
+	s.mu.Lock()
+	records, _, err := s.readRecords(log)
+	s.mu.Unlock()
+	if err != nil {
+		return err
+	}
+	for _, r := range records {
+		if r.seq < from {
+			continue
+		}
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		if err := fn(r.seq, r.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TruncateLog removes the records of a log before sequence number before
+func (s *FileStorage) TruncateLog(ctx context.Context, log string, before uint64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	seq, err := s.lastSeq(log)
+	if err != nil {
+		return err
+	}
+	records, _, err := s.readRecords(log)
+	if err != nil {
+		return err
+	}
+
+	var buf []byte
+	for _, r := range records {
+		if r.seq >= before {
+			buf = append(buf, encodeLogRecord(r.seq, r.data)...)
+		}
+	}
+
+	// The last sequence number outlives its record
+	if err := writeFileAtomic(s.seqPath(log), []byte(strconv.FormatUint(seq, 10))); err != nil {
+		return err
+	}
+	return writeFileAtomic(s.logPath(log), buf)
+}
+
+// Close releases the storage
+func (s *FileStorage) Close() error {
+	return nil
+}
+
+// maxLogRecordSize bounds the records of a log file, so that a corrupt
+// length cannot make a read allocate gigabytes
+const maxLogRecordSize = 16 << 20
+
+// logRecord is a record of a log file
+type logRecord struct {
+	seq  uint64
+	data []byte
+}
+
+// encodeLogRecord frames a record as its sequence number, length and data
+func encodeLogRecord(seq uint64, data []byte) []byte {
+	buf := make([]byte, 12, 12+len(data))
+	binary.BigEndian.PutUint64(buf[0:8], seq)
+	binary.BigEndian.PutUint32(buf[8:12], uint32(len(data)))
+	return append(buf, data...)
+}
+
+// readRecords reads the records of a log file and returns the offset
+// following the last complete one. A record cut short by a crash while
+// appending, or with a corrupt length, ends the log.
+func (s *FileStorage) readRecords(log string) ([]logRecord, int64, error) {
+	f, err := os.Open(s.logPath(log))
+	if errors.Is(err, os.ErrNotExist) {
+		return nil, 0, nil
+	}
+	if err != nil {
+		return nil, 0, err
+	}
+	defer f.Close()
+
+	var (
+		records []logRecord
+		end     int64
+	)
+	r := bufio.NewReader(f)
+	header := make([]byte, 12)
+	for {
+		if _, err := io.ReadFull(r, header); err != nil {
+			break
+		}
+		length := binary.BigEndian.Uint32(header[8:12])
+		if length > maxLogRecordSize {
+			break
+		}
+		data := make([]byte, length)
+		if _, err := io.ReadFull(r, data); err != nil {
+			break
+		}
+		records = append(records, logRecord{seq: binary.BigEndian.Uint64(header[0:8]), data: data})
+		end += int64(len(header)) + int64(length)
+	}
+	return records, end, nil
+}
+
+// lastSeq returns the last sequence number of a log. The caller holds mu.
+// The first call for a log cuts off what follows its last complete record,
+// left by a crash while appending, so that later records stay readable.
+func (s *FileStorage) lastSeq(log string) (uint64, error) {
+	if seq, ok := s.seqs[log]; ok {
+		return seq, nil
+	}
+
+	var seq uint64
+	if data, err := os.ReadFile(s.seqPath(log)); err == nil {
+		seq, _ = strconv.ParseUint(strings.TrimSpace(string(data)), 10, 64)
+	}
+	records, end, err := s.readRecords(log)
+	if err != nil {
+		return 0, err
+	}
+	if info, err := os.Stat(s.logPath(log)); err == nil && info.Size() > end {
+		if err := os.Truncate(s.logPath(log), end); err != nil {
+			return 0, err
+		}
+	}
+	if n := len(records); n > 0 && records[n-1].seq > seq {
+		seq = records[n-1].seq
+	}
+
+	s.seqs[log] = seq
+	return seq, nil
+}
+
+// writeFileAtomic replaces a file through a temporary file and a rename
+func writeFileAtomic(path string, data []byte) error {
+	f, err := os.CreateTemp(filepath.Dir(path), ".tmp-*")
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(data); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		os.Remove(f.Name())
+		return err
+	}
+	if err := f.Close(); err != nil {
+		os.Remove(f.Name())
+		return err
+	}
+	if err := os.Rename(f.Name(), path); err != nil {
+		return err
+	}
+
+	// The rename is durable once the directory is synced
+	dir, err := os.Open(filepath.Dir(path))
+	if err != nil {
+		return err
+	}
+	defer dir.Close()
+	return dir.Sync()
+}
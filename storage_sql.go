@@ -0,0 +1,172 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+)
+
+// sqlSchema creates the tables of a SQLStorage
+var sqlSchema = []string{
+	`CREATE TABLE IF NOT EXISTS bacnet_kv (
+		key   TEXT PRIMARY KEY,
+		value BLOB NOT NULL
+	)`,
+	`CREATE TABLE IF NOT EXISTS bacnet_log (
+		log    TEXT    NOT NULL,
+		seq    INTEGER NOT NULL,
+		record BLOB    NOT NULL,
+		PRIMARY KEY (log, seq)
+	)`,
+	`CREATE TABLE IF NOT EXISTS bacnet_log_seq (
+		log TEXT    PRIMARY KEY,
+		seq INTEGER NOT NULL
+	)`,
+}
+
+// SQLStorage is a Storage backed by a SQLite database. The database is
+// opened by the caller with the driver of its choice, e.g.
+//
+//	db, err := sql.Open("sqlite", "bacnet.db") // modernc.org/sqlite
+//	storage, err := bacnet.NewSQLStorage(ctx, db)
+type SQLStorage struct {
+	db *sql.DB
+}
+
+// NewSQLStorage creates the storage tables in db if needed
+func NewSQLStorage(ctx context.Context, db *sql.DB) (*SQLStorage, error) {
+	for _, stmt := range sqlSchema {
+		if _, err := db.ExecContext(ctx, stmt); err != nil {
+			return nil, fmt.Errorf("sql storage: %w", err)
+		}
+	}
+	return &SQLStorage{db: db}, nil
+}
+
+// Get returns the value of a key
+func (s *SQLStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	var value []byte
+	err := s.db.QueryRowContext(ctx, `SELECT value FROM bacnet_kv WHERE key = ?`, key).Scan(&value)
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, ErrStorageNotFound
+	}
+	return value, err
+}
+
+// Put sets the value of a key
+func (s *SQLStorage) Put(ctx context.Context, key string, value []byte) error {
+	_, err := s.db.ExecContext(ctx,
+		`INSERT INTO bacnet_kv (key, value) VALUES (?, ?)
+		 ON CONFLICT (key) DO UPDATE SET value = excluded.value`, key, value)
+	return err
+}
+
+// Delete removes a key
+func (s *SQLStorage) Delete(ctx context.Context, key string) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM bacnet_kv WHERE key = ?`, key)
+	return err
+}
+
+// List returns the keys starting with prefix
+func (s *SQLStorage) List(ctx context.Context, prefix string) ([]string, error) {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT key FROM bacnet_kv WHERE substr(key, 1, length(?)) = ? ORDER BY key`, prefix, prefix)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var keys []string
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+// Append adds a record to a log
+func (s *SQLStorage) Append(ctx context.Context, log string, record []byte) (uint64, error) {
+	tx, err := s.db.BeginTx(ctx, nil)
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+
+	var seq uint64
+	err = tx.QueryRowContext(ctx, `SELECT seq FROM bacnet_log_seq WHERE log = ?`, log).Scan(&seq)
+	if err != nil && !errors.Is(err, sql.ErrNoRows) {
+		return 0, err
+	}
+	seq++
+
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO bacnet_log_seq (log, seq) VALUES (?, ?)
+		 ON CONFLICT (log) DO UPDATE SET seq = excluded.seq`, log, seq); err != nil {
+		return 0, err
+	}
+	if _, err := tx.ExecContext(ctx,
+		`INSERT INTO bacnet_log (log, seq, record) VALUES (?, ?, ?)`, log, seq, record); err != nil {
+		return 0, err
+	}
+	return seq, tx.Commit()
+}
+
+// ReadLog calls fn with the records of a log from sequence number from.
+// The records are read before fn is called, so fn may use the storage.
+func (s *SQLStorage) ReadLog(ctx context.Context, log string, from uint64, fn func(seq uint64, record []byte) error) error {
+	rows, err := s.db.QueryContext(ctx,
+		`SELECT seq, record FROM bacnet_log WHERE log = ? AND seq >= ? ORDER BY seq`, log, from)
+	if err != nil {
+		return err
+	}
+
+	var records []logRecord
+	for rows.Next() {
+		var r logRecord
+		if err := rows.Scan(&r.seq, &r.data); err != nil {
+			rows.Close()
+			return err
+		}
+		records = append(records, r)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	for _, r := range records {
+		if err := fn(r.seq, r.data); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// TruncateLog removes the records of a log before sequence number before
+func (s *SQLStorage) TruncateLog(ctx context.Context, log string, before uint64) error {
+	_, err := s.db.ExecContext(ctx, `DELETE FROM bacnet_log WHERE log = ? AND seq < ?`, log, before)
+	return err
+}
+
+// Close releases the storage. The database stays open.
+func (s *SQLStorage) Close() error {
+	return nil
+}
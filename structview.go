@@ -0,0 +1,319 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+)
+
+// NodeType is the BACnetNodeType of a Structured View object
+type NodeType uint8
+
+const (
+	NodeTypeUnknown        NodeType = 0
+	NodeTypeSystem         NodeType = 1
+	NodeTypeNetwork        NodeType = 2
+	NodeTypeDevice         NodeType = 3
+	NodeTypeOrganizational NodeType = 4
+	NodeTypeArea           NodeType = 5
+	NodeTypeEquipment      NodeType = 6
+	NodeTypePoint          NodeType = 7
+	NodeTypeCollection     NodeType = 8
+	NodeTypeProperty       NodeType = 9
+	NodeTypeFunctional     NodeType = 10
+	NodeTypeOther          NodeType = 11
+	NodeTypeSubsystem      NodeType = 12
+	NodeTypeBuilding       NodeType = 13
+	NodeTypeFloor          NodeType = 14
+	NodeTypeSection        NodeType = 15
+	NodeTypeModule         NodeType = 16
+	NodeTypeTree           NodeType = 17
+	NodeTypeMember         NodeType = 18
+	NodeTypeProtocol       NodeType = 19
+	NodeTypeRoom           NodeType = 20
+	NodeTypeZone           NodeType = 21
+)
+
+var nodeTypeNames = map[NodeType]string{
+	NodeTypeUnknown:        "unknown",
+	NodeTypeSystem:         "system",
+	NodeTypeNetwork:        "network",
+	NodeTypeDevice:         "device",
+	NodeTypeOrganizational: "organizational",
+	NodeTypeArea:           "area",
+	NodeTypeEquipment:      "equipment",
+	NodeTypePoint:          "point",
+	NodeTypeCollection:     "collection",
+	NodeTypeProperty:       "property",
+	NodeTypeFunctional:     "functional",
+	NodeTypeOther:          "other",
+	NodeTypeSubsystem:      "subsystem",
+	NodeTypeBuilding:       "building",
+	NodeTypeFloor:          "floor",
+	NodeTypeSection:        "section",
+	NodeTypeModule:         "module",
+	NodeTypeTree:           "tree",
+	NodeTypeMember:         "member",
+	NodeTypeProtocol:       "protocol",
+	NodeTypeRoom:           "room",
+	NodeTypeZone:           "zone",
+}
+
+func (t NodeType) String() string {
+	if name, ok := nodeTypeNames[t]; ok {
+		return name
+	}
+	return fmt.Sprintf("node-type(%d)", t)
+}
+
+// DeviceObjectReference is a BACnetDeviceObjectReference. Device is nil
+// when the object is in the device holding the reference.
+type DeviceObjectReference struct {
+	Device *ObjectIdentifier
+	Object ObjectIdentifier
+}
+
+// decodeDeviceObjectReference decodes a BACnetDeviceObjectReference
+func decodeDeviceObjectReference(d *Decoder) DeviceObjectReference {
+	var ref DeviceObjectReference
+	if d.IsContext(0) {
+		device := d.ContextObjectIdentifier(0)
+		ref.Device = &device
+	}
+	ref.Object = d.ContextObjectIdentifier(1)
+	return ref
+}
+
+// ViewNode is a node of the navigation tree built from Structured View
+// objects
+type ViewNode struct {
+	// DeviceID is the device holding the object
+	DeviceID   uint32
+	ObjectID   ObjectIdentifier
+	Name       string
+	Annotation string
+
+	// NodeType is the node-type of Structured View nodes
+	NodeType NodeType
+
+	Children []*ViewNode
+
+	// Err is set when the node could not be read
+	Err error
+}
+
+// Walk calls fn for the node and its descendants, depth first, with their
+// depth below n
+func (n *ViewNode) Walk(fn func(node *ViewNode, depth int)) {
+	n.walk(fn, 0)
+}
+
+func (n *ViewNode) walk(fn func(node *ViewNode, depth int), depth int) {
+	fn(n, depth)
+	for _, child := range n.Children {
+		child.walk(fn, depth+1)
+	}
+}
+
+// maxViewDepth bounds the nesting of Structured Views followed by the walker
+const maxViewDepth = 16
+
+// viewKey identifies a Structured View across devices
+type viewKey struct {
+	deviceID uint32
+	objectID ObjectIdentifier
+}
+
+// WalkStructuredView reads a Structured View object and, recursively, the
+// views among its subordinates, including those of other devices, and
+// returns the navigation tree rooted at it. Subordinates that cannot be read
+// are kept in the tree with their error; a view met again below itself is
+// not expanded.
+func (c *Client) WalkStructuredView(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) (*ViewNode, error) {
+	root := &ViewNode{DeviceID: deviceID, ObjectID: objectID}
+	name, err := c.ReadProperty(ctx, deviceID, objectID, PropertyObjectName)
+	if err != nil {
+		return nil, err
+	}
+	root.Name, _ = name.(string)
+
+	if err := c.expandView(ctx, root, map[viewKey]bool{}, 0); err != nil {
+		return nil, err
+	}
+	return root, nil
+}
+
+// ReadViewHierarchy walks the Structured Views of a device and returns the
+// trees of the views that are not subordinates of another view of the
+// device, in the order of the object list. Of views listing each other, the
+// first one met is the root.
+func (c *Client) ReadViewHierarchy(ctx context.Context, deviceID uint32) ([]*ViewNode, error) {
+	objects, err := c.GetObjectList(ctx, deviceID)
+	if err != nil {
+		return nil, err
+	}
+
+	var trees []*ViewNode
+	nested := make(map[ObjectIdentifier]bool)
+	for _, oid := range objects {
+		if oid.Type != ObjectTypeStructuredView || nested[oid] {
+			continue
+		}
+		tree, err := c.WalkStructuredView(ctx, deviceID, oid)
+		if err != nil {
+			return nil, err
+		}
+		tree.Walk(func(node *ViewNode, depth int) {
+			if depth > 0 && node.DeviceID == deviceID && node.ObjectID != oid {
+				nested[node.ObjectID] = true
+			}
+		})
+		trees = append(trees, tree)
+	}
+
+	// Drop the trees walked before a view listing them was met
+	roots := trees[:0]
+	for _, tree := range trees {
+		if !nested[tree.ObjectID] {
+			roots = append(roots, tree)
+		}
+	}
+	return roots, nil
+}
+
+// expandView reads the node type and subordinates of a Structured View node
+// and expands the views among them. Only errors reading the subordinate-list
+// of the node itself are returned.
+func (c *Client) expandView(ctx context.Context, node *ViewNode, path map[viewKey]bool, depth int) error {
+	if nodeType, err := c.ReadProperty(ctx, node.DeviceID, node.ObjectID, PropertyNodeType); err == nil {
+		if v, ok := nodeType.(uint32); ok {
+			node.NodeType = NodeType(v)
+		}
+	}
+
+	refs, err := c.readSubordinateList(ctx, node.DeviceID, node.ObjectID)
+	if err != nil {
+		return err
+	}
+
+	// Annotations are optional
+	annotations, _ := c.ReadArray(ctx, node.DeviceID, node.ObjectID, PropertySubordinateAnnotations)
+
+	node.Children = make([]*ViewNode, len(refs))
+	for i, ref := range refs {
+		child := &ViewNode{DeviceID: node.DeviceID, ObjectID: ref.Object}
+		if ref.Device != nil {
+			child.DeviceID = ref.Device.Instance
+		}
+		if i < len(annotations) {
+			child.Annotation, _ = annotations[i].(string)
+		}
+		node.Children[i] = child
+	}
+	c.readViewNames(ctx, node.Children)
+
+	key := viewKey{node.DeviceID, node.ObjectID}
+	path[key] = true
+	defer delete(path, key)
+
+	for _, child := range node.Children {
+		if child.Err != nil || child.ObjectID.Type != ObjectTypeStructuredView {
+			continue
+		}
+		if path[viewKey{child.DeviceID, child.ObjectID}] || depth+1 >= maxViewDepth {
+			c.logger.Debug("structured view not expanded",
+				slog.Uint64("device_id", uint64(child.DeviceID)),
+				slog.String("object", child.ObjectID.String()),
+			)
+			continue
+		}
+		if err := c.expandView(ctx, child, path, depth+1); err != nil {
+			child.Err = err
+		}
+	}
+	return nil
+}
+
+// readSubordinateList reads the subordinate-list of a Structured View
+func (c *Client) readSubordinateList(ctx context.Context, deviceID uint32, objectID ObjectIdentifier) ([]DeviceObjectReference, error) {
+	resp, err := c.readProperty(ctx, deviceID, objectID, PropertySubordinateList, &ReadOptions{})
+	if err != nil {
+		return nil, err
+	}
+
+	d := NewDecoder(resp.Data)
+	d.ContextObjectIdentifier(0)
+	d.ContextEnumerated(1)
+	d.Opening(3)
+	var refs []DeviceObjectReference
+	for d.Err() == nil && d.Len() > 0 && !d.IsClosing(3) {
+		refs = append(refs, decodeDeviceObjectReference(d))
+	}
+	d.Closing(3)
+	if err := d.Err(); err != nil {
+		return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+	}
+	return refs, nil
+}
+
+// readViewNames reads the object-name of nodes, one ReadPropertyMultiple per
+// device, and sets the error of the nodes that cannot be read
+func (c *Client) readViewNames(ctx context.Context, nodes []*ViewNode) {
+	byDevice := make(map[uint32][]*ViewNode)
+	var devices []uint32
+	for _, node := range nodes {
+		if _, ok := byDevice[node.DeviceID]; !ok {
+			devices = append(devices, node.DeviceID)
+		}
+		byDevice[node.DeviceID] = append(byDevice[node.DeviceID], node)
+	}
+
+	for _, deviceID := range devices {
+		group := byDevice[deviceID]
+		requests := make([]ReadPropertyRequest, len(group))
+		for i, node := range group {
+			requests[i] = ReadPropertyRequest{ObjectID: node.ObjectID, PropertyID: PropertyObjectName}
+		}
+
+		results, err := c.ReadPropertyMultiple(ctx, deviceID, requests)
+		if serviceUnsupported(err) {
+			results, err = c.readEach(ctx, deviceID, requests)
+		}
+		if err != nil {
+			for _, node := range group {
+				node.Err = err
+			}
+			continue
+		}
+
+		byObject := make(map[ObjectIdentifier]PropertyResult, len(results))
+		for _, r := range results {
+			byObject[r.ObjectID] = r
+		}
+		for _, node := range group {
+			r, ok := byObject[node.ObjectID]
+			switch {
+			case !ok:
+				node.Err = fmt.Errorf("%w: object-name of %s not returned", ErrInvalidResponse, node.ObjectID)
+			case r.Err != nil:
+				node.Err = r.Err
+			default:
+				node.Name, _ = r.Value.(string)
+			}
+		}
+	}
+}
@@ -0,0 +1,164 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+)
+
+// Default outstanding transactions per device when none is configured,
+// after the frames a device may answer at once: an MS/TP node typically
+// sends a single frame per token (Max_Info_Frames of 1), and devices with
+// small APDUs tend to have small transaction tables
+const (
+	defaultMaxOutstandingMSTP  = 1
+	defaultMaxOutstandingSmall = 2
+	defaultMaxOutstanding      = 4
+)
+
+// transactionSlots bounds the confirmed requests outstanding to a device.
+// Requests beyond the limit wait in order for a slot.
+type transactionSlots struct {
+	active  int
+	waiters []chan struct{}
+}
+
+// maxOutstanding returns the number of confirmed requests that may be
+// outstanding to a device at once
+func (c *Client) maxOutstanding(deviceID uint32) int {
+	if n, ok := c.opts.deviceMaxOutstanding[deviceID]; ok {
+		return n
+	}
+	if c.opts.maxOutstanding > 0 {
+		return c.opts.maxOutstanding
+	}
+
+	c.devicesMu.RLock()
+	device := c.devices[deviceID]
+	c.devicesMu.RUnlock()
+	switch {
+	case device == nil:
+		return defaultMaxOutstanding
+	case c.onMSTPLink(device.Address):
+		return defaultMaxOutstandingMSTP
+	case device.MaxAPDULength <= 480:
+		return defaultMaxOutstandingSmall
+	default:
+		return defaultMaxOutstanding
+	}
+}
+
+// onMSTPLink returns true for an address on the MS/TP data link of the
+// client. The data link of a remote network is not known, so routed devices
+// are bounded by their APDU size.
+func (c *Client) onMSTPLink(addr Address) bool {
+	if addr.Net != 0 {
+		return false
+	}
+	linkAddr, err := c.link.Addr(addr.Addr)
+	if err != nil {
+		return false
+	}
+	_, ok := linkAddr.(MSTPAddr)
+	return ok
+}
+
+// acquireTransaction waits for a transaction slot of a device, queued
+// behind the requests already waiting. The returned function releases the
+// slot. Requests without a device instance, such as probes, are not
+// bounded.
+func (c *Client) acquireTransaction(ctx context.Context, deviceID uint32) (func(), error) {
+	limit := c.maxOutstanding(deviceID)
+	if deviceID == wildcardDeviceInstance || limit <= 0 {
+		return func() {}, nil
+	}
+	release := func() { c.releaseTransaction(deviceID) }
+
+	c.transactionsMu.Lock()
+	slots, ok := c.transactions[deviceID]
+	if !ok {
+		slots = &transactionSlots{}
+		c.transactions[deviceID] = slots
+	}
+	if slots.active < limit && len(slots.waiters) == 0 {
+		slots.active++
+		c.transactionsMu.Unlock()
+		return release, nil
+	}
+	ready := make(chan struct{})
+	slots.waiters = append(slots.waiters, ready)
+	c.transactionsMu.Unlock()
+
+	c.metrics.QueuedRequests.Inc()
+	defer c.metrics.QueuedRequests.Dec()
+
+	select {
+	case <-ready:
+		return release, nil
+	case <-ctx.Done():
+	}
+
+	c.transactionsMu.Lock()
+	for i, w := range slots.waiters {
+		if w == ready {
+			slots.waiters = append(slots.waiters[:i], slots.waiters[i+1:]...)
+			c.transactionsMu.Unlock()
+			return nil, fmt.Errorf("wait for a transaction slot: %w", ctx.Err())
+		}
+	}
+	c.transactionsMu.Unlock()
+
+	// The slot was handed over as the context ended
+	release()
+	return nil, fmt.Errorf("wait for a transaction slot: %w", ctx.Err())
+}
+
+// releaseTransaction hands a transaction slot of a device to the next
+// request waiting, or frees it
+func (c *Client) releaseTransaction(deviceID uint32) {
+	c.transactionsMu.Lock()
+	defer c.transactionsMu.Unlock()
+
+	slots, ok := c.transactions[deviceID]
+	if !ok {
+		return
+	}
+	if len(slots.waiters) > 0 {
+		close(slots.waiters[0])
+		slots.waiters = slots.waiters[1:]
+		return
+	}
+	slots.active--
+	if slots.active <= 0 {
+		delete(c.transactions, deviceID)
+	}
+}
+
+// allocInvokeID assigns a free invoke ID to a pending request, skipping
+// the IDs still awaiting an answer
+func (c *Client) allocInvokeID(respCh chan *APDU) (uint8, error) {
+	c.pendingMu.Lock()
+	defer c.pendingMu.Unlock()
+
+	for i := 0; i < 256; i++ {
+		invokeID := c.nextInvokeID()
+		if _, busy := c.pending[invokeID]; !busy {
+			c.pending[invokeID] = respCh
+			return invokeID, nil
+		}
+	}
+	return 0, ErrNoInvokeID
+}
@@ -0,0 +1,189 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+)
+
+// newTransactionClient returns a client allowing one outstanding request
+// per device
+func newTransactionClient(t *testing.T) *Client {
+	t.Helper()
+	c, err := NewClient(WithMaxOutstandingRequests(1))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return c
+}
+
+// waitForWaiters waits until n requests wait for a slot of a device
+func waitForWaiters(t *testing.T, c *Client, deviceID uint32, n int) {
+	t.Helper()
+	deadline := time.Now().Add(time.Second)
+	for time.Now().Before(deadline) {
+		c.transactionsMu.Lock()
+		slots := c.transactions[deviceID]
+		waiting := slots != nil && len(slots.waiters) == n
+		c.transactionsMu.Unlock()
+		if waiting {
+			return
+		}
+		time.Sleep(time.Millisecond)
+	}
+	t.Fatalf("%d requests never waited for a slot", n)
+}
+
+func TestAcquireTransactionFIFO(t *testing.T) {
+	c := newTransactionClient(t)
+	ctx := context.Background()
+
+	release, err := c.acquireTransaction(ctx, 1)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	type acquired struct {
+		i       int
+		release func()
+	}
+	got := make(chan acquired)
+	const waiters = 3
+	for i := 0; i < waiters; i++ {
+		go func(i int) {
+			release, err := c.acquireTransaction(ctx, 1)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			got <- acquired{i, release}
+		}(i)
+		waitForWaiters(t, c, 1, i+1)
+	}
+
+	for i := 0; i < waiters; i++ {
+		release()
+		select {
+		case a := <-got:
+			if a.i != i {
+				t.Fatalf("waiter %d got the slot before waiter %d", a.i, i)
+			}
+			release = a.release
+		case <-time.After(time.Second):
+			t.Fatalf("waiter %d never got the slot", i)
+		}
+		select {
+		case a := <-got:
+			t.Fatalf("waiter %d got a slot while waiter %d held it", a.i, i)
+		default:
+		}
+	}
+	release()
+
+	c.transactionsMu.Lock()
+	defer c.transactionsMu.Unlock()
+	if slots, ok := c.transactions[1]; ok {
+		t.Errorf("slots not freed: %d active, %d waiting", slots.active, len(slots.waiters))
+	}
+}
+
+func TestAcquireTransactionCancelledAfterHandoff(t *testing.T) {
+	c := newTransactionClient(t)
+
+	if _, err := c.acquireTransaction(context.Background(), 1); err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error, 1)
+	go func() {
+		release, err := c.acquireTransaction(ctx, 1)
+		if err == nil {
+			release()
+		}
+		done <- err
+	}()
+	waitForWaiters(t, c, 1, 1)
+
+	// The waiter sees its context end, then blocks on the lock while the
+	// first request releases its slot, handing it to the waiter
+	c.transactionsMu.Lock()
+	cancel()
+	time.Sleep(20 * time.Millisecond)
+	slots := c.transactions[1]
+	close(slots.waiters[0])
+	slots.waiters = slots.waiters[1:]
+	c.transactionsMu.Unlock()
+
+	if err := <-done; !errors.Is(err, context.Canceled) {
+		t.Fatalf("err = %v, want %v", err, context.Canceled)
+	}
+
+	// The waiter released the slot it was handed
+	c.transactionsMu.Lock()
+	defer c.transactionsMu.Unlock()
+	if slots, ok := c.transactions[1]; ok {
+		t.Errorf("slot not freed: %d active, %d waiting", slots.active, len(slots.waiters))
+	}
+}
+
+func TestAllocInvokeID(t *testing.T) {
+	tests := []struct {
+		name string
+		busy []uint8
+		next uint8
+		want uint8
+		err  error
+	}{
+		{name: "free", next: 5, want: 5},
+		{name: "skips busy", busy: []uint8{5, 6}, next: 5, want: 7},
+		{name: "wraps", busy: []uint8{255, 0}, next: 255, want: 1},
+		{name: "all busy", next: 5, err: ErrNoInvokeID},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c := newTransactionClient(t)
+			if tt.err != nil {
+				for i := 0; i < 256; i++ {
+					c.pending[uint8(i)] = make(chan *APDU)
+				}
+			}
+			for _, id := range tt.busy {
+				c.pending[id] = make(chan *APDU)
+			}
+			// nextInvokeID increments before use
+			c.invokeID.Store(uint32(tt.next) - 1)
+
+			respCh := make(chan *APDU)
+			id, err := c.allocInvokeID(respCh)
+			if !errors.Is(err, tt.err) {
+				t.Fatalf("err = %v, want %v", err, tt.err)
+			}
+			if err != nil {
+				return
+			}
+			if id != tt.want {
+				t.Errorf("invoke ID = %d, want %d", id, tt.want)
+			}
+			if c.pending[id] != respCh {
+				t.Errorf("invoke ID %d not assigned to the request", id)
+			}
+		})
+	}
+}
@@ -0,0 +1,385 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// LogRecordKind tells what a log record holds
+type LogRecordKind uint8
+
+const (
+	// LogRecordValue holds a logged value
+	LogRecordValue LogRecordKind = iota
+	// LogRecordStatus marks a change of the log status, such as an
+	// interruption or a purge of the buffer
+	LogRecordStatus
+	// LogRecordFailure holds the error that prevented reading the value
+	LogRecordFailure
+	// LogRecordTimeChange marks a change of the device clock
+	LogRecordTimeChange
+)
+
+func (k LogRecordKind) String() string {
+	switch k {
+	case LogRecordValue:
+		return "value"
+	case LogRecordStatus:
+		return "status"
+	case LogRecordFailure:
+		return "failure"
+	case LogRecordTimeChange:
+		return "time-change"
+	default:
+		return fmt.Sprintf("log-record-kind(%d)", k)
+	}
+}
+
+// LogStatus is a BACnetLogStatus
+type LogStatus struct {
+	LogDisabled    bool
+	BufferPurged   bool
+	LogInterrupted bool
+}
+
+// LogRecord is a record of the log-buffer of a Trend Log object
+type LogRecord struct {
+	Timestamp time.Time
+	Kind      LogRecordKind
+
+	// Value is the logged value: nil, bool, float32, uint32 (unsigned or
+	// enumerated), int32 or BitString, or the encoding of any other
+	// datatype as []byte
+	Value interface{}
+
+	// Status is set for status records
+	Status LogStatus
+
+	// Err is set for failure records
+	Err error
+
+	// TimeChange is the clock adjustment of time-change records
+	TimeChange time.Duration
+
+	// StatusFlags of the monitored object, if logged
+	StatusFlags *StatusFlags
+}
+
+// decodeLogStatus decodes a context-tagged BACnetLogStatus
+func decodeLogStatus(d *Decoder, tagNum uint8) LogStatus {
+	bits := decodeBitString(d.context(tagNum, 1, 2))
+	return LogStatus{LogDisabled: bits.Bit(0), BufferPurged: bits.Bit(1), LogInterrupted: bits.Bit(2)}
+}
+
+// decodeLogFailure decodes the BACnetError of a failure log datum
+func decodeLogFailure(d *Decoder, tagNum uint8) error {
+	d.Opening(tagNum)
+	class := ErrorClass(d.Enumerated())
+	code := ErrorCode(d.Enumerated())
+	d.Closing(tagNum)
+	return NewBACnetError(class, code)
+}
+
+// decodeAnyValue returns a copy of the encoding enclosed in a constructed
+// tag
+func decodeAnyValue(d *Decoder, tagNum uint8) []byte {
+	d.Opening(tagNum)
+	start := d.Offset()
+	for d.Err() == nil && d.Len() > 0 && !d.IsClosing(tagNum) {
+		d.Skip()
+	}
+	var data []byte
+	if d.Err() == nil {
+		data = append([]byte(nil), d.data[start:d.Offset()]...)
+	}
+	d.Closing(tagNum)
+	return data
+}
+
+// decodeLogRecord decodes a BACnetLogRecord with timestamps in loc
+func decodeLogRecord(d *Decoder, loc *time.Location) LogRecord {
+	var rec LogRecord
+
+	d.Opening(0)
+	rec.Timestamp = decodeDateTime(d, loc)
+	d.Closing(0)
+
+	d.Opening(1)
+	t, ok := d.peek()
+	if !ok || t.class != TagClassContext {
+		d.failf("expected log datum")
+		return rec
+	}
+	switch t.num {
+	case 0:
+		rec.Kind = LogRecordStatus
+		rec.Status = decodeLogStatus(d, 0)
+	case 1:
+		rec.Value = d.ContextBoolean(1)
+	case 2:
+		rec.Value = d.ContextReal(2)
+	case 3:
+		rec.Value = d.ContextEnumerated(3)
+	case 4:
+		rec.Value = d.ContextUnsigned(4)
+	case 5:
+		rec.Value = d.ContextSigned(5)
+	case 6:
+		rec.Value = decodeBitString(d.context(6, 1, 1+255))
+	case 7:
+		d.context(7, 0, 0)
+	case 8:
+		rec.Kind = LogRecordFailure
+		rec.Err = decodeLogFailure(d, 8)
+	case 9:
+		rec.Kind = LogRecordTimeChange
+		rec.TimeChange = time.Duration(float64(d.ContextReal(9)) * float64(time.Second))
+	case 10:
+		rec.Value = decodeAnyValue(d, 10)
+	default:
+		d.failf("unknown log datum [%d]", t.num)
+	}
+	d.Closing(1)
+
+	if d.IsContext(2) {
+		bits := decodeBitString(d.context(2, 1, 2))
+		rec.StatusFlags = &StatusFlags{
+			InAlarm:      bits.Bit(0),
+			Fault:        bits.Bit(1),
+			Overridden:   bits.Bit(2),
+			OutOfService: bits.Bit(3),
+		}
+	}
+	return rec
+}
+
+const (
+	// logRecordEstimate is the assumed encoded length of a log record, to
+	// size ReadRange pages to the device's maximum APDU
+	logRecordEstimate = 32
+
+	// readRangeOverhead is the length of a ReadRange ack without items
+	readRangeOverhead = 24
+)
+
+// ReadTrendLog reads the records of a Trend Log object logged between from
+// and to, paging through the log-buffer with ReadRange, and returns them
+// sorted by timestamp. Timestamps are sent and decoded in the location of
+// from, which should be the time zone of the device. Status records, such
+// as the interruptions of logging, are returned along with the values.
+func (c *Client) ReadTrendLog(ctx context.Context, deviceID uint32, instance uint32, from, to time.Time) ([]LogRecord, error) {
+	objectID := NewObjectIdentifier(ObjectTypeTrendLog, instance)
+	items, err := c.readLogBuffer(ctx, deviceID, objectID, from, to, func(d *Decoder, loc *time.Location) (time.Time, interface{}) {
+		rec := decodeLogRecord(d, loc)
+		return rec.Timestamp, rec
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]LogRecord, len(items))
+	for i, item := range items {
+		records[i] = item.(LogRecord)
+	}
+	return records, nil
+}
+
+// logItemDecoder decodes one record of a log buffer and returns its
+// timestamp
+type logItemDecoder func(d *Decoder, loc *time.Location) (time.Time, interface{})
+
+// readLogBuffer pages through the log-buffer of a Trend Log, Trend Log
+// Multiple or Event Log object with ReadRange and returns the records
+// logged between from and to, sorted by timestamp
+func (c *Client) readLogBuffer(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, from, to time.Time, decode logItemDecoder) ([]interface{}, error) {
+	loc := from.Location()
+
+	count := (c.deviceMaxAPDU(deviceID) - readRangeOverhead) / logRecordEstimate
+	if count < 1 {
+		count = 1
+	}
+
+	type logItem struct {
+		timestamp time.Time
+		record    interface{}
+	}
+
+	// Devices return the records newer than the reference time; start one
+	// hundredth of a second early to include a record logged at from
+	var items []logItem
+	rng := RangeByTime(from.Add(-10*time.Millisecond), int32(count))
+	var last time.Time
+	for {
+		result, err := c.ReadRange(ctx, deviceID, objectID, PropertyLogBuffer, rng)
+		if err != nil {
+			return nil, err
+		}
+
+		var page []logItem
+		d := NewDecoder(result.ItemData)
+		for d.Err() == nil && d.Len() > 0 {
+			ts, rec := decode(d, loc)
+			page = append(page, logItem{timestamp: ts, record: rec})
+		}
+		if err := d.Err(); err != nil {
+			return nil, fmt.Errorf("%w: %v", ErrInvalidResponse, err)
+		}
+
+		done := len(page) == 0 || !result.MoreItems
+		for _, item := range page {
+			if item.timestamp.After(to) {
+				done = true
+				break
+			}
+			// Paging by time may return the last record of the previous
+			// page again
+			if !last.IsZero() && !item.timestamp.After(last) && result.FirstSequenceNumber == nil {
+				continue
+			}
+			if !item.timestamp.Before(from) {
+				items = append(items, item)
+			}
+		}
+		if done {
+			break
+		}
+
+		last = page[len(page)-1].timestamp
+		if result.FirstSequenceNumber != nil {
+			rng = RangeBySequence(*result.FirstSequenceNumber+uint32(len(page)), int32(count))
+		} else {
+			rng = RangeByTime(last, int32(count))
+		}
+	}
+
+	sort.SliceStable(items, func(i, j int) bool {
+		return items[i].timestamp.Before(items[j].timestamp)
+	})
+	records := make([]interface{}, len(items))
+	for i, item := range items {
+		records[i] = item.record
+	}
+	return records, nil
+}
+
+// LogMultipleRecord is a record of the log-buffer of a Trend Log Multiple
+// object
+type LogMultipleRecord struct {
+	Timestamp time.Time
+
+	// Kind is LogRecordValue for logged data, LogRecordStatus or
+	// LogRecordTimeChange
+	Kind LogRecordKind
+
+	// Values holds one value per member of the log-device-object-property
+	// list, of the types of LogRecord.Value. A member that could not be
+	// read holds its error.
+	Values []interface{}
+
+	// Status is set for status records
+	Status LogStatus
+
+	// TimeChange is the clock adjustment of time-change records
+	TimeChange time.Duration
+}
+
+// ReadTrendLogMultiple reads the records of a Trend Log Multiple object
+// logged between from and to, paging through the log-buffer with ReadRange,
+// and returns them sorted by timestamp. Timestamps are sent and decoded in
+// the location of from, which should be the time zone of the device.
+func (c *Client) ReadTrendLogMultiple(ctx context.Context, deviceID uint32, instance uint32, from, to time.Time) ([]LogMultipleRecord, error) {
+	objectID := NewObjectIdentifier(ObjectTypeTrendLogMultiple, instance)
+	items, err := c.readLogBuffer(ctx, deviceID, objectID, from, to, func(d *Decoder, loc *time.Location) (time.Time, interface{}) {
+		rec := decodeLogMultipleRecord(d, loc)
+		return rec.Timestamp, rec
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	records := make([]LogMultipleRecord, len(items))
+	for i, item := range items {
+		records[i] = item.(LogMultipleRecord)
+	}
+	return records, nil
+}
+
+// decodeLogMultipleRecord decodes a BACnetLogMultipleRecord with
+// timestamps in loc
+func decodeLogMultipleRecord(d *Decoder, loc *time.Location) LogMultipleRecord {
+	var rec LogMultipleRecord
+
+	d.Opening(0)
+	rec.Timestamp = decodeDateTime(d, loc)
+	d.Closing(0)
+
+	d.Opening(1)
+	switch {
+	case d.IsContext(0):
+		rec.Kind = LogRecordStatus
+		rec.Status = decodeLogStatus(d, 0)
+	case d.IsOpening(1):
+		d.Opening(1)
+		for d.Err() == nil && d.Len() > 0 && !d.IsClosing(1) {
+			rec.Values = append(rec.Values, decodeLogMultipleValue(d))
+		}
+		d.Closing(1)
+	case d.IsContext(2):
+		rec.Kind = LogRecordTimeChange
+		rec.TimeChange = time.Duration(float64(d.ContextReal(2)) * float64(time.Second))
+	default:
+		d.failf("expected log datum")
+	}
+	d.Closing(1)
+	return rec
+}
+
+// decodeLogMultipleValue decodes one value of the log-data of a
+// BACnetLogMultipleRecord
+func decodeLogMultipleValue(d *Decoder) interface{} {
+	t, ok := d.peek()
+	if !ok || t.class != TagClassContext {
+		d.failf("expected log data value")
+		return nil
+	}
+	switch t.num {
+	case 0:
+		return d.ContextBoolean(0)
+	case 1:
+		return d.ContextReal(1)
+	case 2:
+		return d.ContextEnumerated(2)
+	case 3:
+		return d.ContextUnsigned(3)
+	case 4:
+		return d.ContextSigned(4)
+	case 5:
+		return decodeBitString(d.context(5, 1, 1+255))
+	case 6:
+		d.context(6, 0, 0)
+		return nil
+	case 7:
+		return decodeLogFailure(d, 7)
+	case 8:
+		return decodeAnyValue(d, 8)
+	default:
+		d.failf("unknown log data value [%d]", t.num)
+		return nil
+	}
+}
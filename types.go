@@ -64,6 +64,9 @@ const (
 	NPDUControlPriorityUrgent      NPDUControl = 0x01
 	NPDUControlPriorityCritical    NPDUControl = 0x02
 	NPDUControlPriorityLifeSafety  NPDUControl = 0x03
+
+	// npduPriorityMask selects the network priority bits
+	npduPriorityMask NPDUControl = 0x03
 )
 
 // Network Layer Message Types
@@ -132,6 +135,10 @@ const (
 	ServiceLifeSafetyOperation       ConfirmedServiceChoice = 27
 	ServiceSubscribeCOVProperty      ConfirmedServiceChoice = 28
 	ServiceGetEventInformation       ConfirmedServiceChoice = 29
+	ServiceSubscribeCOVPropertyMultiple ConfirmedServiceChoice = 30
+	ServiceConfirmedCOVNotificationMultiple ConfirmedServiceChoice = 31
+	ServiceConfirmedAuditNotification ConfirmedServiceChoice = 32
+	ServiceAuditLogQuery             ConfirmedServiceChoice = 33
 )
 
 func (s ConfirmedServiceChoice) String() string {
@@ -166,6 +173,10 @@ func (s ConfirmedServiceChoice) String() string {
 		ServiceLifeSafetyOperation:       "LifeSafetyOperation",
 		ServiceSubscribeCOVProperty:      "SubscribeCOVProperty",
 		ServiceGetEventInformation:       "GetEventInformation",
+		ServiceSubscribeCOVPropertyMultiple: "SubscribeCOVPropertyMultiple",
+		ServiceConfirmedCOVNotificationMultiple: "ConfirmedCOVNotificationMultiple",
+		ServiceConfirmedAuditNotification: "ConfirmedAuditNotification",
+		ServiceAuditLogQuery:             "AuditLogQuery",
 	}
 	if name, ok := names[s]; ok {
 		return name
@@ -372,6 +383,8 @@ func ParseObjectType(s string) (ObjectType, bool) {
 		"sch":                 ObjectTypeSchedule,
 		"trend-log":           ObjectTypeTrendLog,
 		"tl":                  ObjectTypeTrendLog,
+		"trend-log-multiple":  ObjectTypeTrendLogMultiple,
+		"tlm":                 ObjectTypeTrendLogMultiple,
 		"calendar":            ObjectTypeCalendar,
 		"cal":                 ObjectTypeCalendar,
 		"notification-class":  ObjectTypeNotificationClass,
@@ -554,6 +567,31 @@ const (
 	PropertyLifeSafetyAlarmValues     PropertyIdentifier = 166
 	PropertyMaxSegmentsAccepted       PropertyIdentifier = 167
 	PropertyProfileName               PropertyIdentifier = 168
+	PropertyNodeSubtype               PropertyIdentifier = 207
+	PropertyNodeType                  PropertyIdentifier = 208
+	PropertyStructuredObjectList      PropertyIdentifier = 209
+	PropertySubordinateAnnotations    PropertyIdentifier = 210
+	PropertySubordinateList           PropertyIdentifier = 211
+	PropertyBackupAndRestoreState     PropertyIdentifier = 338
+	PropertyBackupPreparationTime     PropertyIdentifier = 339
+	PropertyRestoreCompletionTime     PropertyIdentifier = 340
+	PropertyRestorePreparationTime    PropertyIdentifier = 341
+	PropertyGroupMembers              PropertyIdentifier = 345
+	PropertyGroupMemberNames          PropertyIdentifier = 346
+	PropertyBlinkWarnEnable           PropertyIdentifier = 373
+	PropertyDefaultFadeTime           PropertyIdentifier = 374
+	PropertyDefaultRampRate           PropertyIdentifier = 375
+	PropertyDefaultStepIncrement      PropertyIdentifier = 376
+	PropertyEgressTime                PropertyIdentifier = 377
+	PropertyInProgress                PropertyIdentifier = 378
+	PropertyInstantaneousPower        PropertyIdentifier = 379
+	PropertyLightingCommand           PropertyIdentifier = 380
+	PropertyLightingCommandDefaultPriority PropertyIdentifier = 381
+	PropertyMaxActualValue            PropertyIdentifier = 382
+	PropertyMinActualValue            PropertyIdentifier = 383
+	PropertyPower                     PropertyIdentifier = 384
+	PropertyTransition                PropertyIdentifier = 385
+	PropertyEgressActive              PropertyIdentifier = 386
 )
 
 func (p PropertyIdentifier) String() string {
@@ -587,9 +625,165 @@ func (p PropertyIdentifier) String() string {
 		PropertySegmentationSupported: "segmentation-supported",
 		PropertyObjectList:       "object-list",
 		PropertyDatabaseRevision: "database-revision",
+		PropertyTrackingValue:    "tracking-value",
+		PropertyInProgress:       "in-progress",
+		PropertyLightingCommand:  "lighting-command",
+		PropertyLightingCommandDefaultPriority: "lighting-command-default-priority",
+		PropertyDefaultFadeTime:  "default-fade-time",
+		PropertyDefaultRampRate:  "default-ramp-rate",
+		PropertyDefaultStepIncrement: "default-step-increment",
+		PropertyBlinkWarnEnable:  "blink-warn-enable",
+		PropertyEgressTime:       "egress-time",
+		PropertyEgressActive:     "egress-active",
+		PropertyNodeSubtype:      "node-subtype",
+		PropertyNodeType:         "node-type",
+		PropertyStructuredObjectList: "structured-object-list",
+		PropertySubordinateAnnotations: "subordinate-annotations",
+		PropertySubordinateList:  "subordinate-list",
+		PropertyListOfGroupMembers: "list-of-group-members",
+		PropertyGroupMembers:     "group-members",
+		PropertyGroupMemberNames: "group-member-names",
+		PropertyFileAccessMethod: "file-access-method",
+		PropertyFileSize:         "file-size",
+		PropertyFileType:         "file-type",
+		PropertyConfigurationFiles: "configuration-files",
+		PropertyLastRestoreTime:  "last-restore-time",
+		PropertyBackupFailureTimeout: "backup-failure-timeout",
+		PropertyBackupAndRestoreState: "backup-and-restore-state",
+		PropertyBackupPreparationTime: "backup-preparation-time",
+		PropertyRestoreCompletionTime: "restore-completion-time",
+		PropertyRestorePreparationTime: "restore-preparation-time",
 		PropertyAll:              "all",
 		PropertyRequired:         "required",
 		PropertyOptional:         "optional",
+		PropertyAckedTransitions: "acked-transitions",
+		PropertyAckRequired:      "ack-required",
+		PropertyAction:           "action",
+		PropertyActionText:       "action-text",
+		PropertyActiveText:       "active-text",
+		PropertyActiveVtSessions: "active-vt-sessions",
+		PropertyAlarmValue:       "alarm-value",
+		PropertyAlarmValues:      "alarm-values",
+		PropertyAllWritesSuccessful:"all-writes-successful",
+		PropertyApduSegmentTimeout:"apdu-segment-timeout",
+		PropertyApduTimeout:      "apdu-timeout",
+		PropertyArchive:          "archive",
+		PropertyBias:             "bias",
+		PropertyChangeOfStateCount:"change-of-state-count",
+		PropertyChangeOfStateTime:"change-of-state-time",
+		PropertyNotificationClass:"notification-class",
+		PropertyControlledVariableReference:"controlled-variable-reference",
+		PropertyControlledVariableUnits:"controlled-variable-units",
+		PropertyControlledVariableValue:"controlled-variable-value",
+		PropertyDateList:         "date-list",
+		PropertyDaylightSavingsStatus:"daylight-savings-status",
+		PropertyDerivativeConstant:"derivative-constant",
+		PropertyDerivativeConstantUnits:"derivative-constant-units",
+		PropertyDescriptionOfHalt:"description-of-halt",
+		PropertyDeviceAddressBinding:"device-address-binding",
+		PropertyEffectivePeriod:  "effective-period",
+		PropertyElapsedActiveTime:"elapsed-active-time",
+		PropertyErrorLimit:       "error-limit",
+		PropertyEventEnable:      "event-enable",
+		PropertyEventType:        "event-type",
+		PropertyExceptionSchedule:"exception-schedule",
+		PropertyFaultValues:      "fault-values",
+		PropertyFeedbackValue:    "feedback-value",
+		PropertyInactiveText:     "inactive-text",
+		PropertyInProcess:        "in-process",
+		PropertyInstanceOf:       "instance-of",
+		PropertyIntegralConstant: "integral-constant",
+		PropertyIntegralConstantUnits:"integral-constant-units",
+		PropertyLimitEnable:      "limit-enable",
+		PropertyListOfObjectPropertyReferences:"list-of-object-property-references",
+		PropertyLocalDate:        "local-date",
+		PropertyLocalTime:        "local-time",
+		PropertyLocation:         "location",
+		PropertyManipulatedVariableReference:"manipulated-variable-reference",
+		PropertyMaximumOutput:    "maximum-output",
+		PropertyMaxInfoFrames:    "max-info-frames",
+		PropertyMaxMaster:        "max-master",
+		PropertyMaxPresValue:     "max-pres-value",
+		PropertyMinimumOffTime:   "minimum-off-time",
+		PropertyMinimumOnTime:    "minimum-on-time",
+		PropertyMinimumOutput:    "minimum-output",
+		PropertyMinPresValue:     "min-pres-value",
+		PropertyModificationDate: "modification-date",
+		PropertyNotifyType:       "notify-type",
+		PropertyNumberOfApduRetries:"number-of-apdu-retries",
+		PropertyNumberOfStates:   "number-of-states",
+		PropertyObjectPropertyReference:"object-property-reference",
+		PropertyOutputUnits:      "output-units",
+		PropertyEventParameters:  "event-parameters",
+		PropertyPolarity:         "polarity",
+		PropertyPriority:         "priority",
+		PropertyPriorityForWriting:"priority-for-writing",
+		PropertyProcessIdentifier:"process-identifier",
+		PropertyProgramChange:    "program-change",
+		PropertyProgramLocation:  "program-location",
+		PropertyProgramState:     "program-state",
+		PropertyProportionalConstant:"proportional-constant",
+		PropertyProportionalConstantUnits:"proportional-constant-units",
+		PropertyProtocolObjectTypesSupported:"protocol-object-types-supported",
+		PropertyProtocolServicesSupported:"protocol-services-supported",
+		PropertyReadOnly:         "read-only",
+		PropertyReasonForHalt:    "reason-for-halt",
+		PropertyRecipientList:    "recipient-list",
+		PropertyResolution:       "resolution",
+		PropertySetpoint:         "setpoint",
+		PropertySetpointReference:"setpoint-reference",
+		PropertyStateText:        "state-text",
+		PropertyTimeDelay:        "time-delay",
+		PropertyTimeOfActiveTimeReset:"time-of-active-time-reset",
+		PropertyTimeOfStateCountReset:"time-of-state-count-reset",
+		PropertyTimeSynchronizationRecipients:"time-synchronization-recipients",
+		PropertyUpdateInterval:   "update-interval",
+		PropertyUtcOffset:        "utc-offset",
+		PropertyVtClassesSupported:"vt-classes-supported",
+		PropertyWeeklySchedule:   "weekly-schedule",
+		PropertyAttemptedSamples: "attempted-samples",
+		PropertyAverageValue:     "average-value",
+		PropertyBufferSize:       "buffer-size",
+		PropertyClientCovIncrement:"client-cov-increment",
+		PropertyCOVResubscriptionInterval:"cov-resubscription-interval",
+		PropertyEventTimeStamps:  "event-time-stamps",
+		PropertyLogBuffer:        "log-buffer",
+		PropertyLogDeviceObjectProperty:"log-device-object-property",
+		PropertyLogEnable:        "log-enable",
+		PropertyLogInterval:      "log-interval",
+		PropertyMaximumValue:     "maximum-value",
+		PropertyMinimumValue:     "minimum-value",
+		PropertyNotificationThreshold:"notification-threshold",
+		PropertyPreviousNotifyRecord:"previous-notify-record",
+		PropertyRecordsSinceNotification:"records-since-notification",
+		PropertyRecordCount:      "record-count",
+		PropertyStartTime:        "start-time",
+		PropertyStopTime:         "stop-time",
+		PropertyStopWhenFull:     "stop-when-full",
+		PropertyTotalRecordCount: "total-record-count",
+		PropertyValidSamples:     "valid-samples",
+		PropertyWindowInterval:   "window-interval",
+		PropertyWindowSamples:    "window-samples",
+		PropertyMaximumValueTimestamp:"maximum-value-timestamp",
+		PropertyMinimumValueTimestamp:"minimum-value-timestamp",
+		PropertyVarianceValue:    "variance-value",
+		PropertyActiveCOVSubscriptions:"active-cov-subscriptions",
+		PropertyDirectReading:    "direct-reading",
+		PropertyMaintenanceRequired:"maintenance-required",
+		PropertyMemberOf:         "member-of",
+		PropertyMode:             "mode",
+		PropertyOperationExpected:"operation-expected",
+		PropertySetting:          "setting",
+		PropertySilenced:         "silenced",
+		PropertyZoneMembers:      "zone-members",
+		PropertyLifeSafetyAlarmValues:"life-safety-alarm-values",
+		PropertyMaxSegmentsAccepted:"max-segments-accepted",
+		PropertyProfileName:      "profile-name",
+		PropertyInstantaneousPower:"instantaneous-power",
+		PropertyMaxActualValue:   "max-actual-value",
+		PropertyMinActualValue:   "min-actual-value",
+		PropertyPower:            "power",
+		PropertyTransition:       "transition",
 	}
 	if name, ok := names[p]; ok {
 		return name
@@ -632,7 +826,171 @@ func ParsePropertyIdentifier(s string) (PropertyIdentifier, bool) {
 		"system-status":           PropertySystemStatus,
 		"object-list":             PropertyObjectList,
 		"database-revision":       PropertyDatabaseRevision,
+		"tracking-value":          PropertyTrackingValue,
+		"in-progress":             PropertyInProgress,
+		"lighting-command":        PropertyLightingCommand,
+		"node-type":               PropertyNodeType,
+		"subordinate-list":        PropertySubordinateList,
+		"file-size":               PropertyFileSize,
+		"configuration-files":     PropertyConfigurationFiles,
 		"all":                     PropertyAll,
+		"acked-transitions":      PropertyAckedTransitions,
+		"ack-required":           PropertyAckRequired,
+		"action":                 PropertyAction,
+		"action-text":            PropertyActionText,
+		"active-text":            PropertyActiveText,
+		"active-vt-sessions":     PropertyActiveVtSessions,
+		"alarm-value":            PropertyAlarmValue,
+		"alarm-values":           PropertyAlarmValues,
+		"all-writes-successful":  PropertyAllWritesSuccessful,
+		"apdu-segment-timeout":   PropertyApduSegmentTimeout,
+		"apdu-timeout":           PropertyApduTimeout,
+		"archive":                PropertyArchive,
+		"bias":                   PropertyBias,
+		"change-of-state-count":  PropertyChangeOfStateCount,
+		"change-of-state-time":   PropertyChangeOfStateTime,
+		"notification-class":     PropertyNotificationClass,
+		"controlled-variable-reference":PropertyControlledVariableReference,
+		"controlled-variable-units":PropertyControlledVariableUnits,
+		"controlled-variable-value":PropertyControlledVariableValue,
+		"date-list":              PropertyDateList,
+		"daylight-savings-status":PropertyDaylightSavingsStatus,
+		"deadband":               PropertyDeadband,
+		"derivative-constant":    PropertyDerivativeConstant,
+		"derivative-constant-units":PropertyDerivativeConstantUnits,
+		"description-of-halt":    PropertyDescriptionOfHalt,
+		"device-address-binding": PropertyDeviceAddressBinding,
+		"device-type":            PropertyDeviceType,
+		"effective-period":       PropertyEffectivePeriod,
+		"elapsed-active-time":    PropertyElapsedActiveTime,
+		"error-limit":            PropertyErrorLimit,
+		"event-enable":           PropertyEventEnable,
+		"event-type":             PropertyEventType,
+		"exception-schedule":     PropertyExceptionSchedule,
+		"fault-values":           PropertyFaultValues,
+		"feedback-value":         PropertyFeedbackValue,
+		"file-access-method":     PropertyFileAccessMethod,
+		"file-type":              PropertyFileType,
+		"high-limit":             PropertyHighLimit,
+		"inactive-text":          PropertyInactiveText,
+		"in-process":             PropertyInProcess,
+		"instance-of":            PropertyInstanceOf,
+		"integral-constant":      PropertyIntegralConstant,
+		"integral-constant-units":PropertyIntegralConstantUnits,
+		"limit-enable":           PropertyLimitEnable,
+		"list-of-group-members":  PropertyListOfGroupMembers,
+		"list-of-object-property-references":PropertyListOfObjectPropertyReferences,
+		"local-date":             PropertyLocalDate,
+		"local-time":             PropertyLocalTime,
+		"location":               PropertyLocation,
+		"low-limit":              PropertyLowLimit,
+		"manipulated-variable-reference":PropertyManipulatedVariableReference,
+		"maximum-output":         PropertyMaximumOutput,
+		"max-apdu-length-accepted":PropertyMaxApduLengthAccepted,
+		"max-info-frames":        PropertyMaxInfoFrames,
+		"max-master":             PropertyMaxMaster,
+		"max-pres-value":         PropertyMaxPresValue,
+		"minimum-off-time":       PropertyMinimumOffTime,
+		"minimum-on-time":        PropertyMinimumOnTime,
+		"minimum-output":         PropertyMinimumOutput,
+		"min-pres-value":         PropertyMinPresValue,
+		"modification-date":      PropertyModificationDate,
+		"notify-type":            PropertyNotifyType,
+		"number-of-apdu-retries": PropertyNumberOfApduRetries,
+		"number-of-states":       PropertyNumberOfStates,
+		"object-property-reference":PropertyObjectPropertyReference,
+		"optional":               PropertyOptional,
+		"output-units":           PropertyOutputUnits,
+		"event-parameters":       PropertyEventParameters,
+		"polarity":               PropertyPolarity,
+		"priority":               PropertyPriority,
+		"priority-for-writing":   PropertyPriorityForWriting,
+		"process-identifier":     PropertyProcessIdentifier,
+		"program-change":         PropertyProgramChange,
+		"program-location":       PropertyProgramLocation,
+		"program-state":          PropertyProgramState,
+		"proportional-constant":  PropertyProportionalConstant,
+		"proportional-constant-units":PropertyProportionalConstantUnits,
+		"protocol-object-types-supported":PropertyProtocolObjectTypesSupported,
+		"protocol-services-supported":PropertyProtocolServicesSupported,
+		"read-only":              PropertyReadOnly,
+		"reason-for-halt":        PropertyReasonForHalt,
+		"recipient-list":         PropertyRecipientList,
+		"required":               PropertyRequired,
+		"resolution":             PropertyResolution,
+		"segmentation-supported": PropertySegmentationSupported,
+		"setpoint":               PropertySetpoint,
+		"setpoint-reference":     PropertySetpointReference,
+		"state-text":             PropertyStateText,
+		"time-delay":             PropertyTimeDelay,
+		"time-of-active-time-reset":PropertyTimeOfActiveTimeReset,
+		"time-of-state-count-reset":PropertyTimeOfStateCountReset,
+		"time-synchronization-recipients":PropertyTimeSynchronizationRecipients,
+		"update-interval":        PropertyUpdateInterval,
+		"utc-offset":             PropertyUtcOffset,
+		"vt-classes-supported":   PropertyVtClassesSupported,
+		"weekly-schedule":        PropertyWeeklySchedule,
+		"attempted-samples":      PropertyAttemptedSamples,
+		"average-value":          PropertyAverageValue,
+		"buffer-size":            PropertyBufferSize,
+		"client-cov-increment":   PropertyClientCovIncrement,
+		"cov-resubscription-interval":PropertyCOVResubscriptionInterval,
+		"event-time-stamps":      PropertyEventTimeStamps,
+		"log-buffer":             PropertyLogBuffer,
+		"log-device-object-property":PropertyLogDeviceObjectProperty,
+		"log-enable":             PropertyLogEnable,
+		"log-interval":           PropertyLogInterval,
+		"maximum-value":          PropertyMaximumValue,
+		"minimum-value":          PropertyMinimumValue,
+		"notification-threshold": PropertyNotificationThreshold,
+		"previous-notify-record": PropertyPreviousNotifyRecord,
+		"records-since-notification":PropertyRecordsSinceNotification,
+		"record-count":           PropertyRecordCount,
+		"start-time":             PropertyStartTime,
+		"stop-time":              PropertyStopTime,
+		"stop-when-full":         PropertyStopWhenFull,
+		"total-record-count":     PropertyTotalRecordCount,
+		"valid-samples":          PropertyValidSamples,
+		"window-interval":        PropertyWindowInterval,
+		"window-samples":         PropertyWindowSamples,
+		"maximum-value-timestamp":PropertyMaximumValueTimestamp,
+		"minimum-value-timestamp":PropertyMinimumValueTimestamp,
+		"variance-value":         PropertyVarianceValue,
+		"active-cov-subscriptions":PropertyActiveCOVSubscriptions,
+		"backup-failure-timeout": PropertyBackupFailureTimeout,
+		"direct-reading":         PropertyDirectReading,
+		"last-restore-time":      PropertyLastRestoreTime,
+		"maintenance-required":   PropertyMaintenanceRequired,
+		"member-of":              PropertyMemberOf,
+		"mode":                   PropertyMode,
+		"operation-expected":     PropertyOperationExpected,
+		"setting":                PropertySetting,
+		"silenced":               PropertySilenced,
+		"zone-members":           PropertyZoneMembers,
+		"life-safety-alarm-values":PropertyLifeSafetyAlarmValues,
+		"max-segments-accepted":  PropertyMaxSegmentsAccepted,
+		"profile-name":           PropertyProfileName,
+		"node-subtype":           PropertyNodeSubtype,
+		"structured-object-list": PropertyStructuredObjectList,
+		"subordinate-annotations":PropertySubordinateAnnotations,
+		"backup-and-restore-state":PropertyBackupAndRestoreState,
+		"backup-preparation-time":PropertyBackupPreparationTime,
+		"restore-completion-time":PropertyRestoreCompletionTime,
+		"restore-preparation-time":PropertyRestorePreparationTime,
+		"group-members":          PropertyGroupMembers,
+		"group-member-names":     PropertyGroupMemberNames,
+		"blink-warn-enable":      PropertyBlinkWarnEnable,
+		"default-fade-time":      PropertyDefaultFadeTime,
+		"default-ramp-rate":      PropertyDefaultRampRate,
+		"default-step-increment": PropertyDefaultStepIncrement,
+		"egress-time":            PropertyEgressTime,
+		"instantaneous-power":    PropertyInstantaneousPower,
+		"lighting-command-default-priority":PropertyLightingCommandDefaultPriority,
+		"max-actual-value":       PropertyMaxActualValue,
+		"min-actual-value":       PropertyMinActualValue,
+		"power":                  PropertyPower,
+		"transition":             PropertyTransition,
+		"egress-active":          PropertyEgressActive,
 	}
 	if p, ok := props[s]; ok {
 		return p, true
@@ -986,6 +1344,11 @@ type DeviceInfo struct {
 	Description         string
 	Location            string
 	ObjectList          []ObjectIdentifier
+
+	// ForwardedBy is the B/IP address of the BBMD that forwarded the
+	// I-Am of the device in a Forwarded-NPDU, empty when it was received
+	// directly. Address remains the originating address of the device.
+	ForwardedBy string
 }
 
 // PropertyValue represents a property value with metadata
@@ -997,6 +1360,18 @@ type PropertyValue struct {
 	Priority   *uint8
 }
 
+// PropertyResult is the result of one property of a ReadPropertyMultiple
+// request: either its value or the access error reported by the device
+type PropertyResult struct {
+	ObjectID   ObjectIdentifier
+	PropertyID PropertyIdentifier
+	ArrayIndex *uint32
+	Value      interface{}
+
+	// Err is the *BACnetError of a property that could not be read
+	Err error
+}
+
 // ReadPropertyRequest represents a ReadProperty request
 type ReadPropertyRequest struct {
 	ObjectID   ObjectIdentifier
@@ -1013,6 +1388,57 @@ type WritePropertyRequest struct {
 	Priority   *uint8
 }
 
+// Enumerated is a BACnet enumerated value. Values of this type are encoded
+// with the enumerated application tag rather than as unsigned integers.
+type Enumerated uint32
+
+// BitString represents a BACnet bit string
+type BitString struct {
+	Length int
+	Bits   []byte
+}
+
+// NewBitString creates a bit string of the given length with all bits cleared
+func NewBitString(length int) BitString {
+	return BitString{
+		Length: length,
+		Bits:   make([]byte, (length+7)/8),
+	}
+}
+
+// Set sets or clears bit i (bit 0 is the most significant bit of the first octet)
+func (b BitString) Set(i int, value bool) {
+	if i < 0 || i >= b.Length {
+		return
+	}
+	mask := byte(0x80 >> (i % 8))
+	if value {
+		b.Bits[i/8] |= mask
+	} else {
+		b.Bits[i/8] &^= mask
+	}
+}
+
+// Bit returns the value of bit i
+func (b BitString) Bit(i int) bool {
+	if i < 0 || i >= b.Length || i/8 >= len(b.Bits) {
+		return false
+	}
+	return b.Bits[i/8]&(0x80>>(i%8)) != 0
+}
+
+func (b BitString) String() string {
+	buf := make([]byte, b.Length)
+	for i := range buf {
+		if b.Bit(i) {
+			buf[i] = '1'
+		} else {
+			buf[i] = '0'
+		}
+	}
+	return string(buf)
+}
+
 // Tag types for BACnet encoding
 type TagClass uint8
 
@@ -1055,3 +1481,31 @@ func decodeUint16(buf []byte) uint16 {
 func decodeUint32(buf []byte) uint32 {
 	return binary.BigEndian.Uint32(buf)
 }
+
+// toFloat64 converts a decoded numeric property value to float64
+func toFloat64(value interface{}) (float64, bool) {
+	switch v := value.(type) {
+	case float32:
+		return float64(v), true
+	case float64:
+		return v, true
+	case uint32:
+		return float64(v), true
+	case int32:
+		return float64(v), true
+	case int:
+		return float64(v), true
+	case uint8:
+		return float64(v), true
+	case uint16:
+		return float64(v), true
+	case uint64:
+		return float64(v), true
+	case int64:
+		return float64(v), true
+	case Enumerated:
+		return float64(v), true
+	default:
+		return 0, false
+	}
+}
@@ -0,0 +1,63 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"time"
+)
+
+// PointSource supplies the value of a virtual point, e.g. outdoor air
+// temperature from a weather API or a value computed from other points
+type PointSource func(ctx context.Context) (interface{}, error)
+
+// Feed updates the present value of the object from source every interval
+// until ctx is cancelled, exposing an external or computed value as a BACnet
+// object. While the source fails, the last value is kept and the object
+// reports communication-failure reliability, setting its fault flag.
+func (o *LocalObject) Feed(ctx context.Context, interval time.Duration, source PointSource) error {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		o.update(ctx, interval, source)
+
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-ticker.C:
+		}
+	}
+}
+
+// update reads the source once and applies the result
+func (o *LocalObject) update(ctx context.Context, timeout time.Duration, source PointSource) {
+	sourceCtx, cancel := context.WithTimeout(ctx, timeout)
+	value, err := source(sourceCtx)
+	cancel()
+
+	if err == nil {
+		err = o.SetPresentValue(value)
+	}
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		o.SetReliability(ReliabilityCommunicationFailure)
+		return
+	}
+
+	o.SetReliability(ReliabilityNoFaultDetected)
+}
@@ -0,0 +1,142 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"net"
+	"sync"
+)
+
+// ObjectHolder is a device that answered a Who-Has with an I-Have
+type ObjectHolder struct {
+	DeviceID   uint32
+	Address    Address
+	ObjectID   ObjectIdentifier
+	ObjectName string
+}
+
+// iHaveWatcher collects the I-Have received while a Who-Has is pending
+type iHaveWatcher func(holder ObjectHolder)
+
+// WhoHasName asks the devices which hold an object of the given name. It
+// returns the devices answering before the discovery timeout, in the order
+// they answered. WithDeviceRange limits the devices asked.
+func (c *Client) WhoHasName(ctx context.Context, name string, opts ...DiscoverOption) ([]ObjectHolder, error) {
+	return c.whoHas(ctx, func(e *Encoder) { e.ContextCharacterString(3, name) },
+		func(h ObjectHolder) bool { return h.ObjectName == name }, opts)
+}
+
+// WhoHasObject asks the devices which hold the given object, like
+// WhoHasName
+func (c *Client) WhoHasObject(ctx context.Context, objectID ObjectIdentifier, opts ...DiscoverOption) ([]ObjectHolder, error) {
+	return c.whoHas(ctx, func(e *Encoder) { e.ContextObjectIdentifier(2, objectID) },
+		func(h ObjectHolder) bool { return h.ObjectID == objectID }, opts)
+}
+
+// whoHas sends a Who-Has for the object encoded by object and collects the
+// I-Have that match until the discovery timeout, or until ctx ends with the
+// devices that answered so far. A device answering twice, through two
+// routes, is listed once.
+func (c *Client) whoHas(ctx context.Context, object func(e *Encoder), match func(ObjectHolder) bool, opts []DiscoverOption) ([]ObjectHolder, error) {
+	options := defaultDiscoverOptions()
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var e Encoder
+	if options.LowLimit != nil && options.HighLimit != nil {
+		e.ContextUnsigned(0, *options.LowLimit)
+		e.ContextUnsigned(1, *options.HighLimit)
+	}
+	object(&e)
+
+	var addr net.Addr
+	if options.Address != "" {
+		var err error
+		if addr, err = c.link.ParseAddr(options.Address); err != nil {
+			return nil, fmt.Errorf("invalid discovery address: %w", err)
+		}
+	}
+
+	var mu sync.Mutex
+	var holders []ObjectHolder
+	seen := make(map[uint32]bool)
+	stop := c.watchIHave(func(h ObjectHolder) {
+		mu.Lock()
+		defer mu.Unlock()
+		if match(h) && !seen[h.DeviceID] {
+			seen[h.DeviceID] = true
+			holders = append(holders, h)
+		}
+	})
+	defer stop()
+
+	if err := c.sendUnconfirmedRequest(ctx, addr, addr == nil, ServiceWhoHas, e.Bytes()); err != nil {
+		return nil, err
+	}
+
+	err := sleepContext(ctx, c.opts.clock, options.Timeout)
+
+	mu.Lock()
+	defer mu.Unlock()
+	return holders, err
+}
+
+// watchIHave registers a collector of the I-Have received, returning the
+// function that unregisters it
+func (c *Client) watchIHave(w iHaveWatcher) func() {
+	c.iHaveMu.Lock()
+	defer c.iHaveMu.Unlock()
+	if c.iHaveWatchers == nil {
+		c.iHaveWatchers = make(map[int]iHaveWatcher)
+	}
+	c.iHaveWatcherID++
+	id := c.iHaveWatcherID
+	c.iHaveWatchers[id] = w
+
+	return func() {
+		c.iHaveMu.Lock()
+		delete(c.iHaveWatchers, id)
+		c.iHaveMu.Unlock()
+	}
+}
+
+// handleIHave passes a received I-Have to the collectors
+func (c *Client) handleIHave(data []byte, addr net.Addr, npdu *NPDU) {
+	d := NewDecoder(data)
+	device := d.ObjectIdentifier()
+	objectID := d.ObjectIdentifier()
+	name := d.CharacterString()
+	if err := d.Err(); err != nil || device.Type != ObjectTypeDevice {
+		c.logger.Debug("invalid I-Have", slog.Any("error", err))
+		return
+	}
+
+	holder := ObjectHolder{
+		DeviceID:   device.Instance,
+		Address:    c.sourceAddress(addr, npdu),
+		ObjectID:   objectID,
+		ObjectName: name,
+	}
+
+	c.iHaveMu.RLock()
+	defer c.iHaveMu.RUnlock()
+	for _, w := range c.iHaveWatchers {
+		w(holder)
+	}
+}
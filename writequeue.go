@@ -0,0 +1,186 @@
+// Copyright 2025 Edgeo SCADA
+//
+// Licensed under the Apache License, Version 2.0 (the "License");
+// you may not use this file except in compliance with the License.
+// You may obtain a copy of the License at
+//
+//     http://www.apache.org/licenses/LICENSE-2.0
+//
+// Unless required by applicable law or agreed to in writing, software
+// distributed under the License is distributed on an "AS IS" BASIS,
+// WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+// See the License for the specific language governing permissions and
+// limitations under the License.
+
+package bacnet
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+	"time"
+)
+
+// WriteQueue holds property writes in a log of a Storage until they reach
+// their device, so that writes to a device that is offline survive
+// restarts. Writes are delivered in the order they were queued; one that
+// cannot be delivered holds back those behind it. It is safe for concurrent
+// use.
+type WriteQueue struct {
+	client  *Client
+	storage Storage
+	log     string
+
+	// mu serializes deliveries
+	mu sync.Mutex
+}
+
+// queuedWrite is a record of a write queue log
+type queuedWrite struct {
+	Time       time.Time
+	DeviceID   uint32
+	ObjectID   ObjectIdentifier
+	PropertyID PropertyIdentifier
+	ArrayIndex *uint32
+	Priority   *uint8
+
+	// Value is the application-tagged encoding of the value
+	Value []byte
+}
+
+// NewWriteQueue creates a write queue of client kept in the named log of
+// storage. Writes left in the log by a previous run are delivered by the
+// next Flush.
+func NewWriteQueue(client *Client, storage Storage, log string) *WriteQueue {
+	return &WriteQueue{client: client, storage: storage, log: log}
+}
+
+// Enqueue queues a property write and returns its sequence number in the
+// log. The array index and priority options apply; the write is sent by
+// Flush or Run.
+func (q *WriteQueue) Enqueue(ctx context.Context, deviceID uint32, objectID ObjectIdentifier, propertyID PropertyIdentifier, value interface{}, opts ...WriteOption) (uint64, error) {
+	options := &WriteOptions{}
+	for _, opt := range opts {
+		opt(options)
+	}
+
+	var e Encoder
+	if err := e.Value(value); err != nil {
+		return 0, fmt.Errorf("encode value: %w", err)
+	}
+	data, err := json.Marshal(queuedWrite{
+		Time:       q.client.opts.clock.Now(),
+		DeviceID:   deviceID,
+		ObjectID:   objectID,
+		PropertyID: propertyID,
+		ArrayIndex: options.ArrayIndex,
+		Priority:   options.Priority,
+		Value:      e.Bytes(),
+	})
+	if err != nil {
+		return 0, err
+	}
+	return q.storage.Append(ctx, q.log, data)
+}
+
+// Flush sends the queued writes in order and returns the number delivered.
+// A write the device answers with an error or a reject can never succeed:
+// it is logged and removed. Delivery stops at the first write that gets no
+// answer, which stays queued, and its error is returned.
+func (q *WriteQueue) Flush(ctx context.Context) (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	delivered := 0
+	err := q.storage.ReadLog(ctx, q.log, 0, func(seq uint64, record []byte) error {
+		var w queuedWrite
+		if err := json.Unmarshal(record, &w); err != nil {
+			q.client.logger.Warn("dropped undecodable queued write",
+				slog.Uint64("seq", seq),
+				slog.String("error", err.Error()),
+			)
+		} else if err := q.client.sendQueuedWrite(ctx, &w); err != nil {
+			if !isFinalWriteError(err) {
+				return err
+			}
+			q.client.logger.Warn("dropped queued write refused by the device",
+				slog.Uint64("device_id", uint64(w.DeviceID)),
+				slog.String("object", w.ObjectID.String()),
+				slog.String("property", w.PropertyID.String()),
+				slog.String("error", err.Error()),
+			)
+		} else {
+			delivered++
+		}
+		return q.storage.TruncateLog(ctx, q.log, seq+1)
+	})
+	return delivered, err
+}
+
+// Run flushes the queue every interval until ctx ends. Failed deliveries
+// are retried at the next interval.
+func (q *WriteQueue) Run(ctx context.Context, interval time.Duration) error {
+	for {
+		if _, err := q.Flush(ctx); err != nil && ctx.Err() == nil {
+			q.client.logger.Debug("queued writes pending", slog.String("error", err.Error()))
+		}
+		if err := sleepContext(ctx, q.client.opts.clock, interval); err != nil {
+			return err
+		}
+	}
+}
+
+// isFinalWriteError reports whether a device refused a write, which is not
+// worth retrying
+func isFinalWriteError(err error) bool {
+	var bacErr *BACnetError
+	var rejectErr *RejectError
+	return errors.As(err, &bacErr) || errors.As(err, &rejectErr)
+}
+
+// sendQueuedWrite sends a write of a write queue and records it in the
+// journal
+func (c *Client) sendQueuedWrite(ctx context.Context, w *queuedWrite) error {
+	if c.isStandby() {
+		return ErrStandby
+	}
+
+	value, err := c.decodePropertyValue(w.Value)
+	if err != nil {
+		return fmt.Errorf("decode queued value: %w", err)
+	}
+
+	addr, err := c.resolveDevice(ctx, w.DeviceID)
+	if err != nil {
+		return err
+	}
+
+	e := NewEncoder(make([]byte, 0, 32+len(w.Value)))
+	e.ContextObjectIdentifier(0, w.ObjectID)
+	e.ContextEnumerated(1, uint32(w.PropertyID))
+	if w.ArrayIndex != nil {
+		e.ContextUnsigned(2, *w.ArrayIndex)
+	}
+	e.Opening(3)
+	e.Raw(w.Value)
+	e.Closing(3)
+	if w.Priority != nil {
+		e.ContextUnsigned(4, uint32(*w.Priority))
+	}
+
+	_, err = c.sendRequest(ctx, w.DeviceID, addr, ServiceWriteProperty, e.Bytes())
+	c.journalWrite(JournalEntry{
+		DeviceID:   w.DeviceID,
+		Service:    ServiceWriteProperty,
+		ObjectID:   w.ObjectID,
+		PropertyID: w.PropertyID,
+		ArrayIndex: w.ArrayIndex,
+		Value:      value,
+		Priority:   w.Priority,
+		Err:        err,
+	})
+	return err
+}